@@ -3,19 +3,101 @@ package main
 import (
 	"context"
 	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/metrics"
+	"auction_go/configuration/broker"
+	"auction_go/configuration/email"
+	"auction_go/configuration/push"
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/entity/device_entity"
+	"auction_go/internal/events"
+	"auction_go/internal/infra/api/graphql/graphql_resolver"
+	"auction_go/internal/infra/api/grpc/grpc_service"
+	"auction_go/internal/infra/api/web/controller/activity_controller"
+	"auction_go/internal/infra/api/web/controller/admin_controller"
+	"auction_go/internal/infra/api/web/controller/api_key_controller"
+	"auction_go/internal/infra/api/web/controller/announcement_controller"
 	"auction_go/internal/infra/api/web/controller/auction_controller"
 	"auction_go/internal/infra/api/web/controller/bid_controller"
+	"auction_go/internal/infra/api/web/controller/category_controller"
+	"auction_go/internal/infra/api/web/controller/checkout_controller"
+	"auction_go/internal/infra/api/web/controller/device_controller"
+	"auction_go/internal/infra/api/web/controller/docs_controller"
+	"auction_go/internal/infra/api/web/controller/feedback_controller"
+	"auction_go/internal/infra/api/web/controller/graphql_controller"
+	"auction_go/internal/infra/api/web/controller/notification_controller"
+	"auction_go/internal/infra/api/web/controller/receipt_controller"
+	"auction_go/internal/infra/api/web/controller/recommendation_controller"
+	"auction_go/internal/infra/api/web/controller/return_controller"
+	"auction_go/internal/infra/api/web/controller/saved_search_controller"
+	"auction_go/internal/infra/api/web/controller/search_controller"
+	"auction_go/internal/infra/api/web/controller/seller_controller"
+	"auction_go/internal/infra/api/web/controller/stats_controller"
+	"auction_go/internal/infra/api/web/controller/stream_controller"
 	"auction_go/internal/infra/api/web/controller/user_controller"
+	"auction_go/internal/infra/api/web/controller/view_controller"
+	"auction_go/internal/infra/api/web/controller/watchlist_controller"
+	"auction_go/internal/infra/api/web/controller/webhook_controller"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/openapi"
+	"auction_go/internal/infra/api/web/versioning"
+	"auction_go/internal/infra/database/activity"
+	"auction_go/internal/infra/database/admin"
+	"auction_go/internal/infra/database/api_key"
+	"auction_go/internal/infra/database/announcement"
 	"auction_go/internal/infra/database/auction"
 	"auction_go/internal/infra/database/bid"
+	"auction_go/internal/infra/database/category"
+	"auction_go/internal/infra/database/checkout"
+	"auction_go/internal/infra/database/device"
+	"auction_go/internal/infra/database/feedback"
+	"auction_go/internal/infra/database/history"
+	"auction_go/internal/infra/database/notification_preference"
+	"auction_go/internal/infra/database/outbox"
+	"auction_go/internal/infra/database/receipt"
+	"auction_go/internal/infra/database/recommendation"
+	"auction_go/internal/infra/database/return_request"
+	"auction_go/internal/infra/database/saved_search"
+	"auction_go/internal/infra/database/search"
+	"auction_go/internal/infra/database/stats"
 	"auction_go/internal/infra/database/user"
+	"auction_go/internal/infra/database/view"
+	"auction_go/internal/infra/database/watchlist"
+	"auction_go/internal/infra/database/webhook"
+	"auction_go/internal/infra/notification"
+	"auction_go/internal/infra/realtime"
+	"auction_go/internal/usecase/activity_usecase"
+	"auction_go/internal/usecase/admin_usecase"
+	"auction_go/internal/usecase/api_key_usecase"
+	"auction_go/internal/usecase/announcement_usecase"
 	"auction_go/internal/usecase/auction_usecase"
 	"auction_go/internal/usecase/bid_usecase"
+	"auction_go/internal/usecase/category_usecase"
+	"auction_go/internal/usecase/checkout_usecase"
+	"auction_go/internal/usecase/device_usecase"
+	"auction_go/internal/usecase/feedback_usecase"
+	"auction_go/internal/usecase/notification_usecase"
+	"auction_go/internal/usecase/receipt_usecase"
+	"auction_go/internal/usecase/recommendation_usecase"
+	"auction_go/internal/usecase/return_usecase"
+	"auction_go/internal/usecase/saved_search_usecase"
+	"auction_go/internal/usecase/search_usecase"
+	"auction_go/internal/usecase/seller_usecase"
+	"auction_go/internal/usecase/stats_usecase"
 	"auction_go/internal/usecase/user_usecase"
+	"auction_go/internal/usecase/view_usecase"
+	"auction_go/internal/usecase/watchlist_usecase"
+	"auction_go/internal/usecase/webhook_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -33,34 +115,428 @@ func main() {
 	}
 
 	router := gin.Default()
+	router.Use(middleware.QueryExplain())
 
-	userController, bidController, auctionsController := initDependencies(databaseConnection)
+	apiSpec, err := openapi.NewSpec()
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	router.Use(middleware.ValidateRequest(apiSpec))
+
+	auctionRepository, bidRepository, userController, bidController, auctionsController, viewController, recommendationController,
+		searchController, announcementController, checkoutController, returnController, sellerController,
+		adminController, watchlistController, feedbackController, activityController, userRepository, apiKeyController, apiKeyUseCase,
+		categoryRepository, categoryController, statsController, savedSearchController,
+		auctionHub, streamController, eventBus, deviceController, pushNotifier, webhookController, webhookNotifier,
+		notificationRepository, notificationController, outboxRepository, grpcServer, graphQLController, receiptController := initDependencies(databaseConnection)
+
+	if _, err := auctionRepository.BackfillEndTimes(ctx); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	if err := bidRepository.BackfillCurrentPrices(ctx); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	if err := categoryRepository.BackfillCategories(ctx); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	// Schedule the closes of auctions still open from before this start
+	if err := auctionRepository.LoadActiveAuctions(ctx); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.POST("/graphql", middleware.OptionalUserAuth(userRepository), graphQLController.Execute)
+
+	// Every route is served under each version, versioning.Group upgrades
+	// the responses to the version's shape. The unversioned paths answer
+	// like v1 for the clients from before the versions
+	registerRoutes := func(api *gin.RouterGroup) {
+		api.GET("/auction", auctionsController.FindAuctions)
+		api.GET("/auctions/ending-soon", auctionsController.EndingSoonAuctions)
+		api.GET("/auctions/new", auctionsController.NewAuctions)
+		api.GET("/auctions/:auctionId/stats", statsController.FindAuctionStats)
+		api.GET("/auctions/:auctionId/similar", recommendationController.FindSimilarAuctions)
+		api.GET("/auctions/:auctionId/time", auctionsController.FindAuctionTime)
+		api.GET("/auctions/:auctionId/presence", streamController.FindAuctionPresence)
+		api.GET("/ws/auctions/:auctionId", streamController.StreamAuctionWebSocket)
+		api.GET("/sse/auctions/:auctionId", streamController.StreamAuctionEvents)
+		api.GET("/ws/users/me", middleware.UserAuth(userRepository), streamController.StreamMyWebSocket)
+		api.GET("/sse/users/me", middleware.UserAuth(userRepository), streamController.StreamMyEvents)
+		api.GET("/auction/:auctionId", viewController.RegisterView, auctionsController.FindAuctionById)
+		api.GET("/auction/:auctionId/views", middleware.UserAuth(userRepository), viewController.FindAuctionViews)
+		api.GET("/auction/:auctionId/announcements", announcementController.FindAnnouncementsByAuctionId)
+		api.POST("/auction/:auctionId/announcements", middleware.UserAuth(userRepository), announcementController.CreateAnnouncement)
+		api.GET("/auction/:auctionId/checkout", middleware.UserAuth(userRepository), checkoutController.FindCheckoutByAuctionId)
+		api.GET("/auction/:auctionId/bids", bidController.FindBidHistory)
+		api.POST("/auction/:auctionId/feedback", middleware.UserAuth(userRepository), feedbackController.SubmitFeedback)
+		api.POST("/auction", middleware.UserAuth(userRepository), auctionsController.CreateAuction)
+		api.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
+		api.PUT("/auction/:auctionId/cancel", middleware.UserAuth(userRepository), auctionsController.CancelAuction)
+		api.POST("/auction/:auctionId/buy-now", middleware.UserAuth(userRepository), auctionsController.BuyNow)
+		api.POST("/bid", middleware.UserOrApiKeyAuth(apiKeyUseCase, userRepository, api_key_entity.ScopeBid), bidController.CreateBid)
+		api.POST("/bid/proxy", middleware.UserOrApiKeyAuth(apiKeyUseCase, userRepository, api_key_entity.ScopeBid), bidController.CreateProxyBid)
+		api.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
+		api.POST("/checkout", middleware.UserAuth(userRepository), checkoutController.CreateCheckout)
+		api.GET("/checkout/shipping-options", checkoutController.FindShippingOptions)
+		api.GET("/checkout/:checkoutId", middleware.UserAuth(userRepository), checkoutController.FindCheckoutById)
+		api.GET("/checkout/:checkoutId/return", middleware.UserAuth(userRepository), returnController.FindReturnRequestByCheckoutId)
+		api.PUT("/checkout/:checkoutId/address", middleware.UserAuth(userRepository), checkoutController.ConfirmAddress)
+		api.PUT("/checkout/:checkoutId/shipping", middleware.UserAuth(userRepository), checkoutController.SelectShipping)
+		api.PUT("/checkout/:checkoutId/payment", middleware.UserAuth(userRepository), checkoutController.SelectPayment)
+		api.PUT("/checkout/:checkoutId/shipment", middleware.UserAuth(userRepository), checkoutController.AttachShipment)
+		api.POST("/checkout/shipment-updates", checkoutController.ApplyCarrierUpdate)
+		api.POST("/checkout/payment-updates/pix", checkoutController.ConfirmPixPayments)
+		api.POST("/returns", middleware.UserAuth(userRepository), returnController.CreateReturnRequest)
+		api.GET("/returns/:returnId", middleware.UserAuth(userRepository), returnController.FindReturnRequestById)
+		api.PUT("/returns/:returnId/accept", middleware.UserAuth(userRepository), returnController.AcceptReturnRequest)
+		api.PUT("/returns/:returnId/decline", middleware.UserAuth(userRepository), returnController.DeclineReturnRequest)
+		api.GET("/sellers/:sellerId", sellerController.FindSellerById)
+		api.GET("/sellers/by-slug/:slug", sellerController.FindSellerBySlug)
+		api.GET("/sellers/:sellerId/feedback", feedbackController.FindFeedbackBySellerId)
+		api.POST("/login", userController.Login)
+		api.POST("/user", userController.CreateUser)
+		api.POST("/users/verify", userController.VerifyEmail)
+		api.GET("/user/:userId", userController.FindUserById)
+		api.PUT("/user/:userId", middleware.UserAuth(userRepository), userController.UpdateUser)
+		api.DELETE("/user/:userId", middleware.UserAuth(userRepository), userController.DeleteAccount)
+		api.GET("/users/me/recommendations", middleware.UserAuth(userRepository), recommendationController.FindOwnRecommendations)
+		api.GET("/users/me/receipts", middleware.UserAuth(userRepository), receiptController.ListReceipts)
+		api.GET("/users/me/receipts/:receiptId", middleware.UserAuth(userRepository), receiptController.FindReceiptById)
+		api.GET("/user/:userId/bids", bidController.FindActiveBidsByUser)
+		api.GET("/user/:userId/activity", activityController.FindActivityByUserId)
+		api.GET("/watchlist", middleware.UserOrApiKeyAuth(apiKeyUseCase, userRepository, api_key_entity.ScopeRead), watchlistController.ListWatchlist)
+		api.POST("/watchlist", middleware.UserAuth(userRepository), watchlistController.AddToWatchlist)
+		api.DELETE("/watchlist/:auctionId", middleware.UserAuth(userRepository), watchlistController.RemoveFromWatchlist)
+		api.GET("/saved-searches", middleware.UserAuth(userRepository), savedSearchController.ListSavedSearches)
+		api.POST("/saved-searches", middleware.UserAuth(userRepository), savedSearchController.CreateSavedSearch)
+		api.DELETE("/saved-searches/:savedSearchId", middleware.UserAuth(userRepository), savedSearchController.DeleteSavedSearch)
+		api.GET("/devices", middleware.UserAuth(userRepository), deviceController.ListDevices)
+		api.POST("/devices", middleware.UserAuth(userRepository), deviceController.RegisterDevice)
+		api.DELETE("/devices/:deviceId", middleware.UserAuth(userRepository), deviceController.DeleteDevice)
+		api.GET("/notification-preferences", middleware.UserAuth(userRepository), notificationController.FindPreferences)
+		api.PUT("/notification-preferences", middleware.UserAuth(userRepository), notificationController.UpdatePreferences)
+		api.GET("/api-keys", middleware.UserAuth(userRepository), apiKeyController.ListApiKeys)
+		api.POST("/api-keys", middleware.UserAuth(userRepository), apiKeyController.CreateApiKey)
+		api.POST("/api-keys/:keyId/rotate", middleware.UserAuth(userRepository), apiKeyController.RotateApiKey)
+		api.DELETE("/api-keys/:keyId", middleware.UserAuth(userRepository), apiKeyController.DeleteApiKey)
+		api.GET("/categories", categoryController.FindCategories)
+		api.GET("/search", auctionsController.SearchAuctions)
+		api.GET("/search/synonyms", searchController.FindSynonymGroups)
+
+		admin := api.Group("/admin", middleware.AdminAuth())
+		admin.POST("/auctions/bulk-status", adminController.StartBulkStatusJob)
+		admin.PUT("/auctions/:auctionId/end-time", adminController.AdjustAuctionEndTime)
+		admin.PUT("/auctions/:auctionId/pause", adminController.PauseAuction)
+		admin.PUT("/auctions/:auctionId/resume", adminController.ResumeAuction)
+		admin.PUT("/auctions/:auctionId/reopen", adminController.ReopenAuction)
+		admin.GET("/auctions/:auctionId/audit", adminController.FindAuditEntriesByAuctionId)
+		admin.GET("/auctions/:auctionId/replay", adminController.ReplayAuction)
+		admin.GET("/jobs/:jobId", adminController.FindBulkStatusJobById)
+		admin.GET("/jobs/:jobId/audit", adminController.FindAuditEntriesByJobId)
+		admin.POST("/categories", categoryController.CreateCategory)
+		admin.POST("/search/synonyms", searchController.CreateSynonymGroup)
+		admin.DELETE("/search/synonyms/:synonymGroupId", searchController.DeleteSynonymGroup)
+		admin.PUT("/users/:userId/block", userController.BlockUser)
+		admin.DELETE("/users/:userId/block", userController.UnblockUser)
+		admin.GET("/webhooks", webhookController.ListWebhooks)
+		admin.POST("/webhooks", webhookController.CreateWebhook)
+		admin.DELETE("/webhooks/:webhookId", webhookController.DeleteWebhook)
+		admin.GET("/webhooks/:webhookId/dead-letters", webhookController.FindDeadLettersByWebhookId)
+	}
+	registerRoutes(&router.RouterGroup)
+	for _, version := range versioning.Versions {
+		registerRoutes(versioning.Group(router, version))
+	}
+
+	docsController := docs_controller.NewDocsController(apiSpec)
+	router.GET("/swagger", docsController.SwaggerUI)
+	router.GET("/swagger/openapi.json", docsController.OpenAPISpec)
+
+	// Documented once every route is registered
+	apiSpec.Register(router.Routes())
+
+	server := &http.Server{Addr: ":8080", Handler: router}
+
+	shutdownCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	router.GET("/auction", auctionsController.FindAuctions)
-	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
-	router.POST("/auction", auctionsController.CreateAuction)
-	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
-	router.POST("/bid", bidController.CreateBid)
-	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
-	router.GET("/user/:userId", userController.FindUserById)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err.Error())
+		}
+	}()
+
+	grpcListener, err := net.Listen("tcp", grpc_service.Address())
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal(err.Error())
+		}
+	}()
+
+	<-shutdownCtx.Done()
+
+	serverCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// WebSockets are hijacked connections the server doesn't wait for, and
+	// event streams would keep it waiting until they end
+	auctionHub.Close()
+
+	if err := server.Shutdown(serverCtx); err != nil {
+		log.Println("Error shutting down server:", err.Error())
+	}
+	grpcServer.GracefulStop()
 
-	router.Run(":8080")
+	bidRepository.Close()
+	userRepository.Close()
+	notificationRepository.Close()
+	auctionRepository.Shutdown(serverCtx)
+
+	// Last, so the events of the closes above are still handled
+	if err := eventBus.Shutdown(serverCtx); err != nil {
+		log.Println("Error shutting down event bus:", err.Error())
+	}
+	pushNotifier.Close()
+	outboxRepository.Close()
+	webhookNotifier.Close()
 }
 
 func initDependencies(database *mongo.Database) (
+	auctionRepository *auction.AuctionRepository,
+	bidRepository *bid.BidRepository,
 	userController *user_controller.UserController,
 	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+	auctionController *auction_controller.AuctionController,
+	viewController *view_controller.ViewController,
+	recommendationController *recommendation_controller.RecommendationController,
+	searchController *search_controller.SearchController,
+	announcementController *announcement_controller.AnnouncementController,
+	checkoutController *checkout_controller.CheckoutController,
+	returnController *return_controller.ReturnController,
+	sellerController *seller_controller.SellerController,
+	adminController *admin_controller.AdminController,
+	watchlistController *watchlist_controller.WatchlistController,
+	feedbackController *feedback_controller.FeedbackController,
+	activityController *activity_controller.ActivityController,
+	userRepository *user.UserRepository,
+	apiKeyController *api_key_controller.ApiKeyController,
+	apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface,
+	categoryRepository *category.CategoryRepository,
+	categoryController *category_controller.CategoryController,
+	statsController *stats_controller.StatsController,
+	savedSearchController *saved_search_controller.SavedSearchController,
+	auctionHub *realtime.Hub,
+	streamController *stream_controller.StreamController,
+	eventBus *events.Bus,
+	deviceController *device_controller.DeviceController,
+	pushNotifier *notification.PushNotifier,
+	webhookController *webhook_controller.WebhookController,
+	webhookNotifier *notification.WebhookNotifier,
+	notificationRepository *notification_preference.NotificationRepository,
+	notificationController *notification_controller.NotificationController,
+	outboxRepository *outbox.OutboxRepository,
+	grpcServer *grpc.Server,
+	graphQLController *graphql_controller.GraphQLController,
+	receiptController *receipt_controller.ReceiptController) {
+
+	auctionRepository = auction.NewAuctionRepository(database)
+	bidRepository = bid.NewBidRepository(database, auctionRepository)
+	userRepository = user.NewUserRepository(database)
+	viewRepository := view.NewViewRepository(database)
+	recommendationRepository := recommendation.NewRecommendationRepository(database)
+	searchRepository := search.NewSearchRepository(database)
+	announcementRepository := announcement.NewAnnouncementRepository(database)
+	checkoutRepository := checkout.NewCheckoutRepository(database)
+	returnRepository := return_request.NewReturnRepository(database)
+	adminRepository := admin.NewAdminRepository(database)
+	watchlistRepository := watchlist.NewWatchlistRepository(database)
+	feedbackRepository := feedback.NewFeedbackRepository(database)
+	activityRepository := activity.NewActivityRepository(database)
+	apiKeyRepository := api_key.NewApiKeyRepository(database)
+	categoryRepository = category.NewCategoryRepository(database)
+	statsRepository := stats.NewStatsRepository(database)
+	savedSearchRepository := saved_search.NewSavedSearchRepository(database)
+	deviceRepository := device.NewDeviceRepository(database)
+	webhookRepository := webhook.NewWebhookRepository(database)
+	notificationRepository = notification_preference.NewNotificationRepository(database)
+	outboxRepository = outbox.NewOutboxRepository(database, brokerPublisher())
+	historyRepository := history.NewHistoryRepository(database)
+	receiptRepository := receipt.NewReceiptRepository(database)
+
+	// The repositories publish what happens on the event bus, side effects
+	// subscribe to it and run in the background
+	eventBus = events.NewBus()
+	auctionRepository.RegisterOnCreateHook(eventBus.PublishAuctionCreated)
+	bidRepository.RegisterOnBidHook(eventBus.PublishBidPlaced)
+	auctionRepository.RegisterOnPriceDropHook(eventBus.PublishPriceDrop)
+	auctionRepository.RegisterOnExtendHook(eventBus.PublishExtension)
+	auctionRepository.RegisterOnCloseHook(eventBus.PublishAuctionClosed)
+	bidRepository.SetOutbidNotifier(eventBus)
+	watchlistRepository.SetEndingSoonNotifier(eventBus)
+
+	// The lifecycle scheduler reminds watchers and the high bidder of
+	// auctions about to close, through the same ending soon alerts
+	auctionRepository.RegisterOnReminderHook(watchlistRepository.RemindEndingSoon)
+
+	// New auctions are matched against the saved searches, and bids, price
+	// drops, extensions and closes are streamed to the auctions' watchers.
+	// Outbid bidders are told on their own stream right away
+	auctionHub = realtime.NewHub()
+	savedSearchRepository.SetMatchNotifier(eventBus)
+	eventBus.Subscribe("saved_search_matcher", events.Only(savedSearchRepository.MatchAuction))
+	eventBus.Subscribe("realtime_hub", auctionHub.HandleEvent)
+
+	// Outbid, won and ending soon alerts are pushed to the users' devices
+	pushNotifier = notification.NewPushNotifier(
+		deviceRepository, auctionRepository, notificationRepository, pushProviders())
+	eventBus.Subscribe("push_notifier", pushNotifier.HandleEvent)
+
+	// and emailed, along with saved search matches and the end of their
+	// auctions to sellers. Those two wait for the digest of users who asked
+	// for one. Winners are emailed their receipt, kept for them to look up
+	emailNotifier := notification.NewEmailNotifier(
+		userRepository, auctionRepository, notificationRepository, receiptRepository, emailSender())
+	eventBus.Subscribe("email_notifier", emailNotifier.HandleEvent)
+	notificationRepository.SetDigestSender(emailNotifier)
+
+	// Creates, bids and closes are written to the outbox along with the
+	// change itself, and relayed from there to the message broker and to
+	// the webhooks subscribed to them
+	auctionRepository.SetOutbox(outboxRepository)
+	bidRepository.SetOutbox(outboxRepository)
+	webhookNotifier = notification.NewWebhookNotifier(webhookRepository)
+	outboxRepository.AddDestination("webhooks", webhookNotifier)
+
+	// Every auction change and bid is appended to the auction's history in
+	// the same transaction, so past states can be replayed
+	auctionRepository.SetEventLog(historyRepository)
+	bidRepository.SetEventLog(historyRepository)
 
-	auctionRepository := auction.NewAuctionRepository(database)
-	bidRepository := bid.NewBidRepository(database, auctionRepository)
-	userRepository := user.NewUserRepository(database)
+	// Bids placed for proxies are checked like the bidder's own, they may
+	// have been blocked since they set the proxy
+	bidRepository.SetProxyBidCheck(bid_usecase.ProxyBidCheck(auctionRepository, userRepository))
+
+	searchUseCase := search_usecase.NewSearchUseCase(searchRepository)
+	auctionUseCase := auction_usecase.NewAuctionUseCase(
+		auctionRepository, bidRepository, userRepository, categoryRepository, searchUseCase)
 
 	userController = user_controller.NewUserController(
-		user_usecase.NewUserUseCase(userRepository))
-	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
-	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+		user_usecase.NewUserUseCase(userRepository, emailNotifier))
+	auctionController = auction_controller.NewAuctionController(auctionUseCase)
+	bidUseCase := bid_usecase.NewBidUseCase(bidRepository, auctionRepository, userRepository)
+	bidController = bid_controller.NewBidController(bidUseCase)
+	viewController = view_controller.NewViewController(view_usecase.NewViewUseCase(viewRepository, auctionRepository))
+	recommendationController = recommendation_controller.NewRecommendationController(
+		recommendation_usecase.NewRecommendationUseCase(recommendationRepository, auctionRepository, bidRepository))
+	searchController = search_controller.NewSearchController(searchUseCase)
+	announcementController = announcement_controller.NewAnnouncementController(
+		announcement_usecase.NewAnnouncementUseCase(announcementRepository, auctionRepository))
+	checkoutController = checkout_controller.NewCheckoutController(
+		checkout_usecase.NewCheckoutUseCase(checkoutRepository, auctionRepository, bidRepository))
+	returnController = return_controller.NewReturnController(
+		return_usecase.NewReturnUseCase(returnRepository, checkoutRepository, auctionRepository))
+	sellerUseCase := seller_usecase.NewSellerUseCase(userRepository, auctionUseCase)
+	sellerController = seller_controller.NewSellerController(sellerUseCase)
+	adminController = admin_controller.NewAdminController(
+		admin_usecase.NewAdminUseCase(
+			adminRepository, auctionRepository, announcementRepository, checkoutRepository, historyRepository))
+	watchlistController = watchlist_controller.NewWatchlistController(
+		watchlist_usecase.NewWatchlistUseCase(watchlistRepository, auctionRepository))
+	feedbackController = feedback_controller.NewFeedbackController(
+		feedback_usecase.NewFeedbackUseCase(feedbackRepository, auctionRepository))
+	activityController = activity_controller.NewActivityController(
+		activity_usecase.NewActivityUseCase(activityRepository))
+	apiKeyUseCase = api_key_usecase.NewApiKeyUseCase(apiKeyRepository)
+	apiKeyController = api_key_controller.NewApiKeyController(apiKeyUseCase)
+	categoryController = category_controller.NewCategoryController(
+		category_usecase.NewCategoryUseCase(categoryRepository))
+	statsUseCase := stats_usecase.NewStatsUseCase(statsRepository, auctionRepository)
+	statsController = stats_controller.NewStatsController(statsUseCase)
+	streamController = stream_controller.NewStreamController(auctionUseCase, auctionHub)
+	savedSearchController = saved_search_controller.NewSavedSearchController(
+		saved_search_usecase.NewSavedSearchUseCase(savedSearchRepository, categoryRepository, searchUseCase))
+	deviceController = device_controller.NewDeviceController(
+		device_usecase.NewDeviceUseCase(deviceRepository))
+	webhookController = webhook_controller.NewWebhookController(
+		webhook_usecase.NewWebhookUseCase(webhookRepository))
+	notificationController = notification_controller.NewNotificationController(
+		notification_usecase.NewNotificationUseCase(notificationRepository))
+	receiptController = receipt_controller.NewReceiptController(
+		receipt_usecase.NewReceiptUseCase(receiptRepository))
+
+	// gRPC clients get auctions and bids from the same use cases
+	grpcServer = grpc_service.NewServer(auctionUseCase, bidUseCase, apiKeyUseCase, userRepository, auctionHub)
+
+	// and GraphQL clients query exactly the fields they need from them
+	graphQLController = graphql_controller.NewGraphQLController(
+		graphql_resolver.NewResolver(auctionUseCase, bidUseCase, sellerUseCase, statsUseCase))
 
 	return
 }
+
+// pushProviders sets up the push services configured in the environment
+func pushProviders() map[device_entity.Platform]push.Provider {
+	providers := make(map[device_entity.Platform]push.Provider)
+
+	fcm, err := push.NewFCMFromEnv()
+	if err != nil {
+		log.Fatal("Error setting up FCM: ", err.Error())
+	}
+	if fcm != nil {
+		providers[device_entity.FCM] = fcm
+	}
+
+	apns, err := push.NewAPNsFromEnv()
+	if err != nil {
+		log.Fatal("Error setting up APNs: ", err.Error())
+	}
+	if apns != nil {
+		providers[device_entity.APNs] = apns
+	}
+
+	return providers
+}
+
+// brokerPublisher is nil when no message broker is set up
+func brokerPublisher() broker.Publisher {
+	publisher, err := broker.NewPublisherFromEnv()
+	if err != nil {
+		log.Fatal("Error setting up the message broker: ", err.Error())
+	}
+
+	return publisher
+}
+
+// emailSender sends through SES when it is set up and through SMTP
+// otherwise, nil when neither is
+func emailSender() email.Sender {
+	ses, err := email.NewSESFromEnv()
+	if err != nil {
+		log.Fatal("Error setting up SES: ", err.Error())
+	}
+	if ses != nil {
+		return ses
+	}
+
+	smtp, err := email.NewSMTPFromEnv()
+	if err != nil {
+		log.Fatal("Error setting up SMTP: ", err.Error())
+	}
+	if smtp != nil {
+		return smtp
+	}
+
+	return nil
+}
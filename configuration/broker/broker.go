@@ -0,0 +1,49 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// BROKER_DRIVER picks the message broker events are published to, kafka or
+// rabbitmq. Events aren't published to any broker while it is unset
+const BROKER_DRIVER = "BROKER_DRIVER"
+
+// Message is an event encoded for the broker. Key keeps the events of an
+// auction in order on brokers that partition them
+type Message struct {
+	Id      string
+	Type    string
+	Key     string
+	Payload []byte
+}
+
+// Publisher publishes messages to one broker, Publish only returns once the
+// broker took the message
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+	Close() error
+}
+
+// NewPublisherFromEnv returns nil when BROKER_DRIVER is unset
+func NewPublisherFromEnv() (Publisher, error) {
+	switch driver := os.Getenv(BROKER_DRIVER); driver {
+	case "":
+		return nil, nil
+	case "kafka":
+		return newKafkaFromEnv()
+	case "rabbitmq":
+		return newRabbitMQFromEnv()
+	default:
+		return nil, fmt.Errorf("%s must be kafka or rabbitmq, not %q", BROKER_DRIVER, driver)
+	}
+}
+
+func getEnv(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,61 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// BROKER_KAFKA_BROKERS lists the bootstrap brokers separated by commas,
+// events go to BROKER_KAFKA_TOPIC
+const (
+	BROKER_KAFKA_BROKERS = "BROKER_KAFKA_BROKERS"
+	BROKER_KAFKA_TOPIC   = "BROKER_KAFKA_TOPIC"
+)
+
+// Kafka publishes every event to one topic, keyed by auction so the events
+// of an auction land on the same partition in order
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+func newKafkaFromEnv() (*Kafka, error) {
+	brokers := strings.Split(os.Getenv(BROKER_KAFKA_BROKERS), ",")
+	for i := range brokers {
+		brokers[i] = strings.TrimSpace(brokers[i])
+	}
+	if len(brokers) == 0 || brokers[0] == "" {
+		return nil, fmt.Errorf("%s must be set for the kafka driver", BROKER_KAFKA_BROKERS)
+	}
+
+	return &Kafka{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        getEnv(BROKER_KAFKA_TOPIC, "auction-events"),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+func (k *Kafka) Publish(ctx context.Context, message Message) error {
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(message.Key),
+		Value: message.Payload,
+		Headers: []kafka.Header{
+			{Key: "id", Value: []byte(message.Id)},
+			{Key: "type", Value: []byte(message.Type)},
+			{Key: "content-type", Value: []byte("application/json")},
+		},
+	})
+}
+
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
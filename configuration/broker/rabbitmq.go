@@ -0,0 +1,127 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// BROKER_RABBITMQ_URL is the AMQP URL of the broker, events are published
+// to the BROKER_RABBITMQ_EXCHANGE topic exchange with their type as routing
+// key
+const (
+	BROKER_RABBITMQ_URL      = "BROKER_RABBITMQ_URL"
+	BROKER_RABBITMQ_EXCHANGE = "BROKER_RABBITMQ_EXCHANGE"
+)
+
+// RabbitMQ publishes persistent messages and waits for the broker to
+// confirm each one. It connects on the first publish and again after the
+// connection dropped
+type RabbitMQ struct {
+	url      string
+	exchange string
+
+	connection *amqp.Connection
+	channel    *amqp.Channel
+	mutex      *sync.Mutex
+}
+
+func newRabbitMQFromEnv() (*RabbitMQ, error) {
+	url := os.Getenv(BROKER_RABBITMQ_URL)
+	if url == "" {
+		return nil, fmt.Errorf("%s must be set for the rabbitmq driver", BROKER_RABBITMQ_URL)
+	}
+
+	return &RabbitMQ{
+		url:      url,
+		exchange: getEnv(BROKER_RABBITMQ_EXCHANGE, "auction-events"),
+		mutex:    &sync.Mutex{},
+	}, nil
+}
+
+// Publish sends one message at a time, the outbox relay doesn't need more
+func (r *RabbitMQ) Publish(ctx context.Context, message Message) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	channel, err := r.connect()
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, r.exchange, message.Type, false, false,
+		amqp.Publishing{
+			MessageId:    message.Id,
+			Type:         message.Type,
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+			Headers:      amqp.Table{"key": message.Key},
+			Body:         message.Payload,
+		})
+	if err != nil {
+		r.disconnect()
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		r.disconnect()
+		return err
+	}
+	if !acked {
+		return errors.New("RabbitMQ turned the message down")
+	}
+
+	return nil
+}
+
+// connect declares the exchange, so publishing works against a fresh broker
+func (r *RabbitMQ) connect() (*amqp.Channel, error) {
+	if r.channel != nil && !r.channel.IsClosed() {
+		return r.channel, nil
+	}
+	r.disconnect()
+
+	connection, err := amqp.Dial(r.url)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := connection.Channel()
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+	if err := channel.Confirm(false); err != nil {
+		connection.Close()
+		return nil, err
+	}
+	if err := channel.ExchangeDeclare(r.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	r.connection, r.channel = connection, channel
+	return channel, nil
+}
+
+func (r *RabbitMQ) disconnect() {
+	if r.connection != nil {
+		r.connection.Close()
+	}
+	r.connection, r.channel = nil, nil
+}
+
+func (r *RabbitMQ) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.disconnect()
+	return nil
+}
@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"auction_go/configuration/metrics"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mongodb_command_duration_seconds",
+	Help:    "Latency of MongoDB commands by collection, command and outcome.",
+	Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"collection", "command", "outcome"})
+
+func init() {
+	metrics.Registry.MustRegister(commandDuration)
+}
+
+type commandLabels struct {
+	collection string
+	command    string
+}
+
+// commandMonitor records every command into the commandDuration histogram,
+// the error rate is the share of the failure outcome
+type commandMonitor struct {
+	inFlight sync.Map
+}
+
+func newCommandMonitor() *event.CommandMonitor {
+	monitor := &commandMonitor{}
+
+	return &event.CommandMonitor{
+		Started:   monitor.started,
+		Succeeded: monitor.succeeded,
+		Failed:    monitor.failed,
+	}
+}
+
+// Succeeded and failed events don't carry the command, so the labels are
+// kept by request id from the started event
+func (m *commandMonitor) started(_ context.Context, evt *event.CommandStartedEvent) {
+	m.inFlight.Store(evt.RequestID, commandLabels{
+		collection: commandCollection(evt),
+		command:    evt.CommandName,
+	})
+}
+
+// Write errors such as duplicate keys come back on an ok reply, they still
+// count as failures
+func (m *commandMonitor) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	outcome := "success"
+	if _, err := evt.Reply.LookupErr("writeErrors"); err == nil {
+		outcome = "failure"
+	}
+
+	m.observe(evt.RequestID, evt.CommandName, evt.Duration, outcome)
+}
+
+func (m *commandMonitor) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	m.observe(evt.RequestID, evt.CommandName, evt.Duration, "failure")
+}
+
+func (m *commandMonitor) observe(requestId int64, commandName string, duration time.Duration, outcome string) {
+	labels := commandLabels{collection: "none", command: commandName}
+	if value, ok := m.inFlight.LoadAndDelete(requestId); ok {
+		labels = value.(commandLabels)
+	}
+
+	commandDuration.WithLabelValues(labels.collection, labels.command, outcome).Observe(duration.Seconds())
+}
+
+// The collection is the value of the command's first element, except for
+// getMore where it is sent apart from the cursor id
+func commandCollection(evt *event.CommandStartedEvent) string {
+	if evt.CommandName == "getMore" {
+		if collection, ok := evt.Command.Lookup("collection").StringValueOK(); ok {
+			return collection
+		}
+	}
+
+	if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+		return collection
+	}
+
+	return "none"
+}
@@ -14,12 +14,14 @@ const (
 	MONGODB_DB  = "MONGODB_DB"
 )
 
+// NewMongoDBConnection fails unless the server takes transactions, see
+// CheckTransactions
 func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
 	mongoURL := os.Getenv(MONGODB_URL)
 	mongoDatabase := os.Getenv(MONGODB_DB)
 
 	client, err := mongo.Connect(
-		ctx, options.Client().ApplyURI(mongoURL))
+		ctx, options.Client().ApplyURI(mongoURL).SetMonitor(newCommandMonitor()))
 	if err != nil {
 		logger.Error("Error trying to connect to mongodb database", err)
 		return nil, err
@@ -30,5 +32,11 @@ func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
 		return nil, err
 	}
 
-	return client.Database(mongoDatabase), nil
+	database := client.Database(mongoDatabase)
+	if err := CheckTransactions(ctx, database); err != nil {
+		logger.Error("Error trying to check mongodb transaction support", err)
+		return nil, err
+	}
+
+	return database, nil
 }
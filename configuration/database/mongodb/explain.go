@@ -0,0 +1,90 @@
+package mongodb
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const MONGODB_EXPLAIN_QUERIES = "MONGODB_EXPLAIN_QUERIES"
+
+type explainKey struct{}
+
+// WithExplain marks the context so list and search queries run with it are
+// explained, whatever MONGODB_EXPLAIN_QUERIES says
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainKey{}, true)
+}
+
+func explainEnabled(ctx context.Context) bool {
+	if enabled, ok := ctx.Value(explainKey{}).(bool); ok && enabled {
+		return true
+	}
+
+	return os.Getenv(MONGODB_EXPLAIN_QUERIES) == "true"
+}
+
+type explainResult struct {
+	QueryPlanner struct {
+		WinningPlan bson.Raw `bson:"winningPlan"`
+	} `bson:"queryPlanner"`
+	ExecutionStats struct {
+		NReturned           int64 `bson:"nReturned"`
+		ExecutionTimeMillis int64 `bson:"executionTimeMillis"`
+		TotalKeysExamined   int64 `bson:"totalKeysExamined"`
+		TotalDocsExamined   int64 `bson:"totalDocsExamined"`
+	} `bson:"executionStats"`
+}
+
+// Explain logs the winning plan and execution stats of the find the caller is
+// about to run when explaining is enabled for ctx. It is diagnostics only, a
+// failing explain is logged and never fails the query itself
+func Explain(
+	ctx context.Context,
+	collection *mongo.Collection,
+	filter interface{},
+	opts ...*options.FindOptions) {
+	if !explainEnabled(ctx) {
+		return
+	}
+
+	find := bson.D{{Key: "find", Value: collection.Name()}, {Key: "filter", Value: filter}}
+	findOptions := options.MergeFindOptions(opts...)
+	if findOptions.Sort != nil {
+		find = append(find, bson.E{Key: "sort", Value: findOptions.Sort})
+	}
+	if findOptions.Skip != nil {
+		find = append(find, bson.E{Key: "skip", Value: *findOptions.Skip})
+	}
+	if findOptions.Limit != nil {
+		find = append(find, bson.E{Key: "limit", Value: *findOptions.Limit})
+	}
+	if findOptions.Projection != nil {
+		find = append(find, bson.E{Key: "projection", Value: findOptions.Projection})
+	}
+
+	command := bson.D{{Key: "explain", Value: find}, {Key: "verbosity", Value: "executionStats"}}
+
+	var result explainResult
+	if err := collection.Database().RunCommand(ctx, command).Decode(&result); err != nil {
+		logger.Error("Error trying to explain query", err, zap.String("collection", collection.Name()))
+		return
+	}
+
+	winningPlan := result.QueryPlanner.WinningPlan.String()
+
+	logger.Info("Query explain",
+		zap.String("collection", collection.Name()),
+		zap.String("winning_plan", winningPlan),
+		zap.Bool("collection_scan", strings.Contains(winningPlan, "COLLSCAN")),
+		zap.Int64("docs_examined", result.ExecutionStats.TotalDocsExamined),
+		zap.Int64("keys_examined", result.ExecutionStats.TotalKeysExamined),
+		zap.Int64("returned", result.ExecutionStats.NReturned),
+		zap.Int64("execution_time_ms", result.ExecutionStats.ExecutionTimeMillis))
+}
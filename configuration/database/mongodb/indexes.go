@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const MONGODB_INDEX_TIMEOUT = "MONGODB_INDEX_TIMEOUT"
+
+// Indexes lists the indexes the repositories rely on by collection, each
+// repository ensures the ones of the collections it owns when it is built
+var Indexes = map[string][]mongo.IndexModel{
+	"auctions": {
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "status", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "winner_user_id", Value: 1}, {Key: "end_time", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "start_time", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "end_time", Value: 1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "status", Value: 1}, {Key: "end_time", Value: 1}}},
+		{Keys: bson.D{{Key: "location", Value: "2dsphere"}}},
+		{
+			Keys: bson.D{{Key: "product_name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetWeights(bson.M{"product_name": 5, "description": 1}).
+				SetDefaultLanguage("none"),
+		},
+	},
+	"saved_searches": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "condition", Value: 1}}},
+	},
+	"devices": {
+		{Keys: bson.D{{Key: "platform", Value: 1}, {Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	},
+	"categories": {
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+	},
+	"receipts": {
+		{Keys: bson.D{{Key: "auction_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	},
+	"notification_digest_items": {
+		{Keys: bson.D{{Key: "due_at", Value: 1}}},
+	},
+	"auction_events": {
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "timestamp", Value: 1}}},
+	},
+	"outbox": {
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "sent_at", Value: 1}}},
+	},
+	"webhooks": {
+		{Keys: bson.D{{Key: "events", Value: 1}}},
+	},
+	"webhook_dead_letters": {
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	},
+	"auction_close_jobs": {
+		{Keys: bson.D{{Key: "end_time", Value: 1}}},
+	},
+	"bids": {
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: -1}, {Key: "sequence", Value: 1}}},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "auction_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "idempotency_key", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"idempotency_key": bson.M{"$exists": true}}),
+		},
+	},
+	"proxy_bids": {
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "max_amount", Value: -1}, {Key: "timestamp", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	},
+	"users": {
+		{
+			Keys: bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"email": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$exists": true}}),
+		},
+	},
+	"feedback": {
+		{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+	},
+	"watchlist": {
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "auction_id", Value: 1}}},
+	},
+	"api_keys": {
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	},
+	"checkouts": {
+		{Keys: bson.D{{Key: "auction_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "buyer_user_id", Value: 1}}},
+	},
+}
+
+// EnsureIndexes creates the indexes listed for the collections. Indexes
+// already there are left alone, so it is safe to run on every start. Each
+// index is created on its own, one conflicting with an existing index of the
+// same name doesn't keep the others from being built. Repositories only log
+// a failure, their queries still work without the indexes, just slower
+func EnsureIndexes(ctx context.Context, database *mongo.Database, collections ...string) error {
+	var errs []error
+	for _, collection := range collections {
+		for _, model := range Indexes[collection] {
+			if _, err := database.Collection(collection).Indexes().CreateOne(ctx, model); err != nil {
+				errs = append(errs, fmt.Errorf("creating index %v on %s: %w", model.Keys, collection, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// IndexContext bounds building the indexes on start by
+// MONGODB_INDEX_TIMEOUT, a minute by default
+func IndexContext() (context.Context, context.CancelFunc) {
+	timeout, err := time.ParseDuration(os.Getenv(MONGODB_INDEX_TIMEOUT))
+	if err != nil || timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	return context.WithTimeout(context.Background(), timeout)
+}
@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const MONGODB_STREAM_BATCH_SIZE = "MONGODB_STREAM_BATCH_SIZE"
+
+// Stream runs the query and hands each matching document to fn as soon as it
+// is decoded, so bulk reads keep a single batch in memory instead of the
+// whole result set. A fn error stops the iteration and is returned as is
+func Stream[T any](
+	ctx context.Context,
+	collection *mongo.Collection,
+	filter interface{},
+	fn func(document T) error,
+	opts ...*options.FindOptions) error {
+	findOptions := append([]*options.FindOptions{
+		options.Find().SetBatchSize(getStreamBatchSize())}, opts...)
+
+	cursor, err := collection.Find(ctx, filter, findOptions...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var document T
+		if err := cursor.Decode(&document); err != nil {
+			return err
+		}
+
+		if err := fn(document); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func getStreamBatchSize() int32 {
+	batchSize, err := strconv.ParseInt(os.Getenv(MONGODB_STREAM_BATCH_SIZE), 10, 32)
+	if err != nil || batchSize <= 0 {
+		return 500
+	}
+
+	return int32(batchSize)
+}
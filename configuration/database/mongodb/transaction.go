@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrNoTransactions is returned by CheckTransactions for standalone servers
+var ErrNoTransactions = errors.New(
+	"mongodb does not take transactions, run it as a replica set (a single node one will do)")
+
+// CheckTransactions makes sure the database runs on a replica set or a
+// sharded cluster. Outbox entries, bid events and price changes are written
+// in the same transaction as the state they belong to, so the application
+// refuses to start on a standalone server rather than lose them
+func CheckTransactions(ctx context.Context, database *mongo.Database) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return err
+	}
+
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return ErrNoTransactions
+	}
+	return nil
+}
+
+// RunInTransaction runs fn in a transaction, the writes fn makes through
+// the ctx it is given commit together or not at all. The driver retries fn
+// on transient errors, so it may run more than once
+func RunInTransaction(ctx context.Context, database *mongo.Database, fn func(ctx context.Context) error) error {
+	session, err := database.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
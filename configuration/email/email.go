@@ -0,0 +1,39 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"time"
+)
+
+// EMAIL_FROM is the address notification emails are sent from, it may carry
+// a display name like "Auctions <no-reply@example.com>"
+const EMAIL_FROM = "EMAIL_FROM"
+
+// Message is a notification email, HTML is the alternative to Text for
+// clients that render it
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender sends emails through one email service
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func getFrom() (*mail.Address, error) {
+	from, err := mail.ParseAddress(os.Getenv(EMAIL_FROM))
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an email address: %w", EMAIL_FROM, err)
+	}
+
+	return from, nil
+}
@@ -0,0 +1,167 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+)
+
+// SES emails are off while EMAIL_SES_REGION is unset, the credentials are
+// the usual AWS ones
+const (
+	EMAIL_SES_REGION      = "EMAIL_SES_REGION"
+	AWS_ACCESS_KEY_ID     = "AWS_ACCESS_KEY_ID"
+	AWS_SECRET_ACCESS_KEY = "AWS_SECRET_ACCESS_KEY"
+	AWS_SESSION_TOKEN     = "AWS_SESSION_TOKEN"
+)
+
+// SES sends emails through the Amazon SES v2 API, signing requests with
+// AWS Signature Version 4
+type SES struct {
+	region          string
+	accessKeyId     string
+	secretAccessKey string
+	sessionToken    string
+	from            *mail.Address
+}
+
+// NewSESFromEnv reads the region from EMAIL_SES_REGION, it returns nil
+// when that is unset
+func NewSESFromEnv() (*SES, error) {
+	region := os.Getenv(EMAIL_SES_REGION)
+	if region == "" {
+		return nil, nil
+	}
+
+	accessKeyId, secretAccessKey := os.Getenv(AWS_ACCESS_KEY_ID), os.Getenv(AWS_SECRET_ACCESS_KEY)
+	if accessKeyId == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("%s and %s must be set along with %s",
+			AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, EMAIL_SES_REGION)
+	}
+
+	from, err := getFrom()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SES{
+		region:          region,
+		accessKeyId:     accessKeyId,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    os.Getenv(AWS_SESSION_TOKEN),
+		from:            from,
+	}, nil
+}
+
+func (s *SES) Send(ctx context.Context, message Message) error {
+	body := map[string]any{
+		"Text": map[string]string{"Data": message.Text, "Charset": "UTF-8"},
+	}
+	if message.HTML != "" {
+		body["Html"] = map[string]string{"Data": message.HTML, "Charset": "UTF-8"}
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"FromEmailAddress": s.from.String(),
+		"Destination":      map[string]any{"ToAddresses": []string{message.To}},
+		"Content": map[string]any{"Simple": map[string]any{
+			"Subject": map[string]string{"Data": message.Subject, "Charset": "UTF-8"},
+			"Body":    body,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	host := fmt.Sprintf("email.%s.amazonaws.com", s.region)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://"+host+"/v2/email/outbound-emails", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	s.sign(request, host, payload, time.Now().UTC())
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		reason, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("SES answered %d %s", response.StatusCode, reason)
+	}
+
+	return nil
+}
+
+// sign adds the Signature Version 4 headers, see
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+func (s *SES) sign(request *http.Request, host string, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if s.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.sessionToken)
+		headers = append(headers, "x-amz-security-token")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, header := range headers {
+		value := request.Header.Get(header)
+		if header == "host" {
+			value = host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", header, strings.TrimSpace(value))
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/ses/aws4_request", date, s.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "ses")
+	key = hmacSHA256(key, "aws4_request")
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyId, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,176 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SMTP emails are off while EMAIL_SMTP_HOST is unset. EMAIL_SMTP_PORT
+// defaults to 587, port 465 is spoken over TLS from the start and any
+// other upgrades to TLS when the server offers it
+const (
+	EMAIL_SMTP_HOST     = "EMAIL_SMTP_HOST"
+	EMAIL_SMTP_PORT     = "EMAIL_SMTP_PORT"
+	EMAIL_SMTP_USERNAME = "EMAIL_SMTP_USERNAME"
+	EMAIL_SMTP_PASSWORD = "EMAIL_SMTP_PASSWORD"
+)
+
+// SMTP sends emails through an SMTP relay
+type SMTP struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     *mail.Address
+}
+
+// NewSMTPFromEnv reads the relay from EMAIL_SMTP_HOST, it returns nil when
+// that is unset
+func NewSMTPFromEnv() (*SMTP, error) {
+	host := os.Getenv(EMAIL_SMTP_HOST)
+	if host == "" {
+		return nil, nil
+	}
+
+	from, err := getFrom()
+	if err != nil {
+		return nil, err
+	}
+
+	port := os.Getenv(EMAIL_SMTP_PORT)
+	if port == "" {
+		port = "587"
+	}
+
+	return &SMTP{
+		host:     host,
+		port:     port,
+		username: os.Getenv(EMAIL_SMTP_USERNAME),
+		password: os.Getenv(EMAIL_SMTP_PASSWORD),
+		from:     from,
+	}, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, message Message) error {
+	body, err := s.compose(message)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(s.host, s.port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: s.host}
+	if s.port == "465" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		// PlainAuth refuses to send the password over a connection that
+		// isn't encrypted, unless the relay runs on localhost
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// compose builds the MIME message, text and HTML as alternatives of each
+// other
+func (s *SMTP) compose(message Message) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&header, "To: %s\r\n", message.To)
+	fmt.Fprintf(&header, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&header, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&header, "Message-ID: <%s@%s>\r\n",
+		uuid.New().String(), s.from.Address[strings.LastIndex(s.from.Address, "@")+1:])
+	fmt.Fprintf(&header, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&header, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+
+	return append(header.Bytes(), body.Bytes()...), nil
+}
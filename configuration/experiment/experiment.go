@@ -0,0 +1,135 @@
+package experiment
+
+import (
+	"auction_go/configuration/logger"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const EXPERIMENTS = "EXPERIMENTS"
+
+type Variant struct {
+	Name   string
+	Weight uint64
+}
+
+type Experiment struct {
+	Name     string
+	Variants []Variant
+}
+
+var (
+	experiments     map[string]Experiment
+	loadExperiments sync.Once
+)
+
+// VariantFor returns the variant of the experiment the user is bucketed into, or
+// an empty string when the experiment is not configured
+func VariantFor(experimentName, userId string) string {
+	experiment, ok := registry()[experimentName]
+	if !ok {
+		return ""
+	}
+
+	return experiment.assign(userId)
+}
+
+// Assignments returns every configured experiment with the user's variant
+func Assignments(userId string) map[string]string {
+	assignments := make(map[string]string)
+	for name, experiment := range registry() {
+		assignments[name] = experiment.assign(userId)
+	}
+
+	return assignments
+}
+
+func (e Experiment) assign(userId string) string {
+	var totalWeight uint64
+	for _, variant := range e.Variants {
+		totalWeight += variant.Weight
+	}
+
+	sum := sha256.Sum256([]byte(e.Name + ":" + userId))
+	bucket := binary.BigEndian.Uint64(sum[:8]) % totalWeight
+
+	for _, variant := range e.Variants {
+		if bucket < variant.Weight {
+			return variant.Name
+		}
+		bucket -= variant.Weight
+	}
+
+	return e.Variants[len(e.Variants)-1].Name
+}
+
+func registry() map[string]Experiment {
+	loadExperiments.Do(func() {
+		experiments = parseExperiments(os.Getenv(EXPERIMENTS))
+	})
+
+	return experiments
+}
+
+// parseExperiments reads definitions such as
+// "bid_sort:control=50,amount_desc=50;anti_snipe:control,short"
+// where a variant without an explicit weight counts as 1
+func parseExperiments(value string) map[string]Experiment {
+	parsed := make(map[string]Experiment)
+
+	for _, definition := range strings.Split(value, ";") {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+
+		experiment, err := parseExperiment(definition)
+		if err != nil {
+			logger.Error("Error trying to parse experiment definition", err,
+				zap.String("definition", definition))
+			continue
+		}
+
+		parsed[experiment.Name] = experiment
+	}
+
+	return parsed
+}
+
+func parseExperiment(definition string) (Experiment, error) {
+	name, variantList, found := strings.Cut(definition, ":")
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return Experiment{}, errors.New("experiment must be declared as name:variants")
+	}
+
+	experiment := Experiment{Name: name}
+	seen := make(map[string]bool)
+	for _, rawVariant := range strings.Split(variantList, ",") {
+		variantName, rawWeight, hasWeight := strings.Cut(strings.TrimSpace(rawVariant), "=")
+		variant := Variant{Name: strings.TrimSpace(variantName), Weight: 1}
+		if variant.Name == "" || seen[variant.Name] {
+			return Experiment{}, errors.New("variant names must be non-empty and unique")
+		}
+
+		if hasWeight {
+			weight, err := strconv.ParseUint(strings.TrimSpace(rawWeight), 10, 64)
+			if err != nil || weight == 0 {
+				return Experiment{}, errors.New("variant weight must be a positive integer")
+			}
+			variant.Weight = weight
+		}
+
+		seen[variant.Name] = true
+		experiment.Variants = append(experiment.Variants, variant)
+	}
+
+	return experiment, nil
+}
@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	JWT_SECRET = "JWT_SECRET"
+	JWT_TTL    = "JWT_TTL"
+)
+
+var (
+	ErrNoSecret     = errors.New("JWT_SECRET is not set")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Only HS256 is issued and accepted, tokens naming any other algorithm are
+// turned down before their signature is looked at
+var header = encode([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// PurposeEmailVerification marks tokens that confirm the user's email
+const PurposeEmailVerification = "email_verification"
+
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+
+	// Purpose is empty on login tokens, tokens issued for a purpose are only
+	// accepted by ParsePurpose
+	Purpose string `json:"pur,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// Issue signs a login token for the subject valid for JWT_TTL, a day by
+// default. Tokens can't be issued while JWT_SECRET is unset
+func Issue(subject string, now time.Time) (string, time.Time, error) {
+	return IssueClaims(Claims{Subject: subject}, now, getTTL())
+}
+
+// IssueClaims signs the claims valid for ttl from now
+func IssueClaims(claims Claims, now time.Time, ttl time.Duration) (string, time.Time, error) {
+	secret := os.Getenv(JWT_SECRET)
+	if secret == "" {
+		return "", time.Time{}, ErrNoSecret
+	}
+
+	expiresAt := now.Add(ttl)
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = expiresAt.Unix()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := header + "." + encode(payload)
+	return unsigned + "." + sign(unsigned, secret), expiresAt, nil
+}
+
+// Parse checks the login token's signature and expiry and returns its claims
+func Parse(token string, now time.Time) (*Claims, error) {
+	return ParsePurpose(token, "", now)
+}
+
+// ParsePurpose is Parse for tokens issued for purpose, a token issued for
+// anything else is invalid
+func ParsePurpose(token, purpose string, now time.Time) (*Claims, error) {
+	secret := os.Getenv(JWT_SECRET)
+	if secret == "" {
+		return nil, ErrNoSecret
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return nil, ErrInvalidToken
+	}
+
+	signature := sign(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(signature), []byte(parts[2])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" || claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func sign(unsigned, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return encode(mac.Sum(nil))
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func getTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv(JWT_TTL))
+	if err != nil || ttl <= 0 {
+		return 24 * time.Hour
+	}
+
+	return ttl
+}
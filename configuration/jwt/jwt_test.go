@@ -0,0 +1,183 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "test-secret"
+
+type JwtSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (suite *JwtSuite) SetupTest() {
+	suite.T().Setenv(JWT_SECRET, testSecret)
+	suite.T().Setenv(JWT_TTL, "")
+	suite.now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *JwtSuite) issue(claims Claims) string {
+	token, _, err := IssueClaims(claims, suite.now, time.Hour)
+	assert.Nil(suite.T(), err)
+	return token
+}
+
+// signedWith signs payload under a header of its own, as a forged token would
+func signedWith(rawHeader, payload, secret string) string {
+	unsigned := encode([]byte(rawHeader)) + "." + encode([]byte(payload))
+	return unsigned + "." + sign(unsigned, secret)
+}
+
+func (suite *JwtSuite) TestIssuedTokensParseUntilTheyExpire() {
+	token, expiresAt, err := Issue("user-1", suite.now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.now.Add(24*time.Hour), expiresAt)
+
+	claims, err := Parse(token, suite.now.Add(time.Minute))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "user-1", claims.Subject)
+	assert.Equal(suite.T(), suite.now.Unix(), claims.IssuedAt)
+
+	_, err = Parse(token, expiresAt)
+	assert.ErrorIs(suite.T(), err, ErrExpiredToken)
+}
+
+func (suite *JwtSuite) TestTokensAreTurnedDown() {
+	valid := suite.issue(Claims{Subject: "user-1"})
+	parts := strings.Split(valid, ".")
+	payload := `{"sub":"user-2","iat":1704110400,"exp":1704114000}`
+
+	cases := []struct {
+		name    string
+		token   string
+		purpose string
+		err     error
+	}{
+		{
+			name:  "malformed",
+			token: "not-a-token",
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "empty",
+			token: "",
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "tampered payload",
+			token: parts[0] + "." + encode([]byte(payload)) + "." + parts[2],
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "tampered signature",
+			token: parts[0] + "." + parts[1] + "." + encode([]byte("signature")),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "signed with another secret",
+			token: signedWith(`{"alg":"HS256","typ":"JWT"}`, payload, "other-secret"),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "alg none",
+			token: encode([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + encode([]byte(payload)) + ".",
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "another algorithm",
+			token: signedWith(`{"alg":"HS512","typ":"JWT"}`, payload, testSecret),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "the same header written differently",
+			token: signedWith(`{"typ":"JWT","alg":"HS256"}`, payload, testSecret),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "payload that isn't base64",
+			token: parts[0] + ".!!!." + sign(parts[0]+".!!!", testSecret),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "no subject",
+			token: suite.issue(Claims{}),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:  "issued for a purpose",
+			token: suite.issue(Claims{Subject: "user-1", Purpose: PurposeEmailVerification}),
+			err:   ErrInvalidToken,
+		},
+		{
+			name:    "login token used for a purpose",
+			token:   valid,
+			purpose: PurposeEmailVerification,
+			err:     ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			claims, err := ParsePurpose(tc.token, tc.purpose, suite.now)
+			assert.ErrorIs(suite.T(), err, tc.err)
+			assert.Nil(suite.T(), claims)
+		})
+	}
+}
+
+func (suite *JwtSuite) TestPurposeTokensCarryTheirClaims() {
+	token := suite.issue(Claims{Subject: "user-1", Purpose: PurposeEmailVerification, Email: "a@example.com"})
+
+	claims, err := ParsePurpose(token, PurposeEmailVerification, suite.now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "a@example.com", claims.Email)
+}
+
+func (suite *JwtSuite) TestOnlyHS256IsIssued() {
+	token := suite.issue(Claims{Subject: "user-1"})
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	assert.Nil(suite.T(), err)
+	assert.JSONEq(suite.T(), `{"alg":"HS256","typ":"JWT"}`, string(rawHeader))
+}
+
+func (suite *JwtSuite) TestNothingWorksWithoutASecret() {
+	token := suite.issue(Claims{Subject: "user-1"})
+	suite.T().Setenv(JWT_SECRET, "")
+
+	_, _, err := Issue("user-1", suite.now)
+	assert.ErrorIs(suite.T(), err, ErrNoSecret)
+
+	_, err = Parse(token, suite.now)
+	assert.ErrorIs(suite.T(), err, ErrNoSecret)
+}
+
+func (suite *JwtSuite) TestTTLFallsBackToADay() {
+	cases := []struct {
+		value string
+		ttl   time.Duration
+	}{
+		{value: "", ttl: 24 * time.Hour},
+		{value: "15m", ttl: 15 * time.Minute},
+		{value: "-1h", ttl: 24 * time.Hour},
+		{value: "soon", ttl: 24 * time.Hour},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.value, func() {
+			suite.T().Setenv(JWT_TTL, tc.value)
+			assert.Equal(suite.T(), tc.ttl, getTTL())
+		})
+	}
+}
+
+func TestJwtSuite(t *testing.T) {
+	suite.Run(t, new(JwtSuite))
+}
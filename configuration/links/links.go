@@ -0,0 +1,27 @@
+package links
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// APP_URL is where the web app is served, like "https://auctions.example.com".
+// Links sent to users point at its pages
+const APP_URL = "APP_URL"
+
+// Auction links to the auction's page, where the user can bid on it
+func Auction(auctionId string) string {
+	return link("/auctions/" + url.PathEscape(auctionId))
+}
+
+// Checkout links to the page where the winner pays for the auction
+func Checkout(auctionId string) string {
+	return link("/auctions/" + url.PathEscape(auctionId) + "/checkout")
+}
+
+// link is only the path while APP_URL is unset, clients resolve it against
+// the app they run in
+func link(path string) string {
+	return strings.TrimSuffix(os.Getenv(APP_URL), "/") + path
+}
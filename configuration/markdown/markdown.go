@@ -0,0 +1,29 @@
+package markdown
+
+import (
+	"auction_go/configuration/logger"
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+var (
+	// Raw HTML is let through the renderer on purpose: the sanitizer below is
+	// the single place deciding which tags and attributes survive
+	renderer = goldmark.New(goldmark.WithRendererOptions(html.WithUnsafe()))
+	policy   = bluemonday.UGCPolicy()
+)
+
+// Render converts a Markdown (or limited HTML) description into HTML that is
+// safe to embed in a page
+func Render(source string) string {
+	var buffer bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buffer); err != nil {
+		logger.Error("Error trying to render markdown description", err)
+		return policy.Sanitize(source)
+	}
+
+	return policy.Sanitize(buffer.String())
+}
@@ -0,0 +1,86 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	PIX_KEY           = "PIX_KEY"
+	PIX_MERCHANT_NAME = "PIX_MERCHANT_NAME"
+	PIX_MERCHANT_CITY = "PIX_MERCHANT_CITY"
+
+	// Static charges accept transaction ids of up to 25 alphanumeric chars
+	MaxTxIdLength = 25
+)
+
+type Charge struct {
+	Key          string
+	MerchantName string
+	MerchantCity string
+	TxId         string
+	Amount       float64
+}
+
+// Payload builds the BR Code of the charge, the EMV string buyers paste in
+// their bank app ("copia e cola") and that QR codes encode as is
+func Payload(charge Charge) string {
+	merchantAccount := field("00", "br.gov.bcb.pix") + field("01", charge.Key)
+
+	payload := field("00", "01") +
+		field("01", "12") +
+		field("26", merchantAccount) +
+		field("52", "0000") +
+		field("53", "986") +
+		field("54", fmt.Sprintf("%.2f", charge.Amount)) +
+		field("58", "BR") +
+		field("59", sanitize(charge.MerchantName, 25)) +
+		field("60", sanitize(charge.MerchantCity, 15)) +
+		field("62", field("05", charge.TxId)) +
+		"6304"
+
+	return payload + fmt.Sprintf("%04X", crc16(payload))
+}
+
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// Names and cities must be plain ASCII, accents are dropped rather than the
+// whole character
+func sanitize(value string, maxLength int) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(value) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ') {
+			builder.WriteRune(r)
+		}
+	}
+
+	sanitized := strings.ToUpper(strings.TrimSpace(builder.String()))
+	if len(sanitized) > maxLength {
+		sanitized = sanitized[:maxLength]
+	}
+
+	return sanitized
+}
+
+// crc16 is the CRC16-CCITT (polynomial 0x1021, initial value 0xFFFF) the BR
+// Code spec requires over the payload up to and including the CRC field id
+func crc16(payload string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(payload); i++ {
+		crc ^= uint16(payload[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
@@ -0,0 +1,196 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// APNs pushes are off while PUSH_APNS_KEY_FILE, the .p8 signing key, is
+// unset. PUSH_APNS_SANDBOX sends to the development environment
+const (
+	PUSH_APNS_KEY_FILE = "PUSH_APNS_KEY_FILE"
+	PUSH_APNS_KEY_ID   = "PUSH_APNS_KEY_ID"
+	PUSH_APNS_TEAM_ID  = "PUSH_APNS_TEAM_ID"
+	PUSH_APNS_TOPIC    = "PUSH_APNS_TOPIC"
+	PUSH_APNS_SANDBOX  = "PUSH_APNS_SANDBOX"
+)
+
+const (
+	apnsHost        = "https://api.push.apple.com"
+	apnsSandboxHost = "https://api.sandbox.push.apple.com"
+
+	// Apple turns down provider tokens older than an hour and throttles
+	// ones renewed more often than every 20 minutes
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// APNs sends pushes through the Apple Push Notification service, signing
+// in with a token based key
+type APNs struct {
+	host   string
+	keyId  string
+	teamId string
+	topic  string
+	key    *ecdsa.PrivateKey
+
+	providerToken string
+	issuedAt      time.Time
+	tokenMutex    *sync.Mutex
+}
+
+// NewAPNsFromEnv reads the signing key from PUSH_APNS_KEY_FILE, it returns
+// nil when that is unset
+func NewAPNsFromEnv() (*APNs, error) {
+	path := os.Getenv(PUSH_APNS_KEY_FILE)
+	if path == "" {
+		return nil, nil
+	}
+
+	keyId, teamId, topic := os.Getenv(PUSH_APNS_KEY_ID), os.Getenv(PUSH_APNS_TEAM_ID), os.Getenv(PUSH_APNS_TOPIC)
+	if keyId == "" || teamId == "" || topic == "" {
+		return nil, fmt.Errorf("%s, %s and %s must be set along with %s",
+			PUSH_APNS_KEY_ID, PUSH_APNS_TEAM_ID, PUSH_APNS_TOPIC, PUSH_APNS_KEY_FILE)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("APNs key file holds no private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs key is not an EC key")
+	}
+
+	host := apnsHost
+	if os.Getenv(PUSH_APNS_SANDBOX) == "true" {
+		host = apnsSandboxHost
+	}
+
+	return &APNs{
+		host:       host,
+		keyId:      keyId,
+		teamId:     teamId,
+		topic:      topic,
+		key:        key,
+		tokenMutex: &sync.Mutex{},
+	}, nil
+}
+
+func (a *APNs) Send(ctx context.Context, token string, message Message) error {
+	providerToken, err := a.getProviderToken()
+	if err != nil {
+		return err
+	}
+
+	// Custom data sits next to the aps dictionary
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": message.Title, "body": message.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		a.host+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "bearer "+providerToken)
+	request.Header.Set("apns-topic", a.topic)
+	request.Header.Set("apns-push-type", "alert")
+	request.Header.Set("apns-priority", "10")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var answer struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(response.Body).Decode(&answer)
+
+	switch answer.Reason {
+	case "BadDeviceToken", "DeviceTokenNotForTopic", "Unregistered":
+		return ErrInvalidToken
+	case "ExpiredProviderToken":
+		a.tokenMutex.Lock()
+		a.providerToken = ""
+		a.tokenMutex.Unlock()
+		return fmt.Errorf("%w: APNs turned down the provider token", ErrUnavailable)
+	}
+
+	return statusError("APNs", response.StatusCode, answer.Reason)
+}
+
+// getProviderToken signs the ES256 token APNs authenticates providers
+// with, reused for apnsTokenTTL
+func (a *APNs) getProviderToken() (string, error) {
+	a.tokenMutex.Lock()
+	defer a.tokenMutex.Unlock()
+
+	now := time.Now()
+	if a.providerToken != "" && now.Sub(a.issuedAt) < apnsTokenTTL {
+		return a.providerToken, nil
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": a.keyId})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{"iss": a.teamId, "iat": now.Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	// JWS wants r and s as fixed size big endian halves, not ASN.1
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	a.providerToken = unsigned + "." + encode(signature)
+	a.issuedAt = now
+
+	return a.providerToken, nil
+}
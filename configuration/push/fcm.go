@@ -0,0 +1,227 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PUSH_FCM_CREDENTIALS_FILE is the Firebase service account key, FCM
+// pushes are off while it is unset
+const PUSH_FCM_CREDENTIALS_FILE = "PUSH_FCM_CREDENTIALS_FILE"
+
+const (
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmEndpoint = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+)
+
+type fcmCredentials struct {
+	ProjectId   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCM sends pushes through the Firebase Cloud Messaging HTTP v1 API,
+// authenticating as the service account
+type FCM struct {
+	credentials fcmCredentials
+	key         *rsa.PrivateKey
+
+	accessToken string
+	expiresAt   time.Time
+	tokenMutex  *sync.Mutex
+}
+
+// NewFCMFromEnv reads the service account from PUSH_FCM_CREDENTIALS_FILE,
+// it returns nil when that is unset
+func NewFCMFromEnv() (*FCM, error) {
+	path := os.Getenv(PUSH_FCM_CREDENTIALS_FILE)
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials fcmCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
+	if credentials.ProjectId == "" || credentials.ClientEmail == "" || credentials.TokenURI == "" {
+		return nil, errors.New("FCM credentials miss the project, client email or token URI")
+	}
+
+	block, _ := pem.Decode([]byte(credentials.PrivateKey))
+	if block == nil {
+		return nil, errors.New("FCM credentials hold no private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("FCM private key is not an RSA key")
+	}
+
+	return &FCM{credentials: credentials, key: key, tokenMutex: &sync.Mutex{}}, nil
+}
+
+func (f *FCM) Send(ctx context.Context, token string, message Message) error {
+	accessToken, err := f.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token":        token,
+			"notification": map[string]string{"title": message.Title, "body": message.Body},
+			"data":         message.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(fcmEndpoint, f.credentials.ProjectId), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var answer struct {
+		Error struct {
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	json.NewDecoder(response.Body).Decode(&answer)
+
+	for _, detail := range answer.Error.Details {
+		switch detail.ErrorCode {
+		case "UNREGISTERED", "SENDER_ID_MISMATCH":
+			return ErrInvalidToken
+		}
+	}
+
+	// The access token was revoked or expired early, the next try gets a
+	// new one
+	if response.StatusCode == http.StatusUnauthorized {
+		f.tokenMutex.Lock()
+		f.accessToken = ""
+		f.tokenMutex.Unlock()
+		return fmt.Errorf("%w: FCM turned down the access token", ErrUnavailable)
+	}
+
+	return statusError("FCM", response.StatusCode, answer.Error.Message)
+}
+
+// getAccessToken trades a JWT signed with the service account key for an
+// OAuth access token, reused until shortly before it expires
+func (f *FCM) getAccessToken(ctx context.Context) (string, error) {
+	f.tokenMutex.Lock()
+	defer f.tokenMutex.Unlock()
+
+	now := time.Now()
+	if f.accessToken != "" && now.Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	assertion, err := f.signAssertion(now)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		f.credentials.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", statusError("Google OAuth", response.StatusCode, "")
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	f.accessToken = token.AccessToken
+	f.expiresAt = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+
+	return f.accessToken, nil
+}
+
+func (f *FCM) signAssertion(now time.Time) (string, error) {
+	claims, err := json.Marshal(map[string]any{
+		"iss":   f.credentials.ClientEmail,
+		"scope": fcmScope,
+		"aud":   f.credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encode([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + encode(signature), nil
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,43 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrInvalidToken means the push service no longer knows the device,
+	// its token should be forgotten
+	ErrInvalidToken = errors.New("push token is no longer valid")
+
+	// ErrUnavailable means the push service failed for now, the push may
+	// be sent again later
+	ErrUnavailable = errors.New("push service is unavailable")
+)
+
+// Message is what the device shows, Data reaches the app along with it
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Provider sends pushes through one push service
+type Provider interface {
+	Send(ctx context.Context, token string, message Message) error
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// statusError tells retryable answers apart from the ones a retry won't
+// fix, invalid tokens are told apart by each provider
+func statusError(service string, status int, reason string) error {
+	if status == http.StatusTooManyRequests || status >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %s answered %d %s", ErrUnavailable, service, status, reason)
+	}
+
+	return fmt.Errorf("%s answered %d %s", service, status, reason)
+}
@@ -2,7 +2,10 @@ package rest_err
 
 import (
 	"auction_go/internal/internal_error"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type RestErr struct {
@@ -10,6 +13,9 @@ type RestErr struct {
 	Err     string   `json:"err"`
 	Code    int      `json:"code"`
 	Causes  []Causes `json:"causes"`
+
+	// RetryAfter is sent as the Retry-After header, see SetRetryAfter
+	RetryAfter time.Duration `json:"-"`
 }
 
 type Causes struct {
@@ -24,9 +30,21 @@ func (r *RestErr) Error() string {
 func ConvertError(internalError *internal_error.InternalError) *RestErr {
 	switch internalError.Err {
 	case "bad_request":
-		return NewBadRequestError(internalError.Error())
+		var causes []Causes
+		for _, cause := range internalError.Causes {
+			causes = append(causes, Causes{Field: cause.Field, Message: cause.Message})
+		}
+		return NewBadRequestError(internalError.Error(), causes...)
 	case "not_found":
 		return NewNotFoundError(internalError.Error())
+	case "unauthorized":
+		return NewUnauthorizedError(internalError.Error())
+	case "forbidden":
+		return NewForbiddenError(internalError.Error())
+	case "invalid_transition":
+		return NewConflictError(internalError.Error())
+	case "too_many_requests":
+		return NewTooManyRequestsError(internalError.Error(), internalError.RetryAfter)
 	default:
 		return NewInternalServerError(internalError.Error())
 	}
@@ -41,6 +59,17 @@ func NewBadRequestError(message string, causes ...Causes) *RestErr {
 	}
 }
 
+// NewUnprocessableEntityError reports a well-formed body that doesn't match
+// the route's schema, causes point at the offending fields
+func NewUnprocessableEntityError(message string, causes ...Causes) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unprocessable_entity",
+		Code:    http.StatusUnprocessableEntity,
+		Causes:  causes,
+	}
+}
+
 func NewInternalServerError(message string) *RestErr {
 	return &RestErr{
 		Message: message,
@@ -50,6 +79,33 @@ func NewInternalServerError(message string) *RestErr {
 	}
 }
 
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unauthorized",
+		Code:    http.StatusUnauthorized,
+		Causes:  nil,
+	}
+}
+
+func NewForbiddenError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "forbidden",
+		Code:    http.StatusForbidden,
+		Causes:  nil,
+	}
+}
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "conflict",
+		Code:    http.StatusConflict,
+		Causes:  nil,
+	}
+}
+
 func NewNotFoundError(message string) *RestErr {
 	return &RestErr{
 		Message: message,
@@ -58,3 +114,23 @@ func NewNotFoundError(message string) *RestErr {
 		Causes:  nil,
 	}
 }
+
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *RestErr {
+	return &RestErr{
+		Message:    message,
+		Err:        "too_many_requests",
+		Code:       http.StatusTooManyRequests,
+		Causes:     nil,
+		RetryAfter: retryAfter,
+	}
+}
+
+// SetRetryAfter tells the client how many seconds to wait before trying
+// again, rounded up
+func (r *RestErr) SetRetryAfter(header http.Header) {
+	if r.RetryAfter <= 0 {
+		return
+	}
+
+	header.Set("Retry-After", strconv.Itoa(int(math.Ceil(r.RetryAfter.Seconds()))))
+}
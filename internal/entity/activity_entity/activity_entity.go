@@ -0,0 +1,35 @@
+package activity_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type ActivityType string
+
+const (
+	AuctionCreated ActivityType = "auction_created"
+	BidPlaced      ActivityType = "bid_placed"
+	AuctionWon     ActivityType = "auction_won"
+)
+
+// Activity is one entry of a user's timeline. Amount is the bid for bids
+// and the winning amount for won auctions, ReferenceId is the bid or
+// auction the entry is about
+type Activity struct {
+	Type        ActivityType
+	ReferenceId string
+	AuctionId   string
+	Amount      float64
+	Timestamp   time.Time
+}
+
+type ActivityRepositoryInterface interface {
+	// FindActivityByUserId returns one page of the user's timeline, newest
+	// first, along with the total number of entries
+	FindActivityByUserId(
+		ctx context.Context,
+		userId string,
+		skip, limit int64) ([]Activity, int64, *internal_error.InternalError)
+}
@@ -0,0 +1,134 @@
+package admin_entity
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus int
+
+const (
+	Pending JobStatus = iota
+	Running
+	Finished
+	Failed
+)
+
+// Actions a bulk status job can apply to the matching auctions
+const (
+	ActionClose  = "close"
+	ActionCancel = "cancel"
+
+	ActionAdjustEndTime = "adjust_end_time"
+	ActionPause         = "pause"
+	ActionResume        = "resume"
+	ActionReopen        = "reopen"
+)
+
+type BulkStatusFilter struct {
+	Category      string
+	SellerId      string
+	CreatedBefore time.Time
+}
+
+type BulkStatusJob struct {
+	Id          string
+	Action      string
+	Filter      BulkStatusFilter
+	Reason      string
+	RequestedBy string
+	Status      JobStatus
+	Total       int64
+	Processed   int64
+	Changed     int64
+	Error       string
+	Timestamp   time.Time
+	FinishedAt  time.Time
+}
+
+// AuditEntry records a single auction changed by an admin, JobId is only set
+// when the change was made by a bulk job
+type AuditEntry struct {
+	Id              string
+	JobId           string
+	AuctionId       string
+	Action          string
+	PreviousStatus  auction_entity.AuctionStatus
+	NewStatus       auction_entity.AuctionStatus
+	PreviousEndTime time.Time
+	NewEndTime      time.Time
+	Reason          string
+	RequestedBy     string
+	Timestamp       time.Time
+}
+
+func CreateBulkStatusJob(
+	action string,
+	filter BulkStatusFilter,
+	reason, requestedBy string) (*BulkStatusJob, *internal_error.InternalError) {
+	job := &BulkStatusJob{
+		Id:          uuid.New().String(),
+		Action:      action,
+		Filter:      filter,
+		Reason:      reason,
+		RequestedBy: requestedBy,
+		Status:      Pending,
+		Timestamp:   time.Now(),
+	}
+
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (j *BulkStatusJob) Validate() *internal_error.InternalError {
+	if j.Action != ActionClose && j.Action != ActionCancel {
+		return internal_error.NewBadRequestError("Action must be close or cancel")
+	} else if j.Reason == "" || j.RequestedBy == "" {
+		return internal_error.NewBadRequestError("Reason and requester are required")
+	} else if j.Filter.Category == "" && j.Filter.SellerId == "" && j.Filter.CreatedBefore.IsZero() {
+		return internal_error.NewBadRequestError("At least one filter is required")
+	} else if j.Filter.SellerId != "" && uuid.Validate(j.Filter.SellerId) != nil {
+		return internal_error.NewBadRequestError("SellerId is not a valid id")
+	}
+
+	return nil
+}
+
+// TargetStatus is the auction status the job action leads to
+func (j *BulkStatusJob) TargetStatus() auction_entity.AuctionStatus {
+	if j.Action == ActionCancel {
+		return auction_entity.Cancelled
+	}
+
+	return auction_entity.Completed
+}
+
+type AdminRepositoryInterface interface {
+	CreateBulkStatusJob(
+		ctx context.Context,
+		jobEntity *BulkStatusJob) *internal_error.InternalError
+
+	UpdateBulkStatusJob(
+		ctx context.Context,
+		jobEntity *BulkStatusJob) *internal_error.InternalError
+
+	FindBulkStatusJobById(
+		ctx context.Context, id string) (*BulkStatusJob, *internal_error.InternalError)
+
+	CreateAuditEntries(
+		ctx context.Context,
+		auditEntries []AuditEntry) *internal_error.InternalError
+
+	FindAuditEntriesByJobId(
+		ctx context.Context, jobId string) ([]AuditEntry, *internal_error.InternalError)
+
+	FindAuditEntriesByAuctionId(
+		ctx context.Context, auctionId string) ([]AuditEntry, *internal_error.InternalError)
+}
@@ -0,0 +1,59 @@
+package announcement_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Announcements are append-only: a correction is published as a new
+// announcement pointing at the one it replaces, never as an edit
+type Announcement struct {
+	Id         string
+	AuctionId  string
+	Message    string
+	ReplacesId string
+	Timestamp  time.Time
+}
+
+func CreateAnnouncement(auctionId, message, replacesId string) (*Announcement, *internal_error.InternalError) {
+	announcement := &Announcement{
+		Id:         uuid.New().String(),
+		AuctionId:  auctionId,
+		Message:    message,
+		ReplacesId: replacesId,
+		Timestamp:  time.Now(),
+	}
+
+	if err := announcement.Validate(); err != nil {
+		return nil, err
+	}
+
+	return announcement, nil
+}
+
+func (a *Announcement) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(a.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	} else if len(a.Message) < 3 || len(a.Message) > 500 {
+		return internal_error.NewBadRequestError("Message must have between 3 and 500 characters")
+	} else if a.ReplacesId != "" && uuid.Validate(a.ReplacesId) != nil {
+		return internal_error.NewBadRequestError("ReplacesId is not a valid id")
+	}
+
+	return nil
+}
+
+type AnnouncementRepositoryInterface interface {
+	CreateAnnouncement(
+		ctx context.Context,
+		announcementEntity *Announcement) *internal_error.InternalError
+
+	FindAnnouncementById(
+		ctx context.Context, id string) (*Announcement, *internal_error.InternalError)
+
+	FindAnnouncementsByAuctionId(
+		ctx context.Context, auctionId string) ([]Announcement, *internal_error.InternalError)
+}
@@ -0,0 +1,169 @@
+package api_key_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxApiKeys bounds how many keys a user may hold at once
+const MaxApiKeys = 10
+
+// MaxNameLength bounds the label a user gives a key
+const MaxNameLength = 100
+
+// keyPrefix starts every key, so leaked keys are easy to spot
+const keyPrefix = "ak_"
+
+// Scope is what a key may be used for
+type Scope string
+
+const (
+	ScopeRead Scope = "read"
+	ScopeBid  Scope = "bid"
+)
+
+// Allows tells whether a key granted s may be used for scope, bidding
+// includes reading
+func (s Scope) Allows(scope Scope) bool {
+	return s == scope || (s == ScopeBid && scope == ScopeRead)
+}
+
+// ApiKey lets a machine client act as the user. Only the key's SHA-256 hash
+// is kept, keys are random enough that a slow hash buys nothing. Prefix is
+// the start of the key, shown so users can tell their keys apart
+type ApiKey struct {
+	Id        string
+	UserId    string
+	Name      string
+	Prefix    string
+	Hash      string
+	Scopes    []Scope
+	CreatedAt time.Time
+	RotatedAt time.Time
+}
+
+// CreateApiKey returns the key along with the secret, which is shown once
+// and never stored
+func CreateApiKey(userId, name string, scopes []Scope) (*ApiKey, string, *internal_error.InternalError) {
+	apiKey := &ApiKey{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Name:      strings.TrimSpace(name),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+
+	if err := apiKey.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := apiKey.newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, secret, nil
+}
+
+// Rotate gives the key a new secret, the old one stops working once stored
+func (k *ApiKey) Rotate(now time.Time) (string, *internal_error.InternalError) {
+	secret, err := k.newSecret()
+	if err != nil {
+		return "", err
+	}
+
+	k.RotatedAt = now
+	return secret, nil
+}
+
+func (k *ApiKey) newSecret() (string, *internal_error.InternalError) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", internal_error.NewInternalServerError("Error trying to generate API key")
+	}
+
+	secret := keyPrefix + base64.RawURLEncoding.EncodeToString(random)
+	k.Hash = HashKey(secret)
+	k.Prefix = secret[:len(keyPrefix)+6]
+	return secret, nil
+}
+
+// Allows tells whether any of the key's scopes covers scope
+func (k *ApiKey) Allows(scope Scope) bool {
+	for _, granted := range k.Scopes {
+		if granted.Allows(scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *ApiKey) Validate() *internal_error.InternalError {
+	var causes []internal_error.Cause
+	if uuid.Validate(k.UserId) != nil {
+		causes = append(causes, internal_error.Cause{Field: "user_id", Message: "Invalid UUID value"})
+	}
+	if k.Name == "" || len(k.Name) > MaxNameLength {
+		causes = append(causes, internal_error.Cause{
+			Field:   "name",
+			Message: fmt.Sprintf("Must be between 1 and %d characters", MaxNameLength),
+		})
+	}
+	if len(k.Scopes) == 0 {
+		causes = append(causes, internal_error.Cause{Field: "scopes", Message: "At least one scope is required"})
+	}
+	for _, scope := range k.Scopes {
+		if scope != ScopeRead && scope != ScopeBid {
+			causes = append(causes, internal_error.Cause{
+				Field:   "scopes",
+				Message: fmt.Sprintf("Unknown scope %q, must be %s or %s", scope, ScopeRead, ScopeBid),
+			})
+		}
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewValidationError("Invalid API key", causes...)
+	}
+
+	return nil
+}
+
+// HashKey is how keys are stored and looked up
+func HashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+type ApiKeyRepositoryInterface interface {
+	CreateApiKey(
+		ctx context.Context, apiKey *ApiKey) *internal_error.InternalError
+
+	FindApiKeysByUserId(
+		ctx context.Context, userId string) ([]ApiKey, *internal_error.InternalError)
+
+	FindApiKeyById(
+		ctx context.Context, userId, keyId string) (*ApiKey, *internal_error.InternalError)
+
+	FindApiKeyByHash(
+		ctx context.Context, hash string) (*ApiKey, *internal_error.InternalError)
+
+	CountApiKeysByUserId(
+		ctx context.Context, userId string) (int64, *internal_error.InternalError)
+
+	// RotateApiKey stores the key's new hash, the old one stops matching
+	RotateApiKey(
+		ctx context.Context, apiKey *ApiKey) *internal_error.InternalError
+
+	DeleteApiKey(
+		ctx context.Context, userId, keyId string) *internal_error.InternalError
+}
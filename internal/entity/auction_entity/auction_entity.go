@@ -3,14 +3,31 @@ package auction_entity
 import (
 	"auction_go/internal/internal_error"
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Bounds of the duration a seller may pick for an auction
+const (
+	MinDuration = time.Minute
+	MaxDuration = 30 * 24 * time.Hour
+)
+
+// MaxRelists bounds how many times an unsold auction may be relisted
+const MaxRelists = 10
+
+// CreateAuction builds an auction that is active right away, or scheduled
+// when startTime is in the future. A zero duration leaves it to the default
+// AUCTION_INTERVAL
 func CreateAuction(
 	productName, category, description string,
-	condition ProductCondition) (*Auction, *internal_error.InternalError) {
+	condition ProductCondition,
+	startTime time.Time,
+	duration time.Duration) (*Auction, *internal_error.InternalError) {
+	now := time.Now()
+
 	auction := &Auction{
 		Id:          uuid.New().String(),
 		ProductName: productName,
@@ -18,7 +35,14 @@ func CreateAuction(
 		Description: description,
 		Condition:   condition,
 		Status:      Active,
-		Timestamp:   time.Now(),
+		Timestamp:   now,
+		StartTime:   now,
+		Duration:    duration,
+	}
+
+	if startTime.After(now) {
+		auction.Status = Scheduled
+		auction.StartTime = startTime
 	}
 
 	if err := auction.Validate(); err != nil {
@@ -37,17 +61,223 @@ func (au *Auction) Validate() *internal_error.InternalError {
 		return internal_error.NewBadRequestError("invalid auction object")
 	}
 
+	if au.Type != English && au.Type != Dutch {
+		return internal_error.NewBadRequestError("invalid auction type")
+	}
+
+	if au.Type == Dutch {
+		if au.BuyNowPrice > 0 || au.ReservePrice > 0 {
+			return internal_error.NewBadRequestError(
+				"Dutch auctions take no buy now or reserve price, the floor price is the reserve")
+		}
+		if err := au.DutchPricing.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if au.MinIncrement < 0 {
+		return internal_error.NewBadRequestError("MinIncrement can't be negative")
+	}
+
+	if au.RelistPolicy.MaxRelists < 0 || au.RelistPolicy.MaxRelists > MaxRelists {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("MaxRelists must be between 0 and %d", MaxRelists))
+	}
+
+	if au.Duration != 0 && (au.Duration < MinDuration || au.Duration > MaxDuration) {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Duration must be between %s and %s", MinDuration, MaxDuration))
+	}
+
+	if au.Location != nil {
+		if err := au.Location.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 type Auction struct {
-	Id          string
-	ProductName string
-	Category    string
-	Description string
-	Condition   ProductCondition
-	Status      AuctionStatus
-	Timestamp   time.Time
+	Id              string
+	SellerId        string
+	ProductName     string
+	Category        string
+	Description     string
+	DescriptionHTML string
+	Condition       ProductCondition
+	Status          AuctionStatus
+	Timestamp       time.Time
+	StartTime       time.Time
+	Duration        time.Duration
+	EndTime         time.Time
+	WinnerUserId    string
+	WinningAmount   float64
+
+	// ReservePrice is the lowest winning bid the seller accepts, zero for no
+	// reserve. Buyers only see its amount when ReservePublic is set
+	ReservePrice  float64
+	ReservePublic bool
+
+	// BuyNowPrice lets a buyer end the auction right away, zero when the
+	// seller doesn't offer it
+	BuyNowPrice float64
+
+	// MinIncrement is the least a bid has to add to the current price, zero
+	// leaves it to the default BID_INCREMENT
+	MinIncrement float64
+
+	// Type is how the price moves, Dutch auctions follow DutchPricing down
+	// and DutchPrice is what they sell for right now
+	Type         AuctionType
+	DutchPricing DutchPricing
+	DutchPrice   float64
+
+	RelistPolicy RelistPolicy
+	// RelistCount is how many times the product was put back on sale before
+	// this auction, RelistedFrom the auction it was relisted from
+	RelistCount  int
+	RelistedFrom string
+
+	CancellationReason string
+	CancelledAt        time.Time
+
+	// PausedAt is only set while the auction is paused
+	PausedAt time.Time
+
+	// Location is where the product can be picked up, nil when the seller
+	// gave no coordinates. Only auctions with a Location are found by
+	// distance, PostalCode is shown as given
+	Location   *GeoPoint
+	PostalCode string
+}
+
+// GeoPoint is a position on Earth in degrees
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (p GeoPoint) Validate() *internal_error.InternalError {
+	if p.Latitude < -90 || p.Latitude > 90 {
+		return internal_error.NewBadRequestError("Latitude must be between -90 and 90")
+	}
+
+	if p.Longitude < -180 || p.Longitude > 180 {
+		return internal_error.NewBadRequestError("Longitude must be between -180 and 180")
+	}
+
+	return nil
+}
+
+// ReserveMet reports whether a winning bid of amount would sell the product,
+// always true without a reserve
+func (au *Auction) ReserveMet(amount float64) bool {
+	return amount >= au.ReservePrice
+}
+
+// Remaining is how long the auction has left to run at now. The clock
+// stands still while it is paused, and auctions that ended have none left
+func (au *Auction) Remaining(now time.Time) time.Duration {
+	switch au.Status {
+	case Active, Scheduled:
+	case Paused:
+		now = au.PausedAt
+	default:
+		return 0
+	}
+
+	if remaining := au.EndTime.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// RelistPolicy puts an auction that ends unsold back on sale as a new
+// auction with the same duration, at most MaxRelists times
+type RelistPolicy struct {
+	MaxRelists int
+}
+
+// Relist builds the auction that puts an unsold one back on sale, starting
+// now. It returns nil once the policy allows no more relists
+func (au *Auction) Relist() *Auction {
+	if au.RelistCount >= au.RelistPolicy.MaxRelists {
+		return nil
+	}
+
+	now := time.Now()
+	return &Auction{
+		Id:              uuid.New().String(),
+		SellerId:        au.SellerId,
+		ProductName:     au.ProductName,
+		Category:        au.Category,
+		Description:     au.Description,
+		DescriptionHTML: au.DescriptionHTML,
+		Condition:       au.Condition,
+		Status:          Active,
+		Timestamp:       now,
+		StartTime:       now,
+		Duration:        au.Duration,
+		ReservePrice:    au.ReservePrice,
+		ReservePublic:   au.ReservePublic,
+		BuyNowPrice:     au.BuyNowPrice,
+		MinIncrement:    au.MinIncrement,
+		Type:            au.Type,
+		DutchPricing:    au.DutchPricing,
+		RelistPolicy:    au.RelistPolicy,
+		RelistCount:     au.RelistCount + 1,
+		RelistedFrom:    au.Id,
+		Location:        au.Location,
+		PostalCode:      au.PostalCode,
+	}
+}
+
+type AuctionType int
+
+const (
+	// English auctions go up, the highest bid wins when time runs out
+	English AuctionType = iota
+	// Dutch auctions go down, the first bid at the current price wins and
+	// closes the auction
+	Dutch
+)
+
+// DutchPricing lowers the price of a Dutch auction by Decrement every
+// Interval, starting at StartPrice and never going below FloorPrice
+type DutchPricing struct {
+	StartPrice float64
+	FloorPrice float64
+	Decrement  float64
+	Interval   time.Duration
+}
+
+func (p DutchPricing) Validate() *internal_error.InternalError {
+	if p.StartPrice <= 0 || p.FloorPrice < 0 || p.FloorPrice >= p.StartPrice {
+		return internal_error.NewBadRequestError("StartPrice must be above the FloorPrice")
+	}
+
+	if p.Decrement <= 0 {
+		return internal_error.NewBadRequestError("Decrement must be positive")
+	}
+
+	if p.Interval < time.Second {
+		return internal_error.NewBadRequestError("Interval must be at least a second")
+	}
+
+	return nil
+}
+
+// NextPrice is the price one drop after price
+func (p DutchPricing) NextPrice(price float64) float64 {
+	return max(price-p.Decrement, p.FloorPrice)
+}
+
+// AuctionWinner is the highest bid as recorded when the auction closed
+type AuctionWinner struct {
+	AuctionId string
+	UserId    string
+	Amount    float64
 }
 
 type ProductCondition int
@@ -56,24 +286,222 @@ type AuctionStatus int
 const (
 	Active AuctionStatus = iota
 	Completed
+	Cancelled
+	Scheduled
+	Paused
+	// CompletedNotSold auctions ended with no bid reaching the reserve
+	CompletedNotSold
 )
 
+// transitions lists the statuses each status may move to. Completed auctions
+// only go back to Active when an admin reopens them, Cancelled is final
+var transitions = map[AuctionStatus][]AuctionStatus{
+	Scheduled: {Active, Cancelled},
+	Active:    {Completed, CompletedNotSold, Cancelled, Paused},
+	Paused:    {Active, Cancelled},
+	Completed: {Active},
+}
+
+// CanTransition reports whether an auction may move from one status to the
+// other
+func CanTransition(from, to AuctionStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewInvalidTransitionError(from, to AuctionStatus) *internal_error.InternalError {
+	return internal_error.NewInvalidTransitionError(
+		fmt.Sprintf("Auction can't move from %s to %s", from, to))
+}
+
+func (s AuctionStatus) String() string {
+	switch s {
+	case Active:
+		return "active"
+	case Completed:
+		return "completed"
+	case Cancelled:
+		return "cancelled"
+	case Scheduled:
+		return "scheduled"
+	case Paused:
+		return "paused"
+	case CompletedNotSold:
+		return "completed not sold"
+	default:
+		return fmt.Sprintf("status %d", int(s))
+	}
+}
+
 const (
 	New ProductCondition = iota + 1
 	Used
 	Refurbished
 )
 
+// AuctionSort is the order auctions are listed in, ties are broken by id
+type AuctionSort string
+
+const (
+	SortByEndTime   AuctionSort = "end_time"
+	SortByCreatedAt AuctionSort = "created_at"
+	SortByPrice     AuctionSort = "price"
+)
+
+// AuctionQuery filters, orders and pages a listing of auctions, zero fields
+// don't filter. Price is the current price, the highest bid so far or zero
+// when nobody bid yet
+type AuctionQuery struct {
+	Status             *AuctionStatus
+	Category           string
+	Condition          ProductCondition
+	SellerId           string
+	MinPrice           float64
+	MaxPrice           float64
+	ProductNameQueries []string
+
+	// EndsBefore and CreatedAfter bound the end and creation times, zero
+	// times don't
+	EndsBefore   time.Time
+	CreatedAfter time.Time
+
+	// Near only lists auctions located within RadiusKm of it
+	Near     *GeoPoint
+	RadiusKm float64
+
+	Sort       AuctionSort
+	Descending bool
+
+	// Cursor is where the previous page ended, Limit is the page size and
+	// zero lists every auction that matches
+	Cursor string
+	Limit  int64
+}
+
+// PriceFacetBoundaries split current prices into the buckets counted by
+// FindAuctionFacets, prices from the last boundary up share one bucket
+var PriceFacetBoundaries = []float64{0, 50, 100, 250, 500, 1000, 5000}
+
+// AuctionFacets counts the auctions a query matches by category, condition
+// and current price bucket, values nothing matched are left out
+type AuctionFacets struct {
+	Categories []CategoryFacet
+	Conditions []ConditionFacet
+	Prices     []PriceFacet
+}
+
+type CategoryFacet struct {
+	Category string
+	Count    int64
+}
+
+type ConditionFacet struct {
+	Condition ProductCondition
+	Count     int64
+}
+
+// PriceFacet counts the prices from Min up to, but not including, Max. The
+// last bucket has a zero Max
+type PriceFacet struct {
+	Min   float64
+	Max   float64
+	Count int64
+}
+
+// AuctionSearch finds auctions by keyword, Terms are matched against the
+// product name and description and any of them is enough. Status and
+// Category narrow the results when set
+type AuctionSearch struct {
+	Terms    []string
+	Status   *AuctionStatus
+	Category string
+
+	Skip  int64
+	Limit int64
+}
+
+// ScoredAuction is a search result, a higher Score is a better match
+type ScoredAuction struct {
+	Auction
+	Score float64
+}
+
 type AuctionRepositoryInterface interface {
 	CreateAuction(
 		ctx context.Context,
 		auctionEntity *Auction) *internal_error.InternalError
 
+	// SearchAuctions returns the page of results, best match first, along
+	// with how many auctions matched
+	SearchAuctions(
+		ctx context.Context,
+		search AuctionSearch) ([]ScoredAuction, int64, *internal_error.InternalError)
+
+	// FindAuctions returns the cursor of the next page along with the
+	// auctions, empty once there are no more
 	FindAuctions(
 		ctx context.Context,
+		query AuctionQuery) ([]Auction, string, *internal_error.InternalError)
+
+	// FindAuctionFacets counts every auction the query matches, its sort and
+	// paging are ignored
+	FindAuctionFacets(
+		ctx context.Context,
+		query AuctionQuery) (*AuctionFacets, *internal_error.InternalError)
+
+	FindAuctionsBySellerId(
+		ctx context.Context,
+		sellerId string,
 		status AuctionStatus,
-		category, productName string) ([]Auction, *internal_error.InternalError)
+		skip, limit int64) ([]Auction, int64, *internal_error.InternalError)
+
+	FindActiveAuctionIds(
+		ctx context.Context,
+		category, sellerId string,
+		createdBefore time.Time) ([]string, *internal_error.InternalError)
+
+	UpdateAuctionStatus(
+		ctx context.Context,
+		id string,
+		from, to AuctionStatus) (bool, *internal_error.InternalError)
+
+	UpdateAuctionEndTime(
+		ctx context.Context,
+		id string,
+		endTime time.Time) *internal_error.InternalError
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
+
+	FindAuctionWinner(
+		ctx context.Context, auctionId string) (*AuctionWinner, *internal_error.InternalError)
+
+	CancelAuction(
+		ctx context.Context,
+		id, reason string) *internal_error.InternalError
+
+	PauseAuction(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	ResumeAuction(
+		ctx context.Context, id string) (time.Time, *internal_error.InternalError)
+
+	ReopenAuction(
+		ctx context.Context,
+		id string,
+		endTime time.Time) *internal_error.InternalError
+
+	BuyNow(
+		ctx context.Context,
+		id, userId string) *internal_error.InternalError
+
+	TakeDutchAuction(
+		ctx context.Context,
+		id, userId string,
+		amount float64) (float64, *internal_error.InternalError)
 }
@@ -3,17 +3,39 @@ package bid_entity
 import (
 	"auction_go/internal/internal_error"
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Why the database turned a bid down
+var (
+	ErrAuctionClosed = errors.New("auction is not taking bids")
+	ErrBidTooLow     = errors.New("bid doesn't beat the current price")
+)
+
+// ErrDuplicateBid turns down a retry of a bid already stored
+var ErrDuplicateBid = errors.New("bid with this idempotency key was already placed")
+
+// ErrSellerBid turns down bids by the seller on their own auction, which
+// would only push the price up for the other bidders
+var ErrSellerBid = errors.New("sellers can't bid on their own auction")
+
 type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
 	Amount    float64
 	Timestamp time.Time
+
+	// Sequence orders the bids accepted on an auction, it is assigned when
+	// the bid is stored and breaks ties between bids of the same amount
+	Sequence int64
+
+	// IdempotencyKey is picked by the client, a user's bids with the same key
+	// are retries of one bid and only the first one is stored
+	IdempotencyKey string
 }
 
 func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.InternalError) {
@@ -44,14 +66,127 @@ func (b *Bid) Validate() *internal_error.InternalError {
 	return nil
 }
 
+// ProxyBid bids on the user's behalf, raising their bid by the increment
+// whenever they are outbid, up to MaxAmount. Of two proxies with the same
+// maximum the earliest one wins
+type ProxyBid struct {
+	Id        string
+	UserId    string
+	AuctionId string
+	MaxAmount float64
+	Timestamp time.Time
+}
+
+func CreateProxyBid(userId, auctionId string, maxAmount float64) (*ProxyBid, *internal_error.InternalError) {
+	proxyBid := &ProxyBid{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		AuctionId: auctionId,
+		MaxAmount: maxAmount,
+		Timestamp: time.Now(),
+	}
+
+	if err := proxyBid.Validate(); err != nil {
+		return nil, err
+	}
+
+	return proxyBid, nil
+}
+
+func (p *ProxyBid) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(p.UserId); err != nil {
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	} else if err := uuid.Validate(p.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	} else if p.MaxAmount <= 0 {
+		return internal_error.NewBadRequestError("MaxAmount is not a valid value")
+	}
+
+	return nil
+}
+
+// CurrentPrice is the leading bid on an auction and how many bids it took to
+// get there, zero until the first bid
+type CurrentPrice struct {
+	AuctionId     string
+	Amount        float64
+	LeadingUserId string
+	BidCount      int64
+}
+
+// ActiveBid is where a user stands on an auction they bid on that is still
+// running: their highest bid against the leading one
+type ActiveBid struct {
+	AuctionId     string
+	HighestAmount float64
+	LeadingAmount float64
+	LeadingUserId string
+	Winning       bool
+}
+
+// OutbidEvent tells the bidder who was leading an auction that a higher bid
+// took the lead
+type OutbidEvent struct {
+	AuctionId        string
+	PreviousBidderId string
+	NewAmount        float64
+}
+
+// OutbidNotifier delivers outbid events, it is called right after the new
+// bid is stored so slow deliveries should be handed off
+type OutbidNotifier interface {
+	NotifyOutbid(ctx context.Context, event OutbidEvent)
+}
+
+// BidSort orders a bid history, both orders put the highest or latest bid
+// first
+type BidSort string
+
+const (
+	SortByAmount BidSort = "amount"
+	SortByTime   BidSort = "time"
+)
+
 type BidEntityRepository interface {
 	CreateBid(
 		ctx context.Context,
 		bidEntities []Bid) *internal_error.InternalError
 
+	IngestBid(
+		ctx context.Context,
+		bidEntity Bid) *internal_error.InternalError
+
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	CreateProxyBid(
+		ctx context.Context, proxyBid ProxyBid) *internal_error.InternalError
+
+	FindMinimumBid(
+		ctx context.Context, auctionId string) (float64, *internal_error.InternalError)
+
+	FindBidByIdempotencyKey(
+		ctx context.Context, userId, idempotencyKey string) (*Bid, *internal_error.InternalError)
+
+	FindActiveBidsByUser(
+		ctx context.Context, userId string) ([]ActiveBid, *internal_error.InternalError)
+
+	GetCurrentPrice(
+		ctx context.Context, auctionId string) (*CurrentPrice, *internal_error.InternalError)
+
+	FindBidsByAuctionIdPaginated(
+		ctx context.Context,
+		auctionId, cursor string,
+		limit int64,
+		sort BidSort) ([]Bid, string, *internal_error.InternalError)
+
+	// FindLatestBidsByAuctionIds maps each auction with bids to its latest
+	// ones, up to limit
+	FindLatestBidsByAuctionIds(
+		ctx context.Context,
+		auctionIds []string,
+		limit int64) (map[string][]Bid, *internal_error.InternalError)
 }
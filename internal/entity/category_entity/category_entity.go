@@ -0,0 +1,82 @@
+package category_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxNameLength bounds a category name
+const MaxNameLength = 50
+
+// Category is a node of the taxonomy auctions are filed under. Key is the
+// name folded to lower case with its spaces collapsed, so "Electronics" and
+// "electronics" are the same category. Keys are unique across the whole
+// taxonomy, top-level categories have no ParentId
+type Category struct {
+	Id        string
+	Name      string
+	Key       string
+	ParentId  string
+	Timestamp time.Time
+}
+
+func CreateCategory(name, parentId string) (*Category, *internal_error.InternalError) {
+	name = strings.Join(strings.Fields(name), " ")
+	category := &Category{
+		Id:        uuid.New().String(),
+		Name:      name,
+		Key:       CategoryKey(name),
+		ParentId:  parentId,
+		Timestamp: time.Now(),
+	}
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (c *Category) Validate() *internal_error.InternalError {
+	if len(c.Name) < 2 || len(c.Name) > MaxNameLength {
+		return internal_error.NewBadRequestError("A category name needs between 2 and 50 characters")
+	}
+
+	if c.ParentId == c.Id {
+		return internal_error.NewBadRequestError("A category can't be its own parent")
+	}
+
+	return nil
+}
+
+// CategoryKey is how category names are compared
+func CategoryKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// CategoryCount is how many active auctions are filed under a category,
+// keyed by the category's Key
+type CategoryCount map[string]int64
+
+type CategoryRepositoryInterface interface {
+	// CreateCategory turns down a name another category already has
+	CreateCategory(
+		ctx context.Context, category *Category) *internal_error.InternalError
+
+	FindCategories(
+		ctx context.Context) ([]Category, *internal_error.InternalError)
+
+	FindCategoryById(
+		ctx context.Context, id string) (*Category, *internal_error.InternalError)
+
+	// FindCategoryByName matches the name by its key
+	FindCategoryByName(
+		ctx context.Context, name string) (*Category, *internal_error.InternalError)
+
+	CountActiveAuctions(
+		ctx context.Context) (CategoryCount, *internal_error.InternalError)
+}
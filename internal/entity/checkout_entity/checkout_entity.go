@@ -0,0 +1,283 @@
+package checkout_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CheckoutStatus int
+
+const (
+	AwaitingAddress CheckoutStatus = iota
+	AwaitingShipping
+	AwaitingPayment
+	Paid
+	Shipped
+	Delivered
+	Returned
+)
+
+// Carrier statuses accepted from tracking updates
+const (
+	CarrierInTransit      = "in_transit"
+	CarrierOutForDelivery = "out_for_delivery"
+	CarrierException      = "exception"
+	CarrierDelivered      = "delivered"
+)
+
+type Address struct {
+	Street     string
+	Number     string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+type Shipment struct {
+	Carrier        string
+	TrackingNumber string
+	CarrierStatus  string
+	UpdatedAt      time.Time
+}
+
+// Payment methods accepted at checkout
+const (
+	PaymentMethodPix = "pix"
+)
+
+// Payment is the charge the buyer was asked to pay, PIX charges carry the
+// transaction id the provider echoes back on confirmation
+type Payment struct {
+	Method     string
+	TxId       string
+	PixPayload string
+	EndToEndId string
+	PaidAt     time.Time
+}
+
+// Refund is what the buyer is owed once the seller accepts their return, it
+// is paid back through the method they paid with and settled when the
+// provider confirms it
+type Refund struct {
+	ReturnRequestId string
+	Amount          float64
+	RequestedAt     time.Time
+	RefundedAt      time.Time
+}
+
+type ShippingOption struct {
+	Code  string
+	Price float64
+}
+
+type Checkout struct {
+	Id             string
+	AuctionId      string
+	BuyerUserId    string
+	ItemAmount     float64
+	Address        *Address
+	ShippingOption string
+	ShippingAmount float64
+	TotalAmount    float64
+	Payment        *Payment
+	Shipment       *Shipment
+	Refund         *Refund
+	Status         CheckoutStatus
+	DeliveredAt    time.Time
+	Timestamp      time.Time
+
+	// Version is bumped on every write, an update only applies to the
+	// version it was computed from
+	Version int64
+}
+
+func CreateCheckout(
+	auctionId, buyerUserId string, itemAmount float64) (*Checkout, *internal_error.InternalError) {
+	checkout := &Checkout{
+		Id:          uuid.New().String(),
+		AuctionId:   auctionId,
+		BuyerUserId: buyerUserId,
+		ItemAmount:  itemAmount,
+		TotalAmount: itemAmount,
+		Status:      AwaitingAddress,
+		Timestamp:   time.Now(),
+	}
+
+	if err := checkout.Validate(); err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+func (c *Checkout) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(c.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	} else if err := uuid.Validate(c.BuyerUserId); err != nil {
+		return internal_error.NewBadRequestError("BuyerUserId is not a valid id")
+	} else if c.ItemAmount <= 0 {
+		return internal_error.NewBadRequestError("ItemAmount is not a valid value")
+	}
+
+	return nil
+}
+
+// ConfirmAddress may be repeated until the checkout is paid, changing the
+// address keeps an already selected shipping option
+func (c *Checkout) ConfirmAddress(address Address) *internal_error.InternalError {
+	if c.Status >= Paid {
+		return internal_error.NewBadRequestError("Checkout is already paid")
+	}
+
+	if address.Street == "" || address.City == "" || address.PostalCode == "" || address.Country == "" {
+		return internal_error.NewBadRequestError("Address is missing required fields")
+	}
+
+	c.Address = &address
+	if c.Status == AwaitingAddress {
+		c.Status = AwaitingShipping
+	}
+
+	return nil
+}
+
+func (c *Checkout) SelectShipping(option ShippingOption) *internal_error.InternalError {
+	if c.Status == AwaitingAddress {
+		return internal_error.NewBadRequestError("Address must be confirmed before selecting shipping")
+	} else if c.Status >= Paid {
+		return internal_error.NewBadRequestError("Checkout is already paid")
+	}
+
+	c.ShippingOption = option.Code
+	c.ShippingAmount = option.Price
+	c.TotalAmount = c.ItemAmount + c.ShippingAmount
+	c.Payment = nil
+	c.Status = AwaitingPayment
+
+	return nil
+}
+
+// SelectPayment replaces any charge issued before, so changing the shipping
+// option or retrying payment always gets a charge for the current total
+func (c *Checkout) SelectPayment(payment Payment) *internal_error.InternalError {
+	if c.Status != AwaitingPayment {
+		return internal_error.NewBadRequestError("Checkout is not awaiting payment")
+	}
+
+	c.Payment = &payment
+
+	return nil
+}
+
+func (c *Checkout) MarkPaid() *internal_error.InternalError {
+	if c.Status != AwaitingPayment {
+		return internal_error.NewBadRequestError("Checkout is not awaiting payment")
+	}
+
+	c.Status = Paid
+	if c.Payment != nil {
+		c.Payment.PaidAt = time.Now()
+	}
+
+	return nil
+}
+
+func (c *Checkout) AttachShipment(carrier, trackingNumber string) *internal_error.InternalError {
+	if c.Status != Paid {
+		return internal_error.NewBadRequestError("Shipment can only be attached to a paid checkout")
+	}
+
+	if carrier == "" || trackingNumber == "" {
+		return internal_error.NewBadRequestError("Carrier and tracking number are required")
+	}
+
+	c.Shipment = &Shipment{
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		CarrierStatus:  CarrierInTransit,
+		UpdatedAt:      time.Now(),
+	}
+	c.Status = Shipped
+
+	return nil
+}
+
+// ApplyCarrierStatus records a tracking update, a delivery moves the
+// checkout itself to Delivered
+func (c *Checkout) ApplyCarrierStatus(carrierStatus string) *internal_error.InternalError {
+	if c.Status != Shipped || c.Shipment == nil {
+		return internal_error.NewBadRequestError("Checkout is not in transit")
+	}
+
+	switch carrierStatus {
+	case CarrierInTransit, CarrierOutForDelivery, CarrierException:
+	case CarrierDelivered:
+		c.Status = Delivered
+	default:
+		return internal_error.NewBadRequestError("Carrier status is not recognized")
+	}
+
+	c.Shipment.CarrierStatus = carrierStatus
+	c.Shipment.UpdatedAt = time.Now()
+	if c.Status == Delivered {
+		c.DeliveredAt = c.Shipment.UpdatedAt
+	}
+
+	return nil
+}
+
+// MarkReturned is applied once the seller accepts a return request, the
+// buyer is owed the whole total back
+func (c *Checkout) MarkReturned(returnRequestId string) *internal_error.InternalError {
+	if c.Status != Delivered {
+		return internal_error.NewBadRequestError("Only a delivered checkout can be returned")
+	}
+
+	c.Status = Returned
+	c.Refund = &Refund{
+		ReturnRequestId: returnRequestId,
+		Amount:          c.TotalAmount,
+		RequestedAt:     time.Now(),
+	}
+
+	return nil
+}
+
+// MarkRefunded settles the refund of a returned checkout, settling it again
+// is fine
+func (c *Checkout) MarkRefunded() *internal_error.InternalError {
+	if c.Status != Returned || c.Refund == nil {
+		return internal_error.NewBadRequestError("Checkout is not awaiting a refund")
+	}
+
+	if c.Refund.RefundedAt.IsZero() {
+		c.Refund.RefundedAt = time.Now()
+	}
+
+	return nil
+}
+
+type CheckoutRepositoryInterface interface {
+	CreateCheckout(
+		ctx context.Context,
+		checkoutEntity *Checkout) (*Checkout, *internal_error.InternalError)
+
+	FindCheckoutById(
+		ctx context.Context, id string) (*Checkout, *internal_error.InternalError)
+
+	FindCheckoutByAuctionId(
+		ctx context.Context, auctionId string) (*Checkout, *internal_error.InternalError)
+
+	FindCheckoutByTrackingNumber(
+		ctx context.Context, carrier, trackingNumber string) (*Checkout, *internal_error.InternalError)
+
+	FindCheckoutByPaymentTxId(
+		ctx context.Context, txId string) (*Checkout, *internal_error.InternalError)
+
+	UpdateCheckout(
+		ctx context.Context, checkoutEntity *Checkout) *internal_error.InternalError
+}
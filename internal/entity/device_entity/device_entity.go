@@ -0,0 +1,88 @@
+package device_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDevices bounds how many devices a user may get pushes on, registering
+// one more replaces the oldest
+const MaxDevices = 10
+
+// maxTokenLength is well above what FCM and APNs hand out
+const maxTokenLength = 4096
+
+// Platform is the push service that delivers to the device
+type Platform string
+
+const (
+	FCM  Platform = "fcm"
+	APNs Platform = "apns"
+)
+
+// Device is an app install the user gets push notifications on, Token is
+// what the platform's push service identifies it by
+type Device struct {
+	Id        string
+	UserId    string
+	Platform  Platform
+	Token     string
+	Timestamp time.Time
+}
+
+func CreateDevice(userId string, platform Platform, token string) (*Device, *internal_error.InternalError) {
+	device := &Device{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Platform:  platform,
+		Token:     token,
+		Timestamp: time.Now(),
+	}
+
+	if err := device.Validate(); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+func (d *Device) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(d.UserId); err != nil {
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	}
+
+	if d.Platform != FCM && d.Platform != APNs {
+		return internal_error.NewValidationError("Invalid device",
+			internal_error.Cause{Field: "platform", Message: "Must be fcm or apns"})
+	}
+
+	if d.Token == "" || len(d.Token) > maxTokenLength {
+		return internal_error.NewValidationError("Invalid device",
+			internal_error.Cause{Field: "token", Message: "Must be the token the push service issued"})
+	}
+
+	return nil
+}
+
+type DeviceRepositoryInterface interface {
+	// RegisterDevice moves the token to the user when another user had it,
+	// a device signed in to a new account only gets that account's pushes.
+	// It returns the device as stored, with its original id when the user
+	// already had it
+	RegisterDevice(
+		ctx context.Context, device *Device) (*Device, *internal_error.InternalError)
+
+	DeleteDevice(
+		ctx context.Context, userId, deviceId string) *internal_error.InternalError
+
+	// DeleteDeviceByToken forgets a token the push service no longer
+	// accepts
+	DeleteDeviceByToken(
+		ctx context.Context, platform Platform, token string) *internal_error.InternalError
+
+	FindDevicesByUserId(
+		ctx context.Context, userId string) ([]Device, *internal_error.InternalError)
+}
@@ -0,0 +1,83 @@
+package feedback_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bounds of a rating and of the comment left with it
+const (
+	MinRating        = 1
+	MaxRating        = 5
+	MaxCommentLength = 1000
+)
+
+// Feedback is the winner's rating of the seller of an auction they won, each
+// auction gets at most one
+type Feedback struct {
+	AuctionId string
+	SellerId  string
+	BuyerId   string
+	Rating    int
+	Comment   string
+	Timestamp time.Time
+}
+
+func CreateFeedback(
+	auctionId, sellerId, buyerId string,
+	rating int,
+	comment string) (*Feedback, *internal_error.InternalError) {
+	feedback := &Feedback{
+		AuctionId: auctionId,
+		SellerId:  sellerId,
+		BuyerId:   buyerId,
+		Rating:    rating,
+		Comment:   strings.TrimSpace(comment),
+		Timestamp: time.Now(),
+	}
+
+	if err := feedback.Validate(); err != nil {
+		return nil, err
+	}
+
+	return feedback, nil
+}
+
+func (f *Feedback) Validate() *internal_error.InternalError {
+	var causes []internal_error.Cause
+	if f.Rating < MinRating || f.Rating > MaxRating {
+		causes = append(causes, internal_error.Cause{
+			Field:   "rating",
+			Message: fmt.Sprintf("Must be between %d and %d", MinRating, MaxRating),
+		})
+	}
+	if len(f.Comment) > MaxCommentLength {
+		causes = append(causes, internal_error.Cause{
+			Field:   "comment",
+			Message: fmt.Sprintf("Must be at most %d characters", MaxCommentLength),
+		})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewValidationError("Invalid feedback", causes...)
+	}
+
+	return nil
+}
+
+type FeedbackRepositoryInterface interface {
+	// CreateFeedback stores the feedback and refreshes the seller's
+	// reputation, a second feedback on the same auction is a bad request
+	CreateFeedback(
+		ctx context.Context, feedback *Feedback) *internal_error.InternalError
+
+	// FindFeedbackBySellerId returns one page of the seller's feedback,
+	// newest first, along with the total
+	FindFeedbackBySellerId(
+		ctx context.Context,
+		sellerId string,
+		skip, limit int64) ([]Feedback, int64, *internal_error.InternalError)
+}
@@ -0,0 +1,146 @@
+package history_entity
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType tells what an auction event records
+type EventType string
+
+const (
+	AuctionCreated EventType = "auction_created"
+	StatusChanged  EventType = "status_changed"
+	EndTimeChanged EventType = "end_time_changed"
+	PriceDropped   EventType = "price_dropped"
+	BidPlaced      EventType = "bid_placed"
+)
+
+// AuctionSnapshot is the auction as the change an event records left it
+type AuctionSnapshot struct {
+	SellerId           string
+	ProductName        string
+	Category           string
+	Type               auction_entity.AuctionType
+	Status             auction_entity.AuctionStatus
+	StartTime          time.Time
+	EndTime            time.Time
+	ReservePrice       float64
+	BuyNowPrice        float64
+	DutchPrice         float64
+	WinnerUserId       string
+	WinningAmount      float64
+	CancellationReason string
+}
+
+type BidSnapshot struct {
+	BidId  string
+	UserId string
+	Amount float64
+}
+
+// AuctionEvent is an entry of an auction's event log. Bid events carry the
+// bid, every other event the auction as it was left
+type AuctionEvent struct {
+	Id        string
+	AuctionId string
+	Type      EventType
+	Timestamp time.Time
+	Auction   *AuctionSnapshot
+	Bid       *BidSnapshot
+}
+
+func CreateAuctionEvent(auctionId string, eventType EventType, auction AuctionSnapshot) AuctionEvent {
+	return AuctionEvent{
+		Id:        uuid.New().String(),
+		AuctionId: auctionId,
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Auction:   &auction,
+	}
+}
+
+func CreateBidEvent(bid bid_entity.Bid) AuctionEvent {
+	return AuctionEvent{
+		Id:        uuid.New().String(),
+		AuctionId: bid.AuctionId,
+		Type:      BidPlaced,
+		Timestamp: time.Now(),
+		Bid: &BidSnapshot{
+			BidId:  bid.Id,
+			UserId: bid.UserId,
+			Amount: bid.Amount,
+		},
+	}
+}
+
+// AuctionState is an auction as it stood at some point, along with its
+// bidding up to then
+type AuctionState struct {
+	AuctionId     string
+	At            time.Time
+	Auction       AuctionSnapshot
+	CurrentPrice  float64
+	LeadingUserId string
+	BidCount      int64
+	LastEventId   string
+}
+
+// AuctionReplay is an auction's event log, oldest event first
+type AuctionReplay struct {
+	AuctionId string
+	Events    []AuctionEvent
+}
+
+// StateAt replays the events up to at, it tells false when the auction
+// didn't exist yet
+func (r *AuctionReplay) StateAt(at time.Time) (*AuctionState, bool) {
+	state := &AuctionState{AuctionId: r.AuctionId, At: at}
+	created := false
+
+	for _, event := range r.Events {
+		if event.Timestamp.After(at) {
+			break
+		}
+
+		switch {
+		case event.Auction != nil:
+			state.Auction = *event.Auction
+			created = true
+		case event.Bid != nil:
+			// Only bids beating the price are stored, the ones logged in the
+			// same instant may come in any order
+			state.BidCount++
+			if event.Bid.Amount > state.CurrentPrice {
+				state.CurrentPrice = event.Bid.Amount
+				state.LeadingUserId = event.Bid.UserId
+			}
+		}
+		state.LastEventId = event.Id
+	}
+
+	return state, created
+}
+
+// EventsUntil are the events StateAt replays
+func (r *AuctionReplay) EventsUntil(at time.Time) []AuctionEvent {
+	for i, event := range r.Events {
+		if event.Timestamp.After(at) {
+			return r.Events[:i]
+		}
+	}
+
+	return r.Events
+}
+
+type HistoryRepositoryInterface interface {
+	AppendEvent(ctx context.Context, event AuctionEvent) error
+
+	ReplayAuction(
+		ctx context.Context, auctionId string) (*AuctionReplay, *internal_error.InternalError)
+}
@@ -0,0 +1,178 @@
+package notification_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channel is a way notifications reach users
+type Channel string
+
+const (
+	Email Channel = "email"
+	Push  Channel = "push"
+)
+
+// Channels lists every channel
+var Channels = []Channel{Email, Push}
+
+// EventType is a kind of notification
+type EventType string
+
+const (
+	Outbid           EventType = "outbid"
+	AuctionWon       EventType = "auction_won"
+	AuctionEnded     EventType = "auction_ended"
+	EndingSoon       EventType = "ending_soon"
+	SavedSearchMatch EventType = "saved_search_match"
+)
+
+// EventTypes lists every kind of notification
+var EventTypes = []EventType{Outbid, AuctionWon, AuctionEnded, EndingSoon, SavedSearchMatch}
+
+// Urgent tells whether notifications of the kind are worth nothing once
+// late, those are never held for a digest
+func (e EventType) Urgent() bool {
+	return e == Outbid || e == AuctionWon || e == EndingSoon
+}
+
+// DigestFrequency is how often non-urgent notifications are summed up,
+// DigestOff sends them right away
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestHourly DigestFrequency = "hourly"
+	DigestDaily  DigestFrequency = "daily"
+)
+
+// Period is zero when digests are off
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DigestHourly:
+		return time.Hour
+	case DigestDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Preferences are how a user wants to be notified. Users who never set
+// them get every notification on every channel right away
+type Preferences struct {
+	UserId        string
+	MutedChannels []Channel
+	MutedEvents   []EventType
+	Digest        DigestFrequency
+	UpdatedAt     time.Time
+}
+
+// DefaultPreferences are the preferences of users who never set theirs
+func DefaultPreferences(userId string) *Preferences {
+	return &Preferences{UserId: userId, Digest: DigestOff}
+}
+
+func (p *Preferences) Validate() *internal_error.InternalError {
+	var causes []internal_error.Cause
+	for _, channel := range p.MutedChannels {
+		if !slices.Contains(Channels, channel) {
+			causes = append(causes, internal_error.Cause{
+				Field:   "muted_channels",
+				Message: fmt.Sprintf("Unknown channel %q", channel),
+			})
+		}
+	}
+	for _, event := range p.MutedEvents {
+		if !slices.Contains(EventTypes, event) {
+			causes = append(causes, internal_error.Cause{
+				Field:   "muted_events",
+				Message: fmt.Sprintf("Unknown event %q", event),
+			})
+		}
+	}
+	if p.Digest != DigestOff && p.Digest.Period() == 0 {
+		causes = append(causes, internal_error.Cause{
+			Field:   "digest",
+			Message: fmt.Sprintf("Must be %s, %s or %s", DigestOff, DigestHourly, DigestDaily),
+		})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewValidationError("Invalid notification preferences", causes...)
+	}
+
+	return nil
+}
+
+// Allows tells whether the user wants the notification on the channel at
+// all, digested or not
+func (p *Preferences) Allows(channel Channel, event EventType) bool {
+	return !slices.Contains(p.MutedChannels, channel) && !slices.Contains(p.MutedEvents, event)
+}
+
+// Digests tells whether the notification is held for the user's next
+// digest rather than sent right away
+func (p *Preferences) Digests(event EventType) bool {
+	return p.Digest.Period() > 0 && !event.Urgent()
+}
+
+// DigestItem is a notification held for a digest. Items of a user held
+// within the same period share DueAt, the end of that period
+type DigestItem struct {
+	Id        string
+	UserId    string
+	Event     EventType
+	Subject   string
+	AuctionId string
+	Timestamp time.Time
+	DueAt     time.Time
+}
+
+// CreateDigestItem holds the notification until the end of the current
+// period of the frequency, which must not be DigestOff
+func CreateDigestItem(
+	userId string, event EventType, subject, auctionId string,
+	frequency DigestFrequency, now time.Time) *DigestItem {
+	period := frequency.Period()
+
+	return &DigestItem{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Event:     event,
+		Subject:   subject,
+		AuctionId: auctionId,
+		Timestamp: now,
+		DueAt:     now.Truncate(period).Add(period),
+	}
+}
+
+// Digest sums up the items of a user that came due, oldest first
+type Digest struct {
+	UserId string
+	Items  []DigestItem
+}
+
+// DigestSender delivers the digests that came due, it is called once per
+// user and digest
+type DigestSender interface {
+	SendDigest(ctx context.Context, digest Digest)
+}
+
+type NotificationRepositoryInterface interface {
+	// FindPreferences returns DefaultPreferences for users who never set
+	// theirs
+	FindPreferences(
+		ctx context.Context, userId string) (*Preferences, *internal_error.InternalError)
+
+	SavePreferences(
+		ctx context.Context, preferences *Preferences) *internal_error.InternalError
+
+	AddDigestItem(
+		ctx context.Context, item *DigestItem) *internal_error.InternalError
+}
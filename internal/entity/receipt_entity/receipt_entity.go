@@ -0,0 +1,59 @@
+package receipt_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentWindow is how long the winner is given to pay once the auction
+// closes, the receipt states the deadline
+const PaymentWindow = 72 * time.Hour
+
+// Receipt is what the winner of an auction owes for it, emailed when the
+// auction closes and kept for their records. Fees are charged on top of
+// the final price, the marketplace charges none yet and shipping is only
+// picked at checkout
+type Receipt struct {
+	Id              string
+	AuctionId       string
+	UserId          string
+	ProductName     string
+	FinalPrice      float64
+	Fees            float64
+	TotalAmount     float64
+	PaymentLink     string
+	PaymentDeadline time.Time
+	Timestamp       time.Time
+}
+
+func CreateReceipt(
+	auctionId, userId, productName string, finalPrice float64, paymentLink string, closedAt time.Time) *Receipt {
+	return &Receipt{
+		Id:              uuid.New().String(),
+		AuctionId:       auctionId,
+		UserId:          userId,
+		ProductName:     productName,
+		FinalPrice:      finalPrice,
+		TotalAmount:     finalPrice,
+		PaymentLink:     paymentLink,
+		PaymentDeadline: closedAt.Add(PaymentWindow),
+		Timestamp:       closedAt,
+	}
+}
+
+type ReceiptRepositoryInterface interface {
+	// CreateReceipt keeps the receipt issued first for an auction, it returns
+	// the receipt as stored
+	CreateReceipt(
+		ctx context.Context, receipt *Receipt) (*Receipt, *internal_error.InternalError)
+
+	FindReceiptsByUserId(
+		ctx context.Context, userId string) ([]Receipt, *internal_error.InternalError)
+
+	// FindReceiptById only finds receipts issued to the user
+	FindReceiptById(
+		ctx context.Context, userId, id string) (*Receipt, *internal_error.InternalError)
+}
@@ -0,0 +1,80 @@
+package recommendation_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+)
+
+// maxKeywords bounds the words of a product name similar auctions are
+// matched on
+const maxKeywords = 10
+
+type CategoryAffinity struct {
+	UserId     string
+	Category   string
+	Score      float64
+	AuctionIds []string
+}
+
+type RecommendedAuction struct {
+	AuctionId   string
+	ProductName string
+	Category    string
+	Score       float64
+}
+
+type Recommendation struct {
+	UserId      string
+	Auctions    []RecommendedAuction
+	GeneratedAt time.Time
+}
+
+// SimilarAuctionQuery looks for active auctions in the category of the
+// auction, other than itself, sharing its keywords or priced close to
+// ReferencePrice. A zero ReferencePrice leaves the price out of the score
+type SimilarAuctionQuery struct {
+	AuctionId      string
+	Category       string
+	Keywords       []string
+	ReferencePrice float64
+	Limit          int64
+}
+
+// Keywords are the distinct lower case words of a product name, words
+// shorter than three letters say little about the product and are dropped
+func Keywords(productName string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(productName)) {
+		if len([]rune(word)) < 3 || seen[word] {
+			continue
+		}
+
+		seen[word] = true
+		keywords = append(keywords, word)
+		if len(keywords) == maxKeywords {
+			break
+		}
+	}
+
+	return keywords
+}
+
+type RecommendationRepositoryInterface interface {
+	// FindSimilarAuctions scores candidates between 0 and 1, best first
+	FindSimilarAuctions(
+		ctx context.Context,
+		query SimilarAuctionQuery) ([]RecommendedAuction, *internal_error.InternalError)
+
+	FindCategoryAffinities(
+		ctx context.Context) ([]CategoryAffinity, *internal_error.InternalError)
+
+	UpsertRecommendation(
+		ctx context.Context,
+		recommendation *Recommendation) *internal_error.InternalError
+
+	FindRecommendationByUserId(
+		ctx context.Context, userId string) (*Recommendation, *internal_error.InternalError)
+}
@@ -0,0 +1,146 @@
+package return_entity
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReturnStatus int
+
+const (
+	Requested ReturnStatus = iota
+	Accepted
+	Declined
+)
+
+// Reasons a buyer may give when requesting a return
+const (
+	ReasonNotAsDescribed = "not_as_described"
+	ReasonDamaged        = "damaged"
+	ReasonWrongItem      = "wrong_item"
+	ReasonOther          = "other"
+)
+
+const maxPhotosPerRequest = 5
+
+type ReturnRequest struct {
+	Id          string
+	CheckoutId  string
+	BuyerUserId string
+	Reason      string
+	Description string
+	PhotoUrls   []string
+	Status      ReturnStatus
+	SellerNote  string
+	Timestamp   time.Time
+	ResolvedAt  time.Time
+}
+
+func CreateReturnRequest(
+	checkoutId, buyerUserId, reason, description string,
+	photoUrls []string) (*ReturnRequest, *internal_error.InternalError) {
+	returnRequest := &ReturnRequest{
+		Id:          uuid.New().String(),
+		CheckoutId:  checkoutId,
+		BuyerUserId: buyerUserId,
+		Reason:      reason,
+		Description: description,
+		PhotoUrls:   photoUrls,
+		Status:      Requested,
+		Timestamp:   time.Now(),
+	}
+
+	if err := returnRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return returnRequest, nil
+}
+
+func (r *ReturnRequest) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(r.CheckoutId); err != nil {
+		return internal_error.NewBadRequestError("CheckoutId is not a valid id")
+	} else if err := uuid.Validate(r.BuyerUserId); err != nil {
+		return internal_error.NewBadRequestError("BuyerUserId is not a valid id")
+	}
+
+	switch r.Reason {
+	case ReasonNotAsDescribed, ReasonDamaged, ReasonWrongItem, ReasonOther:
+	default:
+		return internal_error.NewBadRequestError("Reason is not a valid value")
+	}
+
+	if r.Reason == ReasonOther && r.Description == "" {
+		return internal_error.NewBadRequestError("Description is required when the reason is other")
+	} else if len(r.Description) > 1000 {
+		return internal_error.NewBadRequestError("Description must have at most 1000 characters")
+	} else if len(r.PhotoUrls) > maxPhotosPerRequest {
+		return internal_error.NewBadRequestError("A return request accepts at most 5 photos")
+	}
+
+	for _, photoUrl := range r.PhotoUrls {
+		if parsed, err := url.ParseRequestURI(photoUrl); err != nil || parsed.Host == "" ||
+			(parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return internal_error.NewBadRequestError("PhotoUrls must be absolute http(s) urls")
+		}
+	}
+
+	return nil
+}
+
+func (r *ReturnRequest) Accept(sellerNote string) *internal_error.InternalError {
+	if r.Status != Requested {
+		return internal_error.NewBadRequestError("Return request was already resolved")
+	}
+
+	r.Status = Accepted
+	r.SellerNote = sellerNote
+	r.ResolvedAt = time.Now()
+
+	return nil
+}
+
+// Decline requires a note, the buyer is owed an explanation
+func (r *ReturnRequest) Decline(sellerNote string) *internal_error.InternalError {
+	if r.Status != Requested {
+		return internal_error.NewBadRequestError("Return request was already resolved")
+	} else if sellerNote == "" {
+		return internal_error.NewBadRequestError("A note is required to decline a return request")
+	}
+
+	r.Status = Declined
+	r.SellerNote = sellerNote
+	r.ResolvedAt = time.Now()
+
+	return nil
+}
+
+type ReturnRepositoryInterface interface {
+	CreateReturnRequest(
+		ctx context.Context,
+		returnRequestEntity *ReturnRequest) (*ReturnRequest, *internal_error.InternalError)
+
+	FindReturnRequestById(
+		ctx context.Context, id string) (*ReturnRequest, *internal_error.InternalError)
+
+	FindReturnRequestByCheckoutId(
+		ctx context.Context, checkoutId string) (*ReturnRequest, *internal_error.InternalError)
+
+	UpdateReturnRequest(
+		ctx context.Context,
+		returnRequestEntity *ReturnRequest,
+		expectedStatus ReturnStatus) *internal_error.InternalError
+
+	// AcceptReturnRequest stores the accepted request and its checkout, moved
+	// to Returned, together or not at all
+	AcceptReturnRequest(
+		ctx context.Context,
+		returnRequestEntity *ReturnRequest,
+		expectedStatus ReturnStatus,
+		checkoutEntity *checkout_entity.Checkout) *internal_error.InternalError
+}
@@ -0,0 +1,131 @@
+package saved_search_entity
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxSavedSearches bounds how many searches a user may save
+const MaxSavedSearches = 20
+
+// SavedSearch is a search the user wants to hear about, they are notified
+// whenever a new auction matches it. Queries holds the query together with
+// its synonym variants as they were when the search was saved
+type SavedSearch struct {
+	Id        string
+	UserId    string
+	Name      string
+	Query     string
+	Queries   []string
+	Category  string
+	Condition auction_entity.ProductCondition
+	Timestamp time.Time
+}
+
+func CreateSavedSearch(
+	userId, name, query string,
+	queries []string,
+	category string,
+	condition auction_entity.ProductCondition) (*SavedSearch, *internal_error.InternalError) {
+	savedSearch := &SavedSearch{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Name:      strings.TrimSpace(name),
+		Query:     strings.TrimSpace(query),
+		Queries:   queries,
+		Category:  category,
+		Condition: condition,
+		Timestamp: time.Now(),
+	}
+
+	if err := savedSearch.Validate(); err != nil {
+		return nil, err
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SavedSearch) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(s.UserId); err != nil {
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	} else if s.Name == "" {
+		return internal_error.NewBadRequestError("A saved search needs a name")
+	} else if len(s.Queries) == 0 && s.Category == "" && s.Condition == 0 {
+		return internal_error.NewBadRequestError("A saved search needs a query, a category or a condition")
+	}
+
+	return nil
+}
+
+// Matches reports whether the auction passes the search's filters and, when
+// it has a query, whether the product name contains every word of any of
+// the query's variants, like listing auctions by product name does. Sellers
+// aren't told about their own auctions
+func (s *SavedSearch) Matches(auction auction_entity.Auction) bool {
+	if auction.SellerId == s.UserId {
+		return false
+	}
+	if s.Category != "" && !strings.EqualFold(s.Category, auction.Category) {
+		return false
+	}
+	if s.Condition != 0 && s.Condition != auction.Condition {
+		return false
+	}
+	if len(s.Queries) == 0 {
+		return true
+	}
+
+	productName := strings.ToLower(auction.ProductName)
+	for _, query := range s.Queries {
+		if containsAllWords(productName, query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAllWords(productName, query string) bool {
+	words := strings.Fields(strings.ToLower(query))
+	for _, word := range words {
+		if !strings.Contains(productName, word) {
+			return false
+		}
+	}
+
+	return len(words) > 0
+}
+
+// MatchEvent tells a user a new auction matches one of their saved searches
+type MatchEvent struct {
+	UserId        string
+	SavedSearchId string
+	Name          string
+	AuctionId     string
+	ProductName   string
+}
+
+// MatchNotifier delivers saved search matches, it is called once per
+// matching search and new auction
+type MatchNotifier interface {
+	NotifySavedSearchMatch(ctx context.Context, event MatchEvent)
+}
+
+type SavedSearchRepositoryInterface interface {
+	CreateSavedSearch(
+		ctx context.Context, savedSearch *SavedSearch) *internal_error.InternalError
+
+	DeleteSavedSearch(
+		ctx context.Context, userId, savedSearchId string) *internal_error.InternalError
+
+	FindSavedSearchesByUserId(
+		ctx context.Context, userId string) ([]SavedSearch, *internal_error.InternalError)
+
+	CountSavedSearchesByUserId(
+		ctx context.Context, userId string) (int64, *internal_error.InternalError)
+}
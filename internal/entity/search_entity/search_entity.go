@@ -0,0 +1,119 @@
+package search_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upper bound on the phrases a single query expands into, so a crowded
+// dictionary can't turn one search into an enormous regex
+const maxQueryVariants = 20
+
+type SynonymGroup struct {
+	Id        string
+	Terms     []string
+	Timestamp time.Time
+}
+
+func CreateSynonymGroup(terms []string) (*SynonymGroup, *internal_error.InternalError) {
+	synonymGroup := &SynonymGroup{
+		Id:        uuid.New().String(),
+		Timestamp: time.Now(),
+	}
+
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		term = normalizePhrase(term, nil)
+		if term == "" || seen[term] {
+			continue
+		}
+
+		seen[term] = true
+		synonymGroup.Terms = append(synonymGroup.Terms, term)
+	}
+
+	if err := synonymGroup.Validate(); err != nil {
+		return nil, err
+	}
+
+	return synonymGroup, nil
+}
+
+func (sg *SynonymGroup) Validate() *internal_error.InternalError {
+	if len(sg.Terms) < 2 {
+		return internal_error.NewBadRequestError("A synonym group needs at least two distinct terms")
+	}
+
+	return nil
+}
+
+// ExpandQuery removes stop words from the query and returns it together with
+// every variant produced by swapping a matched term for its synonyms
+func ExpandQuery(query string, synonymGroups []SynonymGroup, stopWords map[string]bool) []string {
+	phrase := normalizePhrase(query, stopWords)
+	if phrase == "" {
+		return nil
+	}
+
+	variants := []string{phrase}
+	seen := map[string]bool{phrase: true}
+
+	for _, synonymGroup := range synonymGroups {
+		for _, term := range synonymGroup.Terms {
+			for _, variant := range variants {
+				if !containsPhrase(variant, term) {
+					continue
+				}
+
+				for _, synonym := range synonymGroup.Terms {
+					expanded := replacePhrase(variant, term, synonym)
+					if seen[expanded] || len(variants) >= maxQueryVariants {
+						continue
+					}
+
+					seen[expanded] = true
+					variants = append(variants, expanded)
+				}
+			}
+		}
+	}
+
+	return variants
+}
+
+func normalizePhrase(value string, stopWords map[string]bool) string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(value)) {
+		if stopWords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+func containsPhrase(phrase, term string) bool {
+	return strings.Contains(" "+phrase+" ", " "+term+" ")
+}
+
+func replacePhrase(phrase, term, replacement string) string {
+	replaced := strings.ReplaceAll(" "+phrase+" ", " "+term+" ", " "+replacement+" ")
+	return strings.TrimSpace(replaced)
+}
+
+type SearchRepositoryInterface interface {
+	CreateSynonymGroup(
+		ctx context.Context,
+		synonymGroup *SynonymGroup) *internal_error.InternalError
+
+	FindSynonymGroups(
+		ctx context.Context) ([]SynonymGroup, *internal_error.InternalError)
+
+	DeleteSynonymGroup(
+		ctx context.Context, id string) *internal_error.InternalError
+}
@@ -0,0 +1,53 @@
+package stats_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+// MaxPricePoints bounds the price history of an auction, the buckets are
+// widened to fit its whole run in
+const MaxPricePoints = 48
+
+// PricePoint is the highest bid placed within the bucket starting at
+// Timestamp, along with how many bids came in during it. Buckets nobody bid
+// in are left out
+type PricePoint struct {
+	Timestamp time.Time
+	Amount    float64
+	BidCount  int64
+}
+
+type AuctionStats struct {
+	AuctionId     string
+	BidCount      int64
+	UniqueBidders int64
+	WatchCount    int64
+	PriceHistory  []PricePoint
+}
+
+// BucketFor is the width of the price history buckets for an auction
+// running that long, a whole number of minutes
+func BucketFor(run time.Duration) time.Duration {
+	bucket := (run/MaxPricePoints + time.Minute - 1).Truncate(time.Minute)
+	if bucket < time.Minute {
+		return time.Minute
+	}
+
+	return bucket
+}
+
+type StatsRepositoryInterface interface {
+	// FindAuctionStats may return figures up to a few seconds old
+	FindAuctionStats(
+		ctx context.Context,
+		auctionId string,
+		bucket time.Duration) (*AuctionStats, *internal_error.InternalError)
+
+	// FindStatsByAuctionIds counts the bids, bidders and watchers of many
+	// auctions at once, without their price history. Auctions nobody bid on
+	// or watched are left out
+	FindStatsByAuctionIds(
+		ctx context.Context, auctionIds []string) (map[string]AuctionStats, *internal_error.InternalError)
+}
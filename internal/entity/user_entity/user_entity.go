@@ -3,14 +3,320 @@ package user_entity
 import (
 	"auction_go/internal/internal_error"
 	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bounds of the length of a user's name
+const (
+	MinNameLength = 2
+	MaxNameLength = 100
+)
+
+// Bounds of the length of a password, bcrypt ignores anything past 72 bytes
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
 )
 
 type User struct {
-	Id   string
-	Name string
+	Id           string
+	Name         string
+	Email        string
+	PasswordHash string
+	Slug         string
+	Verification VerificationLevel
+	Block        *Block
+	Reputation   Reputation
+
+	// EmailVerifiedAt is when the user confirmed their current email, zero
+	// until they do
+	EmailVerifiedAt time.Time
+
+	// DeletedAt is set once the user deleted their account
+	DeletedAt time.Time
+
+	// EmailOptOuts are the notification emails the user doesn't want
+	EmailOptOuts []EmailNotification
+}
+
+// EmailNotification is a kind of notification email users may opt out of
+type EmailNotification string
+
+const (
+	OutbidEmail       EmailNotification = "outbid"
+	AuctionWonEmail   EmailNotification = "auction_won"
+	AuctionEndedEmail EmailNotification = "auction_ended"
+	SavedSearchEmail  EmailNotification = "saved_search_match"
+	EndingSoonEmail   EmailNotification = "ending_soon"
+)
+
+// EmailNotifications lists every kind of notification email
+var EmailNotifications = []EmailNotification{
+	OutbidEmail, AuctionWonEmail, AuctionEndedEmail, SavedSearchEmail, EndingSoonEmail}
+
+// DeletedUserName stands in for the name of deleted accounts
+const DeletedUserName = "Deleted user"
+
+// Reputation sums up the feedback buyers left the user as a seller, Score is
+// the average rating
+type Reputation struct {
+	Score float64
+	Count int64
+}
+
+// Block keeps a user from bidding and selling until Until, for good when
+// Until is zero
+type Block struct {
+	Reason string
+	Until  time.Time
+}
+
+// Active tells whether the block still holds, a nil block never does
+func (b *Block) Active(now time.Time) bool {
+	return b != nil && (b.Until.IsZero() || now.Before(b.Until))
+}
+
+// Check turns the user's action down while the block holds
+func (b *Block) Check(now time.Time) *internal_error.InternalError {
+	if !b.Active(now) {
+		return nil
+	}
+
+	if b.Until.IsZero() {
+		return internal_error.NewForbiddenError(fmt.Sprintf("User is blocked: %s", b.Reason))
+	}
+	return internal_error.NewForbiddenError(fmt.Sprintf("User is suspended until %s: %s",
+		b.Until.UTC().Format(time.RFC3339), b.Reason))
+}
+
+// CreateUser builds an unverified user, emails are stored lowercase so they
+// are unique regardless of case
+func CreateUser(name, email, password string) (*User, *internal_error.InternalError) {
+	user := &User{
+		Id:           uuid.New().String(),
+		Name:         strings.TrimSpace(name),
+		Email:        strings.ToLower(strings.TrimSpace(email)),
+		Verification: Unverified,
+	}
+
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	if err := user.SetPassword(password); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetPassword stores the password's bcrypt hash, the password itself is
+// never kept
+func (u *User) SetPassword(password string) *internal_error.InternalError {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return internal_error.NewValidationError("Invalid user", internal_error.Cause{
+			Field:   "password",
+			Message: fmt.Sprintf("Must be between %d and %d characters", MinPasswordLength, MaxPasswordLength),
+		})
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return internal_error.NewInternalServerError("Error trying to hash password")
+	}
+
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword tells whether the password matches, users stored before
+// passwords existed can't log in
+func (u *User) CheckPassword(password string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+func (u *User) Validate() *internal_error.InternalError {
+	var causes []internal_error.Cause
+	if len(u.Name) < MinNameLength || len(u.Name) > MaxNameLength {
+		causes = append(causes, internal_error.Cause{
+			Field:   "name",
+			Message: fmt.Sprintf("Must be between %d and %d characters", MinNameLength, MaxNameLength),
+		})
+	}
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		causes = append(causes, internal_error.Cause{Field: "email", Message: "Invalid email address"})
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewValidationError("Invalid user", causes...)
+	}
+
+	return nil
+}
+
+// Update changes the user's profile, empty fields are kept. A new email has
+// to be verified again, so it takes back an email verification
+func (u *User) Update(name, email, password string) *internal_error.InternalError {
+	updated := *u
+	if name != "" {
+		updated.Name = strings.TrimSpace(name)
+	}
+	if email != "" {
+		updated.Email = strings.ToLower(strings.TrimSpace(email))
+	}
+
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	if password != "" {
+		if err := updated.SetPassword(password); err != nil {
+			return err
+		}
+	}
+
+	if updated.Email != u.Email {
+		updated.EmailVerifiedAt = time.Time{}
+		if updated.Verification == EmailVerified {
+			updated.Verification = Unverified
+		}
+	}
+
+	*u = updated
+	return nil
+}
+
+// Anonymize replaces the user's personal fields with tombstone values. The
+// user is kept so their bids and auctions still point to someone, and is
+// blocked for good so they can't bid or sell anymore. The tombstone
+// email is random, nobody can register it ahead of time
+func (u *User) Anonymize(now time.Time) {
+	u.Name = DeletedUserName
+	u.Email = fmt.Sprintf("deleted-%s@deleted.invalid", uuid.New().String())
+	u.PasswordHash = ""
+	u.Slug = ""
+	u.Verification = Unverified
+	u.EmailVerifiedAt = time.Time{}
+	u.EmailOptOuts = nil
+	u.Block = &Block{Reason: "Account deleted"}
+	u.DeletedAt = now
+}
+
+func (u *User) Deleted() bool {
+	return !u.DeletedAt.IsZero()
+}
+
+// SetEmailOptOuts replaces the notification emails the user opted out of
+func (u *User) SetEmailOptOuts(optOuts []EmailNotification) *internal_error.InternalError {
+	kept := make([]EmailNotification, 0, len(optOuts))
+	for _, optOut := range optOuts {
+		if !slices.Contains(EmailNotifications, optOut) {
+			return internal_error.NewValidationError("Invalid user", internal_error.Cause{
+				Field:   "email_opt_outs",
+				Message: fmt.Sprintf("Unknown notification %s", optOut),
+			})
+		}
+		if !slices.Contains(kept, optOut) {
+			kept = append(kept, optOut)
+		}
+	}
+
+	u.EmailOptOuts = kept
+	return nil
+}
+
+// WantsEmail tells whether the user is to get the notification email,
+// deleted accounts get none
+func (u *User) WantsEmail(notification EmailNotification) bool {
+	return !u.Deleted() && !slices.Contains(u.EmailOptOuts, notification)
+}
+
+// VerificationLevel is how far the user proved who they are, higher levels
+// may place larger bids
+type VerificationLevel string
+
+const (
+	Unverified    VerificationLevel = "unverified"
+	EmailVerified VerificationLevel = "email_verified"
+	KYCVerified   VerificationLevel = "kyc_verified"
+)
+
+// VerifyEmail records that the user confirmed their email, a KYC verified
+// user keeps their level
+func (u *User) VerifyEmail(now time.Time) {
+	u.EmailVerifiedAt = now
+	if u.Level() == Unverified {
+		u.Verification = EmailVerified
+	}
+}
+
+// HasVerifiedEmail tells whether the user confirmed their email, KYC covers it
+func (u *User) HasVerifiedEmail() bool {
+	return !u.EmailVerifiedAt.IsZero() || u.Level() != Unverified
+}
+
+// EmailVerificationSender delivers the token the user confirms their email
+// with, it is called while the request that issued it waits
+type EmailVerificationSender interface {
+	SendEmailVerification(ctx context.Context, user User, token string)
+}
+
+// Level is Unverified for users stored before levels existed
+func (u *User) Level() VerificationLevel {
+	if u.Verification == "" {
+		return Unverified
+	}
+
+	return u.Verification
 }
 
 type UserRepositoryInterface interface {
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	// FindUsersByIds leaves out the ids no user has
+	FindUsersByIds(
+		ctx context.Context, userIds []string) ([]User, *internal_error.InternalError)
+
+	FindUserBySlug(
+		ctx context.Context, slug string) (*User, *internal_error.InternalError)
+
+	FindUserByEmail(
+		ctx context.Context, email string) (*User, *internal_error.InternalError)
+
+	CreateUser(
+		ctx context.Context, user *User) *internal_error.InternalError
+
+	UpdateUser(
+		ctx context.Context, user *User) *internal_error.InternalError
+
+	// VerifyEmail stores the user's email verification, unless their email
+	// changed since it was read
+	VerifyEmail(
+		ctx context.Context, user *User) *internal_error.InternalError
+
+	// DeleteAccount stores the anonymized user and drops what was only kept
+	// for them, see User.Anonymize
+	DeleteAccount(
+		ctx context.Context, user *User) *internal_error.InternalError
+
+	BlockUser(
+		ctx context.Context, userId, reason string, until time.Time) *internal_error.InternalError
+
+	UnblockUser(
+		ctx context.Context, userId string) *internal_error.InternalError
+
+	// FindUserBlock returns the user's block, nil when they aren't blocked
+	// or don't exist
+	FindUserBlock(
+		ctx context.Context, userId string) (*Block, *internal_error.InternalError)
 }
@@ -0,0 +1,118 @@
+package view_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Precision of the HyperLogLog sketch used to count unique visitors per day.
+// 2^10 registers keep the stored document small with a ~3% standard error.
+const (
+	registerPrecision = 10
+	RegisterCount     = 1 << registerPrecision
+)
+
+type AuctionView struct {
+	AuctionId string
+	VisitorId string
+	Timestamp time.Time
+}
+
+type DailyViews struct {
+	AuctionId string
+	Day       string
+	Views     int64
+	Registers map[int]int
+}
+
+func CreateAuctionView(auctionId, visitorId string) (*AuctionView, *internal_error.InternalError) {
+	view := &AuctionView{
+		AuctionId: auctionId,
+		VisitorId: visitorId,
+		Timestamp: time.Now(),
+	}
+
+	if err := view.Validate(); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (v *AuctionView) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(v.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	} else if v.VisitorId == "" {
+		return internal_error.NewBadRequestError("VisitorId is not a valid value")
+	}
+
+	return nil
+}
+
+// Day returns the UTC day bucket the view is aggregated into
+func (v *AuctionView) Day() string {
+	return v.Timestamp.UTC().Format(time.DateOnly)
+}
+
+// Register returns the sketch register index and rank the visitor contributes to
+func (v *AuctionView) Register() (int, int) {
+	sum := sha256.Sum256([]byte(v.VisitorId))
+	hash := binary.BigEndian.Uint64(sum[:8])
+
+	index := int(hash >> (64 - registerPrecision))
+	rank := bits.LeadingZeros64(hash<<registerPrecision|1<<(registerPrecision-1)) + 1
+
+	return index, rank
+}
+
+// MergeRegisters combines sketches so uniques can be estimated across several days
+func MergeRegisters(dailyViews []DailyViews) map[int]int {
+	merged := make(map[int]int)
+	for _, daily := range dailyViews {
+		for index, rank := range daily.Registers {
+			if rank > merged[index] {
+				merged[index] = rank
+			}
+		}
+	}
+
+	return merged
+}
+
+// EstimateUniqueVisitors applies the HyperLogLog estimator to a set of registers
+func EstimateUniqueVisitors(registers map[int]int) int64 {
+	m := float64(RegisterCount)
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	sum := float64(RegisterCount - len(registers))
+	emptyRegisters := RegisterCount - len(registers)
+	for _, rank := range registers {
+		if rank == 0 {
+			emptyRegisters++
+		}
+		sum += math.Pow(2, -float64(rank))
+	}
+
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && emptyRegisters > 0 {
+		estimate = m * math.Log(m/float64(emptyRegisters))
+	}
+
+	return int64(math.Round(estimate))
+}
+
+type ViewRepositoryInterface interface {
+	CreateView(
+		ctx context.Context,
+		viewEntity *AuctionView) *internal_error.InternalError
+
+	FindDailyViewsByAuctionId(
+		ctx context.Context, auctionId string) ([]DailyViews, *internal_error.InternalError)
+}
@@ -0,0 +1,73 @@
+package watchlist_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxWatchlistSize bounds how many auctions a user may watch at once
+const MaxWatchlistSize = 200
+
+// WatchlistEntry is an auction the user follows, they are alerted shortly
+// before it closes
+type WatchlistEntry struct {
+	UserId    string
+	AuctionId string
+	Timestamp time.Time
+}
+
+func CreateWatchlistEntry(userId, auctionId string) (*WatchlistEntry, *internal_error.InternalError) {
+	entry := &WatchlistEntry{
+		UserId:    userId,
+		AuctionId: auctionId,
+		Timestamp: time.Now(),
+	}
+
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (e *WatchlistEntry) Validate() *internal_error.InternalError {
+	if err := uuid.Validate(e.UserId); err != nil {
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	} else if err := uuid.Validate(e.AuctionId); err != nil {
+		return internal_error.NewBadRequestError("AuctionId is not a valid id")
+	}
+
+	return nil
+}
+
+// EndingSoonEvent tells a watcher the auction closes at EndTime
+type EndingSoonEvent struct {
+	UserId    string
+	AuctionId string
+	EndTime   time.Time
+}
+
+// EndingSoonNotifier delivers ending soon alerts, it is called once per
+// reminder for each watcher and the high bidder, and again when the
+// auction is extended
+type EndingSoonNotifier interface {
+	NotifyEndingSoon(ctx context.Context, event EndingSoonEvent)
+}
+
+type WatchlistRepositoryInterface interface {
+	// AddToWatchlist is a no-op when the user already watches the auction
+	AddToWatchlist(
+		ctx context.Context, entry *WatchlistEntry) *internal_error.InternalError
+
+	RemoveFromWatchlist(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	FindWatchlistByUserId(
+		ctx context.Context, userId string) ([]WatchlistEntry, *internal_error.InternalError)
+
+	CountWatchlistByUserId(
+		ctx context.Context, userId string) (int64, *internal_error.InternalError)
+}
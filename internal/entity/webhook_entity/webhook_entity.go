@@ -0,0 +1,156 @@
+package webhook_entity
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxURLLength bounds the URL deliveries are posted to
+const MaxURLLength = 2048
+
+// MaxDeadLetters bounds how many of a webhook's dead letters are listed,
+// newest first
+const MaxDeadLetters = 100
+
+// secretPrefix starts every signing secret, so leaked secrets are easy to
+// spot
+const secretPrefix = "whsec_"
+
+// EventType is an auction lifecycle event a webhook may subscribe to
+type EventType string
+
+const (
+	AuctionCreated EventType = "auction.created"
+	BidPlaced      EventType = "bid.placed"
+	AuctionClosed  EventType = "auction.closed"
+)
+
+// EventTypes lists every event a webhook may subscribe to
+var EventTypes = []EventType{AuctionCreated, BidPlaced, AuctionClosed}
+
+// Webhook posts the events it subscribed to to URL. Unlike API keys the
+// secret is kept as is, every delivery is signed with it
+type Webhook struct {
+	Id        string
+	URL       string
+	Secret    string
+	Events    []EventType
+	Timestamp time.Time
+}
+
+// CreateWebhook generates the webhook's signing secret, it is shown once
+// to whoever creates the webhook
+func CreateWebhook(rawURL string, events []EventType) (*Webhook, *internal_error.InternalError) {
+	webhook := &Webhook{
+		Id:        uuid.New().String(),
+		URL:       strings.TrimSpace(rawURL),
+		Events:    events,
+		Timestamp: time.Now(),
+	}
+
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, internal_error.NewInternalServerError("Error trying to generate webhook secret")
+	}
+	webhook.Secret = secretPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	return webhook, nil
+}
+
+func (w *Webhook) Validate() *internal_error.InternalError {
+	var causes []internal_error.Cause
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" ||
+		len(w.URL) > MaxURLLength {
+		causes = append(causes, internal_error.Cause{
+			Field:   "url",
+			Message: fmt.Sprintf("Must be an http or https URL of at most %d characters", MaxURLLength),
+		})
+	}
+	if len(w.Events) == 0 {
+		causes = append(causes, internal_error.Cause{Field: "events", Message: "At least one event is required"})
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(EventTypes, event) {
+			causes = append(causes, internal_error.Cause{
+				Field:   "events",
+				Message: fmt.Sprintf("Unknown event %q", event),
+			})
+		}
+	}
+
+	if len(causes) > 0 {
+		return internal_error.NewValidationError("Invalid webhook", causes...)
+	}
+
+	return nil
+}
+
+// Sign returns the signature header of a delivery sent at timestamp. It is
+// the HMAC-SHA256 of the unix timestamp and the payload joined by a dot,
+// keyed with the secret, so receivers can also turn down replayed
+// deliveries
+func (w *Webhook) Sign(timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// DeadLetter keeps a delivery the webhook never accepted, either because
+// every attempt failed or because it turned the delivery down for good
+type DeadLetter struct {
+	Id         string
+	WebhookId  string
+	DeliveryId string
+	Event      EventType
+	Payload    []byte
+	Attempts   int
+	LastError  string
+	Timestamp  time.Time
+}
+
+type WebhookRepositoryInterface interface {
+	CreateWebhook(
+		ctx context.Context, webhook *Webhook) *internal_error.InternalError
+
+	FindWebhooks(
+		ctx context.Context) ([]Webhook, *internal_error.InternalError)
+
+	// FindWebhooksByEvent returns the webhooks subscribed to the event
+	FindWebhooksByEvent(
+		ctx context.Context, event EventType) ([]Webhook, *internal_error.InternalError)
+
+	// DeleteWebhook drops the webhook along with its dead letters
+	DeleteWebhook(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	CreateDeadLetter(
+		ctx context.Context, deadLetter *DeadLetter) *internal_error.InternalError
+
+	// FindDeadLettersByWebhookId returns the webhook's latest
+	// MaxDeadLetters dead letters
+	FindDeadLettersByWebhookId(
+		ctx context.Context, webhookId string) ([]DeadLetter, *internal_error.InternalError)
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// queueSize bounds how many events a subscriber may fall behind, events
+// published while its queue is full are dropped for it
+const queueSize = 1000
+
+// Handler reacts to the events a subscriber gets
+type Handler func(ctx context.Context, event Event)
+
+// Bus hands the events published on it to every subscriber. Publishers
+// don't know who listens, so side effects hang off the events rather than
+// being called where things happen
+type Bus struct {
+	subscribers []*subscriber
+	mutex       *sync.RWMutex
+	closed      bool
+}
+
+type subscriber struct {
+	name    string
+	handler Handler
+	queue   chan published
+	done    chan struct{}
+}
+
+type published struct {
+	ctx   context.Context
+	event Event
+}
+
+func NewBus() *Bus {
+	return &Bus{mutex: &sync.RWMutex{}}
+}
+
+// Subscribe runs handler on every event published from then on. Each
+// subscriber works through its events on a goroutine of its own and in the
+// order they were published, so a slow one holds up neither the publisher
+// nor the other subscribers
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	sub := &subscriber{
+		name:    name,
+		handler: handler,
+		queue:   make(chan published, queueSize),
+		done:    make(chan struct{}),
+	}
+	b.subscribers = append(b.subscribers, sub)
+
+	go sub.run()
+}
+
+// Only narrows a handler down to the events of type E
+func Only[E Event](handler func(ctx context.Context, event E)) Handler {
+	return func(ctx context.Context, event Event) {
+		if event, ok := event.(E); ok {
+			handler(ctx, event)
+		}
+	}
+}
+
+// Publish hands the event to every subscriber without waiting on them.
+// Handlers run after the publisher moved on, so they get ctx's values but
+// not its cancellation. Events published after Shutdown are dropped
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.subscribers {
+		select {
+		case sub.queue <- published{ctx: context.WithoutCancel(ctx), event: event}:
+		default:
+			logger.Info("Event subscriber queue is full, event dropped",
+				zap.String("subscriber", sub.name), zap.String("event", fmt.Sprintf("%T", event)))
+		}
+	}
+}
+
+// Shutdown stops taking events and waits for the subscribers to handle
+// the ones they already queued, or for ctx to be done
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.mutex.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.subscribers {
+			close(sub.queue)
+		}
+	}
+	subscribers := b.subscribers
+	b.mutex.Unlock()
+
+	for _, sub := range subscribers {
+		select {
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (s *subscriber) run() {
+	defer close(s.done)
+
+	for published := range s.queue {
+		s.handle(published)
+	}
+}
+
+// A failing handler must not stop the subscriber from handling the next
+// events
+func (s *subscriber) handle(published published) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Event subscriber panicked", fmt.Errorf("%v", r),
+				zap.String("subscriber", s.name), zap.String("event", fmt.Sprintf("%T", published.event)))
+		}
+	}()
+
+	s.handler(published.ctx, published.event)
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"time"
+)
+
+// Event is something that happened in the domain, subscribers tell them
+// apart by their type
+type Event interface {
+	event()
+}
+
+// AuctionCreated is published once a new auction was stored, relisted
+// auctions included
+type AuctionCreated struct {
+	Auction auction_entity.Auction
+}
+
+// BidPlaced is published once a bid was stored, proxy bids and Dutch takes
+// included. The auction's current price is the bid's amount from then on
+type BidPlaced struct {
+	Bid bid_entity.Bid
+}
+
+// BidderOutbid is published when a bid took the lead from UserId
+type BidderOutbid struct {
+	AuctionId string
+	UserId    string
+	NewAmount float64
+}
+
+// AuctionEndingSoon is published once per watcher of an auction about to
+// close, and again when the auction is extended
+type AuctionEndingSoon struct {
+	AuctionId string
+	UserId    string
+	EndTime   time.Time
+}
+
+// SavedSearchMatched is published once per saved search a new auction
+// matches
+type SavedSearchMatched struct {
+	UserId        string
+	SavedSearchId string
+	Name          string
+	AuctionId     string
+	ProductName   string
+}
+
+// AuctionPriceDropped is published when a Dutch auction's price dropped
+type AuctionPriceDropped struct {
+	AuctionId string
+	Price     float64
+	Timestamp time.Time
+}
+
+// AuctionExtended is published when a late bid pushed back an auction's end
+type AuctionExtended struct {
+	AuctionId string
+	EndTime   time.Time
+	Timestamp time.Time
+}
+
+// AuctionClosed is published once an auction moved to Completed,
+// WinnerUserId is empty when it closed without bids
+type AuctionClosed struct {
+	AuctionId    string
+	WinnerUserId string
+	Timestamp    time.Time
+}
+
+func (AuctionCreated) event()      {}
+func (BidPlaced) event()           {}
+func (BidderOutbid) event()        {}
+func (AuctionEndingSoon) event()   {}
+func (SavedSearchMatched) event()  {}
+func (AuctionPriceDropped) event() {}
+func (AuctionExtended) event()     {}
+func (AuctionClosed) event()       {}
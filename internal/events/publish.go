@@ -0,0 +1,65 @@
+package events
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/saved_search_entity"
+	"auction_go/internal/entity/watchlist_entity"
+	"context"
+	"time"
+)
+
+// PublishAuctionCreated is meant to be registered as an auction create
+// hook
+func (b *Bus) PublishAuctionCreated(ctx context.Context, auction auction_entity.Auction) {
+	b.Publish(ctx, AuctionCreated{Auction: auction})
+}
+
+// PublishBidPlaced is meant to be registered as a bid hook
+func (b *Bus) PublishBidPlaced(ctx context.Context, bid bid_entity.Bid) {
+	b.Publish(ctx, BidPlaced{Bid: bid})
+}
+
+// NotifyOutbid makes the bus an outbid notifier
+func (b *Bus) NotifyOutbid(ctx context.Context, event bid_entity.OutbidEvent) {
+	b.Publish(ctx, BidderOutbid{
+		AuctionId: event.AuctionId,
+		UserId:    event.PreviousBidderId,
+		NewAmount: event.NewAmount,
+	})
+}
+
+// NotifyEndingSoon makes the bus an ending soon notifier
+func (b *Bus) NotifyEndingSoon(ctx context.Context, event watchlist_entity.EndingSoonEvent) {
+	b.Publish(ctx, AuctionEndingSoon{
+		AuctionId: event.AuctionId,
+		UserId:    event.UserId,
+		EndTime:   event.EndTime,
+	})
+}
+
+// NotifySavedSearchMatch makes the bus a saved search match notifier
+func (b *Bus) NotifySavedSearchMatch(ctx context.Context, event saved_search_entity.MatchEvent) {
+	b.Publish(ctx, SavedSearchMatched{
+		UserId:        event.UserId,
+		SavedSearchId: event.SavedSearchId,
+		Name:          event.Name,
+		AuctionId:     event.AuctionId,
+		ProductName:   event.ProductName,
+	})
+}
+
+// PublishPriceDrop is meant to be registered as a price drop hook
+func (b *Bus) PublishPriceDrop(ctx context.Context, auctionId string, price float64) {
+	b.Publish(ctx, AuctionPriceDropped{AuctionId: auctionId, Price: price, Timestamp: time.Now()})
+}
+
+// PublishExtension is meant to be registered as an extend hook
+func (b *Bus) PublishExtension(ctx context.Context, auctionId string, endTime time.Time) {
+	b.Publish(ctx, AuctionExtended{AuctionId: auctionId, EndTime: endTime, Timestamp: time.Now()})
+}
+
+// PublishAuctionClosed is meant to be registered as a close hook
+func (b *Bus) PublishAuctionClosed(ctx context.Context, auctionId, winnerUserId string) {
+	b.Publish(ctx, AuctionClosed{AuctionId: auctionId, WinnerUserId: winnerUserId, Timestamp: time.Now()})
+}
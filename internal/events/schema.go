@@ -0,0 +1,98 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is the version of the JSON events are published outside
+// the process with. It is bumped whenever a field changes meaning or goes
+// away, new fields don't bump it
+const SchemaVersion = 1
+
+// Types of the events published outside the process
+const (
+	AuctionCreatedType = "auction.created"
+	BidPlacedType      = "bid.placed"
+	AuctionClosedType  = "auction.closed"
+)
+
+// Message is an event as webhooks and the message broker get it. Id is
+// unique per event, so consumers can drop the ones delivered twice, and
+// Subject is the auction the event is about
+type Message struct {
+	Id         string    `json:"id"`
+	Type       string    `json:"type"`
+	Version    int       `json:"version"`
+	Subject    string    `json:"subject"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+type AuctionCreatedData struct {
+	AuctionId   string    `json:"auction_id"`
+	SellerId    string    `json:"seller_id"`
+	ProductName string    `json:"product_name"`
+	Category    string    `json:"category"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+}
+
+type BidPlacedData struct {
+	BidId     string    `json:"bid_id"`
+	AuctionId string    `json:"auction_id"`
+	UserId    string    `json:"user_id"`
+	Amount    float64   `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type AuctionClosedData struct {
+	AuctionId    string    `json:"auction_id"`
+	WinnerUserId string    `json:"winner_user_id,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// NewMessage tells whether the event is one published outside the process
+func NewMessage(event Event) (Message, bool) {
+	message := Message{Id: uuid.New().String(), Version: SchemaVersion}
+
+	switch event := event.(type) {
+	case AuctionCreated:
+		message.Type = AuctionCreatedType
+		message.Subject = event.Auction.Id
+		message.OccurredAt = event.Auction.Timestamp
+		message.Data = AuctionCreatedData{
+			AuctionId:   event.Auction.Id,
+			SellerId:    event.Auction.SellerId,
+			ProductName: event.Auction.ProductName,
+			Category:    event.Auction.Category,
+			StartTime:   event.Auction.StartTime,
+			EndTime:     event.Auction.EndTime,
+		}
+	case BidPlaced:
+		message.Type = BidPlacedType
+		message.Subject = event.Bid.AuctionId
+		message.OccurredAt = event.Bid.Timestamp
+		message.Data = BidPlacedData{
+			BidId:     event.Bid.Id,
+			AuctionId: event.Bid.AuctionId,
+			UserId:    event.Bid.UserId,
+			Amount:    event.Bid.Amount,
+			Timestamp: event.Bid.Timestamp,
+		}
+	case AuctionClosed:
+		message.Type = AuctionClosedType
+		message.Subject = event.AuctionId
+		message.OccurredAt = event.Timestamp
+		message.Data = AuctionClosedData{
+			AuctionId:    event.AuctionId,
+			WinnerUserId: event.WinnerUserId,
+			Timestamp:    event.Timestamp,
+		}
+	default:
+		return Message{}, false
+	}
+
+	return message, true
+}
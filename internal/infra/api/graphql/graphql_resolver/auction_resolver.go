@@ -0,0 +1,222 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/seller_usecase"
+	"auction_go/internal/usecase/stats_usecase"
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type auctionResolver struct {
+	auction auction_usecase.AuctionOutputDTO
+}
+
+func (a *auctionResolver) ID() graphql.ID {
+	return graphql.ID(a.auction.Id)
+}
+
+// Seller is batched with the other auctions' sellers, see loader
+func (a *auctionResolver) Seller(ctx context.Context) (*sellerResolver, error) {
+	if a.auction.SellerId == "" {
+		return nil, nil
+	}
+
+	seller, found, err := requestFrom(ctx).sellers.Load(ctx, a.auction.SellerId)
+	if err != nil {
+		return nil, toError(err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return &sellerResolver{seller: seller}, nil
+}
+
+func (a *auctionResolver) ProductName() string {
+	return a.auction.ProductName
+}
+
+func (a *auctionResolver) Category() string {
+	return a.auction.Category
+}
+
+func (a *auctionResolver) Description() string {
+	return a.auction.Description
+}
+
+func (a *auctionResolver) Condition() int32 {
+	return int32(a.auction.Condition)
+}
+
+func (a *auctionResolver) Status() int32 {
+	return int32(a.auction.Status)
+}
+
+func (a *auctionResolver) Type() int32 {
+	return int32(a.auction.Type)
+}
+
+func (a *auctionResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: a.auction.Timestamp}
+}
+
+func (a *auctionResolver) StartTime() graphql.Time {
+	return graphql.Time{Time: a.auction.StartTime}
+}
+
+func (a *auctionResolver) EndTime() graphql.Time {
+	return graphql.Time{Time: a.auction.EndTime}
+}
+
+func (a *auctionResolver) DurationSeconds() int32 {
+	return int32(a.auction.DurationSeconds)
+}
+
+func (a *auctionResolver) WinnerUserId() *graphql.ID {
+	if a.auction.WinnerUserId == "" {
+		return nil
+	}
+
+	winnerUserId := graphql.ID(a.auction.WinnerUserId)
+	return &winnerUserId
+}
+
+func (a *auctionResolver) WinningAmount() *float64 {
+	return nonZero(a.auction.WinningAmount)
+}
+
+func (a *auctionResolver) ReservePrice() *float64 {
+	return a.auction.ReservePrice
+}
+
+func (a *auctionResolver) HasReserve() bool {
+	return a.auction.HasReserve
+}
+
+func (a *auctionResolver) BuyNowPrice() *float64 {
+	return nonZero(a.auction.BuyNowPrice)
+}
+
+func (a *auctionResolver) MinIncrement() *float64 {
+	return nonZero(a.auction.MinIncrement)
+}
+
+func (a *auctionResolver) DutchPrice() *float64 {
+	return nonZero(a.auction.DutchPrice)
+}
+
+// Bids are batched with the other auctions' bids, see loader
+func (a *auctionResolver) Bids(ctx context.Context, args struct{ First int32 }) ([]*bidResolver, error) {
+	if args.First < 1 || args.First > maxNestedBids {
+		return nil, toError(internal_error.NewValidationError("Invalid fields", internal_error.Cause{
+			Field:   "first",
+			Message: "first must be between 1 and 20",
+		}))
+	}
+
+	bids, _, err := requestFrom(ctx).bids.Load(ctx, a.auction.Id)
+	if err != nil {
+		return nil, toError(err)
+	}
+	if len(bids) > int(args.First) {
+		bids = bids[:args.First]
+	}
+
+	bidResolvers := make([]*bidResolver, 0, len(bids))
+	for _, bid := range bids {
+		bidResolvers = append(bidResolvers, &bidResolver{bid: bid})
+	}
+
+	return bidResolvers, nil
+}
+
+// Stats are batched with the other auctions' stats, see loader
+func (a *auctionResolver) Stats(ctx context.Context) (*statsResolver, error) {
+	stats, _, err := requestFrom(ctx).stats.Load(ctx, a.auction.Id)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	return &statsResolver{stats: stats}, nil
+}
+
+type auctionConnectionResolver struct {
+	auctions   []*auctionResolver
+	nextCursor string
+}
+
+func (c *auctionConnectionResolver) Auctions() []*auctionResolver {
+	return c.auctions
+}
+
+func (c *auctionConnectionResolver) NextCursor() *string {
+	if c.nextCursor == "" {
+		return nil
+	}
+
+	return &c.nextCursor
+}
+
+type sellerResolver struct {
+	seller seller_usecase.SellerSummaryOutputDTO
+}
+
+func (s *sellerResolver) ID() graphql.ID {
+	return graphql.ID(s.seller.Id)
+}
+
+func (s *sellerResolver) Name() string {
+	return s.seller.Name
+}
+
+func (s *sellerResolver) Slug() *string {
+	if s.seller.Slug == "" {
+		return nil
+	}
+
+	return &s.seller.Slug
+}
+
+func (s *sellerResolver) Reputation() *reputationResolver {
+	return &reputationResolver{reputation: s.seller.Reputation}
+}
+
+type reputationResolver struct {
+	reputation auction_usecase.ReputationOutputDTO
+}
+
+func (r *reputationResolver) Score() float64 {
+	return r.reputation.Score
+}
+
+func (r *reputationResolver) Count() int32 {
+	return int32(r.reputation.Count)
+}
+
+type statsResolver struct {
+	stats stats_usecase.AuctionStatsOutputDTO
+}
+
+func (s *statsResolver) BidCount() int32 {
+	return int32(s.stats.BidCount)
+}
+
+func (s *statsResolver) UniqueBidders() int32 {
+	return int32(s.stats.UniqueBidders)
+}
+
+func (s *statsResolver) WatchCount() int32 {
+	return int32(s.stats.WatchCount)
+}
+
+// nonZero leaves unset amounts out
+func nonZero(amount float64) *float64 {
+	if amount == 0 {
+		return nil
+	}
+
+	return &amount
+}
@@ -0,0 +1,35 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/usecase/bid_usecase"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type bidResolver struct {
+	bid bid_usecase.BidOutputDTO
+}
+
+func (b *bidResolver) ID() graphql.ID {
+	return graphql.ID(b.bid.Id)
+}
+
+func (b *bidResolver) UserId() graphql.ID {
+	return graphql.ID(b.bid.UserId)
+}
+
+func (b *bidResolver) AuctionId() graphql.ID {
+	return graphql.ID(b.bid.AuctionId)
+}
+
+func (b *bidResolver) Amount() float64 {
+	return b.bid.Amount
+}
+
+func (b *bidResolver) Timestamp() graphql.Time {
+	return graphql.Time{Time: b.bid.Timestamp}
+}
+
+func (b *bidResolver) ReserveMet() *bool {
+	return b.bid.ReserveMet
+}
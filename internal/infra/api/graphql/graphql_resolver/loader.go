@@ -0,0 +1,84 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"sync"
+)
+
+// loader batches the lookups of a single request. Lists prime it with the
+// keys their items will ask for, and the first item asking fetches them all
+// in one go, so a page of auctions costs one query per nested field instead
+// of one per auction
+type loader[V any] struct {
+	fetch func(ctx context.Context, keys []string) (map[string]V, *internal_error.InternalError)
+
+	mutex   *sync.Mutex
+	pending map[string]struct{}
+	fetched map[string]*V
+}
+
+func newLoader[V any](
+	fetch func(ctx context.Context, keys []string) (map[string]V, *internal_error.InternalError)) *loader[V] {
+	return &loader[V]{
+		fetch:   fetch,
+		mutex:   &sync.Mutex{},
+		pending: make(map[string]struct{}),
+		fetched: make(map[string]*V),
+	}
+}
+
+// Prime queues keys to be fetched along with the next key loaded
+func (l *loader[V]) Prime(keys ...string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, key := range keys {
+		if _, found := l.fetched[key]; !found && key != "" {
+			l.pending[key] = struct{}{}
+		}
+	}
+}
+
+// Load tells false when fetch found nothing for key. Concurrent loads wait
+// for the fetch in flight rather than starting their own
+func (l *loader[V]) Load(ctx context.Context, key string) (V, bool, *internal_error.InternalError) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if value, found := l.fetched[key]; found {
+		return valueOf(value)
+	}
+
+	l.pending[key] = struct{}{}
+	keys := make([]string, 0, len(l.pending))
+	for pendingKey := range l.pending {
+		keys = append(keys, pendingKey)
+	}
+
+	values, err := l.fetch(ctx, keys)
+	if err != nil {
+		var zero V
+		return zero, false, err
+	}
+
+	for _, fetchedKey := range keys {
+		var value *V
+		if fetchedValue, found := values[fetchedKey]; found {
+			value = &fetchedValue
+		}
+		l.fetched[fetchedKey] = value
+	}
+	clear(l.pending)
+
+	return valueOf(l.fetched[key])
+}
+
+func valueOf[V any](value *V) (V, bool, *internal_error.InternalError) {
+	if value == nil {
+		var zero V
+		return zero, false, nil
+	}
+
+	return *value, true, nil
+}
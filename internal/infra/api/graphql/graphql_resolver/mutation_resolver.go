@@ -0,0 +1,135 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/bid_usecase"
+	"context"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type createAuctionInput struct {
+	ProductName     string
+	Category        string
+	Description     string
+	Condition       int32
+	DurationSeconds *int32
+	StartTime       *graphql.Time
+	ReservePrice    *float64
+	ReservePublic   *bool
+	BuyNowPrice     *float64
+	MinIncrement    *float64
+	MaxRelists      *int32
+	Type            *int32
+	DutchPricing    *dutchPricingInput
+	Location        *locationInput
+	PostalCode      *string
+}
+
+type dutchPricingInput struct {
+	StartPrice      float64
+	FloorPrice      float64
+	Decrement       float64
+	IntervalSeconds int32
+}
+
+type locationInput struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type placeBidInput struct {
+	AuctionId      graphql.ID
+	Amount         float64
+	IdempotencyKey *string
+}
+
+func (r *Resolver) CreateAuction(ctx context.Context, args struct{ Input createAuctionInput }) (bool, error) {
+	userId := requestFrom(ctx).userId
+	if userId == "" {
+		return false, toError(internal_error.NewUnauthorizedError("Missing bearer token"))
+	}
+
+	input := args.Input
+	auctionInputDTO := auction_usecase.AuctionInputDTO{
+		ProductName:     input.ProductName,
+		Category:        input.Category,
+		Description:     input.Description,
+		Condition:       auction_usecase.ProductCondition(input.Condition),
+		SellerId:        userId,
+		DurationSeconds: int64(valueOrZero(input.DurationSeconds)),
+		ReservePrice:    valueOrZero(input.ReservePrice),
+		ReservePublic:   valueOrZero(input.ReservePublic),
+		BuyNowPrice:     valueOrZero(input.BuyNowPrice),
+		MinIncrement:    valueOrZero(input.MinIncrement),
+		MaxRelists:      int(valueOrZero(input.MaxRelists)),
+		Type:            auction_usecase.AuctionType(valueOrZero(input.Type)),
+		PostalCode:      valueOrZero(input.PostalCode),
+	}
+	if input.StartTime != nil {
+		auctionInputDTO.StartTime = input.StartTime.Time
+	}
+	if pricing := input.DutchPricing; pricing != nil {
+		auctionInputDTO.DutchPricing = &auction_usecase.DutchPricingDTO{
+			StartPrice:      pricing.StartPrice,
+			FloorPrice:      pricing.FloorPrice,
+			Decrement:       pricing.Decrement,
+			IntervalSeconds: int64(pricing.IntervalSeconds),
+		}
+	}
+	if location := input.Location; location != nil {
+		auctionInputDTO.Location = &auction_usecase.LocationDTO{
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+		}
+	}
+
+	// The same rules POST /auction binds its body with
+	if err := binding.Validator.ValidateStruct(&auctionInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		causes := make([]internal_error.Cause, 0, len(restErr.Causes))
+		for _, cause := range restErr.Causes {
+			causes = append(causes, internal_error.Cause{Field: cause.Field, Message: cause.Message})
+		}
+		return false, toError(internal_error.NewValidationError(restErr.Message, causes...))
+	}
+
+	if err := r.auctionUseCase.CreateAuction(ctx, auctionInputDTO); err != nil {
+		return false, toError(err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) PlaceBid(ctx context.Context, args struct{ Input placeBidInput }) (*bidResolver, error) {
+	userId := requestFrom(ctx).userId
+	if userId == "" {
+		return nil, toError(internal_error.NewUnauthorizedError("Missing bearer token"))
+	}
+
+	bidOutputDTO, err := r.bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+		UserId:         userId,
+		AuctionId:      string(args.Input.AuctionId),
+		Amount:         args.Input.Amount,
+		IdempotencyKey: valueOrZero(args.Input.IdempotencyKey),
+	})
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	return &bidResolver{bid: *bidOutputDTO}, nil
+}
+
+// valueOrZero is the zero value for arguments left out
+func valueOrZero[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+
+	return *value
+}
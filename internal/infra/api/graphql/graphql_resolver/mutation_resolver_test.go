@@ -0,0 +1,158 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/bid_usecase"
+	"auction_go/internal/usecase/seller_usecase"
+	"auction_go/internal/usecase/stats_usecase"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// auctionUseCase records the auctions it is asked to create, the methods
+// the mutations don't call are left to the embedded nil interface
+type auctionUseCase struct {
+	auction_usecase.AuctionUseCaseInterface
+	created []auction_usecase.AuctionInputDTO
+}
+
+func (u *auctionUseCase) CreateAuction(
+	ctx context.Context, auctionInput auction_usecase.AuctionInputDTO) *internal_error.InternalError {
+	u.created = append(u.created, auctionInput)
+	return nil
+}
+
+// bidUseCase places every bid, turning down the sellers' own
+type bidUseCase struct {
+	bid_usecase.BidUseCaseInterface
+	placed []bid_usecase.BidInputDTO
+}
+
+func (u *bidUseCase) CreateBid(
+	ctx context.Context, bidInputDTO bid_usecase.BidInputDTO) (*bid_usecase.BidOutputDTO, *internal_error.InternalError) {
+	if bidInputDTO.UserId == "seller" {
+		return nil, internal_error.NewForbiddenError("Sellers can't bid on their own auctions")
+	}
+
+	u.placed = append(u.placed, bidInputDTO)
+	return &bid_usecase.BidOutputDTO{
+		Id:        "bid-1",
+		UserId:    bidInputDTO.UserId,
+		AuctionId: bidInputDTO.AuctionId,
+		Amount:    bidInputDTO.Amount,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// sellerUseCase and statsUseCase back the loaders, the mutations never
+// resolve them
+type sellerUseCase struct {
+	seller_usecase.SellerUseCaseInterface
+}
+
+type statsUseCase struct {
+	stats_usecase.StatsUseCaseInterface
+}
+
+const createAuctionMutation = `mutation {
+	createAuction(input: {productName: "Guitar", category: "Music", description: "A well kept guitar", condition: 0})
+}`
+
+const placeBidMutation = `mutation {
+	placeBid(input: {auctionId: "auction-1", amount: 100}) { userId auctionId amount }
+}`
+
+type MutationResolverSuite struct {
+	suite.Suite
+	auctions *auctionUseCase
+	bids     *bidUseCase
+	resolver *Resolver
+	schema   *graphql.Schema
+}
+
+func (suite *MutationResolverSuite) SetupTest() {
+	suite.auctions = &auctionUseCase{}
+	suite.bids = &bidUseCase{}
+	suite.resolver = NewResolver(suite.auctions, suite.bids, sellerUseCase{}, statsUseCase{})
+	suite.schema = suite.resolver.Schema()
+}
+
+// exec runs query for userId, empty for anonymous queries
+func (suite *MutationResolverSuite) exec(userId, query string) *graphql.Response {
+	ctx := suite.resolver.NewRequestContext(context.Background(), userId)
+	return suite.schema.Exec(ctx, query, "", nil)
+}
+
+func (suite *MutationResolverSuite) TestMutationsNeedAUser() {
+	cases := []struct {
+		name   string
+		userId string
+		query  string
+		code   string
+	}{
+		{name: "anonymous auction", userId: "", query: createAuctionMutation, code: "unauthorized"},
+		{name: "anonymous bid", userId: "", query: placeBidMutation, code: "unauthorized"},
+		{name: "seller bidding", userId: "seller", query: placeBidMutation, code: "forbidden"},
+		{name: "authenticated auction", userId: "user-1", query: createAuctionMutation},
+		{name: "authenticated bid", userId: "user-1", query: placeBidMutation},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			response := suite.exec(tc.userId, tc.query)
+			if tc.code == "" {
+				assert.Empty(suite.T(), response.Errors)
+				return
+			}
+
+			assert.Len(suite.T(), response.Errors, 1)
+			assert.Equal(suite.T(), tc.code, response.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func (suite *MutationResolverSuite) TestMutationsActForTheUser() {
+	response := suite.exec("user-1", createAuctionMutation)
+	assert.Empty(suite.T(), response.Errors)
+	assert.Len(suite.T(), suite.auctions.created, 1)
+	assert.Equal(suite.T(), "user-1", suite.auctions.created[0].SellerId)
+
+	response = suite.exec("user-1", placeBidMutation)
+	assert.Empty(suite.T(), response.Errors)
+	assert.Len(suite.T(), suite.bids.placed, 1)
+	assert.Equal(suite.T(), "user-1", suite.bids.placed[0].UserId)
+
+	var data struct {
+		PlaceBid struct {
+			UserId    string  `json:"userId"`
+			AuctionId string  `json:"auctionId"`
+			Amount    float64 `json:"amount"`
+		} `json:"placeBid"`
+	}
+	err := json.Unmarshal(response.Data, &data)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "user-1", data.PlaceBid.UserId)
+	assert.Equal(suite.T(), "auction-1", data.PlaceBid.AuctionId)
+	assert.Equal(suite.T(), 100.0, data.PlaceBid.Amount)
+}
+
+func (suite *MutationResolverSuite) TestInvalidAuctionsAreNotCreated() {
+	response := suite.exec("user-1", `mutation {
+		createAuction(input: {productName: "Guitar", category: "Music", description: "Short", condition: 0})
+	}`)
+
+	assert.Len(suite.T(), response.Errors, 1)
+	assert.Equal(suite.T(), "bad_request", response.Errors[0].Extensions["code"])
+	assert.Empty(suite.T(), suite.auctions.created)
+}
+
+func TestMutationResolverSuite(t *testing.T) {
+	suite.Run(t, new(MutationResolverSuite))
+}
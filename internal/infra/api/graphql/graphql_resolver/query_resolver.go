@@ -0,0 +1,149 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+const maxAuctionPageSize = 100
+
+type auctionFilter struct {
+	Status      *int32
+	Category    *string
+	Condition   *int32
+	SellerId    *graphql.ID
+	MinPrice    *float64
+	MaxPrice    *float64
+	ProductName *string
+}
+
+type auctionsArgs struct {
+	Filter     *auctionFilter
+	Sort       string
+	Descending *bool
+	After      *string
+	First      int32
+}
+
+func (r *Resolver) Auction(ctx context.Context, args struct{ Id graphql.ID }) (*auctionResolver, error) {
+	if err := uuid.Validate(string(args.Id)); err != nil {
+		return nil, toError(internal_error.NewValidationError("Invalid fields", internal_error.Cause{
+			Field:   "id",
+			Message: "Invalid UUID value",
+		}))
+	}
+
+	auction, err := r.auctionUseCase.FindAuctionById(ctx, string(args.Id))
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, nil
+		}
+		return nil, toError(err)
+	}
+
+	return &auctionResolver{auction: *auction}, nil
+}
+
+// Auctions primes the loaders with the page's auctions, so their sellers,
+// bids and stats are each read in a single query
+func (r *Resolver) Auctions(ctx context.Context, args auctionsArgs) (*auctionConnectionResolver, error) {
+	queryInput, err := toAuctionQuery(args)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	auctions, err := r.auctionUseCase.FindAuctions(ctx, queryInput)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	request := requestFrom(ctx)
+	connection := &auctionConnectionResolver{
+		auctions:   make([]*auctionResolver, 0, len(auctions.Auctions)),
+		nextCursor: auctions.NextCursor,
+	}
+	for _, auction := range auctions.Auctions {
+		connection.auctions = append(connection.auctions, &auctionResolver{auction: auction})
+
+		request.sellers.Prime(auction.SellerId)
+		request.bids.Prime(auction.Id)
+		request.stats.Prime(auction.Id)
+	}
+
+	return connection, nil
+}
+
+// toAuctionQuery checks the arguments like GET /auction checks its query
+// params
+func toAuctionQuery(args auctionsArgs) (auction_usecase.AuctionQueryInputDTO, *internal_error.InternalError) {
+	queryInput := auction_usecase.AuctionQueryInputDTO{
+		Sort:  args.Sort,
+		Limit: int64(args.First),
+	}
+	if args.After != nil {
+		queryInput.Cursor = *args.After
+	}
+
+	var causes []internal_error.Cause
+	if filter := args.Filter; filter != nil {
+		if filter.Status != nil {
+			status := auction_usecase.AuctionStatus(*filter.Status)
+			queryInput.Status = &status
+		}
+		if filter.Category != nil {
+			queryInput.Category = *filter.Category
+		}
+		if filter.Condition != nil {
+			if *filter.Condition < 1 {
+				causes = append(causes, internal_error.Cause{Field: "condition", Message: "condition must be a positive number"})
+			}
+			queryInput.Condition = auction_usecase.ProductCondition(*filter.Condition)
+		}
+		if filter.SellerId != nil {
+			if err := uuid.Validate(string(*filter.SellerId)); err != nil {
+				causes = append(causes, internal_error.Cause{Field: "sellerId", Message: "Invalid UUID value"})
+			}
+			queryInput.SellerId = string(*filter.SellerId)
+		}
+		if filter.MinPrice != nil {
+			if *filter.MinPrice < 0 {
+				causes = append(causes, internal_error.Cause{Field: "minPrice", Message: "minPrice must be a positive amount"})
+			}
+			queryInput.MinPrice = *filter.MinPrice
+		}
+		if filter.MaxPrice != nil {
+			if *filter.MaxPrice < 0 {
+				causes = append(causes, internal_error.Cause{Field: "maxPrice", Message: "maxPrice must be a positive amount"})
+			}
+			queryInput.MaxPrice = *filter.MaxPrice
+		}
+		if filter.ProductName != nil {
+			queryInput.ProductName = *filter.ProductName
+		}
+	}
+
+	switch queryInput.Sort {
+	case "end_time", "price":
+	case "created_at":
+		queryInput.Descending = true
+	default:
+		causes = append(causes, internal_error.Cause{Field: "sort", Message: "sort must be end_time, created_at or price"})
+	}
+	if args.Descending != nil {
+		queryInput.Descending = *args.Descending
+	}
+
+	if queryInput.Limit < 1 || queryInput.Limit > maxAuctionPageSize {
+		causes = append(causes, internal_error.Cause{Field: "first", Message: "first must be between 1 and 100"})
+	}
+
+	if len(causes) > 0 {
+		return queryInput, internal_error.NewValidationError("Invalid fields", causes...)
+	}
+
+	return queryInput, nil
+}
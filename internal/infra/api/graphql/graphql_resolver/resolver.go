@@ -0,0 +1,107 @@
+package graphql_resolver
+
+import (
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/bid_usecase"
+	"auction_go/internal/usecase/seller_usecase"
+	"auction_go/internal/usecase/stats_usecase"
+	"context"
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxNestedBids is how many of each auction's latest bids are fetched for
+// the bids field
+const maxNestedBids = 20
+
+// maxParallelism lets every auction of a full page resolve its fields at
+// the same time
+const maxParallelism = 100
+
+// Resolver answers the /graphql queries and mutations from the same use
+// cases as the REST controllers
+type Resolver struct {
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+	bidUseCase     bid_usecase.BidUseCaseInterface
+	sellerUseCase  seller_usecase.SellerUseCaseInterface
+	statsUseCase   stats_usecase.StatsUseCaseInterface
+}
+
+func NewResolver(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface,
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	sellerUseCase seller_usecase.SellerUseCaseInterface,
+	statsUseCase stats_usecase.StatsUseCaseInterface) *Resolver {
+	return &Resolver{
+		auctionUseCase: auctionUseCase,
+		bidUseCase:     bidUseCase,
+		sellerUseCase:  sellerUseCase,
+		statsUseCase:   statsUseCase,
+	}
+}
+
+// Schema parses schema.graphql against the resolver, it panics on a schema
+// the resolver doesn't match
+func (r *Resolver) Schema() *graphql.Schema {
+	return graphql.MustParseSchema(schema, r, graphql.MaxParallelism(maxParallelism))
+}
+
+type requestKey struct{}
+
+// request is what a single query shares across its resolvers
+type request struct {
+	userId  string
+	sellers *loader[seller_usecase.SellerSummaryOutputDTO]
+	bids    *loader[[]bid_usecase.BidOutputDTO]
+	stats   *loader[stats_usecase.AuctionStatsOutputDTO]
+}
+
+// NewRequestContext gives a query its own loaders, userId is who mutations
+// act for and is empty for anonymous queries
+func (r *Resolver) NewRequestContext(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, requestKey{}, &request{
+		userId:  userId,
+		sellers: newLoader(r.sellerUseCase.FindSellersByIds),
+		bids: newLoader(func(ctx context.Context, auctionIds []string) (
+			map[string][]bid_usecase.BidOutputDTO, *internal_error.InternalError) {
+			return r.bidUseCase.FindLatestBidsByAuctionIds(ctx, auctionIds, maxNestedBids)
+		}),
+		stats: newLoader(r.statsUseCase.FindStatsByAuctionIds),
+	})
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// resolverError carries the use cases' error codes and field causes in the
+// error's extensions
+type resolverError struct {
+	err *internal_error.InternalError
+}
+
+func toError(err *internal_error.InternalError) error {
+	return &resolverError{err: err}
+}
+
+func (e *resolverError) Error() string {
+	return e.err.Message
+}
+
+func (e *resolverError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.err.Err}
+	if len(e.err.Causes) > 0 {
+		causes := make([]map[string]string, 0, len(e.err.Causes))
+		for _, cause := range e.err.Causes {
+			causes = append(causes, map[string]string{"field": cause.Field, "message": cause.Message})
+		}
+		extensions["causes"] = causes
+	}
+
+	return extensions
+}
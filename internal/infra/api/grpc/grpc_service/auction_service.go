@@ -0,0 +1,221 @@
+package grpc_service
+
+import (
+	"auction_go/internal/infra/api/grpc/pb"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultAuctionPageSize = 20
+	maxAuctionPageSize     = 100
+)
+
+type AuctionService struct {
+	pb.UnimplementedAuctionServiceServer
+
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+}
+
+func NewAuctionService(auctionUseCase auction_usecase.AuctionUseCaseInterface) *AuctionService {
+	return &AuctionService{
+		auctionUseCase: auctionUseCase,
+	}
+}
+
+func (s *AuctionService) CreateAuction(
+	ctx context.Context, request *pb.CreateAuctionRequest) (*pb.CreateAuctionResponse, error) {
+	auctionInputDTO := auction_usecase.AuctionInputDTO{
+		ProductName:     request.GetProductName(),
+		Category:        request.GetCategory(),
+		Description:     request.GetDescription(),
+		Condition:       auction_usecase.ProductCondition(request.GetCondition()),
+		SellerId:        AuthenticatedUserId(ctx),
+		DurationSeconds: request.GetDurationSeconds(),
+		ReservePrice:    request.GetReservePrice(),
+		ReservePublic:   request.GetReservePublic(),
+		BuyNowPrice:     request.GetBuyNowPrice(),
+		MinIncrement:    request.GetMinIncrement(),
+		MaxRelists:      int(request.GetMaxRelists()),
+		Type:            auction_usecase.AuctionType(request.GetType()),
+		PostalCode:      request.GetPostalCode(),
+	}
+	if request.GetStartTime() != nil {
+		auctionInputDTO.StartTime = request.GetStartTime().AsTime()
+	}
+	if pricing := request.GetDutchPricing(); pricing != nil {
+		auctionInputDTO.DutchPricing = &auction_usecase.DutchPricingDTO{
+			StartPrice:      pricing.GetStartPrice(),
+			FloorPrice:      pricing.GetFloorPrice(),
+			Decrement:       pricing.GetDecrement(),
+			IntervalSeconds: pricing.GetIntervalSeconds(),
+		}
+	}
+	if location := request.GetLocation(); location != nil {
+		auctionInputDTO.Location = &auction_usecase.LocationDTO{
+			Latitude:  location.GetLatitude(),
+			Longitude: location.GetLongitude(),
+		}
+	}
+
+	// The same rules the HTTP API binds its body with
+	if err := binding.Validator.ValidateStruct(&auctionInputDTO); err != nil {
+		return nil, validationError(err)
+	}
+
+	if err := s.auctionUseCase.CreateAuction(ctx, auctionInputDTO); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.CreateAuctionResponse{}, nil
+}
+
+func (s *AuctionService) GetAuction(ctx context.Context, request *pb.GetAuctionRequest) (*pb.Auction, error) {
+	if err := uuid.Validate(request.GetAuctionId()); err != nil {
+		return nil, invalidFields(internal_error.Cause{Field: "auction_id", Message: "Invalid UUID value"})
+	}
+
+	auction, err := s.auctionUseCase.FindAuctionById(ctx, request.GetAuctionId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toAuction(*auction), nil
+}
+
+func (s *AuctionService) ListAuctions(
+	ctx context.Context, request *pb.ListAuctionsRequest) (*pb.ListAuctionsResponse, error) {
+	queryInput, err := toAuctionQuery(request)
+	if err != nil {
+		return nil, err
+	}
+
+	auctions, errFind := s.auctionUseCase.FindAuctions(ctx, queryInput)
+	if errFind != nil {
+		return nil, statusError(errFind)
+	}
+
+	response := &pb.ListAuctionsResponse{
+		Auctions:   make([]*pb.Auction, 0, len(auctions.Auctions)),
+		NextCursor: auctions.NextCursor,
+	}
+	for _, auction := range auctions.Auctions {
+		response.Auctions = append(response.Auctions, toAuction(auction))
+	}
+
+	return response, nil
+}
+
+// toAuctionQuery checks the listing's filters like the HTTP listing does
+// with its query params
+func toAuctionQuery(request *pb.ListAuctionsRequest) (auction_usecase.AuctionQueryInputDTO, error) {
+	queryInput := auction_usecase.AuctionQueryInputDTO{
+		Category:    request.GetCategory(),
+		Condition:   auction_usecase.ProductCondition(request.GetCondition()),
+		SellerId:    request.GetSellerId(),
+		MinPrice:    request.GetMinPrice(),
+		MaxPrice:    request.GetMaxPrice(),
+		ProductName: request.GetProductName(),
+		Sort:        request.GetSort(),
+		Cursor:      request.GetCursor(),
+		Limit:       request.GetLimit(),
+	}
+
+	var causes []internal_error.Cause
+	if request.Status != nil {
+		status := auction_usecase.AuctionStatus(request.GetStatus())
+		queryInput.Status = &status
+	}
+
+	if queryInput.Condition < 0 {
+		causes = append(causes, internal_error.Cause{Field: "condition", Message: "condition must be a positive number"})
+	}
+
+	if queryInput.SellerId != "" {
+		if err := uuid.Validate(queryInput.SellerId); err != nil {
+			causes = append(causes, internal_error.Cause{Field: "seller_id", Message: "Invalid UUID value"})
+		}
+	}
+
+	if queryInput.MinPrice < 0 {
+		causes = append(causes, internal_error.Cause{Field: "min_price", Message: "min_price must be a positive amount"})
+	}
+	if queryInput.MaxPrice < 0 {
+		causes = append(causes, internal_error.Cause{Field: "max_price", Message: "max_price must be a positive amount"})
+	}
+
+	switch queryInput.Sort {
+	case "":
+		queryInput.Sort = "end_time"
+	case "end_time", "price":
+	case "created_at":
+		queryInput.Descending = true
+	default:
+		causes = append(causes, internal_error.Cause{Field: "sort", Message: "sort must be end_time, created_at or price"})
+	}
+	if request.Descending != nil {
+		queryInput.Descending = request.GetDescending()
+	}
+
+	if queryInput.Limit == 0 {
+		queryInput.Limit = defaultAuctionPageSize
+	}
+	if queryInput.Limit < 1 || queryInput.Limit > maxAuctionPageSize {
+		causes = append(causes, internal_error.Cause{Field: "limit", Message: "limit must be between 1 and 100"})
+	}
+
+	if len(causes) > 0 {
+		return queryInput, invalidFields(causes...)
+	}
+
+	return queryInput, nil
+}
+
+func toAuction(auction auction_usecase.AuctionOutputDTO) *pb.Auction {
+	auctionPb := &pb.Auction{
+		Id:              auction.Id,
+		SellerId:        auction.SellerId,
+		ProductName:     auction.ProductName,
+		Category:        auction.Category,
+		Description:     auction.Description,
+		Condition:       pb.ProductCondition(auction.Condition),
+		Status:          pb.AuctionStatus(auction.Status),
+		Timestamp:       toTimestamp(auction.Timestamp),
+		StartTime:       toTimestamp(auction.StartTime),
+		DurationSeconds: auction.DurationSeconds,
+		EndTime:         toTimestamp(auction.EndTime),
+		WinnerUserId:    auction.WinnerUserId,
+		WinningAmount:   auction.WinningAmount,
+		ReservePrice:    auction.ReservePrice,
+		HasReserve:      auction.HasReserve,
+		BuyNowPrice:     auction.BuyNowPrice,
+		MinIncrement:    auction.MinIncrement,
+		Type:            pb.AuctionType(auction.Type),
+		DutchPrice:      auction.DutchPrice,
+	}
+	if auction.DutchPricing != nil {
+		auctionPb.DutchPricing = &pb.DutchPricing{
+			StartPrice:      auction.DutchPricing.StartPrice,
+			FloorPrice:      auction.DutchPricing.FloorPrice,
+			Decrement:       auction.DutchPricing.Decrement,
+			IntervalSeconds: auction.DutchPricing.IntervalSeconds,
+		}
+	}
+
+	return auctionPb
+}
+
+// toTimestamp leaves unset times out
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,87 @@
+package grpc_service
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/infra/api/grpc/pb"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authenticatedMethods are the calls that need a user, along with the API
+// key scope that may stand in for a bearer token. An empty scope only takes
+// bearer tokens
+var authenticatedMethods = map[string]api_key_entity.Scope{
+	pb.AuctionService_CreateAuction_FullMethodName: "",
+	pb.BidService_PlaceBid_FullMethodName:          api_key_entity.ScopeBid,
+}
+
+type userIdKey struct{}
+
+// authInterceptor checks the "authorization: Bearer <token>" or "x-api-key"
+// metadata of the calls in authenticatedMethods, like UserAuth and
+// UserOrApiKeyAuth do for HTTP. The user is read back with
+// AuthenticatedUserId
+func authInterceptor(
+	apiKeys middleware.ApiKeyAuthenticator, accounts middleware.AccountFinder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, found := authenticatedMethods[info.FullMethod]
+		if !found {
+			return handler(ctx, req)
+		}
+
+		userId, err := authenticate(ctx, apiKeys, scope)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		deleted, err := accounts.IsAccountDeleted(ctx, userId)
+		if err != nil {
+			return nil, statusError(err)
+		} else if deleted {
+			return nil, statusError(internal_error.NewUnauthorizedError("The account was deleted"))
+		}
+
+		return handler(context.WithValue(ctx, userIdKey{}, userId), req)
+	}
+}
+
+func authenticate(
+	ctx context.Context, apiKeys middleware.ApiKeyAuthenticator,
+	scope api_key_entity.Scope) (string, *internal_error.InternalError) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get("x-api-key"); len(keys) > 0 && scope != "" {
+		return apiKeys.Authenticate(ctx, keys[0], scope)
+	}
+
+	var token string
+	found := false
+	if authorization := md.Get("authorization"); len(authorization) > 0 {
+		token, found = strings.CutPrefix(authorization[0], "Bearer ")
+	}
+	if !found {
+		return "", internal_error.NewUnauthorizedError("Missing bearer token")
+	}
+
+	claims, err := jwt.Parse(strings.TrimSpace(token), time.Now())
+	if err != nil {
+		return "", internal_error.NewUnauthorizedError("Invalid bearer token")
+	}
+
+	return claims.Subject, nil
+}
+
+// AuthenticatedUserId is the user authInterceptor let through, empty on
+// public calls
+func AuthenticatedUserId(ctx context.Context) string {
+	userId, _ := ctx.Value(userIdKey{}).(string)
+	return userId
+}
@@ -0,0 +1,165 @@
+package grpc_service
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/infra/api/grpc/pb"
+	"auction_go/internal/internal_error"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeys authenticates the keys it holds, each granted a single scope
+type apiKeys map[string]struct {
+	userId string
+	scope  api_key_entity.Scope
+}
+
+func (k apiKeys) Authenticate(
+	ctx context.Context, key string, scope api_key_entity.Scope) (string, *internal_error.InternalError) {
+	apiKey, ok := k[key]
+	if !ok {
+		return "", internal_error.NewUnauthorizedError("Invalid API key")
+	}
+	if !apiKey.scope.Allows(scope) {
+		return "", internal_error.NewForbiddenError("The API key is not allowed to " + string(scope))
+	}
+	return apiKey.userId, nil
+}
+
+// deletedAccounts reports the users it holds as deleted
+type deletedAccounts map[string]bool
+
+func (d deletedAccounts) IsAccountDeleted(ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+	return d[userId], nil
+}
+
+type AuthInterceptorSuite struct {
+	suite.Suite
+	interceptor grpc.UnaryServerInterceptor
+}
+
+func (suite *AuthInterceptorSuite) SetupTest() {
+	suite.T().Setenv(jwt.JWT_SECRET, "test-secret")
+	suite.interceptor = authInterceptor(
+		apiKeys{
+			"ak_bid":     {userId: "user-1", scope: api_key_entity.ScopeBid},
+			"ak_read":    {userId: "user-1", scope: api_key_entity.ScopeRead},
+			"ak_deleted": {userId: "deleted-user", scope: api_key_entity.ScopeBid},
+		},
+		deletedAccounts{"deleted-user": true},
+	)
+}
+
+func (suite *AuthInterceptorSuite) issue(userId string) string {
+	token, _, err := jwt.Issue(userId, time.Now())
+	assert.Nil(suite.T(), err)
+	return "Bearer " + token
+}
+
+// call runs a call to method carrying pairs as metadata, answering with the
+// user the handler saw
+func (suite *AuthInterceptorSuite) call(method string, pairs ...string) (string, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	response, err := suite.interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return AuthenticatedUserId(ctx), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.(string), nil
+}
+
+func (suite *AuthInterceptorSuite) TestAuthInterceptor() {
+	cases := []struct {
+		name   string
+		method string
+		pairs  []string
+		code   codes.Code
+		userId string
+	}{
+		{
+			name:   "public calls go through anonymously",
+			method: pb.AuctionService_GetAuction_FullMethodName,
+			code:   codes.OK,
+			userId: "",
+		},
+		{
+			name:   "bearer token",
+			method: pb.AuctionService_CreateAuction_FullMethodName,
+			pairs:  []string{"authorization", suite.issue("user-2")},
+			code:   codes.OK,
+			userId: "user-2",
+		},
+		{
+			name:   "no token",
+			method: pb.AuctionService_CreateAuction_FullMethodName,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "invalid token",
+			method: pb.BidService_PlaceBid_FullMethodName,
+			pairs:  []string{"authorization", "Bearer not-a-token"},
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "token of a deleted account",
+			method: pb.AuctionService_CreateAuction_FullMethodName,
+			pairs:  []string{"authorization", suite.issue("deleted-user")},
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "API key places bids",
+			method: pb.BidService_PlaceBid_FullMethodName,
+			pairs:  []string{"x-api-key", "ak_bid"},
+			code:   codes.OK,
+			userId: "user-1",
+		},
+		{
+			name:   "API key lacking the scope",
+			method: pb.BidService_PlaceBid_FullMethodName,
+			pairs:  []string{"x-api-key", "ak_read"},
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "unknown API key",
+			method: pb.BidService_PlaceBid_FullMethodName,
+			pairs:  []string{"x-api-key", "ak_unknown"},
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "API key of a deleted account",
+			method: pb.BidService_PlaceBid_FullMethodName,
+			pairs:  []string{"x-api-key", "ak_deleted"},
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "API keys can't create auctions",
+			method: pb.AuctionService_CreateAuction_FullMethodName,
+			pairs:  []string{"x-api-key", "ak_bid"},
+			code:   codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			userId, err := suite.call(tc.method, tc.pairs...)
+			assert.Equal(suite.T(), tc.code, status.Code(err))
+			assert.Equal(suite.T(), tc.userId, userId)
+		})
+	}
+}
+
+func TestAuthInterceptorSuite(t *testing.T) {
+	suite.Run(t, new(AuthInterceptorSuite))
+}
@@ -0,0 +1,223 @@
+package grpc_service
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/infra/api/grpc/pb"
+	"auction_go/internal/infra/realtime"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/bid_usecase"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultBidPageSize = 20
+	maxBidPageSize     = 100
+)
+
+// bidUpdateTypes are the hub events StreamBids sends, viewer counts are
+// left to the HTTP streams
+var bidUpdateTypes = map[realtime.EventType]pb.BidUpdate_Type{
+	realtime.BidPlaced:       pb.BidUpdate_TYPE_BID,
+	realtime.PriceChanged:    pb.BidUpdate_TYPE_PRICE,
+	realtime.AuctionExtended: pb.BidUpdate_TYPE_EXTENDED,
+	realtime.AuctionClosed:   pb.BidUpdate_TYPE_CLOSED,
+}
+
+type BidService struct {
+	pb.UnimplementedBidServiceServer
+
+	bidUseCase     bid_usecase.BidUseCaseInterface
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+	hub            *realtime.Hub
+}
+
+func NewBidService(
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	auctionUseCase auction_usecase.AuctionUseCaseInterface,
+	hub *realtime.Hub) *BidService {
+	return &BidService{
+		bidUseCase:     bidUseCase,
+		auctionUseCase: auctionUseCase,
+		hub:            hub,
+	}
+}
+
+func (s *BidService) PlaceBid(ctx context.Context, request *pb.PlaceBidRequest) (*pb.Bid, error) {
+	bidOutputDTO, err := s.bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+		UserId:         AuthenticatedUserId(ctx),
+		AuctionId:      request.GetAuctionId(),
+		Amount:         request.GetAmount(),
+		IdempotencyKey: request.GetIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toBid(*bidOutputDTO), nil
+}
+
+func (s *BidService) ListBids(ctx context.Context, request *pb.ListBidsRequest) (*pb.ListBidsResponse, error) {
+	var causes []internal_error.Cause
+	if err := uuid.Validate(request.GetAuctionId()); err != nil {
+		causes = append(causes, internal_error.Cause{Field: "auction_id", Message: "Invalid UUID value"})
+	}
+
+	sort := bid_entity.BidSort(request.GetSort())
+	if sort == "" {
+		sort = bid_entity.SortByTime
+	}
+	if sort != bid_entity.SortByAmount && sort != bid_entity.SortByTime {
+		causes = append(causes, internal_error.Cause{Field: "sort", Message: "sort must be amount or time"})
+	}
+
+	limit := request.GetLimit()
+	if limit == 0 {
+		limit = defaultBidPageSize
+	}
+	if limit < 1 || limit > maxBidPageSize {
+		causes = append(causes, internal_error.Cause{Field: "limit", Message: "limit must be between 1 and 100"})
+	}
+
+	if len(causes) > 0 {
+		return nil, invalidFields(causes...)
+	}
+
+	bidPage, err := s.bidUseCase.FindBidsByAuctionId(ctx, request.GetAuctionId(), request.GetCursor(), limit, sort)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &pb.ListBidsResponse{
+		Bids:       make([]*pb.Bid, 0, len(bidPage.Bids)),
+		NextCursor: bidPage.NextCursor,
+	}
+	for _, bid := range bidPage.Bids {
+		response.Bids = append(response.Bids, toBid(bid))
+	}
+
+	return response, nil
+}
+
+// StreamBids follows the auction from the hub like the SSE stream does. An
+// auction that ended while the client was away still sends the updates it
+// missed, and is turned down once there is nothing left to catch up on
+func (s *BidService) StreamBids(
+	request *pb.StreamBidsRequest, stream grpc.ServerStreamingServer[pb.BidUpdate]) error {
+	auctionId := request.GetAuctionId()
+	if err := uuid.Validate(auctionId); err != nil {
+		return invalidFields(internal_error.Cause{Field: "auction_id", Message: "Invalid UUID value"})
+	}
+
+	lastUpdateId := request.GetLastUpdateId()
+	if lastUpdateId < 0 {
+		return invalidFields(internal_error.Cause{Field: "last_update_id", Message: "last_update_id must be an update id"})
+	}
+	resuming := lastUpdateId > 0
+
+	running := true
+	if err := s.checkStreamable(stream.Context(), auctionId); err != nil {
+		if !resuming || err.Err != "invalid_transition" {
+			return statusError(err)
+		}
+		running = false
+	}
+
+	var subscription *realtime.Subscription
+	var missed []realtime.Event
+	if resuming {
+		subscription, missed = s.hub.Resume(auctionId, lastUpdateId)
+	} else {
+		subscription = s.hub.Subscribe(auctionId)
+	}
+	defer subscription.Close()
+
+	if !running && len(missed) == 0 {
+		return statusError(internal_error.NewInvalidTransitionError("Auction is no longer running"))
+	}
+
+	for _, event := range missed {
+		if done, err := sendUpdate(stream, event); done || err != nil {
+			return err
+		}
+	}
+	if !running {
+		return nil
+	}
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return nil
+			}
+			if done, err := sendUpdate(stream, event); done || err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// checkStreamable only streams auctions that may still take bids
+func (s *BidService) checkStreamable(ctx context.Context, auctionId string) *internal_error.InternalError {
+	auction, err := s.auctionUseCase.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	switch auction.Status {
+	case auction_usecase.AuctionStatus(auction_entity.Active),
+		auction_usecase.AuctionStatus(auction_entity.Scheduled),
+		auction_usecase.AuctionStatus(auction_entity.Paused):
+		return nil
+	default:
+		return internal_error.NewInvalidTransitionError("Auction is no longer running")
+	}
+}
+
+// sendUpdate tells whether the stream is done, either because the auction
+// closed or because the client is gone
+func sendUpdate(stream grpc.ServerStreamingServer[pb.BidUpdate], event realtime.Event) (bool, error) {
+	updateType, found := bidUpdateTypes[event.Type]
+	if !found {
+		return false, nil
+	}
+
+	update := &pb.BidUpdate{
+		Id:         event.Id,
+		Type:       updateType,
+		AuctionId:  event.AuctionId,
+		UserId:     event.UserId,
+		Amount:     event.Amount,
+		Timestamp:  toTimestamp(event.Timestamp),
+		ServerTime: timestamppb.New(time.Now()),
+	}
+	if event.EndTime != nil {
+		update.EndTime = timestamppb.New(*event.EndTime)
+	}
+
+	if err := stream.Send(update); err != nil {
+		return true, err
+	}
+
+	return event.Type == realtime.AuctionClosed, nil
+}
+
+func toBid(bid bid_usecase.BidOutputDTO) *pb.Bid {
+	return &pb.Bid{
+		Id:         bid.Id,
+		UserId:     bid.UserId,
+		AuctionId:  bid.AuctionId,
+		Amount:     bid.Amount,
+		Timestamp:  toTimestamp(bid.Timestamp),
+		ReserveMet: bid.ReserveMet,
+	}
+}
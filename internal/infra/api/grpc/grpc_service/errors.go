@@ -0,0 +1,73 @@
+package grpc_service
+
+import (
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/internal_error"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// statusError maps the use cases' errors to the status codes matching the
+// ones the HTTP API answers with. Field causes are sent as BadRequest
+// details and rate limits as RetryInfo
+func statusError(err *internal_error.InternalError) error {
+	code := codes.Internal
+	switch err.Err {
+	case "bad_request":
+		code = codes.InvalidArgument
+	case "not_found":
+		code = codes.NotFound
+	case "unauthorized":
+		code = codes.Unauthenticated
+	case "forbidden":
+		code = codes.PermissionDenied
+	case "invalid_transition":
+		code = codes.FailedPrecondition
+	case "too_many_requests":
+		code = codes.ResourceExhausted
+	}
+
+	var details []protoadapt.MessageV1
+	if len(err.Causes) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, cause := range err.Causes {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       cause.Field,
+				Description: cause.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	if err.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(err.RetryAfter)})
+	}
+
+	st := status.New(code, err.Message)
+	if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+// invalidFields rejects a request failing the checks the HTTP controllers
+// run on theirs
+func invalidFields(causes ...internal_error.Cause) error {
+	return statusError(internal_error.NewValidationError("Invalid fields", causes...))
+}
+
+// validationError rejects a request failing the binding rules of its DTO
+func validationError(err error) error {
+	restErr := validation.ValidateErr(err)
+
+	causes := make([]internal_error.Cause, 0, len(restErr.Causes))
+	for _, cause := range restErr.Causes {
+		causes = append(causes, internal_error.Cause{Field: cause.Field, Message: cause.Message})
+	}
+
+	return statusError(internal_error.NewValidationError(restErr.Message, causes...))
+}
@@ -0,0 +1,45 @@
+package grpc_service
+
+import (
+	"auction_go/internal/infra/api/grpc/pb"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/realtime"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/bid_usecase"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+//go:generate protoc --proto_path=../proto --go_out=../pb --go_opt=paths=source_relative --go-grpc_out=../pb --go-grpc_opt=paths=source_relative auction.proto
+
+const GRPC_PORT = "GRPC_PORT"
+
+const defaultPort = "50051"
+
+// Address is where the gRPC server listens, GRPC_PORT or 50051
+func Address() string {
+	port := os.Getenv(GRPC_PORT)
+	if port == "" {
+		port = defaultPort
+	}
+
+	return ":" + port
+}
+
+// NewServer serves AuctionService and BidService on the same use cases as
+// the HTTP controllers, authenticating the calls that need a user the way
+// the HTTP middleware does
+func NewServer(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface,
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	apiKeys middleware.ApiKeyAuthenticator,
+	accounts middleware.AccountFinder,
+	hub *realtime.Hub) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(authInterceptor(apiKeys, accounts)))
+
+	pb.RegisterAuctionServiceServer(server, NewAuctionService(auctionUseCase))
+	pb.RegisterBidServiceServer(server, NewBidService(bidUseCase, auctionUseCase, hub))
+
+	return server
+}
@@ -0,0 +1,399 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: auction.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AuctionService_CreateAuction_FullMethodName = "/auction.v1.AuctionService/CreateAuction"
+	AuctionService_GetAuction_FullMethodName    = "/auction.v1.AuctionService/GetAuction"
+	AuctionService_ListAuctions_FullMethodName  = "/auction.v1.AuctionService/ListAuctions"
+)
+
+// AuctionServiceClient is the client API for AuctionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AuctionService creates and lists auctions, it runs the same use cases as
+// the HTTP API. CreateAuction needs "authorization: Bearer <token>" in the
+// metadata, the other calls are public
+type AuctionServiceClient interface {
+	CreateAuction(ctx context.Context, in *CreateAuctionRequest, opts ...grpc.CallOption) (*CreateAuctionResponse, error)
+	GetAuction(ctx context.Context, in *GetAuctionRequest, opts ...grpc.CallOption) (*Auction, error)
+	ListAuctions(ctx context.Context, in *ListAuctionsRequest, opts ...grpc.CallOption) (*ListAuctionsResponse, error)
+}
+
+type auctionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuctionServiceClient(cc grpc.ClientConnInterface) AuctionServiceClient {
+	return &auctionServiceClient{cc}
+}
+
+func (c *auctionServiceClient) CreateAuction(ctx context.Context, in *CreateAuctionRequest, opts ...grpc.CallOption) (*CreateAuctionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAuctionResponse)
+	err := c.cc.Invoke(ctx, AuctionService_CreateAuction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) GetAuction(ctx context.Context, in *GetAuctionRequest, opts ...grpc.CallOption) (*Auction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Auction)
+	err := c.cc.Invoke(ctx, AuctionService_GetAuction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auctionServiceClient) ListAuctions(ctx context.Context, in *ListAuctionsRequest, opts ...grpc.CallOption) (*ListAuctionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuctionsResponse)
+	err := c.cc.Invoke(ctx, AuctionService_ListAuctions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuctionServiceServer is the server API for AuctionService service.
+// All implementations must embed UnimplementedAuctionServiceServer
+// for forward compatibility.
+//
+// AuctionService creates and lists auctions, it runs the same use cases as
+// the HTTP API. CreateAuction needs "authorization: Bearer <token>" in the
+// metadata, the other calls are public
+type AuctionServiceServer interface {
+	CreateAuction(context.Context, *CreateAuctionRequest) (*CreateAuctionResponse, error)
+	GetAuction(context.Context, *GetAuctionRequest) (*Auction, error)
+	ListAuctions(context.Context, *ListAuctionsRequest) (*ListAuctionsResponse, error)
+	mustEmbedUnimplementedAuctionServiceServer()
+}
+
+// UnimplementedAuctionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuctionServiceServer struct{}
+
+func (UnimplementedAuctionServiceServer) CreateAuction(context.Context, *CreateAuctionRequest) (*CreateAuctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAuction not implemented")
+}
+func (UnimplementedAuctionServiceServer) GetAuction(context.Context, *GetAuctionRequest) (*Auction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuction not implemented")
+}
+func (UnimplementedAuctionServiceServer) ListAuctions(context.Context, *ListAuctionsRequest) (*ListAuctionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuctions not implemented")
+}
+func (UnimplementedAuctionServiceServer) mustEmbedUnimplementedAuctionServiceServer() {}
+func (UnimplementedAuctionServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeAuctionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuctionServiceServer will
+// result in compilation errors.
+type UnsafeAuctionServiceServer interface {
+	mustEmbedUnimplementedAuctionServiceServer()
+}
+
+func RegisterAuctionServiceServer(s grpc.ServiceRegistrar, srv AuctionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAuctionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuctionService_ServiceDesc, srv)
+}
+
+func _AuctionService_CreateAuction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAuctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).CreateAuction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_CreateAuction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).CreateAuction(ctx, req.(*CreateAuctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_GetAuction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).GetAuction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_GetAuction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).GetAuction(ctx, req.(*GetAuctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuctionService_ListAuctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuctionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuctionServiceServer).ListAuctions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuctionService_ListAuctions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuctionServiceServer).ListAuctions(ctx, req.(*ListAuctionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuctionService_ServiceDesc is the grpc.ServiceDesc for AuctionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuctionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auction.v1.AuctionService",
+	HandlerType: (*AuctionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAuction",
+			Handler:    _AuctionService_CreateAuction_Handler,
+		},
+		{
+			MethodName: "GetAuction",
+			Handler:    _AuctionService_GetAuction_Handler,
+		},
+		{
+			MethodName: "ListAuctions",
+			Handler:    _AuctionService_ListAuctions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auction.proto",
+}
+
+const (
+	BidService_PlaceBid_FullMethodName   = "/auction.v1.BidService/PlaceBid"
+	BidService_ListBids_FullMethodName   = "/auction.v1.BidService/ListBids"
+	BidService_StreamBids_FullMethodName = "/auction.v1.BidService/StreamBids"
+)
+
+// BidServiceClient is the client API for BidService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BidService places and lists bids. PlaceBid needs a bearer token or an
+// "x-api-key" granted the bid scope, the other calls are public
+type BidServiceClient interface {
+	PlaceBid(ctx context.Context, in *PlaceBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	ListBids(ctx context.Context, in *ListBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error)
+	// StreamBids sends the auction's bids, price changes and extensions as
+	// they happen, and ends with its close. A client reconnecting with the
+	// id of the last update it got first receives the ones it missed
+	StreamBids(ctx context.Context, in *StreamBidsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BidUpdate], error)
+}
+
+type bidServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBidServiceClient(cc grpc.ClientConnInterface) BidServiceClient {
+	return &bidServiceClient{cc}
+}
+
+func (c *bidServiceClient) PlaceBid(ctx context.Context, in *PlaceBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_PlaceBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListBids(ctx context.Context, in *ListBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBidsResponse)
+	err := c.cc.Invoke(ctx, BidService_ListBids_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) StreamBids(ctx context.Context, in *StreamBidsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BidUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BidService_ServiceDesc.Streams[0], BidService_StreamBids_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamBidsRequest, BidUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BidService_StreamBidsClient = grpc.ServerStreamingClient[BidUpdate]
+
+// BidServiceServer is the server API for BidService service.
+// All implementations must embed UnimplementedBidServiceServer
+// for forward compatibility.
+//
+// BidService places and lists bids. PlaceBid needs a bearer token or an
+// "x-api-key" granted the bid scope, the other calls are public
+type BidServiceServer interface {
+	PlaceBid(context.Context, *PlaceBidRequest) (*Bid, error)
+	ListBids(context.Context, *ListBidsRequest) (*ListBidsResponse, error)
+	// StreamBids sends the auction's bids, price changes and extensions as
+	// they happen, and ends with its close. A client reconnecting with the
+	// id of the last update it got first receives the ones it missed
+	StreamBids(*StreamBidsRequest, grpc.ServerStreamingServer[BidUpdate]) error
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+// UnimplementedBidServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBidServiceServer struct{}
+
+func (UnimplementedBidServiceServer) PlaceBid(context.Context, *PlaceBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceBid not implemented")
+}
+func (UnimplementedBidServiceServer) ListBids(context.Context, *ListBidsRequest) (*ListBidsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBids not implemented")
+}
+func (UnimplementedBidServiceServer) StreamBids(*StreamBidsRequest, grpc.ServerStreamingServer[BidUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBids not implemented")
+}
+func (UnimplementedBidServiceServer) mustEmbedUnimplementedBidServiceServer() {}
+func (UnimplementedBidServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeBidServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BidServiceServer will
+// result in compilation errors.
+type UnsafeBidServiceServer interface {
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+func RegisterBidServiceServer(s grpc.ServiceRegistrar, srv BidServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBidServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BidService_ServiceDesc, srv)
+}
+
+func _BidService_PlaceBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).PlaceBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_PlaceBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).PlaceBid(ctx, req.(*PlaceBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ListBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ListBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ListBids(ctx, req.(*ListBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_StreamBids_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBidsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BidServiceServer).StreamBids(m, &grpc.GenericServerStream[StreamBidsRequest, BidUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BidService_StreamBidsServer = grpc.ServerStreamingServer[BidUpdate]
+
+// BidService_ServiceDesc is the grpc.ServiceDesc for BidService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BidService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auction.v1.BidService",
+	HandlerType: (*BidServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PlaceBid",
+			Handler:    _BidService_PlaceBid_Handler,
+		},
+		{
+			MethodName: "ListBids",
+			Handler:    _BidService_ListBids_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBids",
+			Handler:       _BidService_StreamBids_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "auction.proto",
+}
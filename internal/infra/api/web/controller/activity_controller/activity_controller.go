@@ -0,0 +1,80 @@
+package activity_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/usecase/activity_usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type ActivityController struct {
+	activityUseCase activity_usecase.ActivityUseCaseInterface
+}
+
+func NewActivityController(activityUseCase activity_usecase.ActivityUseCaseInterface) *ActivityController {
+	return &ActivityController{
+		activityUseCase: activityUseCase,
+	}
+}
+
+func (u *ActivityController) FindActivityByUserId(c *gin.Context) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	page, pageSize, ok := validatePagination(c)
+	if !ok {
+		return
+	}
+
+	activityData, err := u.activityUseCase.FindActivityByUserId(c.Request.Context(), userId, page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, activityData)
+}
+
+func validatePagination(c *gin.Context) (int64, int64, bool) {
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if err != nil || page < 1 {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "page",
+			Message: "page must be a positive number",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return 0, 0, false
+	}
+
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)), 10, 64)
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "page_size",
+			Message: "page_size must be between 1 and 100",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return 0, 0, false
+	}
+
+	return page, pageSize, true
+}
@@ -0,0 +1,42 @@
+package admin_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/admin_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	adminUseCase admin_usecase.AdminUseCaseInterface
+}
+
+func NewAdminController(adminUseCase admin_usecase.AdminUseCaseInterface) *AdminController {
+	return &AdminController{
+		adminUseCase: adminUseCase,
+	}
+}
+
+func (u *AdminController) StartBulkStatusJob(c *gin.Context) {
+	var bulkStatusInputDTO admin_usecase.BulkStatusInputDTO
+
+	if err := c.ShouldBindJSON(&bulkStatusInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	job, err := u.adminUseCase.StartBulkStatusJob(context.Background(), bulkStatusInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
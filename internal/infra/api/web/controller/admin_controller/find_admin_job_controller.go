@@ -0,0 +1,58 @@
+package admin_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *AdminController) FindBulkStatusJobById(c *gin.Context) {
+	jobId, ok := validateJobId(c)
+	if !ok {
+		return
+	}
+
+	jobData, err := u.adminUseCase.FindBulkStatusJobById(context.Background(), jobId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobData)
+}
+
+func (u *AdminController) FindAuditEntriesByJobId(c *gin.Context) {
+	jobId, ok := validateJobId(c)
+	if !ok {
+		return
+	}
+
+	auditEntries, err := u.adminUseCase.FindAuditEntriesByJobId(context.Background(), jobId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditEntries)
+}
+
+func validateJobId(c *gin.Context) (string, bool) {
+	jobId := c.Param("jobId")
+
+	if err := uuid.Validate(jobId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "jobId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return jobId, true
+}
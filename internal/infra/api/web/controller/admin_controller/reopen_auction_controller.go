@@ -0,0 +1,36 @@
+package admin_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/admin_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *AdminController) ReopenAuction(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	var endTimeInputDTO admin_usecase.EndTimeInputDTO
+	if err := c.ShouldBindJSON(&endTimeInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auditEntry, err := u.adminUseCase.ReopenAuction(context.Background(), auctionId, endTimeInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditEntry)
+}
@@ -0,0 +1,42 @@
+package admin_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplayAuction answers the auction as it stood at the time given by the
+// at query parameter, in RFC 3339, or as its last event left it
+func (u *AdminController) ReplayAuction(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	var at time.Time
+	if value := c.Query("at"); value != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "at",
+				Message: "at must be an RFC 3339 time",
+			})
+
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+	}
+
+	replay, err := u.adminUseCase.ReplayAuction(context.Background(), auctionId, at)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, replay)
+}
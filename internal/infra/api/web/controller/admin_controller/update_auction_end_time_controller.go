@@ -0,0 +1,69 @@
+package admin_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/admin_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *AdminController) AdjustAuctionEndTime(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	var endTimeInputDTO admin_usecase.EndTimeInputDTO
+	if err := c.ShouldBindJSON(&endTimeInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auditEntry, err := u.adminUseCase.AdjustAuctionEndTime(context.Background(), auctionId, endTimeInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditEntry)
+}
+
+func (u *AdminController) FindAuditEntriesByAuctionId(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	auditEntries, err := u.adminUseCase.FindAuditEntriesByAuctionId(context.Background(), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditEntries)
+}
+
+func validateAuctionId(c *gin.Context) (string, bool) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return auctionId, true
+}
@@ -0,0 +1,61 @@
+package admin_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/admin_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *AdminController) PauseAuction(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	var pauseInputDTO admin_usecase.PauseInputDTO
+	if err := c.ShouldBindJSON(&pauseInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auditEntry, err := u.adminUseCase.PauseAuction(context.Background(), auctionId, pauseInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditEntry)
+}
+
+func (u *AdminController) ResumeAuction(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	var resumeInputDTO admin_usecase.PauseInputDTO
+	if err := c.ShouldBindJSON(&resumeInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auditEntry, err := u.adminUseCase.ResumeAuction(context.Background(), auctionId, resumeInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, auditEntry)
+}
@@ -0,0 +1,59 @@
+package announcement_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/announcement_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AnnouncementController struct {
+	announcementUseCase announcement_usecase.AnnouncementUseCaseInterface
+}
+
+func NewAnnouncementController(
+	announcementUseCase announcement_usecase.AnnouncementUseCaseInterface) *AnnouncementController {
+	return &AnnouncementController{
+		announcementUseCase: announcementUseCase,
+	}
+}
+
+func (u *AnnouncementController) CreateAnnouncement(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var announcementInputDTO announcement_usecase.AnnouncementInputDTO
+	if err := c.ShouldBindJSON(&announcementInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	announcementInputDTO.SellerId = middleware.AuthenticatedUserId(c)
+
+	announcement, err := u.announcementUseCase.CreateAnnouncement(
+		context.Background(), auctionId, announcementInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, announcement)
+}
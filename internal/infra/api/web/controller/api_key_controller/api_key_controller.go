@@ -0,0 +1,101 @@
+package api_key_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/api_key_usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApiKeyController struct {
+	apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface
+}
+
+func NewApiKeyController(apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface) *ApiKeyController {
+	return &ApiKeyController{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (u *ApiKeyController) CreateApiKey(c *gin.Context) {
+	var apiKeyInputDTO api_key_usecase.ApiKeyInputDTO
+	if err := c.ShouldBindJSON(&apiKeyInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	apiKeyInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	apiKeyData, err := u.apiKeyUseCase.CreateApiKey(c.Request.Context(), apiKeyInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiKeyData)
+}
+
+func (u *ApiKeyController) ListApiKeys(c *gin.Context) {
+	apiKeys, err := u.apiKeyUseCase.ListApiKeys(c.Request.Context(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeys)
+}
+
+func (u *ApiKeyController) RotateApiKey(c *gin.Context) {
+	keyId, ok := validateKeyId(c)
+	if !ok {
+		return
+	}
+
+	apiKeyData, err := u.apiKeyUseCase.RotateApiKey(c.Request.Context(), middleware.AuthenticatedUserId(c), keyId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeyData)
+}
+
+func (u *ApiKeyController) DeleteApiKey(c *gin.Context) {
+	keyId, ok := validateKeyId(c)
+	if !ok {
+		return
+	}
+
+	if err := u.apiKeyUseCase.DeleteApiKey(c.Request.Context(), middleware.AuthenticatedUserId(c), keyId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func validateKeyId(c *gin.Context) (string, bool) {
+	keyId := c.Param("keyId")
+
+	if err := uuid.Validate(keyId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "keyId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return keyId, true
+}
@@ -2,6 +2,7 @@ package auction_controller
 
 import (
 	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
 	"auction_go/internal/infra/api/web/validation"
 	"auction_go/internal/usecase/auction_usecase"
 	"context"
@@ -29,6 +30,7 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		c.JSON(restErr.Code, restErr)
 		return
 	}
+	auctionInputDTO.SellerId = middleware.AuthenticatedUserId(c)
 
 	err := u.auctionUseCase.CreateAuction(context.Background(), auctionInputDTO)
 	if err != nil {
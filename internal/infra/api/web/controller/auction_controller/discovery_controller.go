@@ -0,0 +1,101 @@
+package auction_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/usecase/auction_usecase"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bounds of the windows the discovery feeds look at
+const (
+	defaultEndingWithin = time.Hour
+	maxEndingWithin     = 7 * 24 * time.Hour
+	defaultListedWithin = 24 * time.Hour
+	maxListedWithin     = 30 * 24 * time.Hour
+)
+
+// EndingSoonAuctions lists the active auctions ending within the window
+// given by within, the ones ending first at the top
+func (u *AuctionController) EndingSoonAuctions(c *gin.Context) {
+	queryInput, within, errRest := parseDiscoveryQuery(c, "within", defaultEndingWithin, maxEndingWithin)
+	if errRest != nil {
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	queryInput.EndingWithin = within
+	queryInput.Sort = "end_time"
+
+	u.findDiscoveryAuctions(c, queryInput)
+}
+
+// NewAuctions lists the active auctions created within the window given by
+// since, the newest at the top
+func (u *AuctionController) NewAuctions(c *gin.Context) {
+	queryInput, since, errRest := parseDiscoveryQuery(c, "since", defaultListedWithin, maxListedWithin)
+	if errRest != nil {
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	queryInput.ListedWithin = since
+	queryInput.Sort = "created_at"
+	queryInput.Descending = true
+
+	u.findDiscoveryAuctions(c, queryInput)
+}
+
+func (u *AuctionController) findDiscoveryAuctions(c *gin.Context, queryInput auction_usecase.AuctionQueryInputDTO) {
+	auctions, err := u.auctionUseCase.FindAuctions(c.Request.Context(), queryInput)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctions)
+}
+
+// parseDiscoveryQuery reads the window the param gives, along with the
+// category, cursor and limit the feeds page by. The feeds only list active
+// auctions
+func parseDiscoveryQuery(
+	c *gin.Context,
+	param string,
+	fallback, maxWindow time.Duration) (auction_usecase.AuctionQueryInputDTO, time.Duration, *rest_err.RestErr) {
+	active := auction_usecase.AuctionStatus(auction_entity.Active)
+	queryInput := auction_usecase.AuctionQueryInputDTO{
+		Status:   &active,
+		Category: c.Query("category"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	var causes []rest_err.Causes
+	window := fallback
+	if value := c.Query(param); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxWindow {
+			causes = append(causes, rest_err.Causes{
+				Field:   param,
+				Message: fmt.Sprintf("%s must be a duration up to %s, like 1h", param, maxWindow),
+			})
+		}
+		window = parsed
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultAuctionPageSize)), 10, 64)
+	if err != nil || limit < 1 || limit > maxAuctionPageSize {
+		causes = append(causes, rest_err.Causes{Field: "limit", Message: "limit must be between 1 and 100"})
+	}
+	queryInput.Limit = limit
+
+	if len(causes) > 0 {
+		return queryInput, 0, rest_err.NewBadRequestError("Invalid fields", causes...)
+	}
+
+	return queryInput, window, nil
+}
@@ -2,10 +2,12 @@ package auction_controller
 
 import (
 	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/versioning"
 	"auction_go/internal/usecase/auction_usecase"
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -34,27 +36,209 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 	c.JSON(http.StatusOK, auctionData)
 }
 
-func (u *AuctionController) FindAuctions(c *gin.Context) {
-	status := c.Query("status")
-	category := c.Query("category")
-	productName := c.Query("productName")
+// FindAuctionTime answers with the server clock and how long the auction
+// has left, for clients to keep their countdown from drifting
+func (u *AuctionController) FindAuctionTime(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	auctionTime, err := u.auctionUseCase.FindAuctionTime(c.Request.Context(), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	// A cached answer would be off by however long it was kept
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, auctionTime)
+}
+
+const (
+	defaultAuctionPageSize = 20
+	maxAuctionPageSize     = 100
+)
+
+// Bounds of the radius, in km, of a search around near
+const (
+	defaultSearchRadiusKm = 25
+	maxSearchRadiusKm     = 500
+)
 
-	statusNumber, errConv := strconv.Atoi(status)
-	if errConv != nil {
-		errRest := rest_err.NewBadRequestError("Error trying to validate auction status param")
+// FindAuctions lists auctions filtered by status, category, condition,
+// sellerId, minPrice, maxPrice and productName, sorted by end_time,
+// created_at or price. near=lat,lng only lists auctions located within
+// radius km of it. A page holds up to limit auctions, cursor takes the
+// X-Next-Cursor of the previous page. facets=true adds the counts per
+// category, condition and price to the first page.
+//
+// The page is an array of auctions, V2 wraps it in an object along with
+// next_cursor and facets
+func (u *AuctionController) FindAuctions(c *gin.Context) {
+	queryInput, errRest := parseAuctionQuery(c)
+	if errRest != nil {
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	auctions, err := u.auctionUseCase.FindAuctions(context.Background(),
-		auction_usecase.AuctionStatus(statusNumber), category, productName)
+	auctions, err := u.auctionUseCase.FindAuctions(c.Request.Context(), queryInput)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	c.JSON(http.StatusOK, auctions)
+	fields := make(map[string]any)
+	if auctions.NextCursor != "" {
+		c.Header("X-Next-Cursor", auctions.NextCursor)
+		fields["next_cursor"] = auctions.NextCursor
+	}
+	if auctions.Facets != nil {
+		fields["facets"] = auctions.Facets
+	}
+	versioning.SetEnvelope(c, fields)
+
+	c.JSON(http.StatusOK, auctions.Auctions)
+}
+
+// parseAuctionQuery reads the listing's query params. Auctions ending first
+// and the newest auctions come first unless order says otherwise, prices go
+// up by default
+func parseAuctionQuery(c *gin.Context) (auction_usecase.AuctionQueryInputDTO, *rest_err.RestErr) {
+	queryInput := auction_usecase.AuctionQueryInputDTO{
+		Category:    c.Query("category"),
+		ProductName: c.Query("productName"),
+		Cursor:      c.Query("cursor"),
+		Sort:        c.DefaultQuery("sort", "end_time"),
+	}
+
+	var causes []rest_err.Causes
+	if facets := c.Query("facets"); facets != "" {
+		parsed, err := strconv.ParseBool(facets)
+		if err != nil {
+			causes = append(causes, rest_err.Causes{Field: "facets", Message: "facets must be true or false"})
+		}
+		queryInput.Facets = parsed
+	}
+
+	if status := c.Query("status"); status != "" {
+		statusNumber, err := strconv.Atoi(status)
+		if err != nil {
+			causes = append(causes, rest_err.Causes{Field: "status", Message: "status must be a number"})
+		}
+		auctionStatus := auction_usecase.AuctionStatus(statusNumber)
+		queryInput.Status = &auctionStatus
+	}
+
+	if condition := c.Query("condition"); condition != "" {
+		conditionNumber, err := strconv.Atoi(condition)
+		if err != nil || conditionNumber < 1 {
+			causes = append(causes, rest_err.Causes{Field: "condition", Message: "condition must be a positive number"})
+		}
+		queryInput.Condition = auction_usecase.ProductCondition(conditionNumber)
+	}
+
+	if sellerId := c.Query("sellerId"); sellerId != "" {
+		if err := uuid.Validate(sellerId); err != nil {
+			causes = append(causes, rest_err.Causes{Field: "sellerId", Message: "Invalid UUID value"})
+		}
+		queryInput.SellerId = sellerId
+	}
+
+	prices := []struct {
+		field string
+		value *float64
+	}{
+		{"minPrice", &queryInput.MinPrice},
+		{"maxPrice", &queryInput.MaxPrice},
+	}
+	for _, price := range prices {
+		value := c.Query(price.field)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			causes = append(causes, rest_err.Causes{Field: price.field, Message: price.field + " must be a positive amount"})
+		}
+		*price.value = parsed
+	}
+
+	if near := c.Query("near"); near != "" {
+		location, ok := parseLocation(near)
+		if !ok {
+			causes = append(causes, rest_err.Causes{Field: "near", Message: "near must be a latitude,longitude pair"})
+		}
+		queryInput.Near = location
+
+		radius, err := strconv.ParseFloat(c.DefaultQuery("radius", strconv.Itoa(defaultSearchRadiusKm)), 64)
+		if err != nil || radius <= 0 || radius > maxSearchRadiusKm {
+			causes = append(causes, rest_err.Causes{Field: "radius", Message: "radius must be between 0 and 500 km"})
+		}
+		queryInput.RadiusKm = radius
+	} else if c.Query("radius") != "" {
+		causes = append(causes, rest_err.Causes{Field: "radius", Message: "radius needs near"})
+	}
+
+	switch queryInput.Sort {
+	case "end_time", "price":
+	case "created_at":
+		queryInput.Descending = true
+	default:
+		causes = append(causes, rest_err.Causes{Field: "sort", Message: "sort must be end_time, created_at or price"})
+	}
+
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		queryInput.Descending = false
+	case "desc":
+		queryInput.Descending = true
+	default:
+		causes = append(causes, rest_err.Causes{Field: "order", Message: "order must be asc or desc"})
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultAuctionPageSize)), 10, 64)
+	if err != nil || limit < 1 || limit > maxAuctionPageSize {
+		causes = append(causes, rest_err.Causes{Field: "limit", Message: "limit must be between 1 and 100"})
+	}
+	queryInput.Limit = limit
+
+	if len(causes) > 0 {
+		return queryInput, rest_err.NewBadRequestError("Invalid fields", causes...)
+	}
+
+	return queryInput, nil
+}
+
+// parseLocation reads a latitude,longitude pair in degrees
+func parseLocation(value string) (*auction_usecase.LocationDTO, bool) {
+	latitude, longitude, found := strings.Cut(value, ",")
+	if !found {
+		return nil, false
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, false
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, false
+	}
+
+	return &auction_usecase.LocationDTO{Latitude: lat, Longitude: lng}, true
 }
 
 func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
@@ -0,0 +1,65 @@
+package auction_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/usecase/auction_usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSearchQueryLength bounds the keywords a search may send
+const maxSearchQueryLength = 200
+
+// SearchAuctions finds auctions by the keywords in q, best match first. The
+// results can be narrowed by status and category and are paged with page
+// and page_size
+func (u *AuctionController) SearchAuctions(c *gin.Context) {
+	searchInput := auction_usecase.AuctionSearchInputDTO{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Category: c.Query("category"),
+	}
+
+	var causes []rest_err.Causes
+	if searchInput.Query == "" || len(searchInput.Query) > maxSearchQueryLength {
+		causes = append(causes, rest_err.Causes{Field: "q", Message: "q must have between 1 and 200 characters"})
+	}
+
+	if status := c.Query("status"); status != "" {
+		statusNumber, err := strconv.Atoi(status)
+		if err != nil {
+			causes = append(causes, rest_err.Causes{Field: "status", Message: "status must be a number"})
+		}
+		auctionStatus := auction_usecase.AuctionStatus(statusNumber)
+		searchInput.Status = &auctionStatus
+	}
+
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if err != nil || page < 1 {
+		causes = append(causes, rest_err.Causes{Field: "page", Message: "page must be a positive number"})
+	}
+	searchInput.Page = page
+
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", strconv.Itoa(defaultAuctionPageSize)), 10, 64)
+	if err != nil || pageSize < 1 || pageSize > maxAuctionPageSize {
+		causes = append(causes, rest_err.Causes{Field: "page_size", Message: "page_size must be between 1 and 100"})
+	}
+	searchInput.PageSize = pageSize
+
+	if len(causes) > 0 {
+		errRest := rest_err.NewBadRequestError("Invalid fields", causes...)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	results, searchErr := u.auctionUseCase.SearchAuctions(c.Request.Context(), searchInput)
+	if searchErr != nil {
+		errRest := rest_err.ConvertError(searchErr)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
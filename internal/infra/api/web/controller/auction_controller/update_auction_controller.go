@@ -0,0 +1,73 @@
+package auction_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/auction_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *AuctionController) CancelAuction(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var cancelInputDTO auction_usecase.CancelAuctionInputDTO
+	if err := c.ShouldBindJSON(&cancelInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	cancelInputDTO.SellerId = middleware.AuthenticatedUserId(c)
+
+	auctionData, err := u.auctionUseCase.CancelAuction(context.Background(), auctionId, cancelInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctionData)
+}
+
+func (u *AuctionController) BuyNow(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	buyNowInputDTO := auction_usecase.BuyNowInputDTO{
+		UserId: middleware.AuthenticatedUserId(c),
+	}
+
+	auctionData, err := u.auctionUseCase.BuyNow(context.Background(), auctionId, buyNowInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctionData)
+}
@@ -2,6 +2,7 @@ package bid_controller
 
 import (
 	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
 	"auction_go/internal/infra/api/web/validation"
 	"auction_go/internal/usecase/bid_usecase"
 	"context"
@@ -30,13 +31,17 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	bidInputDTO.UserId = middleware.AuthenticatedUserId(c)
+	bidInputDTO.IdempotencyKey = c.GetHeader("Idempotency-Key")
+
+	bidOutputDTO, err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
+		restErr.SetRetryAfter(c.Writer.Header())
 		c.JSON(restErr.Code, restErr)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, bidOutputDTO)
 }
@@ -0,0 +1,34 @@
+package bid_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/bid_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *BidController) CreateProxyBid(c *gin.Context) {
+	var proxyBidInputDTO bid_usecase.ProxyBidInputDTO
+
+	if err := c.ShouldBindJSON(&proxyBidInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	proxyBidInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	err := u.bidUseCase.CreateProxyBid(context.Background(), proxyBidInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusCreated)
+}
@@ -2,8 +2,10 @@ package bid_controller
 
 import (
 	"auction_go/configuration/rest_err"
+	"auction_go/internal/entity/bid_entity"
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -31,3 +33,77 @@ func (u *BidController) FindBidByAuctionId(c *gin.Context) {
 
 	c.JSON(http.StatusOK, bidOutputList)
 }
+
+const (
+	defaultBidPageSize = 20
+	maxBidPageSize     = 100
+)
+
+func (u *BidController) FindBidHistory(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	sort := bid_entity.BidSort(c.DefaultQuery("sort", string(bid_entity.SortByTime)))
+	if sort != bid_entity.SortByAmount && sort != bid_entity.SortByTime {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "sort",
+			Message: "sort must be amount or time",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultBidPageSize)), 10, 64)
+	if err != nil || limit < 1 || limit > maxBidPageSize {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "limit",
+			Message: "limit must be between 1 and 100",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	bidPage, errFind := u.bidUseCase.FindBidsByAuctionId(
+		context.Background(), auctionId, c.Query("cursor"), limit, sort)
+	if errFind != nil {
+		errRest := rest_err.ConvertError(errFind)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, bidPage)
+}
+
+func (u *BidController) FindActiveBidsByUser(c *gin.Context) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	activeBids, err := u.bidUseCase.FindActiveBidsByUser(context.Background(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, activeBids)
+}
@@ -0,0 +1,55 @@
+package category_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/category_usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController struct {
+	categoryUseCase category_usecase.CategoryUseCaseInterface
+}
+
+func NewCategoryController(categoryUseCase category_usecase.CategoryUseCaseInterface) *CategoryController {
+	return &CategoryController{
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+func (u *CategoryController) CreateCategory(c *gin.Context) {
+	var categoryInputDTO category_usecase.CategoryInputDTO
+
+	if err := c.ShouldBindJSON(&categoryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	category, err := u.categoryUseCase.CreateCategory(c.Request.Context(), categoryInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// FindCategories returns the taxonomy as a tree, each category with how
+// many active auctions are filed under it
+func (u *CategoryController) FindCategories(c *gin.Context) {
+	categories, err := u.categoryUseCase.FindCategories(c.Request.Context())
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
@@ -0,0 +1,45 @@
+package checkout_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/checkout_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckoutController struct {
+	checkoutUseCase checkout_usecase.CheckoutUseCaseInterface
+}
+
+func NewCheckoutController(checkoutUseCase checkout_usecase.CheckoutUseCaseInterface) *CheckoutController {
+	return &CheckoutController{
+		checkoutUseCase: checkoutUseCase,
+	}
+}
+
+func (u *CheckoutController) CreateCheckout(c *gin.Context) {
+	var checkoutInputDTO checkout_usecase.CheckoutInputDTO
+
+	if err := c.ShouldBindJSON(&checkoutInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	checkoutInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	checkout, err := u.checkoutUseCase.CreateCheckout(context.Background(), checkoutInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, checkout)
+}
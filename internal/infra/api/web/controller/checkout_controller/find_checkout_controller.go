@@ -0,0 +1,63 @@
+package checkout_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *CheckoutController) FindCheckoutById(c *gin.Context) {
+	checkoutId := c.Param("checkoutId")
+
+	if err := uuid.Validate(checkoutId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "checkoutId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.FindCheckoutById(
+		context.Background(), middleware.AuthenticatedUserId(c), checkoutId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
+
+func (u *CheckoutController) FindCheckoutByAuctionId(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.FindCheckoutByAuctionId(
+		context.Background(), middleware.AuthenticatedUserId(c), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
+
+func (u *CheckoutController) FindShippingOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, u.checkoutUseCase.FindShippingOptions())
+}
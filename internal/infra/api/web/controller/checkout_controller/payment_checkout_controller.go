@@ -0,0 +1,70 @@
+package checkout_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/checkout_usecase"
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *CheckoutController) SelectPayment(c *gin.Context) {
+	checkoutId, ok := validateCheckoutId(c)
+	if !ok {
+		return
+	}
+
+	var paymentInputDTO checkout_usecase.PaymentInputDTO
+	if err := c.ShouldBindJSON(&paymentInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.SelectPayment(
+		context.Background(), middleware.AuthenticatedUserId(c), checkoutId, paymentInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
+
+// ConfirmPixPayments is the webhook called by the PIX provider, it is
+// rejected unless the request carries the shared PIX_WEBHOOK_SECRET
+func (u *CheckoutController) ConfirmPixPayments(c *gin.Context) {
+	secret := os.Getenv("PIX_WEBHOOK_SECRET")
+	providedSecret := c.GetHeader("X-Pix-Secret")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(providedSecret)) != 1 {
+		errRest := rest_err.NewUnauthorizedError("Invalid payment provider credentials")
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var pixWebhookInputDTO checkout_usecase.PixWebhookInputDTO
+	if err := c.ShouldBindJSON(&pixWebhookInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	if err := u.checkoutUseCase.ConfirmPixPayments(context.Background(), pixWebhookInputDTO); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
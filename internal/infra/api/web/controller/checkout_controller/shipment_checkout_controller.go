@@ -0,0 +1,71 @@
+package checkout_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/checkout_usecase"
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *CheckoutController) AttachShipment(c *gin.Context) {
+	checkoutId, ok := validateCheckoutId(c)
+	if !ok {
+		return
+	}
+
+	var shipmentInputDTO checkout_usecase.ShipmentInputDTO
+	if err := c.ShouldBindJSON(&shipmentInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.AttachShipment(
+		context.Background(), middleware.AuthenticatedUserId(c), checkoutId, shipmentInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
+
+// ApplyCarrierUpdate is the webhook called by carriers, it is rejected
+// unless the request carries the shared CARRIER_WEBHOOK_SECRET
+func (u *CheckoutController) ApplyCarrierUpdate(c *gin.Context) {
+	secret := os.Getenv("CARRIER_WEBHOOK_SECRET")
+	providedSecret := c.GetHeader("X-Carrier-Secret")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(providedSecret)) != 1 {
+		errRest := rest_err.NewUnauthorizedError("Invalid carrier credentials")
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var carrierUpdateInputDTO checkout_usecase.CarrierUpdateInputDTO
+	if err := c.ShouldBindJSON(&carrierUpdateInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.ApplyCarrierUpdate(context.Background(), carrierUpdateInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
@@ -0,0 +1,81 @@
+package checkout_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/checkout_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *CheckoutController) ConfirmAddress(c *gin.Context) {
+	checkoutId, ok := validateCheckoutId(c)
+	if !ok {
+		return
+	}
+
+	var addressInputDTO checkout_usecase.AddressInputDTO
+	if err := c.ShouldBindJSON(&addressInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.ConfirmAddress(
+		context.Background(), middleware.AuthenticatedUserId(c), checkoutId, addressInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
+
+func (u *CheckoutController) SelectShipping(c *gin.Context) {
+	checkoutId, ok := validateCheckoutId(c)
+	if !ok {
+		return
+	}
+
+	var shippingInputDTO checkout_usecase.ShippingInputDTO
+	if err := c.ShouldBindJSON(&shippingInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	checkoutData, err := u.checkoutUseCase.SelectShipping(
+		context.Background(), middleware.AuthenticatedUserId(c), checkoutId, shippingInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkoutData)
+}
+
+func validateCheckoutId(c *gin.Context) (string, bool) {
+	checkoutId := c.Param("checkoutId")
+
+	if err := uuid.Validate(checkoutId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "checkoutId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return checkoutId, true
+}
@@ -0,0 +1,80 @@
+package device_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/device_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceController struct {
+	deviceUseCase device_usecase.DeviceUseCaseInterface
+}
+
+func NewDeviceController(deviceUseCase device_usecase.DeviceUseCaseInterface) *DeviceController {
+	return &DeviceController{
+		deviceUseCase: deviceUseCase,
+	}
+}
+
+func (u *DeviceController) RegisterDevice(c *gin.Context) {
+	var deviceInputDTO device_usecase.DeviceInputDTO
+	if err := c.ShouldBindJSON(&deviceInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	deviceInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	device, err := u.deviceUseCase.RegisterDevice(context.Background(), deviceInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+func (u *DeviceController) DeleteDevice(c *gin.Context) {
+	deviceId := c.Param("deviceId")
+
+	if err := uuid.Validate(deviceId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "deviceId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	err := u.deviceUseCase.DeleteDevice(
+		context.Background(), middleware.AuthenticatedUserId(c), deviceId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *DeviceController) ListDevices(c *gin.Context) {
+	devices, err := u.deviceUseCase.ListDevices(
+		context.Background(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
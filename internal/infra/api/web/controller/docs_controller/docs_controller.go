@@ -0,0 +1,46 @@
+package docs_controller
+
+import (
+	"auction_go/internal/infra/api/web/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocsController struct {
+	spec *openapi.Spec
+}
+
+func NewDocsController(spec *openapi.Spec) *DocsController {
+	return &DocsController{
+		spec: spec,
+	}
+}
+
+// OpenAPISpec serves the API's OpenAPI 3 document
+func (u *DocsController) OpenAPISpec(c *gin.Context) {
+	c.JSON(http.StatusOK, u.spec)
+}
+
+// SwaggerUI serves a Swagger UI page browsing OpenAPISpec, its assets are
+// loaded from a CDN
+func (u *DocsController) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Auction API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/swagger/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,117 @@
+package feedback_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/feedback_usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type FeedbackController struct {
+	feedbackUseCase feedback_usecase.FeedbackUseCaseInterface
+}
+
+func NewFeedbackController(feedbackUseCase feedback_usecase.FeedbackUseCaseInterface) *FeedbackController {
+	return &FeedbackController{
+		feedbackUseCase: feedbackUseCase,
+	}
+}
+
+func (u *FeedbackController) SubmitFeedback(c *gin.Context) {
+	auctionId, ok := validateId(c, "auctionId")
+	if !ok {
+		return
+	}
+
+	var feedbackInputDTO feedback_usecase.FeedbackInputDTO
+	if err := c.ShouldBindJSON(&feedbackInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	feedbackInputDTO.BuyerId = middleware.AuthenticatedUserId(c)
+
+	feedbackData, err := u.feedbackUseCase.SubmitFeedback(c.Request.Context(), auctionId, feedbackInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, feedbackData)
+}
+
+func (u *FeedbackController) FindFeedbackBySellerId(c *gin.Context) {
+	sellerId, ok := validateId(c, "sellerId")
+	if !ok {
+		return
+	}
+
+	page, pageSize, ok := validatePagination(c)
+	if !ok {
+		return
+	}
+
+	feedbackData, err := u.feedbackUseCase.FindFeedbackBySellerId(c.Request.Context(), sellerId, page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, feedbackData)
+}
+
+func validateId(c *gin.Context, param string) (string, bool) {
+	id := c.Param(param)
+
+	if err := uuid.Validate(id); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   param,
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return id, true
+}
+
+func validatePagination(c *gin.Context) (int64, int64, bool) {
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if err != nil || page < 1 {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "page",
+			Message: "page must be a positive number",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return 0, 0, false
+	}
+
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)), 10, 64)
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "page_size",
+			Message: "page_size must be between 1 and 100",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return 0, 0, false
+	}
+
+	return page, pageSize, true
+}
@@ -0,0 +1,48 @@
+package graphql_controller
+
+import (
+	"auction_go/internal/infra/api/graphql/graphql_resolver"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type GraphQLController struct {
+	resolver *graphql_resolver.Resolver
+	schema   *graphql.Schema
+}
+
+func NewGraphQLController(resolver *graphql_resolver.Resolver) *GraphQLController {
+	return &GraphQLController{
+		resolver: resolver,
+		schema:   resolver.Schema(),
+	}
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Execute runs a GraphQL query or mutation. Errors raised while resolving
+// are reported in the response's errors, with a 200 like GraphQL servers
+// do
+func (u *GraphQLController) Execute(c *gin.Context) {
+	var request graphQLRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	ctx := u.resolver.NewRequestContext(c.Request.Context(), middleware.AuthenticatedUserId(c))
+	response := u.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+
+	c.JSON(http.StatusOK, response)
+}
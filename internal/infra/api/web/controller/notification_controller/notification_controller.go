@@ -0,0 +1,56 @@
+package notification_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/notification_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	notificationUseCase notification_usecase.NotificationUseCaseInterface
+}
+
+func NewNotificationController(
+	notificationUseCase notification_usecase.NotificationUseCaseInterface) *NotificationController {
+	return &NotificationController{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+func (u *NotificationController) FindPreferences(c *gin.Context) {
+	preferences, err := u.notificationUseCase.FindPreferences(
+		context.Background(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+func (u *NotificationController) UpdatePreferences(c *gin.Context) {
+	var preferencesInputDTO notification_usecase.PreferencesInputDTO
+	if err := c.ShouldBindJSON(&preferencesInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	preferencesInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	preferences, err := u.notificationUseCase.UpdatePreferences(context.Background(), preferencesInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
@@ -0,0 +1,58 @@
+package receipt_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/usecase/receipt_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReceiptController struct {
+	receiptUseCase receipt_usecase.ReceiptUseCaseInterface
+}
+
+func NewReceiptController(receiptUseCase receipt_usecase.ReceiptUseCaseInterface) *ReceiptController {
+	return &ReceiptController{
+		receiptUseCase: receiptUseCase,
+	}
+}
+
+func (u *ReceiptController) ListReceipts(c *gin.Context) {
+	receipts, err := u.receiptUseCase.ListReceipts(
+		context.Background(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, receipts)
+}
+
+func (u *ReceiptController) FindReceiptById(c *gin.Context) {
+	receiptId := c.Param("receiptId")
+
+	if err := uuid.Validate(receiptId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "receiptId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	receipt, err := u.receiptUseCase.FindReceiptById(
+		context.Background(), middleware.AuthenticatedUserId(c), receiptId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
@@ -0,0 +1,62 @@
+package recommendation_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/usecase/recommendation_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecommendationController struct {
+	recommendationUseCase recommendation_usecase.RecommendationUseCaseInterface
+}
+
+func NewRecommendationController(
+	recommendationUseCase recommendation_usecase.RecommendationUseCaseInterface) *RecommendationController {
+	return &RecommendationController{
+		recommendationUseCase: recommendationUseCase,
+	}
+}
+
+// FindOwnRecommendations lists the auctions recommended to the
+// authenticated user, nobody else's can be read
+func (u *RecommendationController) FindOwnRecommendations(c *gin.Context) {
+	recommendationData, err := u.recommendationUseCase.FindRecommendationsByUserId(
+		context.Background(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendationData)
+}
+
+// FindSimilarAuctions lists active auctions in the same category sharing
+// the auction's keywords or priced like it, best match first
+func (u *RecommendationController) FindSimilarAuctions(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	similarAuctions, err := u.recommendationUseCase.FindSimilarAuctions(c.Request.Context(), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, similarAuctions)
+}
@@ -0,0 +1,45 @@
+package return_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/return_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReturnController struct {
+	returnUseCase return_usecase.ReturnUseCaseInterface
+}
+
+func NewReturnController(returnUseCase return_usecase.ReturnUseCaseInterface) *ReturnController {
+	return &ReturnController{
+		returnUseCase: returnUseCase,
+	}
+}
+
+func (u *ReturnController) CreateReturnRequest(c *gin.Context) {
+	var returnRequestInputDTO return_usecase.ReturnRequestInputDTO
+
+	if err := c.ShouldBindJSON(&returnRequestInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	returnRequestInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	returnRequest, err := u.returnUseCase.CreateReturnRequest(context.Background(), returnRequestInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, returnRequest)
+}
@@ -0,0 +1,52 @@
+package return_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *ReturnController) FindReturnRequestById(c *gin.Context) {
+	returnId, ok := validateReturnId(c)
+	if !ok {
+		return
+	}
+
+	returnRequestData, err := u.returnUseCase.FindReturnRequestById(
+		context.Background(), middleware.AuthenticatedUserId(c), returnId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, returnRequestData)
+}
+
+func (u *ReturnController) FindReturnRequestByCheckoutId(c *gin.Context) {
+	checkoutId := c.Param("checkoutId")
+
+	if err := uuid.Validate(checkoutId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "checkoutId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	returnRequestData, err := u.returnUseCase.FindReturnRequestByCheckoutId(
+		context.Background(), middleware.AuthenticatedUserId(c), checkoutId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, returnRequestData)
+}
@@ -0,0 +1,83 @@
+package return_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/return_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *ReturnController) AcceptReturnRequest(c *gin.Context) {
+	returnId, ok := validateReturnId(c)
+	if !ok {
+		return
+	}
+
+	var decisionInputDTO return_usecase.ReturnDecisionInputDTO
+	if err := c.ShouldBindJSON(&decisionInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	decisionInputDTO.SellerId = middleware.AuthenticatedUserId(c)
+
+	returnRequestData, err := u.returnUseCase.AcceptReturnRequest(context.Background(), returnId, decisionInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, returnRequestData)
+}
+
+func (u *ReturnController) DeclineReturnRequest(c *gin.Context) {
+	returnId, ok := validateReturnId(c)
+	if !ok {
+		return
+	}
+
+	var decisionInputDTO return_usecase.ReturnDecisionInputDTO
+	if err := c.ShouldBindJSON(&decisionInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	decisionInputDTO.SellerId = middleware.AuthenticatedUserId(c)
+
+	returnRequestData, err := u.returnUseCase.DeclineReturnRequest(context.Background(), returnId, decisionInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, returnRequestData)
+}
+
+func validateReturnId(c *gin.Context) (string, bool) {
+	returnId := c.Param("returnId")
+
+	if err := uuid.Validate(returnId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "returnId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return returnId, true
+}
@@ -0,0 +1,80 @@
+package saved_search_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/saved_search_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SavedSearchController struct {
+	savedSearchUseCase saved_search_usecase.SavedSearchUseCaseInterface
+}
+
+func NewSavedSearchController(savedSearchUseCase saved_search_usecase.SavedSearchUseCaseInterface) *SavedSearchController {
+	return &SavedSearchController{
+		savedSearchUseCase: savedSearchUseCase,
+	}
+}
+
+func (u *SavedSearchController) CreateSavedSearch(c *gin.Context) {
+	var savedSearchInputDTO saved_search_usecase.SavedSearchInputDTO
+	if err := c.ShouldBindJSON(&savedSearchInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	savedSearchInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	savedSearch, err := u.savedSearchUseCase.CreateSavedSearch(context.Background(), savedSearchInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, savedSearch)
+}
+
+func (u *SavedSearchController) DeleteSavedSearch(c *gin.Context) {
+	savedSearchId := c.Param("savedSearchId")
+
+	if err := uuid.Validate(savedSearchId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "savedSearchId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	err := u.savedSearchUseCase.DeleteSavedSearch(
+		context.Background(), middleware.AuthenticatedUserId(c), savedSearchId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *SavedSearchController) ListSavedSearches(c *gin.Context) {
+	savedSearches, err := u.savedSearchUseCase.ListSavedSearches(
+		context.Background(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, savedSearches)
+}
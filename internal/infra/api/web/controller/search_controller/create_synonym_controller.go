@@ -0,0 +1,42 @@
+package search_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/search_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController struct {
+	searchUseCase search_usecase.SearchUseCaseInterface
+}
+
+func NewSearchController(searchUseCase search_usecase.SearchUseCaseInterface) *SearchController {
+	return &SearchController{
+		searchUseCase: searchUseCase,
+	}
+}
+
+func (u *SearchController) CreateSynonymGroup(c *gin.Context) {
+	var synonymGroupInputDTO search_usecase.SynonymGroupInputDTO
+
+	if err := c.ShouldBindJSON(&synonymGroupInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	synonymGroup, err := u.searchUseCase.CreateSynonymGroup(context.Background(), synonymGroupInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, synonymGroup)
+}
@@ -0,0 +1,32 @@
+package search_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *SearchController) DeleteSynonymGroup(c *gin.Context) {
+	synonymGroupId := c.Param("synonymGroupId")
+
+	if err := uuid.Validate(synonymGroupId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "synonymGroupId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if err := u.searchUseCase.DeleteSynonymGroup(context.Background(), synonymGroupId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,20 @@
+package search_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *SearchController) FindSynonymGroups(c *gin.Context) {
+	synonymGroups, err := u.searchUseCase.FindSynonymGroups(context.Background())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, synonymGroups)
+}
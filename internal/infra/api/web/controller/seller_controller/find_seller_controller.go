@@ -0,0 +1,98 @@
+package seller_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/usecase/seller_usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type SellerController struct {
+	sellerUseCase seller_usecase.SellerUseCaseInterface
+}
+
+func NewSellerController(sellerUseCase seller_usecase.SellerUseCaseInterface) *SellerController {
+	return &SellerController{
+		sellerUseCase: sellerUseCase,
+	}
+}
+
+func (u *SellerController) FindSellerById(c *gin.Context) {
+	sellerId := c.Param("sellerId")
+
+	if err := uuid.Validate(sellerId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "sellerId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	page, pageSize, ok := validatePagination(c)
+	if !ok {
+		return
+	}
+
+	sellerData, err := u.sellerUseCase.FindSellerById(c.Request.Context(), sellerId, page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, sellerData)
+}
+
+func (u *SellerController) FindSellerBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	page, pageSize, ok := validatePagination(c)
+	if !ok {
+		return
+	}
+
+	sellerData, err := u.sellerUseCase.FindSellerBySlug(c.Request.Context(), slug, page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, sellerData)
+}
+
+func validatePagination(c *gin.Context) (int64, int64, bool) {
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if err != nil || page < 1 {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "page",
+			Message: "page must be a positive number",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return 0, 0, false
+	}
+
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)), 10, 64)
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "page_size",
+			Message: "page_size must be between 1 and 100",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return 0, 0, false
+	}
+
+	return page, pageSize, true
+}
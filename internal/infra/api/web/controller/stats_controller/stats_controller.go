@@ -0,0 +1,43 @@
+package stats_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/usecase/stats_usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StatsController struct {
+	statsUseCase stats_usecase.StatsUseCaseInterface
+}
+
+func NewStatsController(statsUseCase stats_usecase.StatsUseCaseInterface) *StatsController {
+	return &StatsController{
+		statsUseCase: statsUseCase,
+	}
+}
+
+func (u *StatsController) FindAuctionStats(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	stats, err := u.statsUseCase.FindAuctionStats(c.Request.Context(), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
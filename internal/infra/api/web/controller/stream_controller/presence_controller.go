@@ -0,0 +1,34 @@
+package stream_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FindAuctionPresence answers with how many clients follow the auction's
+// live stream, over WebSockets and Server-Sent Events alike. Only the
+// clients connected to this replica are counted
+func (u *StreamController) FindAuctionPresence(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if _, err := u.auctionUseCase.FindAuctionById(c.Request.Context(), auctionId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, u.hub.Presence(auctionId))
+}
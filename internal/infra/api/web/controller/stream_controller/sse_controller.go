@@ -0,0 +1,157 @@
+package stream_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/realtime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// keepAliveInterval sends a comment on quiet streams, so proxies don't
+// time out the connection
+const keepAliveInterval = 15 * time.Second
+
+// StreamAuctionEvents streams the auction's bids, price changes, extensions
+// and close as Server-Sent Events, for clients behind proxies that block
+// WebSockets. A client reconnecting with Last-Event-ID first gets the
+// events it missed, and once the auction ended and there is nothing left
+// to catch up on it is answered 204 so it stops reconnecting
+func (u *StreamController) StreamAuctionEvents(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	lastEventId, resuming, errRest := parseLastEventId(c)
+	if errRest != nil {
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	running := true
+	if errRest := u.checkStreamable(c, auctionId); errRest != nil {
+		// An auction that ended while the client was away still owes it
+		// the events it missed
+		if !resuming || errRest.Code != http.StatusConflict {
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+		running = false
+	}
+
+	var subscription *realtime.Subscription
+	var missed []realtime.Event
+	if resuming {
+		subscription, missed = u.hub.Resume(auctionId, lastEventId)
+	} else {
+		subscription = u.hub.Subscribe(auctionId)
+	}
+	defer subscription.Close()
+
+	if !running && len(missed) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	for _, event := range missed {
+		if !writeEvent(c, event) || event.Type == realtime.AuctionClosed {
+			return
+		}
+	}
+	if running {
+		streamEvents(c, subscription)
+	}
+}
+
+// parseLastEventId reads the Last-Event-ID header EventSource clients send
+// when they reconnect
+func parseLastEventId(c *gin.Context) (int64, bool, *rest_err.RestErr) {
+	header := c.GetHeader("Last-Event-ID")
+	if header == "" {
+		return 0, false, nil
+	}
+
+	lastEventId, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || lastEventId < 0 {
+		return 0, false, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "Last-Event-ID",
+			Message: "Last-Event-ID must be an event id",
+		})
+	}
+
+	return lastEventId, true, nil
+}
+
+func streamEvents(c *gin.Context, subscription *realtime.Subscription) {
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok || !writeEvent(c, event) || event.Type == realtime.AuctionClosed {
+				return
+			}
+		case <-keepAlive.C:
+			if !writeKeepAlive(c) {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEvent tells whether the client is still there
+func writeEvent(c *gin.Context, event realtime.Event) bool {
+	event.ServerTime = time.Now()
+	http.NewResponseController(c.Writer).SetWriteDeadline(event.ServerTime.Add(writeTimeout))
+
+	// Events without an id, like viewer counts, leave the client's
+	// Last-Event-ID where it was
+	var id string
+	if event.Id > 0 {
+		id = strconv.FormatInt(event.Id, 10)
+	}
+
+	err := sse.Encode(c.Writer, sse.Event{
+		Id:    id,
+		Event: string(event.Type),
+		Data:  event,
+	})
+	if err != nil {
+		return false
+	}
+
+	c.Writer.Flush()
+	return true
+}
+
+func writeKeepAlive(c *gin.Context) bool {
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	if _, err := c.Writer.WriteString(":\n\n"); err != nil {
+		return false
+	}
+
+	c.Writer.Flush()
+	return true
+}
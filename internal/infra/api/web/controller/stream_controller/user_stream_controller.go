@@ -0,0 +1,43 @@
+package stream_controller
+
+import (
+	"auction_go/internal/infra/api/web/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// StreamMyWebSocket upgrades to a WebSocket that receives the events meant
+// for the authenticated user, like being outbid, as JSON messages
+func (u *StreamController) StreamMyWebSocket(c *gin.Context) {
+	userId := middleware.AuthenticatedUserId(c)
+
+	// The user was authenticated by their token, which other origins
+	// can't read, so any origin may open it
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		conn.MaxPayloadBytes = maxClientMessage
+
+		subscription := u.hub.SubscribeUser(userId)
+		defer subscription.Close()
+
+		streamWebSocket(conn, subscription)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// StreamMyEvents streams the events meant for the authenticated user, like
+// being outbid, as Server-Sent Events. They aren't replayed, a client that
+// was away gets them from the user's other channels
+func (u *StreamController) StreamMyEvents(c *gin.Context) {
+	subscription := u.hub.SubscribeUser(middleware.AuthenticatedUserId(c))
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	streamEvents(c, subscription)
+}
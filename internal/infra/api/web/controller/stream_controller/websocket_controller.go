@@ -0,0 +1,115 @@
+package stream_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/infra/realtime"
+	"auction_go/internal/usecase/auction_usecase"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// writeTimeout drops clients that stop reading
+const writeTimeout = 10 * time.Second
+
+// maxClientMessage bounds what clients may send, the stream is one way
+const maxClientMessage = 512
+
+type StreamController struct {
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+	hub            *realtime.Hub
+}
+
+func NewStreamController(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface, hub *realtime.Hub) *StreamController {
+	return &StreamController{
+		auctionUseCase: auctionUseCase,
+		hub:            hub,
+	}
+}
+
+// StreamAuctionWebSocket upgrades to a WebSocket that receives the
+// auction's bids, price changes and close as JSON messages. The server
+// closes the connection after the close event
+func (u *StreamController) StreamAuctionWebSocket(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if errRest := u.checkStreamable(c, auctionId); errRest != nil {
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	// The stream only carries public data, so any origin may open it
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		conn.MaxPayloadBytes = maxClientMessage
+
+		subscription := u.hub.Subscribe(auctionId)
+		defer subscription.Close()
+
+		streamWebSocket(conn, subscription)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkStreamable only streams auctions that may still take bids
+func (u *StreamController) checkStreamable(c *gin.Context, auctionId string) *rest_err.RestErr {
+	auction, err := u.auctionUseCase.FindAuctionById(c.Request.Context(), auctionId)
+	if err != nil {
+		return rest_err.ConvertError(err)
+	}
+
+	switch auction.Status {
+	case auction_usecase.AuctionStatus(auction_entity.Active),
+		auction_usecase.AuctionStatus(auction_entity.Scheduled),
+		auction_usecase.AuctionStatus(auction_entity.Paused):
+		return nil
+	default:
+		return rest_err.NewConflictError("Auction is no longer running")
+	}
+}
+
+func streamWebSocket(conn *websocket.Conn, subscription *realtime.Subscription) {
+	// Clients aren't expected to send anything, reading notices them leave
+	left := make(chan struct{})
+	go func() {
+		defer close(left)
+
+		var message []byte
+		for websocket.Message.Receive(conn, &message) == nil {
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+
+			event.ServerTime = time.Now()
+			conn.SetWriteDeadline(event.ServerTime.Add(writeTimeout))
+			if err := websocket.JSON.Send(conn, event); err != nil {
+				return
+			}
+
+			if event.Type == realtime.AuctionClosed {
+				return
+			}
+		case <-left:
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package user_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/user_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *UserController) CreateUser(c *gin.Context) {
+	var userInputDTO user_usecase.UserInputDTO
+	if err := c.ShouldBindJSON(&userInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.CreateUser(context.Background(), userInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, userData)
+}
+
+func (u *UserController) UpdateUser(c *gin.Context) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	if !ownsProfile(c, userId) {
+		return
+	}
+
+	var updateUserInputDTO user_usecase.UpdateUserInputDTO
+	if err := c.ShouldBindJSON(&updateUserInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.UpdateUser(context.Background(), userId, updateUserInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, userData)
+}
+
+// DeleteAccount anonymizes the user, see the use case
+func (u *UserController) DeleteAccount(c *gin.Context) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	if !ownsProfile(c, userId) {
+		return
+	}
+
+	if err := u.userUseCase.DeleteAccount(context.Background(), userId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ownsProfile turns away users changing someone else's profile
+func ownsProfile(c *gin.Context, userId string) bool {
+	if middleware.AuthenticatedUserId(c) != userId {
+		errRest := rest_err.NewForbiddenError("Users may only change their own profile")
+
+		c.JSON(errRest.Code, errRest)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,31 @@
+package user_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/user_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *UserController) Login(c *gin.Context) {
+	var loginInputDTO user_usecase.LoginInputDTO
+	if err := c.ShouldBindJSON(&loginInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	loginData, err := u.userUseCase.Login(context.Background(), loginInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, loginData)
+}
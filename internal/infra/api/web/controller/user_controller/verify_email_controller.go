@@ -0,0 +1,31 @@
+package user_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/user_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (u *UserController) VerifyEmail(c *gin.Context) {
+	var verifyEmailInputDTO user_usecase.VerifyEmailInputDTO
+	if err := c.ShouldBindJSON(&verifyEmailInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.VerifyEmail(context.Background(), verifyEmailInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, userData)
+}
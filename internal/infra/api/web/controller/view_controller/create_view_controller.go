@@ -0,0 +1,40 @@
+package view_controller
+
+import (
+	"auction_go/internal/usecase/view_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ViewController struct {
+	viewUseCase view_usecase.ViewUseCaseInterface
+}
+
+func NewViewController(viewUseCase view_usecase.ViewUseCaseInterface) *ViewController {
+	return &ViewController{
+		viewUseCase: viewUseCase,
+	}
+}
+
+// RegisterView runs ahead of the auction detail handler and counts the view
+// once the detail was served successfully
+func (u *ViewController) RegisterView(c *gin.Context) {
+	c.Next()
+
+	if c.Writer.Status() != http.StatusOK {
+		return
+	}
+
+	visitorId := c.GetHeader("X-Visitor-Id")
+	if visitorId == "" {
+		visitorId = c.ClientIP() + "|" + c.Request.UserAgent()
+	}
+
+	u.viewUseCase.CreateView(context.Background(), view_usecase.ViewInputDTO{
+		AuctionId: c.Param("auctionId"),
+		VisitorId: visitorId,
+		UserAgent: c.Request.UserAgent(),
+	})
+}
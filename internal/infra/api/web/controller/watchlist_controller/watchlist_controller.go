@@ -0,0 +1,78 @@
+package watchlist_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/middleware"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/watchlist_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WatchlistController struct {
+	watchlistUseCase watchlist_usecase.WatchlistUseCaseInterface
+}
+
+func NewWatchlistController(watchlistUseCase watchlist_usecase.WatchlistUseCaseInterface) *WatchlistController {
+	return &WatchlistController{
+		watchlistUseCase: watchlistUseCase,
+	}
+}
+
+func (u *WatchlistController) AddToWatchlist(c *gin.Context) {
+	var watchlistInputDTO watchlist_usecase.WatchlistInputDTO
+	if err := c.ShouldBindJSON(&watchlistInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	watchlistInputDTO.UserId = middleware.AuthenticatedUserId(c)
+
+	if err := u.watchlistUseCase.AddToWatchlist(context.Background(), watchlistInputDTO); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusCreated)
+}
+
+func (u *WatchlistController) RemoveFromWatchlist(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	err := u.watchlistUseCase.RemoveFromWatchlist(
+		context.Background(), middleware.AuthenticatedUserId(c), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *WatchlistController) ListWatchlist(c *gin.Context) {
+	watchlist, err := u.watchlistUseCase.ListWatchlist(context.Background(), middleware.AuthenticatedUserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
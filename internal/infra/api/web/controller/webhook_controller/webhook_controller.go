@@ -0,0 +1,100 @@
+package webhook_controller
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/validation"
+	"auction_go/internal/usecase/webhook_usecase"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookController struct {
+	webhookUseCase webhook_usecase.WebhookUseCaseInterface
+}
+
+func NewWebhookController(webhookUseCase webhook_usecase.WebhookUseCaseInterface) *WebhookController {
+	return &WebhookController{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (u *WebhookController) CreateWebhook(c *gin.Context) {
+	var webhookInputDTO webhook_usecase.WebhookInputDTO
+	if err := c.ShouldBindJSON(&webhookInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	webhook, err := u.webhookUseCase.CreateWebhook(context.Background(), webhookInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+func (u *WebhookController) ListWebhooks(c *gin.Context) {
+	webhooks, err := u.webhookUseCase.ListWebhooks(context.Background())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (u *WebhookController) DeleteWebhook(c *gin.Context) {
+	webhookId, ok := webhookIdParam(c)
+	if !ok {
+		return
+	}
+
+	if err := u.webhookUseCase.DeleteWebhook(context.Background(), webhookId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (u *WebhookController) FindDeadLettersByWebhookId(c *gin.Context) {
+	webhookId, ok := webhookIdParam(c)
+	if !ok {
+		return
+	}
+
+	deadLetters, err := u.webhookUseCase.FindDeadLettersByWebhookId(context.Background(), webhookId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+func webhookIdParam(c *gin.Context) (string, bool) {
+	webhookId := c.Param("webhookId")
+
+	if err := uuid.Validate(webhookId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "webhookId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return webhookId, true
+}
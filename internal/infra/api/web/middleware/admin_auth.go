@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"auction_go/configuration/rest_err"
+	"crypto/subtle"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth only lets through requests carrying the ADMIN_API_TOKEN in the
+// X-Admin-Token header, admin routes stay closed while it is unset
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			errRest := rest_err.NewUnauthorizedError("Invalid admin credentials")
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func isAdmin(c *gin.Context) bool {
+	token := os.Getenv("ADMIN_API_TOKEN")
+	providedToken := c.GetHeader("X-Admin-Token")
+
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(providedToken)) == 1
+}
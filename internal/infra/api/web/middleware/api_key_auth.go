@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// ApiKeyAuthenticator resolves an API key to the user it belongs to, as long
+// as the key was granted scope
+type ApiKeyAuthenticator interface {
+	Authenticate(
+		ctx context.Context, key string, scope api_key_entity.Scope) (string, *internal_error.InternalError)
+}
+
+// UserOrApiKeyAuth lets through the requests UserAuth does, as well as
+// requests carrying an X-API-Key granted scope. Either way the user is read
+// back with AuthenticatedUserId
+func UserOrApiKeyAuth(
+	apiKeys ApiKeyAuthenticator, accounts AccountFinder, scope api_key_entity.Scope) gin.HandlerFunc {
+	userAuth := UserAuth(accounts)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(apiKeyHeader)
+		if key == "" {
+			userAuth(c)
+			return
+		}
+
+		userId, err := apiKeys.Authenticate(c.Request.Context(), key, scope)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		if !setAuthenticatedUser(c, accounts, userId) {
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// apiKeys authenticates the keys it holds, each granted a single scope
+type apiKeys map[string]struct {
+	userId string
+	scope  api_key_entity.Scope
+}
+
+func (k apiKeys) Authenticate(
+	ctx context.Context, key string, scope api_key_entity.Scope) (string, *internal_error.InternalError) {
+	apiKey, ok := k[key]
+	if !ok {
+		return "", internal_error.NewUnauthorizedError("Invalid API key")
+	}
+	if !apiKey.scope.Allows(scope) {
+		return "", internal_error.NewForbiddenError("The API key is not allowed to " + string(scope))
+	}
+	return apiKey.userId, nil
+}
+
+type ApiKeyAuthSuite struct {
+	suite.Suite
+	apiKeys  apiKeys
+	accounts accounts
+}
+
+func (suite *ApiKeyAuthSuite) SetupTest() {
+	suite.T().Setenv(jwt.JWT_SECRET, "test-secret")
+	suite.apiKeys = apiKeys{
+		"ak_read":    {userId: "user-1", scope: api_key_entity.ScopeRead},
+		"ak_bid":     {userId: "user-1", scope: api_key_entity.ScopeBid},
+		"ak_deleted": {userId: "deleted-user", scope: api_key_entity.ScopeBid},
+	}
+	suite.accounts = accounts{deleted: map[string]bool{"deleted-user": true}}
+}
+
+func (suite *ApiKeyAuthSuite) TestUserOrApiKeyAuth() {
+	token, _, err := jwt.Issue("user-2", time.Now())
+	assert.Nil(suite.T(), err)
+
+	cases := []struct {
+		name    string
+		scope   api_key_entity.Scope
+		headers map[string]string
+		code    int
+		userId  string
+	}{
+		{
+			name:    "no key falls back to the bearer token",
+			scope:   api_key_entity.ScopeBid,
+			headers: bearer(token),
+			code:    http.StatusOK,
+			userId:  "user-2",
+		},
+		{
+			name:    "neither key nor token",
+			scope:   api_key_entity.ScopeRead,
+			headers: nil,
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "key acts as its user",
+			scope:   api_key_entity.ScopeRead,
+			headers: map[string]string{"X-API-Key": "ak_read"},
+			code:    http.StatusOK,
+			userId:  "user-1",
+		},
+		{
+			name:    "bid key reads",
+			scope:   api_key_entity.ScopeRead,
+			headers: map[string]string{"X-API-Key": "ak_bid"},
+			code:    http.StatusOK,
+			userId:  "user-1",
+		},
+		{
+			name:    "key lacking the scope",
+			scope:   api_key_entity.ScopeBid,
+			headers: map[string]string{"X-API-Key": "ak_read"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "unknown key",
+			scope:   api_key_entity.ScopeRead,
+			headers: map[string]string{"X-API-Key": "ak_unknown"},
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:  "an invalid key isn't saved by a valid token",
+			scope: api_key_entity.ScopeRead,
+			headers: map[string]string{
+				"X-API-Key":     "ak_unknown",
+				"Authorization": "Bearer " + token,
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:    "key of a deleted account",
+			scope:   api_key_entity.ScopeRead,
+			headers: map[string]string{"X-API-Key": "ak_deleted"},
+			code:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			recorder := serve(UserOrApiKeyAuth(suite.apiKeys, suite.accounts, tc.scope), tc.headers)
+			assert.Equal(suite.T(), tc.code, recorder.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(suite.T(), tc.userId, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestApiKeyAuthSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeyAuthSuite))
+}
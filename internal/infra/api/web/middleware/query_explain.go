@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"auction_go/configuration/database/mongodb"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryExplain turns on query explain logging for a single request when an
+// admin sends X-Debug-Explain: true, other callers are ignored silently
+func QueryExplain() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-Debug-Explain") == "true" && isAdmin(c) {
+			c.Request = c.Request.WithContext(mongodb.WithExplain(c.Request.Context()))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/openapi"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateRequest checks JSON bodies against the schema the spec has for
+// the route, before the controller binds them. Bodies that don't match are
+// answered with a 422 listing every offending field, the body is left for
+// the controller to read otherwise
+func ValidateRequest(spec *openapi.Spec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schema := spec.RequestSchema(c.Request.Method, c.FullPath())
+		if schema == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			errRest := rest_err.NewBadRequestError("Error trying to read the request body")
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if len(bytes.TrimSpace(body)) == 0 {
+			errRest := rest_err.NewUnprocessableEntityError("Request body is required")
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			errRest := rest_err.NewBadRequestError("Error trying to convert fields")
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		if err := schema.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+			errRest := rest_err.NewUnprocessableEntityError(
+				"Request body doesn't match its schema", schemaCauses(err)...)
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// schemaCauses flattens the validation errors, each field is given as its
+// dotted path in the body
+func schemaCauses(err error) []rest_err.Causes {
+	var multiError openapi3.MultiError
+	if errors.As(err, &multiError) {
+		var causes []rest_err.Causes
+		for _, e := range multiError {
+			causes = append(causes, schemaCauses(e)...)
+		}
+		return causes
+	}
+
+	var schemaError *openapi3.SchemaError
+	if !errors.As(err, &schemaError) {
+		return []rest_err.Causes{{Message: err.Error()}}
+	}
+
+	message := schemaError.Reason
+	if message == "" {
+		message = "doesn't match the " + schemaError.SchemaField + " rule"
+	}
+
+	return []rest_err.Causes{{
+		Field:   strings.Join(schemaError.JSONPointer(), "."),
+		Message: message,
+	}}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const userIdKey = "userId"
+
+// AccountFinder tells whether the user deleted their account, the tokens
+// issued before the deletion are turned down from then on
+type AccountFinder interface {
+	IsAccountDeleted(ctx context.Context, userId string) (bool, *internal_error.InternalError)
+}
+
+// UserAuth only lets through requests carrying a valid token issued by
+// POST /login as "Authorization: Bearer <token>", the user it was issued to
+// is read back with AuthenticatedUserId
+func UserAuth(accounts AccountFinder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			errRest := rest_err.NewUnauthorizedError("Missing bearer token")
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		claims, err := jwt.Parse(strings.TrimSpace(token), time.Now())
+		if err != nil {
+			errRest := rest_err.NewUnauthorizedError("Invalid bearer token")
+
+			c.AbortWithStatusJSON(errRest.Code, errRest)
+			return
+		}
+
+		if !setAuthenticatedUser(c, accounts, claims.Subject) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// setAuthenticatedUser turns the request away when the user deleted their
+// account
+func setAuthenticatedUser(c *gin.Context, accounts AccountFinder, userId string) bool {
+	deleted, err := accounts.IsAccountDeleted(c.Request.Context(), userId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+
+		c.AbortWithStatusJSON(errRest.Code, errRest)
+		return false
+	}
+	if deleted {
+		errRest := rest_err.NewUnauthorizedError("The account was deleted")
+
+		c.AbortWithStatusJSON(errRest.Code, errRest)
+		return false
+	}
+
+	c.Set(userIdKey, userId)
+	return true
+}
+
+// OptionalUserAuth lets requests without a token through anonymously, the
+// ones carrying a token are checked like UserAuth does
+func OptionalUserAuth(accounts AccountFinder) gin.HandlerFunc {
+	userAuth := UserAuth(accounts)
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		userAuth(c)
+	}
+}
+
+// AuthenticatedUserId is the user UserAuth let through, empty on routes it
+// doesn't guard
+func AuthenticatedUserId(c *gin.Context) string {
+	return c.GetString(userIdKey)
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/internal/internal_error"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// accounts reports the users in deleted as deleted and fails for the ones
+// in failing
+type accounts struct {
+	deleted map[string]bool
+	failing map[string]bool
+}
+
+func (a accounts) IsAccountDeleted(ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+	if a.failing[userId] {
+		return false, internal_error.NewInternalServerError("Error trying to find user")
+	}
+	return a.deleted[userId], nil
+}
+
+// serve runs the request through handler and answers with the user it let
+// through
+func serve(handler gin.HandlerFunc, headers map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", handler, func(c *gin.Context) {
+		c.String(http.StatusOK, AuthenticatedUserId(c))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func bearer(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token}
+}
+
+type UserAuthSuite struct {
+	suite.Suite
+	accounts accounts
+}
+
+func (suite *UserAuthSuite) SetupTest() {
+	suite.T().Setenv(jwt.JWT_SECRET, "test-secret")
+	suite.accounts = accounts{
+		deleted: map[string]bool{"deleted-user": true},
+		failing: map[string]bool{"failing-user": true},
+	}
+}
+
+func (suite *UserAuthSuite) issue(userId string, now time.Time) string {
+	token, _, err := jwt.Issue(userId, now)
+	assert.Nil(suite.T(), err)
+	return token
+}
+
+func (suite *UserAuthSuite) TestUserAuth() {
+	cases := []struct {
+		name    string
+		headers map[string]string
+		code    int
+		userId  string
+	}{
+		{
+			name:    "valid token",
+			headers: bearer(suite.issue("user-1", time.Now())),
+			code:    http.StatusOK,
+			userId:  "user-1",
+		},
+		{
+			name:    "no token",
+			headers: nil,
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "not a bearer token",
+			headers: map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "invalid token",
+			headers: bearer("not-a-token"),
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "expired token",
+			headers: bearer(suite.issue("user-1", time.Now().Add(-48*time.Hour))),
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "token of a deleted account",
+			headers: bearer(suite.issue("deleted-user", time.Now())),
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "account can't be looked up",
+			headers: bearer(suite.issue("failing-user", time.Now())),
+			code:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			recorder := serve(UserAuth(suite.accounts), tc.headers)
+			assert.Equal(suite.T(), tc.code, recorder.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(suite.T(), tc.userId, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func (suite *UserAuthSuite) TestOptionalUserAuth() {
+	cases := []struct {
+		name    string
+		headers map[string]string
+		code    int
+		userId  string
+	}{
+		{
+			name:    "anonymous",
+			headers: nil,
+			code:    http.StatusOK,
+			userId:  "",
+		},
+		{
+			name:    "valid token",
+			headers: bearer(suite.issue("user-1", time.Now())),
+			code:    http.StatusOK,
+			userId:  "user-1",
+		},
+		{
+			name:    "invalid token isn't taken as anonymous",
+			headers: bearer("not-a-token"),
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "token of a deleted account",
+			headers: bearer(suite.issue("deleted-user", time.Now())),
+			code:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			recorder := serve(OptionalUserAuth(suite.accounts), tc.headers)
+			assert.Equal(suite.T(), tc.code, recorder.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(suite.T(), tc.userId, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserAuthSuite(t *testing.T) {
+	suite.Run(t, new(UserAuthSuite))
+}
@@ -0,0 +1,447 @@
+package openapi
+
+import (
+	"auction_go/internal/infra/realtime"
+	"auction_go/internal/usecase/activity_usecase"
+	"auction_go/internal/usecase/admin_usecase"
+	"auction_go/internal/usecase/announcement_usecase"
+	"auction_go/internal/usecase/api_key_usecase"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/bid_usecase"
+	"auction_go/internal/usecase/category_usecase"
+	"auction_go/internal/usecase/checkout_usecase"
+	"auction_go/internal/usecase/device_usecase"
+	"auction_go/internal/usecase/feedback_usecase"
+	"auction_go/internal/usecase/notification_usecase"
+	"auction_go/internal/usecase/receipt_usecase"
+	"auction_go/internal/usecase/recommendation_usecase"
+	"auction_go/internal/usecase/return_usecase"
+	"auction_go/internal/usecase/saved_search_usecase"
+	"auction_go/internal/usecase/search_usecase"
+	"auction_go/internal/usecase/seller_usecase"
+	"auction_go/internal/usecase/stats_usecase"
+	"auction_go/internal/usecase/user_usecase"
+	"auction_go/internal/usecase/view_usecase"
+	"auction_go/internal/usecase/watchlist_usecase"
+	"auction_go/internal/usecase/webhook_usecase"
+	"net/http"
+)
+
+// The security schemes an operation accepts
+const (
+	bearerAuth = "bearerAuth"
+	apiKeyAuth = "apiKeyAuth"
+	adminAuth  = "adminAuth"
+)
+
+// operation describes a route beyond what the router knows. request is the
+// body the controller binds, it is also what incoming bodies are validated
+// against. status defaults to 200
+type operation struct {
+	summary  string
+	tag      string
+	request  any
+	response any
+	status   int
+	security []string
+}
+
+// operations are keyed by method and the route's path without its version
+// prefix. Routes left out still show up in the spec, without a body
+var operations = map[string]operation{
+	"GET /metrics": {summary: "Prometheus metrics", tag: "monitoring"},
+
+	"GET /auction": {
+		summary: "List auctions with filters, sorting and cursor pagination", tag: "auctions",
+		response: []auction_usecase.AuctionOutputDTO{},
+	},
+	"POST /auction": {
+		summary: "Create an auction", tag: "auctions",
+		request: auction_usecase.AuctionInputDTO{}, status: http.StatusCreated,
+		security: []string{bearerAuth},
+	},
+	"GET /auction/:auctionId": {
+		summary: "Find an auction", tag: "auctions",
+		response: auction_usecase.AuctionOutputDTO{},
+	},
+	"GET /auction/winner/:auctionId": {
+		summary: "Find the winning bid of an auction", tag: "auctions",
+		response: auction_usecase.WinningInfoOutputDTO{},
+	},
+	"PUT /auction/:auctionId/cancel": {
+		summary: "Cancel an auction", tag: "auctions",
+		request: auction_usecase.CancelAuctionInputDTO{}, response: auction_usecase.AuctionOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"POST /auction/:auctionId/buy-now": {
+		summary: "Buy an auction at its buy now price", tag: "auctions",
+		response: auction_usecase.AuctionOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /auction/:auctionId/views": {
+		summary: "Daily views of an auction", tag: "auctions",
+		response: view_usecase.AuctionViewsOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /auction/:auctionId/announcements": {
+		summary: "List the seller's announcements on an auction", tag: "auctions",
+		response: []announcement_usecase.AnnouncementOutputDTO{},
+	},
+	"POST /auction/:auctionId/announcements": {
+		summary: "Post an announcement on an auction", tag: "auctions",
+		request: announcement_usecase.AnnouncementInputDTO{}, response: announcement_usecase.AnnouncementOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"GET /auction/:auctionId/checkout": {
+		summary: "Find the checkout of an auction", tag: "checkout",
+		response: checkout_usecase.CheckoutOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /auction/:auctionId/bids": {
+		summary: "Page through the bids of an auction", tag: "bids",
+		response: bid_usecase.BidPageOutputDTO{},
+	},
+	"POST /auction/:auctionId/feedback": {
+		summary: "Leave feedback on a sale", tag: "sellers",
+		request: feedback_usecase.FeedbackInputDTO{}, response: feedback_usecase.FeedbackOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"GET /auctions/ending-soon": {
+		summary: "Active auctions ending soon", tag: "auctions",
+		response: auction_usecase.AuctionListOutputDTO{},
+	},
+	"GET /auctions/new": {
+		summary: "Recently created auctions", tag: "auctions",
+		response: auction_usecase.AuctionListOutputDTO{},
+	},
+	"GET /auctions/:auctionId/stats": {
+		summary: "Bidding statistics of an auction", tag: "auctions",
+		response: stats_usecase.AuctionStatsOutputDTO{},
+	},
+	"GET /auctions/:auctionId/similar": {
+		summary: "Auctions similar to an auction", tag: "auctions",
+		response: []recommendation_usecase.RecommendedAuctionOutputDTO{},
+	},
+	"GET /auctions/:auctionId/time": {
+		summary: "Remaining time of an auction, with the server clock", tag: "auctions",
+		response: auction_usecase.AuctionTimeOutputDTO{},
+	},
+	"GET /auctions/:auctionId/presence": {
+		summary: "How many clients are watching an auction", tag: "stream",
+		response: realtime.Presence{},
+	},
+	"GET /ws/users/me": {
+		summary: "Follow your notifications, like being outbid, over a WebSocket", tag: "stream",
+		security: []string{bearerAuth},
+	},
+	"GET /sse/users/me": {
+		summary: "Follow your notifications, like being outbid, as server-sent events", tag: "stream",
+		security: []string{bearerAuth},
+	},
+	"GET /ws/auctions/:auctionId":  {summary: "Follow an auction over a WebSocket", tag: "stream"},
+	"GET /sse/auctions/:auctionId": {summary: "Follow an auction as server-sent events", tag: "stream"},
+	"POST /graphql":                {summary: "Run a GraphQL query or mutation", tag: "graphql"},
+
+	"POST /bid": {
+		summary: "Place a bid", tag: "bids",
+		request: bid_usecase.BidInputDTO{}, response: bid_usecase.BidOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth, apiKeyAuth},
+	},
+	"POST /bid/proxy": {
+		summary: "Set a maximum amount to be bid automatically", tag: "bids",
+		request: bid_usecase.ProxyBidInputDTO{}, status: http.StatusCreated,
+		security: []string{bearerAuth, apiKeyAuth},
+	},
+	"GET /bid/:auctionId": {
+		summary: "List every bid of an auction", tag: "bids",
+		response: []bid_usecase.BidOutputDTO{},
+	},
+
+	"POST /checkout": {
+		summary: "Start the checkout of a won auction", tag: "checkout",
+		request: checkout_usecase.CheckoutInputDTO{}, response: checkout_usecase.CheckoutOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"GET /checkout/shipping-options": {
+		summary: "Available shipping options", tag: "checkout",
+		response: []checkout_usecase.ShippingOptionOutputDTO{},
+	},
+	"GET /checkout/:checkoutId": {
+		summary: "Find a checkout", tag: "checkout",
+		response: checkout_usecase.CheckoutOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /checkout/:checkoutId/return": {
+		summary: "Find the return request of a checkout", tag: "returns",
+		response: return_usecase.ReturnRequestOutputDTO{}, security: []string{bearerAuth},
+	},
+	"PUT /checkout/:checkoutId/address": {
+		summary: "Confirm the shipping address", tag: "checkout",
+		request: checkout_usecase.AddressInputDTO{}, response: checkout_usecase.CheckoutOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"PUT /checkout/:checkoutId/shipping": {
+		summary: "Select a shipping option", tag: "checkout",
+		request: checkout_usecase.ShippingInputDTO{}, response: checkout_usecase.CheckoutOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"PUT /checkout/:checkoutId/payment": {
+		summary: "Select the payment method", tag: "checkout",
+		request: checkout_usecase.PaymentInputDTO{}, response: checkout_usecase.CheckoutOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"PUT /checkout/:checkoutId/shipment": {
+		summary: "Attach the shipment's tracking", tag: "checkout",
+		request: checkout_usecase.ShipmentInputDTO{}, response: checkout_usecase.CheckoutOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"POST /checkout/shipment-updates": {
+		summary: "Carrier tracking updates", tag: "checkout",
+		request: checkout_usecase.CarrierUpdateInputDTO{}, response: checkout_usecase.CheckoutOutputDTO{},
+	},
+	"POST /checkout/payment-updates/pix": {
+		summary: "Pix payment confirmations", tag: "checkout",
+		request: checkout_usecase.PixWebhookInputDTO{},
+	},
+
+	"POST /returns": {
+		summary: "Request a return", tag: "returns",
+		request: return_usecase.ReturnRequestInputDTO{}, response: return_usecase.ReturnRequestOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"GET /returns/:returnId": {
+		summary: "Find a return request", tag: "returns",
+		response: return_usecase.ReturnRequestOutputDTO{}, security: []string{bearerAuth},
+	},
+	"PUT /returns/:returnId/accept": {
+		summary: "Accept a return request", tag: "returns",
+		request: return_usecase.ReturnDecisionInputDTO{}, response: return_usecase.ReturnRequestOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"PUT /returns/:returnId/decline": {
+		summary: "Decline a return request", tag: "returns",
+		request: return_usecase.ReturnDecisionInputDTO{}, response: return_usecase.ReturnRequestOutputDTO{},
+		security: []string{bearerAuth},
+	},
+
+	"GET /sellers/:sellerId": {
+		summary: "Seller profile and auctions", tag: "sellers",
+		response: seller_usecase.SellerOutputDTO{},
+	},
+	"GET /sellers/by-slug/:slug": {
+		summary: "Seller profile and auctions, by the seller's slug", tag: "sellers",
+		response: seller_usecase.SellerOutputDTO{},
+	},
+	"GET /sellers/:sellerId/feedback": {
+		summary: "Page through a seller's feedback", tag: "sellers",
+		response: feedback_usecase.FeedbackPageOutputDTO{},
+	},
+
+	"POST /login": {
+		summary: "Log in, the token goes in the Authorization header", tag: "users",
+		request: user_usecase.LoginInputDTO{}, response: user_usecase.LoginOutputDTO{},
+	},
+	"POST /user": {
+		summary: "Sign up", tag: "users",
+		request: user_usecase.UserInputDTO{}, response: user_usecase.UserOutputDTO{},
+		status: http.StatusCreated,
+	},
+	"POST /users/verify": {
+		summary: "Verify an email address", tag: "users",
+		request: user_usecase.VerifyEmailInputDTO{}, response: user_usecase.UserOutputDTO{},
+	},
+	"GET /user/:userId": {
+		summary: "Find a user", tag: "users",
+		response: user_usecase.UserOutputDTO{},
+	},
+	"PUT /user/:userId": {
+		summary: "Update your profile", tag: "users",
+		request: user_usecase.UpdateUserInputDTO{}, response: user_usecase.UserOutputDTO{},
+		security: []string{bearerAuth},
+	},
+	"DELETE /user/:userId": {
+		summary: "Delete your account", tag: "users",
+		status: http.StatusNoContent, security: []string{bearerAuth},
+	},
+	"GET /users/me/recommendations": {
+		summary: "Auctions recommended to the authenticated user", tag: "users",
+		response: recommendation_usecase.RecommendationOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /users/me/receipts": {
+		summary: "Receipts of the auctions you won", tag: "users",
+		response: []receipt_usecase.ReceiptOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /users/me/receipts/:receiptId": {
+		summary: "A receipt of an auction you won", tag: "users",
+		response: receipt_usecase.ReceiptOutputDTO{}, security: []string{bearerAuth},
+	},
+	"GET /user/:userId/bids": {
+		summary: "Auctions a user is bidding on", tag: "bids",
+		response: []bid_usecase.ActiveBidOutputDTO{},
+	},
+	"GET /user/:userId/activity": {
+		summary: "Page through a user's activity", tag: "users",
+		response: activity_usecase.ActivityPageOutputDTO{},
+	},
+
+	"GET /watchlist": {
+		summary: "List your watchlist", tag: "watchlist",
+		response: []watchlist_usecase.WatchlistEntryOutputDTO{}, security: []string{bearerAuth, apiKeyAuth},
+	},
+	"POST /watchlist": {
+		summary: "Watch an auction", tag: "watchlist",
+		request: watchlist_usecase.WatchlistInputDTO{}, status: http.StatusCreated,
+		security: []string{bearerAuth},
+	},
+	"DELETE /watchlist/:auctionId": {
+		summary: "Stop watching an auction", tag: "watchlist",
+		status: http.StatusNoContent, security: []string{bearerAuth},
+	},
+
+	"GET /saved-searches": {
+		summary: "List your saved searches", tag: "saved-searches",
+		response: []saved_search_usecase.SavedSearchOutputDTO{}, security: []string{bearerAuth},
+	},
+	"POST /saved-searches": {
+		summary: "Save a search to be alerted of new matches", tag: "saved-searches",
+		request: saved_search_usecase.SavedSearchInputDTO{}, response: saved_search_usecase.SavedSearchOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"DELETE /saved-searches/:savedSearchId": {
+		summary: "Delete a saved search", tag: "saved-searches",
+		status: http.StatusNoContent, security: []string{bearerAuth},
+	},
+
+	"GET /devices": {
+		summary: "List your push devices", tag: "devices",
+		response: []device_usecase.DeviceOutputDTO{}, security: []string{bearerAuth},
+	},
+	"POST /devices": {
+		summary: "Register a push device", tag: "devices",
+		request: device_usecase.DeviceInputDTO{}, response: device_usecase.DeviceOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"DELETE /devices/:deviceId": {
+		summary: "Remove a push device", tag: "devices",
+		status: http.StatusNoContent, security: []string{bearerAuth},
+	},
+
+	"GET /notification-preferences": {
+		summary: "Your notification preferences", tag: "notifications",
+		response: notification_usecase.PreferencesOutputDTO{}, security: []string{bearerAuth},
+	},
+	"PUT /notification-preferences": {
+		summary: "Update your notification preferences", tag: "notifications",
+		request: notification_usecase.PreferencesInputDTO{}, response: notification_usecase.PreferencesOutputDTO{},
+		security: []string{bearerAuth},
+	},
+
+	"GET /api-keys": {
+		summary: "List your API keys", tag: "api-keys",
+		response: []api_key_usecase.ApiKeyOutputDTO{}, security: []string{bearerAuth},
+	},
+	"POST /api-keys": {
+		summary: "Create an API key, the key is only shown once", tag: "api-keys",
+		request: api_key_usecase.ApiKeyInputDTO{}, response: api_key_usecase.ApiKeyOutputDTO{},
+		status: http.StatusCreated, security: []string{bearerAuth},
+	},
+	"POST /api-keys/:keyId/rotate": {
+		summary: "Rotate an API key", tag: "api-keys",
+		response: api_key_usecase.ApiKeyOutputDTO{}, security: []string{bearerAuth},
+	},
+	"DELETE /api-keys/:keyId": {
+		summary: "Revoke an API key", tag: "api-keys",
+		status: http.StatusNoContent, security: []string{bearerAuth},
+	},
+
+	"GET /categories": {
+		summary: "The category tree", tag: "categories",
+		response: []category_usecase.CategoryOutputDTO{},
+	},
+	"GET /search": {
+		summary: "Full-text search over auctions", tag: "search",
+		response: auction_usecase.AuctionSearchOutputDTO{},
+	},
+	"GET /search/synonyms": {
+		summary: "List the search synonym groups", tag: "search",
+		response: []search_usecase.SynonymGroupOutputDTO{},
+	},
+
+	"POST /admin/auctions/bulk-status": {
+		summary: "Change the status of the auctions matching a filter", tag: "admin",
+		request: admin_usecase.BulkStatusInputDTO{}, response: admin_usecase.BulkStatusJobOutputDTO{},
+		status: http.StatusAccepted, security: []string{adminAuth},
+	},
+	"PUT /admin/auctions/:auctionId/end-time": {
+		summary: "Move an auction's end time", tag: "admin",
+		request: admin_usecase.EndTimeInputDTO{}, response: admin_usecase.AuditEntryOutputDTO{},
+		security: []string{adminAuth},
+	},
+	"PUT /admin/auctions/:auctionId/pause": {
+		summary: "Pause an auction", tag: "admin",
+		request: admin_usecase.PauseInputDTO{}, response: admin_usecase.AuditEntryOutputDTO{},
+		security: []string{adminAuth},
+	},
+	"PUT /admin/auctions/:auctionId/resume": {
+		summary: "Resume a paused auction", tag: "admin",
+		request: admin_usecase.PauseInputDTO{}, response: admin_usecase.AuditEntryOutputDTO{},
+		security: []string{adminAuth},
+	},
+	"PUT /admin/auctions/:auctionId/reopen": {
+		summary: "Reopen an auction closed by mistake", tag: "admin",
+		request: admin_usecase.EndTimeInputDTO{}, response: admin_usecase.AuditEntryOutputDTO{},
+		security: []string{adminAuth},
+	},
+	"GET /admin/auctions/:auctionId/audit": {
+		summary: "Admin changes made to an auction", tag: "admin",
+		response: []admin_usecase.AuditEntryOutputDTO{}, security: []string{adminAuth},
+	},
+	"GET /admin/auctions/:auctionId/replay": {
+		summary: "Rebuild an auction's state at some point in time", tag: "admin",
+		response: admin_usecase.AuctionReplayOutputDTO{}, security: []string{adminAuth},
+	},
+	"GET /admin/jobs/:jobId": {
+		summary: "Find a bulk status job", tag: "admin",
+		response: admin_usecase.BulkStatusJobOutputDTO{}, security: []string{adminAuth},
+	},
+	"GET /admin/jobs/:jobId/audit": {
+		summary: "Changes made by a bulk status job", tag: "admin",
+		response: []admin_usecase.AuditEntryOutputDTO{}, security: []string{adminAuth},
+	},
+	"POST /admin/categories": {
+		summary: "Create a category", tag: "admin",
+		request: category_usecase.CategoryInputDTO{}, response: category_usecase.CategoryOutputDTO{},
+		status: http.StatusCreated, security: []string{adminAuth},
+	},
+	"POST /admin/search/synonyms": {
+		summary: "Create a search synonym group", tag: "admin",
+		request: search_usecase.SynonymGroupInputDTO{}, response: search_usecase.SynonymGroupOutputDTO{},
+		status: http.StatusCreated, security: []string{adminAuth},
+	},
+	"DELETE /admin/search/synonyms/:synonymGroupId": {
+		summary: "Delete a search synonym group", tag: "admin",
+		status: http.StatusNoContent, security: []string{adminAuth},
+	},
+	"PUT /admin/users/:userId/block": {
+		summary: "Block a user", tag: "admin",
+		request: user_usecase.BlockUserInputDTO{}, status: http.StatusNoContent,
+		security: []string{adminAuth},
+	},
+	"DELETE /admin/users/:userId/block": {
+		summary: "Unblock a user", tag: "admin",
+		status: http.StatusNoContent, security: []string{adminAuth},
+	},
+	"GET /admin/webhooks": {
+		summary: "List the webhooks", tag: "admin",
+		response: []webhook_usecase.WebhookOutputDTO{}, security: []string{adminAuth},
+	},
+	"POST /admin/webhooks": {
+		summary: "Register a webhook", tag: "admin",
+		request: webhook_usecase.WebhookInputDTO{}, response: webhook_usecase.WebhookOutputDTO{},
+		status: http.StatusCreated, security: []string{adminAuth},
+	},
+	"DELETE /admin/webhooks/:webhookId": {
+		summary: "Delete a webhook", tag: "admin",
+		status: http.StatusNoContent, security: []string{adminAuth},
+	},
+	"GET /admin/webhooks/:webhookId/dead-letters": {
+		summary: "Deliveries a webhook gave up on", tag: "admin",
+		response: []webhook_usecase.DeadLetterOutputDTO{}, security: []string{adminAuth},
+	},
+}
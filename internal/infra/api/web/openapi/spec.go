@@ -0,0 +1,414 @@
+package openapi
+
+import (
+	"auction_go/configuration/rest_err"
+	"auction_go/internal/infra/api/web/versioning"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/gin-gonic/gin"
+)
+
+// Spec is the OpenAPI 3 document of the HTTP API. The body schemas are
+// generated from the DTOs the controllers bind, along with their binding
+// rules, so the document and the validation follow the code
+type Spec struct {
+	document       *openapi3.T
+	requestSchemas map[string]*openapi3.Schema
+	responses      map[string]*openapi3.SchemaRef
+
+	// components holds the RestErr schema and the recursive types, which
+	// are referenced instead of inlined
+	components openapi3.Schemas
+}
+
+func NewSpec() (*Spec, error) {
+	spec := &Spec{
+		requestSchemas: make(map[string]*openapi3.Schema),
+		responses:      make(map[string]*openapi3.SchemaRef),
+		components:     make(openapi3.Schemas),
+	}
+
+	errorSchema, err := spec.generateSchema(rest_err.RestErr{})
+	if err != nil {
+		return nil, err
+	}
+	spec.components["RestErr"] = errorSchema
+
+	for key, operation := range operations {
+		if operation.request != nil {
+			schemaRef, err := spec.generateSchema(operation.request)
+			if err != nil {
+				return nil, fmt.Errorf("request body of %s: %w", key, err)
+			}
+			spec.requestSchemas[key] = schemaRef.Value
+		}
+
+		if operation.response != nil {
+			schemaRef, err := spec.generateSchema(operation.response)
+			if err != nil {
+				return nil, fmt.Errorf("response of %s: %w", key, err)
+			}
+			spec.responses[key] = schemaRef
+		}
+	}
+
+	return spec, nil
+}
+
+// RequestSchema is the schema of the body the route binds, nil when it
+// takes no body
+func (s *Spec) RequestSchema(method, fullPath string) *openapi3.Schema {
+	return s.requestSchemas[operationKey(method, fullPath)]
+}
+
+// operationKey is the same for a route in every version
+func operationKey(method, fullPath string) string {
+	return method + " " + versioning.RoutePath(fullPath)
+}
+
+// Register documents every route of the router, it is called once they
+// are all registered. The unversioned paths kept for older clients are
+// left out when the route is also served under a version
+func (s *Spec) Register(routes gin.RoutesInfo) {
+	document := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:   "Auction API",
+			Version: "1.0.0",
+			Description: "The API is served under /api/v1 and /api/v2. Operations whose responses changed " +
+				"in a version describe the change. Unversioned paths answer like /api/v1",
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: s.components,
+			SecuritySchemes: openapi3.SecuritySchemes{
+				bearerAuth: &openapi3.SecuritySchemeRef{
+					Value: openapi3.NewJWTSecurityScheme(),
+				},
+				apiKeyAuth: &openapi3.SecuritySchemeRef{
+					Value: openapi3.NewSecurityScheme().WithType("apiKey").WithIn("header").WithName("X-API-Key"),
+				},
+				adminAuth: &openapi3.SecuritySchemeRef{
+					Value: openapi3.NewSecurityScheme().WithType("apiKey").WithIn("header").WithName("X-Admin-Token"),
+				},
+			},
+		},
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	versioned := make(map[string]bool)
+	for _, route := range routes {
+		if _, ok := versioning.RouteVersion(route.Path); ok {
+			versioned[operationKey(route.Method, route.Path)] = true
+		}
+	}
+
+	for _, route := range routes {
+		path, parameters := toOpenAPIPath(route.Path)
+		if strings.HasPrefix(path, "/swagger") {
+			continue
+		}
+		if _, ok := versioning.RouteVersion(route.Path); !ok && versioned[operationKey(route.Method, route.Path)] {
+			continue
+		}
+
+		pathItem := document.Paths.Value(path)
+		if pathItem == nil {
+			pathItem = &openapi3.PathItem{}
+			document.Paths.Set(path, pathItem)
+		}
+		pathItem.SetOperation(route.Method, s.newOperation(route.Method, route.Path, parameters))
+	}
+
+	s.document = document
+}
+
+func (s *Spec) newOperation(method, fullPath string, parameters []string) *openapi3.Operation {
+	key := operationKey(method, fullPath)
+	operationInfo := operations[key]
+
+	operation := openapi3.NewOperation()
+	operation.Summary = operationInfo.summary
+	if version, ok := versioning.RouteVersion(fullPath); ok {
+		operation.Description = strings.Join(versioning.Changes(version, key), "\n\n")
+	}
+	if operationInfo.tag != "" {
+		operation.Tags = []string{operationInfo.tag}
+	}
+
+	for _, parameter := range parameters {
+		operation.AddParameter(openapi3.NewPathParameter(parameter).WithSchema(openapi3.NewStringSchema()))
+	}
+
+	if schema, ok := s.requestSchemas[key]; ok {
+		operation.RequestBody = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchema(schema),
+		}
+	}
+
+	if len(operationInfo.security) > 0 {
+		requirements := openapi3.NewSecurityRequirements()
+		for _, scheme := range operationInfo.security {
+			requirements.With(openapi3.NewSecurityRequirement().Authenticate(scheme))
+		}
+		operation.Security = requirements
+	}
+
+	status := operationInfo.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := openapi3.NewResponse().WithDescription(http.StatusText(status))
+	if schemaRef, ok := s.responses[key]; ok {
+		response.WithContent(openapi3.NewContentWithJSONSchemaRef(schemaRef))
+	}
+	operation.AddResponse(status, response)
+
+	errorResponse := openapi3.NewResponse().WithDescription("Error").
+		WithContent(openapi3.NewContentWithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/RestErr", nil)))
+	operation.Responses.Set("default", &openapi3.ResponseRef{Value: errorResponse})
+	if _, ok := s.requestSchemas[key]; ok {
+		unprocessable := openapi3.NewResponse().WithDescription("The body doesn't match its schema").
+			WithContent(openapi3.NewContentWithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/RestErr", nil)))
+		operation.AddResponse(http.StatusUnprocessableEntity, unprocessable)
+	}
+
+	return operation
+}
+
+// MarshalJSON writes the document, it is empty until Register
+func (s *Spec) MarshalJSON() ([]byte, error) {
+	if s.document == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(s.document)
+}
+
+// toOpenAPIPath turns the router's :param segments into {param}, and lists
+// them
+func toOpenAPIPath(fullPath string) (string, []string) {
+	segments := strings.Split(fullPath, "/")
+	var parameters []string
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			parameter := segment[1:]
+			parameters = append(parameters, parameter)
+			segments[i] = "{" + parameter + "}"
+		}
+	}
+
+	return strings.Join(segments, "/"), parameters
+}
+
+func (s *Spec) generateSchema(value any) (*openapi3.SchemaRef, error) {
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(
+		value, s.components, openapi3gen.SchemaCustomizer(nullableCollections))
+	if err != nil {
+		return nil, err
+	}
+
+	applyBindings(schemaRef.Value, reflect.TypeOf(value))
+	return schemaRef, nil
+}
+
+// nullableCollections lets nil slices and maps through like the JSON
+// decoding does. Having a customizer also keeps the generator from sharing
+// one schema between fields of the same type, which applyBindings relies on
+func nullableCollections(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if name != "_root" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Map) {
+		schema.Nullable = true
+	}
+
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// applyBindings adds the binding rules of t's fields to the schema
+// generated for it. Rules after dive apply to the items of a slice
+func applyBindings(schema *openapi3.Schema, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if schema == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		if schema.Items != nil && schema.Items.Ref == "" {
+			applyBindings(schema.Items.Value, t.Elem())
+		}
+		return
+	case reflect.Map:
+		if additional := schema.AdditionalProperties.Schema; additional != nil && additional.Ref == "" {
+			applyBindings(additional.Value, t.Elem())
+		}
+		return
+	case reflect.Struct:
+		if t == timeType {
+			return
+		}
+	default:
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		// Properties referencing a component are recursive types, their
+		// rules were applied where the type was first generated
+		property := schema.Properties[name]
+		if property == nil || property.Value == nil || property.Ref != "" {
+			continue
+		}
+
+		rules, itemRules, hasDive := strings.Cut(field.Tag.Get("binding"), ",dive")
+		if strings.HasPrefix(rules, "dive") {
+			rules, itemRules, hasDive = "", strings.TrimPrefix(rules, "dive"), true
+		}
+
+		if applyRules(property.Value, rules) {
+			schema.Required = append(schema.Required, name)
+		}
+		if hasDive && property.Value.Items != nil {
+			applyRules(property.Value.Items.Value, strings.TrimPrefix(itemRules, ","))
+		}
+
+		applyBindings(property.Value, field.Type)
+	}
+}
+
+// applyRules sets the constraints the rules map to, and tells whether the
+// value is required. Lower bounds and enums are left out under omitempty,
+// since the zero value skips them
+func applyRules(schema *openapi3.Schema, rules string) bool {
+	if rules == "" || schema == nil {
+		return false
+	}
+
+	list := strings.Split(rules, ",")
+	omitEmpty := false
+	for _, rule := range list {
+		if rule == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	required := false
+	for _, rule := range list {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "email", "uuid":
+			schema.Format = name
+		case "url":
+			schema.Format = "uri"
+		case "min", "gte":
+			if !omitEmpty {
+				setLowerBound(schema, param, false)
+			}
+		case "gt":
+			if !omitEmpty {
+				setLowerBound(schema, param, true)
+			}
+		case "max", "lte":
+			setUpperBound(schema, param, false)
+		case "lt":
+			setUpperBound(schema, param, true)
+		case "len":
+			if !omitEmpty {
+				setLowerBound(schema, param, false)
+			}
+			setUpperBound(schema, param, false)
+		case "oneof":
+			if !omitEmpty {
+				setEnum(schema, strings.Fields(param))
+			}
+		}
+	}
+
+	return required
+}
+
+func setLowerBound(schema *openapi3.Schema, param string, exclusive bool) {
+	switch {
+	case schema.Type.Is("string"):
+		if length, err := strconv.ParseUint(param, 10, 64); err == nil {
+			schema.MinLength = length
+		}
+	case schema.Type.Is("array"):
+		if length, err := strconv.ParseUint(param, 10, 64); err == nil {
+			schema.MinItems = length
+		}
+	case schema.Type.Is("number"), schema.Type.Is("integer"):
+		if bound, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Min = &bound
+			schema.ExclusiveMin = exclusive
+		}
+	}
+}
+
+func setUpperBound(schema *openapi3.Schema, param string, exclusive bool) {
+	switch {
+	case schema.Type.Is("string"):
+		if length, err := strconv.ParseUint(param, 10, 64); err == nil {
+			schema.MaxLength = &length
+		}
+	case schema.Type.Is("array"):
+		if length, err := strconv.ParseUint(param, 10, 64); err == nil {
+			schema.MaxItems = &length
+		}
+	case schema.Type.Is("number"), schema.Type.Is("integer"):
+		if bound, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Max = &bound
+			schema.ExclusiveMax = exclusive
+		}
+	}
+}
+
+func setEnum(schema *openapi3.Schema, values []string) {
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		// Decoded JSON numbers are float64, the enum has to match them
+		if schema.Type.Is("integer") || schema.Type.Is("number") {
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				enum = append(enum, number)
+			}
+			continue
+		}
+		enum = append(enum, value)
+	}
+
+	schema.Enum = enum
+}
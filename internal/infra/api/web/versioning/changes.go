@@ -0,0 +1,113 @@
+package versioning
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"encoding/json"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// change is a breaking change to some responses, introduced in version.
+// transform rewrites a response body of one of routes, decoded as generic
+// JSON, from the shape of the version before and returns it
+type change struct {
+	version     Version
+	description string
+	routes      []string
+	transform   func(c *gin.Context, body any) any
+}
+
+var changes = []change{
+	{
+		version: V2,
+		description: "An auction's end_time is when its bidding ends or ended: null while it is paused, since " +
+			"resuming moves it, and the cancellation time once cancelled. The end it was scheduled for is " +
+			"kept in scheduled_end_time",
+		routes: []string{
+			"GET /auction",
+			"GET /auction/:auctionId",
+			"GET /auction/winner/:auctionId",
+			"PUT /auction/:auctionId/cancel",
+			"POST /auction/:auctionId/buy-now",
+			"GET /auctions/ending-soon",
+			"GET /auctions/new",
+			"GET /auctions/:auctionId/time",
+			"GET /sellers/:sellerId",
+			"GET /sellers/by-slug/:slug",
+			"GET /search",
+		},
+		transform: forEachAuction(effectiveEndTime),
+	},
+	{
+		version: V2,
+		description: "The listing is a page object, {auctions, next_cursor, facets}, rather than an array of " +
+			"auctions. Every version sends next_cursor in the X-Next-Cursor header as well",
+		routes:    []string{"GET /auction"},
+		transform: envelope("auctions"),
+	},
+}
+
+// Changes describes how the route answers in version compared to V1, for
+// the API documentation. route is the method and the unversioned path
+func Changes(version Version, route string) []string {
+	var descriptions []string
+	for _, change := range changes {
+		if change.version <= version && slices.Contains(change.routes, route) {
+			descriptions = append(descriptions, change.description)
+		}
+	}
+
+	return descriptions
+}
+
+// forEachAuction runs transform on every auction in the body, the objects
+// carrying both a status and an end_time
+func forEachAuction(transform func(auction map[string]any)) func(c *gin.Context, body any) any {
+	var walk func(value any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			_, hasStatus := value["status"]
+			_, hasEndTime := value["end_time"]
+			if hasStatus && hasEndTime {
+				transform(value)
+			}
+			for _, field := range value {
+				walk(field)
+			}
+		case []any:
+			for _, item := range value {
+				walk(item)
+			}
+		}
+	}
+
+	return func(c *gin.Context, body any) any {
+		walk(body)
+		return body
+	}
+}
+
+// effectiveEndTime leaves alone the objects whose status isn't an auction
+// status
+func effectiveEndTime(auction map[string]any) {
+	statusNumber, ok := auction["status"].(json.Number)
+	if !ok {
+		return
+	}
+	status, err := statusNumber.Int64()
+	if err != nil {
+		return
+	}
+
+	auction["scheduled_end_time"] = auction["end_time"]
+	switch auction_entity.AuctionStatus(status) {
+	case auction_entity.Paused:
+		auction["end_time"] = nil
+	case auction_entity.Cancelled:
+		if cancelledAt, ok := auction["cancelled_at"]; ok {
+			auction["end_time"] = cancelledAt
+		}
+	}
+}
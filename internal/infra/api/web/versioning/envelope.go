@@ -0,0 +1,35 @@
+package versioning
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const envelopeKey = "versioning.envelope"
+
+// SetEnvelope keeps the fields of a response that its V1 shape, a bare
+// array, has no room for. The versions that wrap the array in an object add
+// them next to it
+func SetEnvelope(c *gin.Context, fields map[string]any) {
+	c.Set(envelopeKey, fields)
+}
+
+// envelope wraps the body in an object holding it as name, along with the
+// fields the handler set. Errors are objects already and are left alone
+func envelope(name string) func(c *gin.Context, body any) any {
+	return func(c *gin.Context, body any) any {
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return body
+		}
+
+		wrapped := map[string]any{name: body}
+		value, _ := c.Get(envelopeKey)
+		fields, _ := value.(map[string]any)
+		for field, value := range fields {
+			wrapped[field] = value
+		}
+
+		return wrapped
+	}
+}
@@ -0,0 +1,82 @@
+package versioning
+
+import (
+	"auction_go/configuration/logger"
+	"bytes"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// upgradeResponses applies the changes to the responses of the routes they
+// touch, oldest change first. Other routes are served as they are
+func upgradeResponses(upgrades []change) gin.HandlerFunc {
+	transforms := make(map[string][]func(c *gin.Context, body any) any)
+	for _, change := range upgrades {
+		for _, route := range change.routes {
+			transforms[route] = append(transforms[route], change.transform)
+		}
+	}
+
+	return func(c *gin.Context) {
+		routeTransforms := transforms[c.Request.Method+" "+RoutePath(c.FullPath())]
+		if len(routeTransforms) == 0 {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if upgraded, err := upgrade(c, body, routeTransforms); err != nil {
+			logger.Error("Error trying to upgrade response", err, zap.String("route", c.FullPath()))
+		} else {
+			body = upgraded
+		}
+
+		if len(body) > 0 {
+			c.Writer.Write(body)
+		}
+	}
+}
+
+// upgrade decodes numbers as json.Number, so they are written back as they
+// came
+func upgrade(c *gin.Context, body []byte, transforms []func(c *gin.Context, body any) any) ([]byte, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	for _, transform := range transforms {
+		value = transform(c, value)
+	}
+
+	return json.Marshal(value)
+}
+
+// bufferedWriter holds the body back until the changes are applied, the
+// status and headers go through as usual
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(data string) (int, error) {
+	return w.body.WriteString(data)
+}
@@ -0,0 +1,201 @@
+package versioning
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpgradeSuite struct {
+	suite.Suite
+	router   *gin.Engine
+	body     string
+	status   int
+	envelope map[string]any
+}
+
+func (suite *UpgradeSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+
+	// Every route answers whatever body the case sets, in the V1 shape
+	respond := func(c *gin.Context) {
+		if suite.envelope != nil {
+			SetEnvelope(c, suite.envelope)
+		}
+		c.Data(suite.status, "application/json", []byte(suite.body))
+	}
+	for _, version := range Versions {
+		group := Group(suite.router, version)
+		group.GET("/auction/:auctionId", respond)
+		group.GET("/auction", respond)
+		group.GET("/bid/:auctionId", respond)
+	}
+}
+
+func (suite *UpgradeSuite) get(version Version, path string) string {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, Prefix(version)+path, nil)
+	suite.router.ServeHTTP(recorder, request)
+
+	assert.Equal(suite.T(), suite.status, recorder.Code)
+	return recorder.Body.String()
+}
+
+func (suite *UpgradeSuite) TestAuctionEndTimeInEachVersion() {
+	cases := []struct {
+		name    string
+		version Version
+		path    string
+		status  int
+		body    string
+		want    string
+	}{
+		{
+			name:    "v1 is served as it is",
+			version: V1,
+			path:    "/auction/1",
+			status:  http.StatusOK,
+			body:    `{"id":"1","status":4,"end_time":"2024-01-02T00:00:00Z"}`,
+			want:    `{"id":"1","status":4,"end_time":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:    "active auctions keep their end_time",
+			version: V2,
+			path:    "/auction/1",
+			status:  http.StatusOK,
+			body:    `{"id":"1","status":0,"end_time":"2024-01-02T00:00:00Z"}`,
+			want: `{"id":"1","status":0,"end_time":"2024-01-02T00:00:00Z",` +
+				`"scheduled_end_time":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:    "paused auctions have no end_time",
+			version: V2,
+			path:    "/auction/1",
+			status:  http.StatusOK,
+			body:    `{"id":"1","status":4,"end_time":"2024-01-02T00:00:00Z"}`,
+			want:    `{"id":"1","status":4,"end_time":null,"scheduled_end_time":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:    "cancelled auctions ended when they were cancelled",
+			version: V2,
+			path:    "/auction/1",
+			status:  http.StatusOK,
+			body: `{"id":"1","status":2,"end_time":"2024-01-02T00:00:00Z",` +
+				`"cancelled_at":"2024-01-01T12:00:00Z"}`,
+			want: `{"id":"1","status":2,"end_time":"2024-01-01T12:00:00Z",` +
+				`"cancelled_at":"2024-01-01T12:00:00Z","scheduled_end_time":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:    "v1 lists are arrays",
+			version: V1,
+			path:    "/auction",
+			status:  http.StatusOK,
+			body:    `[{"status":4,"end_time":"2024-01-02T00:00:00Z"}]`,
+			want:    `[{"status":4,"end_time":"2024-01-02T00:00:00Z"}]`,
+		},
+		{
+			name:    "every auction of a list is upgraded",
+			version: V2,
+			path:    "/auction",
+			status:  http.StatusOK,
+			body: `[{"status":4,"end_time":"2024-01-02T00:00:00Z","price":10.50},` +
+				`{"status":0,"end_time":"2024-01-03T00:00:00Z","price":12345678901234567890}]`,
+			want: `{"auctions":[{"status":4,"end_time":null,"scheduled_end_time":"2024-01-02T00:00:00Z",` +
+				`"price":10.50},{"status":0,"end_time":"2024-01-03T00:00:00Z",` +
+				`"scheduled_end_time":"2024-01-03T00:00:00Z","price":12345678901234567890}]}`,
+		},
+		{
+			name:    "routes without changes are left alone",
+			version: V2,
+			path:    "/bid/1",
+			status:  http.StatusOK,
+			body:    `{"status":4,"end_time":"2024-01-02T00:00:00Z"}`,
+			want:    `{"status":4,"end_time":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:    "errors have no auction to upgrade",
+			version: V2,
+			path:    "/auction/1",
+			status:  http.StatusNotFound,
+			body:    `{"message":"Auction not found","err":"not_found","code":404}`,
+			want:    `{"message":"Auction not found","err":"not_found","code":404}`,
+		},
+		{
+			name:    "empty bodies stay empty",
+			version: V2,
+			path:    "/auction/1",
+			status:  http.StatusOK,
+			body:    ``,
+			want:    ``,
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			suite.status = tc.status
+			suite.body = tc.body
+
+			got := suite.get(tc.version, tc.path)
+			if tc.want == "" {
+				assert.Empty(suite.T(), got)
+				return
+			}
+			assert.JSONEq(suite.T(), tc.want, got)
+		})
+	}
+}
+
+func (suite *UpgradeSuite) TestNumbersAreWrittenBackAsTheyCame() {
+	suite.status = http.StatusOK
+	suite.body = `{"status":4,"end_time":"2024-01-02T00:00:00Z","amount":0.1000,"count":9007199254740993}`
+
+	var got map[string]json.RawMessage
+	err := json.Unmarshal([]byte(suite.get(V2, "/auction/1")), &got)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "0.1000", string(got["amount"]))
+	assert.Equal(suite.T(), "9007199254740993", string(got["count"]))
+}
+
+func (suite *UpgradeSuite) TestListsCarryTheirPageInTheEnvelope() {
+	suite.status = http.StatusOK
+	suite.body = `[{"id":"1"}]`
+	suite.envelope = map[string]any{"next_cursor": "abc"}
+	defer func() { suite.envelope = nil }()
+
+	assert.JSONEq(suite.T(), `[{"id":"1"}]`, suite.get(V1, "/auction"))
+	assert.JSONEq(suite.T(), `{"auctions":[{"id":"1"}],"next_cursor":"abc"}`, suite.get(V2, "/auction"))
+
+	// Errors aren't wrapped
+	suite.status = http.StatusBadRequest
+	suite.body = `{"message":"Invalid fields","err":"bad_request","code":400}`
+	assert.JSONEq(suite.T(), suite.body, suite.get(V2, "/auction"))
+}
+
+func (suite *UpgradeSuite) TestChangesAreDocumentedForLaterVersions() {
+	assert.Empty(suite.T(), Changes(V1, "GET /auction/:auctionId"))
+	assert.Len(suite.T(), Changes(V2, "GET /auction/:auctionId"), 1)
+	assert.Len(suite.T(), Changes(V2, "GET /auction"), 2)
+	assert.Empty(suite.T(), Changes(V2, "GET /bid/:auctionId"))
+}
+
+func (suite *UpgradeSuite) TestRoutePathAndVersion() {
+	assert.Equal(suite.T(), "/auction/:auctionId", RoutePath("/api/v2/auction/:auctionId"))
+	assert.Equal(suite.T(), "/", RoutePath("/api/v1"))
+
+	version, ok := RouteVersion("/api/v2/auction")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), V2, version)
+
+	_, ok = RouteVersion("/metrics")
+	assert.False(suite.T(), ok)
+}
+
+func TestUpgradeSuite(t *testing.T) {
+	suite.Run(t, new(UpgradeSuite))
+}
@@ -0,0 +1,62 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version of the HTTP API. Controllers always answer in the V1 shape, the
+// changes of later versions are applied to their responses on the way out
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+// Versions are served side by side, oldest first
+var Versions = []Version{V1, V2}
+
+// Prefix is where the version's routes are mounted
+func Prefix(version Version) string {
+	return fmt.Sprintf("/api/v%d", version)
+}
+
+// Group mounts a version under its prefix. Routes registered on it answer
+// in that version's shape
+func Group(router *gin.Engine, version Version) *gin.RouterGroup {
+	var upgrades []change
+	for _, change := range changes {
+		if change.version <= version {
+			upgrades = append(upgrades, change)
+		}
+	}
+
+	return router.Group(Prefix(version), upgradeResponses(upgrades))
+}
+
+var prefixPattern = regexp.MustCompile(`^/api/v([0-9]+)(/|$)`)
+
+// RoutePath is the route's path without its version prefix, the same in
+// every version
+func RoutePath(fullPath string) string {
+	return prefixPattern.ReplaceAllString(fullPath, "/")
+}
+
+// RouteVersion is the version the route was mounted under, false for the
+// routes mounted outside of them
+func RouteVersion(fullPath string) (Version, bool) {
+	match := prefixPattern.FindStringSubmatch(fullPath)
+	if match == nil {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return Version(version), true
+}
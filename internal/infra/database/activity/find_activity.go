@@ -0,0 +1,133 @@
+package activity
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/activity_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type ActivityEntityMongo struct {
+	Type        string  `bson:"type"`
+	ReferenceId string  `bson:"reference_id"`
+	AuctionId   string  `bson:"auction_id"`
+	Amount      float64 `bson:"amount,omitempty"`
+	Timestamp   int64   `bson:"timestamp"`
+}
+
+type activityPageMongo struct {
+	Entries []ActivityEntityMongo `bson:"entries"`
+	Total   []struct {
+		Count int64 `bson:"count"`
+	} `bson:"total"`
+}
+
+// ActivityRepository reads the timeline straight from the auctions and bids
+// collections, nothing is stored for it
+type ActivityRepository struct {
+	auctionCollection *mongo.Collection
+	bidCollection     *mongo.Collection
+}
+
+func NewActivityRepository(database *mongo.Database) *ActivityRepository {
+	return &ActivityRepository{
+		auctionCollection: database.Collection("auctions"),
+		bidCollection:     database.Collection("bids"),
+	}
+}
+
+// FindActivityByUserId merges the auctions the user created, their bids and
+// the auctions they won in one pipeline. Each branch is served by its own
+// index: auctions (seller_id, timestamp), bids (user_id, timestamp) and
+// auctions (winner_user_id, end_time)
+func (ar *ActivityRepository) FindActivityByUserId(
+	ctx context.Context,
+	userId string,
+	skip, limit int64) ([]activity_entity.Activity, int64, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"seller_id": userId}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"type":         bson.M{"$literal": activity_entity.AuctionCreated},
+			"reference_id": "$_id",
+			"auction_id":   "$_id",
+			"timestamp":    "$timestamp",
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": ar.bidCollection.Name(),
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"user_id": userId}},
+				bson.M{"$project": bson.M{
+					"_id":          0,
+					"type":         bson.M{"$literal": activity_entity.BidPlaced},
+					"reference_id": "$_id",
+					"auction_id":   "$auction_id",
+					"amount":       "$amount",
+					"timestamp":    "$timestamp",
+				}},
+			},
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": ar.auctionCollection.Name(),
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"winner_user_id": userId, "status": auction_entity.Completed}},
+				bson.M{"$project": bson.M{
+					"_id":          0,
+					"type":         bson.M{"$literal": activity_entity.AuctionWon},
+					"reference_id": "$_id",
+					"auction_id":   "$_id",
+					"amount":       "$winning_amount",
+					"timestamp":    "$end_time",
+				}},
+			},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "timestamp", Value: -1},
+			{Key: "type", Value: 1},
+			{Key: "reference_id", Value: 1},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"entries": bson.A{bson.M{"$skip": skip}, bson.M{"$limit": limit}},
+			"total":   bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+
+	cursor, err := ar.auctionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find user activity", err, zap.String("userID", userId))
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find user activity")
+	}
+	defer cursor.Close(ctx)
+
+	var pages []activityPageMongo
+	if err := cursor.All(ctx, &pages); err != nil {
+		logger.Error("Error trying to decode user activity", err, zap.String("userID", userId))
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find user activity")
+	}
+
+	activities := []activity_entity.Activity{}
+	var total int64
+	if len(pages) > 0 {
+		if len(pages[0].Total) > 0 {
+			total = pages[0].Total[0].Count
+		}
+
+		for _, entry := range pages[0].Entries {
+			activities = append(activities, activity_entity.Activity{
+				Type:        activity_entity.ActivityType(entry.Type),
+				ReferenceId: entry.ReferenceId,
+				AuctionId:   entry.AuctionId,
+				Amount:      entry.Amount,
+				Timestamp:   time.Unix(entry.Timestamp, 0),
+			})
+		}
+	}
+
+	return activities, total, nil
+}
@@ -0,0 +1,180 @@
+package admin
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type BulkStatusFilterMongo struct {
+	Category      string `bson:"category,omitempty"`
+	SellerId      string `bson:"seller_id,omitempty"`
+	CreatedBefore int64  `bson:"created_before,omitempty"`
+}
+
+type BulkStatusJobEntityMongo struct {
+	Id          string                 `bson:"_id"`
+	Action      string                 `bson:"action"`
+	Filter      BulkStatusFilterMongo  `bson:"filter"`
+	Reason      string                 `bson:"reason"`
+	RequestedBy string                 `bson:"requested_by"`
+	Status      admin_entity.JobStatus `bson:"status"`
+	Total       int64                  `bson:"total"`
+	Processed   int64                  `bson:"processed"`
+	Changed     int64                  `bson:"changed"`
+	Error       string                 `bson:"error,omitempty"`
+	Timestamp   int64                  `bson:"timestamp"`
+	FinishedAt  int64                  `bson:"finished_at,omitempty"`
+}
+
+type AuditEntryEntityMongo struct {
+	Id              string                       `bson:"_id"`
+	JobId           string                       `bson:"job_id,omitempty"`
+	AuctionId       string                       `bson:"auction_id"`
+	Action          string                       `bson:"action"`
+	PreviousStatus  auction_entity.AuctionStatus `bson:"previous_status"`
+	NewStatus       auction_entity.AuctionStatus `bson:"new_status"`
+	PreviousEndTime int64                        `bson:"previous_end_time,omitempty"`
+	NewEndTime      int64                        `bson:"new_end_time,omitempty"`
+	Reason          string                       `bson:"reason"`
+	RequestedBy     string                       `bson:"requested_by"`
+	Timestamp       int64                        `bson:"timestamp"`
+}
+
+type AdminRepository struct {
+	JobCollection   *mongo.Collection
+	AuditCollection *mongo.Collection
+}
+
+func NewAdminRepository(database *mongo.Database) *AdminRepository {
+	return &AdminRepository{
+		JobCollection:   database.Collection("admin_jobs"),
+		AuditCollection: database.Collection("admin_audit_log"),
+	}
+}
+
+func (ar *AdminRepository) CreateBulkStatusJob(
+	ctx context.Context,
+	jobEntity *admin_entity.BulkStatusJob) *internal_error.InternalError {
+	if _, err := ar.JobCollection.InsertOne(ctx, toBulkStatusJobEntityMongo(jobEntity)); err != nil {
+		logger.Error("Error trying to insert admin job", err)
+		return internal_error.NewInternalServerError("Error trying to insert admin job")
+	}
+
+	return nil
+}
+
+func (ar *AdminRepository) UpdateBulkStatusJob(
+	ctx context.Context,
+	jobEntity *admin_entity.BulkStatusJob) *internal_error.InternalError {
+	filter := bson.M{"_id": jobEntity.Id}
+
+	if _, err := ar.JobCollection.ReplaceOne(ctx, filter, toBulkStatusJobEntityMongo(jobEntity)); err != nil {
+		logger.Error("Error trying to update admin job", err)
+		return internal_error.NewInternalServerError("Error trying to update admin job")
+	}
+
+	return nil
+}
+
+func (ar *AdminRepository) CreateAuditEntries(
+	ctx context.Context,
+	auditEntries []admin_entity.AuditEntry) *internal_error.InternalError {
+	if len(auditEntries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(auditEntries))
+	for _, auditEntry := range auditEntries {
+		auditEntryMongo := &AuditEntryEntityMongo{
+			Id:             auditEntry.Id,
+			JobId:          auditEntry.JobId,
+			AuctionId:      auditEntry.AuctionId,
+			Action:         auditEntry.Action,
+			PreviousStatus: auditEntry.PreviousStatus,
+			NewStatus:      auditEntry.NewStatus,
+			Reason:         auditEntry.Reason,
+			RequestedBy:    auditEntry.RequestedBy,
+			Timestamp:      auditEntry.Timestamp.Unix(),
+		}
+		if !auditEntry.PreviousEndTime.IsZero() {
+			auditEntryMongo.PreviousEndTime = auditEntry.PreviousEndTime.Unix()
+		}
+		if !auditEntry.NewEndTime.IsZero() {
+			auditEntryMongo.NewEndTime = auditEntry.NewEndTime.Unix()
+		}
+
+		documents = append(documents, auditEntryMongo)
+	}
+
+	if _, err := ar.AuditCollection.InsertMany(ctx, documents); err != nil {
+		logger.Error("Error trying to insert audit entries", err)
+		return internal_error.NewInternalServerError("Error trying to insert audit entries")
+	}
+
+	return nil
+}
+
+func toBulkStatusJobEntityMongo(jobEntity *admin_entity.BulkStatusJob) *BulkStatusJobEntityMongo {
+	jobEntityMongo := &BulkStatusJobEntityMongo{
+		Id:     jobEntity.Id,
+		Action: jobEntity.Action,
+		Filter: BulkStatusFilterMongo{
+			Category: jobEntity.Filter.Category,
+			SellerId: jobEntity.Filter.SellerId,
+		},
+		Reason:      jobEntity.Reason,
+		RequestedBy: jobEntity.RequestedBy,
+		Status:      jobEntity.Status,
+		Total:       jobEntity.Total,
+		Processed:   jobEntity.Processed,
+		Changed:     jobEntity.Changed,
+		Error:       jobEntity.Error,
+		Timestamp:   jobEntity.Timestamp.Unix(),
+	}
+
+	if !jobEntity.Filter.CreatedBefore.IsZero() {
+		jobEntityMongo.Filter.CreatedBefore = jobEntity.Filter.CreatedBefore.Unix()
+	}
+
+	if !jobEntity.FinishedAt.IsZero() {
+		jobEntityMongo.FinishedAt = jobEntity.FinishedAt.Unix()
+	}
+
+	return jobEntityMongo
+}
+
+func toBulkStatusJobEntity(jobEntityMongo BulkStatusJobEntityMongo) *admin_entity.BulkStatusJob {
+	jobEntity := &admin_entity.BulkStatusJob{
+		Id:     jobEntityMongo.Id,
+		Action: jobEntityMongo.Action,
+		Filter: admin_entity.BulkStatusFilter{
+			Category: jobEntityMongo.Filter.Category,
+			SellerId: jobEntityMongo.Filter.SellerId,
+		},
+		Reason:      jobEntityMongo.Reason,
+		RequestedBy: jobEntityMongo.RequestedBy,
+		Status:      jobEntityMongo.Status,
+		Total:       jobEntityMongo.Total,
+		Processed:   jobEntityMongo.Processed,
+		Changed:     jobEntityMongo.Changed,
+		Error:       jobEntityMongo.Error,
+		Timestamp:   time.Unix(jobEntityMongo.Timestamp, 0),
+	}
+
+	if jobEntityMongo.Filter.CreatedBefore != 0 {
+		jobEntity.Filter.CreatedBefore = time.Unix(jobEntityMongo.Filter.CreatedBefore, 0)
+	}
+
+	if jobEntityMongo.FinishedAt != 0 {
+		jobEntity.FinishedAt = time.Unix(jobEntityMongo.FinishedAt, 0)
+	}
+
+	return jobEntity
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (ar *AdminRepository) FindBulkStatusJobById(
+	ctx context.Context, id string) (*admin_entity.BulkStatusJob, *internal_error.InternalError) {
+	filter := bson.M{"_id": id}
+
+	var jobEntityMongo BulkStatusJobEntityMongo
+	if err := ar.JobCollection.FindOne(ctx, filter).Decode(&jobEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Admin job not found with this id = %s", id))
+		}
+
+		logger.Error("Error trying to find admin job", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find admin job")
+	}
+
+	return toBulkStatusJobEntity(jobEntityMongo), nil
+}
+
+func (ar *AdminRepository) FindAuditEntriesByJobId(
+	ctx context.Context, jobId string) ([]admin_entity.AuditEntry, *internal_error.InternalError) {
+	return ar.findAuditEntries(ctx, bson.M{"job_id": jobId})
+}
+
+func (ar *AdminRepository) FindAuditEntriesByAuctionId(
+	ctx context.Context, auctionId string) ([]admin_entity.AuditEntry, *internal_error.InternalError) {
+	return ar.findAuditEntries(ctx, bson.M{"auction_id": auctionId})
+}
+
+func (ar *AdminRepository) findAuditEntries(
+	ctx context.Context, filter bson.M) ([]admin_entity.AuditEntry, *internal_error.InternalError) {
+	cursor, err := ar.AuditCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		logger.Error("Error trying to find audit entries", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find audit entries")
+	}
+	defer cursor.Close(ctx)
+
+	var auditEntriesMongo []AuditEntryEntityMongo
+	if err := cursor.All(ctx, &auditEntriesMongo); err != nil {
+		logger.Error("Error trying to decode audit entries", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find audit entries")
+	}
+
+	var auditEntries []admin_entity.AuditEntry
+	for _, auditEntry := range auditEntriesMongo {
+		auditEntryEntity := admin_entity.AuditEntry{
+			Id:             auditEntry.Id,
+			JobId:          auditEntry.JobId,
+			AuctionId:      auditEntry.AuctionId,
+			Action:         auditEntry.Action,
+			PreviousStatus: auditEntry.PreviousStatus,
+			NewStatus:      auditEntry.NewStatus,
+			Reason:         auditEntry.Reason,
+			RequestedBy:    auditEntry.RequestedBy,
+			Timestamp:      time.Unix(auditEntry.Timestamp, 0),
+		}
+		if auditEntry.PreviousEndTime != 0 {
+			auditEntryEntity.PreviousEndTime = time.Unix(auditEntry.PreviousEndTime, 0)
+		}
+		if auditEntry.NewEndTime != 0 {
+			auditEntryEntity.NewEndTime = time.Unix(auditEntry.NewEndTime, 0)
+		}
+
+		auditEntries = append(auditEntries, auditEntryEntity)
+	}
+
+	return auditEntries, nil
+}
@@ -0,0 +1,47 @@
+package announcement
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/announcement_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AnnouncementEntityMongo struct {
+	Id         string `bson:"_id"`
+	AuctionId  string `bson:"auction_id"`
+	Message    string `bson:"message"`
+	ReplacesId string `bson:"replaces_id,omitempty"`
+	Timestamp  int64  `bson:"timestamp"`
+}
+
+type AnnouncementRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewAnnouncementRepository(database *mongo.Database) *AnnouncementRepository {
+	return &AnnouncementRepository{
+		Collection: database.Collection("auction_announcements"),
+	}
+}
+
+func (ar *AnnouncementRepository) CreateAnnouncement(
+	ctx context.Context,
+	announcementEntity *announcement_entity.Announcement) *internal_error.InternalError {
+	announcementEntityMongo := &AnnouncementEntityMongo{
+		Id:         announcementEntity.Id,
+		AuctionId:  announcementEntity.AuctionId,
+		Message:    announcementEntity.Message,
+		ReplacesId: announcementEntity.ReplacesId,
+		Timestamp:  announcementEntity.Timestamp.Unix(),
+	}
+
+	if _, err := ar.Collection.InsertOne(ctx, announcementEntityMongo); err != nil {
+		logger.Error("Error trying to insert announcement", err)
+		return internal_error.NewInternalServerError("Error trying to insert announcement")
+	}
+
+	return nil
+}
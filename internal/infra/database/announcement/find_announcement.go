@@ -0,0 +1,72 @@
+package announcement
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/announcement_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (ar *AnnouncementRepository) FindAnnouncementById(
+	ctx context.Context, id string) (*announcement_entity.Announcement, *internal_error.InternalError) {
+	filter := bson.M{"_id": id}
+
+	var announcementEntityMongo AnnouncementEntityMongo
+	if err := ar.Collection.FindOne(ctx, filter).Decode(&announcementEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Announcement not found with this id = %s", id))
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find announcement by id = %s", id), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find announcement by id")
+	}
+
+	return toAnnouncementEntity(announcementEntityMongo), nil
+}
+
+func (ar *AnnouncementRepository) FindAnnouncementsByAuctionId(
+	ctx context.Context, auctionId string) ([]announcement_entity.Announcement, *internal_error.InternalError) {
+	filter := bson.M{"auction_id": auctionId}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find announcements by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find announcements by auctionId %s", auctionId))
+	}
+	defer cursor.Close(ctx)
+
+	var announcementsMongo []AnnouncementEntityMongo
+	if err := cursor.All(ctx, &announcementsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode announcements by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find announcements by auctionId %s", auctionId))
+	}
+
+	var announcements []announcement_entity.Announcement
+	for _, announcementMongo := range announcementsMongo {
+		announcements = append(announcements, *toAnnouncementEntity(announcementMongo))
+	}
+
+	return announcements, nil
+}
+
+func toAnnouncementEntity(
+	announcementMongo AnnouncementEntityMongo) *announcement_entity.Announcement {
+	return &announcement_entity.Announcement{
+		Id:         announcementMongo.Id,
+		AuctionId:  announcementMongo.AuctionId,
+		Message:    announcementMongo.Message,
+		ReplacesId: announcementMongo.ReplacesId,
+		Timestamp:  time.Unix(announcementMongo.Timestamp, 0),
+	}
+}
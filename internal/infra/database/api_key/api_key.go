@@ -0,0 +1,212 @@
+package api_key
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ApiKeyMongo is looked up by hash on every request made with the key, the
+// hash has a unique index
+type ApiKeyMongo struct {
+	Id        string   `bson:"_id"`
+	UserId    string   `bson:"user_id"`
+	Name      string   `bson:"name"`
+	Prefix    string   `bson:"prefix"`
+	Hash      string   `bson:"hash"`
+	Scopes    []string `bson:"scopes"`
+	CreatedAt int64    `bson:"created_at"`
+	RotatedAt int64    `bson:"rotated_at,omitempty"`
+}
+
+type ApiKeyRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewApiKeyRepository(database *mongo.Database) *ApiKeyRepository {
+	repo := &ApiKeyRepository{
+		Collection: database.Collection("api_keys"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the api_keys collection
+func (ar *ApiKeyRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, ar.Collection.Database(), "api_keys"); err != nil {
+		logger.Error("Error trying to create API key indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create API key indexes")
+	}
+
+	return nil
+}
+
+func (ar *ApiKeyRepository) CreateApiKey(
+	ctx context.Context, apiKey *api_key_entity.ApiKey) *internal_error.InternalError {
+	if _, err := ar.Collection.InsertOne(ctx, toApiKeyMongo(apiKey)); err != nil {
+		logger.Error("Error trying to insert API key", err, zap.String("userID", apiKey.UserId))
+		return internal_error.NewInternalServerError("Error trying to insert API key")
+	}
+
+	return nil
+}
+
+// FindApiKeysByUserId lists the newest keys first
+func (ar *ApiKeyRepository) FindApiKeysByUserId(
+	ctx context.Context, userId string) ([]api_key_entity.ApiKey, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := ar.Collection.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find API keys", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find API keys")
+	}
+	defer cursor.Close(ctx)
+
+	var apiKeysMongo []ApiKeyMongo
+	if err := cursor.All(ctx, &apiKeysMongo); err != nil {
+		logger.Error("Error trying to decode API keys", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find API keys")
+	}
+
+	apiKeys := make([]api_key_entity.ApiKey, 0, len(apiKeysMongo))
+	for _, apiKeyMongo := range apiKeysMongo {
+		apiKeys = append(apiKeys, *toApiKeyEntity(apiKeyMongo))
+	}
+
+	return apiKeys, nil
+}
+
+// FindApiKeyById only finds the key among the user's own
+func (ar *ApiKeyRepository) FindApiKeyById(
+	ctx context.Context, userId, keyId string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	return ar.findApiKey(ctx, bson.M{"_id": keyId, "user_id": userId},
+		fmt.Sprintf("API key not found with this id = %s", keyId))
+}
+
+func (ar *ApiKeyRepository) FindApiKeyByHash(
+	ctx context.Context, hash string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	return ar.findApiKey(ctx, bson.M{"hash": hash}, "API key not found")
+}
+
+func (ar *ApiKeyRepository) findApiKey(
+	ctx context.Context, filter bson.M, notFound string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	var apiKeyMongo ApiKeyMongo
+	if err := ar.Collection.FindOne(ctx, filter).Decode(&apiKeyMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(notFound)
+		}
+
+		logger.Error("Error trying to find API key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find API key")
+	}
+
+	return toApiKeyEntity(apiKeyMongo), nil
+}
+
+func (ar *ApiKeyRepository) CountApiKeysByUserId(
+	ctx context.Context, userId string) (int64, *internal_error.InternalError) {
+	count, err := ar.Collection.CountDocuments(ctx, bson.M{"user_id": userId})
+	if err != nil {
+		logger.Error("Error trying to count API keys", err, zap.String("userID", userId))
+		return 0, internal_error.NewInternalServerError("Error trying to count API keys")
+	}
+
+	return count, nil
+}
+
+func (ar *ApiKeyRepository) RotateApiKey(
+	ctx context.Context, apiKey *api_key_entity.ApiKey) *internal_error.InternalError {
+	filter := bson.M{"_id": apiKey.Id, "user_id": apiKey.UserId}
+	update := bson.M{"$set": bson.M{
+		"hash":       apiKey.Hash,
+		"prefix":     apiKey.Prefix,
+		"rotated_at": apiKey.RotatedAt.Unix(),
+	}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to rotate API key", err, zap.String("keyID", apiKey.Id))
+		return internal_error.NewInternalServerError("Error trying to rotate API key")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("API key not found with this id = %s", apiKey.Id))
+	}
+
+	return nil
+}
+
+func (ar *ApiKeyRepository) DeleteApiKey(
+	ctx context.Context, userId, keyId string) *internal_error.InternalError {
+	result, err := ar.Collection.DeleteOne(ctx, bson.M{"_id": keyId, "user_id": userId})
+	if err != nil {
+		logger.Error("Error trying to delete API key", err, zap.String("keyID", keyId))
+		return internal_error.NewInternalServerError("Error trying to delete API key")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("API key not found with this id = %s", keyId))
+	}
+
+	return nil
+}
+
+func toApiKeyMongo(apiKey *api_key_entity.ApiKey) *ApiKeyMongo {
+	scopes := make([]string, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	apiKeyMongo := &ApiKeyMongo{
+		Id:        apiKey.Id,
+		UserId:    apiKey.UserId,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Hash:      apiKey.Hash,
+		Scopes:    scopes,
+		CreatedAt: apiKey.CreatedAt.Unix(),
+	}
+	if !apiKey.RotatedAt.IsZero() {
+		apiKeyMongo.RotatedAt = apiKey.RotatedAt.Unix()
+	}
+
+	return apiKeyMongo
+}
+
+func toApiKeyEntity(apiKeyMongo ApiKeyMongo) *api_key_entity.ApiKey {
+	scopes := make([]api_key_entity.Scope, 0, len(apiKeyMongo.Scopes))
+	for _, scope := range apiKeyMongo.Scopes {
+		scopes = append(scopes, api_key_entity.Scope(scope))
+	}
+
+	apiKey := &api_key_entity.ApiKey{
+		Id:        apiKeyMongo.Id,
+		UserId:    apiKeyMongo.UserId,
+		Name:      apiKeyMongo.Name,
+		Prefix:    apiKeyMongo.Prefix,
+		Hash:      apiKeyMongo.Hash,
+		Scopes:    scopes,
+		CreatedAt: time.Unix(apiKeyMongo.CreatedAt, 0),
+	}
+	if apiKeyMongo.RotatedAt > 0 {
+		apiKey.RotatedAt = time.Unix(apiKeyMongo.RotatedAt, 0)
+	}
+
+	return apiKey
+}
@@ -2,61 +2,53 @@ package auction
 
 import (
 	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/testhelpers"
 	"context"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AuctionRepositorySuite struct {
 	suite.Suite
-	repo       *AuctionRepository
-	client     *mongo.Client
-	database   *mongo.Database
-	collection *mongo.Collection
+	repo               *AuctionRepository
+	database           *mongo.Database
+	collection         *mongo.Collection
+	closeJobCollection *mongo.Collection
 }
 
 func (suite *AuctionRepositorySuite) SetupSuite() {
 	// Set auction interval to a very short duration for testing
 	os.Setenv("AUCTION_INTERVAL", "2s")
 
-	// Setup MongoDB connection
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
-	if err != nil {
-		suite.T().Fatal(err)
-	}
-	suite.client = client
-	suite.database = client.Database("auction_test")
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
 	suite.collection = suite.database.Collection("auctions")
+	suite.closeJobCollection = suite.database.Collection("auction_close_jobs")
 
 	// Create a new auction repository
 	suite.repo = NewAuctionRepository(suite.database)
 }
 
 func (suite *AuctionRepositorySuite) TearDownSuite() {
-	// Clean up and close the MongoDB connection
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	
+	// The database itself is dropped by the test helper
 	suite.repo.Close()
-	suite.collection.Drop(ctx)
-	suite.client.Disconnect(ctx)
 }
 
 func (suite *AuctionRepositorySuite) SetupTest() {
-	// Clean the collection before each test
+	// Empty the collection before each test, keeping its indexes
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	
-	suite.collection.Drop(ctx)
+
+	suite.collection.DeleteMany(ctx, bson.M{})
+	suite.closeJobCollection.DeleteMany(ctx, bson.M{})
 }
 
 func (suite *AuctionRepositorySuite) TestCreateAuction() {
@@ -66,6 +58,8 @@ func (suite *AuctionRepositorySuite) TestCreateAuction() {
 		"Electronics",
 		"This is a test product description for testing purposes",
 		auction_entity.New,
+		time.Time{},
+		time.Hour,
 	)
 	assert.Nil(suite.T(), err)
 
@@ -80,20 +74,36 @@ func (suite *AuctionRepositorySuite) TestCreateAuction() {
 	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
+	assert.Equal(suite.T(), time.Hour, savedAuction.Duration)
+	assert.Equal(suite.T(), auction.Timestamp.Add(time.Hour).Unix(), savedAuction.EndTime.Unix())
+}
+
+func (suite *AuctionRepositorySuite) TestAuctionOwnDurationOutlivesInterval() {
+	// Past the 2 second interval, but well within its own duration
+	auction := testhelpers.AnAuction().
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		WithDuration(time.Hour).
+		Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	suite.repo.closeExpiredAuctions()
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
 }
 
 func (suite *AuctionRepositorySuite) TestAuctionAutoClose() {
 	// Create a test auction with timestamp set to expire VERY soon
-	auction := &auction_entity.Auction{
-		Id:          "test-auction-123",
-		ProductName: "Test Product",
-		Category:    "Electronics",
-		Description: "This is a test product description for testing purposes",
-		Condition:   auction_entity.New,
-		Status:      auction_entity.Active,
-		// Set timestamp to 3 seconds in the past, which is beyond our 2 second interval
-		Timestamp:   time.Now().Add(-3 * time.Second),
-	}
+	// Set timestamp to 3 seconds in the past, which is beyond our 2 second interval
+	auction := testhelpers.AnAuction().
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		Build()
 
 	// Save the auction
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -124,39 +134,28 @@ func (suite *AuctionRepositorySuite) TestLoadActiveAuctions() {
 	defer cancel()
 
 	// Auction 1: Active and not expired - ensure it's VERY recent
-	auction1 := &auction_entity.Auction{
-		Id:          "test-auction-active",
-		ProductName: "Active Product",
-		Category:    "Electronics",
-		Description: "This is an active product that should remain active",
-		Condition:   auction_entity.New,
-		Status:      auction_entity.Active,
-		// Set timestamp to current time to ensure it won't expire during test
-		Timestamp:   time.Now(),
-	}
+	// Set timestamp to current time to ensure it won't expire during test
+	auction1 := testhelpers.AnAuction().
+		WithProductName("Active Product").
+		WithTimestamp(time.Now()).
+		Build()
 	
 	err := suite.repo.CreateAuction(ctx, auction1)
 	assert.Nil(suite.T(), err)
 
 	// Auction 2: Active but should be expired (timestamp in the past)
-	auction2 := &auction_entity.Auction{
-		Id:          "test-auction-expired",
-		ProductName: "Expired Product",
-		Category:    "Electronics",
-		Description: "This is a product that should be closed when loading",
-		Condition:   auction_entity.New,
-		Status:      auction_entity.Active,
-		// Set to 5 seconds in the past, well beyond our interval
-		Timestamp:   time.Now().Add(-5 * time.Second), 
-	}
+	// Set to 5 seconds in the past, well beyond our interval
+	auction2 := testhelpers.AnAuction().
+		WithProductName("Expired Product").
+		WithTimestamp(time.Now().Add(-5 * time.Second)).
+		Build()
 	
 	err = suite.repo.CreateAuction(ctx, auction2)
 	assert.Nil(suite.T(), err)
 
-	// Clear the active auctions map to test reloading
-	suite.repo.auctionsMutex.Lock()
-	suite.repo.activeAuctions = make(map[string]time.Time)
-	suite.repo.auctionsMutex.Unlock()
+	// Clear the close jobs to test reloading
+	_, errDelete := suite.closeJobCollection.DeleteMany(ctx, bson.M{})
+	assert.Nil(suite.T(), errDelete)
 
 	// Load active auctions from database
 	ctxLoad, cancelLoad := context.WithTimeout(context.Background(), 5*time.Second)
@@ -185,6 +184,862 @@ func (suite *AuctionRepositorySuite) TestLoadActiveAuctions() {
 	assert.Equal(suite.T(), auction_entity.Completed, savedAuction2.Status)
 }
 
+func (suite *AuctionRepositorySuite) TestUpdateAuctionEndTime() {
+	// Create an auction that would expire with the regular 2 second interval
+	auction := testhelpers.AnAuction().
+		WithProductName("Extended Product").
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	// Push the end time forward and make sure the closer honors it
+	endTime := time.Now().Add(time.Hour)
+	err = suite.repo.UpdateAuctionEndTime(ctx, auction.Id, endTime)
+	assert.Nil(suite.T(), err)
+
+	suite.repo.closeExpiredAuctions()
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
+	assert.Equal(suite.T(), endTime.Unix(), savedAuction.EndTime.Unix())
+
+	// The stored end time also survives a reload of the active auctions
+	_, errDelete := suite.closeJobCollection.DeleteMany(ctx, bson.M{})
+	assert.Nil(suite.T(), errDelete)
+
+	err = suite.repo.LoadActiveAuctions(ctx)
+	assert.Nil(suite.T(), err)
+
+	var closeJob CloseJobMongo
+	errFind := suite.closeJobCollection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&closeJob)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), endTime.Unix(), closeJob.EndTime)
+
+	// Completed auctions can't be adjusted anymore
+	_, err = suite.repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Active, auction_entity.Completed)
+	assert.Nil(suite.T(), err)
+
+	err = suite.repo.UpdateAuctionEndTime(ctx, auction.Id, endTime)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *AuctionRepositorySuite) TestAuctionCloseRecordsWinner() {
+	auction := testhelpers.AnAuction().
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		Build()
+	winningBid := testhelpers.ABid().ForAuction(auction.Id).WithAmount(250).Build()
+
+	suite.repo.SetWinningBidFinder(func(ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+		return &winningBid, nil
+	})
+	defer suite.repo.SetWinningBidFinder(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	// Running auctions have no winner yet
+	_, err = suite.repo.FindAuctionWinner(ctx, auction.Id)
+	assert.NotNil(suite.T(), err)
+
+	suite.repo.closeExpiredAuctions()
+
+	winner, err := suite.repo.FindAuctionWinner(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), winningBid.UserId, winner.UserId)
+	assert.Equal(suite.T(), 250.0, winner.Amount)
+}
+
+func (suite *AuctionRepositorySuite) TestExtendAuctionEndTimeOnlyMovesForward() {
+	auction := testhelpers.AnAuction().WithDuration(time.Minute).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	extendedEndTime := auction.Timestamp.Add(2 * time.Minute)
+	err = suite.repo.ExtendAuctionEndTime(ctx, auction.Id, extendedEndTime)
+	assert.Nil(suite.T(), err)
+
+	// An extension that arrives late, with an earlier end time, is ignored
+	err = suite.repo.ExtendAuctionEndTime(ctx, auction.Id, auction.Timestamp.Add(90*time.Second))
+	assert.Nil(suite.T(), err)
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), extendedEndTime.Unix(), savedAuction.EndTime.Unix())
+
+	var closeJob CloseJobMongo
+	errFind := suite.closeJobCollection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&closeJob)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), extendedEndTime.Unix(), closeJob.EndTime)
+}
+
+func (suite *AuctionRepositorySuite) TestExtendHooksRunOnlyWhenExtended() {
+	// Hooks can't be unregistered, so they go on a repository of their own
+	replica := suite.stoppedReplica()
+
+	auction := testhelpers.AnAuction().WithDuration(time.Minute).Build()
+
+	var extendedTo []time.Time
+	replica.RegisterOnExtendHook(func(ctx context.Context, auctionId string, endTime time.Time) {
+		extendedTo = append(extendedTo, endTime)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	extendedEndTime := auction.Timestamp.Add(2 * time.Minute)
+	err = replica.ExtendAuctionEndTime(ctx, auction.Id, extendedEndTime)
+	assert.Nil(suite.T(), err)
+
+	// An earlier end time doesn't move the auction, so nothing is announced
+	err = replica.ExtendAuctionEndTime(ctx, auction.Id, auction.Timestamp.Add(90*time.Second))
+	assert.Nil(suite.T(), err)
+
+	assert.Equal(suite.T(), []time.Time{extendedEndTime}, extendedTo)
+}
+
+func (suite *AuctionRepositorySuite) TestLoadActiveAuctionsRestoresExtendedEndTime() {
+	auction := testhelpers.AnAuction().WithDuration(time.Minute).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	extendedEndTime := auction.Timestamp.Add(2 * time.Minute)
+	err = suite.repo.ExtendAuctionEndTime(ctx, auction.Id, extendedEndTime)
+	assert.Nil(suite.T(), err)
+
+	// A close job left behind with the original end time
+	_, errUpdate := suite.closeJobCollection.UpdateOne(ctx,
+		bson.M{"_id": auction.Id},
+		bson.M{"$set": bson.M{"end_time": auction.Timestamp.Add(time.Minute).Unix()}})
+	assert.Nil(suite.T(), errUpdate)
+
+	err = suite.repo.LoadActiveAuctions(ctx)
+	assert.Nil(suite.T(), err)
+
+	var closeJob CloseJobMongo
+	errFind := suite.closeJobCollection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&closeJob)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), extendedEndTime.Unix(), closeJob.EndTime)
+}
+
+func (suite *AuctionRepositorySuite) TestBackfillEndTimes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Documents stored before end times were persisted, with and without a
+	// duration of their own
+	timestamp := time.Now().Add(-time.Minute).Unix()
+	_, err := suite.collection.InsertMany(ctx, []interface{}{
+		bson.M{"_id": "legacy-with-duration", "status": auction_entity.Active, "timestamp": timestamp, "duration": 3600},
+		bson.M{"_id": "legacy-without-duration", "status": auction_entity.Active, "timestamp": timestamp},
+	})
+	assert.Nil(suite.T(), err)
+
+	backfilled, errBackfill := suite.repo.BackfillEndTimes(ctx)
+	assert.Nil(suite.T(), errBackfill)
+	assert.Equal(suite.T(), int64(2), backfilled)
+
+	var withDuration, withoutDuration AuctionEntityMongo
+	assert.Nil(suite.T(), suite.collection.FindOne(ctx, bson.M{"_id": "legacy-with-duration"}).Decode(&withDuration))
+	assert.Nil(suite.T(), suite.collection.FindOne(ctx, bson.M{"_id": "legacy-without-duration"}).Decode(&withoutDuration))
+	assert.Equal(suite.T(), timestamp+3600, withDuration.EndTime)
+	assert.Equal(suite.T(), timestamp+2, withoutDuration.EndTime)
+
+	// A second run has nothing left to do
+	backfilled, errBackfill = suite.repo.BackfillEndTimes(ctx)
+	assert.Nil(suite.T(), errBackfill)
+	assert.Equal(suite.T(), int64(0), backfilled)
+}
+
+func (suite *AuctionRepositorySuite) TestCloseJobClaimedByOneReplica() {
+	// Two repositories on the same database stand for two replicas
+	replica := suite.stoppedReplica()
+	otherReplica := suite.stoppedReplica()
+
+	auction := testhelpers.AnAuction().
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	closeJobs, errClaim := replica.claimDueCloseJobs(ctx, 10)
+	assert.Nil(suite.T(), errClaim)
+	assert.Len(suite.T(), closeJobs, 1)
+	assert.Equal(suite.T(), auction.Id, closeJobs[0].AuctionId)
+
+	// The lease keeps the other replica away from the claimed job
+	otherCloseJobs, errClaim := otherReplica.claimDueCloseJobs(ctx, 10)
+	assert.Nil(suite.T(), errClaim)
+	assert.Empty(suite.T(), otherCloseJobs)
+}
+
+func (suite *AuctionRepositorySuite) TestCloseExpiredAuctionsInBatches() {
+	replica := suite.stoppedReplica()
+
+	winningBid := testhelpers.ABid().WithAmount(300).Build()
+	replica.SetWinningBidFinder(func(ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+		if auctionId != winningBid.AuctionId {
+			return nil, internal_error.NewNotFoundError("no bids")
+		}
+		return &winningBid, nil
+	})
+	replica.closeBatchSize = 2
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	auctionIds := []string{}
+	for i := 0; i < 5; i++ {
+		auction := testhelpers.AnAuction().
+			WithTimestamp(time.Now().Add(-3 * time.Second)).
+			Build()
+		err := replica.CreateAuction(ctx, auction)
+		assert.Nil(suite.T(), err)
+		auctionIds = append(auctionIds, auction.Id)
+	}
+	winningBid.AuctionId = auctionIds[0]
+
+	replica.closeExpiredAuctions()
+
+	for _, auctionId := range auctionIds {
+		savedAuction, err := replica.FindAuctionById(ctx, auctionId)
+		assert.Nil(suite.T(), err)
+		assert.Equal(suite.T(), auction_entity.Completed, savedAuction.Status)
+	}
+
+	winner, err := replica.FindAuctionWinner(ctx, auctionIds[0])
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), winningBid.UserId, winner.UserId)
+	assert.Equal(suite.T(), 300.0, winner.Amount)
+
+	_, err = replica.FindAuctionWinner(ctx, auctionIds[1])
+	assert.NotNil(suite.T(), err)
+
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": auctionIds}})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), closeJobs)
+}
+
+func (suite *AuctionRepositorySuite) TestCancelAuction() {
+	auction := testhelpers.AnAuction().Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	err = suite.repo.CancelAuction(ctx, auction.Id, "Item was damaged")
+	assert.Nil(suite.T(), err)
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Cancelled, savedAuction.Status)
+	assert.Equal(suite.T(), "Item was damaged", savedAuction.CancellationReason)
+	assert.False(suite.T(), savedAuction.CancelledAt.IsZero())
+
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), closeJobs)
+
+	// A cancelled auction can't be cancelled again
+	err = suite.repo.CancelAuction(ctx, auction.Id, "Item was damaged")
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *AuctionRepositorySuite) TestScheduledAuctionActivates() {
+	startTime := time.Now().Add(-time.Second)
+	auction := testhelpers.AnAuction().WithStartTime(startTime).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	// Scheduled auctions have no close job until they open
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), closeJobs)
+
+	suite.repo.activateScheduledAuctions()
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
+	assert.Equal(suite.T(), startTime.Unix(), savedAuction.StartTime.Unix())
+	assert.Equal(suite.T(), startTime.Add(suite.repo.auctionInterval).Unix(), savedAuction.EndTime.Unix())
+
+	closeJobs, errCount = suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(1), closeJobs)
+}
+
+func (suite *AuctionRepositorySuite) TestPauseResumeKeepsRemainingTime() {
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	err = suite.repo.PauseAuction(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+
+	// Pretend the auction was paused ten minutes ago
+	pausedAt := time.Now().Add(-10 * time.Minute).Unix()
+	_, errUpdate := suite.collection.UpdateOne(ctx,
+		bson.M{"_id": auction.Id}, bson.M{"$set": bson.M{"paused_at": pausedAt}})
+	assert.Nil(suite.T(), errUpdate)
+
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), closeJobs)
+
+	endTime, err := suite.repo.ResumeAuction(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.InDelta(suite.T(), auction.Timestamp.Add(70*time.Minute).Unix(), endTime.Unix(), 2)
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
+	assert.True(suite.T(), savedAuction.PausedAt.IsZero())
+
+	var closeJob CloseJobMongo
+	errFind := suite.closeJobCollection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&closeJob)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), endTime.Unix(), closeJob.EndTime)
+}
+
+func (suite *AuctionRepositorySuite) TestSchedulerClosesAtEndTime() {
+	// AUCTION_INTERVAL is 2s in this suite, well under the old 10s polling
+	auction := testhelpers.AnAuction().Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	assert.Eventually(suite.T(), func() bool {
+		savedAuction, err := suite.repo.FindAuctionById(context.Background(), auction.Id)
+		return err == nil && savedAuction.Status == auction_entity.Completed
+	}, 4*time.Second, 100*time.Millisecond)
+}
+
+func (suite *AuctionRepositorySuite) TestShutdownLeavesPendingClosesScheduled() {
+	replica := NewAuctionRepository(suite.database)
+
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	err = replica.Shutdown(ctx)
+	assert.Nil(suite.T(), err)
+
+	// The close is still there for whichever replica starts next
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(1), closeJobs)
+}
+
+func (suite *AuctionRepositorySuite) TestCloseHooksRunOnClose() {
+	// Hooks can't be unregistered, so they go on a repository of their own
+	replica := suite.stoppedReplica()
+
+	auction := testhelpers.AnAuction().
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		Build()
+	winningBid := testhelpers.ABid().ForAuction(auction.Id).Build()
+
+	replica.SetWinningBidFinder(func(ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+		return &winningBid, nil
+	})
+
+	var closedAuctionId, closedWinnerId string
+	replica.RegisterOnCloseHook(func(ctx context.Context, auctionId, winnerUserId string) {
+		panic("a failing hook doesn't stop the others")
+	})
+	replica.RegisterOnCloseHook(func(ctx context.Context, auctionId, winnerUserId string) {
+		closedAuctionId, closedWinnerId = auctionId, winnerUserId
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	errClose := replica.closeAuction(auction.Id)
+	assert.Nil(suite.T(), errClose)
+	assert.Equal(suite.T(), auction.Id, closedAuctionId)
+	assert.Equal(suite.T(), winningBid.UserId, closedWinnerId)
+}
+
+func (suite *AuctionRepositorySuite) TestCreateHooksRunOnCreate() {
+	// Hooks can't be unregistered, so they go on a repository of their own
+	replica := suite.stoppedReplica()
+
+	auction := testhelpers.AnAuction().Build()
+
+	var createdAuctionId string
+	replica.RegisterOnCreateHook(func(ctx context.Context, auction auction_entity.Auction) {
+		panic("a failing hook doesn't stop the others")
+	})
+	replica.RegisterOnCreateHook(func(ctx context.Context, auction auction_entity.Auction) {
+		createdAuctionId = auction.Id
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction.Id, createdAuctionId)
+}
+
+func (suite *AuctionRepositorySuite) TestReopenAuction() {
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	// Running auctions can't be reopened
+	err = suite.repo.ReopenAuction(ctx, auction.Id, time.Now().Add(time.Hour))
+	assert.NotNil(suite.T(), err)
+
+	err = suite.repo.closeAuction(auction.Id)
+	assert.Nil(suite.T(), err)
+
+	endTime := time.Now().Add(time.Hour)
+	err = suite.repo.ReopenAuction(ctx, auction.Id, endTime)
+	assert.Nil(suite.T(), err)
+
+	savedAuction, err := suite.repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
+	assert.Equal(suite.T(), endTime.Unix(), savedAuction.EndTime.Unix())
+
+	var closeJob CloseJobMongo
+	errFind := suite.closeJobCollection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&closeJob)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), endTime.Unix(), closeJob.EndTime)
+}
+
+func (suite *AuctionRepositorySuite) TestUpdateAuctionStatusRejectsIllegalTransition() {
+	auction := testhelpers.AnAuction().Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	changed, err := suite.repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Active, auction_entity.Cancelled)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), changed)
+
+	// Cancelled is final
+	changed, err = suite.repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Cancelled, auction_entity.Active)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "invalid_transition", err.Err)
+	assert.False(suite.T(), changed)
+}
+
+func (suite *AuctionRepositorySuite) TestReserveNotMetEndsNotSold() {
+	replica := suite.stoppedReplica()
+
+	unsold := testhelpers.AnAuction().WithReservePrice(500).Build()
+	sold := testhelpers.AnAuction().WithReservePrice(200).Build()
+
+	replica.SetWinningBidFinder(func(ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+		bid := testhelpers.ABid().ForAuction(auctionId).WithAmount(300).Build()
+		return &bid, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, auction := range []*auction_entity.Auction{unsold, sold} {
+		err := replica.CreateAuction(ctx, auction)
+		assert.Nil(suite.T(), err)
+	}
+
+	failed, err := replica.closeAuctions(ctx, []string{unsold.Id, sold.Id})
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), failed)
+
+	savedAuction, err := replica.FindAuctionById(ctx, unsold.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.CompletedNotSold, savedAuction.Status)
+	assert.Empty(suite.T(), savedAuction.WinnerUserId)
+
+	savedAuction, err = replica.FindAuctionById(ctx, sold.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Completed, savedAuction.Status)
+	assert.Equal(suite.T(), 300.0, savedAuction.WinningAmount)
+}
+
+func (suite *AuctionRepositorySuite) TestBuyNowOnlyOnce() {
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).WithBuyNowPrice(900).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	buyer := uuid.New().String()
+	err = suite.repo.BuyNow(ctx, auction.Id, buyer)
+	assert.Nil(suite.T(), err)
+
+	// A second buyer arrives too late
+	err = suite.repo.BuyNow(ctx, auction.Id, uuid.New().String())
+	assert.NotNil(suite.T(), err)
+
+	winner, err := suite.repo.FindAuctionWinner(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), buyer, winner.UserId)
+	assert.Equal(suite.T(), 900.0, winner.Amount)
+
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), closeJobs)
+}
+
+func (suite *AuctionRepositorySuite) TestUnsoldAuctionIsRelisted() {
+	replica := suite.stoppedReplica()
+
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).WithMaxRelists(1).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	err = replica.closeAuction(auction.Id)
+	assert.Nil(suite.T(), err)
+
+	var relisted AuctionEntityMongo
+	errFind := suite.collection.FindOne(ctx, bson.M{"relisted_from": auction.Id}).Decode(&relisted)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), auction_entity.Active, relisted.Status)
+	assert.Equal(suite.T(), auction.ProductName, relisted.ProductName)
+	assert.Equal(suite.T(), 1, relisted.RelistCount)
+	assert.Equal(suite.T(), int64(time.Hour.Seconds()), relisted.Duration)
+
+	// The policy allows a single relist
+	err = replica.closeAuction(relisted.Id)
+	assert.Nil(suite.T(), err)
+
+	relists, errCount := suite.collection.CountDocuments(ctx, bson.M{"relisted_from": relisted.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), relists)
+}
+
+func (suite *AuctionRepositorySuite) TestLeaderFailover() {
+	// A database of its own, the suite repository leads on the shared one
+	database := testhelpers.NewMongoDatabase(suite.T())
+	leader := NewAuctionRepository(database)
+	follower := NewAuctionRepository(database)
+	defer follower.Close()
+
+	assert.Eventually(suite.T(), func() bool {
+		return leader.leader.Load() != follower.leader.Load()
+	}, 4*time.Second, 100*time.Millisecond)
+	if follower.leader.Load() {
+		leader, follower = follower, leader
+	}
+
+	// Only one replica is elected however often they campaign
+	follower.campaign()
+	assert.False(suite.T(), follower.leader.Load())
+
+	// The lease is released on shutdown, the other replica takes over on
+	// its next campaign
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(suite.T(), leader.Shutdown(ctx))
+
+	follower.campaign()
+	assert.True(suite.T(), follower.leader.Load())
+}
+
+func (suite *AuctionRepositorySuite) TestDryRunLeavesAuctionsUntouched() {
+	replica := suite.stoppedReplica()
+	replica.dryRun = true
+
+	auction := testhelpers.AnAuction().
+		WithTimestamp(time.Now().Add(-3 * time.Second)).
+		Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	replica.reportDueAuctions()
+	assert.False(suite.T(), replica.reportedUntil.IsZero())
+
+	savedAuction, err := replica.FindAuctionById(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), auction_entity.Active, savedAuction.Status)
+
+	closeJobs, errCount := suite.closeJobCollection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(1), closeJobs)
+}
+
+func (suite *AuctionRepositorySuite) TestDutchAuctionPriceDropsUntilTaken() {
+	replica := suite.stoppedReplica()
+
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).AsDutch(auction_entity.DutchPricing{
+		StartPrice: 100,
+		FloorPrice: 70,
+		Decrement:  20,
+		Interval:   time.Minute,
+	}).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := replica.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	// Two drops are due, the second one stops at the floor
+	_, errUpdate := suite.collection.UpdateOne(ctx, bson.M{"_id": auction.Id},
+		bson.M{"$set": bson.M{"dutch.next_drop": time.Now().Add(-61 * time.Second).Unix()}})
+	assert.Nil(suite.T(), errUpdate)
+
+	replica.dropDutchPrices()
+
+	var stored AuctionEntityMongo
+	errFind := suite.collection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&stored)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), 70.0, stored.Dutch.Price)
+	assert.Equal(suite.T(), int64(0), stored.Dutch.NextDrop)
+
+	// A bid below the price doesn't take it
+	_, err = replica.TakeDutchAuction(ctx, auction.Id, uuid.New().String(), 60)
+	assert.NotNil(suite.T(), err)
+
+	buyer := uuid.New().String()
+	price, err := replica.TakeDutchAuction(ctx, auction.Id, buyer, 80)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 70.0, price)
+
+	_, err = replica.TakeDutchAuction(ctx, auction.Id, uuid.New().String(), 100)
+	assert.NotNil(suite.T(), err)
+
+	winner, err := replica.FindAuctionWinner(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), buyer, winner.UserId)
+	assert.Equal(suite.T(), 70.0, winner.Amount)
+}
+
+func (suite *AuctionRepositorySuite) TestFindAuctionsPagesByPrice() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	category := "Cameras"
+	prices := []float64{300, 100, 200, 50}
+	var ids []string
+	for _, price := range prices {
+		auction := testhelpers.AnAuction().WithCategory(category).WithDuration(time.Hour).Build()
+		assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+
+		_, err := suite.database.Collection(CurrentPriceCollection).InsertOne(ctx,
+			bson.M{"_id": auction.Id, "amount": price})
+		assert.Nil(suite.T(), err)
+	}
+	// Nobody bid on this one, its price is zero
+	unbid := testhelpers.AnAuction().WithCategory(category).WithDuration(time.Hour).Build()
+	assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, unbid))
+
+	query := auction_entity.AuctionQuery{
+		Category: category,
+		MinPrice: 60,
+		Sort:     auction_entity.SortByPrice,
+		Limit:    2,
+	}
+	firstPage, cursor, err := suite.repo.FindAuctions(ctx, query)
+	assert.Nil(suite.T(), err)
+	assert.NotEmpty(suite.T(), cursor)
+	assert.Len(suite.T(), firstPage, 2)
+	assert.Equal(suite.T(), ids[1], firstPage[0].Id)
+	assert.Equal(suite.T(), ids[2], firstPage[1].Id)
+
+	query.Cursor = cursor
+	secondPage, cursor, err := suite.repo.FindAuctions(ctx, query)
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), cursor)
+	assert.Len(suite.T(), secondPage, 1)
+	assert.Equal(suite.T(), ids[0], secondPage[0].Id)
+}
+
+func (suite *AuctionRepositorySuite) TestFindAuctionFacetsCountsMatches() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sellerId := uuid.New().String()
+	prices := map[string]float64{"Lenses": 75, "Tripods": 20}
+	for category, price := range prices {
+		for i := 0; i < 2; i++ {
+			auction := testhelpers.AnAuction().WithSellerId(sellerId).WithCategory(category).Build()
+			assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, auction))
+
+			_, err := suite.database.Collection(CurrentPriceCollection).InsertOne(ctx,
+				bson.M{"_id": auction.Id, "amount": price * float64(i+1)})
+			assert.Nil(suite.T(), err)
+		}
+	}
+
+	facets, err := suite.repo.FindAuctionFacets(ctx, auction_entity.AuctionQuery{SellerId: sellerId})
+	assert.Nil(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []auction_entity.CategoryFacet{
+		{Category: "Lenses", Count: 2},
+		{Category: "Tripods", Count: 2},
+	}, facets.Categories)
+	assert.Equal(suite.T(), []auction_entity.ConditionFacet{{Condition: auction_entity.New, Count: 4}}, facets.Conditions)
+	// Tripods at 20 and 40, lenses at 75 and 150
+	assert.Equal(suite.T(), []auction_entity.PriceFacet{
+		{Min: 0, Max: 50, Count: 2},
+		{Min: 50, Max: 100, Count: 1},
+		{Min: 100, Max: 250, Count: 1},
+	}, facets.Prices)
+}
+
+func (suite *AuctionRepositorySuite) TestFindAuctionsEndingBefore() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	category := "Watches"
+	endingLater := testhelpers.AnAuction().WithCategory(category).WithDuration(3 * time.Hour).Build()
+	endingSoon := testhelpers.AnAuction().WithCategory(category).WithDuration(30 * time.Minute).Build()
+	endingFirst := testhelpers.AnAuction().WithCategory(category).WithDuration(10 * time.Minute).Build()
+	for _, auction := range []*auction_entity.Auction{endingLater, endingSoon, endingFirst} {
+		assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, auction))
+	}
+
+	active := auction_entity.Active
+	auctions, _, err := suite.repo.FindAuctions(ctx, auction_entity.AuctionQuery{
+		Status:     &active,
+		Category:   category,
+		EndsBefore: time.Now().Add(time.Hour),
+		Sort:       auction_entity.SortByEndTime,
+	})
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), auctions, 2)
+	assert.Equal(suite.T(), endingFirst.Id, auctions[0].Id)
+	assert.Equal(suite.T(), endingSoon.Id, auctions[1].Id)
+}
+
+func (suite *AuctionRepositorySuite) TestFindAuctionsNear() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	category := "Bicycles"
+	// Paulista Avenue, Campinas about 85 km away, and an auction without a
+	// location
+	nearby := testhelpers.AnAuction().WithCategory(category).WithLocation(-23.5614, -46.6559).Build()
+	faraway := testhelpers.AnAuction().WithCategory(category).WithLocation(-22.9056, -47.0608).Build()
+	unlocated := testhelpers.AnAuction().WithCategory(category).Build()
+	for _, auction := range []*auction_entity.Auction{nearby, faraway, unlocated} {
+		assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, auction))
+	}
+
+	auctions, _, err := suite.repo.FindAuctions(ctx, auction_entity.AuctionQuery{
+		Category: category,
+		Near:     &auction_entity.GeoPoint{Latitude: -23.5505, Longitude: -46.6333},
+		RadiusKm: 25,
+	})
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), auctions, 1)
+	assert.Equal(suite.T(), nearby.Id, auctions[0].Id)
+	assert.Equal(suite.T(), nearby.Location, auctions[0].Location)
+}
+
+func (suite *AuctionRepositorySuite) TestSearchAuctionsRanksProductNameFirst() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	category := "Guitars"
+	inDescription := testhelpers.AnAuction().WithCategory(category).
+		WithProductName("Amplifier").
+		WithDescription("Tube amplifier, sounds great with a telecaster").Build()
+	inName := testhelpers.AnAuction().WithCategory(category).
+		WithProductName("Telecaster").
+		WithDescription("Vintage electric guitar in good shape").Build()
+	unrelated := testhelpers.AnAuction().WithCategory(category).
+		WithProductName("Drum kit").
+		WithDescription("Five piece drum kit with cymbals").Build()
+	for _, auction := range []*auction_entity.Auction{inDescription, inName, unrelated} {
+		assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, auction))
+	}
+
+	results, total, err := suite.repo.SearchAuctions(ctx, auction_entity.AuctionSearch{
+		Terms:    []string{"telecaster"},
+		Category: category,
+		Limit:    10,
+	})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	assert.Len(suite.T(), results, 2)
+	assert.Equal(suite.T(), inName.Id, results[0].Id)
+	assert.Equal(suite.T(), inDescription.Id, results[1].Id)
+	assert.Greater(suite.T(), results[0].Score, results[1].Score)
+}
+
+// stoppedReplica is another repository on the suite database whose lifecycle
+// goroutine is stopped, so tests drive its closes without racing it
+func (suite *AuctionRepositorySuite) stoppedReplica() *AuctionRepository {
+	replica := NewAuctionRepository(suite.database)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(suite.T(), replica.Shutdown(ctx))
+
+	// A fresh context, so the closes the test drives aren't cut short
+	replica.auctionCloserCtx, replica.cancelCloser = context.WithCancel(context.Background())
+	suite.T().Cleanup(replica.cancelCloser)
+
+	return replica
+}
+
 func TestAuctionRepositorySuite(t *testing.T) {
 	suite.Run(t, new(AuctionRepositorySuite))
 }
@@ -0,0 +1,65 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// CurrentPriceCollection holds the current price of every auction bid on.
+// The bid repository only raises a price while its document isn't closed,
+// this repository closes it before an auction stops being active, so no bid
+// gets in once the winner is being decided
+const CurrentPriceCollection = "auction_current_prices"
+
+// closeBidding stops the auctions from taking bids, creating their price
+// documents with no price when nobody bid yet
+func (ar *AuctionRepository) closeBidding(ctx context.Context, auctionIds ...string) error {
+	if len(auctionIds) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(auctionIds))
+	for _, auctionId := range auctionIds {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": auctionId}).
+			SetUpdate(bson.M{
+				"$set":         bson.M{"closed": true},
+				"$setOnInsert": bson.M{"amount": 0},
+			}).
+			SetUpsert(true))
+	}
+
+	_, err := ar.currentPriceCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// openBidding lets an auction that became active take bids again
+func (ar *AuctionRepository) openBidding(ctx context.Context, auctionId string) {
+	filter := bson.M{"_id": auctionId}
+	update := bson.M{"$unset": bson.M{"closed": ""}}
+
+	if _, err := ar.currentPriceCollection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error("Error opening auction to bids", err, zap.String("auctionID", auctionId))
+	}
+}
+
+// restoreBidding reopens the bidding closed ahead of a transition that
+// didn't happen, when the auction is still active
+func (ar *AuctionRepository) restoreBidding(ctx context.Context, auctionId string) {
+	filter := bson.M{"_id": auctionId, "status": auction_entity.Active}
+	count, err := ar.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding auction to restore bidding", err, zap.String("auctionID", auctionId))
+		return
+	}
+
+	if count > 0 {
+		ar.openBidding(ctx, auctionId)
+	}
+}
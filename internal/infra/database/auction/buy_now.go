@@ -0,0 +1,88 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// BuyNow ends a running auction at its buy now price with userId as the
+// winner. The status change is a compare-and-swap, so of concurrent buyers
+// only the first one gets it
+func (ar *AuctionRepository) BuyNow(
+	ctx context.Context,
+	id, userId string) *internal_error.InternalError {
+	ar.auctionsMutex.RLock()
+	finder := ar.winningBidFinder
+	ar.auctionsMutex.RUnlock()
+
+	// Stop the bidding first, a bid can't outrun the purchase
+	if err := ar.closeBidding(ctx, id); err != nil {
+		logger.Error("Error closing auction to bids", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error buying auction now")
+	}
+
+	filter := transitionFilter(id, auction_entity.Completed, auction_entity.Active)
+	filter["end_time"] = bson.M{"$gt": time.Now().Unix()}
+	filter["buy_now_price"] = bson.M{"$gt": 0}
+
+	// Buy now goes away once the bidding reaches its price
+	if finder != nil {
+		winningBid, err := finder(ctx, id)
+		if err != nil && err.Err != "not_found" {
+			ar.restoreBidding(ctx, id)
+			return err
+		}
+		if winningBid != nil {
+			filter["buy_now_price"] = bson.M{"$gt": winningBid.Amount}
+		}
+	}
+
+	fields := bson.M{
+		"status":         auction_entity.Completed,
+		"winner_user_id": userId,
+		"winning_amount": "$buy_now_price",
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: fields}}}
+
+	var result *mongo.UpdateResult
+	err := ar.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = ar.Collection.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return nil
+		}
+		if err := ar.logChange(ctx, history_entity.StatusChanged, id); err != nil {
+			return err
+		}
+		return ar.addClosedToOutbox(ctx, id, fields)
+	})
+	if err != nil {
+		logger.Error("Error buying auction now", err, zap.String("auctionID", id))
+		ar.restoreBidding(ctx, id)
+		return internal_error.NewInternalServerError("Error buying auction now")
+	}
+
+	if result.MatchedCount == 0 {
+		ar.restoreBidding(ctx, id)
+		return internal_error.NewInvalidTransitionError("This auction can no longer be bought now")
+	}
+
+	if err := ar.deleteCloseJob(ctx, id); err != nil {
+		logger.Error("Error deleting auction close job", err, zap.String("auctionID", id))
+	}
+
+	ar.notifyScheduleChange(id)
+	ar.runCloseHooks(ctx, id, fields)
+
+	return nil
+}
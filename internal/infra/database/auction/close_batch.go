@@ -0,0 +1,187 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// closeAuctions completes the given auctions that are still active in a
+// single UpdateMany, each one getting its own outcome and winner. It returns
+// the ids whose winner couldn't be looked up, those are left untouched to be
+// retried
+func (ar *AuctionRepository) closeAuctions(
+	ctx context.Context, auctionIds []string) (failed []string, internalErr *internal_error.InternalError) {
+	// Read which ones are still active first, UpdateMany only reports how
+	// many documents it changed and the hooks need to know which
+	activeAuctions, err := ar.findActiveForCompletion(ctx, auctionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stop the bidding before looking the winners up, so none is missed
+	activeIds := make([]string, 0, len(activeAuctions))
+	for _, auction := range activeAuctions {
+		activeIds = append(activeIds, auction.Id)
+	}
+	if errBidding := ar.closeBidding(ctx, activeIds...); errBidding != nil {
+		logger.Error("Error closing auctions to bids", errBidding)
+		return nil, internal_error.NewInternalServerError("Error closing auctions")
+	}
+
+	completions := make(map[string]bson.M, len(activeAuctions))
+	active := make([]string, 0, len(activeAuctions))
+	for _, auction := range activeAuctions {
+		fields, err := ar.completionFields(ctx, auction)
+		if err != nil {
+			logger.Error("Error finding the winner of an expired auction", err, zap.String("auctionID", auction.Id))
+			failed = append(failed, auction.Id)
+			continue
+		}
+		completions[auction.Id] = fields
+		active = append(active, auction.Id)
+	}
+
+	if len(active) == 0 {
+		return failed, nil
+	}
+
+	filter := bson.M{
+		"_id":    bson.M{"$in": active},
+		"status": transitionStatuses(auction_entity.Completed, auction_entity.Active),
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: completionSwitch(active, completions)}}}
+
+	var closed []string
+	errUpdate := ar.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := ar.Collection.UpdateMany(ctx, filter, update)
+		if err != nil {
+			logger.Error("Error closing auctions", err)
+			return err
+		}
+
+		closed = active
+		if result.ModifiedCount != int64(len(active)) {
+			// Someone else moved some of them in between, the ones completed
+			// now are the ones this update closed
+			var errFind *internal_error.InternalError
+			if closed, errFind = ar.findCompletedIds(ctx, active); errFind != nil {
+				return errFind
+			}
+		}
+
+		if err := ar.logChange(ctx, history_entity.StatusChanged, closed...); err != nil {
+			return err
+		}
+		for _, auctionId := range closed {
+			if err := ar.addClosedToOutbox(ctx, auctionId, completions[auctionId]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errUpdate != nil {
+		return nil, internal_error.NewInternalServerError("Error closing auctions")
+	}
+
+	logger.Info("Auctions closed successfully", zap.Int("closed", len(closed)))
+	for _, auctionId := range closed {
+		ar.runCloseHooks(ctx, auctionId, completions[auctionId])
+	}
+
+	return failed, nil
+}
+
+// completionSwitch builds the pipeline $set completing every auction in
+// ids, picking each auction's outcome and winner by its id
+func completionSwitch(ids []string, completions map[string]bson.M) bson.M {
+	statusBranches, winnerBranches, amountBranches := bson.A{}, bson.A{}, bson.A{}
+	for _, auctionId := range ids {
+		fields := completions[auctionId]
+		isAuction := bson.M{"$eq": bson.A{"$_id", auctionId}}
+
+		if fields["status"] != auction_entity.Completed {
+			statusBranches = append(statusBranches, bson.M{"case": isAuction, "then": fields["status"]})
+		}
+
+		if _, ok := fields["winner_user_id"]; ok {
+			winnerBranches = append(winnerBranches, bson.M{"case": isAuction, "then": fields["winner_user_id"]})
+			amountBranches = append(amountBranches, bson.M{"case": isAuction, "then": fields["winning_amount"]})
+		}
+	}
+
+	set := bson.M{"status": auction_entity.Completed}
+	if len(statusBranches) > 0 {
+		set["status"] = bson.M{"$switch": bson.M{"branches": statusBranches, "default": auction_entity.Completed}}
+	}
+	if len(winnerBranches) > 0 {
+		set["winner_user_id"] = bson.M{"$switch": bson.M{"branches": winnerBranches, "default": "$$REMOVE"}}
+		set["winning_amount"] = bson.M{"$switch": bson.M{"branches": amountBranches, "default": "$$REMOVE"}}
+	}
+
+	return set
+}
+
+// findActiveForCompletion reads what completing the auctions depends on
+func (ar *AuctionRepository) findActiveForCompletion(
+	ctx context.Context, ids []string) ([]AuctionEntityMongo, *internal_error.InternalError) {
+	filter := bson.M{"_id": bson.M{"$in": ids}, "status": auction_entity.Active}
+	projection := bson.M{"_id": 1, "reserve_price": 1}
+
+	return ar.findForClose(ctx, filter, projection)
+}
+
+func (ar *AuctionRepository) findCompletedIds(
+	ctx context.Context, ids []string) ([]string, *internal_error.InternalError) {
+	filter := bson.M{"_id": bson.M{"$in": ids}, "status": bson.M{"$in": []auction_entity.AuctionStatus{
+		auction_entity.Completed, auction_entity.CompletedNotSold,
+	}}}
+
+	auctions, err := ar.findForClose(ctx, filter, bson.M{"_id": 1})
+	if err != nil {
+		return nil, err
+	}
+
+	auctionIds := make([]string, 0, len(auctions))
+	for _, auction := range auctions {
+		auctionIds = append(auctionIds, auction.Id)
+	}
+
+	return auctionIds, nil
+}
+
+func (ar *AuctionRepository) findForClose(
+	ctx context.Context, filter, projection bson.M) ([]AuctionEntityMongo, *internal_error.InternalError) {
+	cursor, err := ar.Collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		logger.Error("Error finding auctions to close", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions to close")
+	}
+
+	var auctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctions); err != nil {
+		logger.Error("Error finding auctions to close", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions to close")
+	}
+
+	return auctions, nil
+}
+
+// getCloseBatchSize is how many due close jobs a replica claims at once
+func getCloseBatchSize() int64 {
+	batchSize, err := strconv.ParseInt(os.Getenv("AUCTION_CLOSE_BATCH_SIZE"), 10, 64)
+	if err != nil || batchSize <= 0 {
+		return 100
+	}
+
+	return batchSize
+}
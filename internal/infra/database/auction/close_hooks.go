@@ -0,0 +1,47 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// CloseHook runs after an auction moved to Completed, winnerUserId is empty
+// when the auction closed without bids
+type CloseHook func(ctx context.Context, auctionId, winnerUserId string)
+
+// RegisterOnCloseHook adds a side effect to run whenever an auction closes,
+// be it by the lifecycle scheduler or an admin. Hooks run one after the
+// other on the closing goroutine, so slow work should be handed off
+func (ar *AuctionRepository) RegisterOnCloseHook(hook CloseHook) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.closeHooks = append(ar.closeHooks, hook)
+}
+
+func (ar *AuctionRepository) runCloseHooks(ctx context.Context, auctionId string, fields bson.M) {
+	winnerUserId, _ := fields["winner_user_id"].(string)
+
+	ar.auctionsMutex.RLock()
+	hooks := ar.closeHooks
+	ar.auctionsMutex.RUnlock()
+
+	for _, hook := range hooks {
+		runCloseHook(ctx, hook, auctionId, winnerUserId)
+	}
+}
+
+// A failing hook must not take the scheduler down nor stop the other hooks
+func runCloseHook(ctx context.Context, hook CloseHook, auctionId, winnerUserId string) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Auction close hook panicked", fmt.Errorf("%v", r), zap.String("auctionID", auctionId))
+		}
+	}()
+
+	hook(ctx, auctionId, winnerUserId)
+}
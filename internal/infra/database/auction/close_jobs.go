@@ -0,0 +1,168 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CloseJobMongo schedules the close of one active auction. Every replica
+// watches the same jobs, a replica claims a due job by taking its lease, so
+// an auction is closed once however many instances run. RemindedBefore is
+// the last reminder sent, in seconds before RemindedEndTime
+type CloseJobMongo struct {
+	AuctionId       string `bson:"_id"`
+	EndTime         int64  `bson:"end_time"`
+	LeaseOwner      string `bson:"lease_owner,omitempty"`
+	LeaseUntil      int64  `bson:"lease_until,omitempty"`
+	RemindedEndTime int64  `bson:"reminded_end_time,omitempty"`
+	RemindedBefore  int64  `bson:"reminded_before,omitempty"`
+}
+
+// scheduleCloseJob sets the end time of the auction's close job, creating it
+// if needed, and releases any lease so the new time is honored right away
+func (ar *AuctionRepository) scheduleCloseJob(ctx context.Context, auctionId string, endTime time.Time) error {
+	filter := bson.M{"_id": auctionId}
+	update := bson.M{
+		"$set":   bson.M{"end_time": endTime.Unix()},
+		"$unset": bson.M{"lease_owner": "", "lease_until": ""},
+	}
+
+	if _, err := ar.closeJobCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return err
+	}
+
+	ar.deadlines.push(endTime)
+	ar.pushReminders(endTime)
+	return nil
+}
+
+// syncCloseJob makes the close job end when the auction stored it does,
+// creating the job if needed. Unlike scheduleCloseJob it leaves a running
+// close its lease
+func (ar *AuctionRepository) syncCloseJob(ctx context.Context, auctionId string, endTime time.Time) error {
+	filter := bson.M{"_id": auctionId}
+	update := bson.M{"$set": bson.M{"end_time": endTime.Unix()}}
+
+	if _, err := ar.closeJobCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return err
+	}
+
+	ar.deadlines.push(endTime)
+	ar.pushReminders(endTime)
+	return nil
+}
+
+// extendCloseJob only ever moves the job's end time forward
+func (ar *AuctionRepository) extendCloseJob(ctx context.Context, auctionId string, endTime time.Time) error {
+	filter := bson.M{"_id": auctionId}
+	update := bson.M{"$max": bson.M{"end_time": endTime.Unix()}}
+
+	if _, err := ar.closeJobCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return err
+	}
+
+	ar.deadlines.push(endTime)
+	ar.pushReminders(endTime)
+	return nil
+}
+
+func (ar *AuctionRepository) deleteCloseJob(ctx context.Context, auctionId string) error {
+	_, err := ar.closeJobCollection.DeleteOne(ctx, bson.M{"_id": auctionId})
+	return err
+}
+
+// claimDueCloseJobs leases up to limit jobs whose end time has passed and
+// whose lease, if any, expired. Every claim gets its own lease owner, so the
+// jobs returned are exactly the ones this call took. It returns nothing when
+// there is nothing left to close
+func (ar *AuctionRepository) claimDueCloseJobs(ctx context.Context, limit int64) ([]CloseJobMongo, error) {
+	now := time.Now()
+
+	filter := bson.M{
+		"end_time": bson.M{"$lte": now.Unix()},
+		"$or": bson.A{
+			bson.M{"lease_until": bson.M{"$exists": false}},
+			bson.M{"lease_until": bson.M{"$lt": now.Unix()}},
+		},
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "end_time", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"_id": 1})
+
+	var candidates []CloseJobMongo
+	cursor, err := ar.closeJobCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.AuctionId)
+	}
+
+	// Another replica may take some of them between the find and the update,
+	// the filter is repeated so those are skipped
+	leaseOwner := ar.closerId + ":" + uuid.New().String()
+	filter["_id"] = bson.M{"$in": ids}
+	update := bson.M{"$set": bson.M{
+		"lease_owner": leaseOwner,
+		"lease_until": now.Add(ar.closeLease).Unix(),
+	}}
+	if _, err := ar.closeJobCollection.UpdateMany(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	var closeJobs []CloseJobMongo
+	cursor, err = ar.closeJobCollection.Find(ctx, bson.M{"lease_owner": leaseOwner})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &closeJobs); err != nil {
+		return nil, err
+	}
+
+	return closeJobs, nil
+}
+
+// completeCloseJobs removes the jobs this replica closed, except the ones
+// rescheduled meanwhile, which no longer carry our lease
+func (ar *AuctionRepository) completeCloseJobs(ctx context.Context, closeJobs []CloseJobMongo) error {
+	if len(closeJobs) == 0 {
+		return nil
+	}
+
+	claims := make(bson.A, 0, len(closeJobs))
+	for _, closeJob := range closeJobs {
+		claims = append(claims, bson.M{
+			"_id":         closeJob.AuctionId,
+			"lease_owner": closeJob.LeaseOwner,
+			"end_time":    closeJob.EndTime,
+		})
+	}
+
+	_, err := ar.closeJobCollection.DeleteMany(ctx, bson.M{"$or": claims})
+	return err
+}
+
+// getCloseLease is how long a replica may take to close a claimed auction
+// before another one is allowed to retry it
+func getCloseLease() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("AUCTION_CLOSE_LEASE"))
+	if err != nil || duration <= 0 {
+		return 30 * time.Second
+	}
+
+	return duration
+}
@@ -1,186 +1,498 @@
 package auction
 
 import (
+	"auction_go/configuration/database/mongodb"
 	"auction_go/configuration/logger"
 	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/history_entity"
 	"auction_go/internal/internal_error"
 	"context"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
 type AuctionEntityMongo struct {
-	Id          string                          `bson:"_id"`
-	ProductName string                          `bson:"product_name"`
-	Category    string                          `bson:"category"`
-	Description string                          `bson:"description"`
-	Condition   auction_entity.ProductCondition `bson:"condition"`
-	Status      auction_entity.AuctionStatus    `bson:"status"`
-	Timestamp   int64                           `bson:"timestamp"`
+	Id              string                          `bson:"_id"`
+	SellerId        string                          `bson:"seller_id,omitempty"`
+	ProductName     string                          `bson:"product_name"`
+	Category        string                          `bson:"category"`
+	Description     string                          `bson:"description"`
+	DescriptionHTML string                          `bson:"description_html"`
+	Condition       auction_entity.ProductCondition `bson:"condition"`
+	Status          auction_entity.AuctionStatus    `bson:"status"`
+	Timestamp       int64                           `bson:"timestamp"`
+	StartTime       int64                           `bson:"start_time,omitempty"`
+	Duration        int64                           `bson:"duration,omitempty"`
+	EndTime         int64                           `bson:"end_time,omitempty"`
+	WinnerUserId    string                          `bson:"winner_user_id,omitempty"`
+	WinningAmount   float64                         `bson:"winning_amount,omitempty"`
+	ReservePrice    float64                         `bson:"reserve_price,omitempty"`
+	ReservePublic   bool                            `bson:"reserve_public,omitempty"`
+	BuyNowPrice     float64                         `bson:"buy_now_price,omitempty"`
+	MinIncrement    float64                         `bson:"min_increment,omitempty"`
+	Type            auction_entity.AuctionType      `bson:"type,omitempty"`
+	Dutch           *DutchPricingMongo              `bson:"dutch,omitempty"`
+	MaxRelists      int                             `bson:"max_relists,omitempty"`
+	RelistCount     int                             `bson:"relist_count,omitempty"`
+	RelistedFrom    string                          `bson:"relisted_from,omitempty"`
+
+	CancellationReason string `bson:"cancellation_reason,omitempty"`
+	CancelledAt        int64  `bson:"cancelled_at,omitempty"`
+	PausedAt           int64  `bson:"paused_at,omitempty"`
+
+	Location   *GeoJSONPointMongo `bson:"location,omitempty"`
+	PostalCode string             `bson:"postal_code,omitempty"`
 }
 
 type AuctionRepository struct {
-	Collection       *mongo.Collection
-	auctionInterval  time.Duration
-	activeAuctions   map[string]time.Time
-	auctionsMutex    *sync.RWMutex
-	auctionCloserCtx context.Context
-	cancelCloser     context.CancelFunc
+	Collection         *mongo.Collection
+	closeJobCollection *mongo.Collection
+	leaderCollection   *mongo.Collection
+	// currentPriceCollection is shared with the bid repository, see
+	// CurrentPriceCollection
+	currentPriceCollection *mongo.Collection
+	auctionInterval        time.Duration
+	closeLease             time.Duration
+	closeBatchSize         int64
+	deadlines              *deadlineQueue
+	reminders              []time.Duration
+	closerId               string
+	leaderLease            time.Duration
+	leader                 atomic.Bool
+	auctionsMutex          *sync.RWMutex
+	auctionCloserCtx       context.Context
+	cancelCloser           context.CancelFunc
+	closerDone             chan struct{}
+
+	// dryRun only logs what the scheduler would do, reportedUntil is how far
+	// it already reported
+	dryRun        bool
+	reportedUntil time.Time
+
+	scheduleListeners []func(auctionId string)
+	closeHooks        []CloseHook
+	createHooks       []CreateHook
+	priceDropHooks    []PriceDropHook
+	extendHooks       []ExtendHook
+	reminderHooks     []ReminderHook
+	winningBidFinder  WinningBidFinder
+	outbox            Outbox
+	eventLog          EventLog
+
+	// atlasSearchIndex searches through Atlas Search instead of the text
+	// index when set, see SearchAuctions
+	atlasSearchIndex string
 }
 
 func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	repo := &AuctionRepository{
-		Collection:       database.Collection("auctions"),
-		auctionInterval:  getAuctionInterval(),
-		activeAuctions:   make(map[string]time.Time),
-		auctionsMutex:    &sync.RWMutex{},
-		auctionCloserCtx: ctx,
-		cancelCloser:     cancel,
+		Collection:             database.Collection("auctions"),
+		closeJobCollection:     database.Collection("auction_close_jobs"),
+		leaderCollection:       database.Collection("auction_leader"),
+		currentPriceCollection: database.Collection(CurrentPriceCollection),
+		auctionInterval:        getAuctionInterval(),
+		closeLease:             getCloseLease(),
+		closeBatchSize:         getCloseBatchSize(),
+		deadlines:              newDeadlineQueue(getSchedulerResync()),
+		reminders:              getReminders(),
+		closerId:               uuid.New().String(),
+		leaderLease:            getLeaderLease(),
+		dryRun:                 getCloserDryRun(),
+		atlasSearchIndex:       getAtlasSearchIndex(),
+		auctionsMutex:          &sync.RWMutex{},
+		auctionCloserCtx:       ctx,
+		cancelCloser:           cancel,
+		closerDone:             make(chan struct{}),
 	}
 
-	// Start the auction closer goroutine
-	go repo.startAuctionCloser()
+	repo.RegisterOnCloseHook(repo.relistIfUnsold)
+
+	// Start the auction lifecycle goroutine, it only does work while this
+	// replica is the elected leader
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		repo.startLeaderElection()
+	}()
+	go func() {
+		defer workers.Done()
+		repo.startLifecycleScheduler()
+	}()
+	go func() {
+		workers.Wait()
+		close(repo.closerDone)
+	}()
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
 
 	return repo
 }
 
+// EnsureIndexes creates the indexes of the auctions and auction_close_jobs collections
+func (ar *AuctionRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, ar.Collection.Database(), "auctions", "auction_close_jobs"); err != nil {
+		logger.Error("Error trying to create auction indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create auction indexes")
+	}
+
+	return nil
+}
+
 func (ar *AuctionRepository) CreateAuction(
 	ctx context.Context,
 	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	// The duration is stored even when it is the default, so a later change
+	// of AUCTION_INTERVAL only affects new auctions
+	duration := auctionEntity.Duration
+	if duration == 0 {
+		duration = ar.auctionInterval
+	}
+
+	startTime := auctionEntity.StartTime
+	if startTime.IsZero() {
+		startTime = auctionEntity.Timestamp
+	}
+
 	auctionEntityMongo := &AuctionEntityMongo{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   auctionEntity.Condition,
-		Status:      auctionEntity.Status,
-		Timestamp:   auctionEntity.Timestamp.Unix(),
-	}
-	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
+		Id:              auctionEntity.Id,
+		SellerId:        auctionEntity.SellerId,
+		ProductName:     auctionEntity.ProductName,
+		Category:        auctionEntity.Category,
+		Description:     auctionEntity.Description,
+		DescriptionHTML: auctionEntity.DescriptionHTML,
+		Condition:       auctionEntity.Condition,
+		Status:          auctionEntity.Status,
+		Timestamp:       auctionEntity.Timestamp.Unix(),
+		StartTime:       startTime.Unix(),
+		Duration:        int64(duration.Seconds()),
+		EndTime:         startTime.Add(duration).Unix(),
+		ReservePrice:    auctionEntity.ReservePrice,
+		ReservePublic:   auctionEntity.ReservePublic,
+		BuyNowPrice:     auctionEntity.BuyNowPrice,
+		MinIncrement:    auctionEntity.MinIncrement,
+		Type:            auctionEntity.Type,
+		Dutch:           newDutchPricingMongo(auctionEntity, startTime),
+		MaxRelists:      auctionEntity.RelistPolicy.MaxRelists,
+		RelistCount:     auctionEntity.RelistCount,
+		RelistedFrom:    auctionEntity.RelistedFrom,
+		Location:        newLocationMongo(auctionEntity.Location),
+		PostalCode:      auctionEntity.PostalCode,
+	}
+
+	// Auctions that don't start right away take no bids until activated
+	if auctionEntity.Status != auction_entity.Active {
+		if err := ar.closeBidding(ctx, auctionEntity.Id); err != nil {
+			logger.Error("Error closing auction to bids", err, zap.String("auctionID", auctionEntity.Id))
+			return internal_error.NewInternalServerError("Error trying to insert auction")
+		}
+	}
+
+	err := ar.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := ar.Collection.InsertOne(ctx, auctionEntityMongo); err != nil {
+			return err
+		}
+		if err := ar.logCreated(ctx, auctionEntityMongo); err != nil {
+			return err
+		}
+		return ar.addCreatedToOutbox(ctx, *auctionEntity)
+	})
 	if err != nil {
 		logger.Error("Error trying to insert auction", err)
 		return internal_error.NewInternalServerError("Error trying to insert auction")
 	}
 
-	// Add the auction to the active auctions map with its end time
-	endTime := auctionEntity.Timestamp.Add(ar.auctionInterval)
-	ar.auctionsMutex.Lock()
-	ar.activeAuctions[auctionEntity.Id] = endTime
-	ar.auctionsMutex.Unlock()
+	ar.runCreateHooks(ctx, *auctionEntity)
+
+	// Scheduled auctions get their close job when they are activated
+	if auctionEntity.Status != auction_entity.Active {
+		ar.deadlines.push(startTime)
+		return nil
+	}
+
+	// Schedule the close, an auction left without a job is picked up again by
+	// LoadActiveAuctions on the next start
+	endTime := startTime.Add(duration)
+	if err := ar.scheduleCloseJob(ctx, auctionEntity.Id, endTime); err != nil {
+		logger.Error("Error trying to schedule auction close", err, zap.String("auctionID", auctionEntity.Id))
+	}
+
+	if auctionEntityMongo.Dutch != nil {
+		ar.deadlines.push(time.Unix(auctionEntityMongo.Dutch.NextDrop, 0))
+	}
 
 	return nil
 }
 
-// Close auction repository and stop the auction closer goroutine
+// Close auction repository and stop the lifecycle goroutine, pending closes
+// stay in the jobs collection for the other replicas or the next start
 func (ar *AuctionRepository) Close() {
 	ar.cancelCloser()
 }
 
-// Start a goroutine to check for expired auctions and close them
-func (ar *AuctionRepository) startAuctionCloser() {
-	ticker := time.NewTicker(10 * time.Second) // Check every 10 seconds
-	defer ticker.Stop()
+// Shutdown stops the lifecycle goroutine from claiming more work and waits
+// for the close it is running, if any, to finish, then hands the scheduler
+// over to another replica. A close abandoned when ctx expires keeps its lease
+// and is retried once the lease runs out
+func (ar *AuctionRepository) Shutdown(ctx context.Context) *internal_error.InternalError {
+	ar.cancelCloser()
+
+	select {
+	case <-ar.closerDone:
+		return nil
+	case <-ctx.Done():
+		logger.Error("Timed out waiting for auction closes to finish", ctx.Err())
+		return internal_error.NewInternalServerError("Timed out waiting for auction closes to finish")
+	}
+}
+
+// Start a goroutine that activates scheduled auctions once they start,
+// lowers the price of Dutch auctions, reminds bidders of auctions about to
+// close and closes expired ones. It sleeps until the nearest known deadline, and
+// resyncs deadlines from the database periodically to pick up the ones
+// scheduled by other replicas. Only the elected leader does the work, the
+// other replicas just drop their deadlines
+func (ar *AuctionRepository) startLifecycleScheduler() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
+	var nextResync time.Time
 	for {
 		select {
-		case <-ticker.C:
-			ar.closeExpiredAuctions()
+		case <-timer.C:
+		case <-ar.deadlines.wake:
 		case <-ar.auctionCloserCtx.Done():
-			logger.Info("Auction closer goroutine stopped")
+			logger.Info("Auction lifecycle goroutine stopped")
 			return
 		}
+
+		now := time.Now()
+		if !ar.leader.Load() {
+			// A replica elected later resyncs first, it may have missed
+			// deadlines meanwhile
+			ar.deadlines.popDue(now)
+			nextResync = time.Time{}
+			timer.Reset(ar.deadlines.horizon)
+			continue
+		}
+
+		resync := !now.Before(nextResync)
+		if resync {
+			ar.resyncDeadlines()
+			nextResync = now.Add(ar.deadlines.horizon)
+		}
+
+		if ar.deadlines.popDue(now) || resync {
+			if ar.dryRun {
+				ar.reportDueAuctions()
+			} else {
+				ar.activateScheduledAuctions()
+				ar.dropDutchPrices()
+				ar.closeExpiredAuctions()
+				ar.sendDueReminders()
+			}
+		}
+
+		timer.Reset(ar.deadlines.untilNext(nextResync))
 	}
 }
 
-// Check for expired auctions and close them
+// Claim due close jobs in batches and close their auctions, until no
+// replica has anything left to claim
 func (ar *AuctionRepository) closeExpiredAuctions() {
-	now := time.Now()
-	expiredAuctions := make([]string, 0)
+	for ar.auctionCloserCtx.Err() == nil {
+		ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 5*time.Second)
+		closeJobs, err := ar.claimDueCloseJobs(ctx, ar.closeBatchSize)
+		cancel()
 
-	// Find expired auctions
-	ar.auctionsMutex.RLock()
-	for auctionID, endTime := range ar.activeAuctions {
-		if now.After(endTime) {
-			expiredAuctions = append(expiredAuctions, auctionID)
+		if err != nil {
+			logger.Error("Error claiming auction close jobs", err)
+			return
+		}
+		if len(closeJobs) == 0 {
+			return
+		}
+
+		auctionIds := make([]string, 0, len(closeJobs))
+		for _, closeJob := range closeJobs {
+			auctionIds = append(auctionIds, closeJob.AuctionId)
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		failed, errClose := ar.closeAuctions(ctx, auctionIds)
+		if errClose != nil {
+			// The leases expire and the jobs are retried by whichever replica
+			// claims them next
+			logger.Error("Failed to close expired auctions", errClose)
+			ar.deadlines.push(time.Now().Add(ar.closeLease))
+			cancel()
+			continue
+		}
+		if len(failed) > 0 {
+			ar.deadlines.push(time.Now().Add(ar.closeLease))
+		}
+
+		if err := ar.completeCloseJobs(ctx, completedJobs(closeJobs, failed)); err != nil {
+			logger.Error("Error completing auction close jobs", err)
 		}
+		cancel()
 	}
-	ar.auctionsMutex.RUnlock()
+}
 
-	// Close expired auctions
-	for _, auctionID := range expiredAuctions {
-		if err := ar.closeAuction(auctionID); err != nil {
-			logger.Error("Failed to close expired auction", err)
-		} else {
-			// Remove from active auctions map
-			ar.auctionsMutex.Lock()
-			delete(ar.activeAuctions, auctionID)
-			ar.auctionsMutex.Unlock()
+// completedJobs drops the jobs of the auctions that failed to close, they
+// keep their lease until it expires
+func completedJobs(closeJobs []CloseJobMongo, failed []string) []CloseJobMongo {
+	if len(failed) == 0 {
+		return closeJobs
+	}
+
+	failedIds := make(map[string]bool, len(failed))
+	for _, auctionId := range failed {
+		failedIds[auctionId] = true
+	}
+
+	completed := make([]CloseJobMongo, 0, len(closeJobs))
+	for _, closeJob := range closeJobs {
+		if !failedIds[closeJob.AuctionId] {
+			completed = append(completed, closeJob)
 		}
 	}
+
+	return completed
 }
 
-// Close a specific auction by updating its status
-func (ar *AuctionRepository) closeAuction(auctionID string) *internal_error.InternalError {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Activate the scheduled auctions whose start time has passed and schedule
+// their close. Replicas racing on the same auction only activate it once
+func (ar *AuctionRepository) activateScheduledAuctions() {
+	ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 30*time.Second)
 	defer cancel()
 
-	filter := bson.M{"_id": auctionID, "status": auction_entity.Active}
-	update := bson.M{"$set": bson.M{"status": auction_entity.Completed}}
+	filter := bson.M{
+		"status":     auction_entity.Scheduled,
+		"start_time": bson.M{"$lte": time.Now().Unix()},
+	}
+
+	err := mongodb.Stream(ctx, ar.Collection, filter, func(auction AuctionEntityMongo) error {
+		result, err := ar.updateAndLog(ctx, history_entity.StatusChanged, auction.Id,
+			transitionFilter(auction.Id, auction_entity.Active, auction_entity.Scheduled),
+			bson.M{"$set": bson.M{"status": auction_entity.Active}})
+		if err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			return nil
+		}
+
+		ar.openBidding(ctx, auction.Id)
+		if err := ar.scheduleCloseJob(ctx, auction.Id, ar.endTimeOf(auction)); err != nil {
+			logger.Error("Error trying to schedule auction close", err, zap.String("auctionID", auction.Id))
+		}
 
-	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+		ar.notifyScheduleChange(auction.Id)
+		logger.Info("Scheduled auction activated", zap.String("auctionID", auction.Id))
+
+		return nil
+	})
 	if err != nil {
-		logger.Error("Error closing auction", err)
-		return internal_error.NewInternalServerError("Error closing auction")
+		logger.Error("Error activating scheduled auctions", err)
 	}
+}
+
+// Close a specific auction by updating its status and recording its winner
+func (ar *AuctionRepository) closeAuction(auctionID string) *internal_error.InternalError {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	if result.ModifiedCount > 0 {
-		logger.Info("Auction closed successfully", zap.String("auctionID", auctionID))
+	failed, err := ar.closeAuctions(ctx, []string{auctionID})
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return internal_error.NewInternalServerError("Error finding the auction winner")
 	}
 
 	return nil
 }
 
-// Load existing active auctions from database
+// LoadActiveAuctions rebuilds the close schedule from the end time stored on
+// every active auction, which includes the extensions applied while running.
+// It streams them so startup memory doesn't grow with the number of open
+// auctions. Auctions already past their end time are closed by the next
+// closer tick
 func (ar *AuctionRepository) LoadActiveAuctions(ctx context.Context) *internal_error.InternalError {
 	filter := bson.M{"status": auction_entity.Active}
 
-	cursor, err := ar.Collection.Find(ctx, filter)
+	loaded := 0
+	err := mongodb.Stream(ctx, ar.Collection, filter, func(auction AuctionEntityMongo) error {
+		loaded++
+		return ar.syncCloseJob(ctx, auction.Id, ar.endTimeOf(auction))
+	})
 	if err != nil {
 		logger.Error("Error loading active auctions", err)
 		return internal_error.NewInternalServerError("Error loading active auctions")
 	}
-	defer cursor.Close(ctx)
 
-	var auctions []AuctionEntityMongo
-	if err := cursor.All(ctx, &auctions); err != nil {
-		logger.Error("Error decoding auctions", err)
-		return internal_error.NewInternalServerError("Error decoding auctions")
+	logger.Info("Active auctions loaded", zap.Int("scheduled", loaded))
+
+	return nil
+}
+
+// Auctions store their end time when created and whenever it moves, it is
+// only derived for documents BackfillEndTimes didn't reach yet. Auctions
+// stored before durations existed last AUCTION_INTERVAL
+func (ar *AuctionRepository) endTimeOf(auctionEntityMongo AuctionEntityMongo) time.Time {
+	if auctionEntityMongo.EndTime != 0 {
+		return time.Unix(auctionEntityMongo.EndTime, 0)
+	}
+
+	return startTimeOf(auctionEntityMongo).Add(ar.durationOf(auctionEntityMongo))
+}
+
+// Auctions stored before scheduling existed started when they were created
+func startTimeOf(auctionEntityMongo AuctionEntityMongo) time.Time {
+	if auctionEntityMongo.StartTime != 0 {
+		return time.Unix(auctionEntityMongo.StartTime, 0)
+	}
+
+	return time.Unix(auctionEntityMongo.Timestamp, 0)
+}
+
+func (ar *AuctionRepository) durationOf(auctionEntityMongo AuctionEntityMongo) time.Duration {
+	if auctionEntityMongo.Duration != 0 {
+		return time.Duration(auctionEntityMongo.Duration) * time.Second
 	}
 
+	return ar.auctionInterval
+}
+
+// OnScheduleChange registers a listener called whenever an auction leaves
+// its regular schedule, so caches of its status or end time can drop it
+func (ar *AuctionRepository) OnScheduleChange(listener func(auctionId string)) {
 	ar.auctionsMutex.Lock()
 	defer ar.auctionsMutex.Unlock()
 
-	for _, auction := range auctions {
-		auctionTime := time.Unix(auction.Timestamp, 0)
-		endTime := auctionTime.Add(ar.auctionInterval)
+	ar.scheduleListeners = append(ar.scheduleListeners, listener)
+}
 
-		// Only add if not already expired
-		if time.Now().Before(endTime) {
-			ar.activeAuctions[auction.Id] = endTime
-		} else {
-			// Close already expired auctions
-			go ar.closeAuction(auction.Id)
-		}
-	}
+func (ar *AuctionRepository) notifyScheduleChange(auctionId string) {
+	ar.auctionsMutex.RLock()
+	listeners := ar.scheduleListeners
+	ar.auctionsMutex.RUnlock()
 
-	return nil
+	for _, listener := range listeners {
+		listener(auctionId)
+	}
 }
 
 func getAuctionInterval() time.Duration {
@@ -191,4 +503,4 @@ func getAuctionInterval() time.Duration {
 	}
 
 	return duration
-}
\ No newline at end of file
+}
@@ -0,0 +1,44 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// CreateHook runs after a new auction was stored, relisted auctions included
+type CreateHook func(ctx context.Context, auction auction_entity.Auction)
+
+// RegisterOnCreateHook adds a side effect to run whenever an auction is
+// created. Hooks run one after the other on the creating request, so slow
+// work should be handed off
+func (ar *AuctionRepository) RegisterOnCreateHook(hook CreateHook) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.createHooks = append(ar.createHooks, hook)
+}
+
+func (ar *AuctionRepository) runCreateHooks(ctx context.Context, auction auction_entity.Auction) {
+	ar.auctionsMutex.RLock()
+	hooks := ar.createHooks
+	ar.auctionsMutex.RUnlock()
+
+	for _, hook := range hooks {
+		runCreateHook(ctx, hook, auction)
+	}
+}
+
+// A failing hook must not fail the creation nor stop the other hooks
+func runCreateHook(ctx context.Context, hook CreateHook, auction auction_entity.Auction) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Auction create hook panicked", fmt.Errorf("%v", r), zap.String("auctionID", auction.Id))
+		}
+	}()
+
+	hook(ctx, auction)
+}
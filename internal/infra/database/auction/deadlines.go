@@ -0,0 +1,162 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"container/heap"
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// deadlineQueue holds the upcoming start and end times this replica knows
+// about, the lifecycle scheduler sleeps until the earliest one. Entries are
+// only wake ups, the jobs themselves stay in MongoDB, so a stale or duplicate
+// deadline just triggers a claim that finds nothing
+type deadlineQueue struct {
+	mutex     sync.Mutex
+	deadlines deadlineHeap
+	horizon   time.Duration
+	wake      chan struct{}
+}
+
+func newDeadlineQueue(horizon time.Duration) *deadlineQueue {
+	return &deadlineQueue{
+		horizon: horizon,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// push adds a deadline and wakes the scheduler if it is now the earliest.
+// Deadlines past the resync horizon are left to the next resync, which keeps
+// the heap small however many auctions are open
+func (q *deadlineQueue) push(deadline time.Time) {
+	if deadline.After(time.Now().Add(q.horizon)) {
+		return
+	}
+
+	q.mutex.Lock()
+	earliest := len(q.deadlines) == 0 || deadline.Before(q.deadlines[0])
+	heap.Push(&q.deadlines, deadline)
+	q.mutex.Unlock()
+
+	if earliest {
+		q.wakeUp()
+	}
+}
+
+// wakeUp makes the scheduler run right away
+func (q *deadlineQueue) wakeUp() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// popDue removes the deadlines already reached, reporting if there were any
+func (q *deadlineQueue) popDue(now time.Time) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	due := false
+	for len(q.deadlines) > 0 && !q.deadlines[0].After(now) {
+		heap.Pop(&q.deadlines)
+		due = true
+	}
+
+	return due
+}
+
+// untilNext is how long to sleep before the earliest deadline, never past
+// limit
+func (q *deadlineQueue) untilNext(limit time.Time) time.Duration {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	next := limit
+	if len(q.deadlines) > 0 && q.deadlines[0].Before(limit) {
+		next = q.deadlines[0]
+	}
+
+	return max(time.Until(next), 0)
+}
+
+type deadlineHeap []time.Time
+
+func (h deadlineHeap) Len() int           { return len(h) }
+func (h deadlineHeap) Less(i, j int) bool { return h[i].Before(h[j]) }
+func (h deadlineHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *deadlineHeap) Push(x any) { *h = append(*h, x.(time.Time)) }
+
+func (h *deadlineHeap) Pop() any {
+	old := *h
+	deadline := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return deadline
+}
+
+// resyncDeadlines loads the deadlines coming up within the horizon, including
+// the ones scheduled by other replicas, jobs whose lease expired, Dutch
+// price drops and reminders
+func (ar *AuctionRepository) resyncDeadlines() {
+	ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 30*time.Second)
+	defer cancel()
+
+	horizon := time.Now().Add(ar.deadlines.horizon).Unix()
+
+	jobFilter := bson.M{"end_time": bson.M{"$lte": horizon}}
+	jobOptions := options.Find().SetProjection(bson.M{"end_time": 1, "lease_until": 1})
+	cursor, err := ar.closeJobCollection.Find(ctx, jobFilter, jobOptions)
+	if err != nil {
+		logger.Error("Error loading upcoming close jobs", err)
+		return
+	}
+
+	var closeJobs []CloseJobMongo
+	if err := cursor.All(ctx, &closeJobs); err != nil {
+		logger.Error("Error loading upcoming close jobs", err)
+		return
+	}
+	for _, closeJob := range closeJobs {
+		ar.deadlines.push(time.Unix(max(closeJob.EndTime, closeJob.LeaseUntil), 0))
+	}
+
+	auctionFilter := bson.M{
+		"status":     auction_entity.Scheduled,
+		"start_time": bson.M{"$lte": horizon},
+	}
+	auctionOptions := options.Find().SetProjection(bson.M{"start_time": 1})
+	cursor, err = ar.Collection.Find(ctx, auctionFilter, auctionOptions)
+	if err != nil {
+		logger.Error("Error loading upcoming scheduled auctions", err)
+		return
+	}
+
+	var auctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctions); err != nil {
+		logger.Error("Error loading upcoming scheduled auctions", err)
+		return
+	}
+	for _, auction := range auctions {
+		ar.deadlines.push(time.Unix(auction.StartTime, 0))
+	}
+
+	ar.resyncDutchDrops(ctx, horizon)
+	ar.resyncReminders(ctx, horizon)
+}
+
+// getSchedulerResync is how often the scheduler reloads upcoming deadlines
+// from the database, it also bounds how far ahead the heap looks
+func getSchedulerResync() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("AUCTION_SCHEDULER_RESYNC"))
+	if err != nil || duration <= 0 {
+		return time.Minute
+	}
+
+	return duration
+}
@@ -0,0 +1,64 @@
+package auction
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"context"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// getCloserDryRun makes the lifecycle scheduler only log what it would do,
+// so its timing can be checked against a production snapshot in staging
+func getCloserDryRun() bool {
+	return os.Getenv("AUCTION_CLOSER_DRY_RUN") == "true"
+}
+
+// reportDueAuctions logs the auctions that became due since the last report
+// and would be activated or closed, without writing anything
+func (ar *AuctionRepository) reportDueAuctions() {
+	ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	window := bson.M{"$gt": ar.reportedUntil.Unix(), "$lte": now.Unix()}
+
+	scheduledFilter := bson.M{"status": auction_entity.Scheduled, "start_time": window}
+	err := mongodb.Stream(ctx, ar.Collection, scheduledFilter, func(auction AuctionEntityMongo) error {
+		logger.Info("Dry run: would activate scheduled auction",
+			zap.String("auctionID", auction.Id),
+			zap.Time("startTime", startTimeOf(auction)))
+		return nil
+	})
+	if err != nil {
+		logger.Error("Error reporting scheduled auctions", err)
+		return
+	}
+
+	activeFilter := bson.M{"status": auction_entity.Active, "end_time": window}
+	err = mongodb.Stream(ctx, ar.Collection, activeFilter, func(auction AuctionEntityMongo) error {
+		fields, errFields := ar.completionFields(ctx, auction)
+		if errFields != nil {
+			logger.Error("Error finding the winner of expired auction", errFields, zap.String("auctionID", auction.Id))
+			return nil
+		}
+
+		winnerUserId, _ := fields["winner_user_id"].(string)
+		logger.Info("Dry run: would close expired auction",
+			zap.String("auctionID", auction.Id),
+			zap.Time("endTime", ar.endTimeOf(auction)),
+			zap.Stringer("status", fields["status"].(auction_entity.AuctionStatus)),
+			zap.String("winnerUserID", winnerUserId))
+		return nil
+	})
+	if err != nil {
+		logger.Error("Error reporting expired auctions", err)
+		return
+	}
+
+	ar.reportedUntil = now
+}
@@ -0,0 +1,234 @@
+package auction
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// DutchPricingMongo is the price schedule of a Dutch auction. Price is what
+// it sells for now and NextDrop when the scheduler lowers it next, zero once
+// it reached the floor
+type DutchPricingMongo struct {
+	StartPrice float64 `bson:"start_price"`
+	FloorPrice float64 `bson:"floor_price"`
+	Decrement  float64 `bson:"decrement"`
+	Interval   int64   `bson:"interval"`
+	Price      float64 `bson:"price"`
+	NextDrop   int64   `bson:"next_drop,omitempty"`
+}
+
+// newDutchPricingMongo starts the price schedule of a Dutch auction at its
+// start time, nil for other auctions
+func newDutchPricingMongo(auctionEntity *auction_entity.Auction, startTime time.Time) *DutchPricingMongo {
+	if auctionEntity.Type != auction_entity.Dutch {
+		return nil
+	}
+
+	pricing := auctionEntity.DutchPricing
+	return &DutchPricingMongo{
+		StartPrice: pricing.StartPrice,
+		FloorPrice: pricing.FloorPrice,
+		Decrement:  pricing.Decrement,
+		Interval:   int64(pricing.Interval.Seconds()),
+		Price:      pricing.StartPrice,
+		NextDrop:   startTime.Add(pricing.Interval).Unix(),
+	}
+}
+
+func dutchPricingOf(auctionEntityMongo AuctionEntityMongo) auction_entity.DutchPricing {
+	if auctionEntityMongo.Dutch == nil {
+		return auction_entity.DutchPricing{}
+	}
+
+	return auction_entity.DutchPricing{
+		StartPrice: auctionEntityMongo.Dutch.StartPrice,
+		FloorPrice: auctionEntityMongo.Dutch.FloorPrice,
+		Decrement:  auctionEntityMongo.Dutch.Decrement,
+		Interval:   time.Duration(auctionEntityMongo.Dutch.Interval) * time.Second,
+	}
+}
+
+func dutchPriceOf(auctionEntityMongo AuctionEntityMongo) float64 {
+	if auctionEntityMongo.Dutch == nil {
+		return 0
+	}
+
+	return auctionEntityMongo.Dutch.Price
+}
+
+// TakeDutchAuction ends a running Dutch auction with userId as the winner at
+// its current price, as long as amount covers it, and returns the price paid.
+// Like BuyNow the status change is a compare-and-swap, so of concurrent
+// bidders only the first one gets it
+func (ar *AuctionRepository) TakeDutchAuction(
+	ctx context.Context,
+	id, userId string,
+	amount float64) (float64, *internal_error.InternalError) {
+	// Stop the bidding first, nothing else may close the auction meanwhile
+	if err := ar.closeBidding(ctx, id); err != nil {
+		logger.Error("Error closing auction to bids", err, zap.String("auctionID", id))
+		return 0, internal_error.NewInternalServerError("Error taking Dutch auction")
+	}
+
+	filter := transitionFilter(id, auction_entity.Completed, auction_entity.Active)
+	filter["type"] = auction_entity.Dutch
+	filter["end_time"] = bson.M{"$gt": time.Now().Unix()}
+	filter["dutch.price"] = bson.M{"$lte": amount}
+
+	fields := bson.M{
+		"status":         auction_entity.Completed,
+		"winner_user_id": userId,
+		"winning_amount": "$dutch.price",
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: fields}}}
+
+	var auctionEntityMongo AuctionEntityMongo
+	err := ar.inTransaction(ctx, func(ctx context.Context) error {
+		err := ar.Collection.FindOneAndUpdate(ctx, filter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&auctionEntityMongo)
+		if err != nil {
+			return err
+		}
+		if err := ar.logChange(ctx, history_entity.StatusChanged, id); err != nil {
+			return err
+		}
+		return ar.addClosedToOutbox(ctx, id, fields)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		ar.restoreBidding(ctx, id)
+		return 0, internal_error.NewInvalidTransitionError("This auction no longer sells at this price")
+	}
+	if err != nil {
+		logger.Error("Error taking Dutch auction", err, zap.String("auctionID", id))
+		ar.restoreBidding(ctx, id)
+		return 0, internal_error.NewInternalServerError("Error taking Dutch auction")
+	}
+
+	if err := ar.deleteCloseJob(ctx, id); err != nil {
+		logger.Error("Error deleting auction close job", err, zap.String("auctionID", id))
+	}
+
+	ar.notifyScheduleChange(id)
+	ar.runCloseHooks(ctx, id, fields)
+
+	return auctionEntityMongo.WinningAmount, nil
+}
+
+// dropDutchPrices lowers the price of the running Dutch auctions whose next
+// drop is due, catching up on the drops missed while no replica led
+func (ar *AuctionRepository) dropDutchPrices() {
+	ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 30*time.Second)
+	defer cancel()
+
+	now := time.Now().Unix()
+	filter := bson.M{
+		"status":          auction_entity.Active,
+		"type":            auction_entity.Dutch,
+		"dutch.next_drop": bson.M{"$gt": 0, "$lte": now},
+	}
+
+	err := mongodb.Stream(ctx, ar.Collection, filter, func(auction AuctionEntityMongo) error {
+		pricing := dutchPricingOf(auction)
+
+		price, nextDrop := auction.Dutch.Price, auction.Dutch.NextDrop
+		for nextDrop != 0 && nextDrop <= now {
+			price = pricing.NextPrice(price)
+			nextDrop += auction.Dutch.Interval
+			if price <= pricing.FloorPrice {
+				nextDrop = 0
+			}
+		}
+
+		// A bid taking the auction moves it out of Active, a replica that
+		// dropped the price first moves its next drop
+		result, err := ar.updateAndLog(ctx, history_entity.PriceDropped, auction.Id,
+			bson.M{"_id": auction.Id, "status": auction_entity.Active, "dutch.next_drop": auction.Dutch.NextDrop},
+			bson.M{"$set": bson.M{"dutch.price": price, "dutch.next_drop": nextDrop}})
+		if err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			return nil
+		}
+
+		if nextDrop != 0 {
+			ar.deadlines.push(time.Unix(nextDrop, 0))
+		}
+		logger.Info("Dutch auction price dropped", zap.String("auctionID", auction.Id), zap.Float64("price", price))
+		ar.runPriceDropHooks(ctx, auction.Id, price)
+
+		return nil
+	})
+	if err != nil {
+		logger.Error("Error dropping Dutch auction prices", err)
+	}
+}
+
+// PriceDropHook runs after a Dutch auction's price dropped to price
+type PriceDropHook func(ctx context.Context, auctionId string, price float64)
+
+// RegisterOnPriceDropHook adds a side effect to run whenever the lifecycle
+// scheduler lowers the price of a Dutch auction, so only on the leader.
+// Hooks run on the scheduler, slow work should be handed off
+func (ar *AuctionRepository) RegisterOnPriceDropHook(hook PriceDropHook) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.priceDropHooks = append(ar.priceDropHooks, hook)
+}
+
+func (ar *AuctionRepository) runPriceDropHooks(ctx context.Context, auctionId string, price float64) {
+	ar.auctionsMutex.RLock()
+	hooks := ar.priceDropHooks
+	ar.auctionsMutex.RUnlock()
+
+	for _, hook := range hooks {
+		runPriceDropHook(ctx, hook, auctionId, price)
+	}
+}
+
+// A failing hook must not stop the other price drops
+func runPriceDropHook(ctx context.Context, hook PriceDropHook, auctionId string, price float64) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Auction price drop hook panicked", fmt.Errorf("%v", r), zap.String("auctionID", auctionId))
+		}
+	}()
+
+	hook(ctx, auctionId, price)
+}
+
+// resyncDutchDrops loads the price drops coming up until horizon
+func (ar *AuctionRepository) resyncDutchDrops(ctx context.Context, horizon int64) {
+	filter := bson.M{
+		"status":          auction_entity.Active,
+		"type":            auction_entity.Dutch,
+		"dutch.next_drop": bson.M{"$gt": 0, "$lte": horizon},
+	}
+	cursor, err := ar.Collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"dutch.next_drop": 1}))
+	if err != nil {
+		logger.Error("Error loading upcoming Dutch price drops", err)
+		return
+	}
+
+	var auctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctions); err != nil {
+		logger.Error("Error loading upcoming Dutch price drops", err)
+		return
+	}
+	for _, auction := range auctions {
+		ar.deadlines.push(time.Unix(auction.Dutch.NextDrop, 0))
+	}
+}
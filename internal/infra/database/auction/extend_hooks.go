@@ -0,0 +1,44 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ExtendHook runs after a running auction's end was pushed back to endTime
+type ExtendHook func(ctx context.Context, auctionId string, endTime time.Time)
+
+// RegisterOnExtendHook adds a side effect to run whenever a late bid keeps
+// an auction open longer. Hooks run on the bid's write, so slow work should
+// be handed off
+func (ar *AuctionRepository) RegisterOnExtendHook(hook ExtendHook) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.extendHooks = append(ar.extendHooks, hook)
+}
+
+func (ar *AuctionRepository) runExtendHooks(ctx context.Context, auctionId string, endTime time.Time) {
+	ar.auctionsMutex.RLock()
+	hooks := ar.extendHooks
+	ar.auctionsMutex.RUnlock()
+
+	for _, hook := range hooks {
+		runExtendHook(ctx, hook, auctionId, endTime)
+	}
+}
+
+// A failing hook must not fail the extension nor stop the other hooks
+func runExtendHook(ctx context.Context, hook ExtendHook, auctionId string, endTime time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Auction extend hook panicked", fmt.Errorf("%v", r), zap.String("auctionID", auctionId))
+		}
+	}()
+
+	hook(ctx, auctionId, endTime)
+}
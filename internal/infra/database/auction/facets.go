@@ -0,0 +1,100 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FindAuctionFacets counts the auctions matching the query in one $facet
+// aggregation, joining the current prices for the price buckets
+func (repo *AuctionRepository) FindAuctionFacets(
+	ctx context.Context,
+	query auction_entity.AuctionQuery) (*auction_entity.AuctionFacets, *internal_error.InternalError) {
+	boundaries := auction_entity.PriceFacetBoundaries
+	lastBoundary := boundaries[len(boundaries)-1]
+
+	pipeline := repo.pricedAuctionStages(auctionFilter(query), query)
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"categories": bson.A{bson.M{"$sortByCount": "$category"}},
+		"conditions": bson.A{
+			bson.M{"$group": bson.M{"_id": "$condition", "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.M{"_id": 1}},
+		},
+		"prices": bson.A{bson.M{"$bucket": bson.M{
+			"groupBy":    "$current_price",
+			"boundaries": boundaries,
+			// Prices past the last boundary are all counted under it
+			"default": lastBoundary,
+		}}},
+	}}})
+
+	cursor, err := repo.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error counting auction facets", err)
+		return nil, internal_error.NewInternalServerError("Error finding auction facets")
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Categories []struct {
+			Category string `bson:"_id"`
+			Count    int64  `bson:"count"`
+		} `bson:"categories"`
+		Conditions []struct {
+			Condition auction_entity.ProductCondition `bson:"_id"`
+			Count     int64                           `bson:"count"`
+		} `bson:"conditions"`
+		Prices []struct {
+			Min   float64 `bson:"_id"`
+			Count int64   `bson:"count"`
+		} `bson:"prices"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		logger.Error("Error decoding auction facets", err)
+		return nil, internal_error.NewInternalServerError("Error finding auction facets")
+	}
+
+	auctionFacets := &auction_entity.AuctionFacets{
+		Categories: []auction_entity.CategoryFacet{},
+		Conditions: []auction_entity.ConditionFacet{},
+		Prices:     []auction_entity.PriceFacet{},
+	}
+	if len(facets) == 0 {
+		return auctionFacets, nil
+	}
+
+	for _, category := range facets[0].Categories {
+		auctionFacets.Categories = append(auctionFacets.Categories,
+			auction_entity.CategoryFacet{Category: category.Category, Count: category.Count})
+	}
+	for _, condition := range facets[0].Conditions {
+		auctionFacets.Conditions = append(auctionFacets.Conditions,
+			auction_entity.ConditionFacet{Condition: condition.Condition, Count: condition.Count})
+	}
+
+	for _, price := range facets[0].Prices {
+		auctionFacets.Prices = append(auctionFacets.Prices, auction_entity.PriceFacet{
+			Min:   price.Min,
+			Max:   priceFacetMax(price.Min),
+			Count: price.Count,
+		})
+	}
+
+	return auctionFacets, nil
+}
+
+// priceFacetMax is the boundary after min, zero for the last bucket
+func priceFacetMax(min float64) float64 {
+	boundaries := auction_entity.PriceFacetBoundaries
+	for i, boundary := range boundaries[:len(boundaries)-1] {
+		if boundary == min {
+			return boundaries[i+1]
+		}
+	}
+
+	return 0
+}
@@ -1,15 +1,22 @@
 package auction
 
 import (
+	"auction_go/configuration/database/mongodb"
 	"auction_go/configuration/logger"
 	"auction_go/internal/entity/auction_entity"
 	"auction_go/internal/internal_error"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (ar *AuctionRepository) FindAuctionById(
@@ -23,60 +30,473 @@ func (ar *AuctionRepository) FindAuctionById(
 	}
 
 	return &auction_entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
+		Id:              auctionEntityMongo.Id,
+		SellerId:        auctionEntityMongo.SellerId,
+		ProductName:     auctionEntityMongo.ProductName,
+		Category:        auctionEntityMongo.Category,
+		Description:     auctionEntityMongo.Description,
+		DescriptionHTML: auctionEntityMongo.DescriptionHTML,
+		Condition:       auctionEntityMongo.Condition,
+		Status:          auctionEntityMongo.Status,
+		Timestamp:       time.Unix(auctionEntityMongo.Timestamp, 0),
+		StartTime:       startTimeOf(auctionEntityMongo),
+		Duration:        ar.durationOf(auctionEntityMongo),
+		EndTime:         ar.endTimeOf(auctionEntityMongo),
+		WinnerUserId:    auctionEntityMongo.WinnerUserId,
+		WinningAmount:   auctionEntityMongo.WinningAmount,
+		ReservePrice:    auctionEntityMongo.ReservePrice,
+		ReservePublic:   auctionEntityMongo.ReservePublic,
+		BuyNowPrice:     auctionEntityMongo.BuyNowPrice,
+		MinIncrement:    auctionEntityMongo.MinIncrement,
+		Type:            auctionEntityMongo.Type,
+		DutchPricing:    dutchPricingOf(auctionEntityMongo),
+		DutchPrice:      dutchPriceOf(auctionEntityMongo),
+		RelistPolicy:    auction_entity.RelistPolicy{MaxRelists: auctionEntityMongo.MaxRelists},
+		RelistCount:     auctionEntityMongo.RelistCount,
+		RelistedFrom:    auctionEntityMongo.RelistedFrom,
+
+		CancellationReason: auctionEntityMongo.CancellationReason,
+		CancelledAt:        unixOrZero(auctionEntityMongo.CancelledAt),
+		PausedAt:           unixOrZero(auctionEntityMongo.PausedAt),
+		Location:           locationOf(auctionEntityMongo),
+		PostalCode:         auctionEntityMongo.PostalCode,
 	}, nil
 }
 
+// FindBiddingWindow reads just the status and end time of the auction. Bids
+// check them on every batch, since another replica may have extended or
+// closed the auction since it was cached
+func (ar *AuctionRepository) FindBiddingWindow(
+	ctx context.Context, id string) (auction_entity.AuctionStatus, time.Time, *internal_error.InternalError) {
+	projection := bson.M{"status": 1, "timestamp": 1, "start_time": 1, "duration": 1, "end_time": 1}
+
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.Collection.FindOne(ctx, bson.M{"_id": id},
+		options.FindOne().SetProjection(projection)).Decode(&auctionEntityMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find the bidding window of auction id = %s", id), err)
+		return 0, time.Time{}, internal_error.NewInternalServerError("Error trying to find auction by id")
+	}
+
+	return auctionEntityMongo.Status, ar.endTimeOf(auctionEntityMongo), nil
+}
+
+// listedAuctionMongo is an auction as listed, CurrentPrice is only looked up
+// when the query filters or sorts by price
+type listedAuctionMongo struct {
+	AuctionEntityMongo `bson:",inline"`
+	CurrentPrice       float64 `bson:"current_price,omitempty"`
+}
+
+// auctionCursor is the sort value and id of the last auction of a page, the
+// next page starts right after it
+type auctionCursor struct {
+	Value float64 `json:"v"`
+	Id    string  `json:"id"`
+}
+
+// FindAuctions lists the auctions matching the query one page at a time.
+// Queries that don't involve the price are plain finds, the others join the
+// current prices in an aggregation
 func (repo *AuctionRepository) FindAuctions(
 	ctx context.Context,
-	status auction_entity.AuctionStatus,
-	category string,
-	productName string) ([]auction_entity.Auction, *internal_error.InternalError) {
-	filter := bson.M{}
+	query auction_entity.AuctionQuery) ([]auction_entity.Auction, string, *internal_error.InternalError) {
+	filter := auctionFilter(query)
 
-	if status != 0 {
-		filter["status"] = status
+	field := sortField(query.Sort)
+	direction := 1
+	if query.Descending {
+		direction = -1
 	}
 
-	if category != "" {
-		filter["category"] = category
+	var after bson.M
+	if query.Cursor != "" {
+		cursor, err := decodeAuctionCursor(query.Cursor)
+		if err != nil {
+			return nil, "", internal_error.NewBadRequestError("Invalid cursor")
+		}
+
+		comparison := "$gt"
+		if query.Descending {
+			comparison = "$lt"
+		}
+		after = bson.M{"$or": bson.A{
+			bson.M{field: bson.M{comparison: cursor.Value}},
+			bson.M{field: cursor.Value, "_id": bson.M{"$gt": cursor.Id}},
+		}}
 	}
 
-	if productName != "" {
-		filter["productName"] = primitive.Regex{Pattern: productName, Options: "i"}
+	order := bson.D{{Key: field, Value: direction}, {Key: "_id", Value: 1}}
+	limit := query.Limit
+	if limit > 0 {
+		// One more than asked tells whether there is a next page
+		limit++
 	}
 
-	cursor, err := repo.Collection.Find(ctx, filter)
+	var auctionsMongo []listedAuctionMongo
+	var err error
+	if query.Sort == auction_entity.SortByPrice || query.MinPrice > 0 || query.MaxPrice > 0 {
+		auctionsMongo, err = repo.findAuctionsByPrice(ctx, filter, after, query, order, limit)
+	} else {
+		if after != nil {
+			filter = bson.M{"$and": bson.A{filter, after}}
+		}
+		auctionsMongo, err = repo.findListedAuctions(ctx, filter, order, limit)
+	}
 	if err != nil {
 		logger.Error("Error finding auctions", err)
-		return nil, internal_error.NewInternalServerError("Error finding auctions")
+		return nil, "", internal_error.NewInternalServerError("Error finding auctions")
+	}
+
+	nextCursor := ""
+	if query.Limit > 0 && int64(len(auctionsMongo)) > query.Limit {
+		auctionsMongo = auctionsMongo[:query.Limit]
+		nextCursor = encodeAuctionCursor(auctionCursorOf(auctionsMongo[query.Limit-1], query.Sort))
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, listed := range auctionsMongo {
+		auction := listed.AuctionEntityMongo
+		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
+			Id:              auction.Id,
+			SellerId:        auction.SellerId,
+			ProductName:     auction.ProductName,
+			Category:        auction.Category,
+			Status:          auction.Status,
+			Description:     auction.Description,
+			DescriptionHTML: auction.DescriptionHTML,
+			Condition:       auction.Condition,
+			Timestamp:       time.Unix(auction.Timestamp, 0),
+			StartTime:       startTimeOf(auction),
+			Duration:        repo.durationOf(auction),
+			EndTime:         repo.endTimeOf(auction),
+			WinnerUserId:    auction.WinnerUserId,
+			WinningAmount:   auction.WinningAmount,
+			ReservePrice:    auction.ReservePrice,
+			ReservePublic:   auction.ReservePublic,
+			BuyNowPrice:     auction.BuyNowPrice,
+			MinIncrement:    auction.MinIncrement,
+			Type:            auction.Type,
+			DutchPricing:    dutchPricingOf(auction),
+			DutchPrice:      dutchPriceOf(auction),
+			RelistPolicy:    auction_entity.RelistPolicy{MaxRelists: auction.MaxRelists},
+			RelistCount:     auction.RelistCount,
+			RelistedFrom:    auction.RelistedFrom,
+
+			CancellationReason: auction.CancellationReason,
+			CancelledAt:        unixOrZero(auction.CancelledAt),
+			PausedAt:           unixOrZero(auction.PausedAt),
+			Location:           locationOf(auction),
+			PostalCode:         auction.PostalCode,
+		})
+	}
+
+	return auctionsEntity, nextCursor, nil
+}
+
+// auctionFilter matches the auctions the query lists, the price filters
+// are left to priceFilter since the price has to be joined first
+func auctionFilter(query auction_entity.AuctionQuery) bson.M {
+	filter := bson.M{}
+
+	if query.Status != nil {
+		filter["status"] = *query.Status
+	}
+
+	if query.Category != "" {
+		filter["category"] = query.Category
+	}
+
+	if query.Condition != 0 {
+		filter["condition"] = query.Condition
+	}
+
+	if query.SellerId != "" {
+		filter["seller_id"] = query.SellerId
+	}
+
+	if !query.EndsBefore.IsZero() {
+		filter["end_time"] = bson.M{"$lte": query.EndsBefore.Unix()}
+	}
+
+	if !query.CreatedAfter.IsZero() {
+		filter["timestamp"] = bson.M{"$gte": query.CreatedAfter.Unix()}
+	}
+
+	if query.Near != nil {
+		filter["location"] = withinRadius(*query.Near, query.RadiusKm)
+	}
+
+	// Each query matches product names containing all of its words, any
+	// query matching is enough
+	if len(query.ProductNameQueries) > 0 {
+		var anyQuery bson.A
+		for _, productNameQuery := range query.ProductNameQueries {
+			var allWords bson.A
+			for _, word := range strings.Fields(productNameQuery) {
+				allWords = append(allWords, bson.M{"product_name": primitive.Regex{
+					Pattern: regexp.QuoteMeta(word), Options: "i"}})
+			}
+			anyQuery = append(anyQuery, bson.M{"$and": allWords})
+		}
+		filter["$or"] = anyQuery
+	}
+
+	return filter
+}
+
+// priceFilter bounds the joined current price, nil when the query doesn't
+func priceFilter(query auction_entity.AuctionQuery) bson.M {
+	price := bson.M{}
+	if query.MinPrice > 0 {
+		price["$gte"] = query.MinPrice
+	}
+	if query.MaxPrice > 0 {
+		price["$lte"] = query.MaxPrice
+	}
+	if len(price) == 0 {
+		return nil
+	}
+
+	return bson.M{"current_price": price}
+}
+
+func (repo *AuctionRepository) findListedAuctions(
+	ctx context.Context, filter bson.M, order bson.D, limit int64) ([]listedAuctionMongo, error) {
+	findOptions := options.Find().SetSort(order)
+	if limit > 0 {
+		findOptions.SetLimit(limit)
+	}
+
+	mongodb.Explain(ctx, repo.Collection, filter, findOptions)
+
+	cursor, err := repo.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var auctionsMongo []listedAuctionMongo
+	err = cursor.All(ctx, &auctionsMongo)
+	return auctionsMongo, err
+}
+
+// findAuctionsByPrice joins each matching auction's current price before
+// filtering and sorting by it
+func (repo *AuctionRepository) findAuctionsByPrice(
+	ctx context.Context,
+	filter, after bson.M,
+	query auction_entity.AuctionQuery,
+	order bson.D,
+	limit int64) ([]listedAuctionMongo, error) {
+	pipeline := repo.pricedAuctionStages(filter, query)
+	if after != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: after}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: order}})
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	cursor, err := repo.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var auctionsMongo []listedAuctionMongo
+	err = cursor.All(ctx, &auctionsMongo)
+	return auctionsMongo, err
+}
+
+// pricedAuctionStages matches the filter and joins the current price of
+// each auction, keeping those within the query's price bounds
+func (repo *AuctionRepository) pricedAuctionStages(filter bson.M, query auction_entity.AuctionQuery) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         repo.currentPriceCollection.Name(),
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "current_price",
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"current_price": bson.M{"$ifNull": bson.A{bson.M{"$first": "$current_price.amount"}, 0}},
+		}}},
+	}
+	if price := priceFilter(query); price != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: price}})
+	}
+
+	return pipeline
+}
+
+func sortField(sort auction_entity.AuctionSort) string {
+	switch sort {
+	case auction_entity.SortByPrice:
+		return "current_price"
+	case auction_entity.SortByCreatedAt:
+		return "timestamp"
+	default:
+		return "end_time"
+	}
+}
+
+func auctionCursorOf(auction listedAuctionMongo, sort auction_entity.AuctionSort) auctionCursor {
+	switch sort {
+	case auction_entity.SortByPrice:
+		return auctionCursor{Value: auction.CurrentPrice, Id: auction.Id}
+	case auction_entity.SortByCreatedAt:
+		return auctionCursor{Value: float64(auction.Timestamp), Id: auction.Id}
+	default:
+		return auctionCursor{Value: float64(auction.EndTime), Id: auction.Id}
+	}
+}
+
+func encodeAuctionCursor(cursor auctionCursor) string {
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeAuctionCursor(cursor string) (auctionCursor, error) {
+	var decoded auctionCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return decoded, err
+	}
+
+	err = json.Unmarshal(raw, &decoded)
+	return decoded, err
+}
+
+// FindAuctionsBySellerId returns one page of the seller's auctions, newest
+// first, along with the total number of matching auctions
+func (repo *AuctionRepository) FindAuctionsBySellerId(
+	ctx context.Context,
+	sellerId string,
+	status auction_entity.AuctionStatus,
+	skip, limit int64) ([]auction_entity.Auction, int64, *internal_error.InternalError) {
+	filter := bson.M{"seller_id": sellerId, "status": status}
+
+	total, err := repo.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error("Error counting seller auctions", err)
+		return nil, 0, internal_error.NewInternalServerError("Error finding seller auctions")
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	mongodb.Explain(ctx, repo.Collection, filter, findOptions)
+
+	cursor, err := repo.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error finding seller auctions", err)
+		return nil, 0, internal_error.NewInternalServerError("Error finding seller auctions")
 	}
 	defer cursor.Close(ctx)
 
 	var auctionsMongo []AuctionEntityMongo
 	if err := cursor.All(ctx, &auctionsMongo); err != nil {
-		logger.Error("Error decoding auctions", err)
-		return nil, internal_error.NewInternalServerError("Error decoding auctions")
+		logger.Error("Error decoding seller auctions", err)
+		return nil, 0, internal_error.NewInternalServerError("Error decoding seller auctions")
 	}
 
 	var auctionsEntity []auction_entity.Auction
 	for _, auction := range auctionsMongo {
 		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
+			Id:              auction.Id,
+			SellerId:        auction.SellerId,
+			ProductName:     auction.ProductName,
+			Category:        auction.Category,
+			Status:          auction.Status,
+			Description:     auction.Description,
+			DescriptionHTML: auction.DescriptionHTML,
+			Condition:       auction.Condition,
+			Timestamp:       time.Unix(auction.Timestamp, 0),
+			StartTime:       startTimeOf(auction),
+			Duration:        repo.durationOf(auction),
+			EndTime:         repo.endTimeOf(auction),
+			WinnerUserId:    auction.WinnerUserId,
+			WinningAmount:   auction.WinningAmount,
+			ReservePrice:    auction.ReservePrice,
+			ReservePublic:   auction.ReservePublic,
+			BuyNowPrice:     auction.BuyNowPrice,
+			MinIncrement:    auction.MinIncrement,
+			Type:            auction.Type,
+			DutchPricing:    dutchPricingOf(auction),
+			DutchPrice:      dutchPriceOf(auction),
+			RelistPolicy:    auction_entity.RelistPolicy{MaxRelists: auction.MaxRelists},
+			RelistCount:     auction.RelistCount,
+			RelistedFrom:    auction.RelistedFrom,
+
+			CancellationReason: auction.CancellationReason,
+			CancelledAt:        unixOrZero(auction.CancelledAt),
+			PausedAt:           unixOrZero(auction.PausedAt),
+			Location:           locationOf(auction),
+			PostalCode:         auction.PostalCode,
 		})
 	}
 
-	return auctionsEntity, nil
+	return auctionsEntity, total, nil
+}
+
+// StreamAuctions hands every auction in the given status to fn one at a
+// time, for bulk jobs that must not hold the whole collection in memory. A
+// fn error stops the iteration
+func (repo *AuctionRepository) StreamAuctions(
+	ctx context.Context,
+	status auction_entity.AuctionStatus,
+	fn func(auction auction_entity.Auction) error) *internal_error.InternalError {
+	filter := bson.M{"status": status}
+
+	err := mongodb.Stream(ctx, repo.Collection, filter, func(auction AuctionEntityMongo) error {
+		return fn(auction_entity.Auction{
+			Id:              auction.Id,
+			SellerId:        auction.SellerId,
+			ProductName:     auction.ProductName,
+			Category:        auction.Category,
+			Status:          auction.Status,
+			Description:     auction.Description,
+			DescriptionHTML: auction.DescriptionHTML,
+			Condition:       auction.Condition,
+			Timestamp:       time.Unix(auction.Timestamp, 0),
+			StartTime:       startTimeOf(auction),
+			Duration:        repo.durationOf(auction),
+			EndTime:         repo.endTimeOf(auction),
+			WinnerUserId:    auction.WinnerUserId,
+			WinningAmount:   auction.WinningAmount,
+			ReservePrice:    auction.ReservePrice,
+			ReservePublic:   auction.ReservePublic,
+			BuyNowPrice:     auction.BuyNowPrice,
+			MinIncrement:    auction.MinIncrement,
+			Type:            auction.Type,
+			DutchPricing:    dutchPricingOf(auction),
+			DutchPrice:      dutchPriceOf(auction),
+			RelistPolicy:    auction_entity.RelistPolicy{MaxRelists: auction.MaxRelists},
+			RelistCount:     auction.RelistCount,
+			RelistedFrom:    auction.RelistedFrom,
+
+			CancellationReason: auction.CancellationReason,
+			CancelledAt:        unixOrZero(auction.CancelledAt),
+			PausedAt:           unixOrZero(auction.PausedAt),
+			Location:           locationOf(auction),
+			PostalCode:         auction.PostalCode,
+		})
+	})
+	if err != nil {
+		logger.Error("Error streaming auctions", err)
+		return internal_error.NewInternalServerError("Error streaming auctions")
+	}
+
+	return nil
+}
+
+// unixOrZero keeps unset timestamps as the zero time instead of the epoch
+func unixOrZero(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(seconds, 0)
 }
@@ -0,0 +1,109 @@
+package auction
+
+import (
+	"auction_go/internal/entity/history_entity"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EventLog appends the auction's changes to its history. It writes through
+// the ctx it is given, so the events commit along with the change
+type EventLog interface {
+	AppendEvent(ctx context.Context, event history_entity.AuctionEvent) error
+}
+
+// SetEventLog has every change made to an auction from then on appended to
+// eventLog, in the same transaction as the change
+func (ar *AuctionRepository) SetEventLog(eventLog EventLog) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.eventLog = eventLog
+}
+
+func (ar *AuctionRepository) getEventLog() EventLog {
+	ar.auctionsMutex.RLock()
+	defer ar.auctionsMutex.RUnlock()
+
+	return ar.eventLog
+}
+
+// logChange appends the auctions as the change left them, reading them
+// through ctx so a transaction sees its own writes
+func (ar *AuctionRepository) logChange(ctx context.Context, eventType history_entity.EventType, ids ...string) error {
+	eventLog := ar.getEventLog()
+	if eventLog == nil || len(ids) == 0 {
+		return nil
+	}
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+
+	var auctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctions); err != nil {
+		return err
+	}
+
+	for _, auction := range auctions {
+		if err := eventLog.AppendEvent(ctx, ar.newAuctionEvent(auction, eventType)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ar *AuctionRepository) newAuctionEvent(
+	auction AuctionEntityMongo, eventType history_entity.EventType) history_entity.AuctionEvent {
+	return history_entity.CreateAuctionEvent(auction.Id, eventType, history_entity.AuctionSnapshot{
+		SellerId:           auction.SellerId,
+		ProductName:        auction.ProductName,
+		Category:           auction.Category,
+		Type:               auction.Type,
+		Status:             auction.Status,
+		StartTime:          startTimeOf(auction),
+		EndTime:            ar.endTimeOf(auction),
+		ReservePrice:       auction.ReservePrice,
+		BuyNowPrice:        auction.BuyNowPrice,
+		DutchPrice:         dutchPriceOf(auction),
+		WinnerUserId:       auction.WinnerUserId,
+		WinningAmount:      auction.WinningAmount,
+		CancellationReason: auction.CancellationReason,
+	})
+}
+
+// logCreated appends the auction as it was inserted
+func (ar *AuctionRepository) logCreated(ctx context.Context, auction *AuctionEntityMongo) error {
+	eventLog := ar.getEventLog()
+	if eventLog == nil {
+		return nil
+	}
+
+	return eventLog.AppendEvent(ctx, ar.newAuctionEvent(*auction, history_entity.AuctionCreated))
+}
+
+// updateAndLog runs the update and, when it changed the auction, appends
+// the change to the event log in the same transaction
+func (ar *AuctionRepository) updateAndLog(
+	ctx context.Context,
+	eventType history_entity.EventType,
+	id string,
+	filter, update interface{}) (*mongo.UpdateResult, error) {
+	var result *mongo.UpdateResult
+	err := ar.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = ar.Collection.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			return nil
+		}
+		return ar.logChange(ctx, eventType, id)
+	})
+
+	return result, err
+}
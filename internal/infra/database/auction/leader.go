@@ -0,0 +1,114 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// schedulerLeaseId is the lease document electing the replica that runs the
+// lifecycle scheduler
+const schedulerLeaseId = "auction_lifecycle_scheduler"
+
+// LeaderLeaseMongo is held by the elected replica, which renews it on every
+// heartbeat. Once it stops renewing, the lease expires and another replica
+// takes over
+type LeaderLeaseMongo struct {
+	Id         string `bson:"_id"`
+	Owner      string `bson:"owner"`
+	LeaseUntil int64  `bson:"lease_until"`
+}
+
+// Campaign for the scheduler lease every third of its duration, so the
+// leader renews it well before it expires. A dry run never takes the lease
+// from the replicas doing the real work, it reports on its own
+func (ar *AuctionRepository) startLeaderElection() {
+	if ar.dryRun {
+		ar.leader.Store(true)
+		return
+	}
+
+	ticker := time.NewTicker(ar.leaderLease / 3)
+	defer ticker.Stop()
+
+	for {
+		ar.campaign()
+
+		select {
+		case <-ticker.C:
+		case <-ar.auctionCloserCtx.Done():
+			ar.resign()
+			return
+		}
+	}
+}
+
+// campaign takes the lease when it is free or expired, or renews it when
+// this replica already holds it
+func (ar *AuctionRepository) campaign() {
+	ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{
+		"_id": schedulerLeaseId,
+		"$or": bson.A{
+			bson.M{"owner": ar.closerId},
+			bson.M{"lease_until": bson.M{"$lt": now.UnixMilli()}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"owner":       ar.closerId,
+		"lease_until": now.Add(ar.leaderLease).UnixMilli(),
+	}}
+
+	// Another replica holding the lease makes the upsert collide with its
+	// document, that only means this one isn't the leader
+	_, err := ar.leaderCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil && !mongo.IsDuplicateKeyError(err) && ctx.Err() == nil {
+		logger.Error("Error campaigning for the auction scheduler lease", err)
+	}
+
+	elected := err == nil
+	if wasLeader := ar.leader.Swap(elected); wasLeader != elected {
+		if elected {
+			logger.Info("Elected to run the auction scheduler", zap.String("replica", ar.closerId))
+			ar.deadlines.wakeUp()
+		} else {
+			logger.Info("No longer running the auction scheduler", zap.String("replica", ar.closerId))
+		}
+	}
+}
+
+// resign gives the lease up on shutdown, so another replica takes over
+// without waiting for it to expire
+func (ar *AuctionRepository) resign() {
+	if !ar.leader.Swap(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": schedulerLeaseId, "owner": ar.closerId}
+	if _, err := ar.leaderCollection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error releasing the auction scheduler lease", err)
+	}
+}
+
+// getLeaderLease is how long the elected replica keeps running the scheduler
+// without renewing, it bounds how long a failover takes
+func getLeaderLease() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("AUCTION_LEADER_LEASE"))
+	if err != nil || duration <= 0 {
+		return 15 * time.Second
+	}
+
+	return duration
+}
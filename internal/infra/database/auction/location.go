@@ -0,0 +1,51 @@
+package auction
+
+import (
+	"auction_go/internal/entity/auction_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// earthRadiusKm turns distances into the radians $centerSphere takes
+const earthRadiusKm = 6378.1
+
+// GeoJSONPointMongo is stored as GeoJSON so the 2dsphere index on location
+// can serve distance queries, coordinates go longitude first
+type GeoJSONPointMongo struct {
+	Type        string    `bson:"type"`
+	Coordinates []float64 `bson:"coordinates"`
+}
+
+func newLocationMongo(location *auction_entity.GeoPoint) *GeoJSONPointMongo {
+	if location == nil {
+		return nil
+	}
+
+	return &GeoJSONPointMongo{
+		Type:        "Point",
+		Coordinates: []float64{location.Longitude, location.Latitude},
+	}
+}
+
+func locationOf(auctionEntityMongo AuctionEntityMongo) *auction_entity.GeoPoint {
+	location := auctionEntityMongo.Location
+	if location == nil || len(location.Coordinates) != 2 {
+		return nil
+	}
+
+	return &auction_entity.GeoPoint{
+		Latitude:  location.Coordinates[1],
+		Longitude: location.Coordinates[0],
+	}
+}
+
+// withinRadius matches locations within radiusKm of near. Unlike $near it
+// leaves the order alone, so it works with every sort and in aggregations
+func withinRadius(near auction_entity.GeoPoint, radiusKm float64) bson.M {
+	return bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{
+			bson.A{near.Longitude, near.Latitude},
+			radiusKm / earthRadiusKm,
+		},
+	}}
+}
@@ -0,0 +1,36 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// BackfillEndTimes stores the end time on auctions created before it was
+// persisted, computed from their duration or, lacking one, the current
+// AUCTION_INTERVAL. It only touches documents without an end time, so it is
+// safe to run on every start
+func (ar *AuctionRepository) BackfillEndTimes(ctx context.Context) (int64, *internal_error.InternalError) {
+	filter := bson.M{"end_time": bson.M{"$exists": false}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"end_time": bson.M{"$add": bson.A{
+			"$timestamp",
+			bson.M{"$ifNull": bson.A{"$duration", int64(ar.auctionInterval.Seconds())}},
+		}},
+	}}}
+
+	result, err := ar.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error backfilling auction end times", err)
+		return 0, internal_error.NewInternalServerError("Error backfilling auction end times")
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.Info("Auction end times backfilled", zap.Int64("auctions", result.ModifiedCount))
+	}
+
+	return result.ModifiedCount, nil
+}
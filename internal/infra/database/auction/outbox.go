@@ -0,0 +1,60 @@
+package auction
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/internal/entity/auction_entity"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Outbox keeps the auction events published outside the process. It writes
+// through the ctx it is given, so its entries commit along with the change
+// they describe
+type Outbox interface {
+	AddAuctionCreated(ctx context.Context, auction auction_entity.Auction) error
+	AddAuctionClosed(ctx context.Context, auctionId, winnerUserId string) error
+}
+
+// SetOutbox has every auction created or closed from then on add its event
+// to outbox, in the same transaction as the auction's write. Without one
+// the events only reach the hooks
+func (ar *AuctionRepository) SetOutbox(outbox Outbox) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.outbox = outbox
+}
+
+func (ar *AuctionRepository) getOutbox() Outbox {
+	ar.auctionsMutex.RLock()
+	defer ar.auctionsMutex.RUnlock()
+
+	return ar.outbox
+}
+
+// inTransaction runs write in a transaction, see mongodb.RunInTransaction
+func (ar *AuctionRepository) inTransaction(ctx context.Context, write func(ctx context.Context) error) error {
+	return mongodb.RunInTransaction(ctx, ar.Collection.Database(), write)
+}
+
+func (ar *AuctionRepository) addCreatedToOutbox(ctx context.Context, auction auction_entity.Auction) error {
+	outbox := ar.getOutbox()
+	if outbox == nil {
+		return nil
+	}
+
+	return outbox.AddAuctionCreated(ctx, auction)
+}
+
+// addClosedToOutbox takes the fields the auction was completed with, like
+// the close hooks do
+func (ar *AuctionRepository) addClosedToOutbox(ctx context.Context, auctionId string, fields bson.M) error {
+	outbox := ar.getOutbox()
+	if outbox == nil {
+		return nil
+	}
+
+	winnerUserId, _ := fields["winner_user_id"].(string)
+	return outbox.AddAuctionClosed(ctx, auctionId, winnerUserId)
+}
@@ -0,0 +1,33 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// relistIfUnsold is the close hook putting auctions that ended without a
+// winner back on sale, as their relist policy allows
+func (ar *AuctionRepository) relistIfUnsold(ctx context.Context, auctionId, winnerUserId string) {
+	if winnerUserId != "" {
+		return
+	}
+
+	auction, err := ar.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return
+	}
+
+	relisted := auction.Relist()
+	if relisted == nil {
+		return
+	}
+
+	if err := ar.CreateAuction(ctx, relisted); err != nil {
+		return
+	}
+
+	logger.Info("Unsold auction relisted",
+		zap.String("auctionID", auctionId), zap.String("relistedAuctionID", relisted.Id))
+}
@@ -0,0 +1,184 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ReminderHook runs when an auction is about to close at endTime,
+// highBidderId is empty while nobody bid on it
+type ReminderHook func(ctx context.Context, auctionId, highBidderId string, endTime time.Time)
+
+// RegisterOnReminderHook adds a side effect to run at each of the
+// AUCTION_REMINDERS before an auction closes. Reminders are sent by the
+// lifecycle scheduler, so only on the leader, and again for the new end
+// time when the auction is extended. Hooks run on the scheduler, slow work
+// should be handed off
+func (ar *AuctionRepository) RegisterOnReminderHook(hook ReminderHook) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.reminderHooks = append(ar.reminderHooks, hook)
+}
+
+func (ar *AuctionRepository) runReminderHooks(
+	ctx context.Context, auctionId, highBidderId string, endTime time.Time) {
+	ar.auctionsMutex.RLock()
+	hooks := ar.reminderHooks
+	ar.auctionsMutex.RUnlock()
+
+	for _, hook := range hooks {
+		runReminderHook(ctx, hook, auctionId, highBidderId, endTime)
+	}
+}
+
+// A failing hook must not stop the other reminders
+func runReminderHook(
+	ctx context.Context, hook ReminderHook, auctionId, highBidderId string, endTime time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Auction reminder hook panicked", fmt.Errorf("%v", r), zap.String("auctionID", auctionId))
+		}
+	}()
+
+	hook(ctx, auctionId, highBidderId, endTime)
+}
+
+// pushReminders wakes the scheduler for the reminders of an auction ending
+// at endTime, the ones already past are sent on the next wake
+func (ar *AuctionRepository) pushReminders(endTime time.Time) {
+	for _, before := range ar.reminders {
+		ar.deadlines.push(endTime.Add(-before))
+	}
+}
+
+// resyncReminders loads the reminders coming up until horizon from the close
+// jobs, which hold the end time of every running auction
+func (ar *AuctionRepository) resyncReminders(ctx context.Context, horizon int64) {
+	if len(ar.reminders) == 0 {
+		return
+	}
+
+	latest := ar.reminders[len(ar.reminders)-1]
+	filter := bson.M{"end_time": bson.M{
+		"$gt":  time.Now().Unix(),
+		"$lte": horizon + int64(latest.Seconds()),
+	}}
+	cursor, err := ar.closeJobCollection.Find(ctx, filter, options.Find().SetProjection(bson.M{"end_time": 1}))
+	if err != nil {
+		logger.Error("Error loading upcoming auction reminders", err)
+		return
+	}
+
+	var closeJobs []CloseJobMongo
+	if err := cursor.All(ctx, &closeJobs); err != nil {
+		logger.Error("Error loading upcoming auction reminders", err)
+		return
+	}
+	for _, closeJob := range closeJobs {
+		ar.pushReminders(time.Unix(closeJob.EndTime, 0))
+	}
+}
+
+// sendDueReminders sends the reminders of the auctions closing within each
+// of the reminders. The close job records the last one sent for its end
+// time and is claimed before a reminder is sent, so a reminder goes once
+// however many replicas lead in turn. An auction only reminded once it is
+// already within a shorter reminder gets that one alone
+func (ar *AuctionRepository) sendDueReminders() {
+	ctx, cancel := context.WithTimeout(ar.auctionCloserCtx, 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	for _, before := range ar.reminders {
+		filter := bson.M{"end_time": bson.M{"$gt": now.Unix(), "$lte": now.Add(before).Unix()}}
+		cursor, err := ar.closeJobCollection.Find(ctx, filter)
+		if err != nil {
+			logger.Error("Error finding due auction reminders", err)
+			return
+		}
+
+		var closeJobs []CloseJobMongo
+		if err := cursor.All(ctx, &closeJobs); err != nil {
+			logger.Error("Error finding due auction reminders", err)
+			return
+		}
+
+		for _, closeJob := range closeJobs {
+			if closeJob.RemindedEndTime == closeJob.EndTime && closeJob.RemindedBefore <= int64(before.Seconds()) {
+				continue
+			}
+			ar.sendReminder(ctx, closeJob, before)
+		}
+	}
+}
+
+func (ar *AuctionRepository) sendReminder(ctx context.Context, closeJob CloseJobMongo, before time.Duration) {
+	claim := bson.M{
+		"_id":      closeJob.AuctionId,
+		"end_time": closeJob.EndTime,
+		"$or": bson.A{
+			bson.M{"reminded_end_time": bson.M{"$ne": closeJob.EndTime}},
+			bson.M{"reminded_before": bson.M{"$gt": int64(before.Seconds())}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"reminded_end_time": closeJob.EndTime,
+		"reminded_before":   int64(before.Seconds()),
+	}}
+
+	result, err := ar.closeJobCollection.UpdateOne(ctx, claim, update)
+	if err != nil {
+		logger.Error("Error trying to claim auction reminder", err, zap.String("auctionID", closeJob.AuctionId))
+		return
+	}
+	if result.ModifiedCount == 0 {
+		return
+	}
+
+	var currentPrice struct {
+		UserId string `bson:"user_id"`
+	}
+	err = ar.currentPriceCollection.FindOne(ctx, bson.M{"_id": closeJob.AuctionId}).Decode(&currentPrice)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error("Error trying to find the high bidder to remind", err, zap.String("auctionID", closeJob.AuctionId))
+	}
+
+	ar.runReminderHooks(ctx, closeJob.AuctionId, currentPrice.UserId, time.Unix(closeJob.EndTime, 0))
+}
+
+// getReminders reads AUCTION_REMINDERS, how long before the end auctions
+// remind their bidders and watchers, like "1h,10m". Set it to "none" to
+// send no reminders. They are kept sorted from the shortest
+func getReminders() []time.Duration {
+	value := os.Getenv("AUCTION_REMINDERS")
+	if value == "" {
+		value = "1h,10m"
+	} else if value == "none" {
+		return nil
+	}
+
+	var reminders []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || duration <= 0 {
+			logger.Info("Ignoring an invalid auction reminder", zap.String("reminder", part))
+			continue
+		}
+		reminders = append(reminders, duration)
+	}
+
+	slices.Sort(reminders)
+	return slices.Compact(reminders)
+}
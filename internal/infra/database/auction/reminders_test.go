@@ -0,0 +1,117 @@
+package auction
+
+import (
+	"auction_go/internal/testhelpers"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// reminder is one call of a reminder hook
+type reminder struct {
+	auctionId    string
+	highBidderId string
+	endTime      int64
+}
+
+type RemindersSuite struct {
+	suite.Suite
+	database  *mongo.Database
+	repo      *AuctionRepository
+	mutex     sync.Mutex
+	reminders []reminder
+}
+
+func (suite *RemindersSuite) SetupTest() {
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
+	suite.repo = NewAuctionRepository(suite.database)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(suite.T(), suite.repo.Shutdown(ctx))
+
+	// The test drives the scheduler itself
+	suite.repo.auctionCloserCtx, suite.repo.cancelCloser = context.WithCancel(context.Background())
+	suite.T().Cleanup(suite.repo.cancelCloser)
+
+	suite.repo.reminders = []time.Duration{10 * time.Minute, time.Hour}
+	suite.reminders = nil
+	suite.repo.RegisterOnReminderHook(func(ctx context.Context, auctionId, highBidderId string, endTime time.Time) {
+		suite.mutex.Lock()
+		defer suite.mutex.Unlock()
+		suite.reminders = append(suite.reminders, reminder{auctionId, highBidderId, endTime.Unix()})
+	})
+}
+
+// createAuction creates an active auction ending after duration
+func (suite *RemindersSuite) createAuction(duration time.Duration) string {
+	auction := testhelpers.AnAuction().WithDuration(duration).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(suite.T(), suite.repo.CreateAuction(ctx, auction))
+
+	return auction.Id
+}
+
+func (suite *RemindersSuite) sent() []reminder {
+	suite.repo.sendDueReminders()
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	sent := suite.reminders
+	suite.reminders = nil
+	return sent
+}
+
+func (suite *RemindersSuite) TestEachReminderIsSentOnce() {
+	auctionId := suite.createAuction(30 * time.Minute)
+	suite.createAuction(3 * time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := suite.repo.currentPriceCollection.InsertOne(ctx, bson.M{"_id": auctionId, "amount": 100, "user_id": "bidder"})
+	assert.Nil(suite.T(), err)
+
+	// Only the auction closing within the hour is reminded, of its high bidder
+	sent := suite.sent()
+	assert.Len(suite.T(), sent, 1)
+	assert.Equal(suite.T(), auctionId, sent[0].auctionId)
+	assert.Equal(suite.T(), "bidder", sent[0].highBidderId)
+	assert.Empty(suite.T(), suite.sent())
+
+	// Once within ten minutes the shorter reminder goes out
+	endTime := time.Now().Add(5 * time.Minute).Unix()
+	_, err = suite.repo.closeJobCollection.UpdateOne(ctx,
+		bson.M{"_id": auctionId}, bson.M{"$set": bson.M{"end_time": endTime}})
+	assert.Nil(suite.T(), err)
+
+	sent = suite.sent()
+	assert.Len(suite.T(), sent, 1)
+	assert.Equal(suite.T(), endTime, sent[0].endTime)
+	assert.Empty(suite.T(), suite.sent())
+}
+
+func (suite *RemindersSuite) TestExtendedAuctionsAreRemindedAgain() {
+	auctionId := suite.createAuction(5 * time.Minute)
+
+	sent := suite.sent()
+	assert.Len(suite.T(), sent, 1)
+	assert.Empty(suite.T(), sent[0].highBidderId)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(suite.T(), suite.repo.extendCloseJob(ctx, auctionId, time.Now().Add(8*time.Minute)))
+
+	assert.Len(suite.T(), suite.sent(), 1)
+}
+
+func TestRemindersSuite(t *testing.T) {
+	suite.Run(t, new(RemindersSuite))
+}
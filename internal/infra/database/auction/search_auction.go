@@ -0,0 +1,165 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// productNameBoost is how much more a match in the product name counts than
+// one in the description, the text index carries the same weights
+const productNameBoost = 5
+
+// scoredAuctionMongo is an auction found by a search along with its
+// relevance
+type scoredAuctionMongo struct {
+	AuctionEntityMongo `bson:",inline"`
+	Score              float64 `bson:"score"`
+}
+
+// SearchAuctions finds the auctions whose product name or description
+// match any of the terms, best match first. It relies on the text index on
+// product_name and description, or on the Atlas Search index named by
+// SEARCH_ATLAS_INDEX when that is set
+func (ar *AuctionRepository) SearchAuctions(
+	ctx context.Context,
+	search auction_entity.AuctionSearch) ([]auction_entity.ScoredAuction, int64, *internal_error.InternalError) {
+	if len(search.Terms) == 0 {
+		return nil, 0, nil
+	}
+
+	filter := bson.M{}
+	if search.Status != nil {
+		filter["status"] = *search.Status
+	}
+	if search.Category != "" {
+		filter["category"] = search.Category
+	}
+
+	var pipeline mongo.Pipeline
+	if ar.atlasSearchIndex != "" {
+		pipeline = atlasSearchStages(ar.atlasSearchIndex, search.Terms, filter)
+	} else {
+		filter["$text"] = bson.M{"$search": strings.Join(search.Terms, " ")}
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$set", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+		}
+	}
+
+	page := bson.A{bson.M{"$skip": search.Skip}}
+	if search.Limit > 0 {
+		page = append(page, bson.M{"$limit": search.Limit})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"results": page,
+			"total":   bson.A{bson.M{"$count": "count"}},
+		}}},
+	)
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error searching auctions", err)
+		return nil, 0, internal_error.NewInternalServerError("Error searching auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Results []scoredAuctionMongo `bson:"results"`
+		Total   []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		logger.Error("Error decoding auction search results", err)
+		return nil, 0, internal_error.NewInternalServerError("Error searching auctions")
+	}
+	if len(facets) == 0 || len(facets[0].Total) == 0 {
+		return nil, 0, nil
+	}
+
+	var results []auction_entity.ScoredAuction
+	for _, scored := range facets[0].Results {
+		auction := scored.AuctionEntityMongo
+		results = append(results, auction_entity.ScoredAuction{
+			Auction: auction_entity.Auction{
+				Id:              auction.Id,
+				SellerId:        auction.SellerId,
+				ProductName:     auction.ProductName,
+				Category:        auction.Category,
+				Status:          auction.Status,
+				Description:     auction.Description,
+				DescriptionHTML: auction.DescriptionHTML,
+				Condition:       auction.Condition,
+				Timestamp:       time.Unix(auction.Timestamp, 0),
+				StartTime:       startTimeOf(auction),
+				Duration:        ar.durationOf(auction),
+				EndTime:         ar.endTimeOf(auction),
+				WinnerUserId:    auction.WinnerUserId,
+				WinningAmount:   auction.WinningAmount,
+				ReservePrice:    auction.ReservePrice,
+				ReservePublic:   auction.ReservePublic,
+				BuyNowPrice:     auction.BuyNowPrice,
+				MinIncrement:    auction.MinIncrement,
+				Type:            auction.Type,
+				DutchPricing:    dutchPricingOf(auction),
+				DutchPrice:      dutchPriceOf(auction),
+				RelistPolicy:    auction_entity.RelistPolicy{MaxRelists: auction.MaxRelists},
+				RelistCount:     auction.RelistCount,
+				RelistedFrom:    auction.RelistedFrom,
+
+				CancellationReason: auction.CancellationReason,
+				CancelledAt:        unixOrZero(auction.CancelledAt),
+				PausedAt:           unixOrZero(auction.PausedAt),
+				Location:           locationOf(auction),
+				PostalCode:         auction.PostalCode,
+			},
+			Score: scored.Score,
+		})
+	}
+
+	return results, facets[0].Total[0].Count, nil
+}
+
+// atlasSearchStages matches the terms through an Atlas Search index, product
+// names weighing more than descriptions like they do in the text index
+func atlasSearchStages(index string, terms []string, filter bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$search", Value: bson.M{
+			"index": index,
+			"compound": bson.M{
+				"should": bson.A{
+					bson.M{"text": bson.M{
+						"query": terms,
+						"path":  "product_name",
+						"score": bson.M{"boost": bson.M{"value": productNameBoost}},
+					}},
+					bson.M{"text": bson.M{"query": terms, "path": "description"}},
+				},
+				"minimumShouldMatch": 1,
+			},
+		}}},
+		{{Key: "$set", Value: bson.M{"score": bson.M{"$meta": "searchScore"}}}},
+	}
+
+	if len(filter) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	}
+
+	return pipeline
+}
+
+// getAtlasSearchIndex names the Atlas Search index to search auctions with,
+// empty searches the text index instead
+func getAtlasSearchIndex() string {
+	return os.Getenv("SEARCH_ATLAS_INDEX")
+}
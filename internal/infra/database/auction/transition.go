@@ -0,0 +1,29 @@
+package auction
+
+import (
+	"auction_go/internal/entity/auction_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// transitionFilter matches the auction only while it is in one of the from
+// statuses the state machine lets move to the target. Status updates use it
+// as a compare-and-swap, so concurrent writers can't make an illegal move
+func transitionFilter(
+	id string,
+	to auction_entity.AuctionStatus,
+	from ...auction_entity.AuctionStatus) bson.M {
+	return bson.M{"_id": id, "status": transitionStatuses(to, from...)}
+}
+
+// transitionStatuses matches the from statuses allowed to move to the target
+func transitionStatuses(to auction_entity.AuctionStatus, from ...auction_entity.AuctionStatus) bson.M {
+	allowed := []auction_entity.AuctionStatus{}
+	for _, status := range from {
+		if auction_entity.CanTransition(status, to) {
+			allowed = append(allowed, status)
+		}
+	}
+
+	return bson.M{"$in": allowed}
+}
@@ -0,0 +1,374 @@
+package auction
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// FindActiveAuctionIds lists the active auctions matching every non-empty
+// filter, a zero createdBefore disables that filter
+func (ar *AuctionRepository) FindActiveAuctionIds(
+	ctx context.Context,
+	category, sellerId string,
+	createdBefore time.Time) ([]string, *internal_error.InternalError) {
+	filter := bson.M{"status": auction_entity.Active}
+
+	if category != "" {
+		filter["category"] = category
+	}
+
+	if sellerId != "" {
+		filter["seller_id"] = sellerId
+	}
+
+	if !createdBefore.IsZero() {
+		filter["timestamp"] = bson.M{"$lt": createdBefore.Unix()}
+	}
+
+	var auctionIds []string
+	err := mongodb.Stream(ctx, ar.Collection, filter, func(auction struct {
+		Id string `bson:"_id"`
+	}) error {
+		auctionIds = append(auctionIds, auction.Id)
+		return nil
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logger.Error("Error finding active auction ids", err)
+		return nil, internal_error.NewInternalServerError("Error finding active auctions")
+	}
+
+	return auctionIds, nil
+}
+
+// UpdateAuctionStatus moves the auction only if it is still in the from
+// status, reporting whether this call made the change. Completing an auction
+// records its winner like the closer does
+func (ar *AuctionRepository) UpdateAuctionStatus(
+	ctx context.Context,
+	id string,
+	from, to auction_entity.AuctionStatus) (bool, *internal_error.InternalError) {
+	if !auction_entity.CanTransition(from, to) {
+		return false, auction_entity.NewInvalidTransitionError(from, to)
+	}
+
+	filter := transitionFilter(id, to, from)
+	fields := bson.M{"status": to}
+
+	// Stop the bidding before the auction leaves active, and before its
+	// winner is looked up
+	if from == auction_entity.Active {
+		if err := ar.closeBidding(ctx, id); err != nil {
+			logger.Error("Error closing auction to bids", err, zap.String("auctionID", id))
+			return false, internal_error.NewInternalServerError("Error updating auction status")
+		}
+	}
+
+	if to == auction_entity.Completed {
+		var auction AuctionEntityMongo
+		projection := options.FindOne().SetProjection(bson.M{"reserve_price": 1})
+		if err := ar.Collection.FindOne(ctx, bson.M{"_id": id}, projection).Decode(&auction); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return false, nil
+			}
+			logger.Error("Error finding auction to complete", err, zap.String("auctionID", id))
+			ar.restoreBidding(ctx, id)
+			return false, internal_error.NewInternalServerError("Error updating auction status")
+		}
+
+		var err *internal_error.InternalError
+		if fields, err = ar.completionFields(ctx, auction); err != nil {
+			ar.restoreBidding(ctx, id)
+			return false, err
+		}
+	}
+	update := bson.M{"$set": fields}
+
+	var result *mongo.UpdateResult
+	err := ar.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = ar.Collection.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			return nil
+		}
+		if err := ar.logChange(ctx, history_entity.StatusChanged, id); err != nil {
+			return err
+		}
+		if to == auction_entity.Completed {
+			return ar.addClosedToOutbox(ctx, id, fields)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Error updating auction status", err, zap.String("auctionID", id))
+		if from == auction_entity.Active {
+			ar.restoreBidding(ctx, id)
+		}
+		return false, internal_error.NewInternalServerError("Error updating auction status")
+	}
+
+	if result.ModifiedCount == 0 && from == auction_entity.Active {
+		ar.restoreBidding(ctx, id)
+	}
+	if result.ModifiedCount > 0 && to == auction_entity.Active {
+		ar.openBidding(ctx, id)
+	}
+
+	if result.ModifiedCount > 0 && to != auction_entity.Active {
+		if err := ar.deleteCloseJob(ctx, id); err != nil {
+			logger.Error("Error deleting auction close job", err, zap.String("auctionID", id))
+		}
+
+		ar.notifyScheduleChange(id)
+	}
+
+	if result.ModifiedCount > 0 && to == auction_entity.Completed {
+		ar.runCloseHooks(ctx, id, fields)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// UpdateAuctionEndTime stores the new end time of an active auction and
+// reschedules its close, an end time already past closes it on the next tick
+func (ar *AuctionRepository) UpdateAuctionEndTime(
+	ctx context.Context,
+	id string,
+	endTime time.Time) *internal_error.InternalError {
+	filter := bson.M{"_id": id, "status": auction_entity.Active}
+	update := bson.M{"$set": bson.M{"end_time": endTime.Unix()}}
+
+	result, err := ar.updateAndLog(ctx, history_entity.EndTimeChanged, id, filter, update)
+	if err != nil {
+		logger.Error("Error updating auction end time", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error updating auction end time")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewBadRequestError("Only active auctions can have their end time adjusted")
+	}
+
+	if err := ar.scheduleCloseJob(ctx, id, endTime); err != nil {
+		logger.Error("Error rescheduling auction close", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error updating auction end time")
+	}
+
+	ar.notifyScheduleChange(id)
+
+	return nil
+}
+
+// ExtendAuctionEndTime pushes the end of an active auction to endTime unless
+// it already ends later. Concurrent extensions keep the latest end time
+func (ar *AuctionRepository) ExtendAuctionEndTime(
+	ctx context.Context,
+	id string,
+	endTime time.Time) *internal_error.InternalError {
+	filter := bson.M{"_id": id, "status": auction_entity.Active}
+	update := bson.M{"$max": bson.M{"end_time": endTime.Unix()}}
+
+	result, err := ar.updateAndLog(ctx, history_entity.EndTimeChanged, id, filter, update)
+	if err != nil {
+		logger.Error("Error extending auction end time", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error extending auction end time")
+	}
+
+	if result.ModifiedCount == 0 {
+		return nil
+	}
+
+	if err := ar.extendCloseJob(ctx, id, endTime); err != nil {
+		logger.Error("Error extending auction close job", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error extending auction end time")
+	}
+
+	ar.notifyScheduleChange(id)
+	ar.runExtendHooks(ctx, id, endTime)
+
+	return nil
+}
+
+// CancelAuction stops an active or scheduled auction for good, keeping why and when it was
+// cancelled on the document. Its pending close is dropped with it
+func (ar *AuctionRepository) CancelAuction(
+	ctx context.Context,
+	id, reason string) *internal_error.InternalError {
+	if err := ar.closeBidding(ctx, id); err != nil {
+		logger.Error("Error closing auction to bids", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error cancelling auction")
+	}
+
+	filter := transitionFilter(id, auction_entity.Cancelled, auction_entity.Active, auction_entity.Scheduled)
+	update := bson.M{"$set": bson.M{
+		"status":              auction_entity.Cancelled,
+		"cancellation_reason": reason,
+		"cancelled_at":        time.Now().Unix(),
+	}}
+
+	result, err := ar.updateAndLog(ctx, history_entity.StatusChanged, id, filter, update)
+	if err != nil {
+		logger.Error("Error cancelling auction", err, zap.String("auctionID", id))
+		ar.restoreBidding(ctx, id)
+		return internal_error.NewInternalServerError("Error cancelling auction")
+	}
+
+	if result.MatchedCount == 0 {
+		ar.restoreBidding(ctx, id)
+		return internal_error.NewInvalidTransitionError("Only active or scheduled auctions can be cancelled")
+	}
+
+	if err := ar.deleteCloseJob(ctx, id); err != nil {
+		logger.Error("Error deleting auction close job", err, zap.String("auctionID", id))
+	}
+
+	ar.notifyScheduleChange(id)
+
+	return nil
+}
+
+// PauseAuction stops the countdown of an active auction that hasn't ended
+// yet. The close job is dropped until the auction is resumed
+func (ar *AuctionRepository) PauseAuction(
+	ctx context.Context, id string) *internal_error.InternalError {
+	if err := ar.closeBidding(ctx, id); err != nil {
+		logger.Error("Error closing auction to bids", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error pausing auction")
+	}
+
+	now := time.Now().Unix()
+
+	filter := transitionFilter(id, auction_entity.Paused, auction_entity.Active)
+	filter["end_time"] = bson.M{"$gt": now}
+	update := bson.M{"$set": bson.M{"status": auction_entity.Paused, "paused_at": now}}
+
+	result, err := ar.updateAndLog(ctx, history_entity.StatusChanged, id, filter, update)
+	if err != nil {
+		logger.Error("Error pausing auction", err, zap.String("auctionID", id))
+		ar.restoreBidding(ctx, id)
+		return internal_error.NewInternalServerError("Error pausing auction")
+	}
+
+	if result.MatchedCount == 0 {
+		ar.restoreBidding(ctx, id)
+		return internal_error.NewInvalidTransitionError("Only running active auctions can be paused")
+	}
+
+	if err := ar.deleteCloseJob(ctx, id); err != nil {
+		logger.Error("Error deleting auction close job", err, zap.String("auctionID", id))
+	}
+
+	ar.notifyScheduleChange(id)
+
+	return nil
+}
+
+// ResumeAuction reactivates a paused auction, pushing its end time by how
+// long it was paused so the remaining duration is kept. It returns the new
+// end time
+func (ar *AuctionRepository) ResumeAuction(
+	ctx context.Context, id string) (time.Time, *internal_error.InternalError) {
+	pausedFor := bson.M{"$subtract": bson.A{time.Now().Unix(), "$paused_at"}}
+
+	// Dutch prices don't drop while paused, the next drop moves like the end
+	// time does
+	dutchFilter := bson.M{
+		"_id":             id,
+		"status":          auction_entity.Paused,
+		"dutch.next_drop": bson.M{"$gt": 0},
+	}
+	dutchUpdate := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"dutch.next_drop": bson.M{"$add": bson.A{"$dutch.next_drop", pausedFor}},
+	}}}}
+
+	filter := transitionFilter(id, auction_entity.Active, auction_entity.Paused)
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status":   auction_entity.Active,
+			"end_time": bson.M{"$add": bson.A{"$end_time", pausedFor}},
+		}}},
+		{{Key: "$unset", Value: "paused_at"}},
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	err := ar.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := ar.Collection.UpdateOne(ctx, dutchFilter, dutchUpdate); err != nil {
+			return err
+		}
+
+		err := ar.Collection.FindOneAndUpdate(ctx, filter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&auctionEntityMongo)
+		if err != nil {
+			return err
+		}
+		return ar.logChange(ctx, history_entity.StatusChanged, id)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, internal_error.NewInvalidTransitionError("Only paused auctions can be resumed")
+	}
+	if err != nil {
+		logger.Error("Error resuming auction", err, zap.String("auctionID", id))
+		return time.Time{}, internal_error.NewInternalServerError("Error resuming auction")
+	}
+
+	ar.openBidding(ctx, id)
+
+	endTime := time.Unix(auctionEntityMongo.EndTime, 0)
+	if err := ar.scheduleCloseJob(ctx, id, endTime); err != nil {
+		logger.Error("Error rescheduling auction close", err, zap.String("auctionID", id))
+		return time.Time{}, internal_error.NewInternalServerError("Error resuming auction")
+	}
+	if auctionEntityMongo.Dutch != nil && auctionEntityMongo.Dutch.NextDrop != 0 {
+		ar.deadlines.push(time.Unix(auctionEntityMongo.Dutch.NextDrop, 0))
+	}
+
+	ar.notifyScheduleChange(id)
+
+	return endTime, nil
+}
+
+// ReopenAuction puts a completed auction back on until endTime, dropping the
+// winner recorded when it closed
+func (ar *AuctionRepository) ReopenAuction(
+	ctx context.Context,
+	id string,
+	endTime time.Time) *internal_error.InternalError {
+	filter := transitionFilter(id, auction_entity.Active, auction_entity.Completed)
+	update := bson.M{
+		"$set":   bson.M{"status": auction_entity.Active, "end_time": endTime.Unix()},
+		"$unset": bson.M{"winner_user_id": "", "winning_amount": ""},
+	}
+
+	result, err := ar.updateAndLog(ctx, history_entity.StatusChanged, id, filter, update)
+	if err != nil {
+		logger.Error("Error reopening auction", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error reopening auction")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewInvalidTransitionError("Only completed auctions can be reopened")
+	}
+
+	ar.openBidding(ctx, id)
+
+	// LoadActiveAuctions schedules the close on the next start if this fails
+	if err := ar.scheduleCloseJob(ctx, id, endTime); err != nil {
+		logger.Error("Error rescheduling auction close", err, zap.String("auctionID", id))
+		return internal_error.NewInternalServerError("Error reopening auction")
+	}
+
+	ar.notifyScheduleChange(id)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package auction
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WinningBidFinder returns the highest bid of an auction, or a not found
+// error when nobody bid on it
+type WinningBidFinder func(ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError)
+
+// SetWinningBidFinder wires the bid repository in, it can't be passed to the
+// constructor because the bid repository is built on top of this one
+func (ar *AuctionRepository) SetWinningBidFinder(finder WinningBidFinder) {
+	ar.auctionsMutex.Lock()
+	defer ar.auctionsMutex.Unlock()
+
+	ar.winningBidFinder = finder
+}
+
+// completionFields are the fields set on an auction as it is completed, the
+// winner is left out when the auction had no bids. An auction whose highest
+// bid doesn't reach its reserve ends not sold, without a winner
+func (ar *AuctionRepository) completionFields(
+	ctx context.Context, auction AuctionEntityMongo) (bson.M, *internal_error.InternalError) {
+	fields := bson.M{"status": auction_entity.Completed}
+	notSold := bson.M{"status": auction_entity.CompletedNotSold}
+
+	ar.auctionsMutex.RLock()
+	finder := ar.winningBidFinder
+	ar.auctionsMutex.RUnlock()
+
+	if finder == nil {
+		if auction.ReservePrice > 0 {
+			return notSold, nil
+		}
+		return fields, nil
+	}
+
+	winningBid, err := finder(ctx, auction.Id)
+	if err != nil {
+		if err.Err != "not_found" {
+			return nil, err
+		}
+		if auction.ReservePrice > 0 {
+			return notSold, nil
+		}
+		return fields, nil
+	}
+
+	if winningBid.Amount < auction.ReservePrice {
+		return notSold, nil
+	}
+
+	fields["winner_user_id"] = winningBid.UserId
+	fields["winning_amount"] = winningBid.Amount
+
+	return fields, nil
+}
+
+// FindAuctionWinner returns the winner recorded when the auction closed, a
+// not found error means it is still running or closed without bids
+func (ar *AuctionRepository) FindAuctionWinner(
+	ctx context.Context, auctionId string) (*auction_entity.AuctionWinner, *internal_error.InternalError) {
+	filter := bson.M{"_id": auctionId, "status": auction_entity.Completed, "winner_user_id": bson.M{"$exists": true}}
+	findOptions := options.FindOne().SetProjection(bson.M{"winner_user_id": 1, "winning_amount": 1})
+
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.Collection.FindOne(ctx, filter, findOptions).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No winner recorded for auction id = %s", auctionId))
+		}
+
+		logger.Error("Error trying to find auction winner", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction winner")
+	}
+
+	return &auction_entity.AuctionWinner{
+		AuctionId: auctionId,
+		UserId:    auctionEntityMongo.WinnerUserId,
+		Amount:    auctionEntityMongo.WinningAmount,
+	}, nil
+}
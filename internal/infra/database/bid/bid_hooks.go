@@ -0,0 +1,46 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// BidHook runs after a bid was stored, the auction's current price is the
+// bid's amount from then on
+type BidHook func(ctx context.Context, bid bid_entity.Bid)
+
+// RegisterOnBidHook adds a side effect to run whenever a bid is stored,
+// proxy bids and Dutch takes included. Hooks run on the bidding goroutine,
+// so slow work should be handed off
+func (bd *BidRepository) RegisterOnBidHook(hook BidHook) {
+	bd.auctionStateMutex.Lock()
+	defer bd.auctionStateMutex.Unlock()
+
+	bd.bidHooks = append(bd.bidHooks, hook)
+}
+
+func (bd *BidRepository) runBidHooks(ctx context.Context, bidEntityMongo *BidEntityMongo) {
+	bd.auctionStateMutex.Lock()
+	hooks := bd.bidHooks
+	bd.auctionStateMutex.Unlock()
+
+	bid := toBidEntity(bidEntityMongo)
+	for _, hook := range hooks {
+		runBidHook(ctx, hook, bid)
+	}
+}
+
+// A failing hook must not fail the bid that was already stored
+func runBidHook(ctx context.Context, hook BidHook, bid bid_entity.Bid) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Bid hook panicked", fmt.Errorf("%v", r), zap.String("auctionID", bid.AuctionId))
+		}
+	}()
+
+	hook(ctx, bid)
+}
@@ -0,0 +1,210 @@
+package bid
+
+import (
+	"auction_go/configuration/metrics"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	bidQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "bid_queue_depth",
+		Help: "Bids waiting in the per-auction queues, including those waiting for room.",
+	})
+	bidQueuesActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "bid_queues_active",
+		Help: "Auctions with a running bid queue.",
+	})
+	bidQueueFull = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "bid_queue_full_total",
+		Help: "Bids that had to wait for room in a full auction queue.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(bidQueueDepth, bidQueuesActive, bidQueueFull)
+}
+
+// auctionQueue holds the bids on one auction, a single worker writes them in
+// the order they came in. Pending counts the bids handed to the queue and not
+// written yet, it is guarded by the repository's ingestMutex
+type auctionQueue struct {
+	auctionId string
+	requests  chan ingestRequest
+	pending   int
+
+	// wake tells the worker a bid gave up before entering the queue, so it
+	// may retire
+	wake chan struct{}
+}
+
+// IngestBid queues the bid behind the other bids on its auction and waits for
+// its outcome. Each auction has its own queue, so auctions are written in
+// parallel while bids on the same auction are written strictly in order. A
+// full queue holds the caller back until there is room
+func (bd *BidRepository) IngestBid(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	queue := bd.reserveQueue(bid.AuctionId)
+	if queue == nil {
+		return internal_error.NewInternalServerError("Bids are no longer being taken")
+	}
+
+	request := ingestRequest{bid: bid, result: make(chan error, 1)}
+	select {
+	case queue.requests <- request:
+	default:
+		bidQueueFull.Inc()
+
+		select {
+		case queue.requests <- request:
+		case <-bd.ingestCtx.Done():
+			bd.abandonQueue(queue)
+			return internal_error.NewInternalServerError("Bids are no longer being taken")
+		case <-ctx.Done():
+			bd.abandonQueue(queue)
+			return internal_error.NewInternalServerError("Timed out queueing bid")
+		}
+	}
+
+	select {
+	case err := <-request.result:
+		return bidError(err)
+	case <-ctx.Done():
+		return internal_error.NewInternalServerError("Timed out waiting for bid")
+	}
+}
+
+// Close stops taking bids, the ones already queued are written first
+func (bd *BidRepository) Close() {
+	// Taking the lock keeps new workers from starting while waiting on them
+	bd.ingestMutex.Lock()
+	bd.cancelIngest()
+	bd.ingestMutex.Unlock()
+
+	bd.ingestWorkers.Wait()
+}
+
+// reserveQueue makes room for a bid in the auction's queue, starting its
+// worker if the auction has none. It returns nil once the repository is
+// closed
+func (bd *BidRepository) reserveQueue(auctionId string) *auctionQueue {
+	bd.ingestMutex.Lock()
+	defer bd.ingestMutex.Unlock()
+
+	if bd.ingestCtx.Err() != nil {
+		return nil
+	}
+
+	queue, ok := bd.ingestQueues[auctionId]
+	if !ok {
+		queue = &auctionQueue{
+			auctionId: auctionId,
+			requests:  make(chan ingestRequest, bd.ingestQueueDepth),
+			wake:      make(chan struct{}, 1),
+		}
+		bd.ingestQueues[auctionId] = queue
+		bidQueuesActive.Inc()
+
+		bd.ingestWorkers.Add(1)
+		go bd.runAuctionQueue(queue)
+	}
+
+	queue.pending++
+	bidQueueDepth.Inc()
+	return queue
+}
+
+// abandonQueue gives back the room of a bid that never entered the queue
+func (bd *BidRepository) abandonQueue(queue *auctionQueue) {
+	bd.ingestMutex.Lock()
+	queue.pending--
+	bd.ingestMutex.Unlock()
+	bidQueueDepth.Dec()
+
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runAuctionQueue writes the auction's bids as they come in, taking what is
+// already queued along as one batch. The worker retires once the queue sat
+// idle for BID_QUEUE_IDLE, or once the repository is closed and the queued
+// bids are written
+func (bd *BidRepository) runAuctionQueue(queue *auctionQueue) {
+	defer bd.ingestWorkers.Done()
+
+	idle := time.NewTimer(bd.ingestIdle)
+	defer idle.Stop()
+
+	shutdown := bd.ingestCtx.Done()
+	for {
+		select {
+		case request := <-queue.requests:
+			bd.writeQueued(queue, request)
+			idle.Reset(bd.ingestIdle)
+			continue
+		case <-idle.C:
+			idle.Reset(bd.ingestIdle)
+		case <-queue.wake:
+		case <-shutdown:
+			shutdown = nil
+		}
+
+		if bd.retireQueue(queue) {
+			return
+		}
+	}
+}
+
+func (bd *BidRepository) writeQueued(queue *auctionQueue, first ingestRequest) {
+	batch := []ingestRequest{first}
+	for len(batch) < bd.ingestBatchSize && len(queue.requests) > 0 {
+		batch = append(batch, <-queue.requests)
+	}
+	bidQueueDepth.Sub(float64(len(batch)))
+
+	bd.flush(batch)
+
+	bd.ingestMutex.Lock()
+	queue.pending -= len(batch)
+	bd.ingestMutex.Unlock()
+}
+
+// retireQueue removes the queue when no bid is waiting on it. A bid that
+// reserved room and has yet to enter the queue keeps it alive
+func (bd *BidRepository) retireQueue(queue *auctionQueue) bool {
+	bd.ingestMutex.Lock()
+	defer bd.ingestMutex.Unlock()
+
+	if queue.pending > 0 {
+		return false
+	}
+
+	delete(bd.ingestQueues, queue.auctionId)
+	bidQueuesActive.Dec()
+	return true
+}
+
+func getIngestQueueDepth() int {
+	depth, err := strconv.Atoi(os.Getenv("BID_QUEUE_DEPTH"))
+	if err != nil || depth <= 0 {
+		return 100
+	}
+
+	return depth
+}
+
+func getIngestIdle() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("BID_QUEUE_IDLE"))
+	if err != nil || duration <= 0 {
+		return time.Minute
+	}
+
+	return duration
+}
@@ -0,0 +1,567 @@
+package bid
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/infra/database/auction"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/testhelpers"
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type BidRepositorySuite struct {
+	suite.Suite
+	database          *mongo.Database
+	auctionRepository *auction.AuctionRepository
+	repo              *BidRepository
+}
+
+func (suite *BidRepositorySuite) SetupSuite() {
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
+	suite.auctionRepository = auction.NewAuctionRepository(suite.database)
+	suite.repo = NewBidRepository(suite.database, suite.auctionRepository)
+}
+
+func (suite *BidRepositorySuite) TearDownSuite() {
+	// The database itself is dropped by the test helper
+	suite.repo.Close()
+	suite.auctionRepository.Close()
+}
+
+func (suite *BidRepositorySuite) createAuction() string {
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.auctionRepository.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	return auction.Id
+}
+
+func (suite *BidRepositorySuite) TestConcurrentBidsOnlyRaiseThePrice() {
+	auctionId := suite.createAuction()
+
+	// The batch is inserted concurrently, in no particular order
+	bids := []bid_entity.Bid{
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build(),
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build(),
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(90).Build(),
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(150).Build(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.CreateBid(ctx, bids)
+	assert.Nil(suite.T(), err)
+
+	var currentPrice CurrentPriceMongo
+	errFind := suite.repo.currentPriceCollection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&currentPrice)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), 150.0, currentPrice.Amount)
+
+	// Only one of the equal bids gets in
+	equalBids, errCount := suite.repo.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId, "amount": 100})
+	assert.Nil(suite.T(), errCount)
+	assert.LessOrEqual(suite.T(), equalBids, int64(1))
+
+	// A later, lower bid is rejected
+	lowBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(120).Build()
+	err = suite.repo.CreateBid(ctx, []bid_entity.Bid{lowBid})
+	assert.Nil(suite.T(), err)
+
+	lowBids, errCount := suite.repo.Collection.CountDocuments(ctx, bson.M{"_id": lowBid.Id})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), lowBids)
+}
+
+func (suite *BidRepositorySuite) TestBidsRejectedOnceAuctionCloses() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	winningBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build()
+	err := suite.repo.CreateBid(ctx, []bid_entity.Bid{winningBid})
+	assert.Nil(suite.T(), err)
+
+	changed, err := suite.auctionRepository.UpdateAuctionStatus(
+		ctx, auctionId, auction_entity.Active, auction_entity.Completed)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), changed)
+
+	// A bid that already passed the status check is still turned down
+	lateBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(200).Build()
+	_, errRaise := suite.repo.raiseCurrentPrice(ctx, &BidEntityMongo{
+		Id:        lateBid.Id,
+		UserId:    lateBid.UserId,
+		AuctionId: lateBid.AuctionId,
+		Amount:    lateBid.Amount,
+		Timestamp: lateBid.Timestamp.Unix(),
+	}, suite.repo.increment)
+	assert.ErrorIs(suite.T(), errRaise, bid_entity.ErrAuctionClosed)
+
+	winner, err := suite.auctionRepository.FindAuctionWinner(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), winningBid.UserId, winner.UserId)
+	assert.Equal(suite.T(), 100.0, winner.Amount)
+}
+
+func (suite *BidRepositorySuite) TestProxyBidsOutbidUpToTheirMaximum() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, _ := bid_entity.CreateProxyBid(uuid.New().String(), auctionId, 100)
+	first.Timestamp = time.Now().Add(-time.Minute)
+	err := suite.repo.CreateProxyBid(ctx, *first)
+	assert.Nil(suite.T(), err)
+
+	// A manual bid is answered right away
+	manualBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(50).Build()
+	err = suite.repo.CreateBid(ctx, []bid_entity.Bid{manualBid})
+	assert.Nil(suite.T(), err)
+
+	winner, err := suite.repo.FindWinningBidByAuctionId(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), first.UserId, winner.UserId)
+	assert.Equal(suite.T(), 50+suite.repo.increment, winner.Amount)
+
+	// The same maximum set later loses the tie, the first proxy goes all in
+	second, _ := bid_entity.CreateProxyBid(uuid.New().String(), auctionId, 100)
+	err = suite.repo.CreateProxyBid(ctx, *second)
+	assert.Nil(suite.T(), err)
+
+	winner, err = suite.repo.FindWinningBidByAuctionId(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), first.UserId, winner.UserId)
+	assert.Equal(suite.T(), 100.0, winner.Amount)
+}
+
+func (suite *BidRepositorySuite) TestProxiesOfBiddersTurnedDownAreDropped() {
+	auctionId := suite.createAuction()
+	blockedUserId := uuid.New().String()
+
+	suite.repo.SetProxyBidCheck(func(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+		if bid.UserId == blockedUserId {
+			return internal_error.NewForbiddenError("User is blocked")
+		}
+		return nil
+	})
+	defer suite.repo.SetProxyBidCheck(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	blocked, _ := bid_entity.CreateProxyBid(blockedUserId, auctionId, 200)
+	blocked.Timestamp = time.Now().Add(-time.Minute)
+	err := suite.repo.CreateProxyBid(ctx, *blocked)
+	assert.Nil(suite.T(), err)
+
+	allowed, _ := bid_entity.CreateProxyBid(uuid.New().String(), auctionId, 100)
+	err = suite.repo.CreateProxyBid(ctx, *allowed)
+	assert.Nil(suite.T(), err)
+
+	// The stronger proxy is dropped, the other one bids instead
+	manualBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(50).Build()
+	err = suite.repo.CreateBid(ctx, []bid_entity.Bid{manualBid})
+	assert.Nil(suite.T(), err)
+
+	winner, err := suite.repo.FindWinningBidByAuctionId(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), allowed.UserId, winner.UserId)
+
+	proxies, errCount := suite.repo.proxyBidCollection.CountDocuments(ctx, bson.M{"user_id": blockedUserId})
+	assert.Nil(suite.T(), errCount)
+	assert.Equal(suite.T(), int64(0), proxies)
+}
+
+func (suite *BidRepositorySuite) TestBidsMustBeatThePriceByTheIncrement() {
+	auction := testhelpers.AnAuction().WithDuration(time.Hour).WithMinIncrement(10).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.auctionRepository.CreateAuction(ctx, auction)
+	assert.Nil(suite.T(), err)
+
+	// Any amount opens the bidding
+	minimum, err := suite.repo.FindMinimumBid(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0.0, minimum)
+
+	err = suite.repo.CreateBid(ctx, []bid_entity.Bid{
+		testhelpers.ABid().ForAuction(auction.Id).WithAmount(100).Build(),
+	})
+	assert.Nil(suite.T(), err)
+
+	minimum, err = suite.repo.FindMinimumBid(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 110.0, minimum)
+
+	// A bid that beats the price by less than the increment is dropped
+	err = suite.repo.CreateBid(ctx, []bid_entity.Bid{
+		testhelpers.ABid().ForAuction(auction.Id).WithAmount(105).Build(),
+	})
+	assert.Nil(suite.T(), err)
+
+	winner, err := suite.repo.FindWinningBidByAuctionId(ctx, auction.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 100.0, winner.Amount)
+}
+
+func (suite *BidRepositorySuite) TestFindBidsByAuctionIdPaginated() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Stored directly, equal amounts are ordered by id
+	for _, amount := range []float64{10, 20, 20, 30} {
+		bid := testhelpers.ABid().ForAuction(auctionId).WithAmount(amount).Build()
+		_, err := suite.repo.Collection.InsertOne(ctx, BidEntityMongo{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp.Unix(),
+		})
+		assert.Nil(suite.T(), err)
+	}
+
+	var amounts []float64
+	seen := map[string]bool{}
+	cursor := ""
+	for page := 0; page < 3; page++ {
+		bids, nextCursor, err := suite.repo.FindBidsByAuctionIdPaginated(
+			ctx, auctionId, cursor, 3, bid_entity.SortByAmount)
+		assert.Nil(suite.T(), err)
+
+		for _, bid := range bids {
+			assert.False(suite.T(), seen[bid.Id])
+			seen[bid.Id] = true
+			amounts = append(amounts, bid.Amount)
+		}
+
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	assert.Equal(suite.T(), []float64{30, 20, 20, 10}, amounts)
+
+	_, _, err := suite.repo.FindBidsByAuctionIdPaginated(ctx, auctionId, "not a cursor", 3, bid_entity.SortByAmount)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *BidRepositorySuite) TestIngestBidReportsEachOutcome() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := suite.repo.IngestBid(ctx, testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build())
+	assert.Nil(suite.T(), err)
+
+	err = suite.repo.IngestBid(ctx, testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build())
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "bad_request", err.Err)
+
+	// Unknown auctions fail their own bids only
+	err = suite.repo.IngestBid(ctx, testhelpers.ABid().ForAuction(uuid.New().String()).WithAmount(100).Build())
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *BidRepositorySuite) TestEndTimeMovedByAnotherReplicaIsSeen() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first bid caches the auction
+	err := suite.repo.IngestBid(ctx, testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build())
+	assert.Nil(suite.T(), err)
+
+	// Another replica moves the end time, this one isn't told
+	setEndTime := func(endTime time.Time) {
+		_, errUpdate := suite.auctionRepository.Collection.UpdateOne(ctx, bson.M{"_id": auctionId},
+			bson.M{"$set": bson.M{"end_time": endTime.Unix()}})
+		assert.Nil(suite.T(), errUpdate)
+	}
+
+	setEndTime(time.Now().Add(-time.Minute))
+	err = suite.repo.IngestBid(ctx, testhelpers.ABid().ForAuction(auctionId).WithAmount(200).Build())
+	assert.NotNil(suite.T(), err)
+
+	setEndTime(time.Now().Add(time.Hour))
+	err = suite.repo.IngestBid(ctx, testhelpers.ABid().ForAuction(auctionId).WithAmount(300).Build())
+	assert.Nil(suite.T(), err)
+}
+
+type outbidRecorder struct {
+	events chan bid_entity.OutbidEvent
+}
+
+func (r outbidRecorder) NotifyOutbid(ctx context.Context, event bid_entity.OutbidEvent) {
+	r.events <- event
+}
+
+func (suite *BidRepositorySuite) TestOutbidBidderIsNotified() {
+	recorder := outbidRecorder{events: make(chan bid_entity.OutbidEvent, 10)}
+	suite.repo.SetOutbidNotifier(recorder)
+	defer suite.repo.SetOutbidNotifier(logOutbidNotifier{})
+
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	firstBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build()
+	assert.Nil(suite.T(), suite.repo.IngestBid(ctx, firstBid))
+
+	// Raising your own bid doesn't outbid you
+	ownBid := testhelpers.ABid().ForAuction(auctionId).WithUserId(firstBid.UserId).WithAmount(110).Build()
+	assert.Nil(suite.T(), suite.repo.IngestBid(ctx, ownBid))
+
+	higherBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(150).Build()
+	assert.Nil(suite.T(), suite.repo.IngestBid(ctx, higherBid))
+
+	assert.Len(suite.T(), recorder.events, 1)
+	assert.Equal(suite.T(), bid_entity.OutbidEvent{
+		AuctionId:        auctionId,
+		PreviousBidderId: firstBid.UserId,
+		NewAmount:        150,
+	}, <-recorder.events)
+}
+
+func (suite *BidRepositorySuite) TestBidHooksRunOnAcceptedBids() {
+	auctionId := suite.createAuction()
+
+	// Hooks can't be unregistered, so this one ignores the other tests' bids
+	stored := make(chan bid_entity.Bid, 10)
+	suite.repo.RegisterOnBidHook(func(ctx context.Context, bid bid_entity.Bid) {
+		if bid.AuctionId == auctionId {
+			stored <- bid
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	acceptedBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build()
+	assert.Nil(suite.T(), suite.repo.IngestBid(ctx, acceptedBid))
+
+	// Bids not beating the current price are turned down before any hook
+	lowBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(90).Build()
+	assert.NotNil(suite.T(), suite.repo.IngestBid(ctx, lowBid))
+
+	assert.Len(suite.T(), stored, 1)
+	bid := <-stored
+	assert.Equal(suite.T(), acceptedBid.Id, bid.Id)
+	assert.Equal(suite.T(), 100.0, bid.Amount)
+}
+
+func (suite *BidRepositorySuite) TestGetCurrentPriceCountsAcceptedBids() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	currentPrice, err := suite.repo.GetCurrentPrice(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), &bid_entity.CurrentPrice{AuctionId: auctionId}, currentPrice)
+
+	leadingBid := testhelpers.ABid().ForAuction(auctionId).WithAmount(150).Build()
+	for _, bid := range []bid_entity.Bid{
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build(),
+		leadingBid,
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(120).Build(),
+	} {
+		suite.repo.IngestBid(ctx, bid)
+	}
+
+	// The rejected bid isn't counted
+	currentPrice, err = suite.repo.GetCurrentPrice(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), &bid_entity.CurrentPrice{
+		AuctionId:     auctionId,
+		Amount:        150,
+		LeadingUserId: leadingBid.UserId,
+		BidCount:      2,
+	}, currentPrice)
+}
+
+func (suite *BidRepositorySuite) TestAcceptedBidsAreSequenced() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bids := []bid_entity.Bid{
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build(),
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build(),
+		testhelpers.ABid().ForAuction(auctionId).WithAmount(150).Build(),
+	}
+	results := suite.repo.insertBatch(ctx, bids)
+	assert.Nil(suite.T(), results[0])
+	assert.NotNil(suite.T(), results[1])
+	assert.Nil(suite.T(), results[2])
+
+	page, _, err := suite.repo.FindBidsByAuctionIdPaginated(ctx, auctionId, "", 10, bid_entity.SortByTime)
+	assert.Nil(suite.T(), err)
+
+	sequences := make(map[string]int64)
+	for _, bid := range page {
+		sequences[bid.Id] = bid.Sequence
+	}
+	assert.Equal(suite.T(), map[string]int64{bids[0].Id: 1, bids[2].Id: 2}, sequences)
+}
+
+func (suite *BidRepositorySuite) TestRetriedBidsAreStoredOnce() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	original := testhelpers.ABid().ForAuction(auctionId).WithAmount(100).WithIdempotencyKey("retry-me").Build()
+	retry := testhelpers.ABid().ForAuction(auctionId).WithUserId(original.UserId).
+		WithAmount(150).WithIdempotencyKey("retry-me").Build()
+
+	// A retry in the same batch and one in a later batch
+	results := suite.repo.insertBatch(ctx, []bid_entity.Bid{original, retry})
+	assert.Nil(suite.T(), results[0])
+	assert.ErrorIs(suite.T(), results[1], bid_entity.ErrDuplicateBid)
+
+	results = suite.repo.insertBatch(ctx, []bid_entity.Bid{retry})
+	assert.ErrorIs(suite.T(), results[0], bid_entity.ErrDuplicateBid)
+
+	// The retries never touched the price
+	currentPrice, err := suite.repo.GetCurrentPrice(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 100.0, currentPrice.Amount)
+
+	found, err := suite.repo.FindBidByIdempotencyKey(ctx, original.UserId, "retry-me")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), original.Id, found.Id)
+}
+
+func (suite *BidRepositorySuite) TestFindActiveBidsByUser() {
+	leadingAuctionId := suite.createAuction()
+	outbidAuctionId := suite.createAuction()
+	closedAuctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userId := uuid.New().String()
+	rival := testhelpers.ABid().ForAuction(outbidAuctionId).WithAmount(200).Build()
+	results := suite.repo.insertBatch(ctx, []bid_entity.Bid{
+		testhelpers.ABid().ForAuction(leadingAuctionId).WithUserId(userId).WithAmount(100).Build(),
+		testhelpers.ABid().ForAuction(leadingAuctionId).WithUserId(userId).WithAmount(120).Build(),
+		testhelpers.ABid().ForAuction(outbidAuctionId).WithUserId(userId).WithAmount(150).Build(),
+		rival,
+		testhelpers.ABid().ForAuction(closedAuctionId).WithUserId(userId).WithAmount(100).Build(),
+	})
+	for _, result := range results {
+		assert.Nil(suite.T(), result)
+	}
+
+	_, errUpdate := suite.repo.AuctionRepository.UpdateAuctionStatus(
+		ctx, closedAuctionId, auction_entity.Active, auction_entity.Completed)
+	assert.Nil(suite.T(), errUpdate)
+
+	activeBids, err := suite.repo.FindActiveBidsByUser(ctx, userId)
+	assert.Nil(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []bid_entity.ActiveBid{
+		{AuctionId: leadingAuctionId, HighestAmount: 120, LeadingAmount: 120, LeadingUserId: userId, Winning: true},
+		{AuctionId: outbidAuctionId, HighestAmount: 150, LeadingAmount: 200, LeadingUserId: rival.UserId},
+	}, activeBids)
+}
+
+func TestBidRepositorySuite(t *testing.T) {
+	suite.Run(t, new(BidRepositorySuite))
+}
+
+func (suite *BidRepositorySuite) TestQueuedBidsAreWrittenInOrderPerAuction() {
+	auctionIds := []string{suite.createAuction(), suite.createAuction()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, auctionId := range auctionIds {
+		for amount := 100; amount < 120; amount++ {
+			wg.Add(1)
+			go func(bid bid_entity.Bid) {
+				defer wg.Done()
+				suite.repo.IngestBid(ctx, bid)
+			}(testhelpers.ABid().ForAuction(auctionId).WithAmount(float64(amount)).Build())
+		}
+	}
+	wg.Wait()
+
+	// Every accepted bid beat the one written before it
+	for _, auctionId := range auctionIds {
+		page, _, err := suite.repo.FindBidsByAuctionIdPaginated(ctx, auctionId, "", 50, bid_entity.SortByTime)
+		assert.Nil(suite.T(), err)
+		assert.NotEmpty(suite.T(), page)
+
+		sort.Slice(page, func(i, j int) bool { return page[i].Sequence < page[j].Sequence })
+		for i, bid := range page {
+			assert.Equal(suite.T(), int64(i+1), bid.Sequence)
+			if i > 0 {
+				assert.Greater(suite.T(), bid.Amount, page[i-1].Amount)
+			}
+		}
+	}
+}
+
+func (suite *BidRepositorySuite) TestFailedInsertsRestoreThePrice() {
+	auctionId := suite.createAuction()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stored := testhelpers.ABid().ForAuction(auctionId).WithAmount(100).Build()
+	results := suite.repo.insertBatch(ctx, []bid_entity.Bid{stored})
+	assert.Nil(suite.T(), results[0])
+
+	// Bids reusing the stored bid's id raise the price, then fail to insert
+	batched := testhelpers.ABid().ForAuction(auctionId).WithAmount(150).Build()
+	batched.Id = stored.Id
+	results = suite.repo.insertBatch(ctx, []bid_entity.Bid{batched})
+	assert.NotNil(suite.T(), results[0])
+
+	single := testhelpers.ABid().ForAuction(auctionId).WithAmount(200).Build()
+	inserted := suite.repo.insertBid(ctx, &BidEntityMongo{
+		Id:        stored.Id,
+		UserId:    single.UserId,
+		AuctionId: single.AuctionId,
+		Amount:    single.Amount,
+		Timestamp: single.Timestamp.Unix(),
+	}, suite.repo.increment)
+	assert.False(suite.T(), inserted)
+
+	winner, err := suite.repo.FindWinningBidByAuctionId(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), stored.UserId, winner.UserId)
+	assert.Equal(suite.T(), 100.0, winner.Amount)
+
+	currentPrice, err := suite.repo.GetCurrentPrice(ctx, auctionId)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), currentPrice.BidCount)
+}
@@ -1,17 +1,21 @@
 package bid
 
 import (
+	"auction_go/configuration/database/mongodb"
 	"auction_go/configuration/logger"
 	"auction_go/internal/entity/auction_entity"
 	"auction_go/internal/entity/bid_entity"
 	"auction_go/internal/infra/database/auction"
 	"auction_go/internal/internal_error"
 	"context"
+	"errors"
 	"os"
 	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
 )
 
 type BidEntityMongo struct {
@@ -20,101 +24,252 @@ type BidEntityMongo struct {
 	AuctionId string  `bson:"auction_id"`
 	Amount    float64 `bson:"amount"`
 	Timestamp int64   `bson:"timestamp"`
+	Sequence  int64   `bson:"sequence,omitempty"`
+
+	IdempotencyKey string `bson:"idempotency_key,omitempty"`
 }
 
 type BidRepository struct {
-	Collection            *mongo.Collection
-	AuctionRepository     *auction.AuctionRepository
-	auctionInterval       time.Duration
-	auctionStatusMap      map[string]auction_entity.AuctionStatus
-	auctionEndTimeMap     map[string]time.Time
-	auctionStatusMapMutex *sync.Mutex
-	auctionEndTimeMutex   *sync.Mutex
+	Collection             *mongo.Collection
+	currentPriceCollection *mongo.Collection
+	proxyBidCollection     *mongo.Collection
+	increment              float64
+	extensionWindow        time.Duration
+	extension              time.Duration
+	AuctionRepository      *auction.AuctionRepository
+	auctionSettingsMap     map[string]auctionSettings
+	auctionStateMutex      *sync.Mutex
+	outbidNotifier         bid_entity.OutbidNotifier
+	bidHooks               []BidHook
+	proxyBidCheck          ProxyBidCheck
+	outbox                 Outbox
+	eventLog               EventLog
+
+	// Bids are queued per auction, see IngestBid
+	ingestQueues     map[string]*auctionQueue
+	ingestMutex      *sync.Mutex
+	ingestWorkers    *sync.WaitGroup
+	ingestQueueDepth int
+	ingestIdle       time.Duration
+	ingestBatchSize  int
+	ingestCtx        context.Context
+	cancelIngest     context.CancelFunc
+}
+
+// auctionSettings is what never changes about an auction once bidding
+// started, cached until the auction repository reports a change to it
+type auctionSettings struct {
+	auctionType  auction_entity.AuctionType
+	minIncrement float64
+}
+
+// auctionState is what bidding needs to know about an auction. The status
+// and end time are read fresh, anti-sniping and admins move the end time and
+// any replica may do it
+type auctionState struct {
+	auctionSettings
+	status  auction_entity.AuctionStatus
+	endTime time.Time
+}
+
+func (s auctionState) open(now time.Time) bool {
+	return s.status == auction_entity.Active && !now.After(s.endTime)
 }
 
 func NewBidRepository(database *mongo.Database, auctionRepository *auction.AuctionRepository) *BidRepository {
-	return &BidRepository{
-		auctionInterval:       getAuctionInterval(),
-		auctionStatusMap:      make(map[string]auction_entity.AuctionStatus),
-		auctionEndTimeMap:     make(map[string]time.Time),
-		auctionStatusMapMutex: &sync.Mutex{},
-		auctionEndTimeMutex:   &sync.Mutex{},
-		Collection:            database.Collection("bids"),
-		AuctionRepository:     auctionRepository,
+	ingestCtx, cancelIngest := context.WithCancel(context.Background())
+
+	bidRepository := &BidRepository{
+		auctionSettingsMap:     make(map[string]auctionSettings),
+		auctionStateMutex:      &sync.Mutex{},
+		Collection:             database.Collection("bids"),
+		currentPriceCollection: database.Collection(auction.CurrentPriceCollection),
+		proxyBidCollection:     database.Collection("proxy_bids"),
+		increment:              getBidIncrement(),
+		extensionWindow:        getDurationEnv("AUCTION_EXTENSION_WINDOW", 30*time.Second),
+		extension:              getDurationEnv("AUCTION_EXTENSION", 30*time.Second),
+		AuctionRepository:      auctionRepository,
+		outbidNotifier:         logOutbidNotifier{},
+		ingestQueues:           make(map[string]*auctionQueue),
+		ingestMutex:            &sync.Mutex{},
+		ingestWorkers:          &sync.WaitGroup{},
+		ingestQueueDepth:       getIngestQueueDepth(),
+		ingestIdle:             getIngestIdle(),
+		ingestBatchSize:        getIngestBatchSize(),
+		ingestCtx:              ingestCtx,
+		cancelIngest:           cancelIngest,
 	}
+
+	auctionRepository.OnScheduleChange(bidRepository.forgetAuction)
+	auctionRepository.SetWinningBidFinder(bidRepository.FindWinningBidByAuctionId)
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	bidRepository.EnsureIndexes(indexCtx)
+
+	return bidRepository
+}
+
+// EnsureIndexes creates the indexes of the bids and proxy_bids collections
+func (bd *BidRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, bd.Collection.Database(), "bids", "proxy_bids"); err != nil {
+		logger.Error("Error trying to create bid indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create bid indexes")
+	}
+
+	return nil
 }
 
+// forgetAuction drops the cached settings of an auction, the next bid
+// reloads them from the database
+func (bd *BidRepository) forgetAuction(auctionId string) {
+	bd.auctionStateMutex.Lock()
+	delete(bd.auctionSettingsMap, auctionId)
+	bd.auctionStateMutex.Unlock()
+}
+
+// findAuctionSettings returns the cached settings of the auction, loading
+// them on the first bid
+func (bd *BidRepository) findAuctionSettings(
+	ctx context.Context, auctionId string) (auctionSettings, *internal_error.InternalError) {
+	bd.auctionStateMutex.Lock()
+	settings, ok := bd.auctionSettingsMap[auctionId]
+	bd.auctionStateMutex.Unlock()
+	if ok {
+		return settings, nil
+	}
+
+	auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return auctionSettings{}, err
+	}
+
+	settings = auctionSettings{
+		auctionType:  auctionEntity.Type,
+		minIncrement: auctionEntity.MinIncrement,
+	}
+	if settings.minIncrement <= 0 {
+		settings.minIncrement = bd.increment
+	}
+
+	bd.auctionStateMutex.Lock()
+	bd.auctionSettingsMap[auctionId] = settings
+	bd.auctionStateMutex.Unlock()
+
+	return settings, nil
+}
+
+// findAuctionState returns the cached settings of the auction along with
+// its status and end time as stored right now
+func (bd *BidRepository) findAuctionState(
+	ctx context.Context, auctionId string) (auctionState, *internal_error.InternalError) {
+	settings, err := bd.findAuctionSettings(ctx, auctionId)
+	if err != nil {
+		return auctionState{}, err
+	}
+
+	status, endTime, err := bd.AuctionRepository.FindBiddingWindow(ctx, auctionId)
+	if err != nil {
+		return auctionState{}, err
+	}
+
+	return auctionState{auctionSettings: settings, status: status, endTime: endTime}, nil
+}
+
+// CreateBid stores a batch of bids right away, bids that are turned down
+// are only logged. IngestBid reports the outcome of each bid instead
 func (bd *BidRepository) CreateBid(
 	ctx context.Context,
 	bidEntities []bid_entity.Bid) *internal_error.InternalError {
-	var wg sync.WaitGroup
-	for _, bid := range bidEntities {
-		wg.Add(1)
-		go func(bidValue bid_entity.Bid) {
-			defer wg.Done()
-
-			bd.auctionStatusMapMutex.Lock()
-			auctionStatus, okStatus := bd.auctionStatusMap[bidValue.AuctionId]
-			bd.auctionStatusMapMutex.Unlock()
-
-			bd.auctionEndTimeMutex.Lock()
-			auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
-			bd.auctionEndTimeMutex.Unlock()
-
-			bidEntityMongo := &BidEntityMongo{
-				Id:        bidValue.Id,
-				UserId:    bidValue.UserId,
-				AuctionId: bidValue.AuctionId,
-				Amount:    bidValue.Amount,
-				Timestamp: bidValue.Timestamp.Unix(),
-			}
-
-			if okEndTime && okStatus {
-				now := time.Now()
-				if auctionStatus == auction_entity.Completed || now.After(auctionEndTime) {
-					return
-				}
-
-				if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-					logger.Error("Error trying to insert bid", err)
-					return
-				}
-
-				return
-			}
-
-			auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
-			if err != nil {
-				logger.Error("Error trying to find auction by id", err)
-				return
-			}
-			if auctionEntity.Status == auction_entity.Completed {
-				return
-			}
-
-			bd.auctionStatusMapMutex.Lock()
-			bd.auctionStatusMap[bidValue.AuctionId] = auctionEntity.Status
-			bd.auctionStatusMapMutex.Unlock()
-
-			bd.auctionEndTimeMutex.Lock()
-			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.Timestamp.Add(bd.auctionInterval)
-			bd.auctionEndTimeMutex.Unlock()
-
-			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-				logger.Error("Error trying to insert bid", err)
-				return
-			}
-		}(bid)
-	}
-	wg.Wait()
+	for i, err := range bd.insertBatch(ctx, bidEntities) {
+		if err != nil {
+			logger.Info("Bid not stored", zap.String("reason", err.Error()),
+				zap.String("auctionID", bidEntities[i].AuctionId), zap.Float64("amount", bidEntities[i].Amount))
+		}
+	}
+
 	return nil
 }
 
-func getAuctionInterval() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
+// FindMinimumBid is the least the next bid on the auction has to be, zero
+// when nobody bid yet. Dutch auctions take bids at their current price
+func (bd *BidRepository) FindMinimumBid(
+	ctx context.Context, auctionId string) (float64, *internal_error.InternalError) {
+	settings, err := bd.findAuctionSettings(ctx, auctionId)
 	if err != nil {
-		return time.Minute * 5
+		return 0, err
+	}
+
+	// The price drops on its own, it isn't cached
+	if settings.auctionType == auction_entity.Dutch {
+		auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return 0, err
+		}
+		return auctionEntity.DutchPrice, nil
+	}
+
+	var currentPrice CurrentPriceMongo
+	if err := bd.currentPriceCollection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&currentPrice); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+
+		logger.Error("Error trying to find the auction current price", err, zap.String("auctionID", auctionId))
+		return 0, internal_error.NewInternalServerError("Error trying to find the auction current price")
+	}
+
+	return minimumBid(currentPrice.Amount, settings.minIncrement), nil
+}
+
+// minimumBid is the current price plus the increment, any amount opens the
+// bidding
+func minimumBid(currentAmount, increment float64) float64 {
+	if currentAmount <= 0 {
+		return 0
+	}
+
+	return currentAmount + increment
+}
+
+// placeBid stores the bid when it beats the current price by the increment,
+// extending the auction when it came in late. It reports whether the bid
+// was accepted
+func (bd *BidRepository) placeBid(ctx context.Context, bid bid_entity.Bid, state auctionState) bool {
+	if !bd.insertBid(ctx, toBidEntityMongo(bid), state.minIncrement) {
+		return false
+	}
+
+	bd.extendIfSniped(ctx, bid, state.endTime)
+	return true
+}
+
+// extendIfSniped keeps the auction open for another extension after a bid
+// that landed within the extension window of its end, so other bidders get
+// a fair chance to answer it. A zero window turns extensions off
+func (bd *BidRepository) extendIfSniped(ctx context.Context, bid bid_entity.Bid, auctionEndTime time.Time) {
+	if bd.extensionWindow <= 0 || auctionEndTime.Sub(bid.Timestamp) > bd.extensionWindow {
+		return
+	}
+
+	newEndTime := bid.Timestamp.Add(bd.extension)
+	if !newEndTime.After(auctionEndTime) {
+		return
+	}
+
+	if err := bd.AuctionRepository.ExtendAuctionEndTime(ctx, bid.AuctionId, newEndTime); err != nil {
+		logger.Error("Error trying to extend sniped auction", err, zap.String("auctionID", bid.AuctionId))
+		return
+	}
+
+	logger.Info("Auction extended after a late bid",
+		zap.String("auctionID", bid.AuctionId), zap.Time("endTime", newEndTime))
+}
+
+func getDurationEnv(name string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || duration < 0 {
+		return fallback
 	}
 
 	return duration
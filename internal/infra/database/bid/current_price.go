@@ -0,0 +1,188 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// CurrentPriceMongo holds the highest bid accepted on an auction and how many
+// bids were accepted. Bids only go in after raising it, so two concurrent
+// bids can't both beat the same price. The auction repository sets Closed
+// once the auction stops taking bids
+type CurrentPriceMongo struct {
+	AuctionId string  `bson:"_id"`
+	Amount    float64 `bson:"amount"`
+	BidId     string  `bson:"bid_id,omitempty"`
+	UserId    string  `bson:"user_id,omitempty"`
+	Timestamp int64   `bson:"timestamp,omitempty"`
+	BidCount  int64   `bson:"bid_count,omitempty"`
+	Closed    bool    `bson:"closed,omitempty"`
+}
+
+// raiseCurrentPrice sets the bid as the auction's current price when it
+// beats the price so far by the increment and the auction still takes bids,
+// creating the document on the first bid. The bid count doubles as the
+// auction's bid sequence, the bid gets the next number. It returns the price
+// it replaced, with the bidder who was leading if any, or
+// bid_entity.ErrBidTooLow or bid_entity.ErrAuctionClosed when the bid is
+// turned down
+func (bd *BidRepository) raiseCurrentPrice(
+	ctx context.Context, bid *BidEntityMongo, increment float64) (CurrentPriceMongo, error) {
+	filter := bson.M{
+		"_id": bid.AuctionId,
+		"$or": bson.A{
+			bson.M{"amount": bson.M{"$lte": 0}},
+			bson.M{"amount": bson.M{"$lte": bid.Amount - increment}},
+		},
+		"closed": bson.M{"$ne": true},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"amount":    bid.Amount,
+			"bid_id":    bid.Id,
+			"user_id":   bid.UserId,
+			"timestamp": bid.Timestamp,
+		},
+		"$inc": bson.M{"bid_count": 1},
+	}
+
+	// A price the bid doesn't beat, or closed bidding, doesn't match the
+	// filter, so the upsert collides with the document instead
+	var previous CurrentPriceMongo
+	findOptions := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+	err := bd.currentPriceCollection.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&previous)
+	if err == nil || errors.Is(err, mongo.ErrNoDocuments) {
+		bid.Sequence = previous.BidCount + 1
+		return previous, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return CurrentPriceMongo{}, err
+	}
+
+	var currentPrice CurrentPriceMongo
+	if err := bd.currentPriceCollection.FindOne(ctx, bson.M{"_id": bid.AuctionId}).Decode(&currentPrice); err != nil {
+		return CurrentPriceMongo{}, err
+	}
+	if currentPrice.Closed {
+		return CurrentPriceMongo{}, bid_entity.ErrAuctionClosed
+	}
+
+	return CurrentPriceMongo{}, bid_entity.ErrBidTooLow
+}
+
+// restoreCurrentPrice puts back the price a bid replaced when the bid
+// couldn't be stored, so the auction isn't won by a bid that doesn't exist.
+// A price a later bid raised meanwhile is left alone, that bid was stored
+// on top of it
+func (bd *BidRepository) restoreCurrentPrice(
+	ctx context.Context, bid *BidEntityMongo, previous CurrentPriceMongo) {
+	filter := bson.M{"_id": bid.AuctionId, "bid_id": bid.Id}
+	update := bson.M{
+		"$set": bson.M{
+			"amount":    previous.Amount,
+			"bid_id":    previous.BidId,
+			"user_id":   previous.UserId,
+			"timestamp": previous.Timestamp,
+			"bid_count": previous.BidCount,
+		},
+	}
+
+	if _, err := bd.currentPriceCollection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error("Error trying to restore the auction current price", err,
+			zap.String("auctionID", bid.AuctionId), zap.String("bidID", bid.Id))
+	}
+}
+
+// BackfillCurrentPrices stores the current price of auctions bid on before
+// it was tracked, from their highest bid, and counts the bids of prices
+// stored before bids were counted. Existing prices are left alone otherwise,
+// so it is safe to run on every start
+func (bd *BidRepository) BackfillCurrentPrices(ctx context.Context) *internal_error.InternalError {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{
+			{Key: "auction_id", Value: 1},
+			{Key: "amount", Value: -1},
+			{Key: "sequence", Value: 1},
+			{Key: "timestamp", Value: 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$auction_id",
+			"amount":    bson.M{"$first": "$amount"},
+			"bid_id":    bson.M{"$first": "$_id"},
+			"user_id":   bson.M{"$first": "$user_id"},
+			"timestamp": bson.M{"$first": "$timestamp"},
+			"bid_count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into": bd.currentPriceCollection.Name(),
+			"whenMatched": bson.A{
+				bson.M{"$set": bson.M{"bid_count": bson.M{"$ifNull": bson.A{"$bid_count", "$$new.bid_count"}}}},
+			},
+			"whenNotMatched": "insert",
+		}}},
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error backfilling auction current prices", err)
+		return internal_error.NewInternalServerError("Error backfilling auction current prices")
+	}
+	cursor.Close(ctx)
+
+	return nil
+}
+
+// GetCurrentPrice reads the auction's current price without going through
+// its bids, auctions nobody bid on have a zero price
+func (bd *BidRepository) GetCurrentPrice(
+	ctx context.Context, auctionId string) (*bid_entity.CurrentPrice, *internal_error.InternalError) {
+	var currentPrice CurrentPriceMongo
+	if err := bd.currentPriceCollection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&currentPrice); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &bid_entity.CurrentPrice{AuctionId: auctionId}, nil
+		}
+
+		logger.Error("Error trying to find the auction current price", err, zap.String("auctionID", auctionId))
+		return nil, internal_error.NewInternalServerError("Error trying to find the auction current price")
+	}
+
+	return &bid_entity.CurrentPrice{
+		AuctionId:     currentPrice.AuctionId,
+		Amount:        currentPrice.Amount,
+		LeadingUserId: currentPrice.UserId,
+		BidCount:      currentPrice.BidCount,
+	}, nil
+}
+
+// insertBid stores the bid once it raised the current price, bids that
+// don't are dropped
+func (bd *BidRepository) insertBid(ctx context.Context, bid *BidEntityMongo, increment float64) bool {
+	previous, err := bd.raiseCurrentPrice(ctx, bid, increment)
+	if errors.Is(err, bid_entity.ErrBidTooLow) || errors.Is(err, bid_entity.ErrAuctionClosed) {
+		logger.Info("Bid rejected", zap.String("reason", err.Error()),
+			zap.String("auctionID", bid.AuctionId), zap.Float64("amount", bid.Amount))
+		return false
+	}
+	if err != nil {
+		logger.Error("Error trying to raise the auction current price", err, zap.String("auctionID", bid.AuctionId))
+		return false
+	}
+
+	if err := bd.storeBid(ctx, bid); err != nil {
+		logger.Error("Error trying to insert bid", err)
+		bd.restoreCurrentPrice(ctx, bid, previous)
+		return false
+	}
+
+	bd.notifyOutbid(ctx, previous.UserId, bid)
+	bd.runBidHooks(ctx, bid)
+	return true
+}
@@ -0,0 +1,48 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// takeDutchAuction wins the Dutch auction for the bidder when the bid covers
+// its current price, and stores the bid at the price paid. The auction closed
+// bidding, so the current price is set without racing other bids
+func (bd *BidRepository) takeDutchAuction(ctx context.Context, bid bid_entity.Bid) error {
+	price, err := bd.AuctionRepository.TakeDutchAuction(ctx, bid.AuctionId, bid.UserId, bid.Amount)
+	if err != nil {
+		return err
+	}
+
+	bid.Amount = price
+	bidEntityMongo := toBidEntityMongo(bid)
+
+	update := bson.M{
+		"$set": bson.M{
+			"amount":    bidEntityMongo.Amount,
+			"bid_id":    bidEntityMongo.Id,
+			"user_id":   bidEntityMongo.UserId,
+			"timestamp": bidEntityMongo.Timestamp,
+		},
+		"$inc": bson.M{"bid_count": 1},
+	}
+	var previous CurrentPriceMongo
+	if err := bd.currentPriceCollection.FindOneAndUpdate(ctx, bson.M{"_id": bid.AuctionId}, update).
+		Decode(&previous); err != nil {
+		logger.Error("Error trying to set the Dutch auction price", err, zap.String("auctionID", bid.AuctionId))
+		return err
+	}
+	bidEntityMongo.Sequence = previous.BidCount + 1
+
+	if err := bd.storeBid(ctx, bidEntityMongo); err != nil {
+		logger.Error("Error trying to insert bid", err, zap.String("auctionID", bid.AuctionId))
+		return err
+	}
+	bd.runBidHooks(ctx, bidEntityMongo)
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// activeBidMongo is one row of the active bids pipeline
+type activeBidMongo struct {
+	AuctionId     string  `bson:"_id"`
+	HighestAmount float64 `bson:"highest_amount"`
+	LeadingAmount float64 `bson:"leading_amount"`
+	LeadingUserId string  `bson:"leading_user_id"`
+}
+
+// FindActiveBidsByUser returns, for every running auction the user bid on,
+// their highest bid and the leading one, in a single aggregation
+func (bd *BidRepository) FindActiveBidsByUser(
+	ctx context.Context, userId string) ([]bid_entity.ActiveBid, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userId}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$auction_id",
+			"highest_amount": bson.M{"$max": "$amount"},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": bd.AuctionRepository.Collection.Name(),
+			"let":  bson.M{"auctionId": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$_id", "$$auctionId"}},
+					bson.M{"$eq": bson.A{"$status", auction_entity.Active}},
+				}}}},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "auction",
+		}}},
+		{{Key: "$match", Value: bson.M{"auction": bson.M{"$ne": bson.A{}}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         bd.currentPriceCollection.Name(),
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "current_price",
+		}}},
+		{{Key: "$unwind", Value: "$current_price"}},
+		{{Key: "$project", Value: bson.M{
+			"highest_amount":  1,
+			"leading_amount":  "$current_price.amount",
+			"leading_user_id": "$current_price.user_id",
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find active bids", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find active bids")
+	}
+
+	var activeBidsMongo []activeBidMongo
+	if err := cursor.All(ctx, &activeBidsMongo); err != nil {
+		logger.Error("Error trying to find active bids", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find active bids")
+	}
+
+	activeBids := make([]bid_entity.ActiveBid, 0, len(activeBidsMongo))
+	for _, activeBid := range activeBidsMongo {
+		activeBids = append(activeBids, bid_entity.ActiveBid{
+			AuctionId:     activeBid.AuctionId,
+			HighestAmount: activeBid.HighestAmount,
+			LeadingAmount: activeBid.LeadingAmount,
+			LeadingUserId: activeBid.LeadingUserId,
+			Winning:       activeBid.LeadingUserId == userId,
+		})
+	}
+
+	return activeBids, nil
+}
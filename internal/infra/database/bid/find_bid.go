@@ -5,11 +5,12 @@ import (
 	"auction_go/internal/entity/bid_entity"
 	"auction_go/internal/internal_error"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func (bd *BidRepository) FindBidByAuctionId(
@@ -40,28 +41,35 @@ func (bd *BidRepository) FindBidByAuctionId(
 			AuctionId: bidEntityMongo.AuctionId,
 			Amount:    bidEntityMongo.Amount,
 			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+			Sequence:  bidEntityMongo.Sequence,
 		})
 	}
 
 	return bidEntities, nil
 }
 
+// FindWinningBidByAuctionId reads the auction's current price, the bid that
+// set it is the highest one, even if it isn't stored in the bids yet
 func (bd *BidRepository) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auction_id": auctionId}
+	filter := bson.M{"_id": auctionId, "amount": bson.M{"$gt": 0}}
+
+	var currentPrice CurrentPriceMongo
+	if err := bd.currentPriceCollection.FindOne(ctx, filter).Decode(&currentPrice); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No bids found for auction id = %s", auctionId))
+		}
 
-	var bidEntityMongo BidEntityMongo
-	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}})
-	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
 		logger.Error("Error trying to find the auction winner", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
 	}
 
 	return &bid_entity.Bid{
-		Id:        bidEntityMongo.Id,
-		UserId:    bidEntityMongo.UserId,
-		AuctionId: bidEntityMongo.AuctionId,
-		Amount:    bidEntityMongo.Amount,
-		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+		Id:        currentPrice.BidId,
+		UserId:    currentPrice.UserId,
+		AuctionId: currentPrice.AuctionId,
+		Amount:    currentPrice.Amount,
+		Timestamp: time.Unix(currentPrice.Timestamp, 0),
 	}, nil
 }
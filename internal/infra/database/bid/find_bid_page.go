@@ -0,0 +1,127 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// bidCursor is where a page of bids ended: the sort value, sequence and id
+// of its last bid. Pages go on from it rather than skipping, so bids placed
+// while paging don't shift the next page
+type bidCursor struct {
+	Value    float64 `json:"v"`
+	Sequence int64   `json:"s,omitempty"`
+	Id       string  `json:"id"`
+}
+
+// FindBidsByAuctionIdPaginated returns up to limit bids of the auction,
+// highest or latest first, after the bid the cursor points to. Bids of the
+// same amount come in the order they were placed, as they would win. The
+// cursor returned for the next page is empty once there are no more bids
+func (bd *BidRepository) FindBidsByAuctionIdPaginated(
+	ctx context.Context,
+	auctionId, cursor string,
+	limit int64,
+	sort bid_entity.BidSort) ([]bid_entity.Bid, string, *internal_error.InternalError) {
+	field := "timestamp"
+	order := bson.D{{Key: field, Value: -1}, {Key: "_id", Value: 1}}
+	if sort == bid_entity.SortByAmount {
+		field = "amount"
+		order = bson.D{{Key: field, Value: -1}, {Key: "sequence", Value: 1}, {Key: "_id", Value: 1}}
+	}
+
+	filter := bson.M{"auction_id": auctionId}
+	if cursor != "" {
+		after, err := decodeBidCursor(cursor)
+		if err != nil {
+			return nil, "", internal_error.NewBadRequestError("Invalid cursor")
+		}
+
+		filter["$or"] = bson.A{
+			bson.M{field: bson.M{"$lt": after.Value}},
+			bson.M{field: after.Value, "_id": bson.M{"$gt": after.Id}},
+		}
+		if sort == bid_entity.SortByAmount {
+			// Bids stored before sequences existed have none, null matches them
+			var sequence any
+			if after.Sequence != 0 {
+				sequence = after.Sequence
+			}
+
+			filter["$or"] = bson.A{
+				bson.M{field: bson.M{"$lt": after.Value}},
+				bson.M{field: after.Value, "sequence": bson.M{"$gt": after.Sequence}},
+				bson.M{field: after.Value, "sequence": sequence, "_id": bson.M{"$gt": after.Id}},
+			}
+		}
+	}
+
+	// One more than asked tells whether there is a next page
+	findOptions := options.Find().
+		SetSort(order).
+		SetLimit(limit + 1)
+
+	cursorMongo, err := bd.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find bids page", err, zap.String("auctionID", auctionId))
+		return nil, "", internal_error.NewInternalServerError("Error trying to find bids")
+	}
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursorMongo.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.Error("Error trying to find bids page", err, zap.String("auctionID", auctionId))
+		return nil, "", internal_error.NewInternalServerError("Error trying to find bids")
+	}
+
+	nextCursor := ""
+	if int64(len(bidEntitiesMongo)) > limit {
+		bidEntitiesMongo = bidEntitiesMongo[:limit]
+
+		last := bidEntitiesMongo[limit-1]
+		value := float64(last.Timestamp)
+		if sort == bid_entity.SortByAmount {
+			value = last.Amount
+		}
+		nextCursor = encodeBidCursor(bidCursor{Value: value, Sequence: last.Sequence, Id: last.Id})
+	}
+
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, bid_entity.Bid{
+			Id:        bidEntityMongo.Id,
+			UserId:    bidEntityMongo.UserId,
+			AuctionId: bidEntityMongo.AuctionId,
+			Amount:    bidEntityMongo.Amount,
+			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+			Sequence:  bidEntityMongo.Sequence,
+		})
+	}
+
+	return bidEntities, nextCursor, nil
+}
+
+func encodeBidCursor(cursor bidCursor) string {
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeBidCursor(cursor string) (bidCursor, error) {
+	var decoded bidCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return decoded, err
+	}
+
+	err = json.Unmarshal(raw, &decoded)
+	return decoded, err
+}
@@ -0,0 +1,59 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// FindLatestBidsByAuctionIds reads the latest bids of many auctions in a
+// single aggregation, up to limit per auction. $topN keeps only those in
+// memory however many bids an auction got
+func (bd *BidRepository) FindLatestBidsByAuctionIds(
+	ctx context.Context,
+	auctionIds []string,
+	limit int64) (map[string][]bid_entity.Bid, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"auction_id": bson.M{"$in": auctionIds}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$auction_id",
+			"bids": bson.M{"$topN": bson.M{
+				"n":      limit,
+				"sortBy": bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: 1}},
+				"output": "$$ROOT",
+			}},
+		}}},
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find latest bids", err, zap.Int("auctions", len(auctionIds)))
+		return nil, internal_error.NewInternalServerError("Error trying to find bids")
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		AuctionId string           `bson:"_id"`
+		Bids      []BidEntityMongo `bson:"bids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error("Error trying to find latest bids", err, zap.Int("auctions", len(auctionIds)))
+		return nil, internal_error.NewInternalServerError("Error trying to find bids")
+	}
+
+	bidsByAuction := make(map[string][]bid_entity.Bid, len(groups))
+	for _, group := range groups {
+		bids := make([]bid_entity.Bid, 0, len(group.Bids))
+		for i := range group.Bids {
+			bids = append(bids, toBidEntity(&group.Bids[i]))
+		}
+		bidsByAuction[group.AuctionId] = bids
+	}
+
+	return bidsByAuction, nil
+}
@@ -0,0 +1,48 @@
+package bid
+
+import (
+	"auction_go/internal/entity/history_entity"
+	"context"
+)
+
+// EventLog appends the bids to their auction's history. It writes through
+// the ctx it is given, so the events commit along with the bids
+type EventLog interface {
+	AppendEvent(ctx context.Context, event history_entity.AuctionEvent) error
+}
+
+// SetEventLog has every bid stored from then on appended to eventLog, in the
+// same transaction as the bid's insert
+func (bd *BidRepository) SetEventLog(eventLog EventLog) {
+	bd.auctionStateMutex.Lock()
+	defer bd.auctionStateMutex.Unlock()
+
+	bd.eventLog = eventLog
+}
+
+func (bd *BidRepository) logBids(ctx context.Context, bidsMongo ...*BidEntityMongo) error {
+	bd.auctionStateMutex.Lock()
+	eventLog := bd.eventLog
+	bd.auctionStateMutex.Unlock()
+
+	if eventLog == nil {
+		return nil
+	}
+
+	for _, bidEntityMongo := range bidsMongo {
+		if err := eventLog.AppendEvent(ctx, history_entity.CreateBidEvent(toBidEntity(bidEntityMongo))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordBids appends the stored bids to the event log and the outbox
+func (bd *BidRepository) recordBids(ctx context.Context, bidsMongo ...*BidEntityMongo) error {
+	if err := bd.logBids(ctx, bidsMongo...); err != nil {
+		return err
+	}
+
+	return bd.addToOutbox(ctx, bidsMongo...)
+}
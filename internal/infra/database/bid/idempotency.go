@@ -0,0 +1,111 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// idempotencyKey identifies the bid a retry repeats, keys are picked by
+// clients so they are only unique per user
+type idempotencyKey struct {
+	userId string
+	key    string
+}
+
+// FindBidByIdempotencyKey returns the bid the user placed with the key, not
+// found when there is none
+func (bd *BidRepository) FindBidByIdempotencyKey(
+	ctx context.Context, userId, idempotencyKey string) (*bid_entity.Bid, *internal_error.InternalError) {
+	filter := bson.M{"user_id": userId, "idempotency_key": idempotencyKey}
+
+	var bidEntityMongo BidEntityMongo
+	if err := bd.Collection.FindOne(ctx, filter).Decode(&bidEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError("No bid found for this idempotency key")
+		}
+
+		logger.Error("Error trying to find bid by idempotency key", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find bid by idempotency key")
+	}
+
+	return &bid_entity.Bid{
+		Id:             bidEntityMongo.Id,
+		UserId:         bidEntityMongo.UserId,
+		AuctionId:      bidEntityMongo.AuctionId,
+		Amount:         bidEntityMongo.Amount,
+		Timestamp:      time.Unix(bidEntityMongo.Timestamp, 0),
+		Sequence:       bidEntityMongo.Sequence,
+		IdempotencyKey: bidEntityMongo.IdempotencyKey,
+	}, nil
+}
+
+// rejectRetries turns down the retries of bids already stored or queued
+// earlier in the batch, before they can raise the price. The unique index on
+// the key still catches the ones this misses
+func (bd *BidRepository) rejectRetries(ctx context.Context, bids []bid_entity.Bid, results []error) {
+	seen := make(map[idempotencyKey]bool)
+	var keys bson.A
+	for i, bid := range bids {
+		if bid.IdempotencyKey == "" {
+			continue
+		}
+
+		key := idempotencyKey{userId: bid.UserId, key: bid.IdempotencyKey}
+		if seen[key] {
+			results[i] = bid_entity.ErrDuplicateBid
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, bson.M{"user_id": bid.UserId, "idempotency_key": bid.IdempotencyKey})
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	stored, err := bd.storedKeys(ctx, keys)
+	if err != nil {
+		logger.Error("Error trying to find retried bids", err)
+	}
+
+	for i, bid := range bids {
+		if results[i] != nil || bid.IdempotencyKey == "" {
+			continue
+		}
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		if stored[idempotencyKey{userId: bid.UserId, key: bid.IdempotencyKey}] {
+			results[i] = bid_entity.ErrDuplicateBid
+		}
+	}
+}
+
+func (bd *BidRepository) storedKeys(ctx context.Context, keys bson.A) (map[idempotencyKey]bool, error) {
+	findOptions := options.Find().SetProjection(bson.M{"user_id": 1, "idempotency_key": 1})
+	cursor, err := bd.Collection.Find(ctx, bson.M{"$or": keys}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var storedBids []BidEntityMongo
+	if err := cursor.All(ctx, &storedBids); err != nil {
+		return nil, err
+	}
+
+	stored := make(map[idempotencyKey]bool, len(storedBids))
+	for _, storedBid := range storedBids {
+		stored[idempotencyKey{userId: storedBid.UserId, key: storedBid.IdempotencyKey}] = true
+	}
+
+	return stored, nil
+}
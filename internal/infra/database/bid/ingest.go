@@ -0,0 +1,220 @@
+package bid
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ingestRequest is a bid waiting in its auction's queue, its outcome is sent
+// on result once it is written
+type ingestRequest struct {
+	bid    bid_entity.Bid
+	result chan error
+}
+
+func (bd *BidRepository) flush(pending []ingestRequest) {
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bids := make([]bid_entity.Bid, 0, len(pending))
+	for _, request := range pending {
+		bids = append(bids, request.bid)
+	}
+
+	for i, err := range bd.insertBatch(ctx, bids) {
+		pending[i].result <- err
+	}
+}
+
+// insertBatch stores the bids that beat their auction's current price with
+// a single InsertMany, returning the outcome of each bid in order. Bids on
+// the same auction raise its price one after the other, in the order given
+func (bd *BidRepository) insertBatch(ctx context.Context, bids []bid_entity.Bid) []error {
+	results := make([]error, len(bids))
+	previousPrices := make([]CurrentPriceMongo, len(bids))
+	// Bids on Dutch auctions are stored as they take the auction
+	stored := make([]bool, len(bids))
+
+	// Raising the price assigns the bid its sequence
+	bidsMongo := make([]*BidEntityMongo, len(bids))
+	for i, bid := range bids {
+		bidsMongo[i] = toBidEntityMongo(bid)
+	}
+
+	bd.rejectRetries(ctx, bids, results)
+
+	byAuction := make(map[string][]int)
+	for i, bid := range bids {
+		byAuction[bid.AuctionId] = append(byAuction[bid.AuctionId], i)
+	}
+
+	var wg sync.WaitGroup
+	states := make(map[string]auctionState, len(byAuction))
+	var statesMutex sync.Mutex
+	for auctionId, indexes := range byAuction {
+		wg.Add(1)
+		go func(auctionId string, indexes []int) {
+			defer wg.Done()
+
+			state, err := bd.findAuctionState(ctx, auctionId)
+			if err != nil {
+				for _, i := range indexes {
+					results[i] = err
+				}
+				return
+			}
+
+			statesMutex.Lock()
+			states[auctionId] = state
+			statesMutex.Unlock()
+
+			for _, i := range indexes {
+				if results[i] != nil {
+					continue
+				}
+				if !state.open(time.Now()) {
+					results[i] = bid_entity.ErrAuctionClosed
+					continue
+				}
+				if state.auctionType == auction_entity.Dutch {
+					results[i], stored[i] = bd.takeDutchAuction(ctx, bids[i]), true
+					continue
+				}
+				previousPrices[i], results[i] = bd.raiseCurrentPrice(ctx, bidsMongo[i], state.minIncrement)
+			}
+		}(auctionId, indexes)
+	}
+	wg.Wait()
+
+	accepted := make([]int, 0, len(bids))
+	acceptedMongo := make([]*BidEntityMongo, 0, len(bids))
+	for i, err := range results {
+		if err == nil && !stored[i] {
+			accepted = append(accepted, i)
+			acceptedMongo = append(acceptedMongo, bidsMongo[i])
+		}
+	}
+	if len(acceptedMongo) == 0 {
+		return results
+	}
+
+	for j, err := range bd.storeBatch(ctx, acceptedMongo) {
+		results[accepted[j]] = err
+	}
+
+	// Latest first, so a price is put back before the one it replaced
+	for j := len(accepted) - 1; j >= 0; j-- {
+		if i := accepted[j]; results[i] != nil {
+			bd.restoreCurrentPrice(ctx, bidsMongo[i], previousPrices[i])
+		}
+	}
+
+	proxyAuctions := make(map[string]bool)
+	for _, i := range accepted {
+		if results[i] != nil {
+			continue
+		}
+		bd.notifyOutbid(ctx, previousPrices[i].UserId, bidsMongo[i])
+		bd.runBidHooks(ctx, bidsMongo[i])
+		bd.extendIfSniped(ctx, bids[i], states[bids[i].AuctionId].endTime)
+		proxyAuctions[bids[i].AuctionId] = true
+	}
+	for auctionId := range proxyAuctions {
+		bd.bidForProxies(ctx, auctionId, states[auctionId])
+	}
+
+	return results
+}
+
+// storeBatch inserts the bids along with their events and outbox entries,
+// returning the outcome of each bid in order. In a transaction one failing
+// bid fails the whole batch, so a batch some bids failed is stored again
+// one bid at a time
+func (bd *BidRepository) storeBatch(ctx context.Context, bidsMongo []*BidEntityMongo) []error {
+	results := make([]error, len(bidsMongo))
+	documents := make([]interface{}, 0, len(bidsMongo))
+	for _, bidEntityMongo := range bidsMongo {
+		documents = append(documents, bidEntityMongo)
+	}
+	insertOptions := options.InsertMany().SetOrdered(false)
+
+	err := mongodb.RunInTransaction(ctx, bd.Collection.Database(), func(ctx context.Context) error {
+		if _, err := bd.Collection.InsertMany(ctx, documents, insertOptions); err != nil {
+			return err
+		}
+		return bd.recordBids(ctx, bidsMongo...)
+	})
+	if err == nil {
+		return results
+	}
+	logger.Error("Error trying to insert bids", err)
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) {
+		for i := range results {
+			results[i] = err
+		}
+		return results
+	}
+	for i, bidEntityMongo := range bidsMongo {
+		results[i] = bd.storeBid(ctx, bidEntityMongo)
+	}
+	return results
+}
+
+// bidError turns the outcome of a bid into what the caller is told
+func bidError(err error) *internal_error.InternalError {
+	if err == nil {
+		return nil
+	}
+
+	var internalErr *internal_error.InternalError
+	switch {
+	case errors.As(err, &internalErr):
+		return internalErr
+	case errors.Is(err, bid_entity.ErrBidTooLow),
+		errors.Is(err, bid_entity.ErrAuctionClosed),
+		errors.Is(err, bid_entity.ErrDuplicateBid):
+		return internal_error.NewBadRequestError(err.Error())
+	default:
+		return internal_error.NewInternalServerError("Error trying to insert bid")
+	}
+}
+
+func toBidEntityMongo(bid bid_entity.Bid) *BidEntityMongo {
+	return &BidEntityMongo{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount,
+		Timestamp: bid.Timestamp.Unix(),
+		Sequence:  bid.Sequence,
+
+		IdempotencyKey: bid.IdempotencyKey,
+	}
+}
+
+func getIngestBatchSize() int {
+	batchSize, err := strconv.Atoi(os.Getenv("BID_INGEST_BATCH_SIZE"))
+	if err != nil || batchSize <= 0 {
+		return 100
+	}
+
+	return batchSize
+}
@@ -0,0 +1,53 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/bid_entity"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// SetOutbidNotifier replaces where outbid events go, they are only logged
+// until a notifier is set
+func (bd *BidRepository) SetOutbidNotifier(notifier bid_entity.OutbidNotifier) {
+	bd.auctionStateMutex.Lock()
+	defer bd.auctionStateMutex.Unlock()
+
+	bd.outbidNotifier = notifier
+}
+
+// notifyOutbid tells the bidder the stored bid took the lead from, bidders
+// raising their own bid aren't outbid
+func (bd *BidRepository) notifyOutbid(ctx context.Context, previousBidderId string, bid *BidEntityMongo) {
+	if previousBidderId == "" || previousBidderId == bid.UserId {
+		return
+	}
+
+	bd.auctionStateMutex.Lock()
+	notifier := bd.outbidNotifier
+	bd.auctionStateMutex.Unlock()
+
+	// A failing notifier must not fail the bid that was already stored
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Outbid notifier panicked", fmt.Errorf("%v", r), zap.String("auctionID", bid.AuctionId))
+		}
+	}()
+
+	notifier.NotifyOutbid(ctx, bid_entity.OutbidEvent{
+		AuctionId:        bid.AuctionId,
+		PreviousBidderId: previousBidderId,
+		NewAmount:        bid.Amount,
+	})
+}
+
+type logOutbidNotifier struct{}
+
+func (logOutbidNotifier) NotifyOutbid(ctx context.Context, event bid_entity.OutbidEvent) {
+	logger.Info("Bidder outbid",
+		zap.String("auctionID", event.AuctionId),
+		zap.String("previousBidderID", event.PreviousBidderId),
+		zap.Float64("newAmount", event.NewAmount))
+}
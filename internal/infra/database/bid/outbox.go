@@ -0,0 +1,63 @@
+package bid
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/internal/entity/bid_entity"
+	"context"
+	"time"
+)
+
+// Outbox keeps the bids published outside the process. It writes through
+// the ctx it is given, so its entries commit along with the bid
+type Outbox interface {
+	AddBidPlaced(ctx context.Context, bid bid_entity.Bid) error
+}
+
+// SetOutbox has every bid stored from then on add its event to outbox, in
+// the same transaction as the bid's insert. Without one the bids only reach
+// the hooks
+func (bd *BidRepository) SetOutbox(outbox Outbox) {
+	bd.auctionStateMutex.Lock()
+	defer bd.auctionStateMutex.Unlock()
+
+	bd.outbox = outbox
+}
+
+func (bd *BidRepository) addToOutbox(ctx context.Context, bidsMongo ...*BidEntityMongo) error {
+	bd.auctionStateMutex.Lock()
+	outbox := bd.outbox
+	bd.auctionStateMutex.Unlock()
+
+	if outbox == nil {
+		return nil
+	}
+
+	for _, bidEntityMongo := range bidsMongo {
+		if err := outbox.AddBidPlaced(ctx, toBidEntity(bidEntityMongo)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storeBid inserts the bid along with its event and outbox entry
+func (bd *BidRepository) storeBid(ctx context.Context, bidEntityMongo *BidEntityMongo) error {
+	return mongodb.RunInTransaction(ctx, bd.Collection.Database(), func(ctx context.Context) error {
+		if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
+			return err
+		}
+		return bd.recordBids(ctx, bidEntityMongo)
+	})
+}
+
+func toBidEntity(bidEntityMongo *BidEntityMongo) bid_entity.Bid {
+	return bid_entity.Bid{
+		Id:        bidEntityMongo.Id,
+		UserId:    bidEntityMongo.UserId,
+		AuctionId: bidEntityMongo.AuctionId,
+		Amount:    bidEntityMongo.Amount,
+		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+		Sequence:  bidEntityMongo.Sequence,
+	}
+}
@@ -0,0 +1,207 @@
+package bid
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ProxyBidMongo is the maximum a user is willing to pay for an auction, a
+// user has at most one per auction
+type ProxyBidMongo struct {
+	Id        string  `bson:"_id"`
+	UserId    string  `bson:"user_id"`
+	AuctionId string  `bson:"auction_id"`
+	MaxAmount float64 `bson:"max_amount"`
+	Timestamp int64   `bson:"timestamp"`
+}
+
+// ProxyBidCheck tells whether the bidder may still place a bid the
+// repository is about to place for their proxy
+type ProxyBidCheck func(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError
+
+// SetProxyBidCheck sets the check every bid placed for a proxy goes through,
+// proxies bid unchecked until it is set
+func (bd *BidRepository) SetProxyBidCheck(check ProxyBidCheck) {
+	bd.auctionStateMutex.Lock()
+	defer bd.auctionStateMutex.Unlock()
+
+	bd.proxyBidCheck = check
+}
+
+// proxyBidAttempts bounds how often the proxies retry when a concurrent bid
+// beat the one placed for them
+const proxyBidAttempts = 3
+
+// CreateProxyBid stores the user's maximum for a running auction, replacing
+// the one they set before, and bids for them right away if they are outbid.
+// Changing the maximum counts as a new proxy for breaking ties
+func (bd *BidRepository) CreateProxyBid(
+	ctx context.Context, proxyBid bid_entity.ProxyBid) *internal_error.InternalError {
+	state, err := bd.findAuctionState(ctx, proxyBid.AuctionId)
+	if err != nil {
+		return err
+	}
+	if !state.open(time.Now()) {
+		return internal_error.NewBadRequestError("Only running auctions take proxy bids")
+	}
+	if state.auctionType == auction_entity.Dutch {
+		return internal_error.NewBadRequestError("Dutch auctions don't take proxy bids")
+	}
+
+	filter := bson.M{"auction_id": proxyBid.AuctionId, "user_id": proxyBid.UserId}
+	update := bson.M{
+		"$set": bson.M{
+			"max_amount": proxyBid.MaxAmount,
+			"timestamp":  proxyBid.Timestamp.Unix(),
+		},
+		"$setOnInsert": bson.M{"_id": proxyBid.Id},
+	}
+
+	if _, err := bd.proxyBidCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to save proxy bid", err, zap.String("auctionID", proxyBid.AuctionId))
+		return internal_error.NewInternalServerError("Error trying to save proxy bid")
+	}
+
+	bd.bidForProxies(ctx, proxyBid.AuctionId, state)
+
+	return nil
+}
+
+// bidForProxies answers the current price with the strongest proxy of the
+// auction, bidding the least that beats everyone else by the increment,
+// capped at its own maximum. A single bid settles it, the runner-up can't
+// beat it afterwards. A proxy whose bidder may no longer bid is dropped and
+// the next one answers instead
+func (bd *BidRepository) bidForProxies(ctx context.Context, auctionId string, state auctionState) {
+	for attempt := 0; attempt < proxyBidAttempts; attempt++ {
+		if !state.open(time.Now()) {
+			return
+		}
+
+		bid, err := bd.nextProxyBid(ctx, auctionId, state.minIncrement)
+		if err != nil {
+			logger.Error("Error trying to bid for proxies", err, zap.String("auctionID", auctionId))
+			return
+		}
+		if bid == nil {
+			return
+		}
+
+		if errCheck := bd.checkProxyBid(ctx, *bid); errCheck != nil {
+			if errCheck.Err == "internal_server_error" {
+				return
+			}
+			bd.dropProxyBid(ctx, *bid, errCheck)
+			continue
+		}
+
+		if bd.placeBid(ctx, *bid, state) {
+			return
+		}
+	}
+}
+
+func (bd *BidRepository) checkProxyBid(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	bd.auctionStateMutex.Lock()
+	check := bd.proxyBidCheck
+	bd.auctionStateMutex.Unlock()
+
+	if check == nil {
+		return nil
+	}
+	return check(ctx, bid)
+}
+
+// dropProxyBid deletes the proxy of a bidder whose bid was turned down, it
+// would be turned down again on every price change
+func (bd *BidRepository) dropProxyBid(ctx context.Context, bid bid_entity.Bid, reason *internal_error.InternalError) {
+	logger.Info("Proxy bid dropped", zap.String("reason", reason.Message),
+		zap.String("auctionID", bid.AuctionId), zap.String("userID", bid.UserId))
+
+	filter := bson.M{"auction_id": bid.AuctionId, "user_id": bid.UserId}
+	if _, err := bd.proxyBidCollection.DeleteOne(ctx, filter); err != nil {
+		logger.Error("Error trying to drop proxy bid", err, zap.String("auctionID", bid.AuctionId))
+	}
+}
+
+// nextProxyBid is the bid the strongest proxy places against the current
+// price, nil when it is already leading unchallenged or can't beat it
+func (bd *BidRepository) nextProxyBid(
+	ctx context.Context, auctionId string, increment float64) (*bid_entity.Bid, error) {
+	var currentPrice CurrentPriceMongo
+	err := bd.currentPriceCollection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&currentPrice)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if currentPrice.Closed {
+		return nil, nil
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "max_amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(2)
+	cursor, err := bd.proxyBidCollection.Find(ctx, bson.M{"auction_id": auctionId}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var proxies []ProxyBidMongo
+	if err := cursor.All(ctx, &proxies); err != nil {
+		return nil, err
+	}
+	if len(proxies) == 0 {
+		return nil, nil
+	}
+
+	leader := proxies[0]
+	leading := currentPrice.UserId == leader.UserId
+
+	// The amount the strongest proxy has to beat
+	challenge, challenged := 0.0, false
+	if !leading && currentPrice.Amount > 0 {
+		challenge, challenged = currentPrice.Amount, true
+	}
+	if len(proxies) > 1 && proxies[1].MaxAmount > challenge {
+		challenge, challenged = proxies[1].MaxAmount, true
+	}
+	if !challenged && leading {
+		return nil, nil
+	}
+
+	amount := min(challenge+increment, leader.MaxAmount)
+	if amount <= currentPrice.Amount || amount < minimumBid(currentPrice.Amount, increment) {
+		return nil, nil
+	}
+
+	return &bid_entity.Bid{
+		Id:        uuid.New().String(),
+		UserId:    leader.UserId,
+		AuctionId: auctionId,
+		Amount:    amount,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// getBidIncrement is the least a bid has to add to the current price on
+// auctions that don't set their own
+func getBidIncrement() float64 {
+	increment, err := strconv.ParseFloat(os.Getenv("BID_INCREMENT"), 64)
+	if err != nil || increment <= 0 {
+		return 1
+	}
+
+	return increment
+}
@@ -0,0 +1,237 @@
+package category
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/category_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// CategoryEntityMongo is kept unique by a unique index on key
+type CategoryEntityMongo struct {
+	Id        string `bson:"_id"`
+	Name      string `bson:"name"`
+	Key       string `bson:"key"`
+	ParentId  string `bson:"parent_id,omitempty"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+type CategoryRepository struct {
+	Collection        *mongo.Collection
+	auctionCollection *mongo.Collection
+}
+
+func NewCategoryRepository(database *mongo.Database) *CategoryRepository {
+	repo := &CategoryRepository{
+		Collection:        database.Collection("categories"),
+		auctionCollection: database.Collection("auctions"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the categories collection
+func (cr *CategoryRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, cr.Collection.Database(), "categories"); err != nil {
+		logger.Error("Error trying to create category indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create category indexes")
+	}
+
+	return nil
+}
+
+func (cr *CategoryRepository) CreateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	categoryMongo := &CategoryEntityMongo{
+		Id:        category.Id,
+		Name:      category.Name,
+		Key:       category.Key,
+		ParentId:  category.ParentId,
+		Timestamp: category.Timestamp.Unix(),
+	}
+
+	if _, err := cr.Collection.InsertOne(ctx, categoryMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError(
+				fmt.Sprintf("A category named %s already exists", category.Name))
+		}
+
+		logger.Error("Error trying to insert category", err)
+		return internal_error.NewInternalServerError("Error trying to insert category")
+	}
+
+	return nil
+}
+
+// FindCategories lists the whole taxonomy by name
+func (cr *CategoryRepository) FindCategories(
+	ctx context.Context) ([]category_entity.Category, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "key", Value: 1}})
+	cursor, err := cr.Collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find categories", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find categories")
+	}
+	defer cursor.Close(ctx)
+
+	var categoriesMongo []CategoryEntityMongo
+	if err := cursor.All(ctx, &categoriesMongo); err != nil {
+		logger.Error("Error trying to decode categories", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find categories")
+	}
+
+	categories := make([]category_entity.Category, 0, len(categoriesMongo))
+	for _, categoryMongo := range categoriesMongo {
+		categories = append(categories, *toCategoryEntity(categoryMongo))
+	}
+
+	return categories, nil
+}
+
+func (cr *CategoryRepository) FindCategoryById(
+	ctx context.Context, id string) (*category_entity.Category, *internal_error.InternalError) {
+	return cr.findCategory(ctx, bson.M{"_id": id},
+		fmt.Sprintf("Category not found with this id = %s", id))
+}
+
+func (cr *CategoryRepository) FindCategoryByName(
+	ctx context.Context, name string) (*category_entity.Category, *internal_error.InternalError) {
+	return cr.findCategory(ctx, bson.M{"key": category_entity.CategoryKey(name)},
+		fmt.Sprintf("Category not found with this name = %s", name))
+}
+
+func (cr *CategoryRepository) findCategory(
+	ctx context.Context, filter bson.M, notFound string) (*category_entity.Category, *internal_error.InternalError) {
+	var categoryMongo CategoryEntityMongo
+	if err := cr.Collection.FindOne(ctx, filter).Decode(&categoryMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(notFound)
+		}
+
+		logger.Error("Error trying to find category", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find category")
+	}
+
+	return toCategoryEntity(categoryMongo), nil
+}
+
+// CountActiveAuctions groups the active auctions by category key, auctions
+// filed before categories were managed count towards the category their
+// name folds into
+func (cr *CategoryRepository) CountActiveAuctions(
+	ctx context.Context) (category_entity.CategoryCount, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": auction_entity.Active}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$toLower": "$category"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := cr.auctionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to count auctions by category", err)
+		return nil, internal_error.NewInternalServerError("Error trying to count auctions by category")
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Category string `bson:"_id"`
+		Count    int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error("Error trying to decode auction counts by category", err)
+		return nil, internal_error.NewInternalServerError("Error trying to count auctions by category")
+	}
+
+	counts := make(category_entity.CategoryCount, len(groups))
+	for _, group := range groups {
+		counts[category_entity.CategoryKey(group.Category)] += group.Count
+	}
+
+	return counts, nil
+}
+
+// BackfillCategories files every category auctions were created with
+// before the taxonomy existed as a top-level category, and renames the
+// auctions' categories to the managed name so "electronics" and
+// "Electronics" list together. Categories already there are kept, so it is
+// safe to run on every start
+func (cr *CategoryRepository) BackfillCategories(ctx context.Context) *internal_error.InternalError {
+	names, err := cr.auctionCollection.Distinct(ctx, "category", bson.M{})
+	if err != nil {
+		logger.Error("Error trying to find auction categories", err)
+		return internal_error.NewInternalServerError("Error trying to backfill categories")
+	}
+
+	for _, value := range names {
+		name, ok := value.(string)
+		if !ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		category, err := cr.ensureCategory(ctx, name)
+		if err != nil {
+			logger.Error("Error trying to backfill category", err, zap.String("category", name))
+			return internal_error.NewInternalServerError("Error trying to backfill categories")
+		}
+
+		if category.Name == name {
+			continue
+		}
+		if _, err := cr.auctionCollection.UpdateMany(ctx,
+			bson.M{"category": name}, bson.M{"$set": bson.M{"category": category.Name}}); err != nil {
+			logger.Error("Error trying to rename auction category", err, zap.String("category", name))
+			return internal_error.NewInternalServerError("Error trying to backfill categories")
+		}
+	}
+
+	return nil
+}
+
+// ensureCategory finds the category the name folds into, creating it when
+// there is none
+func (cr *CategoryRepository) ensureCategory(ctx context.Context, name string) (*CategoryEntityMongo, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":       uuid.New().String(),
+		"name":      name,
+		"timestamp": time.Now().Unix(),
+	}}
+
+	var categoryMongo CategoryEntityMongo
+	findOptions := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := cr.Collection.FindOneAndUpdate(ctx,
+		bson.M{"key": category_entity.CategoryKey(name)}, update, findOptions).Decode(&categoryMongo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &categoryMongo, nil
+}
+
+func toCategoryEntity(categoryMongo CategoryEntityMongo) *category_entity.Category {
+	return &category_entity.Category{
+		Id:        categoryMongo.Id,
+		Name:      categoryMongo.Name,
+		Key:       categoryMongo.Key,
+		ParentId:  categoryMongo.ParentId,
+		Timestamp: time.Unix(categoryMongo.Timestamp, 0),
+	}
+}
@@ -0,0 +1,244 @@
+package checkout
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AddressMongo struct {
+	Street     string `bson:"street"`
+	Number     string `bson:"number"`
+	City       string `bson:"city"`
+	State      string `bson:"state"`
+	PostalCode string `bson:"postal_code"`
+	Country    string `bson:"country"`
+}
+
+type ShipmentMongo struct {
+	Carrier        string `bson:"carrier"`
+	TrackingNumber string `bson:"tracking_number"`
+	CarrierStatus  string `bson:"carrier_status"`
+	UpdatedAt      int64  `bson:"updated_at"`
+}
+
+type PaymentMongo struct {
+	Method     string `bson:"method"`
+	TxId       string `bson:"tx_id"`
+	PixPayload string `bson:"pix_payload,omitempty"`
+	EndToEndId string `bson:"end_to_end_id,omitempty"`
+	PaidAt     int64  `bson:"paid_at,omitempty"`
+}
+
+// RefundMongo is also written by the return repository, which accepts the
+// return and moves the checkout to Returned together
+type RefundMongo struct {
+	ReturnRequestId string  `bson:"return_request_id"`
+	Amount          float64 `bson:"amount"`
+	RequestedAt     int64   `bson:"requested_at"`
+	RefundedAt      int64   `bson:"refunded_at,omitempty"`
+}
+
+// ToRefundMongo maps the refund of a checkout to how it is stored
+func ToRefundMongo(refund *checkout_entity.Refund) *RefundMongo {
+	if refund == nil {
+		return nil
+	}
+
+	refundMongo := &RefundMongo{
+		ReturnRequestId: refund.ReturnRequestId,
+		Amount:          refund.Amount,
+		RequestedAt:     refund.RequestedAt.Unix(),
+	}
+	if !refund.RefundedAt.IsZero() {
+		refundMongo.RefundedAt = refund.RefundedAt.Unix()
+	}
+
+	return refundMongo
+}
+
+type CheckoutEntityMongo struct {
+	Id             string                         `bson:"_id"`
+	AuctionId      string                         `bson:"auction_id"`
+	BuyerUserId    string                         `bson:"buyer_user_id"`
+	ItemAmount     float64                        `bson:"item_amount"`
+	Address        *AddressMongo                  `bson:"address,omitempty"`
+	ShippingOption string                         `bson:"shipping_option"`
+	ShippingAmount float64                        `bson:"shipping_amount"`
+	TotalAmount    float64                        `bson:"total_amount"`
+	Payment        *PaymentMongo                  `bson:"payment,omitempty"`
+	Shipment       *ShipmentMongo                 `bson:"shipment,omitempty"`
+	Refund         *RefundMongo                   `bson:"refund,omitempty"`
+	Status         checkout_entity.CheckoutStatus `bson:"status"`
+	DeliveredAt    int64                          `bson:"delivered_at,omitempty"`
+	Timestamp      int64                          `bson:"timestamp"`
+	Version        int64                          `bson:"version"`
+}
+
+type CheckoutRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCheckoutRepository(database *mongo.Database) *CheckoutRepository {
+	repo := &CheckoutRepository{
+		Collection: database.Collection("checkouts"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the checkouts collection
+func (cr *CheckoutRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, cr.Collection.Database(), "checkouts"); err != nil {
+		logger.Error("Error trying to create checkout indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create checkout indexes")
+	}
+
+	return nil
+}
+
+// CreateCheckout stores the checkout unless the auction already has one, in
+// which case the existing checkout is returned untouched
+func (cr *CheckoutRepository) CreateCheckout(
+	ctx context.Context,
+	checkoutEntity *checkout_entity.Checkout) (*checkout_entity.Checkout, *internal_error.InternalError) {
+	filter := bson.M{"auction_id": checkoutEntity.AuctionId}
+	update := bson.M{"$setOnInsert": toCheckoutEntityMongo(checkoutEntity)}
+
+	if _, err := cr.Collection.UpdateOne(
+		ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to insert checkout", err)
+		return nil, internal_error.NewInternalServerError("Error trying to insert checkout")
+	}
+
+	return cr.FindCheckoutByAuctionId(ctx, checkoutEntity.AuctionId)
+}
+
+func toCheckoutEntityMongo(checkoutEntity *checkout_entity.Checkout) *CheckoutEntityMongo {
+	checkoutEntityMongo := &CheckoutEntityMongo{
+		Id:             checkoutEntity.Id,
+		AuctionId:      checkoutEntity.AuctionId,
+		BuyerUserId:    checkoutEntity.BuyerUserId,
+		ItemAmount:     checkoutEntity.ItemAmount,
+		ShippingOption: checkoutEntity.ShippingOption,
+		ShippingAmount: checkoutEntity.ShippingAmount,
+		TotalAmount:    checkoutEntity.TotalAmount,
+		Refund:         ToRefundMongo(checkoutEntity.Refund),
+		Status:         checkoutEntity.Status,
+		Timestamp:      checkoutEntity.Timestamp.Unix(),
+		Version:        checkoutEntity.Version,
+	}
+
+	if address := checkoutEntity.Address; address != nil {
+		checkoutEntityMongo.Address = &AddressMongo{
+			Street:     address.Street,
+			Number:     address.Number,
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		}
+	}
+
+	if payment := checkoutEntity.Payment; payment != nil {
+		checkoutEntityMongo.Payment = &PaymentMongo{
+			Method:     payment.Method,
+			TxId:       payment.TxId,
+			PixPayload: payment.PixPayload,
+			EndToEndId: payment.EndToEndId,
+		}
+		if !payment.PaidAt.IsZero() {
+			checkoutEntityMongo.Payment.PaidAt = payment.PaidAt.Unix()
+		}
+	}
+
+	if shipment := checkoutEntity.Shipment; shipment != nil {
+		checkoutEntityMongo.Shipment = &ShipmentMongo{
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			CarrierStatus:  shipment.CarrierStatus,
+			UpdatedAt:      shipment.UpdatedAt.Unix(),
+		}
+	}
+
+	if !checkoutEntity.DeliveredAt.IsZero() {
+		checkoutEntityMongo.DeliveredAt = checkoutEntity.DeliveredAt.Unix()
+	}
+
+	return checkoutEntityMongo
+}
+
+func toCheckoutEntity(checkoutEntityMongo CheckoutEntityMongo) *checkout_entity.Checkout {
+	checkoutEntity := &checkout_entity.Checkout{
+		Id:             checkoutEntityMongo.Id,
+		AuctionId:      checkoutEntityMongo.AuctionId,
+		BuyerUserId:    checkoutEntityMongo.BuyerUserId,
+		ItemAmount:     checkoutEntityMongo.ItemAmount,
+		ShippingOption: checkoutEntityMongo.ShippingOption,
+		ShippingAmount: checkoutEntityMongo.ShippingAmount,
+		TotalAmount:    checkoutEntityMongo.TotalAmount,
+		Status:         checkoutEntityMongo.Status,
+		Timestamp:      time.Unix(checkoutEntityMongo.Timestamp, 0),
+		Version:        checkoutEntityMongo.Version,
+	}
+
+	if address := checkoutEntityMongo.Address; address != nil {
+		checkoutEntity.Address = &checkout_entity.Address{
+			Street:     address.Street,
+			Number:     address.Number,
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		}
+	}
+
+	if payment := checkoutEntityMongo.Payment; payment != nil {
+		checkoutEntity.Payment = &checkout_entity.Payment{
+			Method:     payment.Method,
+			TxId:       payment.TxId,
+			PixPayload: payment.PixPayload,
+			EndToEndId: payment.EndToEndId,
+		}
+		if payment.PaidAt != 0 {
+			checkoutEntity.Payment.PaidAt = time.Unix(payment.PaidAt, 0)
+		}
+	}
+
+	if shipment := checkoutEntityMongo.Shipment; shipment != nil {
+		checkoutEntity.Shipment = &checkout_entity.Shipment{
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			CarrierStatus:  shipment.CarrierStatus,
+			UpdatedAt:      time.Unix(shipment.UpdatedAt, 0),
+		}
+	}
+
+	if refund := checkoutEntityMongo.Refund; refund != nil {
+		checkoutEntity.Refund = &checkout_entity.Refund{
+			ReturnRequestId: refund.ReturnRequestId,
+			Amount:          refund.Amount,
+			RequestedAt:     time.Unix(refund.RequestedAt, 0),
+		}
+		if refund.RefundedAt != 0 {
+			checkoutEntity.Refund.RefundedAt = time.Unix(refund.RefundedAt, 0)
+		}
+	}
+
+	if checkoutEntityMongo.DeliveredAt != 0 {
+		checkoutEntity.DeliveredAt = time.Unix(checkoutEntityMongo.DeliveredAt, 0)
+	}
+
+	return checkoutEntity
+}
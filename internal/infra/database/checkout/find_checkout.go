@@ -0,0 +1,55 @@
+package checkout
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (cr *CheckoutRepository) FindCheckoutById(
+	ctx context.Context, id string) (*checkout_entity.Checkout, *internal_error.InternalError) {
+	return cr.findCheckout(ctx, bson.M{"_id": id},
+		fmt.Sprintf("Checkout not found with this id = %s", id))
+}
+
+func (cr *CheckoutRepository) FindCheckoutByAuctionId(
+	ctx context.Context, auctionId string) (*checkout_entity.Checkout, *internal_error.InternalError) {
+	return cr.findCheckout(ctx, bson.M{"auction_id": auctionId},
+		fmt.Sprintf("Checkout not found for auction id = %s", auctionId))
+}
+
+func (cr *CheckoutRepository) FindCheckoutByTrackingNumber(
+	ctx context.Context, carrier, trackingNumber string) (*checkout_entity.Checkout, *internal_error.InternalError) {
+	filter := bson.M{"shipment.carrier": carrier, "shipment.tracking_number": trackingNumber}
+	return cr.findCheckout(ctx, filter,
+		fmt.Sprintf("Checkout not found for tracking number %s of carrier %s", trackingNumber, carrier))
+}
+
+func (cr *CheckoutRepository) FindCheckoutByPaymentTxId(
+	ctx context.Context, txId string) (*checkout_entity.Checkout, *internal_error.InternalError) {
+	return cr.findCheckout(ctx, bson.M{"payment.tx_id": txId},
+		fmt.Sprintf("Checkout not found for payment txid = %s", txId))
+}
+
+func (cr *CheckoutRepository) findCheckout(
+	ctx context.Context,
+	filter bson.M,
+	notFoundMessage string) (*checkout_entity.Checkout, *internal_error.InternalError) {
+	var checkoutEntityMongo CheckoutEntityMongo
+	if err := cr.Collection.FindOne(ctx, filter).Decode(&checkoutEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(notFoundMessage)
+		}
+
+		logger.Error("Error trying to find checkout", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find checkout")
+	}
+
+	return toCheckoutEntity(checkoutEntityMongo), nil
+}
@@ -0,0 +1,44 @@
+package checkout
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// VersionFilter matches the checkout only while it is still at version,
+// checkouts stored before versions were kept count as version 0
+func VersionFilter(id string, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": id, "version": version}
+}
+
+// UpdateCheckout only writes when the stored checkout is still at the version
+// the change was computed from, so two concurrent writes can't both apply
+// even when they leave the status as it was
+func (cr *CheckoutRepository) UpdateCheckout(
+	ctx context.Context,
+	checkoutEntity *checkout_entity.Checkout) *internal_error.InternalError {
+	filter := VersionFilter(checkoutEntity.Id, checkoutEntity.Version)
+
+	checkoutEntityMongo := toCheckoutEntityMongo(checkoutEntity)
+	checkoutEntityMongo.Version++
+
+	result, err := cr.Collection.ReplaceOne(ctx, filter, checkoutEntityMongo)
+	if err != nil {
+		logger.Error("Error trying to update checkout", err)
+		return internal_error.NewInternalServerError("Error trying to update checkout")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewBadRequestError("Checkout was changed by another request, please retry")
+	}
+
+	checkoutEntity.Version = checkoutEntityMongo.Version
+	return nil
+}
@@ -0,0 +1,91 @@
+package checkout
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/testhelpers"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UpdateCheckoutSuite struct {
+	suite.Suite
+	database *mongo.Database
+	repo     *CheckoutRepository
+}
+
+func (suite *UpdateCheckoutSuite) SetupSuite() {
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
+	suite.repo = NewCheckoutRepository(suite.database)
+}
+
+// createCheckout stores a checkout awaiting payment
+func (suite *UpdateCheckoutSuite) createCheckout() *checkout_entity.Checkout {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	checkout, err := checkout_entity.CreateCheckout(uuid.New().String(), uuid.New().String(), 100)
+	assert.Nil(suite.T(), err)
+	checkout.Status = checkout_entity.AwaitingPayment
+
+	stored, err := suite.repo.CreateCheckout(ctx, checkout)
+	assert.Nil(suite.T(), err)
+	return stored
+}
+
+func (suite *UpdateCheckoutSuite) find(id string) *checkout_entity.Checkout {
+	checkout, err := suite.repo.FindCheckoutById(context.Background(), id)
+	assert.Nil(suite.T(), err)
+	return checkout
+}
+
+func (suite *UpdateCheckoutSuite) TestWritesBumpTheVersion() {
+	checkout := suite.createCheckout()
+	assert.Equal(suite.T(), int64(0), checkout.Version)
+
+	assert.Nil(suite.T(), checkout.SelectPayment(checkout_entity.Payment{Method: "pix", TxId: "tx-1"}))
+	err := suite.repo.UpdateCheckout(context.Background(), checkout)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), checkout.Version)
+	assert.Equal(suite.T(), int64(1), suite.find(checkout.Id).Version)
+}
+
+func (suite *UpdateCheckoutSuite) TestStaleWritesKeepingTheStatusAreRejected() {
+	checkout := suite.createCheckout()
+
+	// Both read the checkout awaiting payment, neither moves it on
+	first, second := suite.find(checkout.Id), suite.find(checkout.Id)
+	assert.Nil(suite.T(), first.SelectPayment(checkout_entity.Payment{Method: "pix", TxId: "tx-1"}))
+	assert.Nil(suite.T(), second.SelectPayment(checkout_entity.Payment{Method: "pix", TxId: "tx-2"}))
+
+	err := suite.repo.UpdateCheckout(context.Background(), first)
+	assert.Nil(suite.T(), err)
+
+	err = suite.repo.UpdateCheckout(context.Background(), second)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "bad_request", err.Err)
+	assert.Equal(suite.T(), "tx-1", suite.find(checkout.Id).Payment.TxId)
+}
+
+func (suite *UpdateCheckoutSuite) TestCheckoutsStoredWithoutAVersionCanBeUpdated() {
+	checkout := suite.createCheckout()
+	_, errUnset := suite.repo.Collection.UpdateOne(context.Background(),
+		bson.M{"_id": checkout.Id}, bson.M{"$unset": bson.M{"version": ""}})
+	assert.Nil(suite.T(), errUnset)
+
+	legacy := suite.find(checkout.Id)
+	assert.Nil(suite.T(), legacy.SelectPayment(checkout_entity.Payment{Method: "pix", TxId: "tx-1"}))
+	err := suite.repo.UpdateCheckout(context.Background(), legacy)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), suite.find(checkout.Id).Version)
+}
+
+func TestUpdateCheckoutSuite(t *testing.T) {
+	suite.Run(t, new(UpdateCheckoutSuite))
+}
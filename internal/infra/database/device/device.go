@@ -0,0 +1,167 @@
+package device
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/device_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// DeviceEntityMongo is kept unique by a unique index on platform and token
+type DeviceEntityMongo struct {
+	Id        string                 `bson:"_id"`
+	UserId    string                 `bson:"user_id"`
+	Platform  device_entity.Platform `bson:"platform"`
+	Token     string                 `bson:"token"`
+	Timestamp int64                  `bson:"timestamp"`
+}
+
+type DeviceRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewDeviceRepository(database *mongo.Database) *DeviceRepository {
+	repo := &DeviceRepository{
+		Collection: database.Collection("devices"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the devices collection
+func (dr *DeviceRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, dr.Collection.Database(), "devices"); err != nil {
+		logger.Error("Error trying to create device indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create device indexes")
+	}
+
+	return nil
+}
+
+// RegisterDevice upserts the device by its token, then drops the user's
+// oldest devices past MaxDevices
+func (dr *DeviceRepository) RegisterDevice(
+	ctx context.Context, device *device_entity.Device) (*device_entity.Device, *internal_error.InternalError) {
+	filter := bson.M{"platform": device.Platform, "token": device.Token}
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":   device.UserId,
+			"timestamp": device.Timestamp.Unix(),
+		},
+		"$setOnInsert": bson.M{"_id": device.Id},
+	}
+
+	var deviceMongo DeviceEntityMongo
+	updateOptions := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := dr.Collection.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&deviceMongo); err != nil {
+		logger.Error("Error trying to register device", err, zap.String("userID", device.UserId))
+		return nil, internal_error.NewInternalServerError("Error trying to register device")
+	}
+
+	if err := dr.trimDevices(ctx, device.UserId); err != nil {
+		logger.Error("Error trying to drop old devices", err, zap.String("userID", device.UserId))
+		return nil, internal_error.NewInternalServerError("Error trying to register device")
+	}
+
+	registered := toDeviceEntity(deviceMongo)
+	return &registered, nil
+}
+
+func (dr *DeviceRepository) trimDevices(ctx context.Context, userId string) error {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(device_entity.MaxDevices).
+		SetProjection(bson.M{"_id": 1})
+	cursor, err := dr.Collection.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		return err
+	}
+
+	var stale []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &stale); err != nil || len(stale) == 0 {
+		return err
+	}
+
+	ids := make(bson.A, 0, len(stale))
+	for _, device := range stale {
+		ids = append(ids, device.Id)
+	}
+	_, err = dr.Collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// DeleteDevice only deletes devices the user registered
+func (dr *DeviceRepository) DeleteDevice(
+	ctx context.Context, userId, deviceId string) *internal_error.InternalError {
+	result, err := dr.Collection.DeleteOne(ctx, bson.M{"_id": deviceId, "user_id": userId})
+	if err != nil {
+		logger.Error("Error trying to delete device", err, zap.String("deviceID", deviceId))
+		return internal_error.NewInternalServerError("Error trying to delete device")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Device not found with this id = %s", deviceId))
+	}
+
+	return nil
+}
+
+func (dr *DeviceRepository) DeleteDeviceByToken(
+	ctx context.Context, platform device_entity.Platform, token string) *internal_error.InternalError {
+	if _, err := dr.Collection.DeleteOne(ctx, bson.M{"platform": platform, "token": token}); err != nil {
+		logger.Error("Error trying to delete device by token", err, zap.String("platform", string(platform)))
+		return internal_error.NewInternalServerError("Error trying to delete device")
+	}
+
+	return nil
+}
+
+// FindDevicesByUserId lists the most recently registered devices first
+func (dr *DeviceRepository) FindDevicesByUserId(
+	ctx context.Context, userId string) ([]device_entity.Device, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := dr.Collection.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find devices", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find devices")
+	}
+	defer cursor.Close(ctx)
+
+	var devicesMongo []DeviceEntityMongo
+	if err := cursor.All(ctx, &devicesMongo); err != nil {
+		logger.Error("Error trying to decode devices", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find devices")
+	}
+
+	devices := make([]device_entity.Device, 0, len(devicesMongo))
+	for _, deviceMongo := range devicesMongo {
+		devices = append(devices, toDeviceEntity(deviceMongo))
+	}
+
+	return devices, nil
+}
+
+func toDeviceEntity(deviceMongo DeviceEntityMongo) device_entity.Device {
+	return device_entity.Device{
+		Id:        deviceMongo.Id,
+		UserId:    deviceMongo.UserId,
+		Platform:  deviceMongo.Platform,
+		Token:     deviceMongo.Token,
+		Timestamp: time.Unix(deviceMongo.Timestamp, 0),
+	}
+}
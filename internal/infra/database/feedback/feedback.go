@@ -0,0 +1,158 @@
+package feedback
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/feedback_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// FeedbackEntityMongo is keyed by auction, so an auction can't be rated
+// twice
+type FeedbackEntityMongo struct {
+	AuctionId string `bson:"_id"`
+	SellerId  string `bson:"seller_id"`
+	BuyerId   string `bson:"buyer_id"`
+	Rating    int    `bson:"rating"`
+	Comment   string `bson:"comment,omitempty"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+type FeedbackRepository struct {
+	Collection     *mongo.Collection
+	userCollection *mongo.Collection
+}
+
+func NewFeedbackRepository(database *mongo.Database) *FeedbackRepository {
+	repo := &FeedbackRepository{
+		Collection:     database.Collection("feedback"),
+		userCollection: database.Collection("users"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the feedback collection
+func (fr *FeedbackRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, fr.Collection.Database(), "feedback"); err != nil {
+		logger.Error("Error trying to create feedback indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create feedback indexes")
+	}
+
+	return nil
+}
+
+func (fr *FeedbackRepository) CreateFeedback(
+	ctx context.Context, feedback *feedback_entity.Feedback) *internal_error.InternalError {
+	feedbackMongo := &FeedbackEntityMongo{
+		AuctionId: feedback.AuctionId,
+		SellerId:  feedback.SellerId,
+		BuyerId:   feedback.BuyerId,
+		Rating:    feedback.Rating,
+		Comment:   feedback.Comment,
+		Timestamp: feedback.Timestamp.Unix(),
+	}
+
+	if _, err := fr.Collection.InsertOne(ctx, feedbackMongo); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError("Feedback was already left for this auction")
+		}
+
+		logger.Error("Error trying to insert feedback", err, zap.String("auctionID", feedback.AuctionId))
+		return internal_error.NewInternalServerError("Error trying to insert feedback")
+	}
+
+	// The feedback is stored either way, the next one fixes the reputation
+	if err := fr.refreshReputation(ctx, feedback.SellerId); err != nil {
+		logger.Error("Error trying to refresh seller reputation", err, zap.String("sellerID", feedback.SellerId))
+	}
+
+	return nil
+}
+
+// refreshReputation recomputes the seller's reputation from all their
+// feedback and merges it into their user document, sellers without one are
+// skipped
+func (fr *FeedbackRepository) refreshReputation(ctx context.Context, sellerId string) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"seller_id": sellerId}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$seller_id",
+			"score": bson.M{"$avg": "$rating"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"reputation": bson.M{
+				"score": bson.M{"$round": bson.A{"$score", 2}},
+				"count": "$count",
+			},
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           fr.userCollection.Name(),
+			"whenMatched":    "merge",
+			"whenNotMatched": "discard",
+		}}},
+	}
+
+	cursor, err := fr.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+func (fr *FeedbackRepository) FindFeedbackBySellerId(
+	ctx context.Context,
+	sellerId string,
+	skip, limit int64) ([]feedback_entity.Feedback, int64, *internal_error.InternalError) {
+	filter := bson.M{"seller_id": sellerId}
+
+	total, err := fr.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error("Error counting seller feedback", err)
+		return nil, 0, internal_error.NewInternalServerError("Error finding seller feedback")
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	cursor, err := fr.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error finding seller feedback", err)
+		return nil, 0, internal_error.NewInternalServerError("Error finding seller feedback")
+	}
+	defer cursor.Close(ctx)
+
+	var feedbackMongo []FeedbackEntityMongo
+	if err := cursor.All(ctx, &feedbackMongo); err != nil {
+		logger.Error("Error decoding seller feedback", err)
+		return nil, 0, internal_error.NewInternalServerError("Error decoding seller feedback")
+	}
+
+	feedback := make([]feedback_entity.Feedback, 0, len(feedbackMongo))
+	for _, value := range feedbackMongo {
+		feedback = append(feedback, feedback_entity.Feedback{
+			AuctionId: value.AuctionId,
+			SellerId:  value.SellerId,
+			BuyerId:   value.BuyerId,
+			Rating:    value.Rating,
+			Comment:   value.Comment,
+			Timestamp: time.Unix(value.Timestamp, 0),
+		})
+	}
+
+	return feedback, total, nil
+}
@@ -0,0 +1,182 @@
+package history
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// AuctionEventMongo is an entry of the append-only auction_events
+// collection, its timestamp is in unix milliseconds so the changes made in
+// the same second keep their order
+type AuctionEventMongo struct {
+	Id        string                   `bson:"_id"`
+	AuctionId string                   `bson:"auction_id"`
+	Type      history_entity.EventType `bson:"type"`
+	Timestamp int64                    `bson:"timestamp"`
+	Auction   *AuctionSnapshotMongo    `bson:"auction,omitempty"`
+	Bid       *BidSnapshotMongo        `bson:"bid,omitempty"`
+}
+
+type AuctionSnapshotMongo struct {
+	SellerId           string                       `bson:"seller_id"`
+	ProductName        string                       `bson:"product_name"`
+	Category           string                       `bson:"category"`
+	Type               auction_entity.AuctionType   `bson:"type"`
+	Status             auction_entity.AuctionStatus `bson:"status"`
+	StartTime          int64                        `bson:"start_time"`
+	EndTime            int64                        `bson:"end_time"`
+	ReservePrice       float64                      `bson:"reserve_price,omitempty"`
+	BuyNowPrice        float64                      `bson:"buy_now_price,omitempty"`
+	DutchPrice         float64                      `bson:"dutch_price,omitempty"`
+	WinnerUserId       string                       `bson:"winner_user_id,omitempty"`
+	WinningAmount      float64                      `bson:"winning_amount,omitempty"`
+	CancellationReason string                       `bson:"cancellation_reason,omitempty"`
+}
+
+type BidSnapshotMongo struct {
+	BidId  string  `bson:"bid_id"`
+	UserId string  `bson:"user_id"`
+	Amount float64 `bson:"amount"`
+}
+
+// HistoryRepository keeps every auction's changes and bids as a log that is
+// only ever appended to, so past states can be rebuilt when a sale is
+// disputed
+type HistoryRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewHistoryRepository(database *mongo.Database) *HistoryRepository {
+	repo := &HistoryRepository{
+		Collection: database.Collection("auction_events"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the auction_events collection
+func (hr *HistoryRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, hr.Collection.Database(), "auction_events"); err != nil {
+		logger.Error("Error trying to create auction event indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create auction event indexes")
+	}
+
+	return nil
+}
+
+// AppendEvent writes through ctx, so the event commits along with the
+// transaction ctx carries. It is meant to be set as the auction and bid
+// repositories' event log
+func (hr *HistoryRepository) AppendEvent(ctx context.Context, event history_entity.AuctionEvent) error {
+	eventMongo := &AuctionEventMongo{
+		Id:        event.Id,
+		AuctionId: event.AuctionId,
+		Type:      event.Type,
+		Timestamp: event.Timestamp.UnixMilli(),
+	}
+	if event.Auction != nil {
+		eventMongo.Auction = &AuctionSnapshotMongo{
+			SellerId:           event.Auction.SellerId,
+			ProductName:        event.Auction.ProductName,
+			Category:           event.Auction.Category,
+			Type:               event.Auction.Type,
+			Status:             event.Auction.Status,
+			StartTime:          event.Auction.StartTime.Unix(),
+			EndTime:            event.Auction.EndTime.Unix(),
+			ReservePrice:       event.Auction.ReservePrice,
+			BuyNowPrice:        event.Auction.BuyNowPrice,
+			DutchPrice:         event.Auction.DutchPrice,
+			WinnerUserId:       event.Auction.WinnerUserId,
+			WinningAmount:      event.Auction.WinningAmount,
+			CancellationReason: event.Auction.CancellationReason,
+		}
+	}
+	if event.Bid != nil {
+		eventMongo.Bid = &BidSnapshotMongo{
+			BidId:  event.Bid.BidId,
+			UserId: event.Bid.UserId,
+			Amount: event.Bid.Amount,
+		}
+	}
+
+	if _, err := hr.Collection.InsertOne(ctx, eventMongo); err != nil {
+		logger.Error("Error trying to append auction event", err,
+			zap.String("auctionID", event.AuctionId), zap.String("type", string(event.Type)))
+		return err
+	}
+
+	return nil
+}
+
+// ReplayAuction reads the auction's whole event log, the replay rebuilds
+// its state at any point in time
+func (hr *HistoryRepository) ReplayAuction(
+	ctx context.Context, auctionId string) (*history_entity.AuctionReplay, *internal_error.InternalError) {
+	replay := &history_entity.AuctionReplay{AuctionId: auctionId}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	err := mongodb.Stream(ctx, hr.Collection, bson.M{"auction_id": auctionId},
+		func(eventMongo AuctionEventMongo) error {
+			replay.Events = append(replay.Events, toAuctionEvent(eventMongo))
+			return nil
+		}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find auction events", err, zap.String("auctionID", auctionId))
+		return nil, internal_error.NewInternalServerError("Error trying to replay auction")
+	}
+
+	if len(replay.Events) == 0 {
+		return nil, internal_error.NewNotFoundError("No events recorded for this auction")
+	}
+
+	return replay, nil
+}
+
+func toAuctionEvent(eventMongo AuctionEventMongo) history_entity.AuctionEvent {
+	event := history_entity.AuctionEvent{
+		Id:        eventMongo.Id,
+		AuctionId: eventMongo.AuctionId,
+		Type:      eventMongo.Type,
+		Timestamp: time.UnixMilli(eventMongo.Timestamp),
+	}
+	if eventMongo.Auction != nil {
+		event.Auction = &history_entity.AuctionSnapshot{
+			SellerId:           eventMongo.Auction.SellerId,
+			ProductName:        eventMongo.Auction.ProductName,
+			Category:           eventMongo.Auction.Category,
+			Type:               eventMongo.Auction.Type,
+			Status:             eventMongo.Auction.Status,
+			StartTime:          time.Unix(eventMongo.Auction.StartTime, 0),
+			EndTime:            time.Unix(eventMongo.Auction.EndTime, 0),
+			ReservePrice:       eventMongo.Auction.ReservePrice,
+			BuyNowPrice:        eventMongo.Auction.BuyNowPrice,
+			DutchPrice:         eventMongo.Auction.DutchPrice,
+			WinnerUserId:       eventMongo.Auction.WinnerUserId,
+			WinningAmount:      eventMongo.Auction.WinningAmount,
+			CancellationReason: eventMongo.Auction.CancellationReason,
+		}
+	}
+	if eventMongo.Bid != nil {
+		event.Bid = &history_entity.BidSnapshot{
+			BidId:  eventMongo.Bid.BidId,
+			UserId: eventMongo.Bid.UserId,
+			Amount: eventMongo.Bid.Amount,
+		}
+	}
+
+	return event
+}
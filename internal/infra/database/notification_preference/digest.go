@@ -0,0 +1,126 @@
+package notification_preference
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/notification_entity"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// SetDigestSender replaces where digests go, they are only logged until a
+// sender is set
+func (nr *NotificationRepository) SetDigestSender(sender notification_entity.DigestSender) {
+	nr.senderMutex.Lock()
+	defer nr.senderMutex.Unlock()
+
+	nr.sender = sender
+}
+
+func (nr *NotificationRepository) startDigester() {
+	defer close(nr.digestsDone)
+
+	ticker := time.NewTicker(nr.digestEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			nr.sendDueDigests(time.Now())
+		case <-nr.digesterCtx.Done():
+			return
+		}
+	}
+}
+
+// sendDueDigests groups the items that came due by user. A user's items are
+// claimed by deleting them before their digest is sent, so replicas running
+// side by side don't send it twice
+func (nr *NotificationRepository) sendDueDigests(now time.Time) {
+	ctx, cancel := context.WithTimeout(nr.digesterCtx, nr.digestEvery)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"due_at": bson.M{"$lte": now.Unix()}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$user_id",
+			"items": bson.M{"$push": "$$ROOT"},
+		}}},
+	}
+	cursor, err := nr.digestItemCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find due digests", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group struct {
+			UserId string                  `bson:"_id"`
+			Items  []DigestItemEntityMongo `bson:"items"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			logger.Error("Error trying to decode digest", err)
+			continue
+		}
+
+		ids := make(bson.A, 0, len(group.Items))
+		for _, item := range group.Items {
+			ids = append(ids, item.Id)
+		}
+		result, err := nr.digestItemCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			logger.Error("Error trying to claim digest", err, zap.String("userID", group.UserId))
+			continue
+		}
+		if result.DeletedCount < int64(len(ids)) {
+			// Another replica claimed it first
+			continue
+		}
+
+		digest := notification_entity.Digest{UserId: group.UserId}
+		for _, item := range group.Items {
+			digest.Items = append(digest.Items, notification_entity.DigestItem{
+				Id:        item.Id,
+				UserId:    item.UserId,
+				Event:     item.Event,
+				Subject:   item.Subject,
+				AuctionId: item.AuctionId,
+				Timestamp: time.Unix(item.Timestamp, 0),
+				DueAt:     time.Unix(item.DueAt, 0),
+			})
+		}
+		nr.sendDigest(ctx, digest)
+	}
+
+	if err := cursor.Err(); err != nil {
+		logger.Error("Error trying to send due digests", err)
+	}
+}
+
+// A failing sender must not keep the other users from getting their digest
+func (nr *NotificationRepository) sendDigest(ctx context.Context, digest notification_entity.Digest) {
+	nr.senderMutex.Lock()
+	sender := nr.sender
+	nr.senderMutex.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Digest sender panicked", fmt.Errorf("%v", r), zap.String("userID", digest.UserId))
+		}
+	}()
+
+	sender.SendDigest(ctx, digest)
+}
+
+type logDigestSender struct{}
+
+func (logDigestSender) SendDigest(ctx context.Context, digest notification_entity.Digest) {
+	logger.Info("Digest came due",
+		zap.String("userID", digest.UserId), zap.Int("items", len(digest.Items)))
+}
@@ -0,0 +1,158 @@
+package notification_preference
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/notification_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// PreferencesEntityMongo is keyed by the user's id
+type PreferencesEntityMongo struct {
+	UserId        string                          `bson:"_id"`
+	MutedChannels []notification_entity.Channel   `bson:"muted_channels,omitempty"`
+	MutedEvents   []notification_entity.EventType `bson:"muted_events,omitempty"`
+	Digest        string                          `bson:"digest"`
+	UpdatedAt     int64                           `bson:"updated_at"`
+}
+
+type DigestItemEntityMongo struct {
+	Id        string                        `bson:"_id"`
+	UserId    string                        `bson:"user_id"`
+	Event     notification_entity.EventType `bson:"event"`
+	Subject   string                        `bson:"subject"`
+	AuctionId string                        `bson:"auction_id,omitempty"`
+	Timestamp int64                         `bson:"timestamp"`
+	DueAt     int64                         `bson:"due_at"`
+}
+
+type NotificationRepository struct {
+	Collection           *mongo.Collection
+	digestItemCollection *mongo.Collection
+
+	digestEvery time.Duration
+	sender      notification_entity.DigestSender
+	senderMutex *sync.Mutex
+	digesterCtx context.Context
+	stopDigests context.CancelFunc
+	digestsDone chan struct{}
+}
+
+func NewNotificationRepository(database *mongo.Database) *NotificationRepository {
+	digesterCtx, stopDigests := context.WithCancel(context.Background())
+
+	repo := &NotificationRepository{
+		Collection:           database.Collection("notification_preferences"),
+		digestItemCollection: database.Collection("notification_digest_items"),
+		digestEvery:          getDigestInterval(),
+		sender:               logDigestSender{},
+		senderMutex:          &sync.Mutex{},
+		digesterCtx:          digesterCtx,
+		stopDigests:          stopDigests,
+		digestsDone:          make(chan struct{}),
+	}
+
+	go repo.startDigester()
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the notification_digest_items
+// collection, preferences are only looked up by id
+func (nr *NotificationRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, nr.Collection.Database(), "notification_digest_items"); err != nil {
+		logger.Error("Error trying to create notification indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create notification indexes")
+	}
+
+	return nil
+}
+
+// Close stops sending digests, waiting for the one being sent
+func (nr *NotificationRepository) Close() {
+	nr.stopDigests()
+	<-nr.digestsDone
+}
+
+func (nr *NotificationRepository) FindPreferences(
+	ctx context.Context, userId string) (*notification_entity.Preferences, *internal_error.InternalError) {
+	var preferencesMongo PreferencesEntityMongo
+	if err := nr.Collection.FindOne(ctx, bson.M{"_id": userId}).Decode(&preferencesMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return notification_entity.DefaultPreferences(userId), nil
+		}
+
+		logger.Error("Error trying to find notification preferences", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find notification preferences")
+	}
+
+	return &notification_entity.Preferences{
+		UserId:        preferencesMongo.UserId,
+		MutedChannels: preferencesMongo.MutedChannels,
+		MutedEvents:   preferencesMongo.MutedEvents,
+		Digest:        notification_entity.DigestFrequency(preferencesMongo.Digest),
+		UpdatedAt:     time.Unix(preferencesMongo.UpdatedAt, 0),
+	}, nil
+}
+
+func (nr *NotificationRepository) SavePreferences(
+	ctx context.Context, preferences *notification_entity.Preferences) *internal_error.InternalError {
+	preferencesMongo := &PreferencesEntityMongo{
+		UserId:        preferences.UserId,
+		MutedChannels: preferences.MutedChannels,
+		MutedEvents:   preferences.MutedEvents,
+		Digest:        string(preferences.Digest),
+		UpdatedAt:     preferences.UpdatedAt.Unix(),
+	}
+
+	replaceOptions := options.Replace().SetUpsert(true)
+	if _, err := nr.Collection.ReplaceOne(ctx, bson.M{"_id": preferences.UserId}, preferencesMongo, replaceOptions); err != nil {
+		logger.Error("Error trying to save notification preferences", err, zap.String("userID", preferences.UserId))
+		return internal_error.NewInternalServerError("Error trying to save notification preferences")
+	}
+
+	return nil
+}
+
+func (nr *NotificationRepository) AddDigestItem(
+	ctx context.Context, item *notification_entity.DigestItem) *internal_error.InternalError {
+	itemMongo := &DigestItemEntityMongo{
+		Id:        item.Id,
+		UserId:    item.UserId,
+		Event:     item.Event,
+		Subject:   item.Subject,
+		AuctionId: item.AuctionId,
+		Timestamp: item.Timestamp.Unix(),
+		DueAt:     item.DueAt.Unix(),
+	}
+
+	if _, err := nr.digestItemCollection.InsertOne(ctx, itemMongo); err != nil {
+		logger.Error("Error trying to insert digest item", err, zap.String("userID", item.UserId))
+		return internal_error.NewInternalServerError("Error trying to insert digest item")
+	}
+
+	return nil
+}
+
+func getDigestInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("NOTIFICATION_DIGEST_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return time.Minute
+	}
+
+	return interval
+}
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"auction_go/configuration/broker"
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/events"
+	"auction_go/internal/internal_error"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Status of an outbox entry
+const (
+	Pending = "pending"
+	Sent    = "sent"
+)
+
+// brokerDestination names the message broker among the destinations
+const brokerDestination = "broker"
+
+// OutboxEntryMongo is an event waiting to be published, or published
+// already and kept until retention passed. Delivered lists the destinations
+// that took it, so a retry skips them, and ClaimedUntil keeps other
+// replicas off it while one publishes it
+type OutboxEntryMongo struct {
+	Id           string   `bson:"_id"`
+	Type         string   `bson:"type"`
+	Key          string   `bson:"key"`
+	Payload      string   `bson:"payload"`
+	Status       string   `bson:"status"`
+	Timestamp    int64    `bson:"timestamp"`
+	Delivered    []string `bson:"delivered,omitempty"`
+	Attempts     int      `bson:"attempts"`
+	LastError    string   `bson:"last_error,omitempty"`
+	ClaimedUntil int64    `bson:"claimed_until,omitempty"`
+	SentAt       int64    `bson:"sent_at,omitempty"`
+}
+
+// Destination takes the entries the relay publishes, an error leaves the
+// entry pending for the next pass
+type Destination interface {
+	Publish(ctx context.Context, message broker.Message) error
+}
+
+// OutboxRepository keeps the events published outside the process in the
+// outbox collection, written in the same transaction as the change they
+// describe, and relays them to the message broker and the other
+// destinations. Events wait there while a destination is down instead of
+// being lost
+type OutboxRepository struct {
+	Collection *mongo.Collection
+
+	publisher         broker.Publisher
+	destinations      map[string]Destination
+	destinationsMutex *sync.RWMutex
+	relayEvery        time.Duration
+	retention         time.Duration
+	relayCtx          context.Context
+	stopRelay         context.CancelFunc
+	relayDone         chan struct{}
+}
+
+// NewOutboxRepository starts relaying the outbox to the publisher, when
+// there is one, and to the destinations added later on. Close stops it
+func NewOutboxRepository(database *mongo.Database, publisher broker.Publisher) *OutboxRepository {
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+
+	destinations := make(map[string]Destination)
+	if publisher != nil {
+		destinations[brokerDestination] = publisher
+	}
+
+	repo := &OutboxRepository{
+		Collection:        database.Collection("outbox"),
+		publisher:         publisher,
+		destinations:      destinations,
+		destinationsMutex: &sync.RWMutex{},
+		relayEvery:        getDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+		retention:         getDuration("OUTBOX_RETENTION", 24*time.Hour),
+		relayCtx:          relayCtx,
+		stopRelay:         stopRelay,
+		relayDone:         make(chan struct{}),
+	}
+
+	go repo.startRelay()
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the outbox collection
+func (ob *OutboxRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, ob.Collection.Database(), "outbox"); err != nil {
+		logger.Error("Error trying to create outbox indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create outbox indexes")
+	}
+
+	return nil
+}
+
+// Close stops relaying, waiting for the entry being published, and
+// disconnects from the broker. Entries still pending are published once the
+// service is back
+func (ob *OutboxRepository) Close() {
+	ob.stopRelay()
+	<-ob.relayDone
+
+	if ob.publisher != nil {
+		if err := ob.publisher.Close(); err != nil {
+			logger.Error("Error trying to disconnect from the message broker", err)
+		}
+	}
+}
+
+// AddDestination has the relay publish every entry to destination as well.
+// Entries still pending are published to it too, even those written
+// before it was added
+func (ob *OutboxRepository) AddDestination(name string, destination Destination) {
+	ob.destinationsMutex.Lock()
+	defer ob.destinationsMutex.Unlock()
+
+	ob.destinations[name] = destination
+}
+
+// AddAuctionCreated is meant to be set as the auction repository's outbox
+func (ob *OutboxRepository) AddAuctionCreated(ctx context.Context, auction auction_entity.Auction) error {
+	return ob.add(ctx, events.AuctionCreated{Auction: auction})
+}
+
+// AddAuctionClosed is meant to be set as the auction repository's outbox
+func (ob *OutboxRepository) AddAuctionClosed(ctx context.Context, auctionId, winnerUserId string) error {
+	return ob.add(ctx, events.AuctionClosed{
+		AuctionId:    auctionId,
+		WinnerUserId: winnerUserId,
+		Timestamp:    time.Now(),
+	})
+}
+
+// AddBidPlaced is meant to be set as the bid repository's outbox
+func (ob *OutboxRepository) AddBidPlaced(ctx context.Context, bid bid_entity.Bid) error {
+	return ob.add(ctx, events.BidPlaced{Bid: bid})
+}
+
+// add writes the entry through ctx, so it commits along with the
+// transaction ctx carries
+func (ob *OutboxRepository) add(ctx context.Context, event events.Event) error {
+	message, ok := events.NewMessage(event)
+	if !ok {
+		return nil
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		logger.Error("Error trying to encode outbox entry", err, zap.String("type", message.Type))
+		return err
+	}
+
+	entry := &OutboxEntryMongo{
+		Id:        message.Id,
+		Type:      message.Type,
+		Key:       message.Subject,
+		Payload:   string(payload),
+		Status:    Pending,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if _, err := ob.Collection.InsertOne(ctx, entry); err != nil {
+		logger.Error("Error trying to insert outbox entry", err,
+			zap.String("type", message.Type), zap.String("auctionID", message.Subject))
+		return err
+	}
+
+	return nil
+}
+
+func getDuration(name string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+
+	return duration
+}
@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"auction_go/configuration/broker"
+	"auction_go/configuration/logger"
+	"context"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	// relayBatchSize bounds how many entries a relay pass publishes
+	relayBatchSize = 100
+
+	// claimLease is how long an entry stays claimed by the replica
+	// publishing it, long enough for the broker to time out first
+	claimLease = 30 * time.Second
+)
+
+func (ob *OutboxRepository) startRelay() {
+	defer close(ob.relayDone)
+
+	ticker := time.NewTicker(ob.relayEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ob.relay(time.Now())
+		case <-ob.relayCtx.Done():
+			return
+		}
+	}
+}
+
+// relay publishes the pending entries oldest first, and stops at the first
+// one a destination didn't take so the others keep their order. Consumers
+// may still get an entry twice, when a replica crashed between publishing
+// it and recording it was delivered
+func (ob *OutboxRepository) relay(now time.Time) {
+	ctx, cancel := context.WithTimeout(ob.relayCtx, claimLease)
+	defer cancel()
+
+	ob.purgeSent(ctx, now)
+
+	filter := bson.M{
+		"status": Pending,
+		"$or": bson.A{
+			bson.M{"claimed_until": bson.M{"$exists": false}},
+			bson.M{"claimed_until": bson.M{"$lt": now.UnixMilli()}},
+		},
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(relayBatchSize)
+	cursor, err := ob.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find pending outbox entries", err)
+		return
+	}
+
+	var entries []OutboxEntryMongo
+	if err := cursor.All(ctx, &entries); err != nil {
+		logger.Error("Error trying to decode outbox entries", err)
+		return
+	}
+
+	for _, entry := range entries {
+		claimed, err := ob.claim(ctx, entry.Id, now)
+		if err != nil || !claimed {
+			continue
+		}
+
+		if err := ob.publish(ctx, entry); err != nil {
+			ob.release(ctx, entry.Id, err)
+			return
+		}
+
+		if _, err := ob.Collection.UpdateOne(ctx, bson.M{"_id": entry.Id}, bson.M{
+			"$set":   bson.M{"status": Sent, "sent_at": time.Now().Unix()},
+			"$unset": bson.M{"claimed_until": ""},
+		}); err != nil {
+			logger.Error("Error trying to mark outbox entry sent", err, zap.String("entryID", entry.Id))
+		}
+	}
+}
+
+func (ob *OutboxRepository) claim(ctx context.Context, id string, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":    id,
+		"status": Pending,
+		"$or": bson.A{
+			bson.M{"claimed_until": bson.M{"$exists": false}},
+			bson.M{"claimed_until": bson.M{"$lt": now.UnixMilli()}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"claimed_until": now.Add(claimLease).UnixMilli()}}
+
+	result, err := ob.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to claim outbox entry", err, zap.String("entryID", id))
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// release hands the entry back for the next pass
+func (ob *OutboxRepository) release(ctx context.Context, id string, publishErr error) {
+	if _, err := ob.Collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$inc":   bson.M{"attempts": 1},
+		"$set":   bson.M{"last_error": publishErr.Error()},
+		"$unset": bson.M{"claimed_until": ""},
+	}); err != nil {
+		logger.Error("Error trying to release outbox entry", err, zap.String("entryID", id))
+	}
+}
+
+// publish hands the entry to every destination it wasn't delivered to yet,
+// recording each one that took it
+func (ob *OutboxRepository) publish(ctx context.Context, entry OutboxEntryMongo) error {
+	message := broker.Message{
+		Id:      entry.Id,
+		Type:    entry.Type,
+		Key:     entry.Key,
+		Payload: []byte(entry.Payload),
+	}
+
+	ob.destinationsMutex.RLock()
+	destinations := make(map[string]Destination, len(ob.destinations))
+	for name, destination := range ob.destinations {
+		destinations[name] = destination
+	}
+	ob.destinationsMutex.RUnlock()
+
+	for name, destination := range destinations {
+		if slices.Contains(entry.Delivered, name) {
+			continue
+		}
+
+		if err := destination.Publish(ctx, message); err != nil {
+			logger.Error("Error trying to publish outbox entry", err, zap.String("entryID", entry.Id),
+				zap.String("type", entry.Type), zap.String("destination", name))
+			return err
+		}
+
+		if _, err := ob.Collection.UpdateOne(ctx, bson.M{"_id": entry.Id},
+			bson.M{"$addToSet": bson.M{"delivered": name}}); err != nil {
+			logger.Error("Error trying to record outbox entry delivery", err,
+				zap.String("entryID", entry.Id), zap.String("destination", name))
+		}
+	}
+
+	return nil
+}
+
+func (ob *OutboxRepository) purgeSent(ctx context.Context, now time.Time) {
+	filter := bson.M{"status": Sent, "sent_at": bson.M{"$lt": now.Add(-ob.retention).Unix()}}
+	if _, err := ob.Collection.DeleteMany(ctx, filter); err != nil {
+		logger.Error("Error trying to purge sent outbox entries", err)
+	}
+}
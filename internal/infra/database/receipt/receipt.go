@@ -0,0 +1,148 @@
+package receipt
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/receipt_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ReceiptEntityMongo is kept one per auction by a unique index on auction_id
+type ReceiptEntityMongo struct {
+	Id              string  `bson:"_id"`
+	AuctionId       string  `bson:"auction_id"`
+	UserId          string  `bson:"user_id"`
+	ProductName     string  `bson:"product_name"`
+	FinalPrice      float64 `bson:"final_price"`
+	Fees            float64 `bson:"fees"`
+	TotalAmount     float64 `bson:"total_amount"`
+	PaymentLink     string  `bson:"payment_link"`
+	PaymentDeadline int64   `bson:"payment_deadline"`
+	Timestamp       int64   `bson:"timestamp"`
+}
+
+type ReceiptRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewReceiptRepository(database *mongo.Database) *ReceiptRepository {
+	repo := &ReceiptRepository{
+		Collection: database.Collection("receipts"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the receipts collection
+func (rr *ReceiptRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, rr.Collection.Database(), "receipts"); err != nil {
+		logger.Error("Error trying to create receipt indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create receipt indexes")
+	}
+
+	return nil
+}
+
+// CreateReceipt upserts the receipt by its auction, a close handled twice
+// doesn't issue a second receipt
+func (rr *ReceiptRepository) CreateReceipt(
+	ctx context.Context,
+	receipt *receipt_entity.Receipt) (*receipt_entity.Receipt, *internal_error.InternalError) {
+	filter := bson.M{"auction_id": receipt.AuctionId}
+	update := bson.M{"$setOnInsert": toReceiptEntityMongo(receipt)}
+
+	var receiptMongo ReceiptEntityMongo
+	updateOptions := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := rr.Collection.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&receiptMongo); err != nil {
+		logger.Error("Error trying to create receipt", err, zap.String("auctionID", receipt.AuctionId))
+		return nil, internal_error.NewInternalServerError("Error trying to create receipt")
+	}
+
+	stored := toReceiptEntity(receiptMongo)
+	return &stored, nil
+}
+
+// FindReceiptsByUserId lists the most recent receipts first
+func (rr *ReceiptRepository) FindReceiptsByUserId(
+	ctx context.Context, userId string) ([]receipt_entity.Receipt, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := rr.Collection.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find receipts", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find receipts")
+	}
+	defer cursor.Close(ctx)
+
+	var receiptsMongo []ReceiptEntityMongo
+	if err := cursor.All(ctx, &receiptsMongo); err != nil {
+		logger.Error("Error trying to decode receipts", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find receipts")
+	}
+
+	receipts := make([]receipt_entity.Receipt, 0, len(receiptsMongo))
+	for _, receiptMongo := range receiptsMongo {
+		receipts = append(receipts, toReceiptEntity(receiptMongo))
+	}
+
+	return receipts, nil
+}
+
+func (rr *ReceiptRepository) FindReceiptById(
+	ctx context.Context, userId, id string) (*receipt_entity.Receipt, *internal_error.InternalError) {
+	var receiptMongo ReceiptEntityMongo
+	if err := rr.Collection.FindOne(ctx, bson.M{"_id": id, "user_id": userId}).Decode(&receiptMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Receipt not found with this id = %s", id))
+		}
+
+		logger.Error("Error trying to find receipt", err, zap.String("receiptID", id))
+		return nil, internal_error.NewInternalServerError("Error trying to find receipt")
+	}
+
+	receipt := toReceiptEntity(receiptMongo)
+	return &receipt, nil
+}
+
+func toReceiptEntityMongo(receipt *receipt_entity.Receipt) ReceiptEntityMongo {
+	return ReceiptEntityMongo{
+		Id:              receipt.Id,
+		AuctionId:       receipt.AuctionId,
+		UserId:          receipt.UserId,
+		ProductName:     receipt.ProductName,
+		FinalPrice:      receipt.FinalPrice,
+		Fees:            receipt.Fees,
+		TotalAmount:     receipt.TotalAmount,
+		PaymentLink:     receipt.PaymentLink,
+		PaymentDeadline: receipt.PaymentDeadline.Unix(),
+		Timestamp:       receipt.Timestamp.Unix(),
+	}
+}
+
+func toReceiptEntity(receiptMongo ReceiptEntityMongo) receipt_entity.Receipt {
+	return receipt_entity.Receipt{
+		Id:              receiptMongo.Id,
+		AuctionId:       receiptMongo.AuctionId,
+		UserId:          receiptMongo.UserId,
+		ProductName:     receiptMongo.ProductName,
+		FinalPrice:      receiptMongo.FinalPrice,
+		Fees:            receiptMongo.Fees,
+		TotalAmount:     receiptMongo.TotalAmount,
+		PaymentLink:     receiptMongo.PaymentLink,
+		PaymentDeadline: time.Unix(receiptMongo.PaymentDeadline, 0),
+		Timestamp:       time.Unix(receiptMongo.Timestamp, 0),
+	}
+}
@@ -0,0 +1,67 @@
+package receipt
+
+import (
+	"auction_go/internal/entity/receipt_entity"
+	"auction_go/internal/testhelpers"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReceiptRepositorySuite struct {
+	suite.Suite
+	database *mongo.Database
+	repo     *ReceiptRepository
+}
+
+func (suite *ReceiptRepositorySuite) SetupSuite() {
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
+	suite.repo = NewReceiptRepository(suite.database)
+}
+
+func (suite *ReceiptRepositorySuite) createReceipt(auctionId, userId string, amount float64) *receipt_entity.Receipt {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	receipt, err := suite.repo.CreateReceipt(ctx, receipt_entity.CreateReceipt(
+		auctionId, userId, "Guitar", amount, "/auctions/"+auctionId+"/checkout", time.Now()))
+	assert.Nil(suite.T(), err)
+	return receipt
+}
+
+func (suite *ReceiptRepositorySuite) TestAnAuctionIssuesOneReceipt() {
+	auctionId, userId := uuid.New().String(), uuid.New().String()
+
+	first := suite.createReceipt(auctionId, userId, 100)
+	again := suite.createReceipt(auctionId, userId, 150)
+	assert.Equal(suite.T(), first.Id, again.Id)
+	assert.Equal(suite.T(), 100.0, again.FinalPrice)
+
+	receipts, err := suite.repo.FindReceiptsByUserId(context.Background(), userId)
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), receipts, 1)
+	assert.Equal(suite.T(), first.PaymentLink, receipts[0].PaymentLink)
+	assert.Equal(suite.T(), first.PaymentDeadline.Unix(), receipts[0].PaymentDeadline.Unix())
+}
+
+func (suite *ReceiptRepositorySuite) TestReceiptsAreOnlyFoundByTheirWinner() {
+	userId := uuid.New().String()
+	receipt := suite.createReceipt(uuid.New().String(), userId, 100)
+
+	found, err := suite.repo.FindReceiptById(context.Background(), userId, receipt.Id)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), receipt.AuctionId, found.AuctionId)
+
+	_, err = suite.repo.FindReceiptById(context.Background(), uuid.New().String(), receipt.Id)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "not_found", err.Err)
+}
+
+func TestReceiptRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ReceiptRepositorySuite))
+}
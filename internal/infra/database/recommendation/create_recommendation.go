@@ -0,0 +1,68 @@
+package recommendation
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/recommendation_entity"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecommendedAuctionMongo struct {
+	AuctionId   string  `bson:"auction_id"`
+	ProductName string  `bson:"product_name"`
+	Category    string  `bson:"category"`
+	Score       float64 `bson:"score"`
+}
+
+type RecommendationEntityMongo struct {
+	UserId      string                    `bson:"_id"`
+	Auctions    []RecommendedAuctionMongo `bson:"auctions"`
+	GeneratedAt int64                     `bson:"generated_at"`
+}
+
+type RecommendationRepository struct {
+	Collection        *mongo.Collection
+	BidCollection     *mongo.Collection
+	AuctionCollection *mongo.Collection
+}
+
+func NewRecommendationRepository(database *mongo.Database) *RecommendationRepository {
+	return &RecommendationRepository{
+		Collection:        database.Collection("recommendations"),
+		BidCollection:     database.Collection("bids"),
+		AuctionCollection: database.Collection("auctions"),
+	}
+}
+
+func (rr *RecommendationRepository) UpsertRecommendation(
+	ctx context.Context,
+	recommendation *recommendation_entity.Recommendation) *internal_error.InternalError {
+	auctionsMongo := make([]RecommendedAuctionMongo, 0, len(recommendation.Auctions))
+	for _, auction := range recommendation.Auctions {
+		auctionsMongo = append(auctionsMongo, RecommendedAuctionMongo{
+			AuctionId:   auction.AuctionId,
+			ProductName: auction.ProductName,
+			Category:    auction.Category,
+			Score:       auction.Score,
+		})
+	}
+
+	recommendationMongo := &RecommendationEntityMongo{
+		UserId:      recommendation.UserId,
+		Auctions:    auctionsMongo,
+		GeneratedAt: recommendation.GeneratedAt.Unix(),
+	}
+
+	filter := bson.M{"_id": recommendation.UserId}
+	if _, err := rr.Collection.ReplaceOne(
+		ctx, filter, recommendationMongo, options.Replace().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to store user recommendations", err)
+		return internal_error.NewInternalServerError("Error trying to store user recommendations")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package recommendation
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/recommendation_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type categoryAffinityMongo struct {
+	Id struct {
+		UserId   string `bson:"user_id"`
+		Category string `bson:"category"`
+	} `bson:"_id"`
+	Score      float64  `bson:"score"`
+	AuctionIds []string `bson:"auction_ids"`
+}
+
+// FindCategoryAffinities scores every user/category pair by how many bids the
+// user placed on auctions of that category
+func (rr *RecommendationRepository) FindCategoryAffinities(
+	ctx context.Context) ([]recommendation_entity.CategoryAffinity, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "auctions",
+			"localField":   "auction_id",
+			"foreignField": "_id",
+			"as":           "auction",
+		}}},
+		{{Key: "$unwind", Value: "$auction"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"user_id": "$user_id", "category": "$auction.category"},
+			"score":       bson.M{"$sum": 1},
+			"auction_ids": bson.M{"$addToSet": "$auction_id"},
+		}}},
+	}
+
+	cursor, err := rr.BidCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to compute category affinities", err)
+		return nil, internal_error.NewInternalServerError("Error trying to compute category affinities")
+	}
+	defer cursor.Close(ctx)
+
+	var affinitiesMongo []categoryAffinityMongo
+	if err := cursor.All(ctx, &affinitiesMongo); err != nil {
+		logger.Error("Error trying to decode category affinities", err)
+		return nil, internal_error.NewInternalServerError("Error trying to compute category affinities")
+	}
+
+	var affinities []recommendation_entity.CategoryAffinity
+	for _, affinity := range affinitiesMongo {
+		affinities = append(affinities, recommendation_entity.CategoryAffinity{
+			UserId:     affinity.Id.UserId,
+			Category:   affinity.Id.Category,
+			Score:      affinity.Score,
+			AuctionIds: affinity.AuctionIds,
+		})
+	}
+
+	return affinities, nil
+}
+
+func (rr *RecommendationRepository) FindRecommendationByUserId(
+	ctx context.Context, userId string) (*recommendation_entity.Recommendation, *internal_error.InternalError) {
+	filter := bson.M{"_id": userId}
+
+	var recommendationMongo RecommendationEntityMongo
+	if err := rr.Collection.FindOne(ctx, filter).Decode(&recommendationMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Recommendations not found for user id = %s", userId))
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find recommendations for user id = %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find recommendations")
+	}
+
+	var auctions []recommendation_entity.RecommendedAuction
+	for _, auction := range recommendationMongo.Auctions {
+		auctions = append(auctions, recommendation_entity.RecommendedAuction{
+			AuctionId:   auction.AuctionId,
+			ProductName: auction.ProductName,
+			Category:    auction.Category,
+			Score:       auction.Score,
+		})
+	}
+
+	return &recommendation_entity.Recommendation{
+		UserId:      recommendationMongo.UserId,
+		Auctions:    auctions,
+		GeneratedAt: time.Unix(recommendationMongo.GeneratedAt, 0),
+	}, nil
+}
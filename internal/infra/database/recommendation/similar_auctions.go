@@ -0,0 +1,111 @@
+package recommendation
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/recommendation_entity"
+	"auction_go/internal/infra/database/auction"
+	"auction_go/internal/internal_error"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Shared keywords weigh twice as much as a close price in the similarity
+// score
+const (
+	keywordWeight = 2.0
+	priceWeight   = 1.0
+)
+
+// FindSimilarAuctions scores the active auctions of the category in one
+// aggregation. The keyword score is the share of the keywords found in the
+// candidate's product name, the price score drops from 1 at the reference
+// price to 0 at twice or none of it. Candidates scoring zero are left out
+func (rr *RecommendationRepository) FindSimilarAuctions(
+	ctx context.Context,
+	query recommendation_entity.SimilarAuctionQuery) ([]recommendation_entity.RecommendedAuction, *internal_error.InternalError) {
+	keywordScore := bson.M{"$literal": 0}
+	if len(query.Keywords) > 0 {
+		keywordScore = bson.M{"$divide": bson.A{
+			bson.M{"$size": bson.M{"$setIntersection": bson.A{
+				bson.M{"$split": bson.A{bson.M{"$toLower": "$product_name"}, " "}},
+				query.Keywords,
+			}}},
+			len(query.Keywords),
+		}}
+	}
+
+	priceScore := bson.M{"$literal": 0}
+	if query.ReferencePrice > 0 {
+		priceScore = bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{1, bson.M{"$divide": bson.A{
+			bson.M{"$abs": bson.M{"$subtract": bson.A{"$current_price", query.ReferencePrice}}},
+			query.ReferencePrice,
+		}}}}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"_id":      bson.M{"$ne": query.AuctionId},
+			"status":   auction_entity.Active,
+			"category": query.Category,
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         auction.CurrentPriceCollection,
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "current_price",
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"current_price": bson.M{"$ifNull": bson.A{bson.M{"$first": "$current_price.amount"}, 0}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"keyword_score": keywordScore,
+			"price_score":   priceScore,
+		}}},
+		{{Key: "$set", Value: bson.M{"score": bson.M{"$divide": bson.A{
+			bson.M{"$add": bson.A{
+				bson.M{"$multiply": bson.A{"$keyword_score", keywordWeight}},
+				bson.M{"$multiply": bson.A{"$price_score", priceWeight}},
+			}},
+			keywordWeight + priceWeight,
+		}}}}},
+		{{Key: "$match", Value: bson.M{"score": bson.M{"$gt": 0}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "end_time", Value: 1}}}},
+		{{Key: "$limit", Value: query.Limit}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"auction_id":   "$_id",
+			"product_name": 1,
+			"category":     1,
+			"score":        1,
+		}}},
+	}
+
+	cursor, err := rr.AuctionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find similar auctions", err, zap.String("auctionID", query.AuctionId))
+		return nil, internal_error.NewInternalServerError("Error trying to find similar auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var similarMongo []RecommendedAuctionMongo
+	if err := cursor.All(ctx, &similarMongo); err != nil {
+		logger.Error("Error trying to decode similar auctions", err, zap.String("auctionID", query.AuctionId))
+		return nil, internal_error.NewInternalServerError("Error trying to find similar auctions")
+	}
+
+	similar := make([]recommendation_entity.RecommendedAuction, 0, len(similarMongo))
+	for _, similarAuction := range similarMongo {
+		similar = append(similar, recommendation_entity.RecommendedAuction{
+			AuctionId:   similarAuction.AuctionId,
+			ProductName: similarAuction.ProductName,
+			Category:    similarAuction.Category,
+			Score:       similarAuction.Score,
+		})
+	}
+
+	return similar, nil
+}
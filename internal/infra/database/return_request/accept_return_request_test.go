@@ -0,0 +1,111 @@
+package return_request
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/testhelpers"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AcceptReturnRequestSuite struct {
+	suite.Suite
+	database *mongo.Database
+	repo     *ReturnRepository
+}
+
+func (suite *AcceptReturnRequestSuite) SetupSuite() {
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
+	suite.repo = NewReturnRepository(suite.database)
+}
+
+// createReturnRequest stores a delivered checkout and a return requested for it
+func (suite *AcceptReturnRequestSuite) createReturnRequest() (*return_entity.ReturnRequest, *checkout_entity.Checkout) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	checkout, err := checkout_entity.CreateCheckout(uuid.New().String(), uuid.New().String(), 100)
+	assert.Nil(suite.T(), err)
+	checkout.Status = checkout_entity.Delivered
+	_, errInsert := suite.repo.checkoutCollection.InsertOne(ctx, bson.M{
+		"_id": checkout.Id, "auction_id": checkout.AuctionId, "buyer_user_id": checkout.BuyerUserId,
+		"total_amount": checkout.TotalAmount, "status": checkout.Status})
+	assert.Nil(suite.T(), errInsert)
+
+	returnRequest, err := return_entity.CreateReturnRequest(
+		checkout.Id, checkout.BuyerUserId, return_entity.ReasonDamaged, "", nil)
+	assert.Nil(suite.T(), err)
+	_, err = suite.repo.CreateReturnRequest(ctx, returnRequest)
+	assert.Nil(suite.T(), err)
+
+	return returnRequest, checkout
+}
+
+func (suite *AcceptReturnRequestSuite) accept(
+	returnRequest *return_entity.ReturnRequest,
+	checkout *checkout_entity.Checkout) *internal_error.InternalError {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.Nil(suite.T(), returnRequest.Accept("Sorry about that"))
+	assert.Nil(suite.T(), checkout.MarkReturned(returnRequest.Id))
+
+	return suite.repo.AcceptReturnRequest(
+		ctx, returnRequest, return_entity.Requested, checkout)
+}
+
+func (suite *AcceptReturnRequestSuite) storedCheckout(id string) bson.M {
+	var checkout bson.M
+	err := suite.repo.checkoutCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&checkout)
+	assert.Nil(suite.T(), err)
+	return checkout
+}
+
+func (suite *AcceptReturnRequestSuite) TestAcceptingOwesTheBuyerARefund() {
+	returnRequest, checkout := suite.createReturnRequest()
+
+	err := suite.accept(returnRequest, checkout)
+	assert.Nil(suite.T(), err)
+
+	stored, errFind := suite.repo.FindReturnRequestById(context.Background(), returnRequest.Id)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), return_entity.Accepted, stored.Status)
+
+	storedCheckout := suite.storedCheckout(checkout.Id)
+	assert.EqualValues(suite.T(), checkout_entity.Returned, storedCheckout["status"])
+	refund := storedCheckout["refund"].(bson.M)
+	assert.Equal(suite.T(), returnRequest.Id, refund["return_request_id"])
+	assert.Equal(suite.T(), 100.0, refund["amount"])
+	assert.EqualValues(suite.T(), 1, storedCheckout["version"])
+	assert.Equal(suite.T(), int64(1), checkout.Version)
+}
+
+func (suite *AcceptReturnRequestSuite) TestNothingIsWrittenWhenTheCheckoutChanged() {
+	returnRequest, checkout := suite.createReturnRequest()
+
+	// Another write landed after the checkout was read, its status alone
+	// doesn't show it
+	_, errUpdate := suite.repo.checkoutCollection.UpdateOne(context.Background(),
+		bson.M{"_id": checkout.Id}, bson.M{"$inc": bson.M{"version": 1}})
+	assert.Nil(suite.T(), errUpdate)
+
+	err := suite.accept(returnRequest, checkout)
+	assert.NotNil(suite.T(), err)
+
+	stored, errFind := suite.repo.FindReturnRequestById(context.Background(), returnRequest.Id)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), return_entity.Requested, stored.Status)
+	assert.Nil(suite.T(), suite.storedCheckout(checkout.Id)["refund"])
+}
+
+func TestAcceptReturnRequestSuite(t *testing.T) {
+	suite.Run(t, new(AcceptReturnRequestSuite))
+}
@@ -0,0 +1,95 @@
+package return_request
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReturnRequestEntityMongo struct {
+	Id          string                     `bson:"_id"`
+	CheckoutId  string                     `bson:"checkout_id"`
+	BuyerUserId string                     `bson:"buyer_user_id"`
+	Reason      string                     `bson:"reason"`
+	Description string                     `bson:"description"`
+	PhotoUrls   []string                   `bson:"photo_urls"`
+	Status      return_entity.ReturnStatus `bson:"status"`
+	SellerNote  string                     `bson:"seller_note"`
+	Timestamp   int64                      `bson:"timestamp"`
+	ResolvedAt  int64                      `bson:"resolved_at,omitempty"`
+}
+
+type ReturnRepository struct {
+	Collection         *mongo.Collection
+	checkoutCollection *mongo.Collection
+}
+
+func NewReturnRepository(database *mongo.Database) *ReturnRepository {
+	return &ReturnRepository{
+		Collection:         database.Collection("return_requests"),
+		checkoutCollection: database.Collection("checkouts"),
+	}
+}
+
+// CreateReturnRequest keeps a single request per checkout, if one already
+// exists it is returned untouched
+func (rr *ReturnRepository) CreateReturnRequest(
+	ctx context.Context,
+	returnRequestEntity *return_entity.ReturnRequest) (*return_entity.ReturnRequest, *internal_error.InternalError) {
+	filter := bson.M{"checkout_id": returnRequestEntity.CheckoutId}
+	update := bson.M{"$setOnInsert": toReturnRequestEntityMongo(returnRequestEntity)}
+
+	if _, err := rr.Collection.UpdateOne(
+		ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to insert return request", err)
+		return nil, internal_error.NewInternalServerError("Error trying to insert return request")
+	}
+
+	return rr.FindReturnRequestByCheckoutId(ctx, returnRequestEntity.CheckoutId)
+}
+
+func toReturnRequestEntityMongo(returnRequestEntity *return_entity.ReturnRequest) *ReturnRequestEntityMongo {
+	returnRequestEntityMongo := &ReturnRequestEntityMongo{
+		Id:          returnRequestEntity.Id,
+		CheckoutId:  returnRequestEntity.CheckoutId,
+		BuyerUserId: returnRequestEntity.BuyerUserId,
+		Reason:      returnRequestEntity.Reason,
+		Description: returnRequestEntity.Description,
+		PhotoUrls:   returnRequestEntity.PhotoUrls,
+		Status:      returnRequestEntity.Status,
+		SellerNote:  returnRequestEntity.SellerNote,
+		Timestamp:   returnRequestEntity.Timestamp.Unix(),
+	}
+
+	if !returnRequestEntity.ResolvedAt.IsZero() {
+		returnRequestEntityMongo.ResolvedAt = returnRequestEntity.ResolvedAt.Unix()
+	}
+
+	return returnRequestEntityMongo
+}
+
+func toReturnRequestEntity(returnRequestEntityMongo ReturnRequestEntityMongo) *return_entity.ReturnRequest {
+	returnRequestEntity := &return_entity.ReturnRequest{
+		Id:          returnRequestEntityMongo.Id,
+		CheckoutId:  returnRequestEntityMongo.CheckoutId,
+		BuyerUserId: returnRequestEntityMongo.BuyerUserId,
+		Reason:      returnRequestEntityMongo.Reason,
+		Description: returnRequestEntityMongo.Description,
+		PhotoUrls:   returnRequestEntityMongo.PhotoUrls,
+		Status:      returnRequestEntityMongo.Status,
+		SellerNote:  returnRequestEntityMongo.SellerNote,
+		Timestamp:   time.Unix(returnRequestEntityMongo.Timestamp, 0),
+	}
+
+	if returnRequestEntityMongo.ResolvedAt != 0 {
+		returnRequestEntity.ResolvedAt = time.Unix(returnRequestEntityMongo.ResolvedAt, 0)
+	}
+
+	return returnRequestEntity
+}
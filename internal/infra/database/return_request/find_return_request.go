@@ -0,0 +1,42 @@
+package return_request
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (rr *ReturnRepository) FindReturnRequestById(
+	ctx context.Context, id string) (*return_entity.ReturnRequest, *internal_error.InternalError) {
+	return rr.findReturnRequest(ctx, bson.M{"_id": id},
+		fmt.Sprintf("Return request not found with this id = %s", id))
+}
+
+func (rr *ReturnRepository) FindReturnRequestByCheckoutId(
+	ctx context.Context, checkoutId string) (*return_entity.ReturnRequest, *internal_error.InternalError) {
+	return rr.findReturnRequest(ctx, bson.M{"checkout_id": checkoutId},
+		fmt.Sprintf("Return request not found for checkout id = %s", checkoutId))
+}
+
+func (rr *ReturnRepository) findReturnRequest(
+	ctx context.Context,
+	filter bson.M,
+	notFoundMessage string) (*return_entity.ReturnRequest, *internal_error.InternalError) {
+	var returnRequestEntityMongo ReturnRequestEntityMongo
+	if err := rr.Collection.FindOne(ctx, filter).Decode(&returnRequestEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(notFoundMessage)
+		}
+
+		logger.Error("Error trying to find return request", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find return request")
+	}
+
+	return toReturnRequestEntity(returnRequestEntityMongo), nil
+}
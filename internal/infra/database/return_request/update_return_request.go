@@ -0,0 +1,91 @@
+package return_request
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/infra/database/checkout"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+var (
+	errReturnRequestChanged = errors.New("return request was changed")
+	errCheckoutChanged      = errors.New("checkout was changed")
+)
+
+func (rr *ReturnRepository) UpdateReturnRequest(
+	ctx context.Context,
+	returnRequestEntity *return_entity.ReturnRequest,
+	expectedStatus return_entity.ReturnStatus) *internal_error.InternalError {
+	filter := bson.M{"_id": returnRequestEntity.Id, "status": expectedStatus}
+
+	result, err := rr.Collection.ReplaceOne(ctx, filter, toReturnRequestEntityMongo(returnRequestEntity))
+	if err != nil {
+		logger.Error("Error trying to update return request", err)
+		return internal_error.NewInternalServerError("Error trying to update return request")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewBadRequestError("Return request was changed by another request, please retry")
+	}
+
+	return nil
+}
+
+// AcceptReturnRequest writes the accepted request and the refund owed on its
+// checkout in one transaction, a failure leaves both as they were so the
+// seller may simply retry
+func (rr *ReturnRepository) AcceptReturnRequest(
+	ctx context.Context,
+	returnRequestEntity *return_entity.ReturnRequest,
+	expectedStatus return_entity.ReturnStatus,
+	checkoutEntity *checkout_entity.Checkout) *internal_error.InternalError {
+	err := mongodb.RunInTransaction(ctx, rr.Collection.Database(), func(ctx context.Context) error {
+		filter := bson.M{"_id": returnRequestEntity.Id, "status": expectedStatus}
+		result, err := rr.Collection.ReplaceOne(ctx, filter, toReturnRequestEntityMongo(returnRequestEntity))
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errReturnRequestChanged
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"status": checkoutEntity.Status,
+				"refund": checkout.ToRefundMongo(checkoutEntity.Refund),
+			},
+			"$inc": bson.M{"version": 1},
+		}
+		result, err = rr.checkoutCollection.UpdateOne(
+			ctx, checkout.VersionFilter(checkoutEntity.Id, checkoutEntity.Version), update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errCheckoutChanged
+		}
+
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, errReturnRequestChanged):
+		return internal_error.NewBadRequestError("Return request was changed by another request, please retry")
+	case errors.Is(err, errCheckoutChanged):
+		return internal_error.NewBadRequestError("Checkout was changed by another request, please retry")
+	case err != nil:
+		logger.Error("Error trying to accept return request", err,
+			zap.String("returnRequestId", returnRequestEntity.Id), zap.String("checkoutId", checkoutEntity.Id))
+		return internal_error.NewInternalServerError("Error trying to accept return request")
+	}
+
+	checkoutEntity.Version++
+	return nil
+}
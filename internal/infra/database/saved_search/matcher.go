@@ -0,0 +1,96 @@
+package saved_search
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/saved_search_entity"
+	"auction_go/internal/events"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// matchTimeout bounds matching a single auction
+const matchTimeout = 30 * time.Second
+
+// SetMatchNotifier replaces where saved search matches go, they are only
+// logged until a notifier is set
+func (sr *SavedSearchRepository) SetMatchNotifier(notifier saved_search_entity.MatchNotifier) {
+	sr.notifierMutex.Lock()
+	defer sr.notifierMutex.Unlock()
+
+	sr.notifier = notifier
+}
+
+// MatchAuction notifies the owners of the saved searches matching a new
+// auction, it is meant to be subscribed to the event bus. The filters
+// narrow the searches down in the database, the queries are checked here
+func (sr *SavedSearchRepository) MatchAuction(ctx context.Context, event events.AuctionCreated) {
+	auction := event.Auction
+
+	ctx, cancel := context.WithTimeout(ctx, matchTimeout)
+	defer cancel()
+
+	filter := bson.M{
+		"category":  bson.M{"$in": bson.A{nil, auction.Category}},
+		"condition": bson.M{"$in": bson.A{nil, auction.Condition}},
+		"user_id":   bson.M{"$ne": auction.SellerId},
+	}
+	cursor, err := sr.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error trying to find saved searches to match", err, zap.String("auctionID", auction.Id))
+		return
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var savedSearchMongo SavedSearchEntityMongo
+		if err := cursor.Decode(&savedSearchMongo); err != nil {
+			logger.Error("Error trying to decode saved search", err, zap.String("auctionID", auction.Id))
+			continue
+		}
+
+		savedSearch := toSavedSearchEntity(savedSearchMongo)
+		if !savedSearch.Matches(auction) {
+			continue
+		}
+
+		sr.notifyMatch(ctx, saved_search_entity.MatchEvent{
+			UserId:        savedSearch.UserId,
+			SavedSearchId: savedSearch.Id,
+			Name:          savedSearch.Name,
+			AuctionId:     auction.Id,
+			ProductName:   auction.ProductName,
+		})
+	}
+
+	if err := cursor.Err(); err != nil {
+		logger.Error("Error trying to match saved searches", err, zap.String("auctionID", auction.Id))
+	}
+}
+
+// A failing notifier must not stop the other users from being notified
+func (sr *SavedSearchRepository) notifyMatch(ctx context.Context, event saved_search_entity.MatchEvent) {
+	sr.notifierMutex.Lock()
+	notifier := sr.notifier
+	sr.notifierMutex.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Saved search notifier panicked", fmt.Errorf("%v", r), zap.String("auctionID", event.AuctionId))
+		}
+	}()
+
+	notifier.NotifySavedSearchMatch(ctx, event)
+}
+
+type logMatchNotifier struct{}
+
+func (logMatchNotifier) NotifySavedSearchMatch(ctx context.Context, event saved_search_entity.MatchEvent) {
+	logger.Info("New auction matches saved search",
+		zap.String("auctionID", event.AuctionId),
+		zap.String("savedSearchID", event.SavedSearchId),
+		zap.String("userID", event.UserId))
+}
@@ -0,0 +1,147 @@
+package saved_search
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/saved_search_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type SavedSearchEntityMongo struct {
+	Id        string                          `bson:"_id"`
+	UserId    string                          `bson:"user_id"`
+	Name      string                          `bson:"name"`
+	Query     string                          `bson:"query,omitempty"`
+	Queries   []string                        `bson:"queries,omitempty"`
+	Category  string                          `bson:"category,omitempty"`
+	Condition auction_entity.ProductCondition `bson:"condition,omitempty"`
+	Timestamp int64                           `bson:"timestamp"`
+}
+
+type SavedSearchRepository struct {
+	Collection *mongo.Collection
+
+	notifier      saved_search_entity.MatchNotifier
+	notifierMutex *sync.Mutex
+}
+
+func NewSavedSearchRepository(database *mongo.Database) *SavedSearchRepository {
+	repo := &SavedSearchRepository{
+		Collection:    database.Collection("saved_searches"),
+		notifier:      logMatchNotifier{},
+		notifierMutex: &sync.Mutex{},
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the saved_searches collection
+func (sr *SavedSearchRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, sr.Collection.Database(), "saved_searches"); err != nil {
+		logger.Error("Error trying to create saved search indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create saved search indexes")
+	}
+
+	return nil
+}
+
+func (sr *SavedSearchRepository) CreateSavedSearch(
+	ctx context.Context, savedSearch *saved_search_entity.SavedSearch) *internal_error.InternalError {
+	savedSearchMongo := &SavedSearchEntityMongo{
+		Id:        savedSearch.Id,
+		UserId:    savedSearch.UserId,
+		Name:      savedSearch.Name,
+		Query:     savedSearch.Query,
+		Queries:   savedSearch.Queries,
+		Category:  savedSearch.Category,
+		Condition: savedSearch.Condition,
+		Timestamp: savedSearch.Timestamp.Unix(),
+	}
+
+	if _, err := sr.Collection.InsertOne(ctx, savedSearchMongo); err != nil {
+		logger.Error("Error trying to insert saved search", err, zap.String("userID", savedSearch.UserId))
+		return internal_error.NewInternalServerError("Error trying to insert saved search")
+	}
+
+	return nil
+}
+
+// DeleteSavedSearch only deletes searches the user saved
+func (sr *SavedSearchRepository) DeleteSavedSearch(
+	ctx context.Context, userId, savedSearchId string) *internal_error.InternalError {
+	result, err := sr.Collection.DeleteOne(ctx, bson.M{"_id": savedSearchId, "user_id": userId})
+	if err != nil {
+		logger.Error("Error trying to delete saved search", err, zap.String("savedSearchID", savedSearchId))
+		return internal_error.NewInternalServerError("Error trying to delete saved search")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Saved search not found with this id = %s", savedSearchId))
+	}
+
+	return nil
+}
+
+// FindSavedSearchesByUserId lists the most recently saved searches first
+func (sr *SavedSearchRepository) FindSavedSearchesByUserId(
+	ctx context.Context, userId string) ([]saved_search_entity.SavedSearch, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := sr.Collection.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find saved searches", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find saved searches")
+	}
+	defer cursor.Close(ctx)
+
+	var savedSearchesMongo []SavedSearchEntityMongo
+	if err := cursor.All(ctx, &savedSearchesMongo); err != nil {
+		logger.Error("Error trying to decode saved searches", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find saved searches")
+	}
+
+	savedSearches := make([]saved_search_entity.SavedSearch, 0, len(savedSearchesMongo))
+	for _, savedSearchMongo := range savedSearchesMongo {
+		savedSearches = append(savedSearches, toSavedSearchEntity(savedSearchMongo))
+	}
+
+	return savedSearches, nil
+}
+
+func (sr *SavedSearchRepository) CountSavedSearchesByUserId(
+	ctx context.Context, userId string) (int64, *internal_error.InternalError) {
+	count, err := sr.Collection.CountDocuments(ctx, bson.M{"user_id": userId})
+	if err != nil {
+		logger.Error("Error trying to count saved searches", err, zap.String("userID", userId))
+		return 0, internal_error.NewInternalServerError("Error trying to count saved searches")
+	}
+
+	return count, nil
+}
+
+func toSavedSearchEntity(savedSearchMongo SavedSearchEntityMongo) saved_search_entity.SavedSearch {
+	return saved_search_entity.SavedSearch{
+		Id:        savedSearchMongo.Id,
+		UserId:    savedSearchMongo.UserId,
+		Name:      savedSearchMongo.Name,
+		Query:     savedSearchMongo.Query,
+		Queries:   savedSearchMongo.Queries,
+		Category:  savedSearchMongo.Category,
+		Condition: savedSearchMongo.Condition,
+		Timestamp: time.Unix(savedSearchMongo.Timestamp, 0),
+	}
+}
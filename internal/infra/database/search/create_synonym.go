@@ -0,0 +1,61 @@
+package search
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/search_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SynonymGroupEntityMongo struct {
+	Id        string   `bson:"_id"`
+	Terms     []string `bson:"terms"`
+	Timestamp int64    `bson:"timestamp"`
+}
+
+type SearchRepository struct {
+	SynonymCollection *mongo.Collection
+}
+
+func NewSearchRepository(database *mongo.Database) *SearchRepository {
+	return &SearchRepository{
+		SynonymCollection: database.Collection("search_synonyms"),
+	}
+}
+
+func (sr *SearchRepository) CreateSynonymGroup(
+	ctx context.Context,
+	synonymGroup *search_entity.SynonymGroup) *internal_error.InternalError {
+	synonymGroupMongo := &SynonymGroupEntityMongo{
+		Id:        synonymGroup.Id,
+		Terms:     synonymGroup.Terms,
+		Timestamp: synonymGroup.Timestamp.Unix(),
+	}
+
+	if _, err := sr.SynonymCollection.InsertOne(ctx, synonymGroupMongo); err != nil {
+		logger.Error("Error trying to insert synonym group", err)
+		return internal_error.NewInternalServerError("Error trying to insert synonym group")
+	}
+
+	return nil
+}
+
+func (sr *SearchRepository) DeleteSynonymGroup(
+	ctx context.Context, id string) *internal_error.InternalError {
+	result, err := sr.SynonymCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to delete synonym group by id = %s", id), err)
+		return internal_error.NewInternalServerError("Error trying to delete synonym group")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Synonym group not found with this id = %s", id))
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package search
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/search_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (sr *SearchRepository) FindSynonymGroups(
+	ctx context.Context) ([]search_entity.SynonymGroup, *internal_error.InternalError) {
+	cursor, err := sr.SynonymCollection.Find(ctx, bson.M{})
+	if err != nil {
+		logger.Error("Error trying to find synonym groups", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find synonym groups")
+	}
+	defer cursor.Close(ctx)
+
+	var synonymGroupsMongo []SynonymGroupEntityMongo
+	if err := cursor.All(ctx, &synonymGroupsMongo); err != nil {
+		logger.Error("Error trying to decode synonym groups", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find synonym groups")
+	}
+
+	var synonymGroups []search_entity.SynonymGroup
+	for _, synonymGroup := range synonymGroupsMongo {
+		synonymGroups = append(synonymGroups, search_entity.SynonymGroup{
+			Id:        synonymGroup.Id,
+			Terms:     synonymGroup.Terms,
+			Timestamp: time.Unix(synonymGroup.Timestamp, 0),
+		})
+	}
+
+	return synonymGroups, nil
+}
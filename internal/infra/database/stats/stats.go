@@ -0,0 +1,242 @@
+package stats
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/stats_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type StatsRepository struct {
+	bidCollection       *mongo.Collection
+	watchlistCollection *mongo.Collection
+
+	// Stats pages are polled while an auction runs, so they are cached for
+	// cacheTTL
+	cache      map[statsKey]cachedStats
+	cacheMutex *sync.Mutex
+	cacheTTL   time.Duration
+}
+
+type statsKey struct {
+	auctionId string
+	bucket    time.Duration
+}
+
+type cachedStats struct {
+	stats     *stats_entity.AuctionStats
+	expiresAt time.Time
+}
+
+func NewStatsRepository(database *mongo.Database) *StatsRepository {
+	return &StatsRepository{
+		bidCollection:       database.Collection("bids"),
+		watchlistCollection: database.Collection("watchlist"),
+		cache:               make(map[statsKey]cachedStats),
+		cacheMutex:          &sync.Mutex{},
+		cacheTTL:            getStatsCacheTTL(),
+	}
+}
+
+// FindAuctionStats sums up the auction's bids in a single aggregation, the
+// price history groups them into buckets of the given width
+func (sr *StatsRepository) FindAuctionStats(
+	ctx context.Context,
+	auctionId string,
+	bucket time.Duration) (*stats_entity.AuctionStats, *internal_error.InternalError) {
+	now := time.Now()
+	key := statsKey{auctionId: auctionId, bucket: bucket}
+
+	sr.cacheMutex.Lock()
+	cached, ok := sr.cache[key]
+	sr.cacheMutex.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.stats, nil
+	}
+
+	stats, err := sr.aggregateAuctionStats(ctx, auctionId, int64(bucket.Seconds()))
+	if err != nil {
+		logger.Error("Error trying to aggregate auction stats", err, zap.String("auctionID", auctionId))
+		return nil, internal_error.NewInternalServerError("Error trying to find auction stats")
+	}
+
+	sr.cacheMutex.Lock()
+	sr.sweepCache(now)
+	sr.cache[key] = cachedStats{stats: stats, expiresAt: now.Add(sr.cacheTTL)}
+	sr.cacheMutex.Unlock()
+
+	return stats, nil
+}
+
+func (sr *StatsRepository) aggregateAuctionStats(
+	ctx context.Context, auctionId string, bucketSeconds int64) (*stats_entity.AuctionStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"auction_id": auctionId}}},
+		{{Key: "$facet", Value: bson.M{
+			"bids": bson.A{bson.M{"$count": "count"}},
+			"bidders": bson.A{
+				bson.M{"$group": bson.M{"_id": "$user_id"}},
+				bson.M{"$count": "count"},
+			},
+			"history": bson.A{
+				bson.M{"$group": bson.M{
+					"_id": bson.M{"$subtract": bson.A{
+						"$timestamp", bson.M{"$mod": bson.A{"$timestamp", bucketSeconds}},
+					}},
+					"amount":    bson.M{"$max": "$amount"},
+					"bid_count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+		}}},
+	}
+
+	cursor, err := sr.bidCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type count struct {
+		Count int64 `bson:"count"`
+	}
+	var facets []struct {
+		Bids    []count `bson:"bids"`
+		Bidders []count `bson:"bidders"`
+		History []struct {
+			Bucket   int64   `bson:"_id"`
+			Amount   float64 `bson:"amount"`
+			BidCount int64   `bson:"bid_count"`
+		} `bson:"history"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	watchCount, err := sr.watchlistCollection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &stats_entity.AuctionStats{
+		AuctionId:    auctionId,
+		WatchCount:   watchCount,
+		PriceHistory: []stats_entity.PricePoint{},
+	}
+	if len(facets) == 0 {
+		return stats, nil
+	}
+
+	if len(facets[0].Bids) > 0 {
+		stats.BidCount = facets[0].Bids[0].Count
+	}
+	if len(facets[0].Bidders) > 0 {
+		stats.UniqueBidders = facets[0].Bidders[0].Count
+	}
+	for _, point := range facets[0].History {
+		stats.PriceHistory = append(stats.PriceHistory, stats_entity.PricePoint{
+			Timestamp: time.Unix(point.Bucket, 0),
+			Amount:    point.Amount,
+			BidCount:  point.BidCount,
+		})
+	}
+
+	return stats, nil
+}
+
+// FindStatsByAuctionIds takes one aggregation over the bids and one over the
+// watchlist however many auctions are asked for. The counts aren't cached
+func (sr *StatsRepository) FindStatsByAuctionIds(
+	ctx context.Context, auctionIds []string) (map[string]stats_entity.AuctionStats, *internal_error.InternalError) {
+	statsByAuction := make(map[string]stats_entity.AuctionStats, len(auctionIds))
+	match := bson.D{{Key: "$match", Value: bson.M{"auction_id": bson.M{"$in": auctionIds}}}}
+
+	var bidCounts []struct {
+		AuctionId     string `bson:"_id"`
+		BidCount      int64  `bson:"bid_count"`
+		UniqueBidders int64  `bson:"unique_bidders"`
+	}
+	err := aggregateAll(ctx, sr.bidCollection, mongo.Pipeline{
+		match,
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$auction_id",
+			"bid_count": bson.M{"$sum": 1},
+			"bidders":   bson.M{"$addToSet": "$user_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"bid_count":      1,
+			"unique_bidders": bson.M{"$size": "$bidders"},
+		}}},
+	}, &bidCounts)
+	if err != nil {
+		logger.Error("Error trying to count auction bids", err, zap.Int("auctions", len(auctionIds)))
+		return nil, internal_error.NewInternalServerError("Error trying to find auction stats")
+	}
+	for _, count := range bidCounts {
+		statsByAuction[count.AuctionId] = stats_entity.AuctionStats{
+			AuctionId:     count.AuctionId,
+			BidCount:      count.BidCount,
+			UniqueBidders: count.UniqueBidders,
+		}
+	}
+
+	var watchCounts []struct {
+		AuctionId  string `bson:"_id"`
+		WatchCount int64  `bson:"watch_count"`
+	}
+	err = aggregateAll(ctx, sr.watchlistCollection, mongo.Pipeline{
+		match,
+		{{Key: "$group", Value: bson.M{"_id": "$auction_id", "watch_count": bson.M{"$sum": 1}}}},
+	}, &watchCounts)
+	if err != nil {
+		logger.Error("Error trying to count auction watchers", err, zap.Int("auctions", len(auctionIds)))
+		return nil, internal_error.NewInternalServerError("Error trying to find auction stats")
+	}
+	for _, count := range watchCounts {
+		stats := statsByAuction[count.AuctionId]
+		stats.AuctionId = count.AuctionId
+		stats.WatchCount = count.WatchCount
+		statsByAuction[count.AuctionId] = stats
+	}
+
+	return statsByAuction, nil
+}
+
+func aggregateAll(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, results any) error {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+// sweepCache drops expired entries once the cache grows large, so auctions
+// nobody looks at anymore don't pile up
+func (sr *StatsRepository) sweepCache(now time.Time) {
+	if len(sr.cache) < 10000 {
+		return
+	}
+
+	for key, cached := range sr.cache {
+		if !now.Before(cached.expiresAt) {
+			delete(sr.cache, key)
+		}
+	}
+}
+
+func getStatsCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("AUCTION_STATS_CACHE_TTL"))
+	if err != nil || ttl < 0 {
+		return 10 * time.Second
+	}
+
+	return ttl
+}
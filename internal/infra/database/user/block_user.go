@@ -0,0 +1,162 @@
+package user
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// UserBlockMongo stores Until in unix seconds, zero for a block with no end
+type UserBlockMongo struct {
+	Reason string `bson:"reason"`
+	Until  int64  `bson:"until,omitempty"`
+}
+
+func (b *UserBlockMongo) toBlock() *user_entity.Block {
+	if b == nil {
+		return nil
+	}
+
+	block := &user_entity.Block{Reason: b.Reason}
+	if b.Until > 0 {
+		block.Until = time.Unix(b.Until, 0)
+	}
+	return block
+}
+
+// cachedBlock also holds whether the account was deleted, both are read on
+// every authenticated request
+type cachedBlock struct {
+	block     *user_entity.Block
+	deleted   bool
+	expiresAt time.Time
+}
+
+// BlockUser keeps the user from bidding and selling until the given time, a
+// zero time blocks them until UnblockUser
+func (ur *UserRepository) BlockUser(
+	ctx context.Context, userId, reason string, until time.Time) *internal_error.InternalError {
+	block := UserBlockMongo{Reason: reason}
+	if !until.IsZero() {
+		block.Until = until.Unix()
+	}
+
+	return ur.setBlock(ctx, userId, bson.M{"$set": bson.M{"block": block}})
+}
+
+func (ur *UserRepository) UnblockUser(
+	ctx context.Context, userId string) *internal_error.InternalError {
+	return ur.setBlock(ctx, userId, bson.M{"$unset": bson.M{"block": ""}})
+}
+
+// setBlock drops the cached block right away, other instances see the
+// change once their cached entry expires. Deleted accounts keep the block
+// they were deleted with and are reported as not found
+func (ur *UserRepository) setBlock(
+	ctx context.Context, userId string, update bson.M) *internal_error.InternalError {
+	filter := bson.M{"_id": userId, "deleted_at": bson.M{"$exists": false}}
+	result, err := ur.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to update user block", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to update user block")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", userId))
+	}
+
+	ur.blockCacheMutex.Lock()
+	delete(ur.blockCache, userId)
+	ur.blockCacheMutex.Unlock()
+
+	return nil
+}
+
+func (ur *UserRepository) FindUserBlock(
+	ctx context.Context, userId string) (*user_entity.Block, *internal_error.InternalError) {
+	cached, err := ur.findCachedBlock(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return cached.block, nil
+}
+
+// IsAccountDeleted is read through the same cache as FindUserBlock, users
+// who don't exist aren't reported as deleted
+func (ur *UserRepository) IsAccountDeleted(
+	ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+	cached, err := ur.findCachedBlock(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return cached.deleted, nil
+}
+
+func (ur *UserRepository) findCachedBlock(
+	ctx context.Context, userId string) (cachedBlock, *internal_error.InternalError) {
+	now := time.Now()
+
+	ur.blockCacheMutex.Lock()
+	cached, ok := ur.blockCache[userId]
+	ur.blockCacheMutex.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	var userEntityMongo UserEntityMongo
+	findOptions := options.FindOne().SetProjection(bson.M{"block": 1, "deleted_at": 1})
+	err := ur.Collection.FindOne(ctx, bson.M{"_id": userId}, findOptions).Decode(&userEntityMongo)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error("Error trying to find user block", err, zap.String("userID", userId))
+		return cachedBlock{}, internal_error.NewInternalServerError("Error trying to find user block")
+	}
+
+	cached = cachedBlock{
+		block:     userEntityMongo.Block.toBlock(),
+		deleted:   userEntityMongo.DeletedAt > 0,
+		expiresAt: now.Add(ur.blockCacheTTL),
+	}
+
+	ur.blockCacheMutex.Lock()
+	ur.sweepBlockCache(now)
+	ur.blockCache[userId] = cached
+	ur.blockCacheMutex.Unlock()
+
+	return cached, nil
+}
+
+// sweepBlockCache drops expired entries once the cache grows large, so
+// users who stopped bidding don't pile up
+func (ur *UserRepository) sweepBlockCache(now time.Time) {
+	if len(ur.blockCache) < 10000 {
+		return
+	}
+
+	for userId, cached := range ur.blockCache {
+		if !now.Before(cached.expiresAt) {
+			delete(ur.blockCache, userId)
+		}
+	}
+}
+
+func getBlockCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("USER_BLOCK_CACHE_TTL"))
+	if err != nil || ttl < 0 {
+		return 30 * time.Second
+	}
+
+	return ttl
+}
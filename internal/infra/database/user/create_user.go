@@ -0,0 +1,109 @@
+package user
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Emails are kept unique by a unique index on email, a duplicate is
+// reported as a bad request
+func (ur *UserRepository) CreateUser(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	if _, err := ur.Collection.InsertOne(ctx, toUserEntityMongo(userEntity)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return emailTakenError()
+		}
+
+		logger.Error("Error trying to insert user", err)
+		return internal_error.NewInternalServerError("Error trying to insert user")
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) UpdateUser(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	update := bson.M{"$set": bson.M{
+		"name":           userEntity.Name,
+		"email":          userEntity.Email,
+		"password_hash":  userEntity.PasswordHash,
+		"verification":   string(userEntity.Verification),
+		"email_opt_outs": emailOptOutsOf(userEntity),
+	}}
+	if userEntity.EmailVerifiedAt.IsZero() {
+		update["$unset"] = bson.M{"email_verified_at": ""}
+	}
+
+	result, err := ur.Collection.UpdateOne(ctx, bson.M{"_id": userEntity.Id}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return emailTakenError()
+		}
+
+		logger.Error("Error trying to update user", err, zap.String("userID", userEntity.Id))
+		return internal_error.NewInternalServerError("Error trying to update user")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", userEntity.Id))
+	}
+
+	return nil
+}
+
+// VerifyEmail only matches while the user still has the email the token was
+// issued for, a change in between leaves the new email unverified
+func (ur *UserRepository) VerifyEmail(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	filter := bson.M{"_id": userEntity.Id, "email": userEntity.Email}
+	update := bson.M{"$set": bson.M{
+		"verification":      string(userEntity.Verification),
+		"email_verified_at": userEntity.EmailVerifiedAt.Unix(),
+	}}
+
+	result, err := ur.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to verify user email", err, zap.String("userID", userEntity.Id))
+		return internal_error.NewInternalServerError("Error trying to verify user email")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewBadRequestError("The email was changed since the token was issued")
+	}
+
+	return nil
+}
+
+func emailTakenError() *internal_error.InternalError {
+	return internal_error.NewValidationError("Invalid user",
+		internal_error.Cause{Field: "email", Message: "Email is already in use"})
+}
+
+func toUserEntityMongo(userEntity *user_entity.User) *UserEntityMongo {
+	return &UserEntityMongo{
+		Id:           userEntity.Id,
+		Name:         userEntity.Name,
+		Email:        userEntity.Email,
+		PasswordHash: userEntity.PasswordHash,
+		Slug:         userEntity.Slug,
+		Verification: string(userEntity.Verification),
+		EmailOptOuts: emailOptOutsOf(userEntity),
+	}
+}
+
+func emailOptOutsOf(userEntity *user_entity.User) []string {
+	optOuts := make([]string, 0, len(userEntity.EmailOptOuts))
+	for _, optOut := range userEntity.EmailOptOuts {
+		optOuts = append(optOuts, string(optOut))
+	}
+
+	return optOuts
+}
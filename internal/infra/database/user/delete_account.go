@@ -0,0 +1,210 @@
+package user
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// purgeBatchSize bounds the accounts purged on each run
+const purgeBatchSize = 500
+
+// DeleteAccount overwrites the user's personal fields with the anonymized
+// ones, bids, auctions and feedback keep pointing to the user. Their
+// watchlist, proxy bids and API keys go away, as do the addresses of
+// checkouts that are done with. An account deleted already is reported as
+// not found
+func (ur *UserRepository) DeleteAccount(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	filter := bson.M{"_id": userEntity.Id, "deleted_at": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set": bson.M{
+			"name":         userEntity.Name,
+			"email":        userEntity.Email,
+			"verification": string(userEntity.Verification),
+			"block":        UserBlockMongo{Reason: userEntity.Block.Reason},
+			"deleted_at":   userEntity.DeletedAt.Unix(),
+		},
+		"$unset": bson.M{"password_hash": "", "slug": "", "email_verified_at": "", "email_opt_outs": ""},
+	}
+
+	result, err := ur.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to delete account", err, zap.String("userID", userEntity.Id))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", userEntity.Id))
+	}
+
+	ur.blockCacheMutex.Lock()
+	delete(ur.blockCache, userEntity.Id)
+	ur.blockCacheMutex.Unlock()
+
+	return ur.dropPersonalData(ctx, userEntity.Id)
+}
+
+// dropPersonalData removes what was only kept for the user. Addresses of
+// checkouts still being shipped are needed to deliver them
+func (ur *UserRepository) dropPersonalData(ctx context.Context, userId string) *internal_error.InternalError {
+	if _, err := ur.watchlistCollection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		logger.Error("Error trying to delete the watchlist of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if _, err := ur.proxyBidCollection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		logger.Error("Error trying to delete the proxy bids of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if _, err := ur.apiKeyCollection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		logger.Error("Error trying to delete the API keys of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if _, err := ur.savedSearchCollection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		logger.Error("Error trying to delete the saved searches of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if _, err := ur.deviceCollection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		logger.Error("Error trying to delete the devices of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if _, err := ur.preferenceCollection.DeleteOne(ctx, bson.M{"_id": userId}); err != nil {
+		logger.Error("Error trying to delete the notification preferences of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	if _, err := ur.digestItemCollection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		logger.Error("Error trying to delete the pending digest of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	filter := bson.M{
+		"buyer_user_id": userId,
+		"status":        bson.M{"$in": bson.A{checkout_entity.Delivered, checkout_entity.Returned}},
+	}
+	if _, err := ur.checkoutCollection.UpdateMany(ctx, filter, bson.M{
+		"$unset": bson.M{"address": ""},
+		"$inc":   bson.M{"version": 1},
+	}); err != nil {
+		logger.Error("Error trying to clear the addresses of a deleted account", err, zap.String("userID", userId))
+		return internal_error.NewInternalServerError("Error trying to delete account")
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) startPurger() {
+	defer close(ur.purgerDone)
+
+	ticker := time.NewTicker(ur.purgeEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ur.purgeDeletedAccounts(time.Now())
+		case <-ur.purgerCtx.Done():
+			return
+		}
+	}
+}
+
+// purgeDeletedAccounts removes accounts deleted more than retention ago that
+// no bid, auction or checkout points to anymore. Accounts something still
+// points to stay anonymized for good
+func (ur *UserRepository) purgeDeletedAccounts(now time.Time) {
+	ctx, cancel := context.WithTimeout(ur.purgerCtx, ur.purgeEvery)
+	defer cancel()
+
+	cutoff := now.Add(-ur.retention).Unix()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": bson.M{"$lte": cutoff}}}},
+		lookupReference(ur.bidCollection.Name(), "user_id", "bids"),
+		lookupReference(ur.auctionCollection.Name(), "seller_id", "sold"),
+		lookupReference(ur.auctionCollection.Name(), "winner_user_id", "won"),
+		lookupReference(ur.checkoutCollection.Name(), "buyer_user_id", "checkouts"),
+		{{Key: "$match", Value: bson.M{
+			"bids":      bson.M{"$size": 0},
+			"sold":      bson.M{"$size": 0},
+			"won":       bson.M{"$size": 0},
+			"checkouts": bson.M{"$size": 0},
+		}}},
+		{{Key: "$limit", Value: purgeBatchSize}},
+		{{Key: "$project", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := ur.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find deleted accounts to purge", err)
+		return
+	}
+
+	var orphans []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &orphans); err != nil {
+		logger.Error("Error trying to decode deleted accounts to purge", err)
+		return
+	}
+	if len(orphans) == 0 {
+		return
+	}
+
+	ids := make(bson.A, 0, len(orphans))
+	for _, orphan := range orphans {
+		ids = append(ids, orphan.Id)
+	}
+
+	result, err := ur.Collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$lte": cutoff}})
+	if err != nil {
+		logger.Error("Error trying to purge deleted accounts", err)
+		return
+	}
+
+	logger.Info("Deleted accounts purged", zap.Int64("count", result.DeletedCount))
+}
+
+// lookupReference fetches at most one document of the collection whose
+// field points to the user, which is all it takes to keep them
+func lookupReference(collection, field, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from":         collection,
+		"localField":   "_id",
+		"foreignField": field,
+		"pipeline":     bson.A{bson.M{"$limit": 1}, bson.M{"$project": bson.M{"_id": 1}}},
+		"as":           as,
+	}}}
+}
+
+func getAccountRetention() time.Duration {
+	retention, err := time.ParseDuration(os.Getenv("ACCOUNT_RETENTION"))
+	if err != nil || retention < 0 {
+		return 30 * 24 * time.Hour
+	}
+
+	return retention
+}
+
+func getAccountPurgeInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("ACCOUNT_PURGE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return time.Hour
+	}
+
+	return interval
+}
@@ -0,0 +1,203 @@
+package user
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/testhelpers"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DeleteAccountSuite struct {
+	suite.Suite
+	database *mongo.Database
+	repo     *UserRepository
+}
+
+func (suite *DeleteAccountSuite) SetupSuite() {
+	suite.database = testhelpers.NewMongoDatabase(suite.T())
+	suite.repo = NewUserRepository(suite.database)
+	suite.repo.retention = 24 * time.Hour
+}
+
+func (suite *DeleteAccountSuite) TearDownSuite() {
+	// The database itself is dropped by the test helper
+	suite.repo.Close()
+}
+
+func (suite *DeleteAccountSuite) createUser() *user_entity.User {
+	userEntity, err := user_entity.CreateUser("Ana", uuid.New().String()+"@example.com", "a-long-password")
+	assert.Nil(suite.T(), err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = suite.repo.CreateUser(ctx, userEntity)
+	assert.Nil(suite.T(), err)
+
+	return userEntity
+}
+
+// deleteAccount deletes the account as of deletedAt
+func (suite *DeleteAccountSuite) deleteAccount(userEntity *user_entity.User, deletedAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userEntity.Anonymize(deletedAt)
+	err := suite.repo.DeleteAccount(ctx, userEntity)
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *DeleteAccountSuite) count(collection *mongo.Collection, filter bson.M) int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := collection.CountDocuments(ctx, filter)
+	assert.Nil(suite.T(), err)
+	return count
+}
+
+func (suite *DeleteAccountSuite) TestDeletedAccountsAreAnonymized() {
+	userEntity := suite.createUser()
+	email := userEntity.Email
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Kept for the user alone, or needed by the other party
+	_, err := suite.repo.watchlistCollection.InsertOne(ctx, bson.M{"_id": uuid.New().String(), "user_id": userEntity.Id})
+	assert.Nil(suite.T(), err)
+	_, err = suite.repo.apiKeyCollection.InsertOne(ctx, bson.M{
+		"_id": uuid.New().String(), "user_id": userEntity.Id, "hash": uuid.New().String()})
+	assert.Nil(suite.T(), err)
+	delivered, shipped := uuid.New().String(), uuid.New().String()
+	_, err = suite.repo.checkoutCollection.InsertMany(ctx, []interface{}{
+		bson.M{"_id": delivered, "auction_id": uuid.New().String(), "buyer_user_id": userEntity.Id,
+			"status": checkout_entity.Delivered, "address": bson.M{"street": "Rua A"}},
+		bson.M{"_id": shipped, "auction_id": uuid.New().String(), "buyer_user_id": userEntity.Id,
+			"status": checkout_entity.Shipped, "address": bson.M{"street": "Rua A"}},
+	})
+	assert.Nil(suite.T(), err)
+
+	suite.deleteAccount(userEntity, time.Now())
+
+	stored, errFind := suite.repo.FindUserById(ctx, userEntity.Id)
+	assert.Nil(suite.T(), errFind)
+	assert.Equal(suite.T(), user_entity.DeletedUserName, stored.Name)
+	assert.NotEqual(suite.T(), email, stored.Email)
+	assert.Empty(suite.T(), stored.PasswordHash)
+	assert.True(suite.T(), stored.Deleted())
+
+	assert.Equal(suite.T(), int64(0), suite.count(suite.repo.watchlistCollection, bson.M{"user_id": userEntity.Id}))
+	assert.Equal(suite.T(), int64(0), suite.count(suite.repo.apiKeyCollection, bson.M{"user_id": userEntity.Id}))
+	assert.Equal(suite.T(), int64(0), suite.count(suite.repo.checkoutCollection,
+		bson.M{"_id": delivered, "address": bson.M{"$exists": true}}))
+	assert.Equal(suite.T(), int64(1), suite.count(suite.repo.checkoutCollection,
+		bson.M{"_id": shipped, "address": bson.M{"$exists": true}}))
+
+	// The email is free again
+	_, errEmail := suite.repo.FindUserByEmail(ctx, email)
+	assert.NotNil(suite.T(), errEmail)
+	assert.Equal(suite.T(), "not_found", errEmail.Err)
+
+	// Deleting twice is reported as not found
+	errDelete := suite.repo.DeleteAccount(ctx, userEntity)
+	assert.NotNil(suite.T(), errDelete)
+	assert.Equal(suite.T(), "not_found", errDelete.Err)
+}
+
+func (suite *DeleteAccountSuite) TestDeletedAccountsStayBlocked() {
+	userEntity := suite.createUser()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Read before the deletion, so a stale cache entry would show
+	deleted, err := suite.repo.IsAccountDeleted(ctx, userEntity.Id)
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), deleted)
+
+	suite.deleteAccount(userEntity, time.Now())
+
+	deleted, err = suite.repo.IsAccountDeleted(ctx, userEntity.Id)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), deleted)
+
+	err = suite.repo.UnblockUser(ctx, userEntity.Id)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "not_found", err.Err)
+
+	err = suite.repo.BlockUser(ctx, userEntity.Id, "Spam", time.Time{})
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "not_found", err.Err)
+
+	block, err := suite.repo.FindUserBlock(ctx, userEntity.Id)
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), block)
+	assert.NotNil(suite.T(), block.Check(time.Now()))
+
+	// Users who don't exist aren't deleted
+	deleted, err = suite.repo.IsAccountDeleted(ctx, uuid.New().String())
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), deleted)
+}
+
+func (suite *DeleteAccountSuite) TestOnlyAccountsNothingPointsToArePurged() {
+	now := time.Now()
+	orphan := suite.createUser()
+	bidder := suite.createUser()
+	seller := suite.createUser()
+	recent := suite.createUser()
+	active := suite.createUser()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := suite.repo.bidCollection.InsertOne(ctx, bson.M{"_id": uuid.New().String(), "user_id": bidder.Id})
+	assert.Nil(suite.T(), err)
+	_, err = suite.repo.auctionCollection.InsertOne(ctx, bson.M{"_id": uuid.New().String(), "seller_id": seller.Id})
+	assert.Nil(suite.T(), err)
+
+	past := now.Add(-2 * suite.repo.retention)
+	suite.deleteAccount(orphan, past)
+	suite.deleteAccount(bidder, past)
+	suite.deleteAccount(seller, past)
+	suite.deleteAccount(recent, now.Add(-suite.repo.retention/2))
+
+	suite.repo.purgeDeletedAccounts(now)
+
+	cases := []struct {
+		name string
+		user *user_entity.User
+		kept bool
+	}{
+		{name: "deleted past retention with nothing pointing to it", user: orphan, kept: false},
+		{name: "bids point to it", user: bidder, kept: true},
+		{name: "an auction points to it", user: seller, kept: true},
+		{name: "deleted within retention", user: recent, kept: true},
+		{name: "not deleted", user: active, kept: true},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			_, err := suite.repo.FindUserById(ctx, tc.user.Id)
+			if tc.kept {
+				assert.Nil(suite.T(), err)
+				return
+			}
+			assert.NotNil(suite.T(), err)
+			assert.Equal(suite.T(), "not_found", err.Err)
+		})
+	}
+}
+
+func TestDeleteAccountSuite(t *testing.T) {
+	suite.Run(t, new(DeleteAccountSuite))
+}
@@ -1,30 +1,118 @@
 package user
 
 import (
+	"auction_go/configuration/database/mongodb"
 	"auction_go/configuration/logger"
 	"auction_go/internal/entity/user_entity"
 	"auction_go/internal/internal_error"
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type UserEntityMongo struct {
-	Id   string `bson:"_id"`
-	Name string `bson:"name"`
+	Id           string `bson:"_id"`
+	Name         string `bson:"name"`
+	Email        string `bson:"email,omitempty"`
+	PasswordHash string `bson:"password_hash,omitempty"`
+	Slug         string `bson:"slug,omitempty"`
+	Verification string `bson:"verification,omitempty"`
+
+	Block      *UserBlockMongo     `bson:"block,omitempty"`
+	Reputation UserReputationMongo `bson:"reputation,omitempty"`
+	DeletedAt  int64               `bson:"deleted_at,omitempty"`
+
+	EmailVerifiedAt int64    `bson:"email_verified_at,omitempty"`
+	EmailOptOuts    []string `bson:"email_opt_outs,omitempty"`
+}
+
+// UserReputationMongo is kept up to date by the feedback repository
+type UserReputationMongo struct {
+	Score float64 `bson:"score"`
+	Count int64   `bson:"count"`
 }
 
 type UserRepository struct {
-	Collection *mongo.Collection
+	Collection            *mongo.Collection
+	watchlistCollection   *mongo.Collection
+	proxyBidCollection    *mongo.Collection
+	checkoutCollection    *mongo.Collection
+	bidCollection         *mongo.Collection
+	auctionCollection     *mongo.Collection
+	apiKeyCollection      *mongo.Collection
+	savedSearchCollection *mongo.Collection
+	deviceCollection      *mongo.Collection
+	preferenceCollection  *mongo.Collection
+	digestItemCollection  *mongo.Collection
+
+	// Blocks are checked on every bid, so they are cached for blockCacheTTL
+	blockCache      map[string]cachedBlock
+	blockCacheMutex *sync.Mutex
+	blockCacheTTL   time.Duration
+
+	// Deleted accounts nothing points to are purged once retention passed,
+	// see purgeDeletedAccounts
+	retention  time.Duration
+	purgeEvery time.Duration
+	purgerCtx  context.Context
+	stopPurger context.CancelFunc
+	purgerDone chan struct{}
 }
 
+// NewUserRepository starts purging deleted accounts, Close stops it
 func NewUserRepository(database *mongo.Database) *UserRepository {
-	return &UserRepository{
-		Collection: database.Collection("users"),
+	purgerCtx, stopPurger := context.WithCancel(context.Background())
+
+	repo := &UserRepository{
+		Collection:            database.Collection("users"),
+		watchlistCollection:   database.Collection("watchlist"),
+		proxyBidCollection:    database.Collection("proxy_bids"),
+		checkoutCollection:    database.Collection("checkouts"),
+		bidCollection:         database.Collection("bids"),
+		auctionCollection:     database.Collection("auctions"),
+		apiKeyCollection:      database.Collection("api_keys"),
+		savedSearchCollection: database.Collection("saved_searches"),
+		deviceCollection:      database.Collection("devices"),
+		preferenceCollection:  database.Collection("notification_preferences"),
+		digestItemCollection:  database.Collection("notification_digest_items"),
+		blockCache:            make(map[string]cachedBlock),
+		blockCacheMutex:       &sync.Mutex{},
+		blockCacheTTL:         getBlockCacheTTL(),
+		retention:             getAccountRetention(),
+		purgeEvery:            getAccountPurgeInterval(),
+		purgerCtx:             purgerCtx,
+		stopPurger:            stopPurger,
+		purgerDone:            make(chan struct{}),
+	}
+
+	go repo.startPurger()
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the users collection
+func (ur *UserRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, ur.Collection.Database(), "users"); err != nil {
+		logger.Error("Error trying to create user indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create user indexes")
 	}
+
+	return nil
+}
+
+// Close stops purging deleted accounts
+func (ur *UserRepository) Close() {
+	ur.stopPurger()
+	<-ur.purgerDone
 }
 
 func (ur *UserRepository) FindUserById(
@@ -35,19 +123,99 @@ func (ur *UserRepository) FindUserById(
 	err := ur.Collection.FindOne(ctx, filter).Decode(&userEntityMongo)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			logger.Error(fmt.Sprintf("User not found with this id = %d", userId), err)
+			logger.Error(fmt.Sprintf("User not found with this id = %s", userId), err)
 			return nil, internal_error.NewNotFoundError(
-				fmt.Sprintf("User not found with this id = %d", userId))
+				fmt.Sprintf("User not found with this id = %s", userId))
 		}
 
 		logger.Error("Error trying to find user by userId", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find user by userId")
 	}
 
+	return toUserEntity(userEntityMongo), nil
+}
+
+func (ur *UserRepository) FindUsersByIds(
+	ctx context.Context, userIds []string) ([]user_entity.User, *internal_error.InternalError) {
+	cursor, err := ur.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": userIds}})
+	if err != nil {
+		logger.Error("Error trying to find users by ids", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find users")
+	}
+
+	var usersMongo []UserEntityMongo
+	if err := cursor.All(ctx, &usersMongo); err != nil {
+		logger.Error("Error trying to find users by ids", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find users")
+	}
+
+	users := make([]user_entity.User, 0, len(usersMongo))
+	for _, userMongo := range usersMongo {
+		users = append(users, *toUserEntity(userMongo))
+	}
+
+	return users, nil
+}
+
+func (ur *UserRepository) FindUserBySlug(
+	ctx context.Context, slug string) (*user_entity.User, *internal_error.InternalError) {
+	filter := bson.M{"slug": slug}
+
+	var userEntityMongo UserEntityMongo
+	err := ur.Collection.FindOne(ctx, filter).Decode(&userEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("User not found with this slug = %s", slug))
+		}
+
+		logger.Error("Error trying to find user by slug", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find user by slug")
+	}
+
+	return toUserEntity(userEntityMongo), nil
+}
+
+// FindUserByEmail expects the email lowercase, as users store it
+func (ur *UserRepository) FindUserByEmail(
+	ctx context.Context, email string) (*user_entity.User, *internal_error.InternalError) {
+	var userEntityMongo UserEntityMongo
+	err := ur.Collection.FindOne(ctx, bson.M{"email": email}).Decode(&userEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError("User not found with this email")
+		}
+
+		logger.Error("Error trying to find user by email", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find user by email")
+	}
+
+	return toUserEntity(userEntityMongo), nil
+}
+
+func toUserEntity(userEntityMongo UserEntityMongo) *user_entity.User {
 	userEntity := &user_entity.User{
-		Id:   userEntityMongo.Id,
-		Name: userEntityMongo.Name,
+		Id:           userEntityMongo.Id,
+		Name:         userEntityMongo.Name,
+		Email:        userEntityMongo.Email,
+		PasswordHash: userEntityMongo.PasswordHash,
+		Slug:         userEntityMongo.Slug,
+		Verification: user_entity.VerificationLevel(userEntityMongo.Verification),
+		Block:        userEntityMongo.Block.toBlock(),
+		Reputation: user_entity.Reputation{
+			Score: userEntityMongo.Reputation.Score,
+			Count: userEntityMongo.Reputation.Count,
+		},
+	}
+	if userEntityMongo.EmailVerifiedAt > 0 {
+		userEntity.EmailVerifiedAt = time.Unix(userEntityMongo.EmailVerifiedAt, 0)
+	}
+	if userEntityMongo.DeletedAt > 0 {
+		userEntity.DeletedAt = time.Unix(userEntityMongo.DeletedAt, 0)
+	}
+	for _, optOut := range userEntityMongo.EmailOptOuts {
+		userEntity.EmailOptOuts = append(userEntity.EmailOptOuts, user_entity.EmailNotification(optOut))
 	}
 
-	return userEntity, nil
+	return userEntity
 }
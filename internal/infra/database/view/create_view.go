@@ -0,0 +1,53 @@
+package view
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/view_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DailyViewsEntityMongo struct {
+	Id        string         `bson:"_id"`
+	AuctionId string         `bson:"auction_id"`
+	Day       string         `bson:"day"`
+	Views     int64          `bson:"views"`
+	Registers map[string]int `bson:"registers"`
+}
+
+type ViewRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewViewRepository(database *mongo.Database) *ViewRepository {
+	return &ViewRepository{
+		Collection: database.Collection("auction_views"),
+	}
+}
+
+func (vr *ViewRepository) CreateView(
+	ctx context.Context,
+	viewEntity *view_entity.AuctionView) *internal_error.InternalError {
+	day := viewEntity.Day()
+	index, rank := viewEntity.Register()
+
+	filter := bson.M{"_id": fmt.Sprintf("%s:%s", viewEntity.AuctionId, day)}
+	update := bson.M{
+		"$inc":         bson.M{"views": 1},
+		"$max":         bson.M{fmt.Sprintf("registers.%d", index): rank},
+		"$setOnInsert": bson.M{"auction_id": viewEntity.AuctionId, "day": day},
+	}
+
+	if _, err := vr.Collection.UpdateOne(
+		ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to register auction view", err)
+		return internal_error.NewInternalServerError("Error trying to register auction view")
+	}
+
+	return nil
+}
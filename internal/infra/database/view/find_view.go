@@ -0,0 +1,55 @@
+package view
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/view_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (vr *ViewRepository) FindDailyViewsByAuctionId(
+	ctx context.Context, auctionId string) ([]view_entity.DailyViews, *internal_error.InternalError) {
+	filter := bson.M{"auction_id": auctionId}
+	opts := options.Find().SetSort(bson.D{{Key: "day", Value: 1}})
+
+	cursor, err := vr.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find views by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find views by auctionId %s", auctionId))
+	}
+	defer cursor.Close(ctx)
+
+	var dailyViewsMongo []DailyViewsEntityMongo
+	if err := cursor.All(ctx, &dailyViewsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode views by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find views by auctionId %s", auctionId))
+	}
+
+	var dailyViews []view_entity.DailyViews
+	for _, daily := range dailyViewsMongo {
+		registers := make(map[int]int, len(daily.Registers))
+		for key, rank := range daily.Registers {
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				continue
+			}
+			registers[index] = rank
+		}
+
+		dailyViews = append(dailyViews, view_entity.DailyViews{
+			AuctionId: daily.AuctionId,
+			Day:       daily.Day,
+			Views:     daily.Views,
+			Registers: registers,
+		})
+	}
+
+	return dailyViews, nil
+}
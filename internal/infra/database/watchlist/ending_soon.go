@@ -0,0 +1,84 @@
+package watchlist
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/watchlist_entity"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// SetEndingSoonNotifier replaces where ending soon alerts go, they are only
+// logged until a notifier is set
+func (wr *WatchlistRepository) SetEndingSoonNotifier(notifier watchlist_entity.EndingSoonNotifier) {
+	wr.notifierMutex.Lock()
+	defer wr.notifierMutex.Unlock()
+
+	wr.notifier = notifier
+}
+
+// RemindEndingSoon alerts the watchers of an auction closing at endTime and
+// its high bidder, once each. It is meant to be registered as an auction
+// reminder hook, the lifecycle scheduler makes sure each reminder is only
+// sent once
+func (wr *WatchlistRepository) RemindEndingSoon(
+	ctx context.Context, auctionId, highBidderId string, endTime time.Time) {
+	cursor, err := wr.Collection.Find(ctx, bson.M{"auction_id": auctionId},
+		options.Find().SetProjection(bson.M{"user_id": 1}))
+	if err != nil {
+		logger.Error("Error trying to find auction watchers", err, zap.String("auctionID", auctionId))
+		return
+	}
+
+	var entries []WatchlistEntryMongo
+	if err := cursor.All(ctx, &entries); err != nil {
+		logger.Error("Error trying to decode auction watchers", err, zap.String("auctionID", auctionId))
+		return
+	}
+
+	userIds := make([]string, 0, len(entries)+1)
+	for _, entry := range entries {
+		if entry.UserId != highBidderId {
+			userIds = append(userIds, entry.UserId)
+		}
+	}
+	if highBidderId != "" {
+		userIds = append(userIds, highBidderId)
+	}
+
+	for _, userId := range userIds {
+		wr.notifyEndingSoon(ctx, watchlist_entity.EndingSoonEvent{
+			UserId:    userId,
+			AuctionId: auctionId,
+			EndTime:   endTime,
+		})
+	}
+}
+
+// A failing notifier must not stop the other watchers from being alerted
+func (wr *WatchlistRepository) notifyEndingSoon(ctx context.Context, event watchlist_entity.EndingSoonEvent) {
+	wr.notifierMutex.Lock()
+	notifier := wr.notifier
+	wr.notifierMutex.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Ending soon notifier panicked", fmt.Errorf("%v", r), zap.String("auctionID", event.AuctionId))
+		}
+	}()
+
+	notifier.NotifyEndingSoon(ctx, event)
+}
+
+type logEndingSoonNotifier struct{}
+
+func (logEndingSoonNotifier) NotifyEndingSoon(ctx context.Context, event watchlist_entity.EndingSoonEvent) {
+	logger.Info("Watched auction ending soon",
+		zap.String("auctionID", event.AuctionId),
+		zap.String("userID", event.UserId),
+		zap.Time("endTime", event.EndTime))
+}
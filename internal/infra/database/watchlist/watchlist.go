@@ -0,0 +1,136 @@
+package watchlist
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/watchlist_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// WatchlistEntryMongo is keyed by user and auction, so watching twice keeps
+// one entry
+type WatchlistEntryMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	AuctionId string `bson:"auction_id"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// WatchlistRepository alerts watchers of auctions about to close when the
+// auction repository reminds it, see RemindEndingSoon
+type WatchlistRepository struct {
+	Collection *mongo.Collection
+
+	notifier      watchlist_entity.EndingSoonNotifier
+	notifierMutex *sync.Mutex
+}
+
+func NewWatchlistRepository(database *mongo.Database) *WatchlistRepository {
+	repo := &WatchlistRepository{
+		Collection:    database.Collection("watchlist"),
+		notifier:      logEndingSoonNotifier{},
+		notifierMutex: &sync.Mutex{},
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the watchlist collection
+func (wr *WatchlistRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.EnsureIndexes(ctx, wr.Collection.Database(), "watchlist"); err != nil {
+		logger.Error("Error trying to create watchlist indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create watchlist indexes")
+	}
+
+	return nil
+}
+
+func entryId(userId, auctionId string) string {
+	return fmt.Sprintf("%s:%s", userId, auctionId)
+}
+
+func (wr *WatchlistRepository) AddToWatchlist(
+	ctx context.Context, entry *watchlist_entity.WatchlistEntry) *internal_error.InternalError {
+	filter := bson.M{"_id": entryId(entry.UserId, entry.AuctionId)}
+	update := bson.M{"$setOnInsert": bson.M{
+		"user_id":    entry.UserId,
+		"auction_id": entry.AuctionId,
+		"timestamp":  entry.Timestamp.Unix(),
+	}}
+
+	if _, err := wr.Collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error("Error trying to add auction to watchlist", err, zap.String("auctionID", entry.AuctionId))
+		return internal_error.NewInternalServerError("Error trying to add auction to watchlist")
+	}
+
+	return nil
+}
+
+func (wr *WatchlistRepository) RemoveFromWatchlist(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	result, err := wr.Collection.DeleteOne(ctx, bson.M{"_id": entryId(userId, auctionId)})
+	if err != nil {
+		logger.Error("Error trying to remove auction from watchlist", err, zap.String("auctionID", auctionId))
+		return internal_error.NewInternalServerError("Error trying to remove auction from watchlist")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction %s is not on the watchlist", auctionId))
+	}
+
+	return nil
+}
+
+// FindWatchlistByUserId lists the most recently watched auctions first
+func (wr *WatchlistRepository) FindWatchlistByUserId(
+	ctx context.Context, userId string) ([]watchlist_entity.WatchlistEntry, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := wr.Collection.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find watchlist", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find watchlist")
+	}
+	defer cursor.Close(ctx)
+
+	var entriesMongo []WatchlistEntryMongo
+	if err := cursor.All(ctx, &entriesMongo); err != nil {
+		logger.Error("Error trying to decode watchlist", err, zap.String("userID", userId))
+		return nil, internal_error.NewInternalServerError("Error trying to find watchlist")
+	}
+
+	entries := make([]watchlist_entity.WatchlistEntry, 0, len(entriesMongo))
+	for _, entryMongo := range entriesMongo {
+		entries = append(entries, watchlist_entity.WatchlistEntry{
+			UserId:    entryMongo.UserId,
+			AuctionId: entryMongo.AuctionId,
+			Timestamp: time.Unix(entryMongo.Timestamp, 0),
+		})
+	}
+
+	return entries, nil
+}
+
+func (wr *WatchlistRepository) CountWatchlistByUserId(
+	ctx context.Context, userId string) (int64, *internal_error.InternalError) {
+	count, err := wr.Collection.CountDocuments(ctx, bson.M{"user_id": userId})
+	if err != nil {
+		logger.Error("Error trying to count watchlist", err, zap.String("userID", userId))
+		return 0, internal_error.NewInternalServerError("Error trying to count watchlist")
+	}
+
+	return count, nil
+}
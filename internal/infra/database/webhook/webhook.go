@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/webhook_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type WebhookEntityMongo struct {
+	Id        string                     `bson:"_id"`
+	URL       string                     `bson:"url"`
+	Secret    string                     `bson:"secret"`
+	Events    []webhook_entity.EventType `bson:"events"`
+	Timestamp int64                      `bson:"timestamp"`
+}
+
+// DeadLetterEntityMongo keeps the payload as the JSON text that was posted
+type DeadLetterEntityMongo struct {
+	Id         string                   `bson:"_id"`
+	WebhookId  string                   `bson:"webhook_id"`
+	DeliveryId string                   `bson:"delivery_id"`
+	Event      webhook_entity.EventType `bson:"event"`
+	Payload    string                   `bson:"payload"`
+	Attempts   int                      `bson:"attempts"`
+	LastError  string                   `bson:"last_error"`
+	Timestamp  int64                    `bson:"timestamp"`
+}
+
+type WebhookRepository struct {
+	Collection           *mongo.Collection
+	deadLetterCollection *mongo.Collection
+}
+
+func NewWebhookRepository(database *mongo.Database) *WebhookRepository {
+	repo := &WebhookRepository{
+		Collection:           database.Collection("webhooks"),
+		deadLetterCollection: database.Collection("webhook_dead_letters"),
+	}
+
+	indexCtx, cancelIndex := mongodb.IndexContext()
+	defer cancelIndex()
+	repo.EnsureIndexes(indexCtx)
+
+	return repo
+}
+
+// EnsureIndexes creates the indexes of the webhooks and webhook_dead_letters
+// collections
+func (wr *WebhookRepository) EnsureIndexes(ctx context.Context) *internal_error.InternalError {
+	for _, collection := range []string{"webhooks", "webhook_dead_letters"} {
+		if err := mongodb.EnsureIndexes(ctx, wr.Collection.Database(), collection); err != nil {
+			logger.Error("Error trying to create webhook indexes", err, zap.String("collection", collection))
+			return internal_error.NewInternalServerError("Error trying to create webhook indexes")
+		}
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) CreateWebhook(
+	ctx context.Context, webhook *webhook_entity.Webhook) *internal_error.InternalError {
+	webhookMongo := &WebhookEntityMongo{
+		Id:        webhook.Id,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Events:    webhook.Events,
+		Timestamp: webhook.Timestamp.Unix(),
+	}
+
+	if _, err := wr.Collection.InsertOne(ctx, webhookMongo); err != nil {
+		logger.Error("Error trying to insert webhook", err)
+		return internal_error.NewInternalServerError("Error trying to insert webhook")
+	}
+
+	return nil
+}
+
+// FindWebhooks lists the webhooks oldest first
+func (wr *WebhookRepository) FindWebhooks(
+	ctx context.Context) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	return wr.findWebhooks(ctx, bson.M{})
+}
+
+func (wr *WebhookRepository) FindWebhooksByEvent(
+	ctx context.Context, event webhook_entity.EventType) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	return wr.findWebhooks(ctx, bson.M{"events": event})
+}
+
+func (wr *WebhookRepository) findWebhooks(
+	ctx context.Context, filter bson.M) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := wr.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find webhooks", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+	defer cursor.Close(ctx)
+
+	var webhooksMongo []WebhookEntityMongo
+	if err := cursor.All(ctx, &webhooksMongo); err != nil {
+		logger.Error("Error trying to decode webhooks", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+
+	webhooks := make([]webhook_entity.Webhook, 0, len(webhooksMongo))
+	for _, webhookMongo := range webhooksMongo {
+		webhooks = append(webhooks, webhook_entity.Webhook{
+			Id:        webhookMongo.Id,
+			URL:       webhookMongo.URL,
+			Secret:    webhookMongo.Secret,
+			Events:    webhookMongo.Events,
+			Timestamp: time.Unix(webhookMongo.Timestamp, 0),
+		})
+	}
+
+	return webhooks, nil
+}
+
+func (wr *WebhookRepository) DeleteWebhook(
+	ctx context.Context, id string) *internal_error.InternalError {
+	result, err := wr.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error("Error trying to delete webhook", err, zap.String("webhookID", id))
+		return internal_error.NewInternalServerError("Error trying to delete webhook")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Webhook not found with this id = %s", id))
+	}
+
+	if _, err := wr.deadLetterCollection.DeleteMany(ctx, bson.M{"webhook_id": id}); err != nil {
+		logger.Error("Error trying to delete webhook dead letters", err, zap.String("webhookID", id))
+		return internal_error.NewInternalServerError("Error trying to delete webhook")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) CreateDeadLetter(
+	ctx context.Context, deadLetter *webhook_entity.DeadLetter) *internal_error.InternalError {
+	deadLetterMongo := &DeadLetterEntityMongo{
+		Id:         deadLetter.Id,
+		WebhookId:  deadLetter.WebhookId,
+		DeliveryId: deadLetter.DeliveryId,
+		Event:      deadLetter.Event,
+		Payload:    string(deadLetter.Payload),
+		Attempts:   deadLetter.Attempts,
+		LastError:  deadLetter.LastError,
+		Timestamp:  deadLetter.Timestamp.Unix(),
+	}
+
+	if _, err := wr.deadLetterCollection.InsertOne(ctx, deadLetterMongo); err != nil {
+		logger.Error("Error trying to insert webhook dead letter", err,
+			zap.String("webhookID", deadLetter.WebhookId))
+		return internal_error.NewInternalServerError("Error trying to insert webhook dead letter")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) FindDeadLettersByWebhookId(
+	ctx context.Context, webhookId string) ([]webhook_entity.DeadLetter, *internal_error.InternalError) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(webhook_entity.MaxDeadLetters)
+	cursor, err := wr.deadLetterCollection.Find(ctx, bson.M{"webhook_id": webhookId}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find webhook dead letters", err, zap.String("webhookID", webhookId))
+		return nil, internal_error.NewInternalServerError("Error trying to find webhook dead letters")
+	}
+	defer cursor.Close(ctx)
+
+	var deadLettersMongo []DeadLetterEntityMongo
+	if err := cursor.All(ctx, &deadLettersMongo); err != nil {
+		logger.Error("Error trying to decode webhook dead letters", err, zap.String("webhookID", webhookId))
+		return nil, internal_error.NewInternalServerError("Error trying to find webhook dead letters")
+	}
+
+	deadLetters := make([]webhook_entity.DeadLetter, 0, len(deadLettersMongo))
+	for _, deadLetterMongo := range deadLettersMongo {
+		deadLetters = append(deadLetters, webhook_entity.DeadLetter{
+			Id:         deadLetterMongo.Id,
+			WebhookId:  deadLetterMongo.WebhookId,
+			DeliveryId: deadLetterMongo.DeliveryId,
+			Event:      deadLetterMongo.Event,
+			Payload:    []byte(deadLetterMongo.Payload),
+			Attempts:   deadLetterMongo.Attempts,
+			LastError:  deadLetterMongo.LastError,
+			Timestamp:  time.Unix(deadLetterMongo.Timestamp, 0),
+		})
+	}
+
+	return deadLetters, nil
+}
@@ -0,0 +1,274 @@
+package notification
+
+import (
+	"auction_go/configuration/email"
+	"auction_go/configuration/links"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/notification_entity"
+	"auction_go/internal/entity/receipt_entity"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/events"
+	"bytes"
+	"context"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const emailTimeout = 30 * time.Second
+
+// digestTemplate sums up the notifications held for a digest, the other
+// templates are named after the kind of email they render
+const digestTemplate = "digest"
+
+// emailVerificationTemplate carries the token users confirm their email with
+const emailVerificationTemplate = "email_verification"
+
+//go:embed templates
+var templateFiles embed.FS
+
+// emailTemplate renders one kind of email, the .txt file defines its
+// "subject" and "text" and the .html file its "html"
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// emailData is what the templates are rendered with, Items are the
+// subjects of the notifications a digest sums up, Token confirms an email,
+// Receipt is what the winner owes and Link opens the auction
+type emailData struct {
+	Name        string
+	ProductName string
+	SearchName  string
+	Amount      float64
+	Sold        bool
+	Items       []string
+	Token       string
+	Receipt     *receipt_entity.Receipt
+	Link        string
+	EndTime     time.Time
+}
+
+// EmailNotifier emails users when they are outbid, win an auction, one they
+// watch or lead is about to close or a new auction matches their saved
+// searches, and sellers when their auction
+// ends. Users may opt out of each kind, and have the ones that aren't
+// urgent summed up in a digest instead. Winners are emailed their receipt,
+// which is stored even when the email isn't sent
+type EmailNotifier struct {
+	userRepository         user_entity.UserRepositoryInterface
+	auctionRepository      auction_entity.AuctionRepositoryInterface
+	notificationRepository notification_entity.NotificationRepositoryInterface
+	receiptRepository      receipt_entity.ReceiptRepositoryInterface
+	sender                 email.Sender
+	templates              map[string]emailTemplate
+}
+
+// NewEmailNotifier parses the templates, without a sender the emails are
+// only logged
+func NewEmailNotifier(
+	userRepository user_entity.UserRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	notificationRepository notification_entity.NotificationRepositoryInterface,
+	receiptRepository receipt_entity.ReceiptRepositoryInterface,
+	sender email.Sender) *EmailNotifier {
+	templates := make(map[string]emailTemplate)
+	names := []string{digestTemplate, emailVerificationTemplate}
+	for _, notification := range user_entity.EmailNotifications {
+		names = append(names, string(notification))
+	}
+	for _, name := range names {
+		templates[name] = emailTemplate{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/"+name+".txt")),
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/"+name+".html")),
+		}
+	}
+
+	return &EmailNotifier{
+		userRepository:         userRepository,
+		auctionRepository:      auctionRepository,
+		notificationRepository: notificationRepository,
+		receiptRepository:      receiptRepository,
+		sender:                 sender,
+		templates:              templates,
+	}
+}
+
+// HandleEvent sends the emails an event calls for, it is meant to be
+// subscribed to the event bus
+func (en *EmailNotifier) HandleEvent(ctx context.Context, event events.Event) {
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	switch event := event.(type) {
+	case events.BidderOutbid:
+		auction, err := en.auctionRepository.FindAuctionById(ctx, event.AuctionId)
+		if err != nil {
+			return
+		}
+		en.notify(ctx, event.UserId, user_entity.OutbidEmail, event.AuctionId, emailData{
+			ProductName: auction.ProductName,
+			Amount:      event.NewAmount,
+			Link:        links.Auction(event.AuctionId),
+		})
+	case events.AuctionClosed:
+		auction, err := en.auctionRepository.FindAuctionById(ctx, event.AuctionId)
+		if err != nil {
+			return
+		}
+
+		data := emailData{
+			ProductName: auction.ProductName,
+			Amount:      auction.WinningAmount,
+			Sold:        event.WinnerUserId != "",
+		}
+		if data.Sold {
+			won := data
+			won.Receipt = en.issueReceipt(ctx, event, auction)
+			en.notify(ctx, event.WinnerUserId, user_entity.AuctionWonEmail, event.AuctionId, won)
+		}
+		en.notify(ctx, auction.SellerId, user_entity.AuctionEndedEmail, event.AuctionId, data)
+	case events.AuctionEndingSoon:
+		auction, err := en.auctionRepository.FindAuctionById(ctx, event.AuctionId)
+		if err != nil {
+			return
+		}
+		en.notify(ctx, event.UserId, user_entity.EndingSoonEmail, event.AuctionId, emailData{
+			ProductName: auction.ProductName,
+			Link:        links.Auction(event.AuctionId),
+			EndTime:     event.EndTime,
+		})
+	case events.SavedSearchMatched:
+		en.notify(ctx, event.UserId, user_entity.SavedSearchEmail, event.AuctionId, emailData{
+			ProductName: event.ProductName,
+			SearchName:  event.Name,
+		})
+	}
+}
+
+// issueReceipt stores the winner's receipt, the one stored first when the
+// close is handled again. The receipt is still emailed when it can't be
+// stored
+func (en *EmailNotifier) issueReceipt(
+	ctx context.Context, event events.AuctionClosed, auction *auction_entity.Auction) *receipt_entity.Receipt {
+	receipt := receipt_entity.CreateReceipt(event.AuctionId, event.WinnerUserId,
+		auction.ProductName, auction.WinningAmount, links.Checkout(event.AuctionId), event.Timestamp)
+
+	stored, err := en.receiptRepository.CreateReceipt(ctx, receipt)
+	if err != nil {
+		return receipt
+	}
+
+	return stored
+}
+
+// notify emails the user right away, or holds the email for their next
+// digest. Kinds of email are named after the notification events
+func (en *EmailNotifier) notify(
+	ctx context.Context, userId string, kind user_entity.EmailNotification, auctionId string, data emailData) {
+	user, err := en.userRepository.FindUserById(ctx, userId)
+	if err != nil || !user.WantsEmail(kind) {
+		return
+	}
+	data.Name = user.Name
+
+	event := notification_entity.EventType(kind)
+	preferences, err := en.notificationRepository.FindPreferences(ctx, userId)
+	if err != nil || !preferences.Allows(notification_entity.Email, event) {
+		return
+	}
+
+	message, renderErr := en.render(string(kind), data)
+	if renderErr != nil {
+		logger.Error("Error trying to render email", renderErr,
+			zap.String("userID", userId), zap.String("notification", string(kind)))
+		return
+	}
+
+	if preferences.Digests(event) {
+		en.notificationRepository.AddDigestItem(ctx, notification_entity.CreateDigestItem(
+			userId, event, message.Subject, auctionId, preferences.Digest, time.Now()))
+		return
+	}
+
+	message.To = user.Email
+	en.send(ctx, userId, message)
+}
+
+// SendDigest emails the user the subjects of the notifications held for
+// them, it makes the notifier a digest sender
+func (en *EmailNotifier) SendDigest(ctx context.Context, digest notification_entity.Digest) {
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	user, err := en.userRepository.FindUserById(ctx, digest.UserId)
+	if err != nil || user.Deleted() {
+		return
+	}
+
+	data := emailData{Name: user.Name}
+	for _, item := range digest.Items {
+		data.Items = append(data.Items, item.Subject)
+	}
+
+	message, renderErr := en.render(digestTemplate, data)
+	if renderErr != nil {
+		logger.Error("Error trying to render digest", renderErr, zap.String("userID", digest.UserId))
+		return
+	}
+
+	message.To = user.Email
+	en.send(ctx, digest.UserId, message)
+}
+
+// SendEmailVerification emails the user the token that confirms their
+// email, it makes the notifier an email verification sender. Opt outs
+// don't apply to it, and the token is never logged
+func (en *EmailNotifier) SendEmailVerification(ctx context.Context, user user_entity.User, token string) {
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	message, err := en.render(emailVerificationTemplate, emailData{Name: user.Name, Token: token})
+	if err != nil {
+		logger.Error("Error trying to render email verification", err, zap.String("userID", user.Id))
+		return
+	}
+
+	message.To = user.Email
+	en.send(ctx, user.Id, message)
+}
+
+func (en *EmailNotifier) send(ctx context.Context, userId string, message email.Message) {
+	if en.sender == nil {
+		logger.Info("Email not sent, no email service is set up",
+			zap.String("userID", userId), zap.String("subject", message.Subject))
+		return
+	}
+
+	if err := en.sender.Send(ctx, message); err != nil {
+		logger.Error("Error trying to send email", err,
+			zap.String("userID", userId), zap.String("subject", message.Subject))
+	}
+}
+
+func (en *EmailNotifier) render(name string, data emailData) (email.Message, error) {
+	template := en.templates[name]
+
+	var subject, text, html bytes.Buffer
+	if err := template.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return email.Message{}, err
+	}
+	if err := template.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return email.Message{}, err
+	}
+	if err := template.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return email.Message{}, err
+	}
+
+	return email.Message{Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"auction_go/configuration/links"
+	"auction_go/configuration/logger"
+	"auction_go/configuration/push"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/device_entity"
+	"auction_go/internal/entity/notification_entity"
+	"auction_go/internal/events"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// pushQueueSize bounds how many pushes may wait for a worker, pushes
+	// queued while it is full are dropped
+	pushQueueSize = 1000
+	pushWorkers   = 4
+
+	// pushAttempts is how many times a push the service couldn't take is
+	// sent, waiting pushRetryDelay before the second try and twice as long
+	// before each one after
+	pushAttempts   = 4
+	pushRetryDelay = time.Second
+
+	pushTimeout = 30 * time.Second
+)
+
+// PushNotifier sends pushes to the devices users registered when they are
+// outbid, win an auction or one they watch is about to end, unless they
+// muted pushes or the kind of notification
+type PushNotifier struct {
+	deviceRepository       device_entity.DeviceRepositoryInterface
+	auctionRepository      auction_entity.AuctionRepositoryInterface
+	notificationRepository notification_entity.NotificationRepositoryInterface
+	providers              map[device_entity.Platform]push.Provider
+
+	queue   chan pushJob
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers *sync.WaitGroup
+}
+
+type pushJob struct {
+	userId    string
+	auctionId string
+	message   push.Message
+}
+
+// NewPushNotifier starts the workers that send the pushes, platforms
+// without a provider only have their pushes logged
+func NewPushNotifier(
+	deviceRepository device_entity.DeviceRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	notificationRepository notification_entity.NotificationRepositoryInterface,
+	providers map[device_entity.Platform]push.Provider) *PushNotifier {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	notifier := &PushNotifier{
+		deviceRepository:       deviceRepository,
+		auctionRepository:      auctionRepository,
+		notificationRepository: notificationRepository,
+		providers:              providers,
+		queue:                  make(chan pushJob, pushQueueSize),
+		ctx:                    ctx,
+		cancel:                 cancel,
+		workers:                &sync.WaitGroup{},
+	}
+
+	for i := 0; i < pushWorkers; i++ {
+		notifier.workers.Add(1)
+		go notifier.work()
+	}
+
+	return notifier
+}
+
+// Close stops sending, pushes still queued are dropped
+func (pn *PushNotifier) Close() {
+	pn.cancel()
+	pn.workers.Wait()
+}
+
+// HandleEvent queues the pushes an event calls for, it is meant to be
+// subscribed to the event bus
+func (pn *PushNotifier) HandleEvent(ctx context.Context, event events.Event) {
+	switch event := event.(type) {
+	case events.BidderOutbid:
+		pn.enqueue(ctx, event.UserId, event.AuctionId, notification_entity.Outbid, func(productName string) push.Message {
+			return push.Message{
+				Title: "You were outbid",
+				Body:  fmt.Sprintf("Someone bid %.2f on %s", event.NewAmount, productName),
+				Data:  map[string]string{"amount": strconv.FormatFloat(event.NewAmount, 'f', 2, 64)},
+			}
+		})
+	case events.AuctionClosed:
+		if event.WinnerUserId == "" {
+			return
+		}
+		pn.enqueue(ctx, event.WinnerUserId, event.AuctionId, notification_entity.AuctionWon, func(productName string) push.Message {
+			return push.Message{
+				Title: "You won!",
+				Body:  fmt.Sprintf("%s is yours, head to checkout to pay for it", productName),
+			}
+		})
+	case events.AuctionEndingSoon:
+		pn.enqueue(ctx, event.UserId, event.AuctionId, notification_entity.EndingSoon, func(productName string) push.Message {
+			return push.Message{
+				Title: "Ending soon",
+				Body: fmt.Sprintf("%s closes in %s",
+					productName, time.Until(event.EndTime).Round(time.Minute)),
+			}
+		})
+	}
+}
+
+// enqueue names the auction's product in the message, the app gets the
+// auction's id and a link to its page to open it. Digests are only emailed, so pushes held for one
+// are dropped
+func (pn *PushNotifier) enqueue(
+	ctx context.Context, userId, auctionId string,
+	event notification_entity.EventType, message func(productName string) push.Message) {
+	preferences, err := pn.notificationRepository.FindPreferences(ctx, userId)
+	if err != nil || !preferences.Allows(notification_entity.Push, event) || preferences.Digests(event) {
+		return
+	}
+
+	productName := "An auction"
+	if auction, err := pn.auctionRepository.FindAuctionById(ctx, auctionId); err == nil {
+		productName = auction.ProductName
+	}
+
+	job := pushJob{userId: userId, auctionId: auctionId, message: message(productName)}
+	if job.message.Data == nil {
+		job.message.Data = make(map[string]string)
+	}
+	job.message.Data["auction_id"] = auctionId
+	job.message.Data["link"] = links.Auction(auctionId)
+
+	select {
+	case pn.queue <- job:
+	default:
+		logger.Info("Push queue is full, push dropped",
+			zap.String("userID", userId), zap.String("auctionID", auctionId))
+	}
+}
+
+func (pn *PushNotifier) work() {
+	defer pn.workers.Done()
+
+	for {
+		select {
+		case job := <-pn.queue:
+			pn.deliver(job)
+		case <-pn.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver sends the push to every device of the user
+func (pn *PushNotifier) deliver(job pushJob) {
+	ctx, cancel := context.WithTimeout(pn.ctx, pushTimeout)
+	defer cancel()
+
+	devices, err := pn.deviceRepository.FindDevicesByUserId(ctx, job.userId)
+	if err != nil {
+		return
+	}
+
+	for _, device := range devices {
+		provider, ok := pn.providers[device.Platform]
+		if !ok || provider == nil {
+			logger.Info("Push not sent, no provider for the platform",
+				zap.String("userID", job.userId),
+				zap.String("auctionID", job.auctionId),
+				zap.String("platform", string(device.Platform)),
+				zap.String("title", job.message.Title))
+			continue
+		}
+
+		pn.send(ctx, provider, device, job)
+	}
+}
+
+// send retries while the push service is unavailable, and forgets the
+// device once the service no longer knows its token
+func (pn *PushNotifier) send(ctx context.Context, provider push.Provider, device device_entity.Device, job pushJob) {
+	delay := pushRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := provider.Send(ctx, device.Token, job.message)
+		if err == nil {
+			return
+		}
+
+		if errors.Is(err, push.ErrInvalidToken) {
+			logger.Info("Push token is no longer valid, device dropped",
+				zap.String("userID", job.userId), zap.String("deviceID", device.Id))
+			pn.deviceRepository.DeleteDeviceByToken(ctx, device.Platform, device.Token)
+			return
+		}
+
+		if !errors.Is(err, push.ErrUnavailable) || attempt == pushAttempts {
+			logger.Error("Error trying to send push", err,
+				zap.String("userID", job.userId), zap.String("deviceID", device.Id))
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return
+		}
+	}
+}
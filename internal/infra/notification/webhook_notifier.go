@@ -0,0 +1,213 @@
+package notification
+
+import (
+	"auction_go/configuration/broker"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/webhook_entity"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// webhookQueueSize bounds how many deliveries may wait for a worker,
+	// deliveries queued while it is full are dropped
+	webhookQueueSize = 1000
+	webhookWorkers   = 4
+
+	// webhookAttempts is how many times a delivery the endpoint failed is
+	// posted, waiting webhookRetryDelay before the second try and twice as
+	// long before each one after, about a minute overall
+	webhookAttempts   = 6
+	webhookRetryDelay = 2 * time.Second
+
+	webhookTimeout = 10 * time.Second
+)
+
+// webhookClient doesn't follow redirects, an endpoint that moved has to be
+// registered again
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// WebhookNotifier posts auction lifecycle events to the webhooks subscribed
+// to them, signed with each webhook's secret
+type WebhookNotifier struct {
+	webhookRepository webhook_entity.WebhookRepositoryInterface
+
+	queue   chan webhookDelivery
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers *sync.WaitGroup
+}
+
+type webhookDelivery struct {
+	id      string
+	webhook webhook_entity.Webhook
+	event   webhook_entity.EventType
+	payload []byte
+}
+
+// NewWebhookNotifier starts the workers that post the deliveries
+func NewWebhookNotifier(webhookRepository webhook_entity.WebhookRepositoryInterface) *WebhookNotifier {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	notifier := &WebhookNotifier{
+		webhookRepository: webhookRepository,
+		queue:             make(chan webhookDelivery, webhookQueueSize),
+		ctx:               ctx,
+		cancel:            cancel,
+		workers:           &sync.WaitGroup{},
+	}
+
+	for i := 0; i < webhookWorkers; i++ {
+		notifier.workers.Add(1)
+		go notifier.work()
+	}
+
+	return notifier
+}
+
+// Close stops posting, deliveries still queued or waiting for a retry are
+// dropped
+func (wn *WebhookNotifier) Close() {
+	wn.cancel()
+	wn.workers.Wait()
+}
+
+// Publish queues a delivery of the message to every webhook subscribed to
+// its type, it is meant to be added as an outbox destination. Webhooks get
+// the event as published outside the process, its id stays the same across
+// retries so receivers can drop duplicates. A full queue turns the message
+// down, the outbox hands it over again later
+func (wn *WebhookNotifier) Publish(ctx context.Context, message broker.Message) error {
+	if wn.ctx.Err() != nil {
+		return errors.New("webhook notifier is closed")
+	}
+	eventType := webhook_entity.EventType(message.Type)
+
+	webhooks, err := wn.webhookRepository.FindWebhooksByEvent(ctx, eventType)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		delivery := webhookDelivery{id: message.Id, webhook: webhook, event: eventType, payload: message.Payload}
+
+		select {
+		case wn.queue <- delivery:
+		default:
+			logger.Info("Webhook queue is full, delivery put off",
+				zap.String("webhookID", webhook.Id), zap.String("event", message.Type))
+			return errors.New("webhook queue is full")
+		}
+	}
+
+	return nil
+}
+
+func (wn *WebhookNotifier) work() {
+	defer wn.workers.Done()
+
+	for {
+		select {
+		case delivery := <-wn.queue:
+			wn.deliver(delivery)
+		case <-wn.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver retries while the endpoint fails or answers with an error it may
+// recover from, and keeps a dead letter once it gives up
+func (wn *WebhookNotifier) deliver(delivery webhookDelivery) {
+	delay := webhookRetryDelay
+	for attempt := 1; ; attempt++ {
+		retryable, err := wn.post(delivery)
+		if err == nil {
+			return
+		}
+
+		if !retryable || attempt == webhookAttempts {
+			wn.deadLetter(delivery, attempt, err)
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-wn.ctx.Done():
+			return
+		}
+	}
+}
+
+// post tells whether a failed delivery is worth another try, endpoints
+// turning it down with a client error other than a timeout or a rate limit
+// won't take it later either
+func (wn *WebhookNotifier) post(delivery webhookDelivery) (bool, error) {
+	request, err := http.NewRequestWithContext(
+		wn.ctx, http.MethodPost, delivery.webhook.URL, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Id", delivery.id)
+	request.Header.Set("X-Webhook-Event", string(delivery.event))
+	request.Header.Set("X-Webhook-Signature", delivery.webhook.Sign(time.Now(), delivery.payload))
+
+	response, err := webhookClient.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("endpoint answered %s", response.Status)
+	switch {
+	case response.StatusCode == http.StatusRequestTimeout,
+		response.StatusCode == http.StatusTooManyRequests,
+		response.StatusCode >= http.StatusInternalServerError:
+		return true, err
+	default:
+		return false, err
+	}
+}
+
+func (wn *WebhookNotifier) deadLetter(delivery webhookDelivery, attempts int, lastErr error) {
+	logger.Info("Webhook delivery failed for good, kept as a dead letter",
+		zap.String("webhookID", delivery.webhook.Id),
+		zap.String("deliveryID", delivery.id),
+		zap.Int("attempts", attempts),
+		zap.String("error", lastErr.Error()))
+
+	ctx, cancel := context.WithTimeout(wn.ctx, webhookTimeout)
+	defer cancel()
+
+	wn.webhookRepository.CreateDeadLetter(ctx, &webhook_entity.DeadLetter{
+		Id:         uuid.New().String(),
+		WebhookId:  delivery.webhook.Id,
+		DeliveryId: delivery.id,
+		Event:      delivery.event,
+		Payload:    delivery.payload,
+		Attempts:   attempts,
+		LastError:  lastErr.Error(),
+		Timestamp:  time.Now(),
+	})
+}
@@ -0,0 +1,315 @@
+package realtime
+
+import (
+	"auction_go/configuration/links"
+	"auction_go/internal/events"
+	"context"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	BidPlaced       EventType = "bid"
+	PriceChanged    EventType = "price"
+	AuctionExtended EventType = "extended"
+	AuctionClosed   EventType = "closed"
+	ViewersChanged  EventType = "viewers"
+	Outbid          EventType = "outbid"
+)
+
+// Event is something that happened on an auction. Bids carry the bidder
+// and amount, price changes the new current price, extensions the new end
+// time, closes the winner, if any, and viewer changes the auction's live
+// viewers. Outbid events only go to the bidder who lost the lead, with the
+// new price and a link to bid again. Id grows with every event the hub
+// publishes, viewer changes and outbid events aren't numbered since they
+// aren't replayed. ServerTime is stamped as the event is sent so clients can
+// sync their countdown with it
+type Event struct {
+	Id         int64      `json:"id,omitempty"`
+	Type       EventType  `json:"type"`
+	AuctionId  string     `json:"auction_id"`
+	UserId     string     `json:"user_id,omitempty"`
+	Amount     float64    `json:"amount,omitempty"`
+	EndTime    *time.Time `json:"end_time,omitempty"`
+	Viewers    int        `json:"viewers,omitempty"`
+	Link       string     `json:"link,omitempty"`
+	Timestamp  time.Time  `json:"timestamp"`
+	ServerTime time.Time  `json:"server_time"`
+}
+
+// subscriptionBuffer is how many events a subscriber may fall behind before
+// it is dropped
+const subscriptionBuffer = 64
+
+const (
+	// historySize is how many of an auction's latest events are kept for
+	// clients resuming their stream
+	historySize = 100
+
+	// historyTTL is how long the events of an auction that went quiet are
+	// kept once the history grows large
+	historyTTL = 10 * time.Minute
+)
+
+// Hub fans the events of each auction out to its subscribers. It only sees
+// what happens on this replica, bids placed on another one aren't streamed
+type Hub struct {
+	subscriptions map[string]map[*Subscription]struct{}
+	history       map[string]*eventHistory
+	lastEventId   int64
+	mutex         *sync.Mutex
+	closed        bool
+
+	// presenceChanged holds the auctions whose viewers joined or left since
+	// their count was last broadcast
+	presenceChanged map[string]struct{}
+	stopPresence    chan struct{}
+	presenceDone    chan struct{}
+
+	// userSubscriptions receive the events meant for a single user
+	userSubscriptions map[string]map[*Subscription]struct{}
+}
+
+type eventHistory struct {
+	events      []Event
+	publishedAt time.Time
+}
+
+// NewHub starts broadcasting the auctions' viewer counts, Close stops it
+func NewHub() *Hub {
+	hub := &Hub{
+		subscriptions:     make(map[string]map[*Subscription]struct{}),
+		userSubscriptions: make(map[string]map[*Subscription]struct{}),
+		history:           make(map[string]*eventHistory),
+		// Ids start from the clock so they keep growing across restarts, a
+		// client resuming from before one isn't mistaken for being ahead
+		lastEventId:     time.Now().UnixNano(),
+		mutex:           &sync.Mutex{},
+		presenceChanged: make(map[string]struct{}),
+		stopPresence:    make(chan struct{}),
+		presenceDone:    make(chan struct{}),
+	}
+
+	go hub.broadcastPresence()
+
+	return hub
+}
+
+// Subscription receives the events of one auction, or the ones meant for
+// one user, on Events until it is closed. Events is closed along with it,
+// and when the subscriber falls too far behind
+type Subscription struct {
+	Events    <-chan Event
+	events    chan Event
+	auctionId string
+	userId    string
+	hub       *Hub
+}
+
+// Subscribe starts receiving the auction's events, the subscription is
+// closed right away once the hub is
+func (h *Hub) Subscribe(auctionId string) *Subscription {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.subscribe(auctionId)
+}
+
+// Resume starts receiving the auction's events along with the ones
+// published after lastEventId, so a reconnecting client doesn't miss what
+// happened in between. Only the latest events are kept, older ones are lost
+func (h *Hub) Resume(auctionId string, lastEventId int64) (*Subscription, []Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var missed []Event
+	if history := h.history[auctionId]; history != nil {
+		for _, event := range history.events {
+			if event.Id > lastEventId {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	return h.subscribe(auctionId), missed
+}
+
+// subscribe must be called with the mutex held
+func (h *Hub) subscribe(auctionId string) *Subscription {
+	events := make(chan Event, subscriptionBuffer)
+	subscription := &Subscription{Events: events, events: events, auctionId: auctionId, hub: h}
+
+	if h.closed {
+		close(events)
+		return subscription
+	}
+
+	if h.subscriptions[auctionId] == nil {
+		h.subscriptions[auctionId] = make(map[*Subscription]struct{})
+	}
+	h.subscriptions[auctionId][subscription] = struct{}{}
+	h.presenceChanged[auctionId] = struct{}{}
+
+	return subscription
+}
+
+// Close stops the subscription, it is a no-op when it already stopped
+func (s *Subscription) Close() {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+
+	s.hub.remove(s)
+}
+
+// remove must be called with the mutex held
+func (h *Hub) remove(subscription *Subscription) {
+	if subscription.userId != "" {
+		h.removeUser(subscription)
+		return
+	}
+
+	subscriptions := h.subscriptions[subscription.auctionId]
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(h.subscriptions, subscription.auctionId)
+	}
+	close(subscription.events)
+	h.presenceChanged[subscription.auctionId] = struct{}{}
+}
+
+// Publish numbers the event and hands it to the auction's subscribers
+// without waiting on them, a subscriber whose buffer is full is dropped
+func (h *Hub) Publish(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastEventId++
+	event.Id = h.lastEventId
+	h.record(event)
+	h.deliver(event)
+}
+
+// deliver must be called with the mutex held
+func (h *Hub) deliver(event Event) {
+	for subscription := range h.subscriptions[event.AuctionId] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+// record must be called with the mutex held
+func (h *Hub) record(event Event) {
+	now := time.Now()
+	h.sweepHistory(now)
+
+	history := h.history[event.AuctionId]
+	if history == nil {
+		history = &eventHistory{}
+		h.history[event.AuctionId] = history
+	}
+
+	history.events = append(history.events, event)
+	if len(history.events) > historySize {
+		history.events = history.events[len(history.events)-historySize:]
+	}
+	history.publishedAt = now
+}
+
+// sweepHistory drops the events of auctions that went quiet once the
+// history grows large, so closed auctions don't pile up
+func (h *Hub) sweepHistory(now time.Time) {
+	if len(h.history) < 1000 {
+		return
+	}
+
+	for auctionId, history := range h.history {
+		if now.Sub(history.publishedAt) > historyTTL {
+			delete(h.history, auctionId)
+		}
+	}
+}
+
+// Close ends every subscription, later ones are closed right away
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.stopPresence)
+	}
+	for _, subscriptions := range h.subscriptions {
+		for subscription := range subscriptions {
+			h.remove(subscription)
+		}
+	}
+	for _, subscriptions := range h.userSubscriptions {
+		for subscription := range subscriptions {
+			h.remove(subscription)
+		}
+	}
+	h.mutex.Unlock()
+
+	<-h.presenceDone
+}
+
+// HandleEvent streams the bids, price changes, extensions and closes
+// published on the event bus, and tells outbid bidders they lost the lead.
+// It is meant to be subscribed to the bus
+func (h *Hub) HandleEvent(ctx context.Context, event events.Event) {
+	switch event := event.(type) {
+	case events.BidPlaced:
+		bid := event.Bid
+		h.Publish(Event{
+			Type:      BidPlaced,
+			AuctionId: bid.AuctionId,
+			UserId:    bid.UserId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+		h.Publish(Event{
+			Type:      PriceChanged,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+	case events.AuctionPriceDropped:
+		h.Publish(Event{
+			Type:      PriceChanged,
+			AuctionId: event.AuctionId,
+			Amount:    event.Price,
+			Timestamp: event.Timestamp,
+		})
+	case events.AuctionExtended:
+		h.Publish(Event{
+			Type:      AuctionExtended,
+			AuctionId: event.AuctionId,
+			EndTime:   &event.EndTime,
+			Timestamp: event.Timestamp,
+		})
+	case events.BidderOutbid:
+		h.NotifyUser(Event{
+			Type:      Outbid,
+			AuctionId: event.AuctionId,
+			UserId:    event.UserId,
+			Amount:    event.NewAmount,
+			Link:      links.Auction(event.AuctionId),
+			Timestamp: time.Now(),
+		})
+	case events.AuctionClosed:
+		h.Publish(Event{
+			Type:      AuctionClosed,
+			AuctionId: event.AuctionId,
+			UserId:    event.WinnerUserId,
+			Timestamp: event.Timestamp,
+		})
+	}
+}
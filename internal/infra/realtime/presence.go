@@ -0,0 +1,62 @@
+package realtime
+
+import "time"
+
+// presenceInterval is how often the viewer counts that changed are
+// broadcast, viewers joining and leaving in between go out as one event
+const presenceInterval = time.Second
+
+// Presence is how many clients follow an auction's live stream
+type Presence struct {
+	AuctionId string `json:"auction_id"`
+	Viewers   int    `json:"viewers"`
+}
+
+// Presence counts the auction's viewers on this replica
+func (h *Hub) Presence(auctionId string) Presence {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return Presence{AuctionId: auctionId, Viewers: len(h.subscriptions[auctionId])}
+}
+
+func (h *Hub) broadcastPresence() {
+	defer close(h.presenceDone)
+
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.publishPresence()
+		case <-h.stopPresence:
+			return
+		}
+	}
+}
+
+// publishPresence tells the viewers of each auction whose count changed how
+// many they are now. Viewers dropped on the way are counted on the next
+// round
+func (h *Hub) publishPresence() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	for auctionId := range h.presenceChanged {
+		delete(h.presenceChanged, auctionId)
+
+		viewers := len(h.subscriptions[auctionId])
+		if viewers == 0 {
+			continue
+		}
+
+		h.deliver(Event{
+			Type:      ViewersChanged,
+			AuctionId: auctionId,
+			Viewers:   viewers,
+			Timestamp: now,
+		})
+	}
+}
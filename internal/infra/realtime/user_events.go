@@ -0,0 +1,54 @@
+package realtime
+
+// SubscribeUser starts receiving the events meant for the user, like being
+// outbid, whatever auction they are about. The subscription is closed right
+// away once the hub is
+func (h *Hub) SubscribeUser(userId string) *Subscription {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	events := make(chan Event, subscriptionBuffer)
+	subscription := &Subscription{Events: events, events: events, userId: userId, hub: h}
+
+	if h.closed {
+		close(events)
+		return subscription
+	}
+
+	if h.userSubscriptions[userId] == nil {
+		h.userSubscriptions[userId] = make(map[*Subscription]struct{})
+	}
+	h.userSubscriptions[userId][subscription] = struct{}{}
+
+	return subscription
+}
+
+// NotifyUser hands the event to the subscriptions of its UserId without
+// waiting on them. It isn't numbered nor kept, users who aren't connected
+// get it from their other channels
+func (h *Hub) NotifyUser(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for subscription := range h.userSubscriptions[event.UserId] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+// removeUser must be called with the mutex held
+func (h *Hub) removeUser(subscription *Subscription) {
+	subscriptions := h.userSubscriptions[subscription.userId]
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(h.userSubscriptions, subscription.userId)
+	}
+	close(subscription.events)
+}
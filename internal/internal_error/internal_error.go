@@ -1,8 +1,18 @@
 package internal_error
 
+import "time"
+
 type InternalError struct {
+	Message    string
+	Err        string
+	Causes     []Cause
+	RetryAfter time.Duration
+}
+
+// Cause points a bad request at the field that caused it
+type Cause struct {
+	Field   string
 	Message string
-	Err     string
 }
 
 func (ie *InternalError) Error() string {
@@ -29,3 +39,40 @@ func NewBadRequestError(message string) *InternalError {
 		Err:     "bad_request",
 	}
 }
+
+func NewValidationError(message string, causes ...Cause) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "bad_request",
+		Causes:  causes,
+	}
+}
+
+func NewUnauthorizedError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "unauthorized",
+	}
+}
+
+func NewForbiddenError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "forbidden",
+	}
+}
+
+func NewInvalidTransitionError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "invalid_transition",
+	}
+}
+
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *InternalError {
+	return &InternalError{
+		Message:    message,
+		Err:        "too_many_requests",
+		RetryAfter: retryAfter,
+	}
+}
@@ -0,0 +1,154 @@
+package testhelpers
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionBuilder starts from a valid active auction created now, tests only
+// set the fields they care about
+type AuctionBuilder struct {
+	auction auction_entity.Auction
+}
+
+func AnAuction() *AuctionBuilder {
+	return &AuctionBuilder{auction: auction_entity.Auction{
+		Id:          uuid.New().String(),
+		ProductName: "Test Product",
+		Category:    "Electronics",
+		Description: "This is a test product description for testing purposes",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}}
+}
+
+func (b *AuctionBuilder) WithId(id string) *AuctionBuilder {
+	b.auction.Id = id
+	return b
+}
+
+func (b *AuctionBuilder) WithSellerId(sellerId string) *AuctionBuilder {
+	b.auction.SellerId = sellerId
+	return b
+}
+
+func (b *AuctionBuilder) WithProductName(productName string) *AuctionBuilder {
+	b.auction.ProductName = productName
+	return b
+}
+
+func (b *AuctionBuilder) WithDescription(description string) *AuctionBuilder {
+	b.auction.Description = description
+	return b
+}
+
+func (b *AuctionBuilder) WithLocation(latitude, longitude float64) *AuctionBuilder {
+	b.auction.Location = &auction_entity.GeoPoint{Latitude: latitude, Longitude: longitude}
+	return b
+}
+
+func (b *AuctionBuilder) WithCategory(category string) *AuctionBuilder {
+	b.auction.Category = category
+	return b
+}
+
+func (b *AuctionBuilder) WithStatus(status auction_entity.AuctionStatus) *AuctionBuilder {
+	b.auction.Status = status
+	return b
+}
+
+func (b *AuctionBuilder) WithTimestamp(timestamp time.Time) *AuctionBuilder {
+	b.auction.Timestamp = timestamp
+	return b
+}
+
+// WithStartTime schedules the auction to open at startTime
+func (b *AuctionBuilder) WithStartTime(startTime time.Time) *AuctionBuilder {
+	b.auction.StartTime = startTime
+	b.auction.Status = auction_entity.Scheduled
+	return b
+}
+
+func (b *AuctionBuilder) WithDuration(duration time.Duration) *AuctionBuilder {
+	b.auction.Duration = duration
+	return b
+}
+
+func (b *AuctionBuilder) WithReservePrice(reservePrice float64) *AuctionBuilder {
+	b.auction.ReservePrice = reservePrice
+	return b
+}
+
+func (b *AuctionBuilder) WithMinIncrement(minIncrement float64) *AuctionBuilder {
+	b.auction.MinIncrement = minIncrement
+	return b
+}
+
+func (b *AuctionBuilder) WithBuyNowPrice(buyNowPrice float64) *AuctionBuilder {
+	b.auction.BuyNowPrice = buyNowPrice
+	return b
+}
+
+func (b *AuctionBuilder) WithMaxRelists(maxRelists int) *AuctionBuilder {
+	b.auction.RelistPolicy = auction_entity.RelistPolicy{MaxRelists: maxRelists}
+	return b
+}
+
+func (b *AuctionBuilder) AsDutch(pricing auction_entity.DutchPricing) *AuctionBuilder {
+	b.auction.Type = auction_entity.Dutch
+	b.auction.DutchPricing = pricing
+	return b
+}
+
+func (b *AuctionBuilder) Build() *auction_entity.Auction {
+	auction := b.auction
+	return &auction
+}
+
+// BidBuilder starts from a valid bid placed now by a random user
+type BidBuilder struct {
+	bid bid_entity.Bid
+}
+
+func ABid() *BidBuilder {
+	return &BidBuilder{bid: bid_entity.Bid{
+		Id:        uuid.New().String(),
+		UserId:    uuid.New().String(),
+		AuctionId: uuid.New().String(),
+		Amount:    100,
+		Timestamp: time.Now(),
+	}}
+}
+
+func (b *BidBuilder) ForAuction(auctionId string) *BidBuilder {
+	b.bid.AuctionId = auctionId
+	return b
+}
+
+func (b *BidBuilder) WithUserId(userId string) *BidBuilder {
+	b.bid.UserId = userId
+	return b
+}
+
+func (b *BidBuilder) WithAmount(amount float64) *BidBuilder {
+	b.bid.Amount = amount
+	return b
+}
+
+func (b *BidBuilder) WithTimestamp(timestamp time.Time) *BidBuilder {
+	b.bid.Timestamp = timestamp
+	return b
+}
+
+func (b *BidBuilder) WithIdempotencyKey(idempotencyKey string) *BidBuilder {
+	b.bid.IdempotencyKey = idempotencyKey
+	return b
+}
+
+func (b *BidBuilder) Build() bid_entity.Bid {
+	return b.bid
+}
@@ -0,0 +1,119 @@
+package testhelpers
+
+import (
+	"auction_go/configuration/database/mongodb"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	tcmongodb "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MONGODB_TEST_URL points the tests at an already running MongoDB instead of
+// starting a container, for CI runners without Docker
+const MONGODB_TEST_URL = "MONGODB_TEST_URL"
+
+var (
+	client      *mongo.Client
+	clientErr   error
+	startClient sync.Once
+)
+
+// NewMongoDatabase returns an empty database with the indexes provisioned,
+// dropped once the test ends. Every call gets its own database, so tests and
+// packages can run in parallel against the one MongoDB started per test
+// binary. The test is skipped when no MongoDB can be reached
+func NewMongoDatabase(t testing.TB) *mongo.Database {
+	t.Helper()
+
+	startClient.Do(func() {
+		client, clientErr = connect()
+	})
+	if clientErr != nil {
+		t.Skipf("MongoDB is not available: %v", clientErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	databaseName := "test_" + strings.ReplaceAll(uuid.New().String(), "-", "")[:16]
+	database := client.Database(databaseName)
+
+	for collection := range mongodb.Indexes {
+		if err := mongodb.EnsureIndexes(ctx, database, collection); err != nil {
+			t.Fatalf("Error trying to create indexes on %s: %v", collection, err)
+		}
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		database.Drop(ctx)
+	})
+
+	return database
+}
+
+// connect uses MONGODB_TEST_URL when set, otherwise starts a throwaway
+// single node replica set that the testcontainers reaper removes when the
+// binary exits. Either way the server has to take transactions
+func connect() (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	uri := os.Getenv(MONGODB_TEST_URL)
+	if uri == "" {
+		containerUri, err := startContainer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uri = containerUri
+	}
+
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mongoClient.Ping(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	if err := mongodb.CheckTransactions(ctx, mongoClient.Database("admin")); err != nil {
+		return nil, err
+	}
+
+	return mongoClient, nil
+}
+
+// startContainer recovers from the panic testcontainers raises on hosts
+// without a Docker daemon, which should skip the tests rather than crash them
+func startContainer(ctx context.Context) (uri string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("starting MongoDB container: %v", recovered)
+		}
+	}()
+
+	container, err := tcmongodb.Run(ctx, "mongo:7", tcmongodb.WithReplicaSet("rs0"))
+	if err != nil {
+		return "", err
+	}
+
+	uri, err = container.ConnectionString(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The member is known by its container address, which the host may not
+	// reach
+	return uri + "/?directConnection=true", nil
+}
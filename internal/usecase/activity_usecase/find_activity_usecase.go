@@ -0,0 +1,72 @@
+package activity_usecase
+
+import (
+	"auction_go/internal/entity/activity_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type ActivityOutputDTO struct {
+	Type        activity_entity.ActivityType `json:"type"`
+	ReferenceId string                       `json:"reference_id"`
+	AuctionId   string                       `json:"auction_id"`
+	Amount      float64                      `json:"amount,omitempty"`
+	Timestamp   time.Time                    `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type ActivityPageOutputDTO struct {
+	Activity []ActivityOutputDTO `json:"activity"`
+	Page     int64               `json:"page"`
+	PageSize int64               `json:"page_size"`
+	Total    int64               `json:"total"`
+}
+
+type ActivityUseCase struct {
+	activityRepository activity_entity.ActivityRepositoryInterface
+}
+
+func NewActivityUseCase(
+	activityRepository activity_entity.ActivityRepositoryInterface) ActivityUseCaseInterface {
+	return &ActivityUseCase{
+		activityRepository: activityRepository,
+	}
+}
+
+type ActivityUseCaseInterface interface {
+	FindActivityByUserId(
+		ctx context.Context,
+		userId string,
+		page, pageSize int64) (*ActivityPageOutputDTO, *internal_error.InternalError)
+}
+
+// FindActivityByUserId returns the user's timeline of auctions created, bids
+// placed and auctions won, newest first
+func (au *ActivityUseCase) FindActivityByUserId(
+	ctx context.Context,
+	userId string,
+	page, pageSize int64) (*ActivityPageOutputDTO, *internal_error.InternalError) {
+	activities, total, err := au.activityRepository.FindActivityByUserId(
+		ctx, userId, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]ActivityOutputDTO, 0, len(activities))
+	for _, value := range activities {
+		output = append(output, ActivityOutputDTO{
+			Type:        value.Type,
+			ReferenceId: value.ReferenceId,
+			AuctionId:   value.AuctionId,
+			Amount:      value.Amount,
+			Timestamp:   value.Timestamp,
+		})
+	}
+
+	return &ActivityPageOutputDTO{
+		Activity: output,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
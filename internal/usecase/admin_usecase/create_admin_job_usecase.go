@@ -0,0 +1,282 @@
+package admin_usecase
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/entity/announcement_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const bulkStatusBatchSize = 100
+
+type BulkStatusInputDTO struct {
+	Action        string     `json:"action" binding:"required,oneof=close cancel"`
+	Category      string     `json:"category"`
+	SellerId      string     `json:"seller_id" binding:"omitempty,uuid"`
+	CreatedBefore *time.Time `json:"created_before"`
+	Reason        string     `json:"reason" binding:"required,max=500"`
+	RequestedBy   string     `json:"requested_by" binding:"required"`
+}
+
+type BulkStatusFilterOutputDTO struct {
+	Category      string     `json:"category,omitempty"`
+	SellerId      string     `json:"seller_id,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+type BulkStatusJobOutputDTO struct {
+	Id          string                    `json:"id"`
+	Action      string                    `json:"action"`
+	Filter      BulkStatusFilterOutputDTO `json:"filter"`
+	Reason      string                    `json:"reason"`
+	RequestedBy string                    `json:"requested_by"`
+	Status      JobStatus                 `json:"status"`
+	Total       int64                     `json:"total"`
+	Processed   int64                     `json:"processed"`
+	Changed     int64                     `json:"changed"`
+	Error       string                    `json:"error,omitempty"`
+	Timestamp   time.Time                 `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	FinishedAt  *time.Time                `json:"finished_at,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+type EndTimeInputDTO struct {
+	EndTime     time.Time `json:"end_time" binding:"required"`
+	Reason      string    `json:"reason" binding:"required,max=300"`
+	RequestedBy string    `json:"requested_by" binding:"required"`
+}
+
+type AuditEntryOutputDTO struct {
+	Id              string     `json:"id"`
+	JobId           string     `json:"job_id,omitempty"`
+	AuctionId       string     `json:"auction_id"`
+	Action          string     `json:"action"`
+	PreviousStatus  int64      `json:"previous_status"`
+	NewStatus       int64      `json:"new_status"`
+	PreviousEndTime *time.Time `json:"previous_end_time,omitempty" time_format:"2006-01-02 15:04:05"`
+	NewEndTime      *time.Time `json:"new_end_time,omitempty" time_format:"2006-01-02 15:04:05"`
+	Reason          string     `json:"reason"`
+	RequestedBy     string     `json:"requested_by"`
+	Timestamp       time.Time  `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type JobStatus int64
+
+type AdminUseCase struct {
+	adminRepository        admin_entity.AdminRepositoryInterface
+	auctionRepository      auction_entity.AuctionRepositoryInterface
+	announcementRepository announcement_entity.AnnouncementRepositoryInterface
+	checkoutRepository     checkout_entity.CheckoutRepositoryInterface
+	historyRepository      history_entity.HistoryRepositoryInterface
+}
+
+func NewAdminUseCase(
+	adminRepository admin_entity.AdminRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	announcementRepository announcement_entity.AnnouncementRepositoryInterface,
+	checkoutRepository checkout_entity.CheckoutRepositoryInterface,
+	historyRepository history_entity.HistoryRepositoryInterface) AdminUseCaseInterface {
+	return &AdminUseCase{
+		adminRepository:        adminRepository,
+		auctionRepository:      auctionRepository,
+		announcementRepository: announcementRepository,
+		checkoutRepository:     checkoutRepository,
+		historyRepository:      historyRepository,
+	}
+}
+
+type AdminUseCaseInterface interface {
+	StartBulkStatusJob(
+		ctx context.Context,
+		bulkStatusInput BulkStatusInputDTO) (*BulkStatusJobOutputDTO, *internal_error.InternalError)
+
+	FindBulkStatusJobById(
+		ctx context.Context, id string) (*BulkStatusJobOutputDTO, *internal_error.InternalError)
+
+	FindAuditEntriesByJobId(
+		ctx context.Context, jobId string) ([]AuditEntryOutputDTO, *internal_error.InternalError)
+
+	AdjustAuctionEndTime(
+		ctx context.Context,
+		auctionId string,
+		endTimeInput EndTimeInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError)
+
+	FindAuditEntriesByAuctionId(
+		ctx context.Context, auctionId string) ([]AuditEntryOutputDTO, *internal_error.InternalError)
+
+	PauseAuction(
+		ctx context.Context,
+		auctionId string,
+		pauseInput PauseInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError)
+
+	ResumeAuction(
+		ctx context.Context,
+		auctionId string,
+		resumeInput PauseInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError)
+
+	ReopenAuction(
+		ctx context.Context,
+		auctionId string,
+		endTimeInput EndTimeInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError)
+
+	ReplayAuction(
+		ctx context.Context,
+		auctionId string,
+		at time.Time) (*AuctionReplayOutputDTO, *internal_error.InternalError)
+}
+
+// StartBulkStatusJob stores the job and returns right away, the auctions are
+// processed in the background and the job document tracks the progress
+func (au *AdminUseCase) StartBulkStatusJob(
+	ctx context.Context,
+	bulkStatusInput BulkStatusInputDTO) (*BulkStatusJobOutputDTO, *internal_error.InternalError) {
+	filter := admin_entity.BulkStatusFilter{
+		Category: bulkStatusInput.Category,
+		SellerId: bulkStatusInput.SellerId,
+	}
+	if bulkStatusInput.CreatedBefore != nil {
+		filter.CreatedBefore = *bulkStatusInput.CreatedBefore
+	}
+
+	job, err := admin_entity.CreateBulkStatusJob(
+		bulkStatusInput.Action, filter, bulkStatusInput.Reason, bulkStatusInput.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.adminRepository.CreateBulkStatusJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Admin bulk status job created",
+		zap.String("jobId", job.Id),
+		zap.String("action", job.Action),
+		zap.String("requestedBy", job.RequestedBy))
+
+	jobOutput := toBulkStatusJobOutputDTO(job)
+	go au.runBulkStatusJob(job)
+
+	return jobOutput, nil
+}
+
+func (au *AdminUseCase) runBulkStatusJob(job *admin_entity.BulkStatusJob) {
+	ctx := context.Background()
+
+	auctionIds, err := au.auctionRepository.FindActiveAuctionIds(
+		ctx, job.Filter.Category, job.Filter.SellerId, job.Filter.CreatedBefore)
+	if err != nil {
+		au.finishBulkStatusJob(ctx, job, err)
+		return
+	}
+
+	job.Status = admin_entity.Running
+	job.Total = int64(len(auctionIds))
+	if err := au.adminRepository.UpdateBulkStatusJob(ctx, job); err != nil {
+		logger.Error("Error trying to report admin job progress", err, zap.String("jobId", job.Id))
+	}
+
+	targetStatus := job.TargetStatus()
+	for start := 0; start < len(auctionIds); start += bulkStatusBatchSize {
+		end := min(start+bulkStatusBatchSize, len(auctionIds))
+
+		var auditEntries []admin_entity.AuditEntry
+		for _, auctionId := range auctionIds[start:end] {
+			changed, err := au.auctionRepository.UpdateAuctionStatus(
+				ctx, auctionId, auction_entity.Active, targetStatus)
+			if err != nil {
+				au.adminRepository.CreateAuditEntries(ctx, auditEntries)
+				au.finishBulkStatusJob(ctx, job, err)
+				return
+			}
+
+			job.Processed++
+			if !changed {
+				continue
+			}
+
+			job.Changed++
+			auditEntries = append(auditEntries, admin_entity.AuditEntry{
+				Id:             uuid.New().String(),
+				JobId:          job.Id,
+				AuctionId:      auctionId,
+				Action:         job.Action,
+				PreviousStatus: auction_entity.Active,
+				NewStatus:      targetStatus,
+				Reason:         job.Reason,
+				RequestedBy:    job.RequestedBy,
+				Timestamp:      time.Now(),
+			})
+		}
+
+		if err := au.adminRepository.CreateAuditEntries(ctx, auditEntries); err != nil {
+			au.finishBulkStatusJob(ctx, job, err)
+			return
+		}
+
+		if err := au.adminRepository.UpdateBulkStatusJob(ctx, job); err != nil {
+			logger.Error("Error trying to report admin job progress", err, zap.String("jobId", job.Id))
+		}
+	}
+
+	au.finishBulkStatusJob(ctx, job, nil)
+}
+
+func (au *AdminUseCase) finishBulkStatusJob(
+	ctx context.Context,
+	job *admin_entity.BulkStatusJob,
+	jobErr *internal_error.InternalError) {
+	job.Status = admin_entity.Finished
+	if jobErr != nil {
+		job.Status = admin_entity.Failed
+		job.Error = jobErr.Message
+	}
+	job.FinishedAt = time.Now()
+
+	if err := au.adminRepository.UpdateBulkStatusJob(ctx, job); err != nil {
+		logger.Error("Error trying to finish admin job", err, zap.String("jobId", job.Id))
+		return
+	}
+
+	logger.Info("Admin bulk status job finished",
+		zap.String("jobId", job.Id),
+		zap.Int64("processed", job.Processed),
+		zap.Int64("changed", job.Changed))
+}
+
+func toBulkStatusJobOutputDTO(job *admin_entity.BulkStatusJob) *BulkStatusJobOutputDTO {
+	jobOutput := &BulkStatusJobOutputDTO{
+		Id:     job.Id,
+		Action: job.Action,
+		Filter: BulkStatusFilterOutputDTO{
+			Category: job.Filter.Category,
+			SellerId: job.Filter.SellerId,
+		},
+		Reason:      job.Reason,
+		RequestedBy: job.RequestedBy,
+		Status:      JobStatus(job.Status),
+		Total:       job.Total,
+		Processed:   job.Processed,
+		Changed:     job.Changed,
+		Error:       job.Error,
+		Timestamp:   job.Timestamp,
+	}
+
+	if !job.Filter.CreatedBefore.IsZero() {
+		createdBefore := job.Filter.CreatedBefore
+		jobOutput.Filter.CreatedBefore = &createdBefore
+	}
+
+	if !job.FinishedAt.IsZero() {
+		finishedAt := job.FinishedAt
+		jobOutput.FinishedAt = &finishedAt
+	}
+
+	return jobOutput
+}
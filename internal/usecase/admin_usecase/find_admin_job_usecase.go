@@ -0,0 +1,76 @@
+package admin_usecase
+
+import (
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+func (au *AdminUseCase) FindBulkStatusJobById(
+	ctx context.Context, id string) (*BulkStatusJobOutputDTO, *internal_error.InternalError) {
+	job, err := au.adminRepository.FindBulkStatusJobById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBulkStatusJobOutputDTO(job), nil
+}
+
+func (au *AdminUseCase) FindAuditEntriesByJobId(
+	ctx context.Context, jobId string) ([]AuditEntryOutputDTO, *internal_error.InternalError) {
+	if _, err := au.adminRepository.FindBulkStatusJobById(ctx, jobId); err != nil {
+		return nil, err
+	}
+
+	auditEntries, err := au.adminRepository.FindAuditEntriesByJobId(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAuditEntryOutputDTOs(auditEntries), nil
+}
+
+func (au *AdminUseCase) FindAuditEntriesByAuctionId(
+	ctx context.Context, auctionId string) ([]AuditEntryOutputDTO, *internal_error.InternalError) {
+	auditEntries, err := au.adminRepository.FindAuditEntriesByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAuditEntryOutputDTOs(auditEntries), nil
+}
+
+func toAuditEntryOutputDTOs(auditEntries []admin_entity.AuditEntry) []AuditEntryOutputDTO {
+	auditEntryOutputs := []AuditEntryOutputDTO{}
+	for _, auditEntry := range auditEntries {
+		auditEntryOutputs = append(auditEntryOutputs, *toAuditEntryOutputDTO(auditEntry))
+	}
+
+	return auditEntryOutputs
+}
+
+func toAuditEntryOutputDTO(auditEntry admin_entity.AuditEntry) *AuditEntryOutputDTO {
+	auditEntryOutput := &AuditEntryOutputDTO{
+		Id:             auditEntry.Id,
+		JobId:          auditEntry.JobId,
+		AuctionId:      auditEntry.AuctionId,
+		Action:         auditEntry.Action,
+		PreviousStatus: int64(auditEntry.PreviousStatus),
+		NewStatus:      int64(auditEntry.NewStatus),
+		Reason:         auditEntry.Reason,
+		RequestedBy:    auditEntry.RequestedBy,
+		Timestamp:      auditEntry.Timestamp,
+	}
+
+	if !auditEntry.PreviousEndTime.IsZero() {
+		previousEndTime := auditEntry.PreviousEndTime
+		auditEntryOutput.PreviousEndTime = &previousEndTime
+	}
+
+	if !auditEntry.NewEndTime.IsZero() {
+		newEndTime := auditEntry.NewEndTime
+		auditEntryOutput.NewEndTime = &newEndTime
+	}
+
+	return auditEntryOutput
+}
@@ -0,0 +1,67 @@
+package admin_usecase
+
+import (
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReopenAuction brings back an auction that was closed by mistake, it runs
+// until the given end time and its previous winner is dropped. Auctions
+// whose winner already started checking out stay closed
+func (au *AdminUseCase) ReopenAuction(
+	ctx context.Context,
+	auctionId string,
+	endTimeInput EndTimeInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError) {
+	endTime := time.Unix(endTimeInput.EndTime.Unix(), 0)
+	if !endTime.After(time.Now()) {
+		return nil, internal_error.NewBadRequestError("EndTime must be in the future")
+	}
+
+	auction, err := au.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != auction_entity.Completed {
+		return nil, internal_error.NewInvalidTransitionError("Only completed auctions can be reopened")
+	}
+
+	if _, err := au.checkoutRepository.FindCheckoutByAuctionId(ctx, auctionId); err == nil {
+		return nil, internal_error.NewBadRequestError("Auctions with a checkout can't be reopened")
+	} else if err.Err != "not_found" {
+		return nil, err
+	}
+
+	if err := au.auctionRepository.ReopenAuction(ctx, auctionId, endTime); err != nil {
+		return nil, err
+	}
+
+	auditEntry := admin_entity.AuditEntry{
+		Id:              uuid.New().String(),
+		AuctionId:       auctionId,
+		Action:          admin_entity.ActionReopen,
+		PreviousStatus:  auction.Status,
+		NewStatus:       auction_entity.Active,
+		PreviousEndTime: auction.EndTime,
+		NewEndTime:      endTime,
+		Reason:          endTimeInput.Reason,
+		RequestedBy:     endTimeInput.RequestedBy,
+		Timestamp:       time.Now(),
+	}
+
+	if err := au.adminRepository.CreateAuditEntries(ctx, []admin_entity.AuditEntry{auditEntry}); err != nil {
+		return nil, err
+	}
+
+	au.announce(ctx, auctionId, fmt.Sprintf(
+		"This auction was reopened and now ends at %s: %s",
+		endTime.UTC().Format("2006-01-02 15:04:05 UTC"), endTimeInput.Reason))
+
+	return toAuditEntryOutputDTO(auditEntry), nil
+}
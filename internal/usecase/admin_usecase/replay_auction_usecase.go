@@ -0,0 +1,124 @@
+package admin_usecase
+
+import (
+	"auction_go/internal/entity/history_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+// AuctionReplayOutputDTO is the auction as it stood at At, along with the
+// events that led there
+type AuctionReplayOutputDTO struct {
+	AuctionId string                  `json:"auction_id"`
+	At        time.Time               `json:"at" time_format:"2006-01-02 15:04:05"`
+	State     AuctionStateOutputDTO   `json:"state"`
+	Events    []AuctionEventOutputDTO `json:"events"`
+}
+
+type AuctionStateOutputDTO struct {
+	SellerId           string    `json:"seller_id"`
+	ProductName        string    `json:"product_name"`
+	Category           string    `json:"category"`
+	Type               int64     `json:"type"`
+	Status             int64     `json:"status"`
+	StartTime          time.Time `json:"start_time" time_format:"2006-01-02 15:04:05"`
+	EndTime            time.Time `json:"end_time" time_format:"2006-01-02 15:04:05"`
+	ReservePrice       float64   `json:"reserve_price,omitempty"`
+	BuyNowPrice        float64   `json:"buy_now_price,omitempty"`
+	DutchPrice         float64   `json:"dutch_price,omitempty"`
+	CurrentPrice       float64   `json:"current_price"`
+	LeadingUserId      string    `json:"leading_user_id,omitempty"`
+	BidCount           int64     `json:"bid_count"`
+	WinnerUserId       string    `json:"winner_user_id,omitempty"`
+	WinningAmount      float64   `json:"winning_amount,omitempty"`
+	CancellationReason string    `json:"cancellation_reason,omitempty"`
+	LastEventId        string    `json:"last_event_id"`
+}
+
+type AuctionEventOutputDTO struct {
+	Id        string     `json:"id"`
+	Type      string     `json:"type"`
+	Timestamp time.Time  `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Status    *int64     `json:"status,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty" time_format:"2006-01-02 15:04:05"`
+	BidId     string     `json:"bid_id,omitempty"`
+	UserId    string     `json:"user_id,omitempty"`
+	Amount    float64    `json:"amount,omitempty"`
+}
+
+// ReplayAuction rebuilds the auction as it stood at the given time from its
+// event log, to settle disputes over what happened when. A zero time
+// replays the whole log
+func (au *AdminUseCase) ReplayAuction(
+	ctx context.Context,
+	auctionId string,
+	at time.Time) (*AuctionReplayOutputDTO, *internal_error.InternalError) {
+	replay, err := au.historyRepository.ReplayAuction(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if at.IsZero() {
+		at = replay.Events[len(replay.Events)-1].Timestamp
+	}
+
+	state, created := replay.StateAt(at)
+	if !created {
+		return nil, internal_error.NewNotFoundError("The auction didn't exist yet at this time")
+	}
+
+	replayOutput := &AuctionReplayOutputDTO{
+		AuctionId: auctionId,
+		At:        at,
+		State: AuctionStateOutputDTO{
+			SellerId:           state.Auction.SellerId,
+			ProductName:        state.Auction.ProductName,
+			Category:           state.Auction.Category,
+			Type:               int64(state.Auction.Type),
+			Status:             int64(state.Auction.Status),
+			StartTime:          state.Auction.StartTime,
+			EndTime:            state.Auction.EndTime,
+			ReservePrice:       state.Auction.ReservePrice,
+			BuyNowPrice:        state.Auction.BuyNowPrice,
+			DutchPrice:         state.Auction.DutchPrice,
+			CurrentPrice:       state.CurrentPrice,
+			LeadingUserId:      state.LeadingUserId,
+			BidCount:           state.BidCount,
+			WinnerUserId:       state.Auction.WinnerUserId,
+			WinningAmount:      state.Auction.WinningAmount,
+			CancellationReason: state.Auction.CancellationReason,
+			LastEventId:        state.LastEventId,
+		},
+		Events: []AuctionEventOutputDTO{},
+	}
+
+	for _, event := range replay.EventsUntil(at) {
+		replayOutput.Events = append(replayOutput.Events, toAuctionEventOutputDTO(event))
+	}
+
+	return replayOutput, nil
+}
+
+func toAuctionEventOutputDTO(event history_entity.AuctionEvent) AuctionEventOutputDTO {
+	eventOutput := AuctionEventOutputDTO{
+		Id:        event.Id,
+		Type:      string(event.Type),
+		Timestamp: event.Timestamp,
+	}
+
+	if event.Auction != nil {
+		status := int64(event.Auction.Status)
+		endTime := event.Auction.EndTime
+		eventOutput.Status = &status
+		eventOutput.EndTime = &endTime
+	}
+
+	if event.Bid != nil {
+		eventOutput.BidId = event.Bid.BidId
+		eventOutput.UserId = event.Bid.UserId
+		eventOutput.Amount = event.Bid.Amount
+	}
+
+	return eventOutput
+}
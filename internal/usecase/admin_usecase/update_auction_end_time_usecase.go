@@ -0,0 +1,60 @@
+package admin_usecase
+
+import (
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdjustAuctionEndTime moves the end of a running auction in either
+// direction, the change is audited and announced on the auction feed
+func (au *AdminUseCase) AdjustAuctionEndTime(
+	ctx context.Context,
+	auctionId string,
+	endTimeInput EndTimeInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError) {
+	endTime := time.Unix(endTimeInput.EndTime.Unix(), 0)
+	if !endTime.After(time.Now()) {
+		return nil, internal_error.NewBadRequestError("EndTime must be in the future")
+	}
+
+	auction, err := au.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != auction_entity.Active {
+		return nil, internal_error.NewBadRequestError("Only active auctions can have their end time adjusted")
+	}
+
+	if err := au.auctionRepository.UpdateAuctionEndTime(ctx, auctionId, endTime); err != nil {
+		return nil, err
+	}
+
+	auditEntry := admin_entity.AuditEntry{
+		Id:              uuid.New().String(),
+		AuctionId:       auctionId,
+		Action:          admin_entity.ActionAdjustEndTime,
+		PreviousStatus:  auction.Status,
+		NewStatus:       auction.Status,
+		PreviousEndTime: auction.EndTime,
+		NewEndTime:      endTime,
+		Reason:          endTimeInput.Reason,
+		RequestedBy:     endTimeInput.RequestedBy,
+		Timestamp:       time.Now(),
+	}
+
+	if err := au.adminRepository.CreateAuditEntries(ctx, []admin_entity.AuditEntry{auditEntry}); err != nil {
+		return nil, err
+	}
+
+	au.announce(ctx, auctionId, fmt.Sprintf(
+		"This auction now ends at %s: %s",
+		endTime.UTC().Format("2006-01-02 15:04:05 UTC"), endTimeInput.Reason))
+
+	return toAuditEntryOutputDTO(auditEntry), nil
+}
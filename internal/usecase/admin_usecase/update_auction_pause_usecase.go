@@ -0,0 +1,108 @@
+package admin_usecase
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/admin_entity"
+	"auction_go/internal/entity/announcement_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type PauseInputDTO struct {
+	Reason      string `json:"reason" binding:"required,max=300"`
+	RequestedBy string `json:"requested_by" binding:"required"`
+}
+
+// PauseAuction freezes a running auction, for instance while suspected fraud
+// is looked into. Bids are refused until it is resumed
+func (au *AdminUseCase) PauseAuction(
+	ctx context.Context,
+	auctionId string,
+	pauseInput PauseInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.auctionRepository.PauseAuction(ctx, auctionId); err != nil {
+		return nil, err
+	}
+
+	auditEntry := admin_entity.AuditEntry{
+		Id:              uuid.New().String(),
+		AuctionId:       auctionId,
+		Action:          admin_entity.ActionPause,
+		PreviousStatus:  auction.Status,
+		NewStatus:       auction_entity.Paused,
+		PreviousEndTime: auction.EndTime,
+		NewEndTime:      auction.EndTime,
+		Reason:          pauseInput.Reason,
+		RequestedBy:     pauseInput.RequestedBy,
+		Timestamp:       time.Now(),
+	}
+
+	if err := au.adminRepository.CreateAuditEntries(ctx, []admin_entity.AuditEntry{auditEntry}); err != nil {
+		return nil, err
+	}
+
+	au.announce(ctx, auctionId, fmt.Sprintf("This auction is paused: %s", pauseInput.Reason))
+
+	return toAuditEntryOutputDTO(auditEntry), nil
+}
+
+// ResumeAuction reopens a paused auction with the time it had left when it
+// was paused
+func (au *AdminUseCase) ResumeAuction(
+	ctx context.Context,
+	auctionId string,
+	resumeInput PauseInputDTO) (*AuditEntryOutputDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime, err := au.auctionRepository.ResumeAuction(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	auditEntry := admin_entity.AuditEntry{
+		Id:              uuid.New().String(),
+		AuctionId:       auctionId,
+		Action:          admin_entity.ActionResume,
+		PreviousStatus:  auction.Status,
+		NewStatus:       auction_entity.Active,
+		PreviousEndTime: auction.EndTime,
+		NewEndTime:      endTime,
+		Reason:          resumeInput.Reason,
+		RequestedBy:     resumeInput.RequestedBy,
+		Timestamp:       time.Now(),
+	}
+
+	if err := au.adminRepository.CreateAuditEntries(ctx, []admin_entity.AuditEntry{auditEntry}); err != nil {
+		return nil, err
+	}
+
+	au.announce(ctx, auctionId, fmt.Sprintf(
+		"This auction is running again and now ends at %s",
+		endTime.UTC().Format("2006-01-02 15:04:05 UTC")))
+
+	return toAuditEntryOutputDTO(auditEntry), nil
+}
+
+// Announcements are best effort, the change itself is already stored
+func (au *AdminUseCase) announce(ctx context.Context, auctionId, message string) {
+	announcement, err := announcement_entity.CreateAnnouncement(auctionId, message, "")
+	if err == nil {
+		err = au.announcementRepository.CreateAnnouncement(ctx, announcement)
+	}
+	if err != nil {
+		logger.Error("Error trying to announce auction change", err, zap.String("auctionId", auctionId))
+	}
+}
@@ -0,0 +1,95 @@
+package announcement_usecase
+
+import (
+	"auction_go/internal/entity/announcement_entity"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type AnnouncementInputDTO struct {
+	// SellerId is the authenticated user, it isn't read from the body
+	SellerId   string `json:"-"`
+	Message    string `json:"message" binding:"required,min=3,max=500"`
+	ReplacesId string `json:"replaces_id" binding:"omitempty,uuid"`
+}
+
+type AnnouncementOutputDTO struct {
+	Id         string    `json:"id"`
+	AuctionId  string    `json:"auction_id"`
+	Message    string    `json:"message"`
+	ReplacesId string    `json:"replaces_id,omitempty"`
+	Timestamp  time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type AnnouncementUseCase struct {
+	announcementRepository announcement_entity.AnnouncementRepositoryInterface
+	auctionRepository      auction_entity.AuctionRepositoryInterface
+}
+
+func NewAnnouncementUseCase(
+	announcementRepository announcement_entity.AnnouncementRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface) AnnouncementUseCaseInterface {
+	return &AnnouncementUseCase{
+		announcementRepository: announcementRepository,
+		auctionRepository:      auctionRepository,
+	}
+}
+
+type AnnouncementUseCaseInterface interface {
+	CreateAnnouncement(
+		ctx context.Context,
+		auctionId string,
+		announcementInput AnnouncementInputDTO) (*AnnouncementOutputDTO, *internal_error.InternalError)
+
+	FindAnnouncementsByAuctionId(
+		ctx context.Context, auctionId string) ([]AnnouncementOutputDTO, *internal_error.InternalError)
+}
+
+func (au *AnnouncementUseCase) CreateAnnouncement(
+	ctx context.Context,
+	auctionId string,
+	announcementInput AnnouncementInputDTO) (*AnnouncementOutputDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerId == "" || auction.SellerId != announcementInput.SellerId {
+		return nil, internal_error.NewForbiddenError("Only the auction seller can post announcements")
+	}
+
+	if auction.Status != auction_entity.Active {
+		return nil, internal_error.NewBadRequestError("Announcements can only be posted on active auctions")
+	}
+
+	if announcementInput.ReplacesId != "" {
+		replaced, err := au.announcementRepository.FindAnnouncementById(ctx, announcementInput.ReplacesId)
+		if err != nil {
+			return nil, err
+		}
+
+		if replaced.AuctionId != auctionId {
+			return nil, internal_error.NewBadRequestError("Replaced announcement belongs to another auction")
+		}
+	}
+
+	announcement, err := announcement_entity.CreateAnnouncement(
+		auctionId, announcementInput.Message, announcementInput.ReplacesId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.announcementRepository.CreateAnnouncement(ctx, announcement); err != nil {
+		return nil, err
+	}
+
+	return &AnnouncementOutputDTO{
+		Id:         announcement.Id,
+		AuctionId:  announcement.AuctionId,
+		Message:    announcement.Message,
+		ReplacesId: announcement.ReplacesId,
+		Timestamp:  announcement.Timestamp,
+	}, nil
+}
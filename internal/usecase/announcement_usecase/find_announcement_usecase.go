@@ -0,0 +1,27 @@
+package announcement_usecase
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+func (au *AnnouncementUseCase) FindAnnouncementsByAuctionId(
+	ctx context.Context, auctionId string) ([]AnnouncementOutputDTO, *internal_error.InternalError) {
+	announcements, err := au.announcementRepository.FindAnnouncementsByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	announcementOutputs := []AnnouncementOutputDTO{}
+	for _, announcement := range announcements {
+		announcementOutputs = append(announcementOutputs, AnnouncementOutputDTO{
+			Id:         announcement.Id,
+			AuctionId:  announcement.AuctionId,
+			Message:    announcement.Message,
+			ReplacesId: announcement.ReplacesId,
+			Timestamp:  announcement.Timestamp,
+		})
+	}
+
+	return announcementOutputs, nil
+}
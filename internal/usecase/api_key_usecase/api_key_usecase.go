@@ -0,0 +1,163 @@
+package api_key_usecase
+
+import (
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+)
+
+type ApiKeyInputDTO struct {
+	UserId string                 `json:"-"`
+	Name   string                 `json:"name" binding:"required,max=100"`
+	Scopes []api_key_entity.Scope `json:"scopes" binding:"required,min=1,dive,oneof=read bid"`
+}
+
+type ApiKeyOutputDTO struct {
+	Id        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Prefix    string                 `json:"prefix"`
+	Scopes    []api_key_entity.Scope `json:"scopes"`
+	CreatedAt time.Time              `json:"created_at" time_format:"2006-01-02 15:04:05"`
+	RotatedAt *time.Time             `json:"rotated_at,omitempty" time_format:"2006-01-02 15:04:05"`
+
+	// Key is only returned when the key is created or rotated, it can't be
+	// read back afterwards
+	Key string `json:"key,omitempty"`
+}
+
+type ApiKeyUseCase struct {
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface
+}
+
+func NewApiKeyUseCase(apiKeyRepository api_key_entity.ApiKeyRepositoryInterface) ApiKeyUseCaseInterface {
+	return &ApiKeyUseCase{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+type ApiKeyUseCaseInterface interface {
+	CreateApiKey(
+		ctx context.Context,
+		apiKeyInput ApiKeyInputDTO) (*ApiKeyOutputDTO, *internal_error.InternalError)
+
+	ListApiKeys(
+		ctx context.Context, userId string) ([]ApiKeyOutputDTO, *internal_error.InternalError)
+
+	RotateApiKey(
+		ctx context.Context, userId, keyId string) (*ApiKeyOutputDTO, *internal_error.InternalError)
+
+	DeleteApiKey(
+		ctx context.Context, userId, keyId string) *internal_error.InternalError
+
+	// Authenticate returns the user the key belongs to, as long as the key
+	// was granted scope
+	Authenticate(
+		ctx context.Context, key string, scope api_key_entity.Scope) (string, *internal_error.InternalError)
+}
+
+// CreateApiKey gives the user a new key, up to MaxApiKeys per user
+func (au *ApiKeyUseCase) CreateApiKey(
+	ctx context.Context,
+	apiKeyInput ApiKeyInputDTO) (*ApiKeyOutputDTO, *internal_error.InternalError) {
+	apiKey, secret, err := api_key_entity.CreateApiKey(apiKeyInput.UserId, apiKeyInput.Name, apiKeyInput.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := au.apiKeyRepository.CountApiKeysByUserId(ctx, apiKey.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if count >= api_key_entity.MaxApiKeys {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("A user holds at most %d API keys", api_key_entity.MaxApiKeys))
+	}
+
+	if err := au.apiKeyRepository.CreateApiKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	output := toApiKeyOutputDTO(*apiKey)
+	output.Key = secret
+	return output, nil
+}
+
+func (au *ApiKeyUseCase) ListApiKeys(
+	ctx context.Context, userId string) ([]ApiKeyOutputDTO, *internal_error.InternalError) {
+	apiKeys, err := au.apiKeyRepository.FindApiKeysByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]ApiKeyOutputDTO, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		output = append(output, *toApiKeyOutputDTO(apiKey))
+	}
+
+	return output, nil
+}
+
+// RotateApiKey replaces the key's secret, keeping its name and scopes. The
+// old secret stops working right away
+func (au *ApiKeyUseCase) RotateApiKey(
+	ctx context.Context, userId, keyId string) (*ApiKeyOutputDTO, *internal_error.InternalError) {
+	apiKey, err := au.apiKeyRepository.FindApiKeyById(ctx, userId, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := apiKey.Rotate(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.apiKeyRepository.RotateApiKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	output := toApiKeyOutputDTO(*apiKey)
+	output.Key = secret
+	return output, nil
+}
+
+func (au *ApiKeyUseCase) DeleteApiKey(
+	ctx context.Context, userId, keyId string) *internal_error.InternalError {
+	return au.apiKeyRepository.DeleteApiKey(ctx, userId, keyId)
+}
+
+// Authenticate looks the key up by its hash, unknown keys are unauthorized
+// and keys lacking the scope forbidden
+func (au *ApiKeyUseCase) Authenticate(
+	ctx context.Context, key string, scope api_key_entity.Scope) (string, *internal_error.InternalError) {
+	apiKey, err := au.apiKeyRepository.FindApiKeyByHash(ctx, api_key_entity.HashKey(key))
+	if err != nil {
+		if err.Err == "not_found" {
+			return "", internal_error.NewUnauthorizedError("Invalid API key")
+		}
+		return "", err
+	}
+
+	if !apiKey.Allows(scope) {
+		return "", internal_error.NewForbiddenError(
+			fmt.Sprintf("The API key is not allowed to %s", scope))
+	}
+
+	return apiKey.UserId, nil
+}
+
+func toApiKeyOutputDTO(apiKey api_key_entity.ApiKey) *ApiKeyOutputDTO {
+	output := &ApiKeyOutputDTO{
+		Id:        apiKey.Id,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if !apiKey.RotatedAt.IsZero() {
+		output.RotatedAt = &apiKey.RotatedAt
+	}
+
+	return output
+}
@@ -0,0 +1,247 @@
+package api_key_usecase
+
+import (
+	"auction_go/internal/entity/api_key_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// apiKeyRepository keeps the keys in memory, the use case only needs what
+// the interface promises
+type apiKeyRepository struct {
+	keys map[string]api_key_entity.ApiKey
+}
+
+func (r *apiKeyRepository) CreateApiKey(
+	ctx context.Context, apiKey *api_key_entity.ApiKey) *internal_error.InternalError {
+	r.keys[apiKey.Id] = *apiKey
+	return nil
+}
+
+func (r *apiKeyRepository) FindApiKeysByUserId(
+	ctx context.Context, userId string) ([]api_key_entity.ApiKey, *internal_error.InternalError) {
+	var apiKeys []api_key_entity.ApiKey
+	for _, apiKey := range r.keys {
+		if apiKey.UserId == userId {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) FindApiKeyById(
+	ctx context.Context, userId, keyId string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	apiKey, ok := r.keys[keyId]
+	if !ok || apiKey.UserId != userId {
+		return nil, internal_error.NewNotFoundError("API key not found")
+	}
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) FindApiKeyByHash(
+	ctx context.Context, hash string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	for _, apiKey := range r.keys {
+		if apiKey.Hash == hash {
+			return &apiKey, nil
+		}
+	}
+	return nil, internal_error.NewNotFoundError("API key not found")
+}
+
+func (r *apiKeyRepository) CountApiKeysByUserId(
+	ctx context.Context, userId string) (int64, *internal_error.InternalError) {
+	apiKeys, _ := r.FindApiKeysByUserId(ctx, userId)
+	return int64(len(apiKeys)), nil
+}
+
+func (r *apiKeyRepository) RotateApiKey(
+	ctx context.Context, apiKey *api_key_entity.ApiKey) *internal_error.InternalError {
+	r.keys[apiKey.Id] = *apiKey
+	return nil
+}
+
+func (r *apiKeyRepository) DeleteApiKey(
+	ctx context.Context, userId, keyId string) *internal_error.InternalError {
+	if _, err := r.FindApiKeyById(ctx, userId, keyId); err != nil {
+		return err
+	}
+	delete(r.keys, keyId)
+	return nil
+}
+
+type ApiKeyUseCaseSuite struct {
+	suite.Suite
+	repo    *apiKeyRepository
+	useCase ApiKeyUseCaseInterface
+}
+
+func (suite *ApiKeyUseCaseSuite) SetupTest() {
+	suite.repo = &apiKeyRepository{keys: make(map[string]api_key_entity.ApiKey)}
+	suite.useCase = NewApiKeyUseCase(suite.repo)
+}
+
+func (suite *ApiKeyUseCaseSuite) createApiKey(userId string, scopes ...api_key_entity.Scope) *ApiKeyOutputDTO {
+	output, err := suite.useCase.CreateApiKey(context.Background(), ApiKeyInputDTO{
+		UserId: userId,
+		Name:   "integration",
+		Scopes: scopes,
+	})
+	assert.Nil(suite.T(), err)
+	return output
+}
+
+func (suite *ApiKeyUseCaseSuite) TestOnlyTheHashIsStored() {
+	output := suite.createApiKey(uuid.New().String(), api_key_entity.ScopeRead)
+
+	stored := suite.repo.keys[output.Id]
+	assert.NotEmpty(suite.T(), output.Key)
+	assert.Equal(suite.T(), api_key_entity.HashKey(output.Key), stored.Hash)
+	assert.NotContains(suite.T(), stored.Hash, output.Key)
+	assert.Equal(suite.T(), output.Key[:len(output.Prefix)], output.Prefix)
+
+	// The key can't be read back
+	listed, err := suite.useCase.ListApiKeys(context.Background(), stored.UserId)
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), listed, 1)
+	assert.Empty(suite.T(), listed[0].Key)
+}
+
+func (suite *ApiKeyUseCaseSuite) TestAuthenticateChecksTheScope() {
+	userId := uuid.New().String()
+	readKey := suite.createApiKey(userId, api_key_entity.ScopeRead)
+	bidKey := suite.createApiKey(userId, api_key_entity.ScopeBid)
+
+	cases := []struct {
+		name  string
+		key   string
+		scope api_key_entity.Scope
+		err   string
+	}{
+		{name: "read key reads", key: readKey.Key, scope: api_key_entity.ScopeRead},
+		{name: "read key can't bid", key: readKey.Key, scope: api_key_entity.ScopeBid, err: "forbidden"},
+		{name: "bid key bids", key: bidKey.Key, scope: api_key_entity.ScopeBid},
+		{name: "bid key reads", key: bidKey.Key, scope: api_key_entity.ScopeRead},
+		{name: "unknown key", key: "ak_unknown", scope: api_key_entity.ScopeRead, err: "unauthorized"},
+		{name: "prefix alone", key: readKey.Prefix, scope: api_key_entity.ScopeRead, err: "unauthorized"},
+		{name: "no key", key: "", scope: api_key_entity.ScopeRead, err: "unauthorized"},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			authenticated, err := suite.useCase.Authenticate(context.Background(), tc.key, tc.scope)
+			if tc.err != "" {
+				assert.NotNil(suite.T(), err)
+				assert.Equal(suite.T(), tc.err, err.Err)
+				assert.Empty(suite.T(), authenticated)
+				return
+			}
+			assert.Nil(suite.T(), err)
+			assert.Equal(suite.T(), userId, authenticated)
+		})
+	}
+}
+
+func (suite *ApiKeyUseCaseSuite) TestUsersHoldAtMostTenKeys() {
+	userId := uuid.New().String()
+	for i := 0; i < api_key_entity.MaxApiKeys; i++ {
+		suite.createApiKey(userId, api_key_entity.ScopeRead)
+	}
+
+	_, err := suite.useCase.CreateApiKey(context.Background(), ApiKeyInputDTO{
+		UserId: userId,
+		Name:   "one too many",
+		Scopes: []api_key_entity.Scope{api_key_entity.ScopeRead},
+	})
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "bad_request", err.Err)
+	assert.Equal(suite.T(), fmt.Sprintf("A user holds at most %d API keys", api_key_entity.MaxApiKeys), err.Message)
+
+	// Other users aren't counted
+	suite.createApiKey(uuid.New().String(), api_key_entity.ScopeRead)
+}
+
+func (suite *ApiKeyUseCaseSuite) TestInvalidKeysAreNotCreated() {
+	cases := []struct {
+		name  string
+		input ApiKeyInputDTO
+		field string
+	}{
+		{
+			name:  "user isn't a UUID",
+			input: ApiKeyInputDTO{UserId: "user-1", Name: "integration", Scopes: []api_key_entity.Scope{api_key_entity.ScopeRead}},
+			field: "user_id",
+		},
+		{
+			name:  "blank name",
+			input: ApiKeyInputDTO{UserId: uuid.New().String(), Name: "  ", Scopes: []api_key_entity.Scope{api_key_entity.ScopeRead}},
+			field: "name",
+		},
+		{
+			name:  "no scope",
+			input: ApiKeyInputDTO{UserId: uuid.New().String(), Name: "integration"},
+			field: "scopes",
+		},
+		{
+			name:  "unknown scope",
+			input: ApiKeyInputDTO{UserId: uuid.New().String(), Name: "integration", Scopes: []api_key_entity.Scope{"admin"}},
+			field: "scopes",
+		},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.name, func() {
+			_, err := suite.useCase.CreateApiKey(context.Background(), tc.input)
+			assert.NotNil(suite.T(), err)
+			assert.Equal(suite.T(), "bad_request", err.Err)
+			assert.Len(suite.T(), err.Causes, 1)
+			assert.Equal(suite.T(), tc.field, err.Causes[0].Field)
+		})
+	}
+	assert.Empty(suite.T(), suite.repo.keys)
+}
+
+func (suite *ApiKeyUseCaseSuite) TestRotatedKeysStopWorking() {
+	userId := uuid.New().String()
+	created := suite.createApiKey(userId, api_key_entity.ScopeBid)
+
+	rotated, err := suite.useCase.RotateApiKey(context.Background(), userId, created.Id)
+	assert.Nil(suite.T(), err)
+	assert.NotEqual(suite.T(), created.Key, rotated.Key)
+	assert.NotNil(suite.T(), rotated.RotatedAt)
+	assert.Equal(suite.T(), created.Scopes, rotated.Scopes)
+
+	_, err = suite.useCase.Authenticate(context.Background(), created.Key, api_key_entity.ScopeRead)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "unauthorized", err.Err)
+
+	authenticated, err := suite.useCase.Authenticate(context.Background(), rotated.Key, api_key_entity.ScopeBid)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), userId, authenticated)
+
+	// Another user's key can't be rotated
+	_, err = suite.useCase.RotateApiKey(context.Background(), uuid.New().String(), created.Id)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "not_found", err.Err)
+}
+
+func (suite *ApiKeyUseCaseSuite) TestDeletedKeysStopWorking() {
+	userId := uuid.New().String()
+	created := suite.createApiKey(userId, api_key_entity.ScopeRead)
+
+	err := suite.useCase.DeleteApiKey(context.Background(), userId, created.Id)
+	assert.Nil(suite.T(), err)
+
+	_, err = suite.useCase.Authenticate(context.Background(), created.Key, api_key_entity.ScopeRead)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), "unauthorized", err.Err)
+}
+
+func TestApiKeyUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeyUseCaseSuite))
+}
@@ -0,0 +1,43 @@
+package auction_usecase
+
+import (
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/bid_usecase"
+	"context"
+	"time"
+)
+
+// BuyNow ends the auction right away at its buy now price, the buyer
+// becomes its winner
+func (au *AuctionUseCase) BuyNow(
+	ctx context.Context,
+	id string,
+	buyNowInput BuyNowInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.BuyNowPrice <= 0 {
+		return nil, internal_error.NewBadRequestError("This auction can't be bought now")
+	}
+
+	// Checked like a bid of the buy now price, so blocked users and the
+	// verification limits can't be got around by buying outright
+	buyNowBid := bid_entity.Bid{
+		UserId:    buyNowInput.UserId,
+		AuctionId: auction.Id,
+		Amount:    auction.BuyNowPrice,
+		Timestamp: time.Now(),
+	}
+	if err := bid_usecase.CheckBid(ctx, au.buyerRules, buyNowBid, auction).Err(); err != nil {
+		return nil, err
+	}
+
+	if err := au.auctionRepositoryInterface.BuyNow(ctx, id, buyNowInput.UserId); err != nil {
+		return nil, err
+	}
+
+	return au.FindAuctionById(ctx, id)
+}
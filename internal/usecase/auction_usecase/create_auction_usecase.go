@@ -1,11 +1,16 @@
 package auction_usecase
 
 import (
+	"auction_go/configuration/markdown"
 	"auction_go/internal/entity/auction_entity"
 	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/category_entity"
+	"auction_go/internal/entity/user_entity"
 	"auction_go/internal/internal_error"
 	"auction_go/internal/usecase/bid_usecase"
+	"auction_go/internal/usecase/search_usecase"
 	"context"
+	"strings"
 	"time"
 )
 
@@ -14,16 +19,175 @@ type AuctionInputDTO struct {
 	Category    string           `json:"category" binding:"required,min=2"`
 	Description string           `json:"description" binding:"required,min=10,max=200"`
 	Condition   ProductCondition `json:"condition" binding:"oneof=0 1 2"`
+
+	// SellerId is the authenticated user, it isn't read from the body
+	SellerId string `json:"-"`
+
+	// DurationSeconds is checked against the entity bounds, zero uses the
+	// default AUCTION_INTERVAL
+	DurationSeconds int64 `json:"duration_seconds" binding:"omitempty,min=0"`
+
+	// StartTime in the future schedules the auction instead of opening it now
+	StartTime time.Time `json:"start_time"`
+
+	// ReservePrice is the lowest bid the seller accepts to sell, its amount is
+	// hidden from buyers unless ReservePublic is set
+	ReservePrice  float64 `json:"reserve_price" binding:"omitempty,min=0"`
+	ReservePublic bool    `json:"reserve_public"`
+
+	// BuyNowPrice offers buyers to end the auction right away at that price
+	BuyNowPrice float64 `json:"buy_now_price" binding:"omitempty,min=0"`
+
+	// MinIncrement is the least each bid has to add to the current price,
+	// zero uses the default BID_INCREMENT
+	MinIncrement float64 `json:"min_increment" binding:"omitempty,min=0"`
+
+	// MaxRelists puts the auction back on sale up to that many times when it
+	// ends without a winner
+	MaxRelists int `json:"max_relists" binding:"omitempty,min=0"`
+
+	// Type 1 makes a Dutch auction, its price follows DutchPricing down and
+	// the first bid at the current price wins
+	Type         AuctionType      `json:"type" binding:"oneof=0 1"`
+	DutchPricing *DutchPricingDTO `json:"dutch_pricing"`
+
+	// Location lets buyers find the auction by distance for local pickup,
+	// PostalCode alone is only shown
+	Location   *LocationDTO `json:"location"`
+	PostalCode string       `json:"postal_code" binding:"max=20"`
+}
+
+type LocationDTO struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type DutchPricingDTO struct {
+	StartPrice      float64 `json:"start_price"`
+	FloorPrice      float64 `json:"floor_price"`
+	Decrement       float64 `json:"decrement"`
+	IntervalSeconds int64   `json:"interval_seconds"`
 }
 
 type AuctionOutputDTO struct {
-	Id          string           `json:"id"`
-	ProductName string           `json:"product_name"`
-	Category    string           `json:"category"`
-	Description string           `json:"description"`
-	Condition   ProductCondition `json:"condition"`
-	Status      AuctionStatus    `json:"status"`
-	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id              string           `json:"id"`
+	SellerId        string           `json:"seller_id,omitempty"`
+	ProductName     string           `json:"product_name"`
+	Category        string           `json:"category"`
+	Description     string           `json:"description"`
+	DescriptionHTML string           `json:"description_html"`
+	Condition       ProductCondition `json:"condition"`
+	Status          AuctionStatus    `json:"status"`
+	Timestamp       time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	StartTime       time.Time        `json:"start_time" time_format:"2006-01-02 15:04:05"`
+	DurationSeconds int64            `json:"duration_seconds"`
+	EndTime         time.Time        `json:"end_time" time_format:"2006-01-02 15:04:05"`
+	WinnerUserId    string           `json:"winner_user_id,omitempty"`
+	WinningAmount   float64          `json:"winning_amount,omitempty"`
+	ReservePrice    *float64         `json:"reserve_price,omitempty"`
+	HasReserve      bool             `json:"has_reserve"`
+	BuyNowPrice     float64          `json:"buy_now_price,omitempty"`
+	MinIncrement    float64          `json:"min_increment,omitempty"`
+	MaxRelists      int              `json:"max_relists,omitempty"`
+	RelistCount     int              `json:"relist_count,omitempty"`
+	RelistedFrom    string           `json:"relisted_from,omitempty"`
+	Type            AuctionType      `json:"type"`
+	DutchPricing    *DutchPricingDTO `json:"dutch_pricing,omitempty"`
+	DutchPrice      float64          `json:"dutch_price,omitempty"`
+
+	// SellerReputation is only filled in on the auction detail
+	SellerReputation *ReputationOutputDTO `json:"seller_reputation,omitempty"`
+
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty" time_format:"2006-01-02 15:04:05"`
+	PausedAt           *time.Time `json:"paused_at,omitempty" time_format:"2006-01-02 15:04:05"`
+
+	Location   *LocationDTO `json:"location,omitempty"`
+	PostalCode string       `json:"postal_code,omitempty"`
+}
+
+type ReputationOutputDTO struct {
+	Score float64 `json:"score"`
+	Count int64   `json:"count"`
+}
+
+type BuyNowInputDTO struct {
+	// UserId is the authenticated buyer, buying now takes no body
+	UserId string `json:"-"`
+}
+
+type CancelAuctionInputDTO struct {
+	// SellerId is the authenticated user, it isn't read from the body
+	SellerId string `json:"-"`
+	Reason   string `json:"reason" binding:"required,max=500"`
+}
+
+type AuctionPageOutputDTO struct {
+	Auctions []AuctionOutputDTO `json:"auctions"`
+	Page     int64              `json:"page"`
+	PageSize int64              `json:"page_size"`
+	Total    int64              `json:"total"`
+}
+
+// AuctionQueryInputDTO lists auctions, zero fields don't filter. Sort is
+// end_time, created_at or price
+type AuctionQueryInputDTO struct {
+	Status      *AuctionStatus
+	Category    string
+	Condition   ProductCondition
+	SellerId    string
+	MinPrice    float64
+	MaxPrice    float64
+	ProductName string
+	Sort        string
+	Descending  bool
+	Cursor      string
+	Limit       int64
+
+	// EndingWithin and ListedWithin only list auctions ending or created
+	// within that long of now
+	EndingWithin time.Duration
+	ListedWithin time.Duration
+
+	// Near only lists auctions located within RadiusKm of it
+	Near     *LocationDTO
+	RadiusKm float64
+
+	// Facets counts every matching auction by category, condition and
+	// price, only on the first page since the counts don't change with it
+	Facets bool
+}
+
+// AuctionListOutputDTO is a page of a listing, NextCursor fetches the
+// following page and is left out on the last one
+type AuctionListOutputDTO struct {
+	Auctions   []AuctionOutputDTO      `json:"auctions"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	Facets     *AuctionFacetsOutputDTO `json:"facets,omitempty"`
+}
+
+type AuctionFacetsOutputDTO struct {
+	Categories []CategoryFacetDTO  `json:"categories"`
+	Conditions []ConditionFacetDTO `json:"conditions"`
+	Prices     []PriceFacetDTO     `json:"prices"`
+}
+
+type CategoryFacetDTO struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+type ConditionFacetDTO struct {
+	Condition ProductCondition `json:"condition"`
+	Count     int64            `json:"count"`
+}
+
+// PriceFacetDTO counts current prices from Min up to Max, Max is left out
+// on the last bucket
+type PriceFacetDTO struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
 }
 
 type WinningInfoOutputDTO struct {
@@ -31,12 +195,29 @@ type WinningInfoOutputDTO struct {
 	Bid     *bid_usecase.BidOutputDTO `json:"bid,omitempty"`
 }
 
+// AuctionTimeOutputDTO lets clients sync their countdown with the server
+// clock, RemainingSeconds counts down from ServerTime
+type AuctionTimeOutputDTO struct {
+	AuctionId        string        `json:"auction_id"`
+	Status           AuctionStatus `json:"status"`
+	ServerTime       time.Time     `json:"server_time"`
+	EndTime          time.Time     `json:"end_time"`
+	RemainingSeconds float64       `json:"remaining_seconds"`
+}
+
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	userRepositoryInterface user_entity.UserRepositoryInterface,
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface,
+	searchUseCase search_usecase.SearchUseCaseInterface) AuctionUseCaseInterface {
 	return &AuctionUseCase{
-		auctionRepositoryInterface: auctionRepositoryInterface,
-		bidRepositoryInterface:     bidRepositoryInterface,
+		auctionRepositoryInterface:  auctionRepositoryInterface,
+		bidRepositoryInterface:      bidRepositoryInterface,
+		userRepositoryInterface:     userRepositoryInterface,
+		categoryRepositoryInterface: categoryRepositoryInterface,
+		searchUseCase:               searchUseCase,
+		buyerRules:                  bid_usecase.BuyerRules(userRepositoryInterface),
 	}
 }
 
@@ -50,33 +231,115 @@ type AuctionUseCaseInterface interface {
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError)
+		queryInput AuctionQueryInputDTO) (*AuctionListOutputDTO, *internal_error.InternalError)
+
+	SearchAuctions(
+		ctx context.Context,
+		searchInput AuctionSearchInputDTO) (*AuctionSearchOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
 		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	FindAuctionTime(
+		ctx context.Context, id string) (*AuctionTimeOutputDTO, *internal_error.InternalError)
+
+	FindActiveAuctionsBySellerId(
+		ctx context.Context,
+		sellerId string,
+		page, pageSize int64) (*AuctionPageOutputDTO, *internal_error.InternalError)
+
+	CancelAuction(
+		ctx context.Context,
+		id string,
+		cancelInput CancelAuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	BuyNow(
+		ctx context.Context,
+		id string,
+		buyNowInput BuyNowInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
 }
 
 type ProductCondition int64
 type AuctionStatus int64
+type AuctionType int64
 
 type AuctionUseCase struct {
-	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
-	bidRepositoryInterface     bid_entity.BidEntityRepository
+	auctionRepositoryInterface  auction_entity.AuctionRepositoryInterface
+	bidRepositoryInterface      bid_entity.BidEntityRepository
+	userRepositoryInterface     user_entity.UserRepositoryInterface
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface
+	searchUseCase               search_usecase.SearchUseCaseInterface
+
+	// buyerRules hold buy now to the rules bidders are held to
+	buyerRules []bid_usecase.BidRule
 }
 
 func (au *AuctionUseCase) CreateAuction(
 	ctx context.Context,
 	auctionInput AuctionInputDTO) *internal_error.InternalError {
+	if auctionInput.SellerId != "" {
+		block, err := au.userRepositoryInterface.FindUserBlock(ctx, auctionInput.SellerId)
+		if err != nil {
+			return err
+		}
+		if err := block.Check(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// Auctions are filed under the category's managed name, whatever case
+	// the seller typed it in
+	category, err := au.categoryRepositoryInterface.FindCategoryByName(ctx, auctionInput.Category)
+	if err != nil {
+		if err.Err == "not_found" {
+			return internal_error.NewValidationError("Invalid auction",
+				internal_error.Cause{Field: "category", Message: "Unknown category, see GET /categories"})
+		}
+		return err
+	}
+
 	auction, err := auction_entity.CreateAuction(
 		auctionInput.ProductName,
-		auctionInput.Category,
+		category.Name,
 		auctionInput.Description,
-		auction_entity.ProductCondition(auctionInput.Condition))
+		auction_entity.ProductCondition(auctionInput.Condition),
+		auctionInput.StartTime,
+		time.Duration(auctionInput.DurationSeconds)*time.Second)
 	if err != nil {
 		return err
 	}
+	auction.SellerId = auctionInput.SellerId
+	auction.ReservePrice = auctionInput.ReservePrice
+	auction.ReservePublic = auctionInput.ReservePublic
+	auction.BuyNowPrice = auctionInput.BuyNowPrice
+	auction.MinIncrement = auctionInput.MinIncrement
+	auction.RelistPolicy = auction_entity.RelistPolicy{MaxRelists: auctionInput.MaxRelists}
+	auction.Type = auction_entity.AuctionType(auctionInput.Type)
+	if auctionInput.DutchPricing != nil {
+		auction.DutchPricing = auction_entity.DutchPricing{
+			StartPrice: auctionInput.DutchPricing.StartPrice,
+			FloorPrice: auctionInput.DutchPricing.FloorPrice,
+			Decrement:  auctionInput.DutchPricing.Decrement,
+			Interval:   time.Duration(auctionInput.DutchPricing.IntervalSeconds) * time.Second,
+		}
+	}
+	if auctionInput.Location != nil {
+		auction.Location = &auction_entity.GeoPoint{
+			Latitude:  auctionInput.Location.Latitude,
+			Longitude: auctionInput.Location.Longitude,
+		}
+	}
+	auction.PostalCode = strings.TrimSpace(auctionInput.PostalCode)
+
+	if auction.BuyNowPrice > 0 && auction.BuyNowPrice < auction.ReservePrice {
+		return internal_error.NewBadRequestError("BuyNowPrice can't be below the ReservePrice")
+	}
+
+	if err := auction.Validate(); err != nil {
+		return err
+	}
+	auction.DescriptionHTML = markdown.Render(auction.Description)
 
 	if err := au.auctionRepositoryInterface.CreateAuction(
 		ctx, auction); err != nil {
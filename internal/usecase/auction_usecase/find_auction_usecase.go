@@ -2,10 +2,12 @@ package auction_usecase
 
 import (
 	"auction_go/configuration/logger"
+	"auction_go/configuration/markdown"
 	"auction_go/internal/entity/auction_entity"
 	"auction_go/internal/internal_error"
 	"auction_go/internal/usecase/bid_usecase"
 	"context"
+	"time"
 )
 
 func (au *AuctionUseCase) FindAuctionById(
@@ -16,40 +18,291 @@ func (au *AuctionUseCase) FindAuctionById(
 	}
 
 	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
+		Id:              auctionEntity.Id,
+		SellerId:        auctionEntity.SellerId,
+		ProductName:     auctionEntity.ProductName,
+		Category:        auctionEntity.Category,
+		Description:     auctionEntity.Description,
+		DescriptionHTML: renderedDescription(*auctionEntity),
+		Condition:       ProductCondition(auctionEntity.Condition),
+		Status:          AuctionStatus(auctionEntity.Status),
+		Timestamp:       auctionEntity.Timestamp,
+		StartTime:       auctionEntity.StartTime,
+		DurationSeconds: int64(auctionEntity.Duration.Seconds()),
+		EndTime:         auctionEntity.EndTime,
+		WinnerUserId:    auctionEntity.WinnerUserId,
+		WinningAmount:   auctionEntity.WinningAmount,
+		ReservePrice:    publicReserve(*auctionEntity),
+		HasReserve:      auctionEntity.ReservePrice > 0,
+		BuyNowPrice:     auctionEntity.BuyNowPrice,
+		MinIncrement:    auctionEntity.MinIncrement,
+		MaxRelists:      auctionEntity.RelistPolicy.MaxRelists,
+		RelistCount:     auctionEntity.RelistCount,
+		RelistedFrom:    auctionEntity.RelistedFrom,
+		Type:            AuctionType(auctionEntity.Type),
+		DutchPricing:    dutchPricingOutput(*auctionEntity),
+		DutchPrice:      auctionEntity.DutchPrice,
+
+		CancellationReason: auctionEntity.CancellationReason,
+		CancelledAt:        optionalTime(auctionEntity.CancelledAt),
+		PausedAt:           optionalTime(auctionEntity.PausedAt),
+		Location:           locationOutput(*auctionEntity),
+		PostalCode:         auctionEntity.PostalCode,
+
+		SellerReputation: au.sellerReputation(ctx, auctionEntity.SellerId),
 	}, nil
 }
 
+// FindAuctionTime reads the clock right after the auction, so the
+// remaining time is as fresh as the end time it counts down to
+func (au *AuctionUseCase) FindAuctionTime(
+	ctx context.Context, id string) (*AuctionTimeOutputDTO, *internal_error.InternalError) {
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &AuctionTimeOutputDTO{
+		AuctionId:        auctionEntity.Id,
+		Status:           AuctionStatus(auctionEntity.Status),
+		ServerTime:       now,
+		EndTime:          auctionEntity.EndTime,
+		RemainingSeconds: auctionEntity.Remaining(now).Seconds(),
+	}, nil
+}
+
+// sellerReputation is left out when the seller can't be read, the auction
+// is shown anyway
+func (au *AuctionUseCase) sellerReputation(ctx context.Context, sellerId string) *ReputationOutputDTO {
+	if sellerId == "" {
+		return nil
+	}
+
+	seller, err := au.userRepositoryInterface.FindUserById(ctx, sellerId)
+	if err != nil {
+		return nil
+	}
+
+	return &ReputationOutputDTO{
+		Score: seller.Reputation.Score,
+		Count: seller.Reputation.Count,
+	}
+}
+
 func (au *AuctionUseCase) FindAuctions(
 	ctx context.Context,
-	status AuctionStatus,
-	category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
-	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(
-		ctx, auction_entity.AuctionStatus(status), category, productName)
+	queryInput AuctionQueryInputDTO) (*AuctionListOutputDTO, *internal_error.InternalError) {
+	if queryInput.MaxPrice > 0 && queryInput.MinPrice > queryInput.MaxPrice {
+		return nil, internal_error.NewValidationError("Invalid auction query",
+			internal_error.Cause{Field: "max_price", Message: "Must not be below min_price"})
+	}
+
+	query := auction_entity.AuctionQuery{
+		Category:   au.categoryName(ctx, queryInput.Category),
+		Condition:  auction_entity.ProductCondition(queryInput.Condition),
+		SellerId:   queryInput.SellerId,
+		MinPrice:   queryInput.MinPrice,
+		MaxPrice:   queryInput.MaxPrice,
+		Sort:       auction_entity.AuctionSort(queryInput.Sort),
+		Descending: queryInput.Descending,
+		Cursor:     queryInput.Cursor,
+		Limit:      queryInput.Limit,
+	}
+	if queryInput.Status != nil {
+		status := auction_entity.AuctionStatus(*queryInput.Status)
+		query.Status = &status
+	}
+
+	now := time.Now()
+	if queryInput.EndingWithin > 0 {
+		query.EndsBefore = now.Add(queryInput.EndingWithin)
+	}
+	if queryInput.ListedWithin > 0 {
+		query.CreatedAfter = now.Add(-queryInput.ListedWithin)
+	}
+
+	if queryInput.Near != nil {
+		query.Near = &auction_entity.GeoPoint{
+			Latitude:  queryInput.Near.Latitude,
+			Longitude: queryInput.Near.Longitude,
+		}
+		query.RadiusKm = queryInput.RadiusKm
+	}
+
+	if queryInput.ProductName != "" {
+		queries, err := au.searchUseCase.ExpandQuery(ctx, queryInput.ProductName)
+		if err != nil {
+			return nil, err
+		}
+		// Only stop words would leave nothing to filter by, and list
+		// every auction
+		if len(queries) == 0 {
+			return nil, internal_error.NewValidationError("Invalid auction query",
+				internal_error.Cause{Field: "productName", Message: "Must have at least one word to search for"})
+		}
+		query.ProductNameQueries = queries
+	}
+
+	auctionEntities, nextCursor, err := au.auctionRepositoryInterface.FindAuctions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputs := make([]AuctionOutputDTO, 0, len(auctionEntities))
+	for _, value := range auctionEntities {
+		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
+			Id:              value.Id,
+			SellerId:        value.SellerId,
+			ProductName:     value.ProductName,
+			Category:        value.Category,
+			Description:     value.Description,
+			DescriptionHTML: renderedDescription(value),
+			Condition:       ProductCondition(value.Condition),
+			Status:          AuctionStatus(value.Status),
+			Timestamp:       value.Timestamp,
+			StartTime:       value.StartTime,
+			DurationSeconds: int64(value.Duration.Seconds()),
+			EndTime:         value.EndTime,
+			WinnerUserId:    value.WinnerUserId,
+			WinningAmount:   value.WinningAmount,
+			ReservePrice:    publicReserve(value),
+			HasReserve:      value.ReservePrice > 0,
+			BuyNowPrice:     value.BuyNowPrice,
+			MinIncrement:    value.MinIncrement,
+			MaxRelists:      value.RelistPolicy.MaxRelists,
+			RelistCount:     value.RelistCount,
+			RelistedFrom:    value.RelistedFrom,
+			Type:            AuctionType(value.Type),
+			DutchPricing:    dutchPricingOutput(value),
+			DutchPrice:      value.DutchPrice,
+
+			CancellationReason: value.CancellationReason,
+			CancelledAt:        optionalTime(value.CancelledAt),
+			PausedAt:           optionalTime(value.PausedAt),
+			Location:           locationOutput(value),
+			PostalCode:         value.PostalCode,
+		})
+	}
+
+	auctionList := &AuctionListOutputDTO{
+		Auctions:   auctionOutputs,
+		NextCursor: nextCursor,
+	}
+	if queryInput.Facets && queryInput.Cursor == "" {
+		facets, err := au.auctionRepositoryInterface.FindAuctionFacets(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		auctionList.Facets = facetsOutput(*facets)
+	}
+
+	return auctionList, nil
+}
+
+func facetsOutput(facets auction_entity.AuctionFacets) *AuctionFacetsOutputDTO {
+	facetsOutput := &AuctionFacetsOutputDTO{
+		Categories: make([]CategoryFacetDTO, 0, len(facets.Categories)),
+		Conditions: make([]ConditionFacetDTO, 0, len(facets.Conditions)),
+		Prices:     make([]PriceFacetDTO, 0, len(facets.Prices)),
+	}
+
+	for _, category := range facets.Categories {
+		facetsOutput.Categories = append(facetsOutput.Categories,
+			CategoryFacetDTO{Category: category.Category, Count: category.Count})
+	}
+	for _, condition := range facets.Conditions {
+		facetsOutput.Conditions = append(facetsOutput.Conditions,
+			ConditionFacetDTO{Condition: ProductCondition(condition.Condition), Count: condition.Count})
+	}
+	for _, price := range facets.Prices {
+		priceFacet := PriceFacetDTO{Min: price.Min, Count: price.Count}
+		if price.Max > 0 {
+			priceFacet.Max = &price.Max
+		}
+		facetsOutput.Prices = append(facetsOutput.Prices, priceFacet)
+	}
+
+	return facetsOutput
+}
+
+// categoryName is the managed name of the category the filter names, so
+// it matches whatever case it is typed in. Unknown categories are left as
+// they are and match nothing
+func (au *AuctionUseCase) categoryName(ctx context.Context, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	category, err := au.categoryRepositoryInterface.FindCategoryByName(ctx, name)
+	if err != nil {
+		return name
+	}
+
+	return category.Name
+}
+
+func (au *AuctionUseCase) FindActiveAuctionsBySellerId(
+	ctx context.Context,
+	sellerId string,
+	page, pageSize int64) (*AuctionPageOutputDTO, *internal_error.InternalError) {
+	auctionEntities, total, err := au.auctionRepositoryInterface.FindAuctionsBySellerId(
+		ctx, sellerId, auction_entity.Active, (page-1)*pageSize, pageSize)
 	if err != nil {
 		return nil, err
 	}
 
-	var auctionOutputs []AuctionOutputDTO
+	auctionOutputs := []AuctionOutputDTO{}
 	for _, value := range auctionEntities {
 		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
+			Id:              value.Id,
+			SellerId:        value.SellerId,
+			ProductName:     value.ProductName,
+			Category:        value.Category,
+			Description:     value.Description,
+			DescriptionHTML: renderedDescription(value),
+			Condition:       ProductCondition(value.Condition),
+			Status:          AuctionStatus(value.Status),
+			Timestamp:       value.Timestamp,
+			StartTime:       value.StartTime,
+			DurationSeconds: int64(value.Duration.Seconds()),
+			EndTime:         value.EndTime,
+			WinnerUserId:    value.WinnerUserId,
+			WinningAmount:   value.WinningAmount,
+			ReservePrice:    publicReserve(value),
+			HasReserve:      value.ReservePrice > 0,
+			BuyNowPrice:     value.BuyNowPrice,
+			MinIncrement:    value.MinIncrement,
+			MaxRelists:      value.RelistPolicy.MaxRelists,
+			RelistCount:     value.RelistCount,
+			RelistedFrom:    value.RelistedFrom,
+			Type:            AuctionType(value.Type),
+			DutchPricing:    dutchPricingOutput(value),
+			DutchPrice:      value.DutchPrice,
+
+			CancellationReason: value.CancellationReason,
+			CancelledAt:        optionalTime(value.CancelledAt),
+			PausedAt:           optionalTime(value.PausedAt),
+			Location:           locationOutput(value),
+			PostalCode:         value.PostalCode,
 		})
 	}
 
-	return auctionOutputs, nil
+	return &AuctionPageOutputDTO{
+		Auctions: auctionOutputs,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+// Auctions stored before descriptions were rendered only carry the raw text
+func renderedDescription(auction auction_entity.Auction) string {
+	if auction.DescriptionHTML != "" {
+		return auction.DescriptionHTML
+	}
+
+	return markdown.Render(auction.Description)
 }
 
 func (au *AuctionUseCase) FindWinningBidByAuctionId(
@@ -61,13 +314,36 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 	}
 
 	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
+		Id:              auction.Id,
+		SellerId:        auction.SellerId,
+		ProductName:     auction.ProductName,
+		Category:        auction.Category,
+		Description:     auction.Description,
+		DescriptionHTML: renderedDescription(*auction),
+		Condition:       ProductCondition(auction.Condition),
+		Status:          AuctionStatus(auction.Status),
+		Timestamp:       auction.Timestamp,
+		StartTime:       auction.StartTime,
+		DurationSeconds: int64(auction.Duration.Seconds()),
+		EndTime:         auction.EndTime,
+		WinnerUserId:    auction.WinnerUserId,
+		WinningAmount:   auction.WinningAmount,
+		ReservePrice:    publicReserve(*auction),
+		HasReserve:      auction.ReservePrice > 0,
+		BuyNowPrice:     auction.BuyNowPrice,
+		MinIncrement:    auction.MinIncrement,
+		MaxRelists:      auction.RelistPolicy.MaxRelists,
+		RelistCount:     auction.RelistCount,
+		RelistedFrom:    auction.RelistedFrom,
+		Type:            AuctionType(auction.Type),
+		DutchPricing:    dutchPricingOutput(*auction),
+		DutchPrice:      auction.DutchPrice,
+
+		CancellationReason: auction.CancellationReason,
+		CancelledAt:        optionalTime(auction.CancelledAt),
+		PausedAt:           optionalTime(auction.PausedAt),
+		Location:           locationOutput(*auction),
+		PostalCode:         auction.PostalCode,
 	}
 
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
@@ -79,12 +355,14 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		}, nil
 	}
 
+	reserveMet := auction.ReserveMet(bidWinning.Amount)
 	bidOutputDTO := &bid_usecase.BidOutputDTO{
-		Id:        bidWinning.Id,
-		UserId:    bidWinning.UserId,
-		AuctionId: bidWinning.AuctionId,
-		Amount:    bidWinning.Amount,
-		Timestamp: bidWinning.Timestamp,
+		Id:         bidWinning.Id,
+		UserId:     bidWinning.UserId,
+		AuctionId:  bidWinning.AuctionId,
+		Amount:     bidWinning.Amount,
+		Timestamp:  bidWinning.Timestamp,
+		ReserveMet: &reserveMet,
 	}
 
 	return &WinningInfoOutputDTO{
@@ -92,3 +370,49 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		Bid:     bidOutputDTO,
 	}, nil
 }
+
+// Times that may be unset are left out of the output instead of showing the
+// zero time
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+// The reserve amount is only shown when the seller made it public, buyers
+// still learn through HasReserve that there is one
+func publicReserve(auction auction_entity.Auction) *float64 {
+	if auction.ReservePrice <= 0 || !auction.ReservePublic {
+		return nil
+	}
+
+	reservePrice := auction.ReservePrice
+	return &reservePrice
+}
+
+func locationOutput(auction auction_entity.Auction) *LocationDTO {
+	if auction.Location == nil {
+		return nil
+	}
+
+	return &LocationDTO{
+		Latitude:  auction.Location.Latitude,
+		Longitude: auction.Location.Longitude,
+	}
+}
+
+// Only Dutch auctions have a price schedule to show
+func dutchPricingOutput(auction auction_entity.Auction) *DutchPricingDTO {
+	if auction.Type != auction_entity.Dutch {
+		return nil
+	}
+
+	return &DutchPricingDTO{
+		StartPrice:      auction.DutchPricing.StartPrice,
+		FloorPrice:      auction.DutchPricing.FloorPrice,
+		Decrement:       auction.DutchPricing.Decrement,
+		IntervalSeconds: int64(auction.DutchPricing.Interval.Seconds()),
+	}
+}
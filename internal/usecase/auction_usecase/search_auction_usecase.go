@@ -0,0 +1,110 @@
+package auction_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// AuctionSearchInputDTO searches auctions by keyword, Status and Category
+// narrow the results when set
+type AuctionSearchInputDTO struct {
+	Query    string
+	Status   *AuctionStatus
+	Category string
+	Page     int64
+	PageSize int64
+}
+
+// AuctionSearchResultDTO is a matching auction and its relevance, a higher
+// score is a better match
+type AuctionSearchResultDTO struct {
+	AuctionOutputDTO
+	Score float64 `json:"score"`
+}
+
+type AuctionSearchOutputDTO struct {
+	Results  []AuctionSearchResultDTO `json:"results"`
+	Page     int64                    `json:"page"`
+	PageSize int64                    `json:"page_size"`
+	Total    int64                    `json:"total"`
+}
+
+// SearchAuctions finds auctions whose product name or description match the
+// query, best match first. The query goes through the stop words and
+// synonyms like the product name filter of the listing does
+func (au *AuctionUseCase) SearchAuctions(
+	ctx context.Context,
+	searchInput AuctionSearchInputDTO) (*AuctionSearchOutputDTO, *internal_error.InternalError) {
+	terms, err := au.searchUseCase.ExpandQuery(ctx, searchInput.Query)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, internal_error.NewValidationError("Invalid search",
+			internal_error.Cause{Field: "q", Message: "Must have at least one word to search for"})
+	}
+
+	search := auction_entity.AuctionSearch{
+		Terms:    terms,
+		Category: au.categoryName(ctx, searchInput.Category),
+		Skip:     (searchInput.Page - 1) * searchInput.PageSize,
+		Limit:    searchInput.PageSize,
+	}
+	if searchInput.Status != nil {
+		status := auction_entity.AuctionStatus(*searchInput.Status)
+		search.Status = &status
+	}
+
+	scoredAuctions, total, err := au.auctionRepositoryInterface.SearchAuctions(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]AuctionSearchResultDTO, 0, len(scoredAuctions))
+	for _, scored := range scoredAuctions {
+		value := scored.Auction
+		results = append(results, AuctionSearchResultDTO{
+			AuctionOutputDTO: AuctionOutputDTO{
+				Id:              value.Id,
+				SellerId:        value.SellerId,
+				ProductName:     value.ProductName,
+				Category:        value.Category,
+				Description:     value.Description,
+				DescriptionHTML: renderedDescription(value),
+				Condition:       ProductCondition(value.Condition),
+				Status:          AuctionStatus(value.Status),
+				Timestamp:       value.Timestamp,
+				StartTime:       value.StartTime,
+				DurationSeconds: int64(value.Duration.Seconds()),
+				EndTime:         value.EndTime,
+				WinnerUserId:    value.WinnerUserId,
+				WinningAmount:   value.WinningAmount,
+				ReservePrice:    publicReserve(value),
+				HasReserve:      value.ReservePrice > 0,
+				BuyNowPrice:     value.BuyNowPrice,
+				MinIncrement:    value.MinIncrement,
+				MaxRelists:      value.RelistPolicy.MaxRelists,
+				RelistCount:     value.RelistCount,
+				RelistedFrom:    value.RelistedFrom,
+				Type:            AuctionType(value.Type),
+				DutchPricing:    dutchPricingOutput(value),
+				DutchPrice:      value.DutchPrice,
+
+				CancellationReason: value.CancellationReason,
+				CancelledAt:        optionalTime(value.CancelledAt),
+				PausedAt:           optionalTime(value.PausedAt),
+				Location:           locationOutput(value),
+				PostalCode:         value.PostalCode,
+			},
+			Score: scored.Score,
+		})
+	}
+
+	return &AuctionSearchOutputDTO{
+		Results:  results,
+		Page:     searchInput.Page,
+		PageSize: searchInput.PageSize,
+		Total:    total,
+	}, nil
+}
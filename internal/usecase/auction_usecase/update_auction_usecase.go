@@ -0,0 +1,28 @@
+package auction_usecase
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// CancelAuction stops an active or scheduled auction on behalf of its seller,
+// no winner is recorded for it
+func (au *AuctionUseCase) CancelAuction(
+	ctx context.Context,
+	id string,
+	cancelInput CancelAuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerId == "" || auction.SellerId != cancelInput.SellerId {
+		return nil, internal_error.NewBadRequestError("Only the auction seller can cancel it")
+	}
+
+	if err := au.auctionRepositoryInterface.CancelAuction(ctx, id, cancelInput.Reason); err != nil {
+		return nil, err
+	}
+
+	return au.FindAuctionById(ctx, id)
+}
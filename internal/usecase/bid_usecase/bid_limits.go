@@ -0,0 +1,95 @@
+package bid_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// bidLimits is the most a single bid may be at each verification level, a
+// zero limit lets the level bid any amount
+type bidLimits map[user_entity.VerificationLevel]float64
+
+func getBidLimits() bidLimits {
+	return bidLimits{
+		user_entity.Unverified:    getBidLimit(user_entity.Unverified, 1000),
+		user_entity.EmailVerified: getBidLimit(user_entity.EmailVerified, 10000),
+		user_entity.KYCVerified:   getBidLimit(user_entity.KYCVerified, 0),
+	}
+}
+
+// verificationLimitRule caps bids by how far the bidder is verified. Bidders
+// without a user document are treated as unverified
+type verificationLimitRule struct {
+	userRepository user_entity.UserRepositoryInterface
+	limits         bidLimits
+}
+
+func (verificationLimitRule) Name() string { return "verification_limit" }
+
+func (r verificationLimitRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	level := user_entity.Unverified
+	user, err := r.userRepository.FindUserById(ctx, bid.UserId)
+	if err != nil && err.Err != "not_found" {
+		return err
+	}
+	if user != nil {
+		level = user.Level()
+	}
+
+	limit := r.limits[level]
+	if limit > 0 && bid.Amount > limit {
+		return internal_error.NewValidationError("Bid is above the limit of your verification level",
+			internal_error.Cause{Field: "amount", Message: fmt.Sprintf("Must be at most %.2f for %s users", limit, level)})
+	}
+
+	return nil
+}
+
+// emailVerifiedRule turns away bidders who haven't confirmed their email,
+// it only runs when BID_REQUIRE_VERIFIED_EMAIL is set
+type emailVerifiedRule struct {
+	userRepository user_entity.UserRepositoryInterface
+}
+
+func (emailVerifiedRule) Name() string { return "email_verified" }
+
+func (r emailVerifiedRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	user, err := r.userRepository.FindUserById(ctx, bid.UserId)
+	if err != nil && err.Err != "not_found" {
+		return err
+	}
+
+	if user == nil || !user.HasVerifiedEmail() {
+		return internal_error.NewForbiddenError("Verify your email before placing bids")
+	}
+
+	return nil
+}
+
+func getRequireVerifiedEmail() bool {
+	require, err := strconv.ParseBool(os.Getenv("BID_REQUIRE_VERIFIED_EMAIL"))
+	return err == nil && require
+}
+
+// getBidLimit reads BID_LIMIT_<LEVEL>, such as BID_LIMIT_EMAIL_VERIFIED
+func getBidLimit(level user_entity.VerificationLevel, fallback float64) float64 {
+	limit, err := strconv.ParseFloat(os.Getenv("BID_LIMIT_"+strings.ToUpper(string(level))), 64)
+	if err != nil || limit < 0 {
+		return fallback
+	}
+
+	return limit
+}
@@ -0,0 +1,94 @@
+package bid_usecase
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bidRateLimiter counts each user's bids on each auction over a sliding
+// window, in memory, so every instance enforces its own limit
+type bidRateLimiter struct {
+	limit     int
+	window    time.Duration
+	bids      map[string][]time.Time
+	lastSweep time.Time
+	mutex     *sync.Mutex
+}
+
+func newBidRateLimiter() *bidRateLimiter {
+	return &bidRateLimiter{
+		limit:  getBidRateLimit(),
+		window: getBidRateWindow(),
+		bids:   make(map[string][]time.Time),
+		mutex:  &sync.Mutex{},
+	}
+}
+
+// allow records a bid by the user on the auction unless they already used up
+// the window, then it returns how long until their oldest bid leaves it. A
+// zero limit turns the throttle off
+func (rl *bidRateLimiter) allow(userId, auctionId string, now time.Time) (bool, time.Duration) {
+	if rl.limit <= 0 {
+		return true, 0
+	}
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	rl.sweep(now)
+
+	key := userId + ":" + auctionId
+	recent := inWindow(rl.bids[key], now.Add(-rl.window))
+	if len(recent) >= rl.limit {
+		rl.bids[key] = recent
+		return false, recent[0].Add(rl.window).Sub(now)
+	}
+
+	rl.bids[key] = append(recent, now)
+	return true, 0
+}
+
+// sweep drops the users who stopped bidding, once per window
+func (rl *bidRateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.window {
+		return
+	}
+	rl.lastSweep = now
+
+	for key, bids := range rl.bids {
+		if len(inWindow(bids, now.Add(-rl.window))) == 0 {
+			delete(rl.bids, key)
+		}
+	}
+}
+
+// inWindow trims the bids placed up to since, bids are kept in order
+func inWindow(bids []time.Time, since time.Time) []time.Time {
+	for i, bidTime := range bids {
+		if bidTime.After(since) {
+			return bids[i:]
+		}
+	}
+
+	return nil
+}
+
+func getBidRateLimit() int {
+	limit, err := strconv.Atoi(os.Getenv("BID_RATE_LIMIT"))
+	if err != nil || limit < 0 {
+		return 5
+	}
+
+	return limit
+}
+
+func getBidRateWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("BID_RATE_WINDOW"))
+	if err != nil || window <= 0 {
+		return time.Second
+	}
+
+	return window
+}
@@ -0,0 +1,248 @@
+package bid_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"time"
+)
+
+// BidRule checks a bid before it is placed, returning why it is turned down.
+// Rules run in order and all of them run, so every reason is reported at once
+type BidRule interface {
+	Name() string
+	Check(
+		ctx context.Context,
+		bid bid_entity.Bid,
+		auction *auction_entity.Auction) *internal_error.InternalError
+}
+
+// BidRejection is a rule that turned the bid down and why
+type BidRejection struct {
+	Rule string
+	Err  *internal_error.InternalError
+}
+
+// BidRuleResult is the outcome of checking a bid against every rule, the bid
+// may be placed when there are no rejections
+type BidRuleResult struct {
+	Rejections []BidRejection
+}
+
+// Err sums the rejections up as a single error. Validation failures are
+// merged into one bad request with a cause per reason, any other failure,
+// such as a rate limit, is returned as is
+func (r BidRuleResult) Err() *internal_error.InternalError {
+	if len(r.Rejections) == 0 {
+		return nil
+	}
+	if len(r.Rejections) == 1 {
+		return r.Rejections[0].Err
+	}
+
+	var causes []internal_error.Cause
+	for _, rejection := range r.Rejections {
+		if rejection.Err.Err != "bad_request" {
+			return rejection.Err
+		}
+
+		if len(rejection.Err.Causes) == 0 {
+			causes = append(causes, internal_error.Cause{Field: rejection.Rule, Message: rejection.Err.Message})
+			continue
+		}
+		causes = append(causes, rejection.Err.Causes...)
+	}
+
+	return internal_error.NewValidationError("Bid rejected", causes...)
+}
+
+// checkBid runs the bid through every rule
+func (bu *BidUseCase) checkBid(
+	ctx context.Context, bid bid_entity.Bid, auction *auction_entity.Auction) BidRuleResult {
+	return CheckBid(ctx, bu.rules, bid, auction)
+}
+
+// CheckBid runs the bid through rules, for callers placing it outside of
+// BidUseCase
+func CheckBid(
+	ctx context.Context, rules []BidRule, bid bid_entity.Bid, auction *auction_entity.Auction) BidRuleResult {
+	var result BidRuleResult
+	for _, rule := range rules {
+		if err := rule.Check(ctx, bid, auction); err != nil {
+			result.Rejections = append(result.Rejections, BidRejection{Rule: rule.Name(), Err: err})
+		}
+	}
+
+	return result
+}
+
+// defaultBidRules are checked on every bid, before the rules passed to
+// NewBidUseCase
+func defaultBidRules(
+	bidRepository bid_entity.BidEntityRepository,
+	userRepository user_entity.UserRepositoryInterface) []BidRule {
+	rules := []BidRule{
+		auctionActiveRule{},
+		userNotBlockedRule{userRepository: userRepository},
+		userNotSellerRule{},
+		newRateLimitRule(),
+		verificationLimitRule{userRepository: userRepository, limits: getBidLimits()},
+		minIncrementRule{bidRepository: bidRepository},
+	}
+	if getRequireVerifiedEmail() {
+		rules = append(rules, emailVerifiedRule{userRepository: userRepository})
+	}
+
+	return rules
+}
+
+// BuyerRules are the rules of defaultBidRules about the bidder rather than
+// the bid, buying an auction now at its price is checked against them
+func BuyerRules(userRepository user_entity.UserRepositoryInterface) []BidRule {
+	rules := []BidRule{
+		userNotBlockedRule{userRepository: userRepository},
+		userNotSellerRule{},
+		verificationLimitRule{userRepository: userRepository, limits: getBidLimits()},
+	}
+	if getRequireVerifiedEmail() {
+		rules = append(rules, emailVerifiedRule{userRepository: userRepository})
+	}
+
+	return rules
+}
+
+// ProxyRules are checked when a proxy is set, against a bid of its maximum,
+// so the bidder learns up front the proxy could never bid that much
+func ProxyRules(userRepository user_entity.UserRepositoryInterface) []BidRule {
+	return append([]BidRule{auctionActiveRule{}}, BuyerRules(userRepository)...)
+}
+
+// ProxyBidCheck checks each bid the repository places for a proxy against
+// BuyerRules, the bidder may have been blocked or lost a verification since
+// the proxy was set
+func ProxyBidCheck(
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) func(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	rules := BuyerRules(userRepository)
+	return func(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+		auction, err := auctionRepository.FindAuctionById(ctx, bid.AuctionId)
+		if err != nil {
+			return err
+		}
+		return CheckBid(ctx, rules, bid, auction).Err()
+	}
+}
+
+type auctionActiveRule struct{}
+
+func (auctionActiveRule) Name() string { return "auction_active" }
+
+func (auctionActiveRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	if auction.Status != auction_entity.Active || bid.Timestamp.After(auction.EndTime) {
+		return internal_error.NewValidationError("Auction is not taking bids",
+			internal_error.Cause{Field: "auction_id", Message: fmt.Sprintf("Auction is %s", auction.Status)})
+	}
+
+	return nil
+}
+
+// userNotBlockedRule reads blocks through the user repository's cache, a
+// block takes a little while to reach every instance
+type userNotBlockedRule struct {
+	userRepository user_entity.UserRepositoryInterface
+}
+
+func (userNotBlockedRule) Name() string { return "user_not_blocked" }
+
+func (r userNotBlockedRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	return blockedUserError(ctx, r.userRepository, bid.UserId, bid.Timestamp)
+}
+
+func blockedUserError(
+	ctx context.Context,
+	userRepository user_entity.UserRepositoryInterface,
+	userId string,
+	now time.Time) *internal_error.InternalError {
+	block, err := userRepository.FindUserBlock(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return block.Check(now)
+}
+
+type userNotSellerRule struct{}
+
+func (userNotSellerRule) Name() string { return "user_not_seller" }
+
+func (userNotSellerRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	return sellerBidError(bid.UserId, auction)
+}
+
+func sellerBidError(userId string, auction *auction_entity.Auction) *internal_error.InternalError {
+	if auction.SellerId != "" && userId == auction.SellerId {
+		return internal_error.NewForbiddenError(bid_entity.ErrSellerBid.Error())
+	}
+
+	return nil
+}
+
+// rateLimitRule counts every bid it checks, including the ones other rules
+// turn down
+type rateLimitRule struct {
+	limiter *bidRateLimiter
+}
+
+func newRateLimitRule() rateLimitRule {
+	return rateLimitRule{limiter: newBidRateLimiter()}
+}
+
+func (rateLimitRule) Name() string { return "rate_limit" }
+
+func (r rateLimitRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	if allowed, retryAfter := r.limiter.allow(bid.UserId, bid.AuctionId, bid.Timestamp); !allowed {
+		return internal_error.NewTooManyRequestsError("Too many bids on this auction, try again later", retryAfter)
+	}
+
+	return nil
+}
+
+// minIncrementRule is checked again when the batch is inserted, the price
+// may rise meanwhile
+type minIncrementRule struct {
+	bidRepository bid_entity.BidEntityRepository
+}
+
+func (minIncrementRule) Name() string { return "min_increment" }
+
+func (r minIncrementRule) Check(
+	ctx context.Context,
+	bid bid_entity.Bid,
+	auction *auction_entity.Auction) *internal_error.InternalError {
+	minimum, err := r.bidRepository.FindMinimumBid(ctx, bid.AuctionId)
+	if err != nil {
+		return err
+	}
+	if bid.Amount < minimum {
+		return internal_error.NewValidationError("Bid is below the minimum increment",
+			internal_error.Cause{Field: "amount", Message: fmt.Sprintf("Must be at least %.2f", minimum)})
+	}
+
+	return nil
+}
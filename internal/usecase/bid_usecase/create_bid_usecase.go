@@ -1,19 +1,34 @@
 package bid_usecase
 
 import (
-	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
 	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/user_entity"
 	"auction_go/internal/internal_error"
 	"context"
-	"os"
-	"strconv"
 	"time"
 )
 
 type BidInputDTO struct {
-	UserId    string  `json:"user_id"`
+	// UserId is the authenticated bidder, it isn't read from the body
+	UserId    string  `json:"-"`
 	AuctionId string  `json:"auction_id"`
 	Amount    float64 `json:"amount"`
+
+	// IdempotencyKey comes from the Idempotency-Key header, a retry with the
+	// same key gets the bid placed the first time
+	IdempotencyKey string `json:"-"`
+}
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// ProxyBidInputDTO sets the most the user will pay, the auction bids for
+// them up to it
+type ProxyBidInputDTO struct {
+	UserId    string  `json:"-"`
+	AuctionId string  `json:"auction_id"`
+	MaxAmount float64 `json:"max_amount"`
 }
 
 type BidOutputDTO struct {
@@ -22,114 +37,163 @@ type BidOutputDTO struct {
 	AuctionId string    `json:"auction_id"`
 	Amount    float64   `json:"amount"`
 	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+
+	// ReserveMet tells whether the bid would sell the product if it won, it
+	// is only set on new and winning bids
+	ReserveMet *bool `json:"reserve_met,omitempty"`
 }
 
-type BidUseCase struct {
-	BidRepository bid_entity.BidEntityRepository
+// BidPageOutputDTO is a page of an auction's bid history, NextCursor fetches
+// the following page and is left out on the last one
+type BidPageOutputDTO struct {
+	Bids       []BidOutputDTO `json:"bids"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
 
-	timer               *time.Timer
-	maxBatchSize        int
-	batchInsertInterval time.Duration
-	bidChannel          chan bid_entity.Bid
+// ActiveBidOutputDTO is where the user stands on an auction still running
+type ActiveBidOutputDTO struct {
+	AuctionId     string  `json:"auction_id"`
+	HighestBid    float64 `json:"highest_bid"`
+	LeadingBid    float64 `json:"leading_bid"`
+	LeadingUserId string  `json:"leading_user_id"`
+	Winning       bool    `json:"winning"`
 }
 
-func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInterface {
-	maxSizeInterval := getMaxBatchSizeInterval()
-	maxBatchSize := getMaxBatchSize()
+type BidUseCase struct {
+	BidRepository     bid_entity.BidEntityRepository
+	AuctionRepository auction_entity.AuctionRepositoryInterface
+	UserRepository    user_entity.UserRepositoryInterface
+	rules             []BidRule
+	proxyRules        []BidRule
+}
 
-	bidUseCase := &BidUseCase{
-		BidRepository:       bidRepository,
-		maxBatchSize:        maxBatchSize,
-		batchInsertInterval: maxSizeInterval,
-		timer:               time.NewTimer(maxSizeInterval),
-		bidChannel:          make(chan bid_entity.Bid, maxBatchSize),
+// NewBidUseCase checks bids against the default rules followed by rules
+func NewBidUseCase(
+	bidRepository bid_entity.BidEntityRepository,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface,
+	rules ...BidRule) BidUseCaseInterface {
+	return &BidUseCase{
+		BidRepository:     bidRepository,
+		AuctionRepository: auctionRepository,
+		UserRepository:    userRepository,
+		rules:             append(defaultBidRules(bidRepository, userRepository), rules...),
+		proxyRules:        ProxyRules(userRepository),
 	}
-
-	bidUseCase.triggerCreateRoutine(context.Background())
-
-	return bidUseCase
 }
 
-var bidBatch []bid_entity.Bid
-
 type BidUseCaseInterface interface {
 	CreateBid(
 		ctx context.Context,
-		bidInputDTO BidInputDTO) *internal_error.InternalError
+		bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
-}
 
-func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
-	go func() {
-		defer close(bu.bidChannel)
-
-		for {
-			select {
-			case bidEntity, ok := <-bu.bidChannel:
-				if !ok {
-					if len(bidBatch) > 0 {
-						if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-							logger.Error("error trying to process bid batch list", err)
-						}
-					}
-					return
-				}
-
-				bidBatch = append(bidBatch, bidEntity)
-
-				if len(bidBatch) >= bu.maxBatchSize {
-					if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-						logger.Error("error trying to process bid batch list", err)
-					}
-
-					bidBatch = nil
-					bu.timer.Reset(bu.batchInsertInterval)
-				}
-			case <-bu.timer.C:
-				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-					logger.Error("error trying to process bid batch list", err)
-				}
-				bidBatch = nil
-				bu.timer.Reset(bu.batchInsertInterval)
-			}
-		}
-	}()
+	CreateProxyBid(
+		ctx context.Context,
+		proxyBidInputDTO ProxyBidInputDTO) *internal_error.InternalError
+
+	FindBidsByAuctionId(
+		ctx context.Context,
+		auctionId, cursor string,
+		limit int64,
+		sort bid_entity.BidSort) (*BidPageOutputDTO, *internal_error.InternalError)
+
+	FindActiveBidsByUser(
+		ctx context.Context, userId string) ([]ActiveBidOutputDTO, *internal_error.InternalError)
+
+	FindLatestBidsByAuctionIds(
+		ctx context.Context,
+		auctionIds []string,
+		limit int64) (map[string][]BidOutputDTO, *internal_error.InternalError)
 }
 
 func (bu *BidUseCase) CreateBid(
 	ctx context.Context,
-	bidInputDTO BidInputDTO) *internal_error.InternalError {
+	bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError) {
 
 	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, bidInputDTO.Amount)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(bidInputDTO.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, internal_error.NewBadRequestError("Idempotency key is too long")
 	}
+	bidEntity.IdempotencyKey = bidInputDTO.IdempotencyKey
 
-	bu.bidChannel <- *bidEntity
+	if original, err := bu.findRetriedBid(ctx, *bidEntity); original != nil || err != nil {
+		return original, err
+	}
+
+	auctionEntity, err := bu.AuctionRepository.FindAuctionById(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	if err := bu.checkBid(ctx, *bidEntity, auctionEntity).Err(); err != nil {
+		return nil, err
+	}
+
+	// Bids are queued per auction in the repository, this waits for this
+	// one to be written
+	if err := bu.BidRepository.IngestBid(ctx, *bidEntity); err != nil {
+		// A retry racing the original is turned down, it gets the original
+		if original, _ := bu.findRetriedBid(ctx, *bidEntity); original != nil {
+			return original, nil
+		}
+		return nil, err
+	}
+
+	reserveMet := auctionEntity.ReserveMet(bidEntity.Amount)
+	return &BidOutputDTO{
+		Id:         bidEntity.Id,
+		UserId:     bidEntity.UserId,
+		AuctionId:  bidEntity.AuctionId,
+		Amount:     bidEntity.Amount,
+		Timestamp:  bidEntity.Timestamp,
+		ReserveMet: &reserveMet,
+	}, nil
 }
 
-func getMaxBatchSizeInterval() time.Duration {
-	batchInsertInterval := os.Getenv("BATCH_INSERT_INTERVAL")
-	duration, err := time.ParseDuration(batchInsertInterval)
+// findRetriedBid returns the bid the user already placed with the bid's
+// idempotency key, nil when this is the first try
+func (bu *BidUseCase) findRetriedBid(
+	ctx context.Context, bid bid_entity.Bid) (*BidOutputDTO, *internal_error.InternalError) {
+	if bid.IdempotencyKey == "" {
+		return nil, nil
+	}
+
+	original, err := bu.BidRepository.FindBidByIdempotencyKey(ctx, bid.UserId, bid.IdempotencyKey)
 	if err != nil {
-		return 3 * time.Minute
+		if err.Err == "not_found" {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	return duration
+	return &BidOutputDTO{
+		Id:         original.Id,
+		UserId:     original.UserId,
+		AuctionId:  original.AuctionId,
+		Amount:     original.Amount,
+		Timestamp:  original.Timestamp,
+		ReserveMet: bu.reserveMet(ctx, original.AuctionId, original.Amount),
+	}, nil
 }
 
-func getMaxBatchSize() int {
-	value, err := strconv.Atoi(os.Getenv("MAX_BATCH_SIZE"))
+// reserveMet is left out when the auction can't be read, the bid itself
+// went through
+func (bu *BidUseCase) reserveMet(ctx context.Context, auctionId string, amount float64) *bool {
+	auctionEntity, err := bu.AuctionRepository.FindAuctionById(ctx, auctionId)
 	if err != nil {
-		return 5
+		return nil
 	}
 
-	return value
+	reserveMet := auctionEntity.ReserveMet(amount)
+	return &reserveMet
 }
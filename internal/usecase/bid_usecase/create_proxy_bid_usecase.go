@@ -0,0 +1,37 @@
+package bid_usecase
+
+import (
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// CreateProxyBid is saved right away rather than batched, the proxy may have
+// to answer the current price as soon as it is set
+func (bu *BidUseCase) CreateProxyBid(
+	ctx context.Context,
+	proxyBidInputDTO ProxyBidInputDTO) *internal_error.InternalError {
+	proxyBid, err := bid_entity.CreateProxyBid(
+		proxyBidInputDTO.UserId, proxyBidInputDTO.AuctionId, proxyBidInputDTO.MaxAmount)
+	if err != nil {
+		return err
+	}
+
+	auctionEntity, err := bu.AuctionRepository.FindAuctionById(ctx, proxyBid.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	// The proxy may end up bidding its whole maximum
+	maxBid := bid_entity.Bid{
+		UserId:    proxyBid.UserId,
+		AuctionId: proxyBid.AuctionId,
+		Amount:    proxyBid.MaxAmount,
+		Timestamp: proxyBid.Timestamp,
+	}
+	if err := CheckBid(ctx, bu.proxyRules, maxBid, auctionEntity).Err(); err != nil {
+		return err
+	}
+
+	return bu.BidRepository.CreateProxyBid(ctx, *proxyBid)
+}
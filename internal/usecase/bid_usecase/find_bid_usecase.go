@@ -1,6 +1,7 @@
 package bid_usecase
 
 import (
+	"auction_go/internal/entity/bid_entity"
 	"auction_go/internal/internal_error"
 	"context"
 )
@@ -26,6 +27,62 @@ func (bu *BidUseCase) FindBidByAuctionId(
 	return bidOutputList, nil
 }
 
+func (bu *BidUseCase) FindBidsByAuctionId(
+	ctx context.Context,
+	auctionId, cursor string,
+	limit int64,
+	sort bid_entity.BidSort) (*BidPageOutputDTO, *internal_error.InternalError) {
+	bidList, nextCursor, err := bu.BidRepository.FindBidsByAuctionIdPaginated(ctx, auctionId, cursor, limit, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	bidOutputList := make([]BidOutputDTO, 0, len(bidList))
+	for _, bid := range bidList {
+		bidOutputList = append(bidOutputList, BidOutputDTO{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+	}
+
+	return &BidPageOutputDTO{
+		Bids:       bidOutputList,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// FindLatestBidsByAuctionIds lists the latest bids of many auctions at once,
+// auctions nobody bid on are left out of the map
+func (bu *BidUseCase) FindLatestBidsByAuctionIds(
+	ctx context.Context,
+	auctionIds []string,
+	limit int64) (map[string][]BidOutputDTO, *internal_error.InternalError) {
+	bidsByAuction, err := bu.BidRepository.FindLatestBidsByAuctionIds(ctx, auctionIds, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	bidOutputsByAuction := make(map[string][]BidOutputDTO, len(bidsByAuction))
+	for auctionId, bidList := range bidsByAuction {
+		bidOutputList := make([]BidOutputDTO, 0, len(bidList))
+		for _, bid := range bidList {
+			bidOutputList = append(bidOutputList, BidOutputDTO{
+				Id:        bid.Id,
+				UserId:    bid.UserId,
+				AuctionId: bid.AuctionId,
+				Amount:    bid.Amount,
+				Timestamp: bid.Timestamp,
+			})
+		}
+		bidOutputsByAuction[auctionId] = bidOutputList
+	}
+
+	return bidOutputsByAuction, nil
+}
+
 func (bu *BidUseCase) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError) {
 	bidEntity, err := bu.BidRepository.FindWinningBidByAuctionId(ctx, auctionId)
@@ -34,12 +91,34 @@ func (bu *BidUseCase) FindWinningBidByAuctionId(
 	}
 
 	bidOutput := &BidOutputDTO{
-		Id:        bidEntity.Id,
-		UserId:    bidEntity.UserId,
-		AuctionId: bidEntity.AuctionId,
-		Amount:    bidEntity.Amount,
-		Timestamp: bidEntity.Timestamp,
+		Id:         bidEntity.Id,
+		UserId:     bidEntity.UserId,
+		AuctionId:  bidEntity.AuctionId,
+		Amount:     bidEntity.Amount,
+		Timestamp:  bidEntity.Timestamp,
+		ReserveMet: bu.reserveMet(ctx, bidEntity.AuctionId, bidEntity.Amount),
 	}
 
 	return bidOutput, nil
 }
+
+func (bu *BidUseCase) FindActiveBidsByUser(
+	ctx context.Context, userId string) ([]ActiveBidOutputDTO, *internal_error.InternalError) {
+	activeBids, err := bu.BidRepository.FindActiveBidsByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	activeBidOutputs := make([]ActiveBidOutputDTO, 0, len(activeBids))
+	for _, activeBid := range activeBids {
+		activeBidOutputs = append(activeBidOutputs, ActiveBidOutputDTO{
+			AuctionId:     activeBid.AuctionId,
+			HighestBid:    activeBid.HighestAmount,
+			LeadingBid:    activeBid.LeadingAmount,
+			LeadingUserId: activeBid.LeadingUserId,
+			Winning:       activeBid.Winning,
+		})
+	}
+
+	return activeBidOutputs, nil
+}
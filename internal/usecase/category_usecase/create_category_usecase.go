@@ -0,0 +1,78 @@
+package category_usecase
+
+import (
+	"auction_go/internal/entity/category_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type CategoryInputDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=50"`
+	ParentId string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+// CategoryOutputDTO is a category along with its subcategories. Active
+// auctions counts the auctions filed right under it, total active auctions
+// adds those of its subcategories
+type CategoryOutputDTO struct {
+	Id                  string              `json:"id"`
+	Name                string              `json:"name"`
+	ParentId            string              `json:"parent_id,omitempty"`
+	Timestamp           time.Time           `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	ActiveAuctions      int64               `json:"active_auctions"`
+	TotalActiveAuctions int64               `json:"total_active_auctions"`
+	Children            []CategoryOutputDTO `json:"children,omitempty"`
+}
+
+type CategoryUseCase struct {
+	categoryRepository category_entity.CategoryRepositoryInterface
+}
+
+func NewCategoryUseCase(categoryRepository category_entity.CategoryRepositoryInterface) CategoryUseCaseInterface {
+	return &CategoryUseCase{
+		categoryRepository: categoryRepository,
+	}
+}
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(
+		ctx context.Context,
+		categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	// FindCategories returns the top-level categories with their
+	// subcategories nested under them
+	FindCategories(
+		ctx context.Context) ([]CategoryOutputDTO, *internal_error.InternalError)
+}
+
+// CreateCategory files the category under its parent, which has to exist
+func (cu *CategoryUseCase) CreateCategory(
+	ctx context.Context,
+	categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError) {
+	category, err := category_entity.CreateCategory(categoryInput.Name, categoryInput.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if category.ParentId != "" {
+		if _, err := cu.categoryRepository.FindCategoryById(ctx, category.ParentId); err != nil {
+			if err.Err == "not_found" {
+				return nil, internal_error.NewValidationError("Invalid category",
+					internal_error.Cause{Field: "parent_id", Message: "Parent category not found"})
+			}
+			return nil, err
+		}
+	}
+
+	if err := cu.categoryRepository.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return &CategoryOutputDTO{
+		Id:        category.Id,
+		Name:      category.Name,
+		ParentId:  category.ParentId,
+		Timestamp: category.Timestamp,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package category_usecase
+
+import (
+	"auction_go/internal/entity/category_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+func (cu *CategoryUseCase) FindCategories(
+	ctx context.Context) ([]CategoryOutputDTO, *internal_error.InternalError) {
+	categories, err := cu.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := cu.categoryRepository.CountActiveAuctions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(categories))
+	children := make(map[string][]category_entity.Category)
+	for _, category := range categories {
+		known[category.Id] = true
+	}
+	for _, category := range categories {
+		// A category whose parent is gone is shown at the top
+		parentId := category.ParentId
+		if !known[parentId] {
+			parentId = ""
+		}
+		children[parentId] = append(children[parentId], category)
+	}
+
+	return categoryTree(children, counts, ""), nil
+}
+
+// categoryTree builds the subcategories of parentId, categories come
+// ordered by name and keep that order
+func categoryTree(
+	children map[string][]category_entity.Category,
+	counts category_entity.CategoryCount,
+	parentId string) []CategoryOutputDTO {
+	categoryOutputs := make([]CategoryOutputDTO, 0, len(children[parentId]))
+	for _, category := range children[parentId] {
+		categoryOutput := CategoryOutputDTO{
+			Id:             category.Id,
+			Name:           category.Name,
+			ParentId:       category.ParentId,
+			Timestamp:      category.Timestamp,
+			ActiveAuctions: counts[category.Key],
+			Children:       categoryTree(children, counts, category.Id),
+		}
+
+		categoryOutput.TotalActiveAuctions = categoryOutput.ActiveAuctions
+		for _, child := range categoryOutput.Children {
+			categoryOutput.TotalActiveAuctions += child.TotalActiveAuctions
+		}
+
+		categoryOutputs = append(categoryOutputs, categoryOutput)
+	}
+
+	return categoryOutputs
+}
@@ -0,0 +1,349 @@
+package checkout_usecase
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/configuration/pix"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type CheckoutInputDTO struct {
+	AuctionId string `json:"auction_id" binding:"required,uuid"`
+	// UserId is the authenticated winner, it isn't read from the body
+	UserId string `json:"-"`
+}
+
+type AddressInputDTO struct {
+	Street     string `json:"street" binding:"required"`
+	Number     string `json:"number"`
+	City       string `json:"city" binding:"required"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code" binding:"required"`
+	Country    string `json:"country" binding:"required,len=2"`
+}
+
+type ShippingInputDTO struct {
+	Option string `json:"option" binding:"required"`
+}
+
+type ShipmentInputDTO struct {
+	Carrier        string `json:"carrier" binding:"required"`
+	TrackingNumber string `json:"tracking_number" binding:"required"`
+}
+
+type CarrierUpdateInputDTO struct {
+	Carrier        string `json:"carrier" binding:"required"`
+	TrackingNumber string `json:"tracking_number" binding:"required"`
+	Status         string `json:"status" binding:"required,oneof=in_transit out_for_delivery exception delivered"`
+}
+
+type PaymentInputDTO struct {
+	Method string `json:"method" binding:"required,oneof=pix"`
+}
+
+// PixWebhookInputDTO follows the notification body PIX providers post for
+// settled charges, amounts come as decimal strings
+type PixWebhookInputDTO struct {
+	Pix []PixNotificationInputDTO `json:"pix" binding:"required,min=1,dive"`
+}
+
+type PixNotificationInputDTO struct {
+	EndToEndId string `json:"endToEndId" binding:"required"`
+	TxId       string `json:"txid" binding:"required"`
+	Amount     string `json:"valor" binding:"required"`
+
+	// Refunds are the devolutions of the charge, providers notify them on
+	// the same webhook
+	Refunds []PixRefundInputDTO `json:"devolucoes" binding:"dive"`
+}
+
+type PixRefundInputDTO struct {
+	Id     string `json:"id" binding:"required"`
+	Amount string `json:"valor" binding:"required"`
+	Status string `json:"status" binding:"required"`
+}
+
+type AddressOutputDTO struct {
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+type ShipmentOutputDTO struct {
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	CarrierStatus  string    `json:"carrier_status"`
+	UpdatedAt      time.Time `json:"updated_at" time_format:"2006-01-02 15:04:05"`
+}
+
+type PaymentOutputDTO struct {
+	Method       string     `json:"method"`
+	TxId         string     `json:"txid"`
+	PixCopyPaste string     `json:"pix_copy_paste,omitempty"`
+	PaidAt       *time.Time `json:"paid_at,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+// RefundOutputDTO is what the buyer is owed after a return, RefundedAt is
+// set once it was paid back
+type RefundOutputDTO struct {
+	ReturnRequestId string     `json:"return_request_id"`
+	Amount          float64    `json:"amount"`
+	RequestedAt     time.Time  `json:"requested_at" time_format:"2006-01-02 15:04:05"`
+	RefundedAt      *time.Time `json:"refunded_at,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+type ShippingOptionOutputDTO struct {
+	Code  string  `json:"code"`
+	Price float64 `json:"price"`
+}
+
+type CheckoutOutputDTO struct {
+	Id             string             `json:"id"`
+	AuctionId      string             `json:"auction_id"`
+	BuyerUserId    string             `json:"buyer_user_id"`
+	ItemAmount     float64            `json:"item_amount"`
+	Address        *AddressOutputDTO  `json:"address,omitempty"`
+	ShippingOption string             `json:"shipping_option,omitempty"`
+	ShippingAmount float64            `json:"shipping_amount"`
+	TotalAmount    float64            `json:"total_amount"`
+	Payment        *PaymentOutputDTO  `json:"payment,omitempty"`
+	Shipment       *ShipmentOutputDTO `json:"shipment,omitempty"`
+	Refund         *RefundOutputDTO   `json:"refund,omitempty"`
+	Status         CheckoutStatus     `json:"status"`
+	DeliveredAt    *time.Time         `json:"delivered_at,omitempty" time_format:"2006-01-02 15:04:05"`
+	Timestamp      time.Time          `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type CheckoutStatus int64
+
+type CheckoutUseCase struct {
+	checkoutRepository checkout_entity.CheckoutRepositoryInterface
+	auctionRepository  auction_entity.AuctionRepositoryInterface
+	bidRepository      bid_entity.BidEntityRepository
+
+	shippingOptions map[string]checkout_entity.ShippingOption
+	pixMerchant     *pix.Charge
+}
+
+func NewCheckoutUseCase(
+	checkoutRepository checkout_entity.CheckoutRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	bidRepository bid_entity.BidEntityRepository) CheckoutUseCaseInterface {
+	return &CheckoutUseCase{
+		checkoutRepository: checkoutRepository,
+		auctionRepository:  auctionRepository,
+		bidRepository:      bidRepository,
+		shippingOptions:    getShippingOptions(),
+		pixMerchant:        getPixMerchant(),
+	}
+}
+
+type CheckoutUseCaseInterface interface {
+	CreateCheckout(
+		ctx context.Context,
+		checkoutInput CheckoutInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	FindCheckoutById(
+		ctx context.Context, userId, id string) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	FindCheckoutByAuctionId(
+		ctx context.Context, userId, auctionId string) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	FindShippingOptions() []ShippingOptionOutputDTO
+
+	ConfirmAddress(
+		ctx context.Context,
+		userId, id string,
+		addressInput AddressInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	SelectShipping(
+		ctx context.Context,
+		userId, id string,
+		shippingInput ShippingInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	SelectPayment(
+		ctx context.Context,
+		userId, id string,
+		paymentInput PaymentInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	ConfirmPayment(
+		ctx context.Context, id string) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	ConfirmPixPayments(
+		ctx context.Context,
+		pixWebhookInput PixWebhookInputDTO) *internal_error.InternalError
+
+	AttachShipment(
+		ctx context.Context,
+		userId, id string,
+		shipmentInput ShipmentInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError)
+
+	ApplyCarrierUpdate(
+		ctx context.Context,
+		carrierUpdateInput CarrierUpdateInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError)
+}
+
+// CreateCheckout opens the checkout of a completed auction for its winner,
+// calling it again returns the checkout already in progress
+func (cu *CheckoutUseCase) CreateCheckout(
+	ctx context.Context,
+	checkoutInput CheckoutInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	auction, err := cu.auctionRepository.FindAuctionById(ctx, checkoutInput.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != auction_entity.Completed {
+		return nil, internal_error.NewBadRequestError("Checkout is only available for completed auctions")
+	}
+
+	// Auctions record their winner as they close, which may not be the
+	// highest bid when it was bought now. Older auctions only have the bids
+	winnerUserId, winningAmount := auction.WinnerUserId, auction.WinningAmount
+	if winnerUserId == "" {
+		winningBid, err := cu.bidRepository.FindWinningBidByAuctionId(ctx, auction.Id)
+		if err != nil {
+			return nil, err
+		}
+		winnerUserId, winningAmount = winningBid.UserId, winningBid.Amount
+	}
+
+	if winnerUserId != checkoutInput.UserId {
+		return nil, internal_error.NewBadRequestError("Only the auction winner can check out")
+	}
+
+	checkout, err := checkout_entity.CreateCheckout(auction.Id, winnerUserId, winningAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	storedCheckout, err := cu.checkoutRepository.CreateCheckout(ctx, checkout)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCheckoutOutputDTO(storedCheckout), nil
+}
+
+func toCheckoutOutputDTO(checkout *checkout_entity.Checkout) *CheckoutOutputDTO {
+	checkoutOutput := &CheckoutOutputDTO{
+		Id:             checkout.Id,
+		AuctionId:      checkout.AuctionId,
+		BuyerUserId:    checkout.BuyerUserId,
+		ItemAmount:     checkout.ItemAmount,
+		ShippingOption: checkout.ShippingOption,
+		ShippingAmount: checkout.ShippingAmount,
+		TotalAmount:    checkout.TotalAmount,
+		Status:         CheckoutStatus(checkout.Status),
+		Timestamp:      checkout.Timestamp,
+	}
+
+	if address := checkout.Address; address != nil {
+		checkoutOutput.Address = &AddressOutputDTO{
+			Street:     address.Street,
+			Number:     address.Number,
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		}
+	}
+
+	if payment := checkout.Payment; payment != nil {
+		checkoutOutput.Payment = &PaymentOutputDTO{
+			Method:       payment.Method,
+			TxId:         payment.TxId,
+			PixCopyPaste: payment.PixPayload,
+		}
+		if !payment.PaidAt.IsZero() {
+			paidAt := payment.PaidAt
+			checkoutOutput.Payment.PaidAt = &paidAt
+		}
+	}
+
+	if shipment := checkout.Shipment; shipment != nil {
+		checkoutOutput.Shipment = &ShipmentOutputDTO{
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			CarrierStatus:  shipment.CarrierStatus,
+			UpdatedAt:      shipment.UpdatedAt,
+		}
+	}
+
+	if refund := checkout.Refund; refund != nil {
+		checkoutOutput.Refund = &RefundOutputDTO{
+			ReturnRequestId: refund.ReturnRequestId,
+			Amount:          refund.Amount,
+			RequestedAt:     refund.RequestedAt,
+		}
+		if !refund.RefundedAt.IsZero() {
+			refundedAt := refund.RefundedAt
+			checkoutOutput.Refund.RefundedAt = &refundedAt
+		}
+	}
+
+	if !checkout.DeliveredAt.IsZero() {
+		deliveredAt := checkout.DeliveredAt
+		checkoutOutput.DeliveredAt = &deliveredAt
+	}
+
+	return checkoutOutput
+}
+
+// getShippingOptions reads SHIPPING_OPTIONS as "code=price" pairs, for
+// instance "standard=25,express=60,pickup=0"
+func getShippingOptions() map[string]checkout_entity.ShippingOption {
+	shippingOptionList, ok := os.LookupEnv("SHIPPING_OPTIONS")
+	if !ok {
+		shippingOptionList = "standard=25,express=60,pickup=0"
+	}
+
+	shippingOptions := make(map[string]checkout_entity.ShippingOption)
+	for _, pair := range strings.Split(shippingOptionList, ",") {
+		code, rawPrice, found := strings.Cut(strings.TrimSpace(pair), "=")
+		price, err := strconv.ParseFloat(strings.TrimSpace(rawPrice), 64)
+		if !found || code == "" || err != nil || price < 0 {
+			logger.Error("Error trying to parse shipping option",
+				errors.New("shipping options must be declared as code=price"), zap.String("option", pair))
+			continue
+		}
+
+		shippingOptions[strings.TrimSpace(code)] = checkout_entity.ShippingOption{
+			Code:  strings.TrimSpace(code),
+			Price: price,
+		}
+	}
+
+	return shippingOptions
+}
+
+func sortedShippingOptions(
+	shippingOptions map[string]checkout_entity.ShippingOption) []ShippingOptionOutputDTO {
+	shippingOptionOutputs := make([]ShippingOptionOutputDTO, 0, len(shippingOptions))
+	for _, option := range shippingOptions {
+		shippingOptionOutputs = append(shippingOptionOutputs, ShippingOptionOutputDTO{
+			Code:  option.Code,
+			Price: option.Price,
+		})
+	}
+
+	sort.Slice(shippingOptionOutputs, func(i, j int) bool {
+		return shippingOptionOutputs[i].Price < shippingOptionOutputs[j].Price
+	})
+
+	return shippingOptionOutputs
+}
@@ -0,0 +1,77 @@
+package checkout_usecase
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// FindCheckoutById is open to the buyer and to the seller, who ships to the
+// address
+func (cu *CheckoutUseCase) FindCheckoutById(
+	ctx context.Context, userId, id string) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	checkout, err := cu.checkoutRepository.FindCheckoutById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cu.checkParty(ctx, checkout, userId); err != nil {
+		return nil, err
+	}
+
+	return toCheckoutOutputDTO(checkout), nil
+}
+
+func (cu *CheckoutUseCase) FindCheckoutByAuctionId(
+	ctx context.Context, userId, auctionId string) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	checkout, err := cu.checkoutRepository.FindCheckoutByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cu.checkParty(ctx, checkout, userId); err != nil {
+		return nil, err
+	}
+
+	return toCheckoutOutputDTO(checkout), nil
+}
+
+func (cu *CheckoutUseCase) FindShippingOptions() []ShippingOptionOutputDTO {
+	return sortedShippingOptions(cu.shippingOptions)
+}
+
+// checkParty turns away everyone but the buyer and the seller of the
+// checkout's auction
+func (cu *CheckoutUseCase) checkParty(
+	ctx context.Context, checkout *checkout_entity.Checkout, userId string) *internal_error.InternalError {
+	if checkout.BuyerUserId == userId {
+		return nil
+	}
+
+	isSeller, err := cu.isSeller(ctx, checkout, userId)
+	if err != nil {
+		return err
+	} else if !isSeller {
+		return internal_error.NewForbiddenError("Only the buyer and the seller can see this checkout")
+	}
+
+	return nil
+}
+
+func checkBuyer(checkout *checkout_entity.Checkout, userId string) *internal_error.InternalError {
+	if checkout.BuyerUserId != userId {
+		return internal_error.NewForbiddenError("Only the buyer can change this checkout")
+	}
+
+	return nil
+}
+
+func (cu *CheckoutUseCase) isSeller(
+	ctx context.Context, checkout *checkout_entity.Checkout, userId string) (bool, *internal_error.InternalError) {
+	auction, err := cu.auctionRepository.FindAuctionById(ctx, checkout.AuctionId)
+	if err != nil {
+		return false, err
+	}
+
+	return auction.SellerId != "" && auction.SellerId == userId, nil
+}
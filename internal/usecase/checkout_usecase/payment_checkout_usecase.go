@@ -0,0 +1,142 @@
+package checkout_usecase
+
+import (
+	"auction_go/configuration/pix"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// pixRefundReturned is the status of a devolution that reached the payer
+const pixRefundReturned = "DEVOLVIDO"
+
+// SelectPayment issues a new charge for the checkout total, for PIX that is
+// the copy and paste payload the buyer pays from their bank app
+func (cu *CheckoutUseCase) SelectPayment(
+	ctx context.Context,
+	userId, id string,
+	paymentInput PaymentInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	if paymentInput.Method != checkout_entity.PaymentMethodPix {
+		return nil, internal_error.NewBadRequestError("Payment method is not supported")
+	}
+
+	if cu.pixMerchant == nil {
+		return nil, internal_error.NewBadRequestError("PIX payments are not available")
+	}
+
+	return cu.updateCheckout(ctx, id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		if err := checkBuyer(checkout, userId); err != nil {
+			return err
+		}
+
+		charge := *cu.pixMerchant
+		charge.TxId = strings.ReplaceAll(uuid.New().String(), "-", "")[:pix.MaxTxIdLength]
+		charge.Amount = checkout.TotalAmount
+
+		return checkout.SelectPayment(checkout_entity.Payment{
+			Method:     checkout_entity.PaymentMethodPix,
+			TxId:       charge.TxId,
+			PixPayload: pix.Payload(charge),
+		})
+	})
+}
+
+// ConfirmPixPayments marks the checkouts of settled PIX charges as paid.
+// Providers retry until they get a success, so a notification already
+// applied is skipped instead of failing the whole batch
+func (cu *CheckoutUseCase) ConfirmPixPayments(
+	ctx context.Context,
+	pixWebhookInput PixWebhookInputDTO) *internal_error.InternalError {
+	for _, notification := range pixWebhookInput.Pix {
+		checkout, err := cu.checkoutRepository.FindCheckoutByPaymentTxId(ctx, notification.TxId)
+		if err != nil {
+			return err
+		}
+
+		if len(notification.Refunds) > 0 {
+			if err := cu.confirmPixRefunds(ctx, checkout.Id, notification.Refunds); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if checkout.Status >= checkout_entity.Paid && checkout.Payment.EndToEndId == notification.EndToEndId {
+			continue
+		}
+
+		amount, errParse := strconv.ParseFloat(notification.Amount, 64)
+		if errParse != nil || toCents(amount) != toCents(checkout.TotalAmount) {
+			return internal_error.NewBadRequestError("PIX amount does not match the checkout total")
+		}
+
+		_, err = cu.updateCheckout(ctx, checkout.Id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+			if checkout.Payment == nil || checkout.Payment.TxId != notification.TxId {
+				return internal_error.NewBadRequestError("PIX charge was replaced by a newer one")
+			}
+
+			checkout.Payment.EndToEndId = notification.EndToEndId
+			return checkout.MarkPaid()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// confirmPixRefunds settles the refund of a returned checkout once the
+// devolutions that reached the buyer cover it, the ones still in progress
+// are notified again when they settle
+func (cu *CheckoutUseCase) confirmPixRefunds(
+	ctx context.Context, id string, refunds []PixRefundInputDTO) *internal_error.InternalError {
+	var refunded float64
+	for _, refund := range refunds {
+		if refund.Status != pixRefundReturned {
+			continue
+		}
+
+		amount, errParse := strconv.ParseFloat(refund.Amount, 64)
+		if errParse != nil {
+			return internal_error.NewBadRequestError("PIX refund amount is not a valid value")
+		}
+		refunded += amount
+	}
+	if refunded == 0 {
+		return nil
+	}
+
+	_, err := cu.updateCheckout(ctx, id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		if checkout.Refund != nil && toCents(refunded) < toCents(checkout.Refund.Amount) {
+			return internal_error.NewBadRequestError("PIX refund does not cover the amount owed")
+		}
+
+		return checkout.MarkRefunded()
+	})
+	return err
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// getPixMerchant reads the receiving PIX key and the merchant name and city
+// printed on the charge, PIX stays disabled while PIX_KEY is unset
+func getPixMerchant() *pix.Charge {
+	key := os.Getenv(pix.PIX_KEY)
+	if key == "" {
+		return nil
+	}
+
+	return &pix.Charge{
+		Key:          key,
+		MerchantName: os.Getenv(pix.PIX_MERCHANT_NAME),
+		MerchantCity: os.Getenv(pix.PIX_MERCHANT_CITY),
+	}
+}
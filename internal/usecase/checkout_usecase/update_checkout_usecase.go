@@ -0,0 +1,108 @@
+package checkout_usecase
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+)
+
+func (cu *CheckoutUseCase) ConfirmAddress(
+	ctx context.Context,
+	userId, id string,
+	addressInput AddressInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	return cu.updateCheckout(ctx, id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		if err := checkBuyer(checkout, userId); err != nil {
+			return err
+		}
+
+		return checkout.ConfirmAddress(checkout_entity.Address{
+			Street:     addressInput.Street,
+			Number:     addressInput.Number,
+			City:       addressInput.City,
+			State:      addressInput.State,
+			PostalCode: addressInput.PostalCode,
+			Country:    addressInput.Country,
+		})
+	})
+}
+
+// SelectShipping looks the price up server-side, the client only picks a code
+func (cu *CheckoutUseCase) SelectShipping(
+	ctx context.Context,
+	userId, id string,
+	shippingInput ShippingInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	option, ok := cu.shippingOptions[shippingInput.Option]
+	if !ok {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("Shipping option %s is not available", shippingInput.Option))
+	}
+
+	return cu.updateCheckout(ctx, id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		if err := checkBuyer(checkout, userId); err != nil {
+			return err
+		}
+
+		return checkout.SelectShipping(option)
+	})
+}
+
+// ConfirmPayment is called by payment providers once the charge is settled
+func (cu *CheckoutUseCase) ConfirmPayment(
+	ctx context.Context, id string) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	return cu.updateCheckout(ctx, id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		return checkout.MarkPaid()
+	})
+}
+
+// AttachShipment is left to the seller of the checkout's auction
+func (cu *CheckoutUseCase) AttachShipment(
+	ctx context.Context,
+	userId, id string,
+	shipmentInput ShipmentInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	return cu.updateCheckout(ctx, id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		isSeller, err := cu.isSeller(ctx, checkout, userId)
+		if err != nil {
+			return err
+		} else if !isSeller {
+			return internal_error.NewForbiddenError("Only the seller can ship this checkout")
+		}
+
+		return checkout.AttachShipment(shipmentInput.Carrier, shipmentInput.TrackingNumber)
+	})
+}
+
+// ApplyCarrierUpdate consumes a tracking event pushed by the carrier
+func (cu *CheckoutUseCase) ApplyCarrierUpdate(
+	ctx context.Context,
+	carrierUpdateInput CarrierUpdateInputDTO) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	checkout, err := cu.checkoutRepository.FindCheckoutByTrackingNumber(
+		ctx, carrierUpdateInput.Carrier, carrierUpdateInput.TrackingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return cu.updateCheckout(ctx, checkout.Id, func(checkout *checkout_entity.Checkout) *internal_error.InternalError {
+		return checkout.ApplyCarrierStatus(carrierUpdateInput.Status)
+	})
+}
+
+func (cu *CheckoutUseCase) updateCheckout(
+	ctx context.Context,
+	id string,
+	apply func(checkout *checkout_entity.Checkout) *internal_error.InternalError) (*CheckoutOutputDTO, *internal_error.InternalError) {
+	checkout, err := cu.checkoutRepository.FindCheckoutById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(checkout); err != nil {
+		return nil, err
+	}
+
+	if err := cu.checkoutRepository.UpdateCheckout(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	return toCheckoutOutputDTO(checkout), nil
+}
@@ -0,0 +1,91 @@
+package device_usecase
+
+import (
+	"auction_go/internal/entity/device_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type DeviceInputDTO struct {
+	UserId   string `json:"-"`
+	Platform string `json:"platform" binding:"required,oneof=fcm apns"`
+	Token    string `json:"token" binding:"required,max=4096"`
+}
+
+// DeviceOutputDTO leaves the push token out, clients already hold it
+type DeviceOutputDTO struct {
+	Id        string    `json:"id"`
+	Platform  string    `json:"platform"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type DeviceUseCase struct {
+	deviceRepository device_entity.DeviceRepositoryInterface
+}
+
+func NewDeviceUseCase(deviceRepository device_entity.DeviceRepositoryInterface) DeviceUseCaseInterface {
+	return &DeviceUseCase{
+		deviceRepository: deviceRepository,
+	}
+}
+
+type DeviceUseCaseInterface interface {
+	RegisterDevice(
+		ctx context.Context,
+		deviceInput DeviceInputDTO) (*DeviceOutputDTO, *internal_error.InternalError)
+
+	DeleteDevice(
+		ctx context.Context, userId, deviceId string) *internal_error.InternalError
+
+	ListDevices(
+		ctx context.Context, userId string) ([]DeviceOutputDTO, *internal_error.InternalError)
+}
+
+// RegisterDevice is safe to call on every app start, registering a token
+// again only refreshes it
+func (du *DeviceUseCase) RegisterDevice(
+	ctx context.Context,
+	deviceInput DeviceInputDTO) (*DeviceOutputDTO, *internal_error.InternalError) {
+	device, err := device_entity.CreateDevice(
+		deviceInput.UserId, device_entity.Platform(deviceInput.Platform), deviceInput.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	registered, err := du.deviceRepository.RegisterDevice(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	output := toDeviceOutputDTO(*registered)
+	return &output, nil
+}
+
+func (du *DeviceUseCase) DeleteDevice(
+	ctx context.Context, userId, deviceId string) *internal_error.InternalError {
+	return du.deviceRepository.DeleteDevice(ctx, userId, deviceId)
+}
+
+func (du *DeviceUseCase) ListDevices(
+	ctx context.Context, userId string) ([]DeviceOutputDTO, *internal_error.InternalError) {
+	devices, err := du.deviceRepository.FindDevicesByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]DeviceOutputDTO, 0, len(devices))
+	for _, device := range devices {
+		output = append(output, toDeviceOutputDTO(device))
+	}
+
+	return output, nil
+}
+
+func toDeviceOutputDTO(device device_entity.Device) DeviceOutputDTO {
+	return DeviceOutputDTO{
+		Id:        device.Id,
+		Platform:  string(device.Platform),
+		Timestamp: device.Timestamp,
+	}
+}
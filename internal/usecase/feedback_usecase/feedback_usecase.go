@@ -0,0 +1,126 @@
+package feedback_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/feedback_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type FeedbackInputDTO struct {
+	// BuyerId is the authenticated user, it isn't read from the body
+	BuyerId string `json:"-"`
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+type FeedbackOutputDTO struct {
+	AuctionId string    `json:"auction_id"`
+	SellerId  string    `json:"seller_id"`
+	BuyerId   string    `json:"buyer_id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment,omitempty"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type FeedbackPageOutputDTO struct {
+	Feedback []FeedbackOutputDTO `json:"feedback"`
+	Page     int64               `json:"page"`
+	PageSize int64               `json:"page_size"`
+	Total    int64               `json:"total"`
+}
+
+type FeedbackUseCase struct {
+	feedbackRepository feedback_entity.FeedbackRepositoryInterface
+	auctionRepository  auction_entity.AuctionRepositoryInterface
+}
+
+func NewFeedbackUseCase(
+	feedbackRepository feedback_entity.FeedbackRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface) FeedbackUseCaseInterface {
+	return &FeedbackUseCase{
+		feedbackRepository: feedbackRepository,
+		auctionRepository:  auctionRepository,
+	}
+}
+
+type FeedbackUseCaseInterface interface {
+	SubmitFeedback(
+		ctx context.Context,
+		auctionId string,
+		feedbackInput FeedbackInputDTO) (*FeedbackOutputDTO, *internal_error.InternalError)
+
+	FindFeedbackBySellerId(
+		ctx context.Context,
+		sellerId string,
+		page, pageSize int64) (*FeedbackPageOutputDTO, *internal_error.InternalError)
+}
+
+// SubmitFeedback rates the seller of a completed auction, only its winner
+// may leave feedback
+func (fu *FeedbackUseCase) SubmitFeedback(
+	ctx context.Context,
+	auctionId string,
+	feedbackInput FeedbackInputDTO) (*FeedbackOutputDTO, *internal_error.InternalError) {
+	auction, err := fu.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != auction_entity.Completed || auction.WinnerUserId == "" {
+		return nil, internal_error.NewBadRequestError("Feedback can only be left on auctions that sold")
+	}
+	if auction.WinnerUserId != feedbackInput.BuyerId {
+		return nil, internal_error.NewForbiddenError("Only the winner of the auction may leave feedback")
+	}
+	if auction.SellerId == "" {
+		return nil, internal_error.NewBadRequestError("The auction has no seller to rate")
+	}
+
+	feedback, err := feedback_entity.CreateFeedback(
+		auction.Id, auction.SellerId, feedbackInput.BuyerId, feedbackInput.Rating, feedbackInput.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fu.feedbackRepository.CreateFeedback(ctx, feedback); err != nil {
+		return nil, err
+	}
+
+	return toFeedbackOutputDTO(*feedback), nil
+}
+
+func (fu *FeedbackUseCase) FindFeedbackBySellerId(
+	ctx context.Context,
+	sellerId string,
+	page, pageSize int64) (*FeedbackPageOutputDTO, *internal_error.InternalError) {
+	feedback, total, err := fu.feedbackRepository.FindFeedbackBySellerId(
+		ctx, sellerId, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]FeedbackOutputDTO, 0, len(feedback))
+	for _, value := range feedback {
+		output = append(output, *toFeedbackOutputDTO(value))
+	}
+
+	return &FeedbackPageOutputDTO{
+		Feedback: output,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+func toFeedbackOutputDTO(feedback feedback_entity.Feedback) *FeedbackOutputDTO {
+	return &FeedbackOutputDTO{
+		AuctionId: feedback.AuctionId,
+		SellerId:  feedback.SellerId,
+		BuyerId:   feedback.BuyerId,
+		Rating:    feedback.Rating,
+		Comment:   feedback.Comment,
+		Timestamp: feedback.Timestamp,
+	}
+}
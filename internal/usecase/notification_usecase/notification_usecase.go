@@ -0,0 +1,94 @@
+package notification_usecase
+
+import (
+	"auction_go/internal/entity/notification_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+// PreferencesInputDTO replaces the user's preferences as a whole
+type PreferencesInputDTO struct {
+	UserId        string   `json:"-"`
+	MutedChannels []string `json:"muted_channels" binding:"dive,oneof=email push"`
+	MutedEvents   []string `json:"muted_events" binding:"dive,oneof=outbid auction_won auction_ended ending_soon saved_search_match"`
+	Digest        string   `json:"digest" binding:"required,oneof=off hourly daily"`
+}
+
+type PreferencesOutputDTO struct {
+	MutedChannels []string `json:"muted_channels"`
+	MutedEvents   []string `json:"muted_events"`
+	Digest        string   `json:"digest"`
+}
+
+type NotificationUseCase struct {
+	notificationRepository notification_entity.NotificationRepositoryInterface
+}
+
+func NewNotificationUseCase(
+	notificationRepository notification_entity.NotificationRepositoryInterface) NotificationUseCaseInterface {
+	return &NotificationUseCase{
+		notificationRepository: notificationRepository,
+	}
+}
+
+type NotificationUseCaseInterface interface {
+	FindPreferences(
+		ctx context.Context, userId string) (*PreferencesOutputDTO, *internal_error.InternalError)
+
+	UpdatePreferences(
+		ctx context.Context,
+		preferencesInput PreferencesInputDTO) (*PreferencesOutputDTO, *internal_error.InternalError)
+}
+
+func (nu *NotificationUseCase) FindPreferences(
+	ctx context.Context, userId string) (*PreferencesOutputDTO, *internal_error.InternalError) {
+	preferences, err := nu.notificationRepository.FindPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferencesOutputDTO(preferences), nil
+}
+
+func (nu *NotificationUseCase) UpdatePreferences(
+	ctx context.Context,
+	preferencesInput PreferencesInputDTO) (*PreferencesOutputDTO, *internal_error.InternalError) {
+	preferences := &notification_entity.Preferences{
+		UserId:    preferencesInput.UserId,
+		Digest:    notification_entity.DigestFrequency(preferencesInput.Digest),
+		UpdatedAt: time.Now(),
+	}
+	for _, channel := range preferencesInput.MutedChannels {
+		preferences.MutedChannels = append(preferences.MutedChannels, notification_entity.Channel(channel))
+	}
+	for _, event := range preferencesInput.MutedEvents {
+		preferences.MutedEvents = append(preferences.MutedEvents, notification_entity.EventType(event))
+	}
+
+	if err := preferences.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := nu.notificationRepository.SavePreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+
+	return toPreferencesOutputDTO(preferences), nil
+}
+
+func toPreferencesOutputDTO(preferences *notification_entity.Preferences) *PreferencesOutputDTO {
+	output := &PreferencesOutputDTO{
+		MutedChannels: make([]string, 0, len(preferences.MutedChannels)),
+		MutedEvents:   make([]string, 0, len(preferences.MutedEvents)),
+		Digest:        string(preferences.Digest),
+	}
+	for _, channel := range preferences.MutedChannels {
+		output.MutedChannels = append(output.MutedChannels, string(channel))
+	}
+	for _, event := range preferences.MutedEvents {
+		output.MutedEvents = append(output.MutedEvents, string(event))
+	}
+
+	return output
+}
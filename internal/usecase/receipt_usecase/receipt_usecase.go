@@ -0,0 +1,80 @@
+package receipt_usecase
+
+import (
+	"auction_go/internal/entity/receipt_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type ReceiptOutputDTO struct {
+	Id              string    `json:"id"`
+	AuctionId       string    `json:"auction_id"`
+	ProductName     string    `json:"product_name"`
+	FinalPrice      float64   `json:"final_price"`
+	Fees            float64   `json:"fees"`
+	TotalAmount     float64   `json:"total_amount"`
+	PaymentLink     string    `json:"payment_link"`
+	PaymentDeadline time.Time `json:"payment_deadline" time_format:"2006-01-02 15:04:05"`
+	Timestamp       time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type ReceiptUseCase struct {
+	receiptRepository receipt_entity.ReceiptRepositoryInterface
+}
+
+func NewReceiptUseCase(receiptRepository receipt_entity.ReceiptRepositoryInterface) ReceiptUseCaseInterface {
+	return &ReceiptUseCase{
+		receiptRepository: receiptRepository,
+	}
+}
+
+// ReceiptUseCaseInterface only reads receipts, they are issued by the email
+// notifier as the auctions close
+type ReceiptUseCaseInterface interface {
+	ListReceipts(
+		ctx context.Context, userId string) ([]ReceiptOutputDTO, *internal_error.InternalError)
+
+	FindReceiptById(
+		ctx context.Context, userId, id string) (*ReceiptOutputDTO, *internal_error.InternalError)
+}
+
+func (ru *ReceiptUseCase) ListReceipts(
+	ctx context.Context, userId string) ([]ReceiptOutputDTO, *internal_error.InternalError) {
+	receipts, err := ru.receiptRepository.FindReceiptsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]ReceiptOutputDTO, 0, len(receipts))
+	for _, receipt := range receipts {
+		output = append(output, toReceiptOutputDTO(receipt))
+	}
+
+	return output, nil
+}
+
+func (ru *ReceiptUseCase) FindReceiptById(
+	ctx context.Context, userId, id string) (*ReceiptOutputDTO, *internal_error.InternalError) {
+	receipt, err := ru.receiptRepository.FindReceiptById(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := toReceiptOutputDTO(*receipt)
+	return &output, nil
+}
+
+func toReceiptOutputDTO(receipt receipt_entity.Receipt) ReceiptOutputDTO {
+	return ReceiptOutputDTO{
+		Id:              receipt.Id,
+		AuctionId:       receipt.AuctionId,
+		ProductName:     receipt.ProductName,
+		FinalPrice:      receipt.FinalPrice,
+		Fees:            receipt.Fees,
+		TotalAmount:     receipt.TotalAmount,
+		PaymentLink:     receipt.PaymentLink,
+		PaymentDeadline: receipt.PaymentDeadline,
+		Timestamp:       receipt.Timestamp,
+	}
+}
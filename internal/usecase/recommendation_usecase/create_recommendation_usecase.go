@@ -0,0 +1,176 @@
+package recommendation_usecase
+
+import (
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/bid_entity"
+	"auction_go/internal/entity/recommendation_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	maxCategoriesPerUser  = 3
+	maxAuctionsPerUser    = 10
+	maxSimilarAuctions    = 10
+	defaultRefreshTimeout = 5 * time.Minute
+)
+
+type RecommendedAuctionOutputDTO struct {
+	AuctionId   string  `json:"auction_id"`
+	ProductName string  `json:"product_name"`
+	Category    string  `json:"category"`
+	Score       float64 `json:"score"`
+}
+
+type RecommendationOutputDTO struct {
+	UserId      string                        `json:"user_id"`
+	Auctions    []RecommendedAuctionOutputDTO `json:"auctions"`
+	GeneratedAt time.Time                     `json:"generated_at" time_format:"2006-01-02 15:04:05"`
+}
+
+type RecommendationUseCase struct {
+	recommendationRepository recommendation_entity.RecommendationRepositoryInterface
+	auctionRepository        auction_entity.AuctionRepositoryInterface
+	bidRepository            bid_entity.BidEntityRepository
+
+	refreshInterval time.Duration
+}
+
+func NewRecommendationUseCase(
+	recommendationRepository recommendation_entity.RecommendationRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	bidRepository bid_entity.BidEntityRepository) RecommendationUseCaseInterface {
+	recommendationUseCase := &RecommendationUseCase{
+		recommendationRepository: recommendationRepository,
+		auctionRepository:        auctionRepository,
+		bidRepository:            bidRepository,
+		refreshInterval:          getRecommendationInterval(),
+	}
+
+	recommendationUseCase.triggerRefreshRoutine()
+
+	return recommendationUseCase
+}
+
+type RecommendationUseCaseInterface interface {
+	GenerateRecommendations(ctx context.Context) *internal_error.InternalError
+
+	FindRecommendationsByUserId(
+		ctx context.Context, userId string) (*RecommendationOutputDTO, *internal_error.InternalError)
+
+	FindSimilarAuctions(
+		ctx context.Context, auctionId string) ([]RecommendedAuctionOutputDTO, *internal_error.InternalError)
+}
+
+// Periodically rebuild the stored recommendations from the bidding history
+func (ru *RecommendationUseCase) triggerRefreshRoutine() {
+	go func() {
+		ticker := time.NewTicker(ru.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), defaultRefreshTimeout)
+			if err := ru.GenerateRecommendations(ctx); err != nil {
+				logger.Error("Error trying to generate recommendations", err)
+			}
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
+
+func (ru *RecommendationUseCase) GenerateRecommendations(ctx context.Context) *internal_error.InternalError {
+	affinities, err := ru.recommendationRepository.FindCategoryAffinities(ctx)
+	if err != nil {
+		return err
+	}
+
+	affinitiesByUser := make(map[string][]recommendation_entity.CategoryAffinity)
+	for _, affinity := range affinities {
+		affinitiesByUser[affinity.UserId] = append(affinitiesByUser[affinity.UserId], affinity)
+	}
+
+	candidatesByCategory := make(map[string][]auction_entity.Auction)
+	for userId, userAffinities := range affinitiesByUser {
+		sort.Slice(userAffinities, func(i, j int) bool {
+			return userAffinities[i].Score > userAffinities[j].Score
+		})
+
+		alreadyBid := make(map[string]bool)
+		for _, affinity := range userAffinities {
+			for _, auctionId := range affinity.AuctionIds {
+				alreadyBid[auctionId] = true
+			}
+		}
+
+		if len(userAffinities) > maxCategoriesPerUser {
+			userAffinities = userAffinities[:maxCategoriesPerUser]
+		}
+
+		var totalScore float64
+		for _, affinity := range userAffinities {
+			totalScore += affinity.Score
+		}
+
+		var recommended []recommendation_entity.RecommendedAuction
+		for _, affinity := range userAffinities {
+			candidates, ok := candidatesByCategory[affinity.Category]
+			if !ok {
+				active := auction_entity.Active
+				candidates, _, err = ru.auctionRepository.FindAuctions(ctx, auction_entity.AuctionQuery{
+					Status:   &active,
+					Category: affinity.Category,
+				})
+				if err != nil {
+					return err
+				}
+				candidatesByCategory[affinity.Category] = candidates
+			}
+
+			for _, auction := range candidates {
+				if auction.Status != auction_entity.Active || alreadyBid[auction.Id] {
+					continue
+				}
+
+				recommended = append(recommended, recommendation_entity.RecommendedAuction{
+					AuctionId:   auction.Id,
+					ProductName: auction.ProductName,
+					Category:    auction.Category,
+					Score:       affinity.Score / totalScore,
+				})
+			}
+		}
+
+		sort.SliceStable(recommended, func(i, j int) bool {
+			return recommended[i].Score > recommended[j].Score
+		})
+		if len(recommended) > maxAuctionsPerUser {
+			recommended = recommended[:maxAuctionsPerUser]
+		}
+
+		if err := ru.recommendationRepository.UpsertRecommendation(ctx, &recommendation_entity.Recommendation{
+			UserId:      userId,
+			Auctions:    recommended,
+			GeneratedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getRecommendationInterval() time.Duration {
+	recommendationInterval := os.Getenv("RECOMMENDATION_INTERVAL")
+	duration, err := time.ParseDuration(recommendationInterval)
+	if err != nil {
+		return time.Hour
+	}
+
+	return duration
+}
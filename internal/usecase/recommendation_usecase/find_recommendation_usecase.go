@@ -0,0 +1,30 @@
+package recommendation_usecase
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+func (ru *RecommendationUseCase) FindRecommendationsByUserId(
+	ctx context.Context, userId string) (*RecommendationOutputDTO, *internal_error.InternalError) {
+	recommendation, err := ru.recommendationRepository.FindRecommendationByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	auctions := []RecommendedAuctionOutputDTO{}
+	for _, auction := range recommendation.Auctions {
+		auctions = append(auctions, RecommendedAuctionOutputDTO{
+			AuctionId:   auction.AuctionId,
+			ProductName: auction.ProductName,
+			Category:    auction.Category,
+			Score:       auction.Score,
+		})
+	}
+
+	return &RecommendationOutputDTO{
+		UserId:      recommendation.UserId,
+		Auctions:    auctions,
+		GeneratedAt: recommendation.GeneratedAt,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package recommendation_usecase
+
+import (
+	"auction_go/internal/entity/recommendation_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// FindSimilarAuctions suggests active auctions like the given one, so its
+// bidders have somewhere to go once it ends. Prices are compared with what
+// the auction sold for, or its current price while it runs
+func (ru *RecommendationUseCase) FindSimilarAuctions(
+	ctx context.Context, auctionId string) ([]RecommendedAuctionOutputDTO, *internal_error.InternalError) {
+	auctionEntity, err := ru.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	referencePrice := auctionEntity.WinningAmount
+	if referencePrice <= 0 {
+		currentPrice, err := ru.bidRepository.GetCurrentPrice(ctx, auctionId)
+		if err != nil {
+			return nil, err
+		}
+		referencePrice = currentPrice.Amount
+	}
+
+	similar, err := ru.recommendationRepository.FindSimilarAuctions(ctx, recommendation_entity.SimilarAuctionQuery{
+		AuctionId:      auctionEntity.Id,
+		Category:       auctionEntity.Category,
+		Keywords:       recommendation_entity.Keywords(auctionEntity.ProductName),
+		ReferencePrice: referencePrice,
+		Limit:          maxSimilarAuctions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auctions := make([]RecommendedAuctionOutputDTO, 0, len(similar))
+	for _, auction := range similar {
+		auctions = append(auctions, RecommendedAuctionOutputDTO{
+			AuctionId:   auction.AuctionId,
+			ProductName: auction.ProductName,
+			Category:    auction.Category,
+			Score:       auction.Score,
+		})
+	}
+
+	return auctions, nil
+}
@@ -0,0 +1,154 @@
+package return_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"time"
+)
+
+type ReturnRequestInputDTO struct {
+	CheckoutId string `json:"checkout_id" binding:"required,uuid"`
+	// UserId is the authenticated buyer, it isn't read from the body
+	UserId      string   `json:"-"`
+	Reason      string   `json:"reason" binding:"required,oneof=not_as_described damaged wrong_item other"`
+	Description string   `json:"description" binding:"max=1000"`
+	PhotoUrls   []string `json:"photo_urls" binding:"max=5,dive,url"`
+}
+
+type ReturnDecisionInputDTO struct {
+	// SellerId is the authenticated user, it isn't read from the body
+	SellerId string `json:"-"`
+	Note     string `json:"note" binding:"max=1000"`
+}
+
+type ReturnRequestOutputDTO struct {
+	Id          string       `json:"id"`
+	CheckoutId  string       `json:"checkout_id"`
+	BuyerUserId string       `json:"buyer_user_id"`
+	Reason      string       `json:"reason"`
+	Description string       `json:"description,omitempty"`
+	PhotoUrls   []string     `json:"photo_urls"`
+	Status      ReturnStatus `json:"status"`
+	SellerNote  string       `json:"seller_note,omitempty"`
+	Timestamp   time.Time    `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	ResolvedAt  *time.Time   `json:"resolved_at,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+type ReturnStatus int64
+
+type ReturnUseCase struct {
+	returnRepository   return_entity.ReturnRepositoryInterface
+	checkoutRepository checkout_entity.CheckoutRepositoryInterface
+	auctionRepository  auction_entity.AuctionRepositoryInterface
+
+	returnWindow time.Duration
+}
+
+func NewReturnUseCase(
+	returnRepository return_entity.ReturnRepositoryInterface,
+	checkoutRepository checkout_entity.CheckoutRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface) ReturnUseCaseInterface {
+	return &ReturnUseCase{
+		returnRepository:   returnRepository,
+		checkoutRepository: checkoutRepository,
+		auctionRepository:  auctionRepository,
+		returnWindow:       getReturnWindow(),
+	}
+}
+
+type ReturnUseCaseInterface interface {
+	CreateReturnRequest(
+		ctx context.Context,
+		returnRequestInput ReturnRequestInputDTO) (*ReturnRequestOutputDTO, *internal_error.InternalError)
+
+	FindReturnRequestById(
+		ctx context.Context, userId, id string) (*ReturnRequestOutputDTO, *internal_error.InternalError)
+
+	FindReturnRequestByCheckoutId(
+		ctx context.Context, userId, checkoutId string) (*ReturnRequestOutputDTO, *internal_error.InternalError)
+
+	AcceptReturnRequest(
+		ctx context.Context,
+		id string,
+		decisionInput ReturnDecisionInputDTO) (*ReturnRequestOutputDTO, *internal_error.InternalError)
+
+	DeclineReturnRequest(
+		ctx context.Context,
+		id string,
+		decisionInput ReturnDecisionInputDTO) (*ReturnRequestOutputDTO, *internal_error.InternalError)
+}
+
+// CreateReturnRequest is only open to the buyer of a delivered checkout and
+// only until the return window counted from the delivery has elapsed
+func (ru *ReturnUseCase) CreateReturnRequest(
+	ctx context.Context,
+	returnRequestInput ReturnRequestInputDTO) (*ReturnRequestOutputDTO, *internal_error.InternalError) {
+	checkout, err := ru.checkoutRepository.FindCheckoutById(ctx, returnRequestInput.CheckoutId)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkout.BuyerUserId != returnRequestInput.UserId {
+		return nil, internal_error.NewForbiddenError("Only the buyer can request a return")
+	} else if checkout.Status != checkout_entity.Delivered {
+		return nil, internal_error.NewBadRequestError("Returns can only be requested for delivered checkouts")
+	} else if time.Now().After(checkout.DeliveredAt.Add(ru.returnWindow)) {
+		return nil, internal_error.NewBadRequestError("The return window for this checkout has closed")
+	}
+
+	returnRequest, err := return_entity.CreateReturnRequest(
+		checkout.Id,
+		returnRequestInput.UserId,
+		returnRequestInput.Reason,
+		returnRequestInput.Description,
+		returnRequestInput.PhotoUrls)
+	if err != nil {
+		return nil, err
+	}
+
+	storedReturnRequest, err := ru.returnRepository.CreateReturnRequest(ctx, returnRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestOutputDTO(storedReturnRequest), nil
+}
+
+func toReturnRequestOutputDTO(returnRequest *return_entity.ReturnRequest) *ReturnRequestOutputDTO {
+	returnRequestOutput := &ReturnRequestOutputDTO{
+		Id:          returnRequest.Id,
+		CheckoutId:  returnRequest.CheckoutId,
+		BuyerUserId: returnRequest.BuyerUserId,
+		Reason:      returnRequest.Reason,
+		Description: returnRequest.Description,
+		PhotoUrls:   returnRequest.PhotoUrls,
+		Status:      ReturnStatus(returnRequest.Status),
+		SellerNote:  returnRequest.SellerNote,
+		Timestamp:   returnRequest.Timestamp,
+	}
+
+	if returnRequestOutput.PhotoUrls == nil {
+		returnRequestOutput.PhotoUrls = []string{}
+	}
+
+	if !returnRequest.ResolvedAt.IsZero() {
+		resolvedAt := returnRequest.ResolvedAt
+		returnRequestOutput.ResolvedAt = &resolvedAt
+	}
+
+	return returnRequestOutput
+}
+
+func getReturnWindow() time.Duration {
+	returnWindow := os.Getenv("RETURN_WINDOW")
+	duration, err := time.ParseDuration(returnWindow)
+	if err != nil || duration <= 0 {
+		return 7 * 24 * time.Hour
+	}
+
+	return duration
+}
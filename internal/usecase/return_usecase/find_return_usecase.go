@@ -0,0 +1,71 @@
+package return_usecase
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// FindReturnRequestById is open to the buyer and to the seller, who
+// resolves it
+func (ru *ReturnUseCase) FindReturnRequestById(
+	ctx context.Context, userId, id string) (*ReturnRequestOutputDTO, *internal_error.InternalError) {
+	returnRequest, err := ru.returnRepository.FindReturnRequestById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ru.checkParty(ctx, returnRequest, userId); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestOutputDTO(returnRequest), nil
+}
+
+func (ru *ReturnUseCase) FindReturnRequestByCheckoutId(
+	ctx context.Context, userId, checkoutId string) (*ReturnRequestOutputDTO, *internal_error.InternalError) {
+	returnRequest, err := ru.returnRepository.FindReturnRequestByCheckoutId(ctx, checkoutId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ru.checkParty(ctx, returnRequest, userId); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestOutputDTO(returnRequest), nil
+}
+
+// checkParty turns away everyone but the buyer and the seller of the
+// checkout's auction
+func (ru *ReturnUseCase) checkParty(
+	ctx context.Context, returnRequest *return_entity.ReturnRequest, userId string) *internal_error.InternalError {
+	if returnRequest.BuyerUserId == userId {
+		return nil
+	}
+
+	checkout, err := ru.checkoutRepository.FindCheckoutById(ctx, returnRequest.CheckoutId)
+	if err != nil {
+		return err
+	}
+
+	isSeller, err := ru.isSeller(ctx, checkout, userId)
+	if err != nil {
+		return err
+	} else if !isSeller {
+		return internal_error.NewForbiddenError("Only the buyer and the seller can see this return request")
+	}
+
+	return nil
+}
+
+func (ru *ReturnUseCase) isSeller(
+	ctx context.Context, checkout *checkout_entity.Checkout, userId string) (bool, *internal_error.InternalError) {
+	auction, err := ru.auctionRepository.FindAuctionById(ctx, checkout.AuctionId)
+	if err != nil {
+		return false, err
+	}
+
+	return auction.SellerId != "" && auction.SellerId == userId, nil
+}
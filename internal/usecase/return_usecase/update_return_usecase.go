@@ -0,0 +1,83 @@
+package return_usecase
+
+import (
+	"auction_go/internal/entity/checkout_entity"
+	"auction_go/internal/entity/return_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// AcceptReturnRequest resolves the request and moves the checkout to
+// Returned together, owing the buyer a refund of the checkout total. The
+// refund is settled once the payment provider reports it paid back
+func (ru *ReturnUseCase) AcceptReturnRequest(
+	ctx context.Context,
+	id string,
+	decisionInput ReturnDecisionInputDTO) (*ReturnRequestOutputDTO, *internal_error.InternalError) {
+	returnRequest, checkout, err := ru.findForSeller(ctx, id, decisionInput.SellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedStatus := returnRequest.Status
+	if err := returnRequest.Accept(decisionInput.Note); err != nil {
+		return nil, err
+	}
+
+	if err := checkout.MarkReturned(returnRequest.Id); err != nil {
+		return nil, err
+	}
+
+	if err := ru.returnRepository.AcceptReturnRequest(
+		ctx, returnRequest, expectedStatus, checkout); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestOutputDTO(returnRequest), nil
+}
+
+func (ru *ReturnUseCase) DeclineReturnRequest(
+	ctx context.Context,
+	id string,
+	decisionInput ReturnDecisionInputDTO) (*ReturnRequestOutputDTO, *internal_error.InternalError) {
+	returnRequest, _, err := ru.findForSeller(ctx, id, decisionInput.SellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedStatus := returnRequest.Status
+	if err := returnRequest.Decline(decisionInput.Note); err != nil {
+		return nil, err
+	}
+
+	if err := ru.returnRepository.UpdateReturnRequest(ctx, returnRequest, expectedStatus); err != nil {
+		return nil, err
+	}
+
+	return toReturnRequestOutputDTO(returnRequest), nil
+}
+
+// findForSeller finds the request and its checkout, resolving it is left to
+// the seller of the checkout's auction
+func (ru *ReturnUseCase) findForSeller(
+	ctx context.Context,
+	id, sellerId string) (*return_entity.ReturnRequest, *checkout_entity.Checkout, *internal_error.InternalError) {
+	returnRequest, err := ru.returnRepository.FindReturnRequestById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkout, err := ru.checkoutRepository.FindCheckoutById(ctx, returnRequest.CheckoutId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isSeller, err := ru.isSeller(ctx, checkout, sellerId)
+	if err != nil {
+		return nil, nil, err
+	} else if !isSeller {
+		return nil, nil, internal_error.NewForbiddenError("Only the seller can resolve a return request")
+	}
+
+	return returnRequest, checkout, nil
+}
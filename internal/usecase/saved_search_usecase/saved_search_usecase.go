@@ -0,0 +1,142 @@
+package saved_search_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/category_entity"
+	"auction_go/internal/entity/saved_search_entity"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"auction_go/internal/usecase/search_usecase"
+	"context"
+	"fmt"
+	"time"
+)
+
+type SavedSearchInputDTO struct {
+	UserId    string                           `json:"-"`
+	Name      string                           `json:"name" binding:"required,max=100"`
+	Query     string                           `json:"query" binding:"max=200"`
+	Category  string                           `json:"category"`
+	Condition auction_usecase.ProductCondition `json:"condition" binding:"omitempty,oneof=1 2 3"`
+}
+
+type SavedSearchOutputDTO struct {
+	Id        string                           `json:"id"`
+	Name      string                           `json:"name"`
+	Query     string                           `json:"query,omitempty"`
+	Category  string                           `json:"category,omitempty"`
+	Condition auction_usecase.ProductCondition `json:"condition,omitempty"`
+	Timestamp time.Time                        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type SavedSearchUseCase struct {
+	savedSearchRepository saved_search_entity.SavedSearchRepositoryInterface
+	categoryRepository    category_entity.CategoryRepositoryInterface
+	searchUseCase         search_usecase.SearchUseCaseInterface
+}
+
+func NewSavedSearchUseCase(
+	savedSearchRepository saved_search_entity.SavedSearchRepositoryInterface,
+	categoryRepository category_entity.CategoryRepositoryInterface,
+	searchUseCase search_usecase.SearchUseCaseInterface) SavedSearchUseCaseInterface {
+	return &SavedSearchUseCase{
+		savedSearchRepository: savedSearchRepository,
+		categoryRepository:    categoryRepository,
+		searchUseCase:         searchUseCase,
+	}
+}
+
+type SavedSearchUseCaseInterface interface {
+	CreateSavedSearch(
+		ctx context.Context,
+		savedSearchInput SavedSearchInputDTO) (*SavedSearchOutputDTO, *internal_error.InternalError)
+
+	DeleteSavedSearch(
+		ctx context.Context, userId, savedSearchId string) *internal_error.InternalError
+
+	ListSavedSearches(
+		ctx context.Context, userId string) ([]SavedSearchOutputDTO, *internal_error.InternalError)
+}
+
+// CreateSavedSearch expands the query through the stop words and synonyms
+// once, new auctions are matched against the variants it had when saved. Up
+// to MaxSavedSearches are kept per user
+func (su *SavedSearchUseCase) CreateSavedSearch(
+	ctx context.Context,
+	savedSearchInput SavedSearchInputDTO) (*SavedSearchOutputDTO, *internal_error.InternalError) {
+	queries, err := su.searchUseCase.ExpandQuery(ctx, savedSearchInput.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryName := ""
+	if savedSearchInput.Category != "" {
+		category, err := su.categoryRepository.FindCategoryByName(ctx, savedSearchInput.Category)
+		if err != nil {
+			if err.Err == "not_found" {
+				return nil, internal_error.NewValidationError("Invalid saved search",
+					internal_error.Cause{Field: "category", Message: "Must be one of the listed categories"})
+			}
+			return nil, err
+		}
+		categoryName = category.Name
+	}
+
+	savedSearch, err := saved_search_entity.CreateSavedSearch(
+		savedSearchInput.UserId,
+		savedSearchInput.Name,
+		savedSearchInput.Query,
+		queries,
+		categoryName,
+		auction_entity.ProductCondition(savedSearchInput.Condition))
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := su.savedSearchRepository.CountSavedSearchesByUserId(ctx, savedSearch.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if count >= saved_search_entity.MaxSavedSearches {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("A user may save at most %d searches", saved_search_entity.MaxSavedSearches))
+	}
+
+	if err := su.savedSearchRepository.CreateSavedSearch(ctx, savedSearch); err != nil {
+		return nil, err
+	}
+
+	output := toSavedSearchOutputDTO(*savedSearch)
+	return &output, nil
+}
+
+func (su *SavedSearchUseCase) DeleteSavedSearch(
+	ctx context.Context, userId, savedSearchId string) *internal_error.InternalError {
+	return su.savedSearchRepository.DeleteSavedSearch(ctx, userId, savedSearchId)
+}
+
+func (su *SavedSearchUseCase) ListSavedSearches(
+	ctx context.Context, userId string) ([]SavedSearchOutputDTO, *internal_error.InternalError) {
+	savedSearches, err := su.savedSearchRepository.FindSavedSearchesByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]SavedSearchOutputDTO, 0, len(savedSearches))
+	for _, savedSearch := range savedSearches {
+		output = append(output, toSavedSearchOutputDTO(savedSearch))
+	}
+
+	return output, nil
+}
+
+func toSavedSearchOutputDTO(savedSearch saved_search_entity.SavedSearch) SavedSearchOutputDTO {
+	return SavedSearchOutputDTO{
+		Id:        savedSearch.Id,
+		Name:      savedSearch.Name,
+		Query:     savedSearch.Query,
+		Category:  savedSearch.Category,
+		Condition: auction_usecase.ProductCondition(savedSearch.Condition),
+		Timestamp: savedSearch.Timestamp,
+	}
+}
@@ -0,0 +1,88 @@
+package search_usecase
+
+import (
+	"auction_go/internal/entity/search_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"os"
+	"strings"
+	"time"
+)
+
+type SynonymGroupInputDTO struct {
+	Terms []string `json:"terms" binding:"required,min=2,dive,required"`
+}
+
+type SynonymGroupOutputDTO struct {
+	Id        string    `json:"id"`
+	Terms     []string  `json:"terms"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type SearchUseCase struct {
+	searchRepository search_entity.SearchRepositoryInterface
+
+	stopWords map[string]bool
+}
+
+func NewSearchUseCase(searchRepository search_entity.SearchRepositoryInterface) SearchUseCaseInterface {
+	return &SearchUseCase{
+		searchRepository: searchRepository,
+		stopWords:        getStopWords(),
+	}
+}
+
+type SearchUseCaseInterface interface {
+	CreateSynonymGroup(
+		ctx context.Context,
+		synonymGroupInput SynonymGroupInputDTO) (*SynonymGroupOutputDTO, *internal_error.InternalError)
+
+	FindSynonymGroups(
+		ctx context.Context) ([]SynonymGroupOutputDTO, *internal_error.InternalError)
+
+	DeleteSynonymGroup(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	ExpandQuery(
+		ctx context.Context, query string) ([]string, *internal_error.InternalError)
+}
+
+func (su *SearchUseCase) CreateSynonymGroup(
+	ctx context.Context,
+	synonymGroupInput SynonymGroupInputDTO) (*SynonymGroupOutputDTO, *internal_error.InternalError) {
+	synonymGroup, err := search_entity.CreateSynonymGroup(synonymGroupInput.Terms)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := su.searchRepository.CreateSynonymGroup(ctx, synonymGroup); err != nil {
+		return nil, err
+	}
+
+	return &SynonymGroupOutputDTO{
+		Id:        synonymGroup.Id,
+		Terms:     synonymGroup.Terms,
+		Timestamp: synonymGroup.Timestamp,
+	}, nil
+}
+
+func (su *SearchUseCase) DeleteSynonymGroup(
+	ctx context.Context, id string) *internal_error.InternalError {
+	return su.searchRepository.DeleteSynonymGroup(ctx, id)
+}
+
+func getStopWords() map[string]bool {
+	stopWordList, ok := os.LookupEnv("SEARCH_STOP_WORDS")
+	if !ok {
+		stopWordList = "a,an,the,and,or,of,for,with,de,da,do,das,dos,e,o,os,as,para,com"
+	}
+
+	stopWords := make(map[string]bool)
+	for _, word := range strings.Split(stopWordList, ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			stopWords[word] = true
+		}
+	}
+
+	return stopWords
+}
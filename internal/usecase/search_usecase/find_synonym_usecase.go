@@ -0,0 +1,38 @@
+package search_usecase
+
+import (
+	"auction_go/internal/entity/search_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+func (su *SearchUseCase) FindSynonymGroups(
+	ctx context.Context) ([]SynonymGroupOutputDTO, *internal_error.InternalError) {
+	synonymGroups, err := su.searchRepository.FindSynonymGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	synonymGroupOutputs := []SynonymGroupOutputDTO{}
+	for _, synonymGroup := range synonymGroups {
+		synonymGroupOutputs = append(synonymGroupOutputs, SynonymGroupOutputDTO{
+			Id:        synonymGroup.Id,
+			Terms:     synonymGroup.Terms,
+			Timestamp: synonymGroup.Timestamp,
+		})
+	}
+
+	return synonymGroupOutputs, nil
+}
+
+// ExpandQuery applies the stop words and the current synonym dictionary to a
+// search query, so a new dictionary entry takes effect without any reindex
+func (su *SearchUseCase) ExpandQuery(
+	ctx context.Context, query string) ([]string, *internal_error.InternalError) {
+	synonymGroups, err := su.searchRepository.FindSynonymGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return search_entity.ExpandQuery(query, synonymGroups, su.stopWords), nil
+}
@@ -0,0 +1,125 @@
+package seller_usecase
+
+import (
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"context"
+	"strings"
+)
+
+type SellerOutputDTO struct {
+	Id             string                               `json:"id"`
+	Name           string                               `json:"name"`
+	Slug           string                               `json:"slug,omitempty"`
+	Reputation     auction_usecase.ReputationOutputDTO  `json:"reputation"`
+	ActiveAuctions auction_usecase.AuctionPageOutputDTO `json:"active_auctions"`
+}
+
+// SellerSummaryOutputDTO is a seller without their auctions
+type SellerSummaryOutputDTO struct {
+	Id         string                              `json:"id"`
+	Name       string                              `json:"name"`
+	Slug       string                              `json:"slug,omitempty"`
+	Reputation auction_usecase.ReputationOutputDTO `json:"reputation"`
+}
+
+type SellerUseCase struct {
+	userRepository user_entity.UserRepositoryInterface
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+}
+
+func NewSellerUseCase(
+	userRepository user_entity.UserRepositoryInterface,
+	auctionUseCase auction_usecase.AuctionUseCaseInterface) SellerUseCaseInterface {
+	return &SellerUseCase{
+		userRepository: userRepository,
+		auctionUseCase: auctionUseCase,
+	}
+}
+
+type SellerUseCaseInterface interface {
+	FindSellerById(
+		ctx context.Context,
+		id string,
+		page, pageSize int64) (*SellerOutputDTO, *internal_error.InternalError)
+
+	FindSellerBySlug(
+		ctx context.Context,
+		slug string,
+		page, pageSize int64) (*SellerOutputDTO, *internal_error.InternalError)
+
+	FindSellersByIds(
+		ctx context.Context, ids []string) (map[string]SellerSummaryOutputDTO, *internal_error.InternalError)
+}
+
+func (su *SellerUseCase) FindSellerById(
+	ctx context.Context,
+	id string,
+	page, pageSize int64) (*SellerOutputDTO, *internal_error.InternalError) {
+	user, err := su.userRepository.FindUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return su.toSellerOutputDTO(ctx, user, page, pageSize)
+}
+
+// Slugs are stored lowercase, so the lookup is case-insensitive
+func (su *SellerUseCase) FindSellerBySlug(
+	ctx context.Context,
+	slug string,
+	page, pageSize int64) (*SellerOutputDTO, *internal_error.InternalError) {
+	user, err := su.userRepository.FindUserBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		return nil, err
+	}
+
+	return su.toSellerOutputDTO(ctx, user, page, pageSize)
+}
+
+// FindSellersByIds reads many sellers in a single query, the ids no user
+// has are left out of the map
+func (su *SellerUseCase) FindSellersByIds(
+	ctx context.Context, ids []string) (map[string]SellerSummaryOutputDTO, *internal_error.InternalError) {
+	users, err := su.userRepository.FindUsersByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	sellers := make(map[string]SellerSummaryOutputDTO, len(users))
+	for _, user := range users {
+		sellers[user.Id] = SellerSummaryOutputDTO{
+			Id:   user.Id,
+			Name: user.Name,
+			Slug: user.Slug,
+			Reputation: auction_usecase.ReputationOutputDTO{
+				Score: user.Reputation.Score,
+				Count: user.Reputation.Count,
+			},
+		}
+	}
+
+	return sellers, nil
+}
+
+func (su *SellerUseCase) toSellerOutputDTO(
+	ctx context.Context,
+	user *user_entity.User,
+	page, pageSize int64) (*SellerOutputDTO, *internal_error.InternalError) {
+	activeAuctions, err := su.auctionUseCase.FindActiveAuctionsBySellerId(ctx, user.Id, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SellerOutputDTO{
+		Id:   user.Id,
+		Name: user.Name,
+		Slug: user.Slug,
+		Reputation: auction_usecase.ReputationOutputDTO{
+			Score: user.Reputation.Score,
+			Count: user.Reputation.Count,
+		},
+		ActiveAuctions: *activeAuctions,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package stats_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/stats_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+type PricePointOutputDTO struct {
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Amount    float64   `json:"amount"`
+	BidCount  int64     `json:"bid_count"`
+}
+
+// AuctionStatsOutputDTO sums up the bidding on an auction. Price history
+// has the highest bid of each bucket someone bid in, BucketSeconds wide
+type AuctionStatsOutputDTO struct {
+	AuctionId     string                `json:"auction_id"`
+	BidCount      int64                 `json:"bid_count"`
+	UniqueBidders int64                 `json:"unique_bidders"`
+	WatchCount    int64                 `json:"watch_count"`
+	BucketSeconds int64                 `json:"bucket_seconds"`
+	PriceHistory  []PricePointOutputDTO `json:"price_history"`
+}
+
+type StatsUseCase struct {
+	statsRepository   stats_entity.StatsRepositoryInterface
+	auctionRepository auction_entity.AuctionRepositoryInterface
+}
+
+func NewStatsUseCase(
+	statsRepository stats_entity.StatsRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface) StatsUseCaseInterface {
+	return &StatsUseCase{
+		statsRepository:   statsRepository,
+		auctionRepository: auctionRepository,
+	}
+}
+
+type StatsUseCaseInterface interface {
+	FindAuctionStats(
+		ctx context.Context, auctionId string) (*AuctionStatsOutputDTO, *internal_error.InternalError)
+
+	FindStatsByAuctionIds(
+		ctx context.Context, auctionIds []string) (map[string]AuctionStatsOutputDTO, *internal_error.InternalError)
+}
+
+// FindAuctionStats sizes the price history buckets so the auction's whole
+// run fits in stats_entity.MaxPricePoints of them
+func (su *StatsUseCase) FindAuctionStats(
+	ctx context.Context, auctionId string) (*AuctionStatsOutputDTO, *internal_error.InternalError) {
+	auctionEntity, err := su.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := stats_entity.BucketFor(auctionEntity.EndTime.Sub(auctionEntity.StartTime))
+	stats, err := su.statsRepository.FindAuctionStats(ctx, auctionId, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	priceHistory := make([]PricePointOutputDTO, 0, len(stats.PriceHistory))
+	for _, point := range stats.PriceHistory {
+		priceHistory = append(priceHistory, PricePointOutputDTO{
+			Timestamp: point.Timestamp,
+			Amount:    point.Amount,
+			BidCount:  point.BidCount,
+		})
+	}
+
+	return &AuctionStatsOutputDTO{
+		AuctionId:     stats.AuctionId,
+		BidCount:      stats.BidCount,
+		UniqueBidders: stats.UniqueBidders,
+		WatchCount:    stats.WatchCount,
+		BucketSeconds: int64(bucket.Seconds()),
+		PriceHistory:  priceHistory,
+	}, nil
+}
+
+// FindStatsByAuctionIds counts the bids, bidders and watchers of many
+// auctions at once, the stats have no price history. Every auction asked
+// for is in the map, the ones nobody bid on or watched with zero counts
+func (su *StatsUseCase) FindStatsByAuctionIds(
+	ctx context.Context, auctionIds []string) (map[string]AuctionStatsOutputDTO, *internal_error.InternalError) {
+	statsByAuction, err := su.statsRepository.FindStatsByAuctionIds(ctx, auctionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	statsOutputs := make(map[string]AuctionStatsOutputDTO, len(auctionIds))
+	for _, auctionId := range auctionIds {
+		stats := statsByAuction[auctionId]
+		statsOutputs[auctionId] = AuctionStatsOutputDTO{
+			AuctionId:     auctionId,
+			BidCount:      stats.BidCount,
+			UniqueBidders: stats.UniqueBidders,
+			WatchCount:    stats.WatchCount,
+			PriceHistory:  []PricePointOutputDTO{},
+		}
+	}
+
+	return statsOutputs, nil
+}
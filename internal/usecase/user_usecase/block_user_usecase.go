@@ -0,0 +1,34 @@
+package user_usecase
+
+import (
+	"auction_go/internal/internal_error"
+	"context"
+	"time"
+)
+
+// BlockUserInputDTO suspends the user until Until, or blocks them for good
+// when it is left out
+type BlockUserInputDTO struct {
+	Reason string     `json:"reason" binding:"required,max=300"`
+	Until  *time.Time `json:"until"`
+}
+
+// BlockUser keeps the user from bidding and creating auctions, bids and
+// auctions they already have are left alone
+func (u *UserUseCase) BlockUser(
+	ctx context.Context, id string, blockInputDTO BlockUserInputDTO) *internal_error.InternalError {
+	var until time.Time
+	if blockInputDTO.Until != nil {
+		until = *blockInputDTO.Until
+		if !until.After(time.Now()) {
+			return internal_error.NewValidationError("Invalid block",
+				internal_error.Cause{Field: "until", Message: "Must be in the future"})
+		}
+	}
+
+	return u.UserRepository.BlockUser(ctx, id, blockInputDTO.Reason, until)
+}
+
+func (u *UserUseCase) UnblockUser(ctx context.Context, id string) *internal_error.InternalError {
+	return u.UserRepository.UnblockUser(ctx, id)
+}
@@ -0,0 +1,152 @@
+package user_usecase
+
+import (
+	"auction_go/configuration/experiment"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type UserInputDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// UpdateUserInputDTO changes the fields that are set, the others are kept
+type UpdateUserInputDTO struct {
+	Name     string `json:"name" binding:"omitempty,min=2,max=100"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Password string `json:"password" binding:"omitempty,min=8,max=72"`
+
+	// EmailOptOuts replaces the notification emails the user opted out of,
+	// an empty list opts back into all of them
+	EmailOptOuts *[]string `json:"email_opt_outs"`
+}
+
+func (u *UserUseCase) CreateUser(
+	ctx context.Context, userInputDTO UserInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := user_entity.CreateUser(userInputDTO.Name, userInputDTO.Email, userInputDTO.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.checkEmailAvailable(ctx, userEntity.Id, userEntity.Email); err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.CreateUser(ctx, userEntity); err != nil {
+		return nil, err
+	}
+	u.sendEmailVerification(ctx, userEntity)
+
+	return ownUserOutput(userEntity), nil
+}
+
+func (u *UserUseCase) UpdateUser(
+	ctx context.Context,
+	id string,
+	updateUserInputDTO UpdateUserInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := u.findActiveUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousEmail := userEntity.Email
+	if err := userEntity.Update(updateUserInputDTO.Name, updateUserInputDTO.Email, updateUserInputDTO.Password); err != nil {
+		return nil, err
+	}
+	if updateUserInputDTO.EmailOptOuts != nil {
+		optOuts := make([]user_entity.EmailNotification, 0, len(*updateUserInputDTO.EmailOptOuts))
+		for _, optOut := range *updateUserInputDTO.EmailOptOuts {
+			optOuts = append(optOuts, user_entity.EmailNotification(optOut))
+		}
+		if err := userEntity.SetEmailOptOuts(optOuts); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.checkEmailAvailable(ctx, userEntity.Id, userEntity.Email); err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.UpdateUser(ctx, userEntity); err != nil {
+		return nil, err
+	}
+	if userEntity.Email != previousEmail {
+		u.sendEmailVerification(ctx, userEntity)
+	}
+
+	return ownUserOutput(userEntity), nil
+}
+
+// DeleteAccount anonymizes the user rather than removing them, their bids
+// and auctions stay on record. The account itself is purged once nothing
+// points to it anymore
+func (u *UserUseCase) DeleteAccount(ctx context.Context, id string) *internal_error.InternalError {
+	userEntity, err := u.findActiveUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	userEntity.Anonymize(time.Now())
+	return u.UserRepository.DeleteAccount(ctx, userEntity)
+}
+
+// findActiveUser reports deleted accounts as not found
+func (u *UserUseCase) findActiveUser(
+	ctx context.Context, id string) (*user_entity.User, *internal_error.InternalError) {
+	userEntity, err := u.UserRepository.FindUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if userEntity.Deleted() {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", id))
+	}
+
+	return userEntity, nil
+}
+
+// checkEmailAvailable turns down an email another user registered. The
+// repository's unique index still catches two users racing for it
+func (u *UserUseCase) checkEmailAvailable(
+	ctx context.Context, userId, email string) *internal_error.InternalError {
+	owner, err := u.UserRepository.FindUserByEmail(ctx, strings.ToLower(email))
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil
+		}
+		return err
+	}
+
+	if owner.Id != userId {
+		return internal_error.NewValidationError("Invalid user",
+			internal_error.Cause{Field: "email", Message: "Email is already in use"})
+	}
+
+	return nil
+}
+
+func ownUserOutput(userEntity *user_entity.User) *UserOutputDTO {
+	output := &UserOutputDTO{
+		Id:           userEntity.Id,
+		Name:         userEntity.Name,
+		Slug:         userEntity.Slug,
+		Email:        userEntity.Email,
+		Verification: string(userEntity.Level()),
+		Experiments:  experiment.Assignments(userEntity.Id),
+	}
+	if !userEntity.EmailVerifiedAt.IsZero() {
+		output.EmailVerifiedAt = &userEntity.EmailVerifiedAt
+	}
+	for _, optOut := range userEntity.EmailOptOuts {
+		output.EmailOptOuts = append(output.EmailOptOuts, string(optOut))
+	}
+
+	return output
+}
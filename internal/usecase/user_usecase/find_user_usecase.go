@@ -1,30 +1,74 @@
 package user_usecase
 
 import (
+	"auction_go/configuration/experiment"
 	"auction_go/internal/entity/user_entity"
 	"auction_go/internal/internal_error"
 	"context"
+	"time"
 )
 
-func NewUserUseCase(userRepository user_entity.UserRepositoryInterface) UserUseCaseInterface {
+// NewUserUseCase sends the email verification tokens through
+// verificationSender
+func NewUserUseCase(
+	userRepository user_entity.UserRepositoryInterface,
+	verificationSender user_entity.EmailVerificationSender) UserUseCaseInterface {
 	return &UserUseCase{
-		userRepository,
+		UserRepository:     userRepository,
+		verificationSender: verificationSender,
 	}
 }
 
 type UserUseCase struct {
-	UserRepository user_entity.UserRepositoryInterface
+	UserRepository     user_entity.UserRepositoryInterface
+	verificationSender user_entity.EmailVerificationSender
 }
 
 type UserOutputDTO struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id           string            `json:"id"`
+	Name         string            `json:"name"`
+	Slug         string            `json:"slug,omitempty"`
+	Verification string            `json:"verification"`
+	Experiments  map[string]string `json:"experiments,omitempty"`
+
+	// Email is only returned to whoever registers or updates the user
+	Email           string     `json:"email,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	EmailOptOuts    []string   `json:"email_opt_outs,omitempty"`
 }
 
 type UserUseCaseInterface interface {
 	FindUserById(
 		ctx context.Context,
 		id string) (*UserOutputDTO, *internal_error.InternalError)
+
+	CreateUser(
+		ctx context.Context,
+		userInputDTO UserInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
+	UpdateUser(
+		ctx context.Context,
+		id string,
+		updateUserInputDTO UpdateUserInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
+	DeleteAccount(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	Login(
+		ctx context.Context,
+		loginInputDTO LoginInputDTO) (*LoginOutputDTO, *internal_error.InternalError)
+
+	BlockUser(
+		ctx context.Context,
+		id string,
+		blockInputDTO BlockUserInputDTO) *internal_error.InternalError
+
+	UnblockUser(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	VerifyEmail(
+		ctx context.Context,
+		verifyEmailInputDTO VerifyEmailInputDTO) (*UserOutputDTO, *internal_error.InternalError)
 }
 
 func (u *UserUseCase) FindUserById(
@@ -35,7 +79,10 @@ func (u *UserUseCase) FindUserById(
 	}
 
 	return &UserOutputDTO{
-		Id:   userEntity.Id,
-		Name: userEntity.Name,
+		Id:           userEntity.Id,
+		Name:         userEntity.Name,
+		Slug:         userEntity.Slug,
+		Verification: string(userEntity.Level()),
+		Experiments:  experiment.Assignments(userEntity.Id),
 	}, nil
 }
@@ -0,0 +1,42 @@
+package user_usecase
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/configuration/logger"
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+	"time"
+)
+
+type LoginInputDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginOutputDTO struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at" time_format:"2006-01-02 15:04:05"`
+}
+
+// Login issues a token for the user the credentials belong to. Unknown
+// emails and wrong passwords get the same answer, so neither gives away
+// which emails are registered
+func (u *UserUseCase) Login(
+	ctx context.Context, loginInputDTO LoginInputDTO) (*LoginOutputDTO, *internal_error.InternalError) {
+	userEntity, err := u.UserRepository.FindUserByEmail(ctx, strings.ToLower(strings.TrimSpace(loginInputDTO.Email)))
+	if err != nil && err.Err != "not_found" {
+		return nil, err
+	}
+	if userEntity == nil || !userEntity.CheckPassword(loginInputDTO.Password) {
+		return nil, internal_error.NewUnauthorizedError("Invalid email or password")
+	}
+
+	token, expiresAt, errIssue := jwt.Issue(userEntity.Id, time.Now())
+	if errIssue != nil {
+		logger.Error("Error trying to issue token", errIssue)
+		return nil, internal_error.NewInternalServerError("Error trying to issue token")
+	}
+
+	return &LoginOutputDTO{Token: token, ExpiresAt: expiresAt}, nil
+}
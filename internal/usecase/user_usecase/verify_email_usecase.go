@@ -0,0 +1,77 @@
+package user_usecase
+
+import (
+	"auction_go/configuration/jwt"
+	"auction_go/configuration/logger"
+	"auction_go/internal/entity/user_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type VerifyEmailInputDTO struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmail confirms the email the token was issued for. Tokens are signed
+// and name the email, so a token sent to an email the user since changed
+// doesn't verify the new one. Confirming twice is fine
+func (u *UserUseCase) VerifyEmail(
+	ctx context.Context,
+	verifyEmailInputDTO VerifyEmailInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	claims, errParse := jwt.ParsePurpose(verifyEmailInputDTO.Token, jwt.PurposeEmailVerification, time.Now())
+	if errParse != nil {
+		if errors.Is(errParse, jwt.ErrNoSecret) {
+			logger.Error("Error trying to check email verification token", errParse)
+			return nil, internal_error.NewInternalServerError("Error trying to verify email")
+		}
+		return nil, internal_error.NewBadRequestError("Invalid or expired verification token")
+	}
+
+	userEntity, err := u.findActiveUser(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if userEntity.Email != claims.Email {
+		return nil, internal_error.NewBadRequestError("The email was changed since the token was issued")
+	}
+
+	if userEntity.EmailVerifiedAt.IsZero() {
+		userEntity.VerifyEmail(time.Now())
+		if err := u.UserRepository.VerifyEmail(ctx, userEntity); err != nil {
+			return nil, err
+		}
+	}
+
+	return ownUserOutput(userEntity), nil
+}
+
+// sendEmailVerification issues a token for the user's email, valid for
+// EMAIL_VERIFICATION_TTL. The user is stored already, so a token that can't
+// be issued is only logged
+func (u *UserUseCase) sendEmailVerification(ctx context.Context, userEntity *user_entity.User) {
+	token, _, err := jwt.IssueClaims(jwt.Claims{
+		Subject: userEntity.Id,
+		Purpose: jwt.PurposeEmailVerification,
+		Email:   userEntity.Email,
+	}, time.Now(), getEmailVerificationTTL())
+	if err != nil {
+		logger.Error("Error trying to issue email verification token", err, zap.String("userID", userEntity.Id))
+		return
+	}
+
+	u.verificationSender.SendEmailVerification(ctx, *userEntity, token)
+}
+
+func getEmailVerificationTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL"))
+	if err != nil || ttl <= 0 {
+		return 48 * time.Hour
+	}
+
+	return ttl
+}
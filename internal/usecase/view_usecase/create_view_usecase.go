@@ -0,0 +1,85 @@
+package view_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/view_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"strings"
+)
+
+type ViewInputDTO struct {
+	AuctionId string
+	VisitorId string
+	UserAgent string
+}
+
+type DailyViewsOutputDTO struct {
+	Day            string `json:"day"`
+	Views          int64  `json:"views"`
+	UniqueVisitors int64  `json:"unique_visitors"`
+}
+
+type AuctionViewsOutputDTO struct {
+	AuctionId      string                `json:"auction_id"`
+	TotalViews     int64                 `json:"total_views"`
+	UniqueVisitors int64                 `json:"unique_visitors"`
+	Daily          []DailyViewsOutputDTO `json:"daily"`
+}
+
+type ViewUseCase struct {
+	viewRepository    view_entity.ViewRepositoryInterface
+	auctionRepository auction_entity.AuctionRepositoryInterface
+}
+
+func NewViewUseCase(
+	viewRepository view_entity.ViewRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface) ViewUseCaseInterface {
+	return &ViewUseCase{
+		viewRepository:    viewRepository,
+		auctionRepository: auctionRepository,
+	}
+}
+
+type ViewUseCaseInterface interface {
+	CreateView(
+		ctx context.Context,
+		viewInput ViewInputDTO) *internal_error.InternalError
+
+	FindAuctionViews(
+		ctx context.Context, userId, auctionId string) (*AuctionViewsOutputDTO, *internal_error.InternalError)
+}
+
+var botUserAgentMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "headless", "preview", "monitor",
+}
+
+func (vu *ViewUseCase) CreateView(
+	ctx context.Context,
+	viewInput ViewInputDTO) *internal_error.InternalError {
+	if isBotUserAgent(viewInput.UserAgent) {
+		return nil
+	}
+
+	viewEntity, err := view_entity.CreateAuctionView(viewInput.AuctionId, viewInput.VisitorId)
+	if err != nil {
+		return err
+	}
+
+	return vu.viewRepository.CreateView(ctx, viewEntity)
+}
+
+func isBotUserAgent(userAgent string) bool {
+	if strings.TrimSpace(userAgent) == "" {
+		return true
+	}
+
+	userAgent = strings.ToLower(userAgent)
+	for _, marker := range botUserAgentMarkers {
+		if strings.Contains(userAgent, marker) {
+			return true
+		}
+	}
+
+	return false
+}
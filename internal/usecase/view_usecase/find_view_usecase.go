@@ -0,0 +1,43 @@
+package view_usecase
+
+import (
+	"auction_go/internal/entity/view_entity"
+	"auction_go/internal/internal_error"
+	"context"
+)
+
+// FindAuctionViews is for the seller's dashboard, other users are turned
+// away
+func (vu *ViewUseCase) FindAuctionViews(
+	ctx context.Context, userId, auctionId string) (*AuctionViewsOutputDTO, *internal_error.InternalError) {
+	auction, err := vu.auctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerId == "" || auction.SellerId != userId {
+		return nil, internal_error.NewForbiddenError("Only the auction seller can see its views")
+	}
+
+	dailyViews, err := vu.viewRepository.FindDailyViewsByAuctionId(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &AuctionViewsOutputDTO{
+		AuctionId: auctionId,
+		Daily:     []DailyViewsOutputDTO{},
+	}
+	for _, daily := range dailyViews {
+		output.TotalViews += daily.Views
+		output.Daily = append(output.Daily, DailyViewsOutputDTO{
+			Day:            daily.Day,
+			Views:          daily.Views,
+			UniqueVisitors: view_entity.EstimateUniqueVisitors(daily.Registers),
+		})
+	}
+	output.UniqueVisitors = view_entity.EstimateUniqueVisitors(
+		view_entity.MergeRegisters(dailyViews))
+
+	return output, nil
+}
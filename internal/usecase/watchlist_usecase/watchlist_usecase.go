@@ -0,0 +1,110 @@
+package watchlist_usecase
+
+import (
+	"auction_go/internal/entity/auction_entity"
+	"auction_go/internal/entity/watchlist_entity"
+	"auction_go/internal/internal_error"
+	"auction_go/internal/usecase/auction_usecase"
+	"context"
+	"fmt"
+	"time"
+)
+
+type WatchlistInputDTO struct {
+	UserId    string `json:"-"`
+	AuctionId string `json:"auction_id" binding:"required,uuid"`
+}
+
+type WatchlistEntryOutputDTO struct {
+	AuctionId   string                        `json:"auction_id"`
+	ProductName string                        `json:"product_name"`
+	Status      auction_usecase.AuctionStatus `json:"status"`
+	EndTime     time.Time                     `json:"end_time" time_format:"2006-01-02 15:04:05"`
+	WatchedAt   time.Time                     `json:"watched_at" time_format:"2006-01-02 15:04:05"`
+}
+
+type WatchlistUseCase struct {
+	watchlistRepository watchlist_entity.WatchlistRepositoryInterface
+	auctionRepository   auction_entity.AuctionRepositoryInterface
+}
+
+func NewWatchlistUseCase(
+	watchlistRepository watchlist_entity.WatchlistRepositoryInterface,
+	auctionRepository auction_entity.AuctionRepositoryInterface) WatchlistUseCaseInterface {
+	return &WatchlistUseCase{
+		watchlistRepository: watchlistRepository,
+		auctionRepository:   auctionRepository,
+	}
+}
+
+type WatchlistUseCaseInterface interface {
+	AddToWatchlist(
+		ctx context.Context,
+		watchlistInput WatchlistInputDTO) *internal_error.InternalError
+
+	RemoveFromWatchlist(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	ListWatchlist(
+		ctx context.Context, userId string) ([]WatchlistEntryOutputDTO, *internal_error.InternalError)
+}
+
+// AddToWatchlist only takes auctions that exist, up to MaxWatchlistSize per
+// user
+func (wu *WatchlistUseCase) AddToWatchlist(
+	ctx context.Context, watchlistInput WatchlistInputDTO) *internal_error.InternalError {
+	entry, err := watchlist_entity.CreateWatchlistEntry(watchlistInput.UserId, watchlistInput.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := wu.auctionRepository.FindAuctionById(ctx, entry.AuctionId); err != nil {
+		return err
+	}
+
+	count, err := wu.watchlistRepository.CountWatchlistByUserId(ctx, entry.UserId)
+	if err != nil {
+		return err
+	}
+	if count >= watchlist_entity.MaxWatchlistSize {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("A watchlist holds at most %d auctions", watchlist_entity.MaxWatchlistSize))
+	}
+
+	return wu.watchlistRepository.AddToWatchlist(ctx, entry)
+}
+
+func (wu *WatchlistUseCase) RemoveFromWatchlist(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	return wu.watchlistRepository.RemoveFromWatchlist(ctx, userId, auctionId)
+}
+
+// ListWatchlist leaves out auctions that no longer exist
+func (wu *WatchlistUseCase) ListWatchlist(
+	ctx context.Context, userId string) ([]WatchlistEntryOutputDTO, *internal_error.InternalError) {
+	entries, err := wu.watchlistRepository.FindWatchlistByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]WatchlistEntryOutputDTO, 0, len(entries))
+	for _, entry := range entries {
+		auction, err := wu.auctionRepository.FindAuctionById(ctx, entry.AuctionId)
+		if err != nil {
+			if err.Err == "not_found" {
+				continue
+			}
+			return nil, err
+		}
+
+		output = append(output, WatchlistEntryOutputDTO{
+			AuctionId:   auction.Id,
+			ProductName: auction.ProductName,
+			Status:      auction_usecase.AuctionStatus(auction.Status),
+			EndTime:     auction.EndTime,
+			WatchedAt:   entry.Timestamp,
+		})
+	}
+
+	return output, nil
+}
@@ -0,0 +1,138 @@
+package webhook_usecase
+
+import (
+	"auction_go/internal/entity/webhook_entity"
+	"auction_go/internal/internal_error"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type WebhookInputDTO struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=auction.created bid.placed auction.closed"`
+}
+
+// WebhookOutputDTO only carries the secret when the webhook was just
+// created
+type WebhookOutputDTO struct {
+	Id        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type DeadLetterOutputDTO struct {
+	Id         string          `json:"id"`
+	DeliveryId string          `json:"delivery_id"`
+	Event      string          `json:"event"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	Timestamp  time.Time       `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type WebhookUseCase struct {
+	webhookRepository webhook_entity.WebhookRepositoryInterface
+}
+
+func NewWebhookUseCase(webhookRepository webhook_entity.WebhookRepositoryInterface) WebhookUseCaseInterface {
+	return &WebhookUseCase{
+		webhookRepository: webhookRepository,
+	}
+}
+
+type WebhookUseCaseInterface interface {
+	CreateWebhook(
+		ctx context.Context,
+		webhookInput WebhookInputDTO) (*WebhookOutputDTO, *internal_error.InternalError)
+
+	ListWebhooks(
+		ctx context.Context) ([]WebhookOutputDTO, *internal_error.InternalError)
+
+	DeleteWebhook(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindDeadLettersByWebhookId(
+		ctx context.Context, webhookId string) ([]DeadLetterOutputDTO, *internal_error.InternalError)
+}
+
+func (wu *WebhookUseCase) CreateWebhook(
+	ctx context.Context,
+	webhookInput WebhookInputDTO) (*WebhookOutputDTO, *internal_error.InternalError) {
+	events := make([]webhook_entity.EventType, 0, len(webhookInput.Events))
+	for _, event := range webhookInput.Events {
+		events = append(events, webhook_entity.EventType(event))
+	}
+
+	webhook, err := webhook_entity.CreateWebhook(webhookInput.URL, events)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wu.webhookRepository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	output := toWebhookOutputDTO(*webhook)
+	output.Secret = webhook.Secret
+	return &output, nil
+}
+
+func (wu *WebhookUseCase) ListWebhooks(
+	ctx context.Context) ([]WebhookOutputDTO, *internal_error.InternalError) {
+	webhooks, err := wu.webhookRepository.FindWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]WebhookOutputDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		output = append(output, toWebhookOutputDTO(webhook))
+	}
+
+	return output, nil
+}
+
+func (wu *WebhookUseCase) DeleteWebhook(
+	ctx context.Context, id string) *internal_error.InternalError {
+	return wu.webhookRepository.DeleteWebhook(ctx, id)
+}
+
+func (wu *WebhookUseCase) FindDeadLettersByWebhookId(
+	ctx context.Context, webhookId string) ([]DeadLetterOutputDTO, *internal_error.InternalError) {
+	deadLetters, err := wu.webhookRepository.FindDeadLettersByWebhookId(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]DeadLetterOutputDTO, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		output = append(output, DeadLetterOutputDTO{
+			Id:         deadLetter.Id,
+			DeliveryId: deadLetter.DeliveryId,
+			Event:      string(deadLetter.Event),
+			Payload:    deadLetter.Payload,
+			Attempts:   deadLetter.Attempts,
+			LastError:  deadLetter.LastError,
+			Timestamp:  deadLetter.Timestamp,
+		})
+	}
+
+	return output, nil
+}
+
+func toWebhookOutputDTO(webhook webhook_entity.Webhook) WebhookOutputDTO {
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+
+	return WebhookOutputDTO{
+		Id:        webhook.Id,
+		URL:       webhook.URL,
+		Events:    events,
+		Timestamp: webhook.Timestamp,
+	}
+}